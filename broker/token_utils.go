@@ -36,6 +36,7 @@ import (
 	"github.com/pelicanplatform/pelican/server_structs"
 	"github.com/pelicanplatform/pelican/token"
 	"github.com/pelicanplatform/pelican/token_scopes"
+	"github.com/pelicanplatform/pelican/utils/registry_jwks"
 )
 
 var (
@@ -55,20 +56,20 @@ func LaunchNamespaceKeyMaintenance(ctx context.Context, egrp *errgroup.Group) {
 
 	loader := ttlcache.LoaderFunc[string, *jwk.Cache](
 		func(cache *ttlcache.Cache[string, *jwk.Cache], prefix string) *ttlcache.Item[string, *jwk.Cache] {
-			iss, err := getRegistryIssValue(prefix)
+			jwksUrls, err := getRegistryJwksUrls(ctx, prefix)
 			if err != nil {
 				return nil
 			}
-			// The actual location of the JWKS at the registry
-			jwksUrl := iss + "/.well-known/issuer.jwks"
 
 			ar := jwk.NewCache(ctx)
 			client := &http.Client{Transport: config.GetBasicTransport()}
-			if err = ar.Register(jwksUrl, jwk.WithMinRefreshInterval(15*time.Minute), jwk.WithHTTPClient(client)); err != nil {
-				log.Errorln("Failed to fetch issuer information for", iss, "from JWKS URL", jwksUrl, ":", err)
-				return nil
+			for _, jwksUrl := range jwksUrls {
+				if err = ar.Register(jwksUrl, jwk.WithMinRefreshInterval(15*time.Minute), jwk.WithHTTPClient(client)); err != nil {
+					log.Errorln("Failed to fetch issuer information for", prefix, "from JWKS URL", jwksUrl, ":", err)
+					return nil
+				}
 			}
-			log.Debugln("Setting public key cache for issuer", iss)
+			log.Debugln("Setting public key cache for namespace", prefix)
 			item := cache.Set(prefix, ar, ttlcache.DefaultTTL)
 			return item
 		},
@@ -100,7 +101,13 @@ func getRegistryIssValue(prefix string) (iss string, err error) {
 		err = errors.New("namespace URL is not set")
 		return
 	}
-	namespaceUrl, err := url.Parse(namespaceUrlStr)
+	return getRegistryIssValueAt(namespaceUrlStr, prefix)
+}
+
+// Given a registry URL and a namespace prefix, return the issuer URL
+// of the namespace at that registry.
+func getRegistryIssValueAt(registryUrlStr, prefix string) (iss string, err error) {
+	namespaceUrl, err := url.Parse(registryUrlStr)
 	if err != nil {
 		return
 	}
@@ -112,15 +119,37 @@ func getRegistryIssValue(prefix string) (iss string, err error) {
 	return
 }
 
+// Given a namespace prefix, return the locations of its JWKS at the
+// federation's registry followed by those at the registry's replicas,
+// in the order they should be tried.
+func getRegistryJwksUrls(ctx context.Context, prefix string) (jwksUrls []string, err error) {
+	registryUrls, err := registry_jwks.GetRegistryEndpoints(ctx)
+	if err != nil {
+		return
+	}
+	for _, registryUrlStr := range registryUrls {
+		var iss string
+		if iss, err = getRegistryIssValueAt(registryUrlStr, prefix); err != nil {
+			return
+		}
+		// The actual location of the JWKS at the registry
+		jwksUrls = append(jwksUrls, iss+"/.well-known/issuer.jwks")
+	}
+	return
+}
+
 // Given a namespace prefix, return the value for the `iss` claim and
-// the public keyset to use
+// the public keyset to use.  The keyset comes from the first registry
+// (or registry replica) that serves it.
 func getRegistryIssuerInfo(ctx context.Context, prefix string) (iss string, keyset jwk.Set, err error) {
 	if iss, err = getRegistryIssValue(prefix); err != nil {
 		return
 	}
 
-	// The actual location of the JWKS at the registry
-	jwksUrl := iss + "/.well-known/issuer.jwks"
+	jwksUrls, err := getRegistryJwksUrls(ctx, prefix)
+	if err != nil {
+		return
+	}
 
 	if namespaceKeys == nil {
 		err = errors.New("namespace key cache not initialized; call LaunchNamespaceKeyMaintenance first")
@@ -132,10 +161,14 @@ func getRegistryIssuerInfo(ctx context.Context, prefix string) (iss string, keys
 		err = errors.Errorf("failed to load issuer information for namespace %s: namespace may not be registered in the registry or registry endpoint is unreachable", prefix)
 		return
 	}
-	keyset, err = item.Value().Get(ctx, jwksUrl)
-	if err != nil {
+	for idx, jwksUrl := range jwksUrls {
+		if keyset, err = item.Value().Get(ctx, jwksUrl); err == nil {
+			return
+		}
 		err = errors.Wrapf(err, "failed to retrieve keyset from JWKS URL %s for namespace %s", jwksUrl, prefix)
-		return
+		if idx < len(jwksUrls)-1 {
+			log.Warningf("%v; trying the next registry", err)
+		}
 	}
 	return
 }
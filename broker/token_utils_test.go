@@ -19,13 +19,22 @@
 package broker
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/lestrrat-go/jwx/v2/jwk"
 	"github.com/lestrrat-go/jwx/v2/jwt"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/pelicanplatform/pelican/param"
 	"github.com/pelicanplatform/pelican/pelican_url"
 	"github.com/pelicanplatform/pelican/server_utils"
 	"github.com/pelicanplatform/pelican/test_utils"
@@ -52,3 +61,55 @@ func TestGetCacheHostnameFromToken(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "https://cache.com", hostname)
 }
+
+func TestGetRegistryIssuerInfoReplica(t *testing.T) {
+	t.Cleanup(test_utils.SetupTestLogging(t))
+	server_utils.ResetTestState()
+	t.Cleanup(server_utils.ResetTestState)
+	ctx, cancel, egrp := test_utils.TestContext(context.Background(), t)
+	defer func() { require.NoError(t, egrp.Wait()) }()
+	defer cancel()
+
+	privKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	key, err := jwk.FromRaw(privKey.PublicKey)
+	require.NoError(t, err)
+	require.NoError(t, jwk.AssignKeyID(key))
+	keyset := jwk.NewSet()
+	require.NoError(t, keyset.AddKey(key))
+	keysetBytes, err := json.Marshal(keyset)
+	require.NoError(t, err)
+
+	replica := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/api/v1.0/registry/foo/.well-known/issuer.jwks" {
+			_, _ = w.Write(keysetBytes)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer replica.Close()
+
+	// The main registry is down
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+	test_utils.MockFederationRoot(t, &pelican_url.FederationDiscovery{RegistryEndpoint: down.URL}, nil)
+	require.NoError(t, param.Federation_RegistryReplicaUrls.Set([]string{replica.URL}))
+
+	namespaceKeys = nil
+	LaunchNamespaceKeyMaintenance(ctx, egrp)
+
+	jwksUrls, err := getRegistryJwksUrls(ctx, "/foo")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		down.URL + "/api/v1.0/registry/foo/.well-known/issuer.jwks",
+		replica.URL + "/api/v1.0/registry/foo/.well-known/issuer.jwks",
+	}, jwksUrls)
+
+	// The issuer remains the main registry while the keys come from the replica
+	iss, fetched, err := getRegistryIssuerInfo(ctx, "/foo")
+	require.NoError(t, err)
+	assert.Equal(t, down.URL+"/api/v1.0/registry/foo", iss)
+	fetchedKey, ok := fetched.LookupKeyID(key.KeyID())
+	require.True(t, ok)
+	assert.True(t, jwk.Equal(key, fetchedKey))
+}
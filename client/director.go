@@ -34,12 +34,14 @@ import (
 
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/pelicanplatform/pelican/config"
 	"github.com/pelicanplatform/pelican/error_codes"
 	"github.com/pelicanplatform/pelican/param"
 	"github.com/pelicanplatform/pelican/pelican_url"
 	"github.com/pelicanplatform/pelican/server_structs"
+	"github.com/pelicanplatform/pelican/tracing"
 	"github.com/pelicanplatform/pelican/utils"
 )
 
@@ -89,6 +91,18 @@ func queryDirector(ctx context.Context, verb string, pUrl *pelican_url.PelicanUR
 	}
 	resourceUrl.RawQuery = pUrl.RawQuery
 
+	ctx, span := tracing.StartClientSpan(ctx, "client.directorLookup",
+		attribute.String("http.request.method", verb),
+		attribute.String("server.address", resourceUrl.Host),
+		attribute.String("url.path", pUrl.Path),
+	)
+	defer func() {
+		if resp != nil {
+			span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+		}
+		tracing.EndSpan(span, err)
+	}()
+
 	// Here we use http.Transport to prevent the client from following the director's
 	// redirect. We use the Location url elsewhere (plus we still need to do the token
 	// dance!)
@@ -119,6 +133,7 @@ func queryDirector(ctx context.Context, verb string, pUrl *pelican_url.PelicanUR
 		// if it supports the version, and provide an error message in the case that it
 		// cannot.
 		req.Header.Set("User-Agent", getUserAgent(""))
+		tracing.InjectHeaders(ctx, req.Header)
 
 		if token != "" {
 			req.Header.Set("Authorization", "Bearer "+token)
@@ -378,6 +393,11 @@ func ParseDirectorInfo(dirResp *http.Response) (server_structs.DirectorResponse,
 		return server_structs.DirectorResponse{}, errors.Wrapf(err, "failed to parse %s header", xPelTokGen.GetName())
 	}
 
+	var xPelFeatures server_structs.XPelFeatures
+	if err := (&xPelFeatures).ParseRawResponse(dirResp); err != nil {
+		return server_structs.DirectorResponse{}, errors.Wrapf(err, "failed to parse %s header", xPelFeatures.GetName())
+	}
+
 	sortedObjectServers, err := parseServersFromDirectorResponse(dirResp)
 	if err != nil {
 		return server_structs.DirectorResponse{}, errors.Wrap(err, "failed to determine object servers from Director's response")
@@ -401,5 +421,6 @@ func ParseDirectorInfo(dirResp *http.Response) (server_structs.DirectorResponse,
 		XPelAuthHdr:   xPelAuth,
 		XPelNsHdr:     xPelNs,
 		XPelTokGenHdr: xPelTokGen,
+		XPelFeatHdr:   xPelFeatures,
 	}, nil
 }
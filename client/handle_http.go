@@ -23,10 +23,12 @@ import (
 	"context"
 	"crypto/md5"
 	"crypto/sha1"
+	"crypto/sha256"
 	"encoding/base64"
 	"encoding/hex"
 	"fmt"
 	"hash"
+	"hash/adler32"
 	"hash/crc32"
 	"io"
 	"io/fs"
@@ -55,6 +57,7 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/studio-b12/gowebdav"
 	"github.com/vbauerster/mpb/v8"
+	"go.opentelemetry.io/otel/attribute"
 	"golang.org/x/sync/errgroup"
 	"golang.org/x/sync/singleflight"
 	"golang.org/x/time/rate"
@@ -64,6 +67,7 @@ import (
 	"github.com/pelicanplatform/pelican/param"
 	"github.com/pelicanplatform/pelican/pelican_url"
 	"github.com/pelicanplatform/pelican/server_structs"
+	"github.com/pelicanplatform/pelican/tracing"
 )
 
 var (
@@ -286,9 +290,11 @@ type (
 		requireChecksum    bool
 		recursive          bool
 		skipAcquire        bool
-		dryRun             bool       // Enable dry-run mode to display what would be transferred without actually doing it
-		syncLevel          SyncLevel  // Policy for handling synchronization when the destination exists
-		prefObjServers     []*url.URL // holds any client-requested caches/origins
+		dryRun             bool           // Enable dry-run mode to display what would be transferred without actually doing it
+		syncLevel          SyncLevel      // Policy for handling synchronization when the destination exists
+		syncDelete         bool           // Delete destination objects not present at the source of a recursive sync
+		syncReport         SyncReportFunc // Optional receiver of the itemized sync changes
+		prefObjServers     []*url.URL     // holds any client-requested caches/origins
 		dirResp            server_structs.DirectorResponse
 		directorUrl        string
 		token              *tokenGenerator
@@ -300,6 +306,9 @@ type (
 		inPlace            bool                    // If true, write directly to final destination; if false, use temporary file
 		forcePrestageAPI   bool                    // If true, force use of prestage API and error if not supported (no fallback)
 		byteRange          *ByteRange              // Optional byte range for partial downloads
+		stripeSources      int                     // Number of caches to download large objects from at once; 0 or 1 disables striping
+		expectedSize       *int64                  // Optional size the transferred object must have
+		expectedChecksum   *ChecksumInfo           // Optional checksum the transferred object must match
 		metadataChan       chan<- TransferMetadata // Optional channel to receive early transfer metadata
 		requestId          string                  // Caller-supplied request ID for end-to-end tracing (X-Pelican-JobId)
 	}
@@ -341,9 +350,10 @@ type (
 		ewmaCtr            atomic.Int64
 		clientLock         sync.RWMutex
 		pelicanUrlCache    *pelican_url.Cache
-		dirRespCache       *DirRespCache   // Prefix-matching cache for director responses
-		prestageAPISupport map[string]bool // Lookup table for caches that support the Pelican prestage API (key: host)
-		prestageAPIMutex   sync.RWMutex    // Protects the prestageAPISupport map
+		dirRespCache       *DirRespCache     // Prefix-matching cache for director responses
+		prestageAPISupport map[string]bool   // Lookup table for caches that support the Pelican prestage API (key: host)
+		prestageAPIMutex   sync.RWMutex      // Protects the prestageAPISupport map
+		reporter           *transferReporter // Sends download throughput reports to the director
 	}
 
 	TransferCallbackFunc = func(path string, downloaded int64, totalSize int64, completed bool)
@@ -378,6 +388,8 @@ type (
 	identTransferOptionAcquireToken            struct{}
 	identTransferOptionToken                   struct{}
 	identTransferOptionSynchronize             struct{}
+	identTransferOptionSyncDelete              struct{}
+	identTransferOptionSyncReport              struct{}
 	identTransferOptionCollectionsUrl          struct{}
 	identTransferOptionChecksums               struct{}
 	identTransferOptionRequireChecksum         struct{}
@@ -389,6 +401,9 @@ type (
 	identTransferOptionDryRun                  struct{}
 	identTransferOptionForcePrestageAPI        struct{}
 	identTransferOptionByteRange               struct{}
+	identTransferOptionStripedDownload         struct{}
+	identTransferOptionExpectedSize            struct{}
+	identTransferOptionExpectedChecksum        struct{}
 	identTransferOptionMetadataChannel         struct{}
 	identTransferOptionFedToken                struct{}
 	identTransferOptionCacheEmbeddedClientMode struct{}
@@ -437,6 +452,8 @@ const (
 	AlgCRC32C                      // Checksum is using the CRC32C algorithm
 	AlgCRC32                       // Checksum is using the CRC32 algorithm
 	AlgSHA1                        // Checksum is using the SHA-1 algorithm
+	AlgSHA256                      // Checksum is using the SHA-256 algorithm
+	AlgAdler32                     // Checksum is using the Adler-32 algorithm
 	AlgUnknown                     // Unknown checksum algorithm.  Always a "trailer" indicating the last known algorithm.
 
 	AlgDefault = AlgCRC32C // Default checksum algorithm is CRC32C if the client doesn't specify one.
@@ -445,9 +462,11 @@ const (
 )
 
 const (
-	SyncNone  SyncLevel = iota // When synchronizing, always re-transfer, regardless of existence at destination.
-	SyncExist                  // Skip synchronization transfer if the destination exists
-	SyncSize                   // Skip synchronization transfer if the destination exists and matches the current source size
+	SyncNone     SyncLevel = iota // When synchronizing, always re-transfer, regardless of existence at destination.
+	SyncExist                     // Skip synchronization transfer if the destination exists
+	SyncSize                      // Skip synchronization transfer if the destination exists and matches the current source size
+	SyncMtime                     // Skip synchronization transfer if the destination matches the source size and is at least as new as the source
+	SyncChecksum                  // Skip synchronization transfer if the destination matches the source size and checksum
 )
 
 const (
@@ -470,8 +489,10 @@ var (
 	ErrServerChecksumMissing = errors.New("no checksum information was returned by server but checksums were required by the client")
 )
 
+// Convert an RFC 3230 digest algorithm name, as registered with IANA, to a
+// checksum type.  Digest algorithm names are case-insensitive.
 func ChecksumFromHttpDigest(httpDigest string) ChecksumType {
-	switch httpDigest {
+	switch strings.ToLower(httpDigest) {
 	case "md5":
 		return AlgMD5
 	case "crc32c":
@@ -480,6 +501,10 @@ func ChecksumFromHttpDigest(httpDigest string) ChecksumType {
 		return AlgCRC32
 	case "sha":
 		return AlgSHA1
+	case "sha-256":
+		return AlgSHA256
+	case "adler32":
+		return AlgAdler32
 	}
 	return AlgUnknown
 }
@@ -513,6 +538,10 @@ func HttpDigestFromChecksum(checksumType ChecksumType) string {
 		return "md5"
 	case AlgSHA1:
 		return "sha"
+	case AlgSHA256:
+		return "sha-256"
+	case AlgAdler32:
+		return "adler32"
 	}
 	return ""
 }
@@ -521,13 +550,9 @@ func HttpDigestFromChecksum(checksumType ChecksumType) string {
 // specified in RFC 3230
 func checksumValueToHttpDigest(checksumType ChecksumType, checksumValue []byte) string {
 	switch checksumType {
-	case AlgCRC32:
-		fallthrough
-	case AlgCRC32C:
+	case AlgCRC32, AlgCRC32C, AlgAdler32:
 		return hex.EncodeToString(checksumValue)
-	case AlgMD5:
-		fallthrough
-	case AlgSHA1:
+	case AlgMD5, AlgSHA1, AlgSHA256:
 		return base64.StdEncoding.EncodeToString(checksumValue)
 	}
 	return "(unknown checksum type)"
@@ -542,25 +567,35 @@ func createAllChecksumHashes() (writers []io.Writer, types []ChecksumType) {
 	writers = make([]io.Writer, 0, len(known))
 	types = make([]ChecksumType, 0, len(known))
 	for _, t := range known {
-		var w io.Writer
-		switch t {
-		case AlgCRC32:
-			w = crc32.NewIEEE()
-		case AlgCRC32C:
-			w = crc32.New(crc32cTable)
-		case AlgMD5:
-			w = md5.New()
-		case AlgSHA1:
-			w = sha1.New()
-		default:
+		h := newChecksumHash(t)
+		if h == nil {
 			continue
 		}
-		writers = append(writers, w)
+		writers = append(writers, h)
 		types = append(types, t)
 	}
 	return
 }
 
+// Create the hash computing a checksum type, or nil if the type is unknown
+func newChecksumHash(checksumType ChecksumType) hash.Hash {
+	switch checksumType {
+	case AlgCRC32:
+		return crc32.NewIEEE()
+	case AlgCRC32C:
+		return crc32.New(crc32cTable)
+	case AlgMD5:
+		return md5.New()
+	case AlgSHA1:
+		return sha1.New()
+	case AlgSHA256:
+		return sha256.New()
+	case AlgAdler32:
+		return adler32.New()
+	}
+	return nil
+}
+
 // verifyTransferChecksums compares server-provided checksums against locally-computed ones.
 //
 // It tries to match any server-provided checksum against the computed values. If the match
@@ -751,6 +786,11 @@ func NewTransferEngine(ctx context.Context) (te *TransferEngine, err error) {
 		return nil, errors.New("client has not been initialized, unable to create transfer engine")
 	}
 
+	// Servers embedding a transfer engine have already set up tracing, in which case this is a no-op.
+	if err := tracing.InitTracing(ctx, nil, "pelican-client"); err != nil {
+		log.Warningln("Failed to configure tracing; continuing without it:", err)
+	}
+
 	ctx, cancel := context.WithCancel(ctx)
 	egrp, _ := errgroup.WithContext(ctx)
 	work := make(chan *clientTransferJob, 5)
@@ -778,6 +818,7 @@ func NewTransferEngine(ctx context.Context) (te *TransferEngine, err error) {
 		pelicanUrlCache:    pelicanUrlCache,
 		dirRespCache:       NewDirRespCache(5 * time.Minute),
 		prestageAPISupport: make(map[string]bool),
+		reporter:           newTransferReporter(),
 	}
 	workerCount := param.Client_WorkerCount.GetInt()
 	if workerCount <= 0 {
@@ -791,6 +832,9 @@ func NewTransferEngine(ctx context.Context) (te *TransferEngine, err error) {
 	te.workersActive = workerCount
 	egrp.Go(te.runMux)
 	egrp.Go(te.runJobHandler)
+	egrp.Go(func() error {
+		return te.reporter.run(ctx)
+	})
 	return
 }
 
@@ -941,6 +985,34 @@ func WithByteRange(start, end int64) TransferOption {
 	return option.New(identTransferOptionByteRange{}, ByteRange{Start: start, End: end})
 }
 
+// Create an option to download large objects from several caches at once
+//
+// Objects of at least Client.StripedDownloadThreshold bytes are split into byte
+// ranges that are fetched concurrently from the top `sources` caches and
+// reassembled in the destination file.  A value of 0 or 1 disables striping.
+// Defaults to the value of Client.StripedDownloadSources.
+func WithStripedDownload(sources int) TransferOption {
+	return option.New(identTransferOptionStripedDownload{}, sources)
+}
+
+// Create an option to fail the transfer unless the object has the given
+// size in bytes
+//
+// Only applies to transfers of a single object.
+func WithExpectedSize(size int64) TransferOption {
+	return option.New(identTransferOptionExpectedSize{}, size)
+}
+
+// Create an option to fail the transfer unless the checksum computed by the
+// client while transferring the object matches the given one
+//
+// Unlike the checksums reported by the server, the expected checksum comes
+// from the caller (e.g., a manifest of the files to transfer).  Only applies
+// to transfers of a single object.
+func WithExpectedChecksum(checksum ChecksumInfo) TransferOption {
+	return option.New(identTransferOptionExpectedChecksum{}, checksum)
+}
+
 // Create an option to receive early transfer metadata before data transfer begins
 //
 // When provided, the channel will receive a TransferMetadata struct containing
@@ -1069,6 +1141,14 @@ func (te *TransferEngine) Shutdown() error {
 	te.cancel()
 
 	err := te.egrp.Wait()
+
+	// The client may exit right after shutting down the engine; make sure its spans are exported.
+	flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if flushErr := tracing.ForceFlush(flushCtx); flushErr != nil {
+		log.Debugln("Failed to flush trace spans:", flushErr)
+	}
+
 	if err != nil && err != context.Canceled {
 		return err
 	}
@@ -1411,6 +1491,7 @@ func (tc *TransferClient) NewTransferJob(ctx context.Context, remoteUrl *url.URL
 		project:        project,
 		token:          newTokenGenerator(&copyUrl, nil, operation, !tc.skipAcquire),
 		inPlace:        false, // Default to using temporary files (rsync-style)
+		stripeSources:  param.Client_StripedDownloadSources.GetInt(),
 	}
 	if upload {
 		tj.xferType = transferTypeUpload
@@ -1442,6 +1523,10 @@ func (tc *TransferClient) NewTransferJob(ctx context.Context, remoteUrl *url.URL
 			tj.fedToken = option.Value().(TokenProvider)
 		case identTransferOptionSynchronize{}:
 			tj.syncLevel = option.Value().(SyncLevel)
+		case identTransferOptionSyncDelete{}:
+			tj.syncDelete = option.Value().(bool)
+		case identTransferOptionSyncReport{}:
+			tj.syncReport = option.Value().(SyncReportFunc)
 		case identTransferOptionChecksums{}:
 			tj.requestedChecksums = option.Value().([]ChecksumType)
 		case identTransferOptionRequireChecksum{}:
@@ -1457,6 +1542,14 @@ func (tc *TransferClient) NewTransferJob(ctx context.Context, remoteUrl *url.URL
 		case identTransferOptionByteRange{}:
 			br := option.Value().(ByteRange)
 			tj.byteRange = &br
+		case identTransferOptionStripedDownload{}:
+			tj.stripeSources = option.Value().(int)
+		case identTransferOptionExpectedSize{}:
+			size := option.Value().(int64)
+			tj.expectedSize = &size
+		case identTransferOptionExpectedChecksum{}:
+			checksum := option.Value().(ChecksumInfo)
+			tj.expectedChecksum = &checksum
 		case identTransferOptionMetadataChannel{}:
 			tj.metadataChan = option.Value().(chan<- TransferMetadata)
 		case identTransferOptionCacheEmbeddedClientMode{}:
@@ -1466,6 +1559,14 @@ func (tc *TransferClient) NewTransferJob(ctx context.Context, remoteUrl *url.URL
 		}
 	}
 
+	if tj.expectedSize != nil || tj.expectedChecksum != nil {
+		if recursive || tj.byteRange != nil {
+			err = errors.New("an expected size or checksum can only be given for a transfer of a whole object")
+			return
+		}
+		tj.requestExpectedChecksum()
+	}
+
 	// Inject the request ID into the job's context so it propagates
 	// through transferFile → downloadHTTP and friends.
 	if tj.requestId != "" {
@@ -1632,8 +1733,15 @@ func (tc *TransferClient) NewPrestageJob(ctx context.Context, remoteUrl *url.URL
 			tj.syncLevel = option.Value().(SyncLevel)
 		case identTransferOptionForcePrestageAPI{}:
 			tj.forcePrestageAPI = option.Value().(bool)
+		case identTransferOptionExpectedSize{}:
+			size := option.Value().(int64)
+			tj.expectedSize = &size
+		case identTransferOptionExpectedChecksum{}:
+			checksum := option.Value().(ChecksumInfo)
+			tj.expectedChecksum = &checksum
 		}
 	}
+	tj.requestExpectedChecksum()
 
 	tj.directorUrl = pelicanURL.FedInfo.DirectorEndpoint
 
@@ -2047,8 +2155,9 @@ func (te *TransferEngine) createTransferFiles(job *clientTransferJob) (err error
 			sortedServerStrings = append(sortedServerStrings, serverUrl.String())
 		}
 
-		// Make sure we only try as many object servers as we have
-		objectServersToTry := ObjectServersToTry
+		// Make sure we only try as many object servers as we have; striped
+		// downloads may want more sources than the usual number of attempts
+		objectServersToTry := max(ObjectServersToTry, job.job.stripeSources)
 		if objectServersToTry > len(sortedServers) {
 			objectServersToTry = len(sortedServers)
 		}
@@ -2223,6 +2332,8 @@ func runTransferWorker(ctx context.Context, workChan <-chan *clientTransferFile,
 				transferResults = newTransferResults(file.file.job)
 				transferResults.Scheme = file.file.remoteURL.Scheme
 				transferResults.Error = err
+			} else {
+				file.file.engine.reportTransfer(file.file, &transferResults)
 			}
 			if file.file.job != nil && file.file.job.dirResp.RedirectInfo != nil {
 				transferResults.DirectorDecision = file.file.job.dirResp.RedirectInfo
@@ -2281,6 +2392,14 @@ func sortAttempts(ctx context.Context, path string, attempts []transferAttemptDe
 	}
 	headChan := make(chan checkResults)
 
+	ctx, span := tracing.StartSpan(ctx, "client.sortAttempts", attribute.Int("pelican.attempt_count", len(attempts)))
+	defer func() {
+		if len(results) > 0 && results[0].Url != nil {
+			span.SetAttributes(attribute.String("pelican.selected_endpoint", results[0].Url.Host))
+		}
+		span.End()
+	}()
+
 	if log.IsLevelEnabled(log.DebugLevel) {
 		attemptHosts := make([]string, len(attempts))
 		for idx, host := range attempts {
@@ -2516,8 +2635,11 @@ func downloadObject(transfer *transferFile) (transferResults TransferResults, er
 		}
 	} else { // Prestage case
 		// Check if we should use the Pelican prestage API
-		// We'll try the API for the first attempt (if supported), then fall back to the traditional method
-		if len(transfer.attempts) > 0 {
+		// We'll try the API for the first attempt (if supported), then fall back to the traditional method.
+		// The API doesn't send the object through the client, so an expected size or checksum needs the traditional method.
+		verifyLocally := transfer.job != nil && (transfer.job.expectedSize != nil || transfer.job.expectedChecksum != nil) &&
+			!transfer.job.forcePrestageAPI
+		if len(transfer.attempts) > 0 && !verifyLocally {
 			firstAttempt := transfer.attempts[0]
 			cacheHost := firstAttempt.Url.Host
 
@@ -2631,6 +2753,28 @@ func downloadObject(transfer *transferFile) (transferResults TransferResults, er
 	var transferStartTime time.Time
 	transferUrls := make([]*url.URL, len(attempts))
 	downloadAttemptCount := 0
+	if sources := stripedDownloadSources(transfer, attempts, size, fp); sources > 1 {
+		transferStartTime = time.Now()
+		striped := downloadStriped(transfer, attempts, sources, size, fp, writeDestination)
+		transferResults.Attempts = append(transferResults.Attempts, striped.attempts...)
+		transferResults.ETag = striped.etag
+		transferUrls = striped.urls
+		downloadAttemptCount = len(striped.urls)
+		downloaded = striped.downloaded
+		xferErrors = striped.errors
+		if striped.err == nil {
+			// The stripes arrived out of order; checksum the reassembled file
+			if _, err := io.Copy(hashesWriter, io.NewSectionReader(fp, 0, size)); err != nil {
+				xferErrors.AddPastError(errors.Wrap(err, "failed to read back the downloaded object"), time.Now())
+			} else {
+				success = true
+			}
+		} else if len(striped.attempts) == 0 {
+			xferErrors.AddPastError(striped.err, time.Now())
+		}
+		// The object has been downloaded (or given up on); skip the sequential attempts
+		attempts = nil
+	}
 	for idx, transferEndpoint := range attempts { // For each transfer attempt (usually 3), try to download via HTTP
 		downloadAttemptCount++
 		var attempt TransferResult
@@ -2640,24 +2784,8 @@ func downloadObject(transfer *transferFile) (transferResults TransferResults, er
 		if transferEndpoint.CacheQuery {
 			attempt.CacheAge = transferEndpoint.CacheAge
 		}
-		// Make a copy of the transfer endpoint URL; otherwise, when we mutate the pointer, other parallel
-		// workers might download from the wrong path.
+		transferEndpoint = prepareDownloadAttempt(transfer, transferEndpoint)
 		transferEndpointUrl := *transferEndpoint.Url
-		transferEndpoint.Url = &transferEndpointUrl
-		if transferEndpointUrl.Scheme == "unix" {
-			transferEndpointUrl.Path = transfer.remoteURL.Path
-		}
-		// If a federation token is set, add it as an access_token query
-		// parameter.  The transfer URL already points at the origin (post
-		// director redirect), so this goes directly to the origin and is
-		// NOT sent to the director.
-		if transfer.fedToken != nil {
-			if ft, ftErr := transfer.fedToken.Get(); ftErr == nil && ft != "" {
-				q := transferEndpointUrl.Query()
-				q.Set("access_token", ft)
-				transferEndpointUrl.RawQuery = q.Encode()
-			}
-		}
 		transferUrls[idx] = transferEndpoint.Url
 		fields := log.Fields{
 			"url": transferEndpoint.Url.String(),
@@ -2821,6 +2949,8 @@ func downloadObject(transfer *transferFile) (transferResults TransferResults, er
 				transfer.requireChecksum, fields,
 			); verifyErr != nil {
 				transferResults.Error = verifyErr
+			} else if expectErr := verifyExpectedObject(transfer.job, allComputed, downloaded, fields); expectErr != nil {
+				transferResults.Error = expectErr
 			}
 		}
 	} else {
@@ -2850,6 +2980,30 @@ func downloadObject(transfer *transferFile) (transferResults TransferResults, er
 	return
 }
 
+// Prepare a download attempt for the transfer's object.
+//
+// The attempt gets its own copy of the transfer endpoint URL; otherwise, when we mutate the pointer,
+// other parallel workers might download from the wrong path.
+func prepareDownloadAttempt(transfer *transferFile, attempt transferAttemptDetails) transferAttemptDetails {
+	transferEndpointUrl := *attempt.Url
+	attempt.Url = &transferEndpointUrl
+	if transferEndpointUrl.Scheme == "unix" {
+		transferEndpointUrl.Path = transfer.remoteURL.Path
+	}
+	// If a federation token is set, add it as an access_token query
+	// parameter.  The transfer URL already points at the origin (post
+	// director redirect), so this goes directly to the origin and is
+	// NOT sent to the director.
+	if transfer.fedToken != nil {
+		if ft, ftErr := transfer.fedToken.Get(); ftErr == nil && ft != "" {
+			q := transferEndpointUrl.Query()
+			q.Set("access_token", ft)
+			transferEndpointUrl.RawQuery = q.Encode()
+		}
+	}
+	return attempt
+}
+
 func parseTransferStatus(status string) (int, string) {
 	parts := strings.SplitN(status, ": ", 2)
 	if len(parts) != 2 {
@@ -2940,7 +3094,7 @@ func fetchChecksum(ctx context.Context, types []ChecksumType, url *url.URL, toke
 	for _, val = range response.Header.Values("Digest") {
 		for _, entry := range strings.Split(val, ",") {
 			ctr++
-			info := strings.SplitN(entry, "=", 2)
+			info := strings.SplitN(strings.TrimSpace(entry), "=", 2)
 			if len(info) != 2 {
 				continue
 			}
@@ -2951,7 +3105,7 @@ func fetchChecksum(ctx context.Context, types []ChecksumType, url *url.URL, toke
 				continue
 			}
 			val := make([]byte, 32)
-			switch info[0] {
+			switch strings.ToLower(info[0]) {
 			case "crc32c":
 				// XRootD has a bug where crc32c is base64 encoded instead (per the spec)
 				// hex encoded.  Accept this case.  We've reported this as a bug so ideally
@@ -2967,8 +3121,8 @@ func fetchChecksum(ctx context.Context, types []ChecksumType, url *url.URL, toke
 					}
 				}
 				fallthrough
-			case "crc32":
-				// CRC32/CRC32C values are 32 bits, which is at most 8 hex characters.
+			case "crc32", "adler32":
+				// CRC32/CRC32C/Adler-32 values are 32 bits, which is at most 8 hex characters.
 				// If the value is longer, the origin likely returned a different algorithm
 				// (e.g., MD5) mislabeled as crc32/crc32c. This can happen with the XRootD
 				// multiuser plugin when it doesn't support the requested algorithm.
@@ -2993,17 +3147,15 @@ func fetchChecksum(ctx context.Context, types []ChecksumType, url *url.URL, toke
 					log.WithFields(fields).Errorf("Failed to parse %s checksum value (%s): %s", info[0], info[1], err)
 					continue
 				}
-			case "md5":
-				fallthrough
-			case "sha":
-				decoder := base64.NewDecoder(base64.StdEncoding, bytes.NewReader([]byte(info[1])))
-				if count, err := decoder.Read(val); err != nil {
+			case "md5", "sha", "sha-256":
+				// A single Read of a base64 decoder may return a partial value; SHA-256
+				// digests are long enough to hit this, so decode the value in one go.
+				decoded, err := base64.StdEncoding.DecodeString(info[1])
+				if err != nil {
 					log.WithFields(fields).Errorf("Failed to parse %s checksum value (%s): %s", info[0], info[1], err)
 					continue
-				} else {
-					val = val[:count]
 				}
-				checksumInfo.Value = val
+				checksumInfo.Value = decoded
 			default:
 				log.WithFields(fields).Warningf("Unknown checksum algorithm: %s", info[0])
 				continue
@@ -3072,6 +3224,16 @@ func downloadHTTP(ctx context.Context, te *TransferEngine, callback TransferCall
 	if !ok {
 		fields = log.Fields{}
 	}
+	// Registered before the panic handler so the span records the recovered error.
+	ctx, span := tracing.StartClientSpan(ctx, "client.downloadHTTP",
+		attribute.String("server.address", transfer.Url.Host),
+		attribute.String("url.path", transfer.Url.Path),
+		attribute.Int64("pelican.range_start", bytesSoFar),
+	)
+	defer func() {
+		span.SetAttributes(attribute.Int64("pelican.bytes_downloaded", downloaded))
+		tracing.EndSpan(span, err)
+	}()
 	defer func() {
 		if r := recover(); r != nil {
 			log.WithFields(fields).Errorln("Panic occurred in downloadHTTP:", r)
@@ -3187,6 +3349,7 @@ func downloadHTTP(ctx context.Context, te *TransferEngine, callback TransferCall
 	}
 	req.Header.Set("TE", "trailers")
 	req.Header.Set("User-Agent", userAgent)
+	tracing.InjectHeaders(ctx, req.Header)
 
 	req = req.WithContext(ctx)
 
@@ -3222,6 +3385,7 @@ func downloadHTTP(ctx context.Context, te *TransferEngine, callback TransferCall
 		return
 	}
 	defer resp.Body.Close()
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
 		log.WithFields(fields).Debugln("Got failure status code:", resp.StatusCode)
@@ -3799,6 +3963,7 @@ func uploadObject(transfer *transferFile) (transferResult TransferResults, err e
 	nonZeroSize := true
 	pack := transfer.packOption
 	fileSizeHint := int64(0)
+	var fileModTime time.Time
 	hasFileSize := false
 	transferResult.Scheme = transfer.remoteURL.Scheme
 
@@ -3866,6 +4031,7 @@ func uploadObject(transfer *transferFile) (transferResult TransferResults, err e
 			ioreader = file
 			sizer = &ConstantSizer{size: fileInfo.Size()}
 			fileSizeHint = fileInfo.Size()
+			fileModTime = fileInfo.ModTime()
 			hasFileSize = true
 			nonZeroSize = fileInfo.Size() > 0
 		}
@@ -3949,7 +4115,15 @@ func uploadObject(transfer *transferFile) (transferResult TransferResults, err e
 
 	useProxy := transfer.attempts[0].Proxy
 
-	go runPut(request, responseChan, errorChan, useProxy)
+	// Files larger than a chunk are uploaded resumably, so a retry or a later
+	// run can continue an interrupted upload instead of starting over
+	chunkSize := int64(param.Client_UploadChunkSize.GetInt())
+	if hasFileSize && pack == "" && chunkSize > 0 && fileSizeHint > chunkSize {
+		state := loadUploadState(transfer.localPath, transfer.remoteURL, fileSizeHint, fileModTime)
+		go runResumablePut(request, tee, state, chunkSize, responseChan, errorChan, useProxy)
+	} else {
+		go runPut(request, responseChan, errorChan, useProxy)
+	}
 	var lastError error = nil
 
 	tickerDuration := 100 * time.Millisecond
@@ -4142,6 +4316,8 @@ Loop:
 			transfer.requireChecksum, fields,
 		); verifyErr != nil {
 			transferResult.Error = verifyErr
+		} else if expectErr := verifyExpectedObject(transfer.job, allComputed, uploaded, fields); expectErr != nil {
+			transferResult.Error = expectErr
 		}
 	}
 	// Add our attempt fields
@@ -4163,17 +4339,7 @@ func runPut(request *http.Request, responseChan chan<- *http.Response, errorChan
 	log.Debugf("Dumping request: %s", dump)
 	response, err := client.Do(request)
 	if err != nil {
-		if !errors.Is(err, context.Canceled) {
-			log.Errorln("Error with PUT:", err)
-			// Wrap connection setup errors (timeout, DNS, TLS handshake, dial) in ConnectionSetupError
-			// Note: TLS certificate validation errors are handled separately (not retryable)
-			if isTLSCertificateValidationError(err) {
-				// TLS certificate validation error - leave unwrapped, will be wrapped as SpecificationError in upload error handler
-			} else if isContextDeadlineError(err) || isDNSError(err) || (isTLSError(err) && !isTLSCertificateValidationError(err)) || isDialError(err) {
-				err = &ConnectionSetupError{URL: request.URL.String(), Err: err}
-			}
-		}
-		errorChan <- err
+		errorChan <- wrapPutError(request, err)
 		close(errorChan)
 		return
 	}
@@ -4197,6 +4363,23 @@ func runPut(request *http.Request, responseChan chan<- *http.Response, errorChan
 
 }
 
+// Log a failed PUT request and wrap connection setup errors for the upload
+// error handler
+func wrapPutError(request *http.Request, err error) error {
+	if errors.Is(err, context.Canceled) {
+		return err
+	}
+	log.Errorln("Error with PUT:", err)
+	// Wrap connection setup errors (timeout, DNS, TLS handshake, dial) in ConnectionSetupError
+	// Note: TLS certificate validation errors are handled separately (not retryable)
+	if isTLSCertificateValidationError(err) {
+		// TLS certificate validation error - leave unwrapped, will be wrapped as SpecificationError in upload error handler
+	} else if isContextDeadlineError(err) || isDNSError(err) || (isTLSError(err) && !isTLSCertificateValidationError(err)) || isDialError(err) {
+		err = &ConnectionSetupError{URL: request.URL.String(), Err: err}
+	}
+	return err
+}
+
 // Determine whether to skip a prestage based on whether an object is at a cache
 func skipPrestage(object string, job *TransferJob) bool {
 	var cache url.URL
@@ -4227,51 +4410,91 @@ func createWebDavClient(collectionsUrl *url.URL, token *tokenGenerator, project
 	return
 }
 
-// Depending on the synchronization policy, decide if a object download should be skipped
-func skipDownload(syncLevel SyncLevel, remoteInfo fs.FileInfo, localPath string) bool {
-	if syncLevel == SyncNone {
-		return false
+// Depending on the synchronization policy, decide if a object download should be skipped.
+// If not, returns the reason the object is transferred.
+func skipDownload(job *TransferJob, remoteInfo fs.FileInfo, localPath string, attempts []transferAttemptDetails) (skip bool, reason string) {
+	if job.syncLevel == SyncNone {
+		return false, syncReasonAlways
 	}
 	localInfo, err := os.Stat(localPath)
 	if err != nil {
-		return false
+		return false, syncReasonNew
+	}
+	if job.syncLevel == SyncExist {
+		return true, ""
+	}
+	if localInfo.Size() != remoteInfo.Size() {
+		return false, syncReasonSize
 	}
-	switch syncLevel {
-	case SyncExist:
-		return true
+	switch job.syncLevel {
 	case SyncSize:
-		return localInfo.Size() == remoteInfo.Size()
+		return true, ""
+	case SyncMtime:
+		// Downloads don't preserve the remote modification time, so a local
+		// file at least as new as the remote object is up to date.
+		if remoteInfo.ModTime().IsZero() || localInfo.ModTime().Before(remoteInfo.ModTime()) {
+			return false, syncReasonMtime
+		}
+		return true, ""
+	case SyncChecksum:
+		if localChecksumMatches(job.ctx, job, localPath, attemptUrls(attempts)) {
+			return true, ""
+		}
+		return false, syncReasonChecksum
 	}
-	return false
+	return false, syncReasonAlways
 }
 
-// Depending on the synchronization policy, decide if the upload should be skipped
-func skipUpload(job *TransferJob, localPath string, remoteUrl *pelican_url.PelicanURL) bool {
+// Depending on the synchronization policy, decide if the upload should be skipped.
+// If not, returns the reason the object is transferred.
+func skipUpload(job *TransferJob, localPath string, remoteUrl *pelican_url.PelicanURL, attempts []transferAttemptDetails) (skip bool, reason string) {
 	if job.syncLevel == SyncNone {
-		return false
+		return false, syncReasonAlways
 	}
 	// Skip the synchronization check if overwrites are enabled
 	if param.Client_EnableOverwrites.GetBool() {
-		return false
+		return false, syncReasonAlways
 	}
 
 	localInfo, err := os.Stat(localPath)
 	if err != nil {
-		return false
+		return false, syncReasonNew
 	}
 
 	remoteInfo, err := statHttp(remoteUrl, job.dirResp, job.token, nil)
 	if err != nil {
-		return false
+		return false, syncReasonNew
 	}
 
+	if job.syncLevel == SyncExist {
+		return true, ""
+	}
+	if localInfo.Size() != remoteInfo.Size {
+		return false, syncReasonSize
+	}
 	switch job.syncLevel {
-	case SyncExist:
-		return true
 	case SyncSize:
-		return localInfo.Size() == remoteInfo.Size
-	}
-	return false
+		return true, ""
+	case SyncMtime:
+		// The origin sets the modification time when the object is written,
+		// so a remote object at least as new as the local file is up to date.
+		if remoteInfo.ModTime.IsZero() || remoteInfo.ModTime.Before(localInfo.ModTime()) {
+			return false, syncReasonMtime
+		}
+		return true, ""
+	case SyncChecksum:
+		objectUrls := attemptUrls(attempts)
+		for idx, objectUrl := range objectUrls {
+			destCopy := *objectUrl
+			destCopy.Path = computeUploadDestPath(remoteUrl.Path, destCopy.Path)
+			objectUrls[idx] = &destCopy
+		}
+		if localChecksumMatches(job.ctx, job, localPath, objectUrls) {
+			return true, ""
+		}
+		return false, syncReasonChecksum
+	}
+	return false, syncReasonAlways
 }
 
 // Walk a remote collection in a WebDAV server, emitting the files discovered
@@ -4287,6 +4510,41 @@ func (te *TransferEngine) walkDirDownload(job *clientTransferJob, transfers []tr
 	return te.walkDirDownloadHelper(job, transfers, files, url.Path, client)
 }
 
+// Rebase the transfer attempts of a recursive download onto a single object.
+//
+// The attempt URLs use the transfer URL's base, _not the collections URL base_.
+// The transfer URL base may differ: "/" for downloads from XRootD or
+// "/api/v1.0/origin/data" for uploads to a POSIXv2 origin in some configurations.
+// The collections URL base may be different for POSIXv2 versus POSIX origins.  Hence,
+// we should never assume they are comparable.
+//
+// The base is calculated by stripping the federation namespace path from the transfer URL.
+func downloadAttemptsForObject(job *TransferJob, transfers []transferAttemptDetails, objectPath string) []transferAttemptDetails {
+	transferAttempts := make([]transferAttemptDetails, len(transfers))
+	for i, attempt := range transfers {
+		transferAttempts[i] = attempt
+		attemptPath := attempt.Url.Path
+		if attemptPath != "" && !strings.HasSuffix(attemptPath, "/") {
+			attemptPath += "/"
+		}
+		federationPath := job.remoteURL.Path
+		if federationPath != "" && !strings.HasSuffix(federationPath, "/") {
+			federationPath += "/"
+		}
+		log.Debugln("Attempt path:", attemptPath, "federation path:", federationPath)
+		transferBase := strings.TrimSuffix(attemptPath, federationPath)
+		fileURL := &url.URL{
+			Scheme:   attempt.Url.Scheme,
+			Host:     attempt.Url.Host,
+			Path:     path.Join(transferBase, objectPath),
+			RawQuery: attempt.Url.RawQuery,
+		}
+		transferAttempts[i].Url = fileURL
+		log.Debugln("Constructed attempt URL for download:", fileURL.String(), "remote path:", objectPath)
+	}
+	return transferAttempts
+}
+
 // Helper function for the `walkDirDownload`.
 //
 // Recursively walks through the remote server collection, emitting transfer files
@@ -4322,38 +4580,12 @@ func (te *TransferEngine) walkDirDownloadHelper(job *clientTransferJob, transfer
 			}
 			// If the path leads to a file and not a collection, create a job to download the file and return
 			if !info.IsDir() {
-				if skipDownload(job.job.syncLevel, info, job.job.localPath) {
+				transferAttempts := downloadAttemptsForObject(job.job, transfers, remotePath)
+				if skip, reason := skipDownload(job.job, info, job.job.localPath, transferAttempts); skip {
 					log.Infoln("Skipping download of object", remotePath, "as it already exists at", job.job.localPath)
+					job.job.reportSync(SyncChange{Action: SyncActionSkip, Path: job.job.localPath})
 				} else {
-					// Construct URL using the transfer URL's base, _not the collections URL base_
-					// The transfer URL base may differ: "/" for downloads from XRootD or
-					// "/api/v1.0/origin/data" for uploads to a POSIXv2 origin in some configurations.
-					// The collections URL base may be different for POSIXv2 versus POSIX origins.  Hence,
-					// we should never assume they are comparable.
-					//
-					// Calculate the base by stripping the federation namespace path from the transfer URL
-					transferAttempts := make([]transferAttemptDetails, len(transfers))
-					for i, attempt := range transfers {
-						transferAttempts[i] = attempt
-						attemptPath := attempt.Url.Path
-						if attemptPath != "" && !strings.HasSuffix(attemptPath, "/") {
-							attemptPath += "/"
-						}
-						federationPath := job.job.remoteURL.Path
-						if federationPath != "" && !strings.HasSuffix(federationPath, "/") {
-							federationPath += "/"
-						}
-						log.Debugln("Attempt path:", attemptPath, "federation path:", federationPath)
-						transferBase := strings.TrimSuffix(attemptPath, federationPath)
-						fileURL := &url.URL{
-							Scheme:   attempt.Url.Scheme,
-							Host:     attempt.Url.Host,
-							Path:     path.Join(transferBase, remotePath),
-							RawQuery: attempt.Url.RawQuery,
-						}
-						transferAttempts[i].Url = fileURL
-						log.Debugln("Constructed attempt URL for download:", fileURL.String(), "remote path:", remotePath)
-					}
+					job.job.reportSync(SyncChange{Action: SyncActionTransfer, Path: job.job.localPath, Reason: reason})
 					job.job.activeXfer.Add(1)
 					select {
 					case <-job.job.ctx.Done():
@@ -4387,6 +4619,12 @@ func (te *TransferEngine) walkDirDownloadHelper(job *clientTransferJob, transfer
 		return errors.Wrap(err, "failed to read remote collection")
 	}
 	localBase := strings.TrimPrefix(remotePath, job.job.remoteURL.Path)
+	// Remove stale local entries before queuing any downloads into this directory
+	if job.job.syncDelete && job.job.xferType == transferTypeDownload && job.job.localPath != os.DevNull {
+		if err := deleteExtraneousLocal(job.job, path.Join(job.job.localPath, localBase), infos); err != nil {
+			return err
+		}
+	}
 	for _, info := range infos {
 		newPath := path.Join(remotePath, info.Name())
 		if info.IsDir() {
@@ -4406,34 +4644,15 @@ func (te *TransferEngine) walkDirDownloadHelper(job *clientTransferJob, transfer
 				targetPath = path.Join(job.job.localPath, localBase, info.Name())
 			}
 
-			if job.job.xferType == transferTypeDownload && skipDownload(job.job.syncLevel, info, targetPath) {
-				log.Infoln("Skipping download of object", newPath, "as it already exists at", targetPath)
-				continue
-			}
-
-			// Construct URL using the transfer URL's base, not the collections URL base.
-			// The two bases may differ in arbitrary ways; see notes above for explanation.
-			transferAttempts := make([]transferAttemptDetails, len(transfers))
-			for i, attempt := range transfers {
-				transferAttempts[i] = attempt
-				attemptPath := attempt.Url.Path
-				if attemptPath != "" && !strings.HasSuffix(attemptPath, "/") {
-					attemptPath += "/"
-				}
-				federationPath := job.job.remoteURL.Path
-				if federationPath != "" && !strings.HasSuffix(federationPath, "/") {
-					federationPath += "/"
-				}
-				log.Debugln("Attempt path:", attemptPath, "federation path:", federationPath)
-				transferBase := strings.TrimSuffix(attemptPath, federationPath)
-				fileURL := &url.URL{
-					Scheme:   attempt.Url.Scheme,
-					Host:     attempt.Url.Host,
-					Path:     path.Join(transferBase, newPath),
-					RawQuery: attempt.Url.RawQuery,
+			transferAttempts := downloadAttemptsForObject(job.job, transfers, newPath)
+			if job.job.xferType == transferTypeDownload {
+				skip, reason := skipDownload(job.job, info, targetPath, transferAttempts)
+				if skip {
+					log.Infoln("Skipping download of object", newPath, "as it already exists at", targetPath)
+					job.job.reportSync(SyncChange{Action: SyncActionSkip, Path: targetPath})
+					continue
 				}
-				transferAttempts[i].Url = fileURL
-				log.Debugln("Constructed attempt URL for download:", fileURL.String(), "remote path:", remotePath)
+				job.job.reportSync(SyncChange{Action: SyncActionTransfer, Path: targetPath, Reason: reason})
 			}
 			job.job.activeXfer.Add(1)
 			select {
@@ -4485,9 +4704,12 @@ func (te *TransferEngine) walkDirUpload(job *clientTransferJob, transfers []tran
 		}
 		// If the path leads to a file and not a directory, create a job to upload the file and return
 		if !info.IsDir() {
-			if remotePath := path.Join(job.job.remoteURL.Path, strings.TrimPrefix(localPath, job.job.localPath)); skipUpload(job.job, localPath, job.job.remoteURL) {
+			remotePath := path.Join(job.job.remoteURL.Path, strings.TrimPrefix(localPath, job.job.localPath))
+			if skip, reason := skipUpload(job.job, localPath, job.job.remoteURL, transfers); skip {
 				log.Infoln("Skipping upload of object", remotePath, "as it already exists at the destination")
+				job.job.reportSync(SyncChange{Action: SyncActionSkip, Path: remotePath})
 			} else if info.Mode().Type().IsRegular() {
+				job.job.reportSync(SyncChange{Action: SyncActionTransfer, Path: remotePath, Reason: reason})
 				job.job.activeXfer.Add(1)
 				select {
 				case <-job.job.ctx.Done():
@@ -4517,6 +4739,13 @@ func (te *TransferEngine) walkDirUpload(job *clientTransferJob, transfers []tran
 		return error_codes.NewParameterError(errors.Wrap(err, "failed to upload local collection"))
 	}
 
+	if job.job.syncDelete {
+		remoteDir := path.Join(job.job.remoteURL.Path, strings.TrimPrefix(localPath, job.job.localPath))
+		if err := deleteExtraneousRemote(job.job, remoteDir, infos); err != nil {
+			return err
+		}
+	}
+
 	for _, info := range infos {
 		newPath := localPath + "/" + info.Name()
 		remoteUrl, err := pelican_url.Parse(job.job.remoteURL.String(), nil, nil)
@@ -4531,9 +4760,13 @@ func (te *TransferEngine) walkDirUpload(job *clientTransferJob, transfers []tran
 			if err != nil {
 				return err
 			}
-		} else if skipUpload(job.job, newPath, remoteUrl) {
+		} else if !info.Type().IsRegular() {
+			continue
+		} else if skip, reason := skipUpload(job.job, newPath, remoteUrl, transfers); skip {
 			log.Infoln("Skipping upload of object", remoteUrl.Path, "as it already exists at the destination")
-		} else if info.Type().IsRegular() {
+			job.job.reportSync(SyncChange{Action: SyncActionSkip, Path: remoteUrl.Path})
+		} else {
+			job.job.reportSync(SyncChange{Action: SyncActionTransfer, Path: remoteUrl.Path, Reason: reason})
 			job.job.activeXfer.Add(1)
 			select {
 			case <-job.job.ctx.Done():
@@ -5104,6 +5337,7 @@ func objectCached(ctx context.Context, objectUrl *url.URL, token *tokenGenerator
 		return
 	}
 	headRequest.Header.Set("Range", "0-0")
+	tracing.InjectHeaders(ctx, headRequest.Header)
 	if token != nil {
 		if tokenContents, err := token.Get(); err == nil && tokenContents != "" {
 			headRequest.Header.Set("Authorization", "Bearer "+tokenContents)
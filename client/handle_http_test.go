@@ -25,12 +25,15 @@ import (
 	"context"
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/sha256"
 	"crypto/tls"
 	"crypto/x509"
 	"crypto/x509/pkix"
+	"encoding/base64"
 	"encoding/hex"
 	"encoding/pem"
 	"fmt"
+	"hash/adler32"
 	"io"
 	"io/fs"
 	"math/big"
@@ -1786,6 +1789,73 @@ func TestChecksum(t *testing.T) {
 	assert.Equal(t, ChecksumType(AlgCRC32C), info.Algorithm)
 }
 
+// Test that SHA-256 and Adler-32 digests are requested, parsed and verified
+func TestChecksumSHA256Adler32(t *testing.T) {
+	t.Cleanup(test_utils.SetupTestLogging(t))
+	test_utils.InitClient(t, map[param.Param]any{
+		param.Logging_Level: "debug",
+	})
+
+	content := []byte("test file content")
+	sha256Sum := sha256.Sum256(content)
+	adler32Sum := adler32.Checksum(content)
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "HEAD" {
+			assert.Contains(t, r.Header.Get("Want-Digest"), "sha-256")
+			assert.Contains(t, r.Header.Get("Want-Digest"), "adler32")
+			w.Header().Set("Content-Length", "17")
+			w.Header().Set("Digest", fmt.Sprintf("SHA-256=%s, adler32=%08x", base64.StdEncoding.EncodeToString(sha256Sum[:]), adler32Sum))
+			w.WriteHeader(http.StatusOK)
+		} else if r.Method == "GET" {
+			w.Header().Set("Content-Length", "17")
+			w.WriteHeader(http.StatusOK)
+			_, err := w.Write(content)
+			assert.NoError(t, err)
+		} else {
+			t.Fatal("Unexpected method:", r.Method)
+		}
+	}))
+	defer svr.Close()
+	svrURL, err := url.Parse(svr.URL)
+	require.NoError(t, err)
+
+	transfer := &transferFile{
+		xferType: transferTypeDownload,
+		ctx:      context.Background(),
+		job: &TransferJob{
+			requireChecksum:    true,
+			requestedChecksums: []ChecksumType{AlgSHA256, AlgAdler32},
+			remoteURL: &pelican_url.PelicanURL{
+				Scheme: "pelican://",
+				Host:   svrURL.Host,
+				Path:   svrURL.Path + "/test.txt",
+			},
+		},
+		localPath: os.DevNull,
+		remoteURL: svrURL,
+		attempts: []transferAttemptDetails{
+			{
+				Url: svrURL,
+			},
+		},
+		requireChecksum:    true,
+		requestedChecksums: []ChecksumType{AlgSHA256, AlgAdler32},
+	}
+	transferResult, err := downloadObject(transfer)
+	require.NoError(t, err)
+	require.NoError(t, transferResult.Error)
+
+	require.Len(t, transferResult.ServerChecksums, 2)
+	assert.Equal(t, ChecksumInfo{Algorithm: AlgSHA256, Value: sha256Sum[:]}, transferResult.ServerChecksums[0])
+	assert.Equal(t, AlgAdler32, transferResult.ServerChecksums[1].Algorithm)
+	assert.Equal(t, fmt.Sprintf("%08x", adler32Sum), hex.EncodeToString(transferResult.ServerChecksums[1].Value))
+	assert.ElementsMatch(t, transferResult.ServerChecksums, transferResult.ClientChecksums)
+
+	for _, alg := range []ChecksumType{AlgSHA256, AlgAdler32} {
+		assert.Equal(t, alg, ChecksumFromHttpDigest(HttpDigestFromChecksum(alg)))
+	}
+}
+
 // Test behavior when checksum is incorrect
 func TestChecksumIncorrectWhenRequired(t *testing.T) {
 	t.Cleanup(test_utils.SetupTestLogging(t))
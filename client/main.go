@@ -741,7 +741,7 @@ func DoGet(ctx context.Context, remoteObject string, localDestination string, re
 	if err != nil {
 		return
 	}
-	tj, err := tc.NewTransferJob(context.Background(), pUrl.GetRawUrl(), localDestination, false, recursive)
+	tj, err := tc.NewTransferJob(context.Background(), pUrl.GetRawUrl(), localDestination, false, recursive, options...)
 	if err != nil {
 		return
 	}
@@ -809,33 +809,19 @@ func DoCopy(ctx context.Context, sourceFile string, destination string, recursiv
 		}
 	}()
 
-	var isPut bool
-	// determine which direction we're headed
-	parsedDest, err := url.Parse(destination)
-	if err != nil {
-		log.Errorln("Failed to parse destination URL:", err)
-		return nil, err
+	if isRemoteCopy(sourceFile, destination) {
+		log.Debugf("Detected a copy from %s to %s", sourceFile, destination)
+		return doRemoteCopy(ctx, sourceFile, destination, recursive, options...)
 	}
-	parsedSrc, err := url.Parse(sourceFile)
+
+	localPath, remotePath, isPut, err := copyEndpoints(sourceFile, destination)
 	if err != nil {
-		log.Errorln("Failed to parse source URL:", err)
 		return nil, err
 	}
-
-	var localPath string
-	var remotePath string
-	if parsedDest.Scheme != "" && (parsedSrc.Scheme == "" || parsedSrc.Scheme == "file") {
-		isPut = true
-		log.Debugf("Detected a PUT from %s to %s", parsedSrc.Path, parsedDest.String())
-		localPath = parsedSrc.Path
-		remotePath = parsedDest.String()
-	} else if (parsedDest.Scheme == "" || parsedDest.Scheme == "file") && parsedSrc.Scheme != "" {
-		isPut = false
-		log.Debugf("Detected a GET from %s to %s", parsedSrc.String(), parsedDest.Path)
-		localPath = parsedDest.Path
-		remotePath = parsedSrc.String()
+	if isPut {
+		log.Debugf("Detected a PUT from %s to %s", localPath, remotePath)
 	} else {
-		return nil, errors.New("unable to determine direction of transfer.  Both source and destination are either local or remote")
+		log.Debugf("Detected a GET from %s to %s", remotePath, localPath)
 	}
 
 	if isPut {
@@ -845,6 +831,29 @@ func DoCopy(ctx context.Context, sourceFile string, destination string, recursiv
 	}
 }
 
+// Determine the direction of a copy from the schemes of its source and
+// destination: exactly one of them must be a remote URL.
+func copyEndpoints(source string, destination string) (localPath string, remotePath string, isPut bool, err error) {
+	parsedDest, err := url.Parse(destination)
+	if err != nil {
+		log.Errorln("Failed to parse destination URL:", err)
+		return
+	}
+	parsedSrc, err := url.Parse(source)
+	if err != nil {
+		log.Errorln("Failed to parse source URL:", err)
+		return
+	}
+
+	if parsedDest.Scheme != "" && (parsedSrc.Scheme == "" || parsedSrc.Scheme == "file") {
+		return parsedSrc.Path, parsedDest.String(), true, nil
+	} else if (parsedDest.Scheme == "" || parsedDest.Scheme == "file") && parsedSrc.Scheme != "" {
+		return parsedDest.Path, parsedSrc.String(), false, nil
+	}
+	err = errors.New("unable to determine direction of transfer.  Both source and destination are either local or remote")
+	return
+}
+
 // getIPs will resolve a hostname and return all corresponding IP addresses
 // in DNS.  This can be used to randomly pick an IP when DNS round robin
 // is used
@@ -0,0 +1,517 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+type (
+	// ManifestEntry is one object of a bulk transfer manifest: where to
+	// transfer it from and to, and optionally the size and checksum the
+	// transferred object must have.
+	ManifestEntry struct {
+		Source      string `json:"source"`
+		Destination string `json:"destination,omitempty"`
+		Size        *int64 `json:"size,omitempty"`     // Expected size in bytes
+		Checksum    string `json:"checksum,omitempty"` // Expected checksum, formatted as "<algorithm>:<hex value>"
+	}
+
+	// ManifestResult is the outcome of the transfer of a manifest entry, as
+	// written to a result manifest.
+	ManifestResult struct {
+		Source           string `json:"source"`
+		Destination      string `json:"destination,omitempty"`
+		Success          bool   `json:"success"`
+		TransferredBytes int64  `json:"transferred_bytes"`
+		ExpectedSize     *int64 `json:"expected_size,omitempty"`
+		Checksum         string `json:"checksum,omitempty"` // Checksum computed by the client while transferring the object
+		ExpectedChecksum string `json:"expected_checksum,omitempty"`
+		Attempts         int    `json:"attempts"`
+		Error            string `json:"error,omitempty"`
+		Retryable        bool   `json:"retryable,omitempty"`
+	}
+)
+
+// The operations a manifest can be used for
+const (
+	ManifestGet      = "get"
+	ManifestPut      = "put"
+	ManifestCopy     = "copy"
+	ManifestPrestage = "prestage"
+)
+
+// The names of the checksum algorithms in manifests.  "sha" and "sha-256"
+// are accepted for consistency with the HTTP digest names used elsewhere.
+var manifestChecksumAlgorithms = map[string]ChecksumType{
+	"md5":     AlgMD5,
+	"crc32c":  AlgCRC32C,
+	"crc32":   AlgCRC32,
+	"sha1":    AlgSHA1,
+	"sha":     AlgSHA1,
+	"sha256":  AlgSHA256,
+	"sha-256": AlgSHA256,
+	"adler32": AlgAdler32,
+}
+
+// ParseChecksum parses a checksum formatted as "<algorithm>:<hex value>",
+// e.g. "md5:d41d8cd98f00b204e9800998ecf8427e".  The algorithm is one of md5,
+// crc32c, crc32, sha1, sha256 or adler32.
+func ParseChecksum(value string) (ChecksumInfo, error) {
+	algName, hexValue, found := strings.Cut(strings.TrimSpace(value), ":")
+	if !found {
+		return ChecksumInfo{}, errors.Errorf("invalid checksum %q; expected <algorithm>:<hex value>", value)
+	}
+	alg, ok := manifestChecksumAlgorithms[strings.ToLower(algName)]
+	if !ok {
+		return ChecksumInfo{}, errors.Errorf("unknown checksum algorithm %q; valid algorithms are md5, crc32c, crc32, sha1, sha256 and adler32", algName)
+	}
+	checksum, err := hex.DecodeString(hexValue)
+	if err != nil || len(checksum) == 0 {
+		return ChecksumInfo{}, errors.Errorf("invalid %s checksum value %q; expected a hex string", algName, hexValue)
+	}
+	if size := newChecksumHash(alg).Size(); len(checksum) != size {
+		return ChecksumInfo{}, errors.Errorf("invalid %s checksum value %q; expected %d hex characters", algName, hexValue, 2*size)
+	}
+	return ChecksumInfo{Algorithm: alg, Value: checksum}, nil
+}
+
+// FormatChecksum formats a checksum the way ParseChecksum reads it
+func FormatChecksum(checksum ChecksumInfo) string {
+	algName := strings.ReplaceAll(HttpDigestFromChecksum(checksum.Algorithm), "-", "")
+	if checksum.Algorithm == AlgSHA1 {
+		algName = "sha1"
+	}
+	return algName + ":" + hex.EncodeToString(checksum.Value)
+}
+
+// ReadManifest reads a manifest from the given file, or from stdin if the
+// path is "-".  See ParseManifest for the format.
+func ReadManifest(manifestPath string) ([]ManifestEntry, error) {
+	if manifestPath == "-" {
+		return ParseManifest(os.Stdin)
+	}
+	file, err := os.Open(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ParseManifest(file)
+}
+
+// ParseManifest parses a manifest of objects to transfer.
+//
+// The manifest is either JSON Lines, with one ManifestEntry object per line,
+// or CSV with the columns source, destination, size and checksum.  A CSV
+// manifest may start with a header naming its columns, in which case they
+// can be in any order and the trailing ones omitted.  Empty fields, blank
+// lines and lines starting with '#' are ignored.  The format is detected from
+// the first entry: JSON Lines entries start with '{'.
+func ParseManifest(r io.Reader) ([]ManifestEntry, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read manifest")
+	}
+	isJSON := false
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+		isJSON = line[0] == '{'
+		break
+	}
+
+	var entries []ManifestEntry
+	if isJSON {
+		entries, err = parseManifestJSONLines(data)
+	} else {
+		entries, err = parseManifestCSV(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, errors.New("manifest has no entries")
+	}
+	return entries, nil
+}
+
+func parseManifestJSONLines(data []byte) ([]ManifestEntry, error) {
+	var entries []ManifestEntry
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+		var entry ManifestEntry
+		decoder := json.NewDecoder(bytes.NewReader(line))
+		// Catch misspelled fields rather than silently skipping their checks
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&entry); err != nil {
+			return nil, errors.Wrapf(err, "invalid manifest entry on line %d", lineNum)
+		}
+		if err := entry.validate(); err != nil {
+			return nil, errors.Wrapf(err, "invalid manifest entry on line %d", lineNum)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to read manifest")
+	}
+	return entries, nil
+}
+
+func parseManifestCSV(data []byte) ([]ManifestEntry, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	columns := map[string]int{"source": 0, "destination": 1, "size": 2, "checksum": 3}
+	field := func(record []string, name string) string {
+		if idx := columns[name]; idx >= 0 && idx < len(record) {
+			return strings.TrimSpace(record[idx])
+		}
+		return ""
+	}
+
+	var entries []ManifestEntry
+	first := true
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid CSV manifest")
+		}
+		line, _ := reader.FieldPos(0)
+		if first {
+			first = false
+			header := make(map[string]int, len(record))
+			for idx, name := range record {
+				name = strings.ToLower(strings.TrimSpace(name))
+				if _, ok := columns[name]; ok {
+					header[name] = idx
+				}
+			}
+			// A header row names the columns, which may come in any order
+			if len(header) > 0 {
+				for name := range columns {
+					if idx, ok := header[name]; ok {
+						columns[name] = idx
+					} else {
+						columns[name] = -1
+					}
+				}
+				if columns["source"] < 0 {
+					return nil, errors.New("CSV manifest header has no source column")
+				}
+				continue
+			}
+		}
+
+		entry := ManifestEntry{
+			Source:      field(record, "source"),
+			Destination: field(record, "destination"),
+			Checksum:    field(record, "checksum"),
+		}
+		if sizeStr := field(record, "size"); sizeStr != "" {
+			size, err := strconv.ParseInt(sizeStr, 10, 64)
+			if err != nil {
+				return nil, errors.Errorf("invalid manifest entry on line %d: invalid size %q", line, sizeStr)
+			}
+			entry.Size = &size
+		}
+		if err := entry.validate(); err != nil {
+			return nil, errors.Wrapf(err, "invalid manifest entry on line %d", line)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func (entry ManifestEntry) validate() error {
+	if entry.Source == "" {
+		return errors.New("missing source")
+	}
+	if entry.Size != nil && *entry.Size < 0 {
+		return errors.Errorf("invalid size %d", *entry.Size)
+	}
+	if entry.Checksum != "" {
+		if _, err := ParseChecksum(entry.Checksum); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Options returns the transfer options checking the transferred object
+// against the entry's expected size and checksum
+func (entry ManifestEntry) Options() ([]TransferOption, error) {
+	var options []TransferOption
+	if entry.Size != nil {
+		options = append(options, WithExpectedSize(*entry.Size))
+	}
+	if entry.Checksum != "" {
+		checksum, err := ParseChecksum(entry.Checksum)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, WithExpectedChecksum(checksum))
+	}
+	return options, nil
+}
+
+// Make sure the client computes the expected checksum, so it's part of the
+// transfer results
+func (tj *TransferJob) requestExpectedChecksum() {
+	if tj.expectedChecksum == nil {
+		return
+	}
+	if len(tj.requestedChecksums) == 0 {
+		tj.requestedChecksums = []ChecksumType{AlgDefault}
+	}
+	for _, checksumType := range tj.requestedChecksums {
+		if checksumType == tj.expectedChecksum.Algorithm {
+			return
+		}
+	}
+	tj.requestedChecksums = append(tj.requestedChecksums, tj.expectedChecksum.Algorithm)
+}
+
+// Check a transferred object against the size and checksum expected by the
+// job, if any.  The checksum is verified like a server-provided one, against
+// the checksums the client computed during the transfer.
+func verifyExpectedObject(job *TransferJob, allComputed map[ChecksumType][]byte, transferred int64, fields log.Fields) error {
+	if job == nil {
+		return nil
+	}
+	if job.expectedSize != nil && transferred != *job.expectedSize {
+		return errors.Errorf("transferred %d bytes but the object was expected to be %d bytes", transferred, *job.expectedSize)
+	}
+	if job.expectedChecksum == nil {
+		return nil
+	}
+	if _, err := verifyTransferChecksums(
+		allComputed, []ChecksumType{job.expectedChecksum.Algorithm}, []ChecksumInfo{*job.expectedChecksum}, true, fields,
+	); err != nil {
+		return errors.Wrap(err, "object does not match its expected checksum")
+	}
+	return nil
+}
+
+// Build the result manifest line for an entry; results is nil if the
+// transfer couldn't be started
+func newManifestResult(entry ManifestEntry, results *TransferResults, err error) ManifestResult {
+	result := ManifestResult{
+		Source:           entry.Source,
+		Destination:      entry.Destination,
+		Success:          err == nil,
+		ExpectedSize:     entry.Size,
+		ExpectedChecksum: entry.Checksum,
+	}
+	if results != nil {
+		result.TransferredBytes = results.TransferredBytes
+		result.Attempts = len(results.Attempts)
+		expected, _ := ParseChecksum(entry.Checksum)
+		for idx, checksum := range results.ClientChecksums {
+			// Report the checksum in the same algorithm as the expected one
+			if idx == 0 || (entry.Checksum != "" && checksum.Algorithm == expected.Algorithm) {
+				result.Checksum = FormatChecksum(checksum)
+			}
+		}
+	}
+	if err != nil {
+		result.Error = err.Error()
+		var te *TransferErrors
+		if errors.As(err, &te) {
+			result.Error = te.UserError()
+		}
+		result.Retryable = ShouldRetry(err)
+	}
+	return result
+}
+
+// Create the transfer job for a manifest entry
+func newManifestJob(ctx context.Context, tc *TransferClient, operation string, entry ManifestEntry, options []TransferOption) (*TransferJob, error) {
+	entryOptions, err := entry.Options()
+	if err != nil {
+		return nil, err
+	}
+	options = append(append([]TransferOption{}, options...), entryOptions...)
+
+	var localPath, remotePath string
+	upload := false
+	switch operation {
+	case ManifestGet:
+		remotePath, localPath = entry.Source, entry.Destination
+	case ManifestPut:
+		localPath, remotePath, upload = entry.Source, entry.Destination, true
+	case ManifestCopy:
+		if localPath, remotePath, upload, err = copyEndpoints(entry.Source, entry.Destination); err != nil {
+			return nil, err
+		}
+	case ManifestPrestage:
+		remoteUrl, err := url.Parse(entry.Source)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse source URL")
+		}
+		return tc.NewPrestageJob(ctx, remoteUrl, options...)
+	default:
+		return nil, errors.Errorf("unknown manifest operation %q", operation)
+	}
+	if localPath == "" || remotePath == "" {
+		return nil, errors.New("manifest entry needs both a source and a destination")
+	}
+
+	remoteUrl, err := url.Parse(remotePath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse remote URL")
+	}
+	if !upload {
+		// As with DoGet, a download into an existing directory keeps the object's name
+		if info, err := os.Stat(localPath); err == nil && info.IsDir() {
+			localPath = filepath.Join(localPath, path.Base(remoteUrl.Path))
+		}
+		if absPath, err := filepath.Abs(localPath); err == nil {
+			localPath = absPath
+		}
+	}
+	return tc.NewTransferJob(ctx, remoteUrl, localPath, upload, false, options...)
+}
+
+// DoManifest transfers the objects listed in a manifest with a single
+// transfer engine, so that the transfers run concurrently.
+//
+// The operation is one of ManifestGet, ManifestPut, ManifestCopy or
+// ManifestPrestage; the direction of each copy is determined as in DoCopy.
+// Each object is checked against the size and checksum of its entry, if
+// given, and report is called with the result of every entry as its
+// transfer completes.  A failed entry doesn't stop the others; the returned
+// error is only for failures to run the manifest at all.
+func DoManifest(ctx context.Context, operation string, entries []ManifestEntry, report func(ManifestResult), options ...TransferOption) (err error) {
+	switch operation {
+	case ManifestGet, ManifestPut, ManifestCopy, ManifestPrestage:
+	default:
+		return errors.Errorf("unknown manifest operation %q", operation)
+	}
+
+	te, err := NewTransferEngine(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if shutdownErr := te.Shutdown(); shutdownErr != nil && err == nil {
+			err = shutdownErr
+		}
+	}()
+	tc, err := te.NewClient(options...)
+	if err != nil {
+		return err
+	}
+	resultsChan := tc.Results()
+
+	var mu sync.Mutex // Protects the maps below and serializes the calls to report
+	jobs := make(map[string]int, len(entries))
+	submitted := make(map[int]*TransferJob, len(entries))
+	reported := make(map[int]bool, len(entries))
+	reportEntry := func(idx int, results *TransferResults, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		reported[idx] = true
+		report(newManifestResult(entries[idx], results, err))
+	}
+
+	go func() {
+		defer tc.Close()
+		for idx, entry := range entries {
+			if ctx.Err() != nil {
+				return
+			}
+			tj, err := newManifestJob(ctx, tc, operation, entry, options)
+			if err != nil {
+				reportEntry(idx, nil, err)
+				continue
+			}
+			mu.Lock()
+			jobs[tj.ID()] = idx
+			submitted[idx] = tj
+			mu.Unlock()
+			if err := tc.Submit(tj); err != nil {
+				return
+			}
+		}
+	}()
+
+	for result := range resultsChan {
+		mu.Lock()
+		idx, ok := jobs[result.ID()]
+		mu.Unlock()
+		if !ok {
+			log.Warningln("Ignoring result of unknown transfer job", result.ID())
+			continue
+		}
+		reportEntry(idx, &result, result.Error)
+	}
+
+	// Jobs whose lookup failed, or that never ran, have no results
+	for idx := range entries {
+		mu.Lock()
+		done := reported[idx]
+		tj := submitted[idx]
+		mu.Unlock()
+		if done {
+			continue
+		}
+		var entryErr error
+		if tj != nil {
+			_, entryErr = tj.GetLookupStatus()
+		}
+		if entryErr == nil {
+			entryErr = ctx.Err()
+		}
+		if entryErr == nil {
+			entryErr = errors.New("transfer did not complete")
+		}
+		reportEntry(idx, nil, entryErr)
+	}
+	return nil
+}
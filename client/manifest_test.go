@@ -0,0 +1,152 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package client
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseChecksum(t *testing.T) {
+	checksum, err := ParseChecksum("md5:d41d8cd98f00b204e9800998ecf8427e")
+	require.NoError(t, err)
+	assert.Equal(t, AlgMD5, checksum.Algorithm)
+	assert.Equal(t, "md5:d41d8cd98f00b204e9800998ecf8427e", FormatChecksum(checksum))
+
+	checksum, err = ParseChecksum("SHA:da39a3ee5e6b4b0d3255bfef95601890afd80709")
+	require.NoError(t, err)
+	assert.Equal(t, AlgSHA1, checksum.Algorithm)
+	assert.Equal(t, "sha1:da39a3ee5e6b4b0d3255bfef95601890afd80709", FormatChecksum(checksum))
+
+	checksum, err = ParseChecksum("sha-256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855")
+	require.NoError(t, err)
+	assert.Equal(t, AlgSHA256, checksum.Algorithm)
+	assert.Equal(t, "sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", FormatChecksum(checksum))
+
+	checksum, err = ParseChecksum("adler32:00000001")
+	require.NoError(t, err)
+	assert.Equal(t, AlgAdler32, checksum.Algorithm)
+	assert.Equal(t, "adler32:00000001", FormatChecksum(checksum))
+
+	for _, invalid := range []string{"", "d41d8cd98f00b204e9800998ecf8427e", "sha512:00", "sha256:00", "crc32c:", "crc32c:xyz"} {
+		_, err := ParseChecksum(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestParseManifest(t *testing.T) {
+	size := int64(1024)
+
+	t.Run("json-lines", func(t *testing.T) {
+		entries, err := ParseManifest(strings.NewReader(`
+# Comments and blank lines are skipped
+{"source": "pelican://example.com/a", "destination": "/tmp/a", "size": 1024, "checksum": "crc32c:1a2b3c4d"}
+
+{"source": "pelican://example.com/b"}
+`))
+		require.NoError(t, err)
+		assert.Equal(t, []ManifestEntry{
+			{Source: "pelican://example.com/a", Destination: "/tmp/a", Size: &size, Checksum: "crc32c:1a2b3c4d"},
+			{Source: "pelican://example.com/b"},
+		}, entries)
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		entries, err := ParseManifest(strings.NewReader("pelican://example.com/a,/tmp/a,1024,crc32c:1a2b3c4d\npelican://example.com/b,/tmp/b\n"))
+		require.NoError(t, err)
+		assert.Equal(t, []ManifestEntry{
+			{Source: "pelican://example.com/a", Destination: "/tmp/a", Size: &size, Checksum: "crc32c:1a2b3c4d"},
+			{Source: "pelican://example.com/b", Destination: "/tmp/b"},
+		}, entries)
+	})
+
+	t.Run("csv-header", func(t *testing.T) {
+		entries, err := ParseManifest(strings.NewReader("checksum,source\ncrc32c:1a2b3c4d,pelican://example.com/a\n,pelican://example.com/b\n"))
+		require.NoError(t, err)
+		assert.Equal(t, []ManifestEntry{
+			{Source: "pelican://example.com/a", Checksum: "crc32c:1a2b3c4d"},
+			{Source: "pelican://example.com/b"},
+		}, entries)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for name, manifest := range map[string]string{
+			"empty":            "# nothing to transfer\n",
+			"unknown-field":    `{"source": "pelican://example.com/a", "checksums": "md5:00"}`,
+			"missing-source":   `{"destination": "/tmp/a"}`,
+			"bad-checksum":     "pelican://example.com/a,/tmp/a,,md5:xyz\n",
+			"bad-size":         "pelican://example.com/a,/tmp/a,big\n",
+			"negative-size":    `{"source": "pelican://example.com/a", "size": -1}`,
+			"header-no-source": "destination,size\n/tmp/a,10\n",
+		} {
+			_, err := ParseManifest(strings.NewReader(manifest))
+			assert.Error(t, err, name)
+		}
+	})
+
+	t.Run("line-numbers", func(t *testing.T) {
+		_, err := ParseManifest(strings.NewReader("{\"source\": \"pelican://example.com/a\"}\n\n{\"source\": \"\"}\n"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "line 3")
+	})
+}
+
+func TestRequestExpectedChecksum(t *testing.T) {
+	tj := &TransferJob{expectedChecksum: &ChecksumInfo{Algorithm: AlgMD5}}
+	tj.requestExpectedChecksum()
+	assert.Equal(t, []ChecksumType{AlgDefault, AlgMD5}, tj.requestedChecksums)
+
+	tj = &TransferJob{requestedChecksums: []ChecksumType{AlgMD5}, expectedChecksum: &ChecksumInfo{Algorithm: AlgMD5}}
+	tj.requestExpectedChecksum()
+	assert.Equal(t, []ChecksumType{AlgMD5}, tj.requestedChecksums)
+}
+
+func TestVerifyExpectedObject(t *testing.T) {
+	contents := []byte("expected contents")
+	sum := md5.Sum(contents)
+	computed := map[ChecksumType][]byte{AlgMD5: sum[:]}
+	fields := log.Fields{"job": "test"}
+	size := int64(len(contents))
+
+	// Nothing is expected of jobs without a manifest entry
+	assert.NoError(t, verifyExpectedObject(&TransferJob{}, computed, 1, fields))
+
+	checksum, err := ParseChecksum("md5:" + hex.EncodeToString(sum[:]))
+	require.NoError(t, err)
+	job := &TransferJob{expectedSize: &size, expectedChecksum: &checksum}
+	assert.NoError(t, verifyExpectedObject(job, computed, size, fields))
+
+	err = verifyExpectedObject(job, computed, size-1, fields)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expected to be")
+
+	other := md5.Sum([]byte("other contents"))
+	err = verifyExpectedObject(job, map[ChecksumType][]byte{AlgMD5: other[:]}, size, fields)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expected checksum")
+	var mismatch *ChecksumMismatchError
+	assert.True(t, errors.As(err, &mismatch))
+}
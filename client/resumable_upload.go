@@ -0,0 +1,252 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package client
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/pelicanplatform/pelican/config"
+	"github.com/pelicanplatform/pelican/error_codes"
+	"github.com/pelicanplatform/pelican/param"
+	"github.com/pelicanplatform/pelican/utils"
+)
+
+// uploadState is the progress of a resumable upload, saved after every chunk
+// so that the upload can continue after the client is interrupted or
+// restarted.  An upload is only resumed if the local file is unchanged.
+type uploadState struct {
+	UploadId  string    `json:"upload_id"`
+	LocalPath string    `json:"local_path"`
+	RemoteURL string    `json:"remote_url"`
+	Size      int64     `json:"size"`
+	ModTime   time.Time `json:"mod_time"`
+	Offset    int64     `json:"offset"`
+
+	// Where the state is saved; empty if there is nowhere to save it
+	path string
+}
+
+// Get the directory where the progress of resumable uploads is recorded
+func getUploadStateDir() (string, error) {
+	if dir := param.Client_UploadStateDirectory.GetString(); dir != "" {
+		return dir, nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get the user's home directory")
+	}
+	return filepath.Join(homeDir, ".pelican", "uploads"), nil
+}
+
+// Load the progress of a previous upload of the local file to the remote
+// object, or start a new upload if there is none or the file has changed
+func loadUploadState(localPath string, remoteURL *url.URL, size int64, modTime time.Time) *uploadState {
+	if absPath, err := filepath.Abs(localPath); err == nil {
+		localPath = absPath
+	}
+	remote := *remoteURL
+	remote.RawQuery = ""
+	state := &uploadState{
+		UploadId:  uuid.NewString(),
+		LocalPath: localPath,
+		RemoteURL: remote.String(),
+		Size:      size,
+		ModTime:   modTime.UTC(),
+	}
+
+	stateDir, err := getUploadStateDir()
+	if err != nil {
+		log.Warningln("Uploads will not be resumable after a restart:", err)
+		return state
+	}
+	sum := sha256.Sum256([]byte(state.LocalPath + "\x00" + state.RemoteURL))
+	state.path = filepath.Join(stateDir, hex.EncodeToString(sum[:])+".json")
+
+	contents, err := os.ReadFile(state.path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Warningf("Failed to read the state of a previous upload from %s: %v", state.path, err)
+		}
+		return state
+	}
+	previous := uploadState{}
+	if err := json.Unmarshal(contents, &previous); err != nil {
+		log.Warningf("Ignoring invalid upload state in %s: %v", state.path, err)
+		return state
+	}
+	if previous.UploadId == "" || previous.LocalPath != state.LocalPath || previous.RemoteURL != state.RemoteURL ||
+		previous.Size != state.Size || !previous.ModTime.Equal(state.ModTime) {
+		log.Debugf("Not resuming the previous upload of %s to %s because the file has changed", state.LocalPath, state.RemoteURL)
+		return state
+	}
+	previous.path = state.path
+	return &previous
+}
+
+// Record the progress of the upload
+func (state *uploadState) save() {
+	if state.path == "" {
+		return
+	}
+	contents, err := json.Marshal(state)
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(state.path), 0700)
+	}
+	if err == nil {
+		// Write to a temporary file first so an interruption can't leave a truncated state behind
+		tmpPath := state.path + ".tmp"
+		if err = os.WriteFile(tmpPath, contents, 0600); err == nil {
+			err = os.Rename(tmpPath, state.path)
+		}
+	}
+	if err != nil {
+		log.Warningf("Failed to save the progress of upload %s to %s: %v", state.UploadId, state.path, err)
+	}
+}
+
+// Forget the upload once it's complete or abandoned
+func (state *uploadState) remove() {
+	if state.path == "" {
+		return
+	}
+	if err := os.Remove(state.path); err != nil && !os.IsNotExist(err) {
+		log.Warningf("Failed to remove the state of upload %s: %v", state.UploadId, err)
+	}
+}
+
+// Get the number of bytes the origin reports having received for an upload
+func uploadOffset(response *http.Response) (int64, error) {
+	offsetStr := response.Header.Get(utils.ResumableUploadOffsetHeader)
+	if offsetStr == "" {
+		return 0, errors.Errorf("response is missing the %s header", utils.ResumableUploadOffsetHeader)
+	}
+	offset, err := strconv.ParseInt(offsetStr, 10, 64)
+	if err != nil || offset < 0 {
+		return 0, errors.Errorf("invalid %s header %q", utils.ResumableUploadOffsetHeader, offsetStr)
+	}
+	return offset, nil
+}
+
+// Perform a resumable upload of the body described by state, in PUTs of up
+// to chunkSize bytes; request is the PUT that would upload the body whole.
+//
+// The origin is first asked how much of the upload it has received; origins
+// without resumable uploads don't know and get the body in a single request
+// instead.  After every chunk, the progress is saved so that a later attempt,
+// possibly by another process, can pick up where this one stopped.
+//
+// Like runPut, this is executed in a separate goroutine and reports through
+// responseChan and errorChan.
+func runResumablePut(request *http.Request, body io.Reader, state *uploadState, chunkSize int64, responseChan chan<- *http.Response, errorChan chan<- error, proxy bool) {
+	client := config.GetClientNoProxy()
+	sendError := func(err error) {
+		errorChan <- wrapPutError(request, err)
+		close(errorChan)
+	}
+
+	statusReq := request.Clone(request.Context())
+	statusReq.Method = http.MethodHead
+	statusReq.Body = http.NoBody
+	statusReq.GetBody = nil
+	statusReq.ContentLength = 0
+	statusReq.Header.Set(utils.ResumableUploadIdHeader, state.UploadId)
+	response, err := client.Do(statusReq)
+	if err != nil {
+		sendError(err)
+		return
+	}
+	response.Body.Close()
+	offset, err := uploadOffset(response)
+	if response.StatusCode != http.StatusOK || err != nil || offset > state.Size {
+		log.Debugf("%s does not offer resumable uploads (HTTP status %d); uploading %s in a single request", request.URL.Host, response.StatusCode, state.LocalPath)
+		state.remove()
+		runPut(request, responseChan, errorChan, proxy)
+		return
+	}
+	if offset > 0 {
+		log.Infof("Resuming upload of %s at byte %d of %d", state.LocalPath, offset, state.Size)
+	}
+	if offset == state.Size {
+		// The origin has every byte but didn't complete the upload; resend
+		// the last one so it tries again
+		offset--
+	}
+	// The skipped bytes still go through the checksums and progress
+	if _, err := io.CopyN(io.Discard, body, offset); err != nil {
+		sendError(errors.Wrapf(err, "failed to read %s", state.LocalPath))
+		return
+	}
+
+	for {
+		chunkLen := min(chunkSize, state.Size-offset)
+		chunkReq := request.Clone(request.Context())
+		chunkReq.Body = io.NopCloser(io.LimitReader(body, chunkLen))
+		chunkReq.GetBody = nil
+		chunkReq.ContentLength = chunkLen
+		chunkReq.Header.Set(utils.ResumableUploadIdHeader, state.UploadId)
+		chunkReq.Header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", offset, offset+chunkLen-1, state.Size))
+		response, err := client.Do(chunkReq)
+		if err != nil {
+			sendError(err)
+			return
+		}
+
+		received, offsetErr := uploadOffset(response)
+		if offsetErr == nil && received <= state.Size {
+			state.Offset = received
+			state.save()
+		}
+		if response.StatusCode != http.StatusPermanentRedirect {
+			if response.StatusCode == http.StatusOK || response.StatusCode == http.StatusCreated {
+				state.remove()
+			} else {
+				log.Errorln("Error status code:", response.Status)
+			}
+			responseChan <- response
+			return
+		}
+		response.Body.Close()
+
+		// The body of a chunk can only be read once; if the origin didn't
+		// get all of it, the next attempt resumes from where the origin is
+		if offsetErr != nil || received != offset+chunkLen {
+			if offsetErr == nil {
+				offsetErr = errors.Errorf("origin has %d bytes of the upload after the chunk ending at byte %d", received, offset+chunkLen)
+			}
+			sendError(error_codes.NewContact_OriginError(errors.Wrap(offsetErr, "chunk of resumable upload was not fully received")))
+			return
+		}
+		offset = received
+		log.Debugf("Uploaded %d of %d bytes of %s", offset, state.Size, state.LocalPath)
+	}
+}
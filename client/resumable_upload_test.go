@@ -0,0 +1,209 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pelicanplatform/pelican/param"
+	"github.com/pelicanplatform/pelican/pelican_url"
+	"github.com/pelicanplatform/pelican/server_structs"
+	"github.com/pelicanplatform/pelican/test_utils"
+	"github.com/pelicanplatform/pelican/utils"
+)
+
+// A minimal origin for resumable uploads that stores a single object
+type resumableUploadServer struct {
+	mu        sync.Mutex
+	resumable bool
+	// Only keep truncateTo bytes of the truncateChunk-th chunk, like a dropped connection
+	truncateChunk int
+	truncateTo    int64
+	staged        map[string][]byte
+	object        []byte
+	chunks        int
+}
+
+func (s *resumableUploadServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	uploadId := r.Header.Get(utils.ResumableUploadIdHeader)
+	switch {
+	case r.Method == "PROPFIND":
+		w.WriteHeader(http.StatusNotFound)
+	case r.Method == http.MethodHead && uploadId != "" && s.resumable:
+		w.Header().Set(utils.ResumableUploadOffsetHeader, strconv.Itoa(len(s.staged[uploadId])))
+		w.WriteHeader(http.StatusOK)
+	case r.Method == http.MethodHead:
+		w.WriteHeader(http.StatusOK)
+	case r.Method == http.MethodPut && r.Header.Get("Content-Range") != "":
+		s.chunks++
+		var first, last, total int
+		_, err := fmt.Sscanf(r.Header.Get("Content-Range"), "bytes %d-%d/%d", &first, &last, &total)
+		if err != nil || first > len(s.staged[uploadId]) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var body io.Reader = r.Body
+		if s.chunks == s.truncateChunk {
+			body = io.LimitReader(r.Body, s.truncateTo)
+		}
+		data, _ := io.ReadAll(body)
+		s.staged[uploadId] = append(s.staged[uploadId][:first], data...)
+		if len(s.staged[uploadId]) < total {
+			w.Header().Set(utils.ResumableUploadOffsetHeader, strconv.Itoa(len(s.staged[uploadId])))
+			w.WriteHeader(http.StatusPermanentRedirect)
+			return
+		}
+		s.object = s.staged[uploadId]
+		delete(s.staged, uploadId)
+		w.WriteHeader(http.StatusCreated)
+	case r.Method == http.MethodPut:
+		s.object, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusCreated)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestResumableUpload(t *testing.T) {
+	t.Cleanup(test_utils.SetupTestLogging(t))
+	stateDir := t.TempDir()
+	test_utils.InitClient(t, map[param.Param]any{
+		param.TLSSkipVerify:                 true,
+		param.Client_UploadChunkSize:        1000,
+		param.Client_UploadStateDirectory:   stateDir,
+		param.Client_StoppedTransferTimeout: "5s",
+	})
+
+	data := bytes.Repeat([]byte("0123456789"), 450)
+	localPath := filepath.Join(t.TempDir(), "testfile.txt")
+	require.NoError(t, os.WriteFile(localPath, data, 0600))
+
+	upload := func(t *testing.T, server *resumableUploadServer) TransferResults {
+		svr := httptest.NewTLSServer(server)
+		t.Cleanup(svr.Close)
+		svrURL, err := url.Parse(svr.URL)
+		require.NoError(t, err)
+		svrURL.Path = "/test/testfile.txt"
+		transfer := &transferFile{
+			ctx: context.Background(),
+			job: &TransferJob{
+				remoteURL: &pelican_url.PelicanURL{
+					Scheme: "pelican://",
+					Host:   svrURL.Host,
+					Path:   svrURL.Path,
+				},
+				dirResp: server_structs.DirectorResponse{
+					XPelNsHdr: server_structs.XPelNs{
+						Namespace:      "/test",
+						CollectionsUrl: svrURL,
+					},
+				},
+			},
+			localPath: localPath,
+			remoteURL: &url.URL{Scheme: "pelican", Host: "federation.example.org", Path: svrURL.Path},
+			attempts:  []transferAttemptDetails{{Url: svrURL}},
+		}
+		result, err := uploadObject(transfer)
+		require.NoError(t, err)
+		return result
+	}
+	stateFiles := func() []string {
+		files, err := filepath.Glob(filepath.Join(stateDir, "*.json"))
+		require.NoError(t, err)
+		return files
+	}
+
+	t.Run("resume-after-interruption", func(t *testing.T) {
+		// The first attempt loses part of the third chunk
+		server := &resumableUploadServer{resumable: true, truncateChunk: 3, truncateTo: 300, staged: map[string][]byte{}}
+		result := upload(t, server)
+		require.Error(t, result.Error)
+		require.Len(t, stateFiles(), 1)
+		contents, err := os.ReadFile(stateFiles()[0])
+		require.NoError(t, err)
+		assert.Contains(t, string(contents), `"offset":2300`)
+
+		// The next attempt only sends what the origin is missing
+		server.chunks = 0
+		server.truncateChunk = 0
+		result = upload(t, server)
+		require.NoError(t, result.Error)
+		assert.Equal(t, data, server.object)
+		assert.Equal(t, 3, server.chunks)
+		assert.Equal(t, int64(len(data)), result.TransferredBytes)
+		assert.Empty(t, stateFiles())
+	})
+
+	t.Run("fallback-to-single-put", func(t *testing.T) {
+		server := &resumableUploadServer{staged: map[string][]byte{}}
+		result := upload(t, server)
+		require.NoError(t, result.Error)
+		assert.Equal(t, data, server.object)
+		assert.Zero(t, server.chunks)
+		assert.Empty(t, stateFiles())
+	})
+}
+
+func TestLoadUploadState(t *testing.T) {
+	test_utils.InitClient(t, map[param.Param]any{
+		param.Client_UploadStateDirectory: t.TempDir(),
+	})
+	remote := &url.URL{Scheme: "pelican", Host: "federation.example.org", Path: "/test/object", RawQuery: "authz=token"}
+	modTime := time.Now()
+
+	state := loadUploadState("testfile", remote, 100, modTime)
+	assert.NotEmpty(t, state.UploadId)
+	assert.Zero(t, state.Offset)
+	// Tokens in the query aren't recorded
+	assert.Equal(t, "pelican://federation.example.org/test/object", state.RemoteURL)
+	state.Offset = 40
+	state.save()
+
+	resumed := loadUploadState("testfile", remote, 100, modTime)
+	assert.Equal(t, state.UploadId, resumed.UploadId)
+	assert.Equal(t, int64(40), resumed.Offset)
+
+	// Changes to the file start a new upload
+	changed := loadUploadState("testfile", remote, 101, modTime)
+	assert.NotEqual(t, state.UploadId, changed.UploadId)
+	changed = loadUploadState("testfile", remote, 100, modTime.Add(time.Second))
+	assert.NotEqual(t, state.UploadId, changed.UploadId)
+	other := loadUploadState("testfile", &url.URL{Scheme: "pelican", Host: "federation.example.org", Path: "/test/other"}, 100, modTime)
+	assert.NotEqual(t, state.UploadId, other.UploadId)
+
+	resumed.remove()
+	assert.NotEqual(t, state.UploadId, loadUploadState("testfile", remote, 100, modTime).UploadId)
+}
@@ -0,0 +1,308 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/pelicanplatform/pelican/param"
+)
+
+type (
+	// A byte range of an object fetched by a striped download
+	stripe struct {
+		start int64 // First byte of the range
+		end   int64 // Last byte of the range (inclusive)
+	}
+
+	// stripeWriter writes a stripe into place in the destination file.
+	// A server ignoring the Range header sends the object from its first
+	// byte; the bytes past the end of the stripe are refused rather than
+	// written over the neighbouring stripes, and the stripe is fetched again.
+	stripeWriter struct {
+		file       *os.File
+		stripe     stripe
+		dest       *io.OffsetWriter
+		remaining  int64
+		overflowed bool
+		written    *atomic.Int64 // Bytes written by all the stripes of the object
+	}
+
+	// The outcome of a striped download
+	stripedDownloadResult struct {
+		downloaded int64
+		etag       string
+		attempts   []TransferResult
+		urls       []*url.URL // The endpoints the stripes were fetched from
+		errors     *TransferErrors
+		err        error
+	}
+)
+
+var errRangeNotSupported = errors.New("server sent more bytes than the requested range; it may not support range requests")
+
+func newStripeWriter(file *os.File, s stripe, written *atomic.Int64) *stripeWriter {
+	return &stripeWriter{
+		file:      file,
+		stripe:    s,
+		dest:      io.NewOffsetWriter(file, s.start),
+		remaining: s.end - s.start + 1,
+		written:   written,
+	}
+}
+
+func (sw *stripeWriter) Write(p []byte) (int, error) {
+	if int64(len(p)) > sw.remaining {
+		sw.overflowed = true
+		return 0, errRangeNotSupported
+	}
+	n, err := sw.dest.Write(p)
+	sw.remaining -= int64(n)
+	sw.written.Add(int64(n))
+	return n, err
+}
+
+// The offset of the next byte of the stripe to fetch.  After an overflow,
+// the bytes written may be from the wrong part of the object, so the stripe
+// starts over.
+func (sw *stripeWriter) next() int64 {
+	if sw.overflowed {
+		sw.written.Add(-(sw.stripe.end - sw.stripe.start + 1 - sw.remaining))
+		sw.dest = io.NewOffsetWriter(sw.file, sw.stripe.start)
+		sw.remaining = sw.stripe.end - sw.stripe.start + 1
+		sw.overflowed = false
+	}
+	return sw.stripe.end + 1 - sw.remaining
+}
+
+// Split an object of the given size into stripes of at most stripeSize bytes
+func splitStripes(size, stripeSize int64) []stripe {
+	stripes := make([]stripe, 0, (size+stripeSize-1)/stripeSize)
+	for start := int64(0); start < size; start += stripeSize {
+		stripes = append(stripes, stripe{start: start, end: min(start+stripeSize, size) - 1})
+	}
+	return stripes
+}
+
+// Get the number of sources to download the object from at once, or 0 if
+// the object should be downloaded from one source at a time.
+//
+// Striping needs the size of the object and a destination file the stripes
+// can be written into at any offset, so byte-range, packed, prestage and
+// custom-writer transfers aren't striped.
+func stripedDownloadSources(transfer *transferFile, attempts []transferAttemptDetails, size int64, fp *os.File) int {
+	if transfer.job == nil || transfer.job.stripeSources < 2 || fp == nil || transfer.writer != nil ||
+		transfer.xferType != transferTypeDownload || transfer.packOption != "" || transfer.byteRange != nil {
+		return 0
+	}
+	stripeSize := int64(param.Client_StripedDownloadStripeSize.GetInt())
+	if stripeSize <= 0 || size < int64(param.Client_StripedDownloadThreshold.GetInt()) || size <= stripeSize {
+		return 0
+	}
+	if len(attempts) < 2 {
+		return 0
+	}
+	for _, attempt := range attempts {
+		// There's no throughput to gain from a local cache
+		if attempt.Url.Scheme == "unix" {
+			return 0
+		}
+	}
+	return min(transfer.job.stripeSources, len(attempts))
+}
+
+// Download an object of the given size into fp in stripes fetched
+// concurrently from the first `sources` attempts.
+//
+// Each source works through the stripes in turn; a failed stripe is resumed
+// from where it stopped at the next endpoint in the attempt list, and the
+// download fails once a stripe has failed at every endpoint.  All stripes
+// must come from the same version of the object: if the servers report
+// different ETags, even for attempts which failed partway, the download is
+// aborted.
+func downloadStriped(transfer *transferFile, attempts []transferAttemptDetails, sources int, size int64, fp *os.File, dest string) (result stripedDownloadResult) {
+	result.errors = NewTransferErrors()
+	stripes := splitStripes(size, int64(param.Client_StripedDownloadStripeSize.GetInt()))
+	log.Debugf("Downloading %s in %d stripes from %d sources", transfer.remoteURL.String(), len(stripes), sources)
+
+	// Size the file up front so the stripes can be written in any order
+	if err := fp.Truncate(size); err != nil {
+		result.err = errors.Wrap(err, "failed to allocate the destination file for a striped download")
+		return
+	}
+
+	endpoints := make([]transferAttemptDetails, len(attempts))
+	for idx, attempt := range attempts {
+		endpoints[idx] = prepareDownloadAttempt(transfer, attempt)
+	}
+	tokenContents := ""
+	if transfer.token != nil {
+		tokenContents, _ = transfer.token.Get()
+	}
+	fedTokenContents := ""
+	if transfer.fedToken != nil {
+		fedTokenContents, _ = transfer.fedToken.Get()
+	}
+
+	queue := make(chan stripe, len(stripes))
+	for _, s := range stripes {
+		queue <- s
+	}
+	close(queue)
+
+	var written atomic.Int64
+	var mu sync.Mutex // Protects result
+	usedEndpoints := make(map[int]bool)
+
+	// Fetch one stripe, moving on to the next endpoint after each failure
+	fetchStripe := func(ctx context.Context, source int, s stripe) error {
+		sw := newStripeWriter(fp, s, &written)
+		var lastErr error
+		for try := 0; try < len(endpoints); try++ {
+			endpointIdx := (source + try) % len(endpoints)
+			endpoint := endpoints[endpointIdx]
+			offset := sw.next()
+			fields := log.Fields{
+				"url":    endpoint.Url.String(),
+				"job":    transfer.job.ID(),
+				"stripe": fmt.Sprintf("%d-%d", offset, s.end),
+			}
+			attemptCtx := context.WithValue(ctx, logFields("fields"), fields)
+			attemptStart := time.Now()
+			attemptDownloaded, timeToFirstByte, cacheAge, serverVersion, attemptETag, err := downloadHTTP(
+				attemptCtx, transfer.engine, nil, endpoint, "", sw, offset, s.end, size, tokenContents, transfer.project, nil,
+			)
+			endTime := time.Now()
+			if ctx.Err() != nil {
+				// Another stripe failed; don't record the cancellation as an attempt
+				return ctx.Err()
+			}
+
+			attempt := TransferResult{
+				CacheAge:          cacheAge,
+				Endpoint:          endpoint.Url.Host,
+				ServerVersion:     serverVersion,
+				TransferFileBytes: attemptDownloaded,
+				TimeToFirstByte:   timeToFirstByte,
+				TransferEndTime:   endTime,
+				TransferTime:      endTime.Sub(attemptStart),
+			}
+			mu.Lock()
+			usedEndpoints[endpointIdx] = true
+			// An attempt failing partway through a stripe leaves its bytes in
+			// place for the next endpoint to resume from, so its ETag counts too
+			if attemptETag != "" {
+				if result.etag == "" {
+					result.etag = attemptETag
+				} else if result.etag != attemptETag {
+					log.WithFields(fields).Errorf("ETag changed between stripes of the download (was %q, now %q)", result.etag, attemptETag)
+					err = errors.New("object was modified during the striped download (ETag mismatch); cannot safely reassemble it")
+					attempt.Error = newTransferAttemptError(attempt.Endpoint, "", false, false, err)
+					result.errors.AddPastError(attempt.Error, endTime)
+					attempt.Number = len(result.attempts)
+					result.attempts = append(result.attempts, attempt)
+					mu.Unlock()
+					return attempt.Error
+				}
+			}
+			if err != nil {
+				log.WithFields(fields).Debugln("Failed to download stripe from", endpoint.Url.String(), ":", err)
+				proxyStr, _ := os.LookupEnv("http_proxy")
+				if !endpoint.Proxy {
+					proxyStr = ""
+				}
+				wrappedErr, isProxyErr, modifiedProxyStr := wrapDownloadError(err, endpoint.Url.String(), tokenContents, fedTokenContents)
+				if isProxyErr {
+					proxyStr += modifiedProxyStr
+				}
+				attempt.Error = newTransferAttemptError(attempt.Endpoint, proxyStr, isProxyErr, false, wrappedErr)
+				result.errors.AddPastError(attempt.Error, endTime)
+				lastErr = attempt.Error
+			}
+			attempt.Number = len(result.attempts)
+			result.attempts = append(result.attempts, attempt)
+			mu.Unlock()
+
+			if err == nil {
+				return nil
+			}
+		}
+		return lastErr
+	}
+
+	ctx, cancel := context.WithCancel(transfer.ctx)
+	defer cancel()
+	egrp, egrpCtx := errgroup.WithContext(ctx)
+	for source := 0; source < sources; source++ {
+		egrp.Go(func() error {
+			for s := range queue {
+				if err := fetchStripe(egrpCtx, source, s); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+
+	// Report the progress of all the stripes together
+	if transfer.callback != nil {
+		transfer.callback(dest, 0, size, false)
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- egrp.Wait()
+	}()
+	progressTicker := time.NewTicker(100 * time.Millisecond)
+	defer progressTicker.Stop()
+Loop:
+	for {
+		select {
+		case <-progressTicker.C:
+			if transfer.callback != nil {
+				transfer.callback(dest, written.Load(), size, false)
+			}
+		case result.err = <-done:
+			break Loop
+		}
+	}
+	result.downloaded = written.Load()
+	if transfer.callback != nil {
+		transfer.callback(dest, result.downloaded, size, true)
+	}
+	for idx := range endpoints {
+		if usedEndpoints[idx] {
+			result.urls = append(result.urls, endpoints[idx].Url)
+		}
+	}
+	if result.err == nil && result.downloaded != size {
+		result.err = errors.Errorf("striped download completed but received size %db does not match expected size %db", result.downloaded, size)
+	}
+	return
+}
@@ -0,0 +1,193 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pelicanplatform/pelican/param"
+	"github.com/pelicanplatform/pelican/test_utils"
+)
+
+func TestSplitStripes(t *testing.T) {
+	assert.Equal(t, []stripe{{0, 99}, {100, 199}, {200, 249}}, splitStripes(250, 100))
+	assert.Equal(t, []stripe{{0, 99}, {100, 199}}, splitStripes(200, 100))
+	assert.Equal(t, []stripe{{0, 49}}, splitStripes(50, 100))
+}
+
+func TestStripedDownload(t *testing.T) {
+	t.Cleanup(test_utils.SetupTestLogging(t))
+	test_utils.InitClient(t, map[param.Param]any{
+		param.TLSSkipVerify:                    true,
+		param.Client_StripedDownloadThreshold:  1000,
+		param.Client_StripedDownloadStripeSize: 1000,
+		param.Client_StoppedTransferTimeout:    "5s",
+	})
+	data := make([]byte, 4500)
+	for idx := range data {
+		data[idx] = byte(idx % 251)
+	}
+
+	t.Run("sources", func(t *testing.T) {
+		good := newStripeServer(t, data, `"v1"`)
+		transfer, attempts, fp := newStripedTransfer(t, 3, good, good, good)
+		assert.Equal(t, 3, stripedDownloadSources(transfer, attempts, int64(len(data)), fp))
+		assert.Equal(t, 2, stripedDownloadSources(transfer, attempts[:2], int64(len(data)), fp))
+		// Small objects, single sources and byte ranges aren't striped
+		assert.Zero(t, stripedDownloadSources(transfer, attempts, 999, fp))
+		assert.Zero(t, stripedDownloadSources(transfer, attempts[:1], int64(len(data)), fp))
+		transfer.byteRange = &ByteRange{Start: 0, End: 10}
+		assert.Zero(t, stripedDownloadSources(transfer, attempts, int64(len(data)), fp))
+		transfer.byteRange = nil
+		transfer.job.stripeSources = 1
+		assert.Zero(t, stripedDownloadSources(transfer, attempts, int64(len(data)), fp))
+	})
+
+	t.Run("retry-failed-stripes", func(t *testing.T) {
+		var failures atomic.Int32
+		broken := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			failures.Add(1)
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		t.Cleanup(broken.Close)
+		brokenURL, err := url.Parse(broken.URL)
+		require.NoError(t, err)
+
+		transfer, attempts, fp := newStripedTransfer(t, 3,
+			newStripeServer(t, data, `"v1"`), brokenURL, newStripeServer(t, data, `"v1"`))
+		result := downloadStriped(transfer, attempts, 3, int64(len(data)), fp, fp.Name())
+		require.NoError(t, result.err)
+		assert.Equal(t, int64(len(data)), result.downloaded)
+		assert.Equal(t, `"v1"`, result.etag)
+		assert.NotZero(t, failures.Load())
+		contents, err := os.ReadFile(fp.Name())
+		require.NoError(t, err)
+		assert.Equal(t, data, contents)
+	})
+
+	t.Run("ignored-range", func(t *testing.T) {
+		// A server ignoring the Range header sends the whole object for every stripe
+		noRange := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("ETag", `"v1"`)
+			_, _ = w.Write(data)
+		}))
+		t.Cleanup(noRange.Close)
+		noRangeURL, err := url.Parse(noRange.URL)
+		require.NoError(t, err)
+
+		transfer, attempts, fp := newStripedTransfer(t, 2, noRangeURL, newStripeServer(t, data, `"v1"`))
+		result := downloadStriped(transfer, attempts, 2, int64(len(data)), fp, fp.Name())
+		require.NoError(t, result.err)
+		assert.Equal(t, int64(len(data)), result.downloaded)
+		contents, err := os.ReadFile(fp.Name())
+		require.NoError(t, err)
+		assert.Equal(t, data, contents)
+	})
+
+	t.Run("etag-mismatch", func(t *testing.T) {
+		changed := bytes.Clone(data)
+		changed[0]++
+		transfer, attempts, fp := newStripedTransfer(t, 2,
+			newStripeServer(t, data, `"v1"`), newStripeServer(t, changed, `"v2"`))
+		result := downloadStriped(transfer, attempts, 2, int64(len(data)), fp, fp.Name())
+		require.Error(t, result.err)
+		assert.Contains(t, result.err.Error(), "ETag mismatch")
+	})
+
+	t.Run("etag-mismatch-on-resume", func(t *testing.T) {
+		// The first endpoint fails partway through the stripe; the bytes it sent
+		// are from a different version than the endpoint resuming the stripe
+		changed := bytes.Clone(data)
+		changed[0]++
+		truncated := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("ETag", `"v2"`)
+			http.ServeContent(&truncatedWriter{ResponseWriter: w, remaining: 10}, r, "object", time.Time{}, bytes.NewReader(changed))
+		}))
+		t.Cleanup(truncated.Close)
+		truncatedURL, err := url.Parse(truncated.URL)
+		require.NoError(t, err)
+
+		transfer, attempts, fp := newStripedTransfer(t, 1, truncatedURL, newStripeServer(t, data, `"v1"`))
+		result := downloadStriped(transfer, attempts, 1, int64(len(data)), fp, fp.Name())
+		require.Error(t, result.err)
+		assert.Contains(t, result.err.Error(), "ETag mismatch")
+	})
+}
+
+// truncatedWriter drops the connection after sending the first bytes of the body
+type truncatedWriter struct {
+	http.ResponseWriter
+	remaining int
+}
+
+func (tw *truncatedWriter) Write(p []byte) (int, error) {
+	if len(p) > tw.remaining {
+		p = p[:tw.remaining]
+	}
+	n, err := tw.ResponseWriter.Write(p)
+	tw.remaining -= n
+	if err == nil && tw.remaining == 0 {
+		err = errors.New("connection dropped")
+	}
+	return n, err
+}
+
+// A cache serving a single object; ServeContent takes care of the Range header
+func newStripeServer(t *testing.T, data []byte, etag string) *url.URL {
+	svr := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", etag)
+		http.ServeContent(w, r, "object", time.Time{}, bytes.NewReader(data))
+	}))
+	t.Cleanup(svr.Close)
+	svrURL, err := url.Parse(svr.URL)
+	require.NoError(t, err)
+	svrURL.Path = "/test/object"
+	return svrURL
+}
+
+func newStripedTransfer(t *testing.T, sources int, urls ...*url.URL) (*transferFile, []transferAttemptDetails, *os.File) {
+	fp, err := os.Create(filepath.Join(t.TempDir(), "object"))
+	require.NoError(t, err)
+	t.Cleanup(func() { fp.Close() })
+	attempts := make([]transferAttemptDetails, len(urls))
+	for idx, u := range urls {
+		attempts[idx] = transferAttemptDetails{Url: u}
+	}
+	transfer := &transferFile{
+		ctx:       context.Background(),
+		job:       &TransferJob{stripeSources: sources},
+		xferType:  transferTypeDownload,
+		remoteURL: &url.URL{Scheme: "pelican", Host: "federation.example.org", Path: "/test/object"},
+		attempts:  attempts,
+	}
+	return transfer, attempts, fp
+}
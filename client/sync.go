@@ -0,0 +1,286 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/lestrrat-go/option"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/pelicanplatform/pelican/error_codes"
+	"github.com/pelicanplatform/pelican/pelican_url"
+)
+
+type (
+	// The action a synchronization takes for a single object
+	SyncAction int
+
+	// One entry of the itemized change report for a synchronization; see
+	// WithSyncReport.
+	SyncChange struct {
+		Action SyncAction
+		Path   string // Destination path of the object: a local path for downloads, an object path for uploads
+		Reason string // Why the object is transferred, e.g. "new" or "checksum"; empty for other actions
+	}
+
+	// Receives the itemized changes of a synchronization.  May be called
+	// concurrently from multiple transfer jobs.
+	SyncReportFunc = func(change SyncChange)
+)
+
+const (
+	SyncActionTransfer SyncAction = iota // The object is missing or differs at the destination and is transferred
+	SyncActionSkip                       // The object is unchanged at the destination
+	SyncActionDelete                     // The destination object is not present at the source and is deleted
+)
+
+// Reasons an object is (re-)transferred during a synchronization
+const (
+	syncReasonNew      = "new"      // The destination does not exist
+	syncReasonAlways   = "always"   // Synchronization checks are disabled (SyncNone)
+	syncReasonSize     = "size"     // The destination size differs
+	syncReasonMtime    = "mtime"    // The destination is older than the source
+	syncReasonChecksum = "checksum" // The destination checksum differs or could not be compared
+)
+
+func (level SyncLevel) String() string {
+	switch level {
+	case SyncNone:
+		return "none"
+	case SyncExist:
+		return "exist"
+	case SyncSize:
+		return "size"
+	case SyncMtime:
+		return "mtime"
+	case SyncChecksum:
+		return "checksum"
+	}
+	return fmt.Sprintf("SyncLevel(%d)", int(level))
+}
+
+// Parse a synchronization level from its name, as returned by
+// SyncLevel.String.
+func ParseSyncLevel(name string) (SyncLevel, error) {
+	for _, level := range []SyncLevel{SyncNone, SyncExist, SyncSize, SyncMtime, SyncChecksum} {
+		if strings.EqualFold(name, level.String()) {
+			return level, nil
+		}
+	}
+	return SyncNone, errors.Errorf("unknown synchronization level %q; must be one of none, exist, size, mtime, or checksum", name)
+}
+
+func (action SyncAction) String() string {
+	switch action {
+	case SyncActionTransfer:
+		return "transfer"
+	case SyncActionSkip:
+		return "skip"
+	case SyncActionDelete:
+		return "delete"
+	}
+	return fmt.Sprintf("SyncAction(%d)", int(action))
+}
+
+// Create an option to delete destination objects that are not present at the
+// source of a recursive synchronization.
+//
+// For downloads, local files and directories are removed; for uploads, remote
+// objects and collections are deleted, which requires the origin to support
+// listings.  In dry-run mode the deletions are only reported.
+func WithSyncDelete(enable bool) TransferOption {
+	return option.New(identTransferOptionSyncDelete{}, enable)
+}
+
+// Create an option to receive an itemized report of the changes made (or, in
+// dry-run mode, that would be made) by a recursive synchronization.
+func WithSyncReport(report SyncReportFunc) TransferOption {
+	return option.New(identTransferOptionSyncReport{}, report)
+}
+
+func (tj *TransferJob) reportSync(change SyncChange) {
+	if tj.syncReport != nil {
+		tj.syncReport(change)
+	}
+}
+
+// Compute the checksums of a local file for each algorithm listed in `remote`
+// that the client supports.
+func computeLocalChecksums(localPath string, remote []ChecksumInfo) (map[ChecksumType][]byte, error) {
+	hashes := make(map[ChecksumType]hash.Hash, len(remote))
+	writers := make([]io.Writer, 0, len(remote))
+	for _, info := range remote {
+		if _, ok := hashes[info.Algorithm]; ok {
+			continue
+		}
+		h := newChecksumHash(info.Algorithm)
+		if h == nil {
+			continue
+		}
+		hashes[info.Algorithm] = h
+		writers = append(writers, h)
+	}
+	if len(writers) == 0 {
+		return nil, nil
+	}
+
+	fp, err := os.Open(localPath)
+	if err != nil {
+		return nil, err
+	}
+	defer fp.Close()
+	if _, err = io.Copy(io.MultiWriter(writers...), fp); err != nil {
+		return nil, err
+	}
+	result := make(map[ChecksumType][]byte, len(hashes))
+	for alg, h := range hashes {
+		result[alg] = h.Sum(nil)
+	}
+	return result, nil
+}
+
+// Determine whether a local file matches the checksum the server reports for
+// the remote object, trying each of the given object URLs in turn.  Returns
+// false if no server provides a checksum the client can compute.
+func localChecksumMatches(ctx context.Context, job *TransferJob, localPath string, objectUrls []*url.URL) bool {
+	token := ""
+	if job.token != nil && (job.dirResp.XPelNsHdr.RequireToken || job.xferType == transferTypeUpload) {
+		if contents, err := job.token.Get(); err == nil {
+			token = contents
+		}
+	}
+	for _, objectUrl := range objectUrls {
+		remote, err := fetchChecksum(ctx, job.requestedChecksums, objectUrl, token, job.project)
+		if err != nil || len(remote) == 0 {
+			log.Debugf("Unable to fetch the checksum of %s for synchronization: %v", objectUrl.String(), err)
+			continue
+		}
+		local, err := computeLocalChecksums(localPath, remote)
+		if err != nil {
+			log.Warningf("Failed to compute the checksum of %s for synchronization: %v", localPath, err)
+			return false
+		}
+		for _, info := range remote {
+			if value, ok := local[info.Algorithm]; ok {
+				return bytes.Equal(value, info.Value)
+			}
+		}
+	}
+	return false
+}
+
+func attemptUrls(attempts []transferAttemptDetails) []*url.URL {
+	urls := make([]*url.URL, 0, len(attempts))
+	for _, attempt := range attempts {
+		if attempt.Url != nil && attempt.Url.Scheme != "unix" {
+			urls = append(urls, attempt.Url)
+		}
+	}
+	return urls
+}
+
+// Returns true if the destination entry should be deleted: it is not present
+// at the source, or it is a file where the source has a collection (or vice
+// versa) and would block the transfer.
+func syncExtraneous(sourceIsDir map[string]bool, name string, isDir bool) bool {
+	srcIsDir, found := sourceIsDir[name]
+	return !found || srcIsDir != isDir
+}
+
+// Remove the entries of a local directory that are not present in the
+// corresponding remote collection.  Used by recursive downloads with
+// WithSyncDelete enabled.
+func deleteExtraneousLocal(job *TransferJob, localDir string, remoteInfos []fs.FileInfo) error {
+	entries, err := os.ReadDir(localDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to read local directory %q for synchronization", localDir)
+	}
+	sourceIsDir := make(map[string]bool, len(remoteInfos))
+	for _, info := range remoteInfos {
+		sourceIsDir[info.Name()] = info.IsDir()
+	}
+	for _, entry := range entries {
+		if !syncExtraneous(sourceIsDir, entry.Name(), entry.IsDir()) {
+			continue
+		}
+		localPath := path.Join(localDir, entry.Name())
+		job.reportSync(SyncChange{Action: SyncActionDelete, Path: localPath})
+		if job.dryRun {
+			fmt.Printf("DELETE: %s\n", localPath)
+			continue
+		}
+		log.Infoln("Deleting", localPath, "as it is not present at the source")
+		if err := os.RemoveAll(localPath); err != nil {
+			return error_codes.NewParameterError(errors.Wrapf(err, "failed to delete %q", localPath))
+		}
+	}
+	return nil
+}
+
+// Delete the objects in a remote collection that are not present in the
+// corresponding local directory.  Used by recursive uploads with
+// WithSyncDelete enabled.
+func deleteExtraneousRemote(job *TransferJob, remoteDir string, localEntries []os.DirEntry) error {
+	remoteInfos, err := listHttp(&pelican_url.PelicanURL{Path: remoteDir}, job.dirResp, job.token, false, 0)
+	if err != nil {
+		// Nothing to delete if the collection hasn't been created yet
+		var pe *error_codes.PelicanError
+		if errors.As(err, &pe) && pe.ErrorType() == "Specification.FileNotFound" {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to list remote collection %s for synchronization", remoteDir)
+	}
+	sourceIsDir := make(map[string]bool, len(localEntries))
+	for _, entry := range localEntries {
+		sourceIsDir[entry.Name()] = entry.IsDir()
+	}
+	for _, info := range remoteInfos {
+		remotePath := path.Clean(info.Name)
+		if remotePath == path.Clean(remoteDir) {
+			continue
+		}
+		if !syncExtraneous(sourceIsDir, path.Base(remotePath), info.IsCollection) {
+			continue
+		}
+		job.reportSync(SyncChange{Action: SyncActionDelete, Path: remotePath})
+		if job.dryRun {
+			fmt.Printf("DELETE: %s\n", remotePath)
+			continue
+		}
+		log.Infoln("Deleting remote object", remotePath, "as it is not present at the source")
+		if err := deleteHttp(job.ctx, &pelican_url.PelicanURL{Path: remotePath}, true, job.dirResp, job.token); err != nil {
+			return errors.Wrapf(err, "failed to delete remote object %s", remotePath)
+		}
+	}
+	return nil
+}
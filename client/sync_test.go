@@ -0,0 +1,176 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package client
+
+import (
+	"context"
+	"encoding/hex"
+	"hash/crc32"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pelicanplatform/pelican/param"
+	"github.com/pelicanplatform/pelican/test_utils"
+)
+
+func TestParseSyncLevel(t *testing.T) {
+	for _, level := range []SyncLevel{SyncNone, SyncExist, SyncSize, SyncMtime, SyncChecksum} {
+		parsed, err := ParseSyncLevel(level.String())
+		require.NoError(t, err)
+		assert.Equal(t, level, parsed)
+	}
+	parsed, err := ParseSyncLevel("Checksum")
+	require.NoError(t, err)
+	assert.Equal(t, SyncChecksum, parsed)
+	_, err = ParseSyncLevel("contents")
+	assert.Error(t, err)
+}
+
+func TestSkipDownload(t *testing.T) {
+	t.Cleanup(test_utils.SetupTestLogging(t))
+	test_utils.InitClient(t, map[param.Param]any{})
+
+	contents := []byte("synchronized data")
+	localPath := filepath.Join(t.TempDir(), "object")
+	require.NoError(t, os.WriteFile(localPath, contents, 0644))
+	localInfo, err := os.Stat(localPath)
+	require.NoError(t, err)
+
+	remoteChecksum := hex.EncodeToString(crc32.New(crc32cTable).Sum(nil))
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodHead, r.Method)
+		w.Header().Set("Digest", "crc32c="+remoteChecksum)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer svr.Close()
+	svrUrl, err := url.Parse(svr.URL + "/foo/object")
+	require.NoError(t, err)
+	attempts := []transferAttemptDetails{{Url: svrUrl}}
+
+	newJob := func(level SyncLevel) *TransferJob {
+		return &TransferJob{ctx: context.Background(), syncLevel: level, xferType: transferTypeDownload}
+	}
+	remote := func(size int64, modTime time.Time) fs.FileInfo {
+		return &pelicanFileInfo{name: "object", size: size, modTime: modTime}
+	}
+	size := int64(len(contents))
+	older := localInfo.ModTime().Add(-time.Hour)
+	newer := localInfo.ModTime().Add(time.Hour)
+
+	tests := []struct {
+		name      string
+		level     SyncLevel
+		remote    fs.FileInfo
+		localPath string
+		skip      bool
+		reason    string
+	}{
+		{"none-always-transfers", SyncNone, remote(size, older), localPath, false, syncReasonAlways},
+		{"missing-destination", SyncSize, remote(size, older), localPath + ".missing", false, syncReasonNew},
+		{"exist", SyncExist, remote(size+1, newer), localPath, true, ""},
+		{"size-differs", SyncSize, remote(size+1, older), localPath, false, syncReasonSize},
+		{"size-matches", SyncSize, remote(size, newer), localPath, true, ""},
+		{"mtime-size-differs", SyncMtime, remote(size+1, older), localPath, false, syncReasonSize},
+		{"mtime-local-older", SyncMtime, remote(size, newer), localPath, false, syncReasonMtime},
+		{"mtime-local-newer", SyncMtime, remote(size, older), localPath, true, ""},
+		{"mtime-unknown", SyncMtime, remote(size, time.Time{}), localPath, false, syncReasonMtime},
+		{"checksum-differs", SyncChecksum, remote(size, older), localPath, false, syncReasonChecksum},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			skip, reason := skipDownload(newJob(tc.level), tc.remote, tc.localPath, attempts)
+			assert.Equal(t, tc.skip, skip)
+			assert.Equal(t, tc.reason, reason)
+		})
+	}
+
+	t.Run("checksum-matches", func(t *testing.T) {
+		hash := crc32.New(crc32cTable)
+		_, err := hash.Write(contents)
+		require.NoError(t, err)
+		remoteChecksum = hex.EncodeToString(hash.Sum(nil))
+		skip, reason := skipDownload(newJob(SyncChecksum), remote(size, newer), localPath, attempts)
+		assert.True(t, skip)
+		assert.Empty(t, reason)
+	})
+}
+
+func TestDeleteExtraneousLocal(t *testing.T) {
+	t.Cleanup(test_utils.SetupTestLogging(t))
+
+	setup := func(t *testing.T) string {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "keep"), []byte("a"), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "stale"), []byte("b"), 0644))
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "stale-dir", "nested"), 0755))
+		// A file where the source now has a collection
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "now-a-dir"), []byte("c"), 0644))
+		return dir
+	}
+	remoteInfos := []fs.FileInfo{
+		&pelicanFileInfo{name: "keep"},
+		&pelicanFileInfo{name: "now-a-dir", isDir: true},
+		&pelicanFileInfo{name: "not-yet-downloaded"},
+	}
+
+	var changes []SyncChange
+	job := &TransferJob{syncReport: func(change SyncChange) { changes = append(changes, change) }}
+
+	t.Run("dry-run", func(t *testing.T) {
+		changes = nil
+		job.dryRun = true
+		dir := setup(t)
+		require.NoError(t, deleteExtraneousLocal(job, dir, remoteInfos))
+		assert.Len(t, changes, 3)
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Len(t, entries, 4, "a dry run must not delete anything")
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		changes = nil
+		job.dryRun = false
+		dir := setup(t)
+		require.NoError(t, deleteExtraneousLocal(job, dir, remoteInfos))
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "keep", entries[0].Name())
+		require.Len(t, changes, 3)
+		for _, change := range changes {
+			assert.Equal(t, SyncActionDelete, change.Action)
+		}
+		assert.ElementsMatch(t,
+			[]string{filepath.Join(dir, "now-a-dir"), filepath.Join(dir, "stale"), filepath.Join(dir, "stale-dir")},
+			[]string{changes[0].Path, changes[1].Path, changes[2].Path})
+	})
+
+	t.Run("missing-directory", func(t *testing.T) {
+		assert.NoError(t, deleteExtraneousLocal(job, filepath.Join(t.TempDir(), "missing"), remoteInfos))
+	})
+}
@@ -0,0 +1,308 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package client
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/pelicanplatform/pelican/config"
+	"github.com/pelicanplatform/pelican/error_codes"
+	"github.com/pelicanplatform/pelican/pelican_url"
+	"github.com/pelicanplatform/pelican/server_structs"
+)
+
+// Copies between two remote objects never write the object to local disk.
+// When the origins holding the source and the destination both advertise the
+// ThirdPartyCopy feature, the client sends the destination an HTTP-TPC COPY
+// request asking it to pull the object from the source, then follows the
+// performance markers it streams back.  Otherwise, the object is streamed
+// through the client, uploading to the destination while downloading from the
+// source.
+
+// Whether both ends of a copy are remote objects
+func isRemoteCopy(source string, destination string) bool {
+	isRemote := func(location string) bool {
+		parsed, err := url.Parse(location)
+		return err == nil && parsed.Scheme != "" && parsed.Scheme != "file"
+	}
+	return isRemote(source) && isRemote(destination)
+}
+
+// Parse a remote object of a copy, resolving its federation metadata
+func parseRemoteCopyUrl(ctx context.Context, remote string) (*pelican_url.PelicanURL, error) {
+	dOpts := []pelican_url.DiscoveryOption{pelican_url.WithContext(ctx)}
+	pUrl, err := pelican_url.Parse(remote, []pelican_url.ParseOption{pelican_url.ValidateQueryParams(true), pelican_url.AllowUnknownQueryParams(true)}, dOpts)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse remote object: %s", remote)
+	}
+	pelicanURL, err := ParseRemoteAsPUrl(ctx, pUrl.GetRawUrl().String())
+	if err != nil {
+		return nil, errors.Wrapf(err, "error generating metadata for %s", remote)
+	}
+	return pelicanURL, nil
+}
+
+// Copy a remote object to another remote location, by third-party copy if
+// the origins of both support it and through the client otherwise
+func doRemoteCopy(ctx context.Context, source string, destination string, recursive bool, options ...TransferOption) (transferResults []TransferResults, err error) {
+	if recursive {
+		return nil, error_codes.NewParameterError(errors.New("recursive copies between two remote locations are not supported"))
+	}
+	// As with uploads, a destination ending in "/" is a collection to copy into
+	if destUrl, err := url.Parse(destination); err == nil && strings.HasSuffix(destUrl.Path, "/") {
+		if srcUrl, err := url.Parse(source); err == nil {
+			destUrl.Path += path.Base(srcUrl.Path)
+			destination = destUrl.String()
+		}
+	}
+	srcPUrl, err := parseRemoteCopyUrl(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+	dstPUrl, err := parseRemoteCopyUrl(ctx, destination)
+	if err != nil {
+		return nil, err
+	}
+
+	te, err := NewTransferEngine(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := te.Shutdown(); err != nil {
+			log.Errorln("Failure when shutting down transfer engine:", err)
+		}
+	}()
+	tc, err := te.NewClient(options...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Third-party copies go straight between the origins, bypassing caches
+	srcInfo, srcErr := getDirectorInfoForPath(ctx, srcPUrl, http.MethodGet, "", true)
+	dstInfo, dstErr := getDirectorInfoForPath(ctx, dstPUrl, http.MethodPut, "", false)
+	if srcErr == nil && dstErr == nil && len(srcInfo.ObjectServers) > 0 && len(dstInfo.ObjectServers) > 0 &&
+		srcInfo.XPelFeatHdr.Has(server_structs.FeatureThirdPartyCopy) && dstInfo.XPelFeatHdr.Has(server_structs.FeatureThirdPartyCopy) {
+		log.Debugf("Copying %s to %s by third-party copy", source, destination)
+		transferResults, err = tc.thirdPartyCopy(ctx, srcPUrl, &srcInfo, dstPUrl, &dstInfo, destination)
+		tc.Close()
+		return
+	}
+	if srcErr != nil || dstErr != nil {
+		log.Debugf("Failed to look up the origins of the copy (source: %v; destination: %v); streaming it through the client", srcErr, dstErr)
+	} else {
+		log.Debugf("The origins of %s and %s don't both support third-party copies; streaming the copy through the client", source, destination)
+	}
+	return streamRemoteCopy(ctx, te, tc, srcPUrl, dstPUrl, options...)
+}
+
+// Acquire the token for one end of a third-party copy
+func (tc *TransferClient) copyToken(pUrl *pelican_url.PelicanURL, dirResp *server_structs.DirectorResponse, operation config.TokenOperation) (string, error) {
+	tokenGen := NewTokenGenerator(pUrl, dirResp, operation, !tc.skipAcquire)
+	if tc.token != "" {
+		tokenGen.SetToken(tc.token)
+	}
+	if tc.tokenLocation != "" {
+		tokenGen.SetTokenLocation(tc.tokenLocation)
+	}
+	return tokenGen.Get()
+}
+
+// The URL of an object at the server the director redirected to
+func copyObjectUrl(server *url.URL, objectPath string) *url.URL {
+	objectUrl := *server
+	if objectUrl.Path == "" || objectUrl.Path == "/" {
+		objectUrl.Path = objectPath
+	}
+	return &objectUrl
+}
+
+// Ask the destination origin to pull the object from the source origin,
+// following the transfer through the performance markers it reports
+func (tc *TransferClient) thirdPartyCopy(ctx context.Context, srcPUrl *pelican_url.PelicanURL, srcInfo *server_structs.DirectorResponse, dstPUrl *pelican_url.PelicanURL, dstInfo *server_structs.DirectorResponse, destination string) ([]TransferResults, error) {
+	var srcToken string
+	if srcInfo.XPelNsHdr.RequireToken {
+		var err error
+		if srcToken, err = tc.copyToken(srcPUrl, srcInfo, config.TokenRead); err != nil {
+			return nil, errors.Wrap(err, "failed to get a token to read the source")
+		}
+	}
+	dstToken, err := tc.copyToken(dstPUrl, dstInfo, config.TokenWrite)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get a token to write the destination")
+	}
+
+	srcUrl := copyObjectUrl(srcInfo.ObjectServers[0], srcPUrl.Path)
+	dstUrl := copyObjectUrl(dstInfo.ObjectServers[0], dstPUrl.Path)
+	req, err := http.NewRequestWithContext(ctx, "COPY", dstUrl.String(), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create the third-party copy request")
+	}
+	req.Header.Set("Source", srcUrl.String())
+	req.Header.Set("Authorization", "Bearer "+dstToken)
+	if srcToken != "" {
+		req.Header.Set("TransferHeaderAuthorization", "Bearer "+srcToken)
+	}
+	req.Header.Set("User-Agent", getUserAgent(""))
+	if jobId, found := getJobId(ctx); found {
+		req.Header.Set("X-Pelican-JobId", jobId)
+		req.Header.Set("TransferHeaderX-Pelican-JobId", jobId)
+	}
+
+	result := TransferResults{
+		JobId:             uuid.New(),
+		Scheme:            dstPUrl.GetRawUrl().Scheme,
+		Source:            srcPUrl.GetRawUrl().String(),
+		TransferStartTime: time.Now(),
+	}
+	httpClient := &http.Client{Transport: config.GetTransport().Clone()}
+	resp, err := httpClient.Do(req)
+	if err == nil {
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusCreated {
+			body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+			err = errors.Errorf("%s refused the third-party copy (%s): %s", dstUrl.Host, resp.Status, strings.TrimSpace(string(body)))
+		} else {
+			result.TransferredBytes, err = followPerfMarkers(resp.Body, func(transferred int64) {
+				if tc.callback != nil {
+					tc.callback(destination, transferred, 0, false)
+				}
+			})
+		}
+	}
+	result.Error = err
+	result.Attempts = []TransferResult{{
+		Number:            0,
+		TransferFileBytes: result.TransferredBytes,
+		TransferEndTime:   time.Now(),
+		TransferTime:      time.Since(result.TransferStartTime),
+		Endpoint:          dstUrl.Host,
+		Error:             err,
+	}}
+	if err != nil {
+		return []TransferResults{result}, errors.Wrapf(err, "third-party copy to %s failed", dstUrl.Host)
+	}
+	if tc.callback != nil {
+		tc.callback(destination, result.TransferredBytes, result.TransferredBytes, true)
+	}
+	return []TransferResults{result}, nil
+}
+
+// Read the body of a third-party copy response, reporting the bytes moved
+// by each performance marker until the final success or failure line
+func followPerfMarkers(body io.Reader, progress func(transferred int64)) (transferred int64, err error) {
+	const bytesField = "Stripe Bytes Transferred:"
+	scanner := bufio.NewScanner(body)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, bytesField):
+			if value, parseErr := strconv.ParseInt(strings.TrimSpace(strings.TrimPrefix(line, bytesField)), 10, 64); parseErr == nil {
+				transferred = value
+				progress(transferred)
+			}
+		case strings.HasPrefix(line, "success:"):
+			return transferred, nil
+		case strings.HasPrefix(line, "failure:"):
+			return transferred, errors.New(strings.TrimSpace(strings.TrimPrefix(line, "failure:")))
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return transferred, errors.Wrap(err, "lost the connection while following the third-party copy")
+	}
+	return transferred, errors.New("the third-party copy ended without reporting its outcome")
+}
+
+// The downloading end of a streamed copy; the pipe is closed once the
+// download's outcome is known, so a failed download never completes the upload
+type copyPipeWriter struct {
+	*io.PipeWriter
+}
+
+func (w copyPipeWriter) Close() error {
+	return nil
+}
+
+// Stream the source object through the client to the destination
+func streamRemoteCopy(ctx context.Context, te *TransferEngine, putClient *TransferClient, srcPUrl *pelican_url.PelicanURL, dstPUrl *pelican_url.PelicanURL, options ...TransferOption) ([]TransferResults, error) {
+	getClient, err := te.NewClient(options...)
+	if err != nil {
+		return nil, err
+	}
+	pr, pw := io.Pipe()
+	getJob, err := getClient.NewTransferJob(ctx, srcPUrl.GetRawUrl(), "", false, false, append(options, WithWriter(copyPipeWriter{pw}))...)
+	if err != nil {
+		return nil, err
+	}
+	putJob, err := putClient.NewTransferJob(ctx, dstPUrl.GetRawUrl(), "", true, false, append(options, WithReader(pr))...)
+	if err != nil {
+		return nil, err
+	}
+	if err = getClient.Submit(getJob); err != nil {
+		return nil, err
+	}
+	if err = putClient.Submit(putJob); err != nil {
+		getJob.Cancel()
+		pw.Close()
+		return nil, err
+	}
+
+	// Each end closes the pipe when it finishes, so the other can't block on it
+	getDone := make(chan error, 1)
+	go func() {
+		_, getErr := waitForCopyJob(getClient, getJob)
+		if getErr != nil {
+			getErr = errors.Wrap(getErr, "failed to download the source")
+		}
+		pw.CloseWithError(getErr)
+		getDone <- getErr
+	}()
+	results, putErr := waitForCopyJob(putClient, putJob)
+	pr.CloseWithError(errors.New("the upload of the copy stopped"))
+	if getErr := <-getDone; getErr != nil {
+		return results, getErr
+	}
+	return results, putErr
+}
+
+// Wait for the only job of a client to finish, returning its results
+func waitForCopyJob(tc *TransferClient, tj *TransferJob) (results []TransferResults, err error) {
+	results, err = tc.Shutdown()
+	if err == nil {
+		err = tj.lookupErr
+	}
+	for _, result := range results {
+		if err == nil && result.Error != nil {
+			err = result.Error
+		}
+	}
+	return
+}
@@ -0,0 +1,118 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pelicanplatform/pelican/pelican_url"
+	"github.com/pelicanplatform/pelican/server_structs"
+)
+
+const testPerfMarker = "Perf Marker\n\tTimestamp: 1700000000\n\tStripe Index: 0\n\tStripe Bytes Transferred: %d\n\tTotal Stripe Count: 1\nEnd\n"
+
+func TestIsRemoteCopy(t *testing.T) {
+	assert.True(t, isRemoteCopy("pelican://fed.example.com/a/obj", "osdf:///b/obj"))
+	assert.False(t, isRemoteCopy("pelican://fed.example.com/a/obj", "/tmp/obj"))
+	assert.False(t, isRemoteCopy("file:///tmp/obj", "pelican://fed.example.com/a/obj"))
+	assert.False(t, isRemoteCopy("/tmp/a", "/tmp/b"))
+}
+
+func TestFollowPerfMarkers(t *testing.T) {
+	var progress []int64
+	record := func(transferred int64) { progress = append(progress, transferred) }
+
+	transferred, err := followPerfMarkers(strings.NewReader(fmt.Sprintf(testPerfMarker, 100)+fmt.Sprintf(testPerfMarker, 250)+"success: Created\n"), record)
+	require.NoError(t, err)
+	assert.Equal(t, int64(250), transferred)
+	assert.Equal(t, []int64{100, 250}, progress)
+
+	transferred, err = followPerfMarkers(strings.NewReader(fmt.Sprintf(testPerfMarker, 100)+"failure: the source responded with 403 Forbidden\n"), record)
+	require.Error(t, err)
+	assert.Equal(t, "the source responded with 403 Forbidden", err.Error())
+	assert.Equal(t, int64(100), transferred)
+
+	// A body cut off before the outcome is a failure
+	_, err = followPerfMarkers(strings.NewReader(fmt.Sprintf(testPerfMarker, 100)), record)
+	assert.Error(t, err)
+}
+
+func TestThirdPartyCopy(t *testing.T) {
+	var copyReq *http.Request
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		copyReq = r
+		if r.URL.Path == "/dst/refused" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+		fmt.Fprintf(w, testPerfMarker, 1024)
+		fmt.Fprintln(w, "success: Created")
+	}))
+	t.Cleanup(origin.Close)
+	originUrl, err := url.Parse(origin.URL)
+	require.NoError(t, err)
+
+	var callbacks []bool
+	tc := &TransferClient{
+		token:       "test-token",
+		skipAcquire: true,
+		callback: func(path string, transferred int64, size int64, completed bool) {
+			assert.Equal(t, "pelican://fed.example.com/dst/obj", path)
+			callbacks = append(callbacks, completed)
+		},
+	}
+	srcPUrl := &pelican_url.PelicanURL{Scheme: "pelican", Host: "fed.example.com", Path: "/src/obj"}
+	srcInfo := &server_structs.DirectorResponse{
+		ObjectServers: []*url.URL{{Scheme: "https", Host: "src-origin.example.com", Path: "/src/obj"}},
+		XPelNsHdr:     server_structs.XPelNs{RequireToken: true},
+	}
+	dstPUrl := &pelican_url.PelicanURL{Scheme: "pelican", Host: "fed.example.com", Path: "/dst/obj"}
+	dstInfo := &server_structs.DirectorResponse{ObjectServers: []*url.URL{originUrl}}
+
+	results, err := tc.thirdPartyCopy(t.Context(), srcPUrl, srcInfo, dstPUrl, dstInfo, "pelican://fed.example.com/dst/obj")
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, int64(1024), results[0].TransferredBytes)
+	assert.Equal(t, "pelican://fed.example.com/src/obj", results[0].Source)
+	assert.Equal(t, []bool{false, true}, callbacks)
+
+	require.NotNil(t, copyReq)
+	assert.Equal(t, "COPY", copyReq.Method)
+	assert.Equal(t, "/dst/obj", copyReq.URL.Path)
+	assert.Equal(t, "https://src-origin.example.com/src/obj", copyReq.Header.Get("Source"))
+	assert.Equal(t, "Bearer test-token", copyReq.Header.Get("Authorization"))
+	assert.Equal(t, "Bearer test-token", copyReq.Header.Get("TransferHeaderAuthorization"))
+
+	// Refusals of the destination are reported with its response
+	dstPUrl.Path = "/dst/refused"
+	results, err = tc.thirdPartyCopy(t.Context(), srcPUrl, srcInfo, dstPUrl, dstInfo, "pelican://fed.example.com/dst/obj")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "403")
+	require.Len(t, results, 1)
+	assert.Error(t, results[0].Error)
+}
@@ -0,0 +1,191 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/pelicanplatform/pelican/config"
+	"github.com/pelicanplatform/pelican/param"
+	"github.com/pelicanplatform/pelican/server_structs"
+)
+
+const (
+	// How often queued transfer reports are sent to the director
+	transferReportInterval = 30 * time.Second
+	// How long to wait for the director when sending transfer reports
+	transferReportTimeout = 10 * time.Second
+)
+
+// Batches the throughput measurements of successful downloads and sends them
+// to the director that redirected the client, which uses them for its
+// "throughput" cache sort method.  Reports are best-effort: failures to send
+// them are logged and the reports dropped.
+type transferReporter struct {
+	mu      sync.Mutex
+	pending map[string][]server_structs.TransferReport // Keyed by director endpoint
+	flush   chan struct{}
+}
+
+func newTransferReporter() *transferReporter {
+	return &transferReporter{
+		pending: make(map[string][]server_structs.TransferReport),
+		flush:   make(chan struct{}, 1),
+	}
+}
+
+// Queue a report for the given director, triggering an early send once a
+// full batch is waiting.
+func (tr *transferReporter) add(directorUrl string, report server_structs.TransferReport) {
+	tr.mu.Lock()
+	tr.pending[directorUrl] = append(tr.pending[directorUrl], report)
+	full := len(tr.pending[directorUrl]) >= server_structs.MaxTransferReportsPerRequest
+	tr.mu.Unlock()
+	if full {
+		select {
+		case tr.flush <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Periodically send queued reports until the context is cancelled, then
+// send whatever remains.
+func (tr *transferReporter) run(ctx context.Context) error {
+	ticker := time.NewTicker(transferReportInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			// The engine context is already cancelled; give the final send its own deadline.
+			sendCtx, cancel := context.WithTimeout(context.Background(), transferReportTimeout)
+			tr.send(sendCtx)
+			cancel()
+			return nil
+		case <-ticker.C:
+			tr.send(ctx)
+		case <-tr.flush:
+			tr.send(ctx)
+		}
+	}
+}
+
+// Send all queued reports to their directors.
+func (tr *transferReporter) send(ctx context.Context) {
+	tr.mu.Lock()
+	pending := tr.pending
+	tr.pending = make(map[string][]server_structs.TransferReport)
+	tr.mu.Unlock()
+
+	for directorUrl, reports := range pending {
+		for len(reports) > 0 {
+			batch := reports[:min(len(reports), server_structs.MaxTransferReportsPerRequest)]
+			reports = reports[len(batch):]
+			if err := postTransferReports(ctx, directorUrl, batch); err != nil {
+				log.Debugf("Failed to send %d transfer reports to director %s: %v", len(batch), directorUrl, err)
+			}
+		}
+	}
+}
+
+func postTransferReports(ctx context.Context, directorUrl string, reports []server_structs.TransferReport) error {
+	reportUrl, err := url.Parse(directorUrl)
+	if err != nil {
+		return errors.Wrap(err, "invalid director URL")
+	}
+	reportUrl.Path = "/api/v1.0/director/transferReports"
+
+	body, err := json.Marshal(server_structs.TransferReportRequest{Reports: reports})
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, transferReportTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, reportUrl.String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", getUserAgent(""))
+
+	resp, err := config.GetClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("director responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Build the throughput report for a successful download, returning false if
+// the transfer should not be reported: it was not redirected by a director,
+// was made on behalf of a cache, was too small to measure, or reports are
+// disabled by Client.DisableTransferReports.
+func newTransferReport(transfer *transferFile, results *TransferResults) (directorUrl string, report server_structs.TransferReport, ok bool) {
+	if param.Client_DisableTransferReports.GetBool() {
+		return
+	}
+	if transfer.xferType != transferTypeDownload || transfer.job == nil || transfer.job.cacheMode || transfer.job.directorUrl == "" {
+		return
+	}
+	if results.Error != nil || len(results.Attempts) == 0 {
+		return
+	}
+	last := results.Attempts[len(results.Attempts)-1]
+	if last.Error != nil || last.TransferFileBytes < server_structs.MinTransferReportBytes || last.TransferTime <= 0 {
+		return
+	}
+
+	for _, attempt := range transfer.attempts {
+		if attempt.Url == nil || attempt.Url.Scheme == "unix" || attempt.Url.Host != last.Endpoint {
+			continue
+		}
+		serverUrl := url.URL{Scheme: attempt.Url.Scheme, Host: attempt.Url.Host}
+		return transfer.job.directorUrl, server_structs.TransferReport{
+			ServerURL:       serverUrl.String(),
+			Bytes:           last.TransferFileBytes,
+			Duration:        last.TransferTime,
+			TimeToFirstByte: last.TimeToFirstByte,
+		}, true
+	}
+	return
+}
+
+// Queue a throughput report for a completed transfer, if it qualifies.
+func (te *TransferEngine) reportTransfer(transfer *transferFile, results *TransferResults) {
+	if te == nil || te.reporter == nil {
+		return
+	}
+	if directorUrl, report, ok := newTransferReport(transfer, results); ok {
+		te.reporter.add(directorUrl, report)
+	}
+}
@@ -0,0 +1,122 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pelicanplatform/pelican/param"
+	"github.com/pelicanplatform/pelican/server_structs"
+	"github.com/pelicanplatform/pelican/server_utils"
+)
+
+func TestNewTransferReport(t *testing.T) {
+	server_utils.ResetTestState()
+	t.Cleanup(server_utils.ResetTestState)
+
+	newTransfer := func() (*transferFile, *TransferResults) {
+		transfer := &transferFile{
+			xferType: transferTypeDownload,
+			job:      &TransferJob{directorUrl: "https://director.example.com"},
+			attempts: []transferAttemptDetails{
+				{Url: &url.URL{Scheme: "https", Host: "cache1.example.com:8443"}},
+				{Url: &url.URL{Scheme: "https", Host: "cache2.example.com:8443"}},
+			},
+		}
+		results := &TransferResults{Attempts: []TransferResult{
+			{Endpoint: "cache1.example.com:8443", Error: assert.AnError},
+			{Endpoint: "cache2.example.com:8443", TransferFileBytes: 8 << 20, TransferTime: 2 * time.Second, TimeToFirstByte: 100 * time.Millisecond},
+		}}
+		return transfer, results
+	}
+
+	transfer, results := newTransfer()
+	directorUrl, report, ok := newTransferReport(transfer, results)
+	require.True(t, ok)
+	assert.Equal(t, "https://director.example.com", directorUrl)
+	assert.Equal(t, server_structs.TransferReport{
+		ServerURL:       "https://cache2.example.com:8443",
+		Bytes:           8 << 20,
+		Duration:        2 * time.Second,
+		TimeToFirstByte: 100 * time.Millisecond,
+	}, report)
+
+	transfer, results = newTransfer()
+	transfer.job.cacheMode = true
+	_, _, ok = newTransferReport(transfer, results)
+	assert.False(t, ok, "transfers made on behalf of a cache are not reported")
+
+	transfer, results = newTransfer()
+	results.Attempts[1].TransferFileBytes = 1024
+	_, _, ok = newTransferReport(transfer, results)
+	assert.False(t, ok, "small transfers are not reported")
+
+	transfer, results = newTransfer()
+	transfer.xferType = transferTypeUpload
+	_, _, ok = newTransferReport(transfer, results)
+	assert.False(t, ok, "uploads are not reported")
+
+	require.NoError(t, param.Client_DisableTransferReports.Set(true))
+	transfer, results = newTransfer()
+	_, _, ok = newTransferReport(transfer, results)
+	assert.False(t, ok, "reports can be disabled")
+}
+
+func TestTransferReporterSend(t *testing.T) {
+	server_utils.ResetTestState()
+	t.Cleanup(server_utils.ResetTestState)
+
+	var mu sync.Mutex
+	var batches [][]server_structs.TransferReport
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/v1.0/director/transferReports", r.URL.Path)
+		var req server_structs.TransferReportRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		mu.Lock()
+		batches = append(batches, req.Reports)
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	tr := newTransferReporter()
+	report := server_structs.TransferReport{ServerURL: "https://cache.example.com", Bytes: 8 << 20, Duration: time.Second}
+	for i := 0; i < server_structs.MaxTransferReportsPerRequest+5; i++ {
+		tr.add(srv.URL, report)
+	}
+	tr.send(context.Background())
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, batches, 2, "reports should be split into batches the director accepts")
+	assert.Len(t, batches[0], server_structs.MaxTransferReportsPerRequest)
+	assert.Len(t, batches[1], 5)
+	assert.Empty(t, tr.pending)
+}
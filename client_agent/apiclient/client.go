@@ -19,6 +19,7 @@
 package apiclient
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -26,6 +27,7 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -96,9 +98,16 @@ func (c *APIClient) IsServerRunning(ctx context.Context) bool {
 
 // CreateJob creates a new transfer job and returns the job ID
 func (c *APIClient) CreateJob(ctx context.Context, transfers []client_agent.TransferRequest, options client_agent.TransferOptions) (string, error) {
+	return c.CreateScheduledJob(ctx, transfers, options, client_agent.JobSchedule{})
+}
+
+// CreateScheduledJob creates a new transfer job with a priority, not-before
+// time and dependencies on other jobs
+func (c *APIClient) CreateScheduledJob(ctx context.Context, transfers []client_agent.TransferRequest, options client_agent.TransferOptions, schedule client_agent.JobSchedule) (string, error) {
 	jobReq := client_agent.JobRequest{
-		Transfers: transfers,
-		Options:   options,
+		Transfers:   transfers,
+		Options:     options,
+		JobSchedule: schedule,
 	}
 
 	body, err := json.Marshal(jobReq)
@@ -187,6 +196,78 @@ func (c *APIClient) WaitForJob(ctx context.Context, jobID string, timeout time.D
 	}
 }
 
+// ErrEventsNotSupported is returned by StreamJobEvents when the server
+// predates the job event stream
+var ErrEventsNotSupported = errors.New("client agent server does not support job event streams")
+
+// StreamJobEvents follows a job's event stream, calling handler for each
+// event until the job reaches a terminal state, the handler returns an error,
+// or the context is cancelled.  The first event is the job's current status.
+func (c *APIClient) StreamJobEvents(ctx context.Context, jobID string, handler func(client_agent.JobEvent) error) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/jobs/"+jobID+"/events", nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to create request")
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	// The stream lasts as long as the job, so don't apply the client timeout
+	streamClient := &http.Client{Transport: c.httpClient.Transport}
+	resp, err := streamClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to send request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		var errResp client_agent.ErrorResponse
+		if resp.StatusCode == http.StatusNotFound && (json.Unmarshal(body, &errResp) != nil || errResp.Code != client_agent.ErrCodeNotFound) {
+			// A 404 from the router rather than for the job itself
+			return ErrEventsNotSupported
+		}
+		return errors.Errorf("server returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	var data strings.Builder
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			// A blank line ends the event
+			if data.Len() == 0 {
+				continue
+			}
+			var event client_agent.JobEvent
+			if err := json.Unmarshal([]byte(data.String()), &event); err != nil {
+				return errors.Wrap(err, "failed to decode job event")
+			}
+			data.Reset()
+			if err := handler(event); err != nil {
+				return err
+			}
+			if event.Type == client_agent.EventJobStatus && client_agent.IsTerminalStatus(event.Status) {
+				return nil
+			}
+		case strings.HasPrefix(line, "data:"):
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+		// Other fields (e.g. "event:") and comments are ignored; the event
+		// type is part of the data
+	}
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		return errors.Wrap(err, "failed to read job event stream")
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return errors.New("job event stream ended before the job completed")
+}
+
 // CancelJob cancels a running job
 func (c *APIClient) CancelJob(ctx context.Context, jobID string) error {
 	req, err := http.NewRequestWithContext(ctx, "DELETE", c.baseURL+"/jobs/"+jobID, nil)
@@ -22,7 +22,10 @@ package apiclient_test
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net"
+	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
@@ -574,3 +577,69 @@ func TestAPIClientEndToEnd(t *testing.T) {
 
 	t.Log("End-to-end test completed successfully!")
 }
+
+// TestAPIClientStreamJobEvents tests parsing of the job event stream against a
+// canned server
+func TestAPIClientStreamJobEvents(t *testing.T) {
+	socketDir, err := os.MkdirTemp("/tmp", "pelican-test-*")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(socketDir) })
+	socketPath := filepath.Join(socketDir, "agent.sock")
+
+	listener, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1.0/transfer-agent/jobs/job-1/events", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		events := []client_agent.JobEvent{
+			{Type: client_agent.EventJobStatus, JobID: "job-1", Status: client_agent.StatusRunning},
+			{Type: client_agent.EventTransferProgress, JobID: "job-1", TransferID: "xfer-1", BytesTransferred: 512, TotalBytes: 1024},
+			{Type: client_agent.EventTransferStatus, JobID: "job-1", TransferID: "xfer-1", Status: client_agent.StatusCompleted},
+			{Type: client_agent.EventJobStatus, JobID: "job-1", Status: client_agent.StatusCompleted},
+			// Nothing after the terminal status is delivered
+			{Type: client_agent.EventJobStatus, JobID: "job-1", Status: client_agent.StatusRunning},
+		}
+		_, _ = fmt.Fprint(w, ": keepalive\n\n")
+		for _, event := range events {
+			data, err := json.Marshal(event)
+			require.NoError(t, err)
+			_, _ = fmt.Fprintf(w, "event:%s\ndata:%s\n\n", event.Type, data)
+		}
+	})
+	mux.HandleFunc("/api/v1.0/transfer-agent/jobs/missing/events", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(client_agent.ErrorResponse{Code: client_agent.ErrCodeNotFound, Error: "Job not found"})
+	})
+	svr := &http.Server{Handler: mux}
+	go func() { _ = svr.Serve(listener) }()
+	t.Cleanup(func() { _ = svr.Close() })
+
+	apiClient, err := apiclient.NewAPIClient(socketPath)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	t.Run("Stream", func(t *testing.T) {
+		var received []client_agent.JobEvent
+		err := apiClient.StreamJobEvents(ctx, "job-1", func(event client_agent.JobEvent) error {
+			received = append(received, event)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Len(t, received, 4)
+		assert.Equal(t, int64(512), received[1].BytesTransferred)
+		assert.Equal(t, "xfer-1", received[2].TransferID)
+		assert.Equal(t, client_agent.StatusCompleted, received[3].Status)
+	})
+
+	t.Run("JobNotFound", func(t *testing.T) {
+		err := apiClient.StreamJobEvents(ctx, "missing", func(client_agent.JobEvent) error { return nil })
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, apiclient.ErrEventsNotSupported)
+	})
+
+	t.Run("Unsupported", func(t *testing.T) {
+		err := apiClient.StreamJobEvents(ctx, "older-server", func(client_agent.JobEvent) error { return nil })
+		assert.ErrorIs(t, err, apiclient.ErrEventsNotSupported)
+	})
+}
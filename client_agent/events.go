@@ -0,0 +1,150 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package client_agent
+
+import (
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/pelicanplatform/pelican/client"
+)
+
+const (
+	// Buffered events per subscriber; a subscriber that falls further behind
+	// misses events rather than blocking the transfer
+	eventBufferSize = 256
+
+	// Minimum time between progress events for a single transfer
+	progressEventInterval = time.Second
+)
+
+// eventBroker fans out job events to the subscribers of each job
+type eventBroker struct {
+	mu   sync.Mutex
+	subs map[string]map[chan JobEvent]struct{} // Keyed by job ID
+}
+
+func newEventBroker() *eventBroker {
+	return &eventBroker{subs: make(map[string]map[chan JobEvent]struct{})}
+}
+
+// subscribe returns a channel receiving the events of the given job, and a
+// function to unsubscribe that must be called once the caller is done.
+func (b *eventBroker) subscribe(jobID string) (<-chan JobEvent, func()) {
+	ch := make(chan JobEvent, eventBufferSize)
+	b.mu.Lock()
+	if b.subs[jobID] == nil {
+		b.subs[jobID] = make(map[chan JobEvent]struct{})
+	}
+	b.subs[jobID][ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			delete(b.subs[jobID], ch)
+			if len(b.subs[jobID]) == 0 {
+				delete(b.subs, jobID)
+			}
+		})
+	}
+}
+
+// publish sends an event to the subscribers of its job without blocking
+func (b *eventBroker) publish(event JobEvent) {
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subs[event.JobID] {
+		select {
+		case ch <- event:
+		default:
+			log.Debugf("Dropping %s event for job %s: subscriber is not keeping up", event.Type, event.JobID)
+		}
+	}
+}
+
+// SubscribeJobEvents returns a channel receiving the progress and state
+// changes of the given job, and a function to unsubscribe
+func (tm *TransferManager) SubscribeJobEvents(jobID string) (<-chan JobEvent, func()) {
+	return tm.events.subscribe(jobID)
+}
+
+// publishJobStatus sends the job's current status to its subscribers.  Must
+// not be called with tm.mu held.
+func (tm *TransferManager) publishJobStatus(job *TransferJob) {
+	status := tm.GetJobStatus(job)
+	tm.events.publish(JobEvent{
+		Type:   EventJobStatus,
+		JobID:  job.ID,
+		Status: status.Status,
+		Job:    &status,
+		Error:  status.Error,
+	})
+}
+
+// publishTransferStatus sends a transfer's state change to the subscribers
+// of its job, along with the results of a completed transfer
+func (tm *TransferManager) publishTransferStatus(transfer *Transfer, status string, results []client.TransferResults, err error) {
+	event := JobEvent{
+		Type:             EventTransferStatus,
+		JobID:            transfer.JobID,
+		TransferID:       transfer.ID,
+		Status:           status,
+		BytesTransferred: transfer.BytesTransferred.Load(),
+		TotalBytes:       transfer.TotalBytes.Load(),
+		Results:          summarizeResults(results),
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	tm.events.publish(event)
+}
+
+func summarizeResults(results []client.TransferResults) []TransferResultInfo {
+	if len(results) == 0 {
+		return nil
+	}
+	infos := make([]TransferResultInfo, 0, len(results))
+	for _, result := range results {
+		info := TransferResultInfo{
+			Source:           result.Source,
+			TransferredBytes: result.TransferredBytes,
+			StartTime:        result.TransferStartTime,
+			Attempts:         len(result.Attempts),
+		}
+		if len(result.ClientChecksums) > 0 {
+			info.Checksum = client.FormatChecksum(result.ClientChecksums[0])
+		}
+		if len(result.Attempts) > 0 {
+			info.Endpoint = result.Attempts[len(result.Attempts)-1].Endpoint
+		}
+		if result.Error != nil {
+			info.Error = result.Error.Error()
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
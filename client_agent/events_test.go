@@ -0,0 +1,143 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package client_agent
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventBroker(t *testing.T) {
+	broker := newEventBroker()
+
+	events, unsubscribe := broker.subscribe("job-1")
+	other, unsubscribeOther := broker.subscribe("job-2")
+	defer unsubscribeOther()
+
+	broker.publish(JobEvent{Type: EventJobStatus, JobID: "job-1", Status: StatusRunning})
+	select {
+	case event := <-events:
+		assert.Equal(t, StatusRunning, event.Status)
+		assert.False(t, event.Timestamp.IsZero())
+	default:
+		t.Fatal("Subscriber did not receive the event of its job")
+	}
+	assert.Empty(t, other, "Subscribers must only receive the events of their job")
+
+	// A subscriber that doesn't keep up must not block the publisher
+	for i := 0; i < eventBufferSize+10; i++ {
+		broker.publish(JobEvent{Type: EventTransferProgress, JobID: "job-1"})
+	}
+	assert.Len(t, events, eventBufferSize)
+
+	unsubscribe()
+	unsubscribe()
+	broker.mu.Lock()
+	_, found := broker.subs["job-1"]
+	broker.mu.Unlock()
+	assert.False(t, found)
+}
+
+func TestJobEventsHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ctx := context.Background()
+	tm := NewTransferManager(ctx, 5, nil)
+	defer func() {
+		_ = tm.Shutdown()
+	}()
+
+	server := &Server{
+		transferManager: tm,
+		router:          gin.New(),
+	}
+	server.setupRoutes()
+	svr := httptest.NewServer(server.router)
+	defer svr.Close()
+
+	t.Run("not-found", func(t *testing.T) {
+		resp, err := http.Get(svr.URL + "/api/v1.0/transfer-agent/jobs/nonexistent/events")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+		var errResp ErrorResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&errResp))
+		assert.Equal(t, ErrCodeNotFound, errResp.Code)
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		job, err := tm.CreateJob([]TransferRequest{
+			{
+				Operation:   "get",
+				Source:      "osdf:///test/file.txt",
+				Destination: "/tmp/test.txt",
+			},
+		}, nil)
+		require.NoError(t, err)
+
+		reqCtx, cancel := context.WithTimeout(ctx, 20*time.Second)
+		defer cancel()
+		req, err := http.NewRequestWithContext(reqCtx, "GET", svr.URL+"/api/v1.0/transfer-agent/jobs/"+job.ID+"/events", nil)
+		require.NoError(t, err)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+		// The job either fails on its own (the client is not initialized in
+		// unit tests) or is cancelled here; either way the stream must end
+		// with a terminal job status
+		_, _, _ = tm.CancelJob(job.ID)
+
+		var events []JobEvent
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			data, found := strings.CutPrefix(scanner.Text(), "data:")
+			if !found {
+				continue
+			}
+			var event JobEvent
+			require.NoError(t, json.Unmarshal([]byte(data), &event))
+			assert.Equal(t, job.ID, event.JobID)
+			events = append(events, event)
+		}
+		require.NoError(t, scanner.Err())
+
+		require.NotEmpty(t, events)
+		assert.Equal(t, EventJobStatus, events[0].Type, "The stream must start with a status snapshot")
+		require.NotNil(t, events[0].Job)
+		last := events[len(events)-1]
+		assert.Equal(t, EventJobStatus, last.Type)
+		assert.True(t, IsTerminalStatus(last.Status), "Unexpected final status %q", last.Status)
+		require.NotNil(t, last.Job)
+		assert.Len(t, last.Job.Transfers, 1)
+	})
+}
@@ -25,6 +25,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 
 	"github.com/pelicanplatform/pelican/client"
@@ -33,6 +34,9 @@ import (
 
 var serverStartTime = time.Now()
 
+// How often an idle job event stream sends a keepalive comment
+const jobEventKeepaliveInterval = 15 * time.Second
+
 // CreateJobHandler handles POST /api/v1.0/transfer-agent/jobs
 func (s *Server) CreateJobHandler(c *gin.Context) {
 	var req JobRequest
@@ -62,13 +66,29 @@ func (s *Server) CreateJobHandler(c *gin.Context) {
 			})
 			return
 		}
+		if transfer.ExpectedSize != nil || transfer.ExpectedChecksum != "" {
+			if err := validateExpectedObject(transfer); err != nil {
+				c.JSON(http.StatusBadRequest, ErrorResponse{
+					Code:  ErrCodeInvalidRequest,
+					Error: err.Error(),
+				})
+				return
+			}
+		}
 	}
 
 	// Build transfer options
 	options := buildTransferOptions(req.Options)
 
 	// Create job
-	job, err := s.transferManager.CreateJob(req.Transfers, options)
+	job, err := s.transferManager.CreateScheduledJob(req.Transfers, options, req.JobSchedule)
+	if errors.Is(err, ErrInvalidSchedule) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Code:  ErrCodeInvalidRequest,
+			Error: err.Error(),
+		})
+		return
+	}
 	if err != nil {
 		log.Errorf("Failed to create job: %v", err)
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
@@ -113,46 +133,77 @@ func (s *Server) GetJobStatusHandler(c *gin.Context) {
 		return
 	}
 
-	// Build transfer statuses
-	transfers := make([]TransferStatus, len(job.Transfers))
-	for i, transfer := range job.Transfers {
-		status := TransferStatus{
-			TransferID:       transfer.ID,
-			JobID:            transfer.JobID,
-			Operation:        transfer.Operation,
-			Source:           transfer.Source,
-			Destination:      transfer.Destination,
-			Status:           transfer.Status,
-			CreatedAt:        transfer.CreatedAt,
-			StartedAt:        transfer.StartedAt,
-			CompletedAt:      transfer.CompletedAt,
-			BytesTransferred: transfer.BytesTransferred.Load(),
-			TotalBytes:       transfer.TotalBytes.Load(),
-		}
-		if transfer.Error != nil {
-			status.Error = transfer.Error.Error()
-		}
-		transfers[i] = status
+	c.JSON(http.StatusOK, s.transferManager.GetJobStatus(job))
+}
+
+// JobEventsHandler handles GET /api/v1.0/transfer-agent/jobs/:job_id/events
+//
+// Streams the job's progress and state changes as Server-Sent Events.  The
+// first event is the job's current status; the stream ends after the job
+// reaches a terminal state.
+func (s *Server) JobEventsHandler(c *gin.Context) {
+	jobID := c.Param("job_id")
+
+	// Subscribe before taking the initial snapshot so no change is missed
+	events, unsubscribe := s.transferManager.SubscribeJobEvents(jobID)
+	defer unsubscribe()
+
+	job, err := s.transferManager.GetJob(jobID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Code:  ErrCodeNotFound,
+			Error: "Job not found",
+		})
+		return
 	}
 
-	// Get progress
-	progress := s.transferManager.GetJobProgress(job)
+	// The stream outlives the server's write timeout
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		log.Debugf("Failed to clear the write deadline for the event stream of job %s: %v", jobID, err)
+	}
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Status(http.StatusOK)
 
-	// Build job status
-	jobStatus := JobStatus{
-		JobID:       job.ID,
-		Status:      job.Status,
-		CreatedAt:   job.CreatedAt,
-		StartedAt:   job.StartedAt,
-		CompletedAt: job.CompletedAt,
-		Progress:    progress,
-		Transfers:   transfers,
+	send := func(event JobEvent) bool {
+		c.SSEvent(event.Type, event)
+		c.Writer.Flush()
+		s.UpdateActivity()
+		return !(event.Type == EventJobStatus && IsTerminalStatus(event.Status))
+	}
+	snapshot := func() JobEvent {
+		status := s.transferManager.GetJobStatus(job)
+		return JobEvent{Type: EventJobStatus, JobID: jobID, Status: status.Status, Job: &status, Error: status.Error, Timestamp: time.Now()}
 	}
-	if job.Error != nil {
-		jobStatus.Error = job.Error.Error()
+
+	if !send(snapshot()) {
+		return
 	}
 
-	c.JSON(http.StatusOK, jobStatus)
+	// Events may be dropped for a slow client, so periodically check whether
+	// the job has finished as well as keeping the connection alive
+	keepalive := time.NewTicker(jobEventKeepaliveInterval)
+	defer keepalive.Stop()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case event := <-events:
+			if !send(event) {
+				return
+			}
+		case <-keepalive.C:
+			if event := snapshot(); IsTerminalStatus(event.Status) {
+				send(event)
+				return
+			}
+			if _, err := c.Writer.WriteString(": keepalive\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+	}
 }
 
 // CancelJobHandler handles DELETE /api/v1.0/transfer-agent/jobs/:job_id
@@ -531,3 +582,19 @@ func buildTransferOptions(opts TransferOptions) []client.TransferOption {
 
 	return options
 }
+
+// validateExpectedObject checks the expected size and checksum of a transfer
+func validateExpectedObject(transfer TransferRequest) error {
+	if transfer.Recursive {
+		return errors.New("An expected size or checksum can only be given for a transfer of a single object")
+	}
+	if transfer.ExpectedSize != nil && *transfer.ExpectedSize < 0 {
+		return errors.Errorf("Invalid expected size %d", *transfer.ExpectedSize)
+	}
+	if transfer.ExpectedChecksum != "" {
+		if _, err := client.ParseChecksum(transfer.ExpectedChecksum); err != nil {
+			return errors.Wrap(err, "Invalid expected checksum")
+		}
+	}
+	return nil
+}
@@ -287,6 +287,46 @@ func TestInvalidJobRequest(t *testing.T) {
 	assert.Contains(t, errResp.Error, "Transfers")
 }
 
+func TestInvalidExpectedChecksum(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ctx := context.Background()
+	tm := NewTransferManager(ctx, 5, nil)
+	defer func() {
+		_ = tm.Shutdown()
+	}()
+
+	server := &Server{
+		transferManager: tm,
+		router:          gin.New(),
+	}
+	server.setupRoutes()
+
+	size := int64(10)
+	for name, transfer := range map[string]TransferRequest{
+		"bad-algorithm": {ExpectedChecksum: "sha256:00"},
+		"bad-value":     {ExpectedChecksum: "md5:xyz"},
+		"recursive":     {Recursive: true, ExpectedSize: &size},
+	} {
+		t.Run(name, func(t *testing.T) {
+			transfer.Operation = "get"
+			transfer.Source = "pelican://example.com/test/file"
+			transfer.Destination = "/tmp/file"
+			body, _ := json.Marshal(JobRequest{Transfers: []TransferRequest{transfer}})
+			req, _ := http.NewRequest("POST", "/api/v1.0/transfer-agent/jobs", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			server.router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			var errResp ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &errResp))
+			assert.Equal(t, ErrCodeInvalidRequest, errResp.Code)
+		})
+	}
+}
+
 func TestGetJobNotFound(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -28,12 +28,26 @@ type TransferRequest struct {
 	Source      string `json:"source" binding:"required"`
 	Destination string `json:"destination"`
 	Recursive   bool   `json:"recursive"`
+	// The size and checksum ("<algorithm>:<hex value>") the transferred object
+	// must have; the transfer fails if it doesn't match.  Only for transfers
+	// of a single object.
+	ExpectedSize     *int64 `json:"expected_size,omitempty"`
+	ExpectedChecksum string `json:"expected_checksum,omitempty"`
 }
 
 // JobRequest represents a request to create a new transfer job
 type JobRequest struct {
 	Transfers []TransferRequest `json:"transfers" binding:"required,min=1,dive"`
 	Options   TransferOptions   `json:"options"`
+	JobSchedule
+}
+
+// JobSchedule contains the scheduling constraints of a job.  Pending jobs
+// start in order of priority, then submission, once they are eligible.
+type JobSchedule struct {
+	Priority  int        `json:"priority,omitempty"`   // Higher priority jobs start first; defaults to 0
+	NotBefore *time.Time `json:"not_before,omitempty"` // The job does not start before this time
+	DependsOn []string   `json:"depends_on,omitempty"` // Jobs that must complete successfully before this job starts
 }
 
 // TransferOptions contains options that apply to all transfers in a job
@@ -71,6 +85,7 @@ type JobStatus struct {
 	Progress    *JobProgress     `json:"progress,omitempty"`
 	Transfers   []TransferStatus `json:"transfers"`
 	Error       string           `json:"error,omitempty"`
+	JobSchedule
 }
 
 // JobProgress tracks overall job progress
@@ -98,9 +113,38 @@ type TransferStatus struct {
 	BytesTransferred int64      `json:"bytes_transferred"`
 	TotalBytes       int64      `json:"total_bytes"`
 	TransferRateMbps float64    `json:"transfer_rate_mbps"`
+	ExpectedSize     *int64     `json:"expected_size,omitempty"`
+	ExpectedChecksum string     `json:"expected_checksum,omitempty"`
 	Error            string     `json:"error,omitempty"`
 }
 
+// JobEvent is a progress update or state change pushed to the subscribers of
+// a job's event stream (GET /api/v1.0/transfer-agent/jobs/:job_id/events)
+type JobEvent struct {
+	Type             string               `json:"type"`
+	JobID            string               `json:"job_id"`
+	TransferID       string               `json:"transfer_id,omitempty"`
+	Status           string               `json:"status,omitempty"`
+	BytesTransferred int64                `json:"bytes_transferred,omitempty"`
+	TotalBytes       int64                `json:"total_bytes,omitempty"`
+	Job              *JobStatus           `json:"job,omitempty"`     // Full job status, for job_status events
+	Results          []TransferResultInfo `json:"results,omitempty"` // Final results, for completed transfers
+	Error            string               `json:"error,omitempty"`
+	Timestamp        time.Time            `json:"timestamp"`
+}
+
+// TransferResultInfo summarizes the client's TransferResults for one object
+// of a completed transfer
+type TransferResultInfo struct {
+	Source           string    `json:"source,omitempty"`
+	TransferredBytes int64     `json:"transferred_bytes"`
+	StartTime        time.Time `json:"start_time"`
+	Attempts         int       `json:"attempts"`
+	Endpoint         string    `json:"endpoint,omitempty"` // Server used by the final attempt
+	Checksum         string    `json:"checksum,omitempty"` // Checksum computed by the client, as "<algorithm>:<hex value>"
+	Error            string    `json:"error,omitempty"`
+}
+
 // JobListItem represents a job in a list response
 type JobListItem struct {
 	JobID              string    `json:"job_id"`
@@ -201,6 +245,13 @@ const (
 	ErrCodeConflict       = "CONFLICT"
 )
 
+// Job event types
+const (
+	EventJobStatus        = "job_status"        // The job changed state; sent first with the current state
+	EventTransferStatus   = "transfer_status"   // A transfer changed state
+	EventTransferProgress = "transfer_progress" // A running transfer moved more data
+)
+
 // Job and transfer status constants
 const (
 	StatusPending   = "pending"
@@ -209,3 +260,9 @@ const (
 	StatusFailed    = "failed"
 	StatusCancelled = "cancelled"
 )
+
+// IsTerminalStatus returns true if a job or transfer in the given status will
+// not change state again
+func IsTerminalStatus(status string) bool {
+	return status == StatusCompleted || status == StatusFailed || status == StatusCancelled
+}
@@ -0,0 +1,226 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package client_agent
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/pelicanplatform/pelican/client_agent/types"
+)
+
+// ErrInvalidSchedule is returned when a job's scheduling constraints cannot be
+// satisfied, e.g. it depends on an unknown or failed job
+var ErrInvalidSchedule = errors.New("invalid job schedule")
+
+type (
+	// jobQueue hands out the manager's execution slots to waiting jobs in
+	// order of priority, then submission
+	jobQueue struct {
+		mu      sync.Mutex
+		limit   int
+		running int
+		waiting []*queuedJob
+	}
+
+	queuedJob struct {
+		job   *TransferJob
+		ready chan struct{} // Closed once the job holds a slot
+	}
+)
+
+func newJobQueue(limit int) *jobQueue {
+	return &jobQueue{limit: limit}
+}
+
+// acquire blocks until the job is granted an execution slot, or the context
+// is cancelled.  A granted slot must be returned with release.
+func (q *jobQueue) acquire(ctx context.Context, job *TransferJob) error {
+	entry := &queuedJob{job: job, ready: make(chan struct{})}
+	q.mu.Lock()
+	q.waiting = append(q.waiting, entry)
+	q.dispatchLocked()
+	q.mu.Unlock()
+
+	select {
+	case <-entry.ready:
+		return nil
+	case <-ctx.Done():
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for idx, waiting := range q.waiting {
+		if waiting == entry {
+			q.waiting = append(q.waiting[:idx], q.waiting[idx+1:]...)
+			return ctx.Err()
+		}
+	}
+	// The slot was granted concurrently with the cancellation; hand it on
+	q.running--
+	q.dispatchLocked()
+	return ctx.Err()
+}
+
+// release returns an execution slot and starts the next waiting job, if any
+func (q *jobQueue) release() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.running--
+	q.dispatchLocked()
+}
+
+func (q *jobQueue) dispatchLocked() {
+	for q.running < q.limit && len(q.waiting) > 0 {
+		next := 0
+		for idx, entry := range q.waiting[1:] {
+			if runsBefore(entry.job, q.waiting[next].job) {
+				next = idx + 1
+			}
+		}
+		entry := q.waiting[next]
+		q.waiting = append(q.waiting[:next], q.waiting[next+1:]...)
+		q.running++
+		close(entry.ready)
+	}
+}
+
+// runsBefore reports whether job a should start before job b
+func runsBefore(a, b *TransferJob) bool {
+	if a.Schedule.Priority != b.Schedule.Priority {
+		return a.Schedule.Priority > b.Schedule.Priority
+	}
+	return a.sequence < b.sequence
+}
+
+// storedSchedule converts a job's schedule to its persisted form
+func storedSchedule(job *TransferJob) types.JobSchedule {
+	return types.JobSchedule{
+		Priority:  job.Schedule.Priority,
+		NotBefore: job.Schedule.NotBefore,
+		DependsOn: job.Schedule.DependsOn,
+		Sequence:  job.sequence,
+	}
+}
+
+// resolveDependencies looks up the jobs a new or recovered job depends on.
+// Dependencies that already completed successfully are dropped; a dependency
+// that is unknown or finished unsuccessfully is an error.  Must be called with
+// tm.mu held.
+func (tm *TransferManager) resolveDependencies(jobIDs []string) ([]*TransferJob, error) {
+	var deps []*TransferJob
+	for _, depID := range jobIDs {
+		if dep, ok := tm.jobs[depID]; ok {
+			if IsTerminalStatus(dep.Status) && dep.Status != StatusCompleted {
+				return nil, errors.Wrapf(ErrInvalidSchedule, "dependency job %s is %s", depID, dep.Status)
+			}
+			deps = append(deps, dep)
+			continue
+		}
+
+		// Jobs that finished before a restart or were archived are only in
+		// the database
+		status := ""
+		if tm.store != nil {
+			if stored, err := tm.store.GetJob(depID); err == nil {
+				status = stored.Status
+			} else if historical, err := tm.store.GetHistoricalJob(depID); err == nil {
+				status = historical.Status
+			}
+		}
+		switch status {
+		case "":
+			return nil, errors.Wrapf(ErrInvalidSchedule, "unknown dependency job %s", depID)
+		case StatusCompleted:
+		default:
+			return nil, errors.Wrapf(ErrInvalidSchedule, "dependency job %s is %s", depID, status)
+		}
+	}
+	return deps, nil
+}
+
+// waitForSchedule blocks until the job's dependencies have completed and its
+// not-before time has passed.  Returns an error if a dependency did not
+// complete successfully or the job was cancelled while waiting.
+func (tm *TransferManager) waitForSchedule(job *TransferJob) error {
+	for _, dep := range job.dependencies {
+		select {
+		case <-dep.done:
+		case <-job.ctx.Done():
+			return job.ctx.Err()
+		}
+		tm.mu.RLock()
+		status := dep.Status
+		tm.mu.RUnlock()
+		if status != StatusCompleted {
+			return errors.Errorf("dependency job %s is %s", dep.ID, status)
+		}
+	}
+
+	if notBefore := job.Schedule.NotBefore; notBefore != nil {
+		if delay := time.Until(*notBefore); delay > 0 {
+			log.Infof("Job %s is scheduled to start at %s", job.ID, notBefore.Format(time.RFC3339))
+			timer := time.NewTimer(delay)
+			defer timer.Stop()
+			select {
+			case <-timer.C:
+			case <-job.ctx.Done():
+				return job.ctx.Err()
+			}
+		}
+	}
+	return nil
+}
+
+// failJobBeforeStart marks a job that cannot run as failed, along with all its
+// transfers
+func (tm *TransferManager) failJobBeforeStart(job *TransferJob, err error) {
+	log.Warnf("Job %s failed before starting: %v", job.ID, err)
+	tm.cancelRemainingTransfers(job)
+
+	tm.mu.Lock()
+	completedAt := time.Now()
+	job.Status = StatusFailed
+	job.Error = err
+	job.CompletedAt = &completedAt
+	tm.mu.Unlock()
+
+	if tm.store != nil {
+		for _, transfer := range job.Transfers {
+			if storeErr := tm.store.UpdateTransferStatus(transfer.ID, StatusCancelled); storeErr != nil {
+				log.Warnf("Failed to update transfer %s status in database: %v", transfer.ID, storeErr)
+			}
+		}
+		if storeErr := tm.store.UpdateJobStatus(job.ID, StatusFailed); storeErr != nil {
+			log.Warnf("Failed to update job %s status in database: %v", job.ID, storeErr)
+		}
+		if storeErr := tm.store.UpdateJobTimes(job.ID, nil, &completedAt); storeErr != nil {
+			log.Warnf("Failed to update job %s completion time in database: %v", job.ID, storeErr)
+		}
+		if storeErr := tm.store.UpdateJobError(job.ID, err.Error()); storeErr != nil {
+			log.Warnf("Failed to update job %s error in database: %v", job.ID, storeErr)
+		}
+	}
+
+	tm.publishJobStatus(job)
+}
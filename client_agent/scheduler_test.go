@@ -0,0 +1,227 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package client_agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pelicanplatform/pelican/client_agent/store"
+	"github.com/pelicanplatform/pelican/client_agent/types"
+)
+
+func TestJobQueueOrder(t *testing.T) {
+	q := newJobQueue(1)
+	ctx := context.Background()
+
+	// Hold the only slot while the other jobs queue up
+	require.NoError(t, q.acquire(ctx, &TransferJob{ID: "first"}))
+
+	jobs := []*TransferJob{
+		{ID: "low", sequence: 1},
+		{ID: "high", sequence: 2, Schedule: JobSchedule{Priority: 5}},
+		{ID: "mid-early", sequence: 3, Schedule: JobSchedule{Priority: 1}},
+		{ID: "mid-late", sequence: 4, Schedule: JobSchedule{Priority: 1}},
+	}
+	started := make(chan string, len(jobs))
+	for _, job := range jobs {
+		go func() {
+			if err := q.acquire(ctx, job); err == nil {
+				started <- job.ID
+				q.release()
+			}
+		}()
+	}
+	require.Eventually(t, func() bool {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		return len(q.waiting) == len(jobs)
+	}, 5*time.Second, 10*time.Millisecond)
+
+	q.release()
+	var order []string
+	for range jobs {
+		select {
+		case id := <-started:
+			order = append(order, id)
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timed out waiting for queued jobs to start")
+		}
+	}
+	assert.Equal(t, []string{"high", "mid-early", "mid-late", "low"}, order)
+
+	t.Run("cancel-while-waiting", func(t *testing.T) {
+		require.NoError(t, q.acquire(ctx, &TransferJob{ID: "holder"}))
+		defer q.release()
+
+		cancelCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+		err := q.acquire(cancelCtx, &TransferJob{ID: "cancelled"})
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		assert.Empty(t, q.waiting)
+		assert.Equal(t, 1, q.running)
+	})
+}
+
+func TestJobDependencies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ctx := context.Background()
+	tm := NewTransferManager(ctx, 5, nil)
+	defer func() {
+		_ = tm.Shutdown()
+	}()
+
+	requests := []TransferRequest{{
+		Operation:   "get",
+		Source:      "osdf:///test/file.txt",
+		Destination: "/tmp/test.txt",
+	}}
+
+	// A job that won't start for an hour, and one that depends on it
+	later := time.Now().Add(time.Hour)
+	first, err := tm.CreateScheduledJob(requests, nil, JobSchedule{NotBefore: &later})
+	require.NoError(t, err)
+	second, err := tm.CreateScheduledJob(requests, nil, JobSchedule{DependsOn: []string{first.ID}})
+	require.NoError(t, err)
+
+	status := tm.GetJobStatus(second)
+	assert.Equal(t, StatusPending, status.Status)
+	assert.Equal(t, []string{first.ID}, status.DependsOn)
+	assert.Equal(t, StatusPending, tm.GetJobStatus(first).Status)
+
+	// The dependent job fails once its dependency is cancelled
+	_, _, err = tm.CancelJob(first.ID)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		return tm.GetJobStatus(second).Status == StatusFailed
+	}, 5*time.Second, 10*time.Millisecond)
+	status = tm.GetJobStatus(second)
+	assert.Contains(t, status.Error, first.ID)
+	assert.Equal(t, StatusCancelled, status.Transfers[0].Status)
+
+	t.Run("failed-dependency", func(t *testing.T) {
+		_, err := tm.CreateScheduledJob(requests, nil, JobSchedule{DependsOn: []string{first.ID}})
+		assert.ErrorIs(t, err, ErrInvalidSchedule)
+	})
+
+	t.Run("unknown-dependency", func(t *testing.T) {
+		_, err := tm.CreateScheduledJob(requests, nil, JobSchedule{DependsOn: []string{"nonexistent"}})
+		assert.ErrorIs(t, err, ErrInvalidSchedule)
+
+		server := &Server{
+			transferManager: tm,
+			router:          gin.New(),
+		}
+		server.setupRoutes()
+		body, err := json.Marshal(JobRequest{
+			Transfers:   requests,
+			JobSchedule: JobSchedule{DependsOn: []string{"nonexistent"}},
+		})
+		require.NoError(t, err)
+		req, _ := http.NewRequest("POST", "/api/v1.0/transfer-agent/jobs", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestScheduledJobRecovery(t *testing.T) {
+	testStore, dbPath := setupTestStore(t)
+
+	// Simulate jobs left pending by a previous run: a prestage scheduled for
+	// later, a get that depends on it, and a job with an unknown dependency
+	now := time.Now()
+	later := now.Add(time.Hour)
+	newTransfer := func(jobID, operation string) []map[string]interface{} {
+		return []map[string]interface{}{{
+			"ID":          jobID + "-transfer",
+			"JobID":       jobID,
+			"Operation":   operation,
+			"Source":      "pelican://example.com/test.txt",
+			"Destination": "/tmp/test.txt",
+			"Recursive":   false,
+			"Status":      StatusPending,
+			"CreatedAt":   now.Unix(),
+		}}
+	}
+	require.NoError(t, testStore.CreateJobWithTransfers("prestage-job", StatusPending, now, "{}", 0,
+		types.JobSchedule{Priority: 3, NotBefore: &later, Sequence: 7}, newTransfer("prestage-job", "prestage")))
+	require.NoError(t, testStore.CreateJobWithTransfers("get-job", StatusPending, now, "{}", 0,
+		types.JobSchedule{DependsOn: []string{"prestage-job"}, Sequence: 8}, newTransfer("get-job", "get")))
+	require.NoError(t, testStore.CreateJobWithTransfers("orphan-job", StatusPending, now, "{}", 0,
+		types.JobSchedule{DependsOn: []string{"missing-job"}, Sequence: 9}, newTransfer("orphan-job", "get")))
+	testStore.Close()
+
+	// Restart
+	testStore, err := store.NewStore(dbPath)
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	tm := NewTransferManager(ctx, 5, testStore)
+	t.Cleanup(func() {
+		cancel()
+		_ = tm.Shutdown()
+		testStore.Close()
+	})
+
+	prestageJob, err := tm.GetJob("prestage-job")
+	require.NoError(t, err)
+	getJob, err := tm.GetJob("get-job")
+	require.NoError(t, err)
+
+	// The schedule survives the restart, and submission order is kept
+	assert.Equal(t, 3, prestageJob.Schedule.Priority)
+	require.NotNil(t, prestageJob.Schedule.NotBefore)
+	assert.Equal(t, later.Unix(), prestageJob.Schedule.NotBefore.Unix())
+	assert.Equal(t, []string{"prestage-job"}, getJob.Schedule.DependsOn)
+	assert.Less(t, prestageJob.sequence, getJob.sequence)
+	stored, err := testStore.GetJob("get-job")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"prestage-job"}, stored.Schedule.DependsOn)
+
+	// A job whose dependency no longer exists fails instead of running
+	require.Eventually(t, func() bool {
+		job, err := testStore.GetJob("orphan-job")
+		return err == nil && job.Status == StatusFailed
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// The dependent job waits for the recovered prestage
+	assert.Equal(t, StatusPending, tm.GetJobStatus(getJob).Status)
+	_, _, err = tm.CancelJob("prestage-job")
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		return tm.GetJobStatus(getJob).Status == StatusFailed
+	}, 5*time.Second, 10*time.Millisecond)
+	stored, err = testStore.GetJob("get-job")
+	require.NoError(t, err)
+	assert.Equal(t, StatusFailed, stored.Status)
+	assert.Contains(t, stored.ErrorMessage, "prestage-job")
+}
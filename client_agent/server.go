@@ -201,6 +201,7 @@ func (s *Server) setupRoutes() {
 		api.POST("/jobs", s.CreateJobHandler)
 		api.GET("/jobs", s.ListJobsHandler)
 		api.GET("/jobs/:job_id", s.GetJobStatusHandler)
+		api.GET("/jobs/:job_id/events", s.JobEventsHandler)
 		api.DELETE("/jobs/:job_id", s.CancelJobHandler)
 
 		// History management
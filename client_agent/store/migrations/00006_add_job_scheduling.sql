@@ -0,0 +1,12 @@
+-- +goose Up
+-- Add scheduling columns to jobs table
+ALTER TABLE jobs ADD COLUMN priority INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE jobs ADD COLUMN not_before INTEGER;  -- Unix timestamp in seconds
+ALTER TABLE jobs ADD COLUMN depends_on TEXT;  -- JSON-encoded list of job IDs
+ALTER TABLE jobs ADD COLUMN sequence INTEGER NOT NULL DEFAULT 0;  -- Submission order, breaks priority ties
+
+-- +goose Down
+ALTER TABLE jobs DROP COLUMN sequence;
+ALTER TABLE jobs DROP COLUMN depends_on;
+ALTER TABLE jobs DROP COLUMN not_before;
+ALTER TABLE jobs DROP COLUMN priority;
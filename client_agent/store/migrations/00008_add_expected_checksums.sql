@@ -0,0 +1,8 @@
+-- +goose Up
+-- The size and checksum a transfer's object is expected to have, if any
+ALTER TABLE transfers ADD COLUMN expected_size INTEGER;
+ALTER TABLE transfers ADD COLUMN expected_checksum TEXT;
+
+-- +goose Down
+ALTER TABLE transfers DROP COLUMN expected_checksum;
+ALTER TABLE transfers DROP COLUMN expected_size;
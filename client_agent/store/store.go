@@ -102,7 +102,7 @@ func (s *Store) Close() error {
 
 // RecoverJob atomically recovers a job by deleting the old version and creating a new one
 // with the same ID but incremented retry count. Uses a transaction to ensure atomicity.
-func (s *Store) RecoverJob(jobID string, retryCount int, createdAt time.Time, optionsJSON string, transfers []map[string]interface{}) error {
+func (s *Store) RecoverJob(jobID string, retryCount int, createdAt time.Time, optionsJSON string, schedule types.JobSchedule, transfers []map[string]interface{}) error {
 	tx, err := s.db.Begin()
 	if err != nil {
 		return errors.Wrap(err, "failed to begin transaction")
@@ -120,13 +120,17 @@ func (s *Store) RecoverJob(jobID string, retryCount int, createdAt time.Time, op
 	}
 
 	// Create new job with same ID but incremented retry count
-	insertJobQuery := `INSERT INTO jobs (id, status, created_at, options, retry_count) VALUES (?, ?, ?, ?, ?)`
-	if _, err := tx.Exec(insertJobQuery, jobID, "pending", createdAt.Unix(), optionsJSON, retryCount); err != nil {
+	notBefore, dependsOn := scheduleColumns(schedule)
+	insertJobQuery := `INSERT INTO jobs (id, status, created_at, options, retry_count, priority, not_before, depends_on, sequence)
+	                   VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	if _, err := tx.Exec(insertJobQuery, jobID, "pending", createdAt.Unix(), optionsJSON, retryCount,
+		schedule.Priority, notBefore, dependsOn, schedule.Sequence); err != nil {
 		return errors.Wrap(err, "failed to create recovered job")
 	}
 
 	// Create transfers for the recovered job
-	insertTransferQuery := `INSERT INTO transfers (id, job_id, operation, source, destination, recursive, status, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	insertTransferQuery := `INSERT INTO transfers (id, job_id, operation, source, destination, recursive, status, created_at,
+	                        expected_size, expected_checksum) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 	for _, transfer := range transfers {
 		_, err := tx.Exec(insertTransferQuery,
 			transfer["ID"],
@@ -137,6 +141,8 @@ func (s *Store) RecoverJob(jobID string, retryCount int, createdAt time.Time, op
 			transfer["Recursive"],
 			transfer["Status"],
 			transfer["CreatedAt"],
+			transfer["ExpectedSize"],
+			transfer["ExpectedChecksum"],
 		)
 		if err != nil {
 			return errors.Wrapf(err, "failed to create transfer %s", transfer["ID"])
@@ -152,7 +158,7 @@ func (s *Store) RecoverJob(jobID string, retryCount int, createdAt time.Time, op
 }
 
 // CreateJobWithTransfers atomically creates a job and all its transfers in a single transaction
-func (s *Store) CreateJobWithTransfers(jobID, status string, createdAt time.Time, optionsJSON string, retryCount int, transfers []map[string]interface{}) error {
+func (s *Store) CreateJobWithTransfers(jobID, status string, createdAt time.Time, optionsJSON string, retryCount int, schedule types.JobSchedule, transfers []map[string]interface{}) error {
 	tx, err := s.db.Begin()
 	if err != nil {
 		return errors.Wrap(err, "failed to begin transaction")
@@ -164,13 +170,17 @@ func (s *Store) CreateJobWithTransfers(jobID, status string, createdAt time.Time
 	}()
 
 	// Create job
-	insertJobQuery := `INSERT INTO jobs (id, status, created_at, options, retry_count) VALUES (?, ?, ?, ?, ?)`
-	if _, err := tx.Exec(insertJobQuery, jobID, status, createdAt.Unix(), optionsJSON, retryCount); err != nil {
+	notBefore, dependsOn := scheduleColumns(schedule)
+	insertJobQuery := `INSERT INTO jobs (id, status, created_at, options, retry_count, priority, not_before, depends_on, sequence)
+	                   VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	if _, err := tx.Exec(insertJobQuery, jobID, status, createdAt.Unix(), optionsJSON, retryCount,
+		schedule.Priority, notBefore, dependsOn, schedule.Sequence); err != nil {
 		return errors.Wrap(err, "failed to create job")
 	}
 
 	// Create all transfers
-	insertTransferQuery := `INSERT INTO transfers (id, job_id, operation, source, destination, recursive, status, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	insertTransferQuery := `INSERT INTO transfers (id, job_id, operation, source, destination, recursive, status, created_at,
+	                        expected_size, expected_checksum) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 	for _, transfer := range transfers {
 		_, err := tx.Exec(insertTransferQuery,
 			transfer["ID"],
@@ -181,6 +191,8 @@ func (s *Store) CreateJobWithTransfers(jobID, status string, createdAt time.Time
 			transfer["Recursive"],
 			transfer["Status"],
 			transfer["CreatedAt"],
+			transfer["ExpectedSize"],
+			transfer["ExpectedChecksum"],
 		)
 		if err != nil {
 			return errors.Wrapf(err, "failed to create transfer %s", transfer["ID"])
@@ -195,6 +207,34 @@ func (s *Store) CreateJobWithTransfers(jobID, status string, createdAt time.Time
 	return nil
 }
 
+// scheduleColumns converts the nullable scheduling fields of a job to their
+// database representation
+func scheduleColumns(schedule types.JobSchedule) (notBefore sql.NullInt64, dependsOn sql.NullString) {
+	if schedule.NotBefore != nil {
+		notBefore = sql.NullInt64{Int64: schedule.NotBefore.Unix(), Valid: true}
+	}
+	if len(schedule.DependsOn) > 0 {
+		if dependsJSON, err := json.Marshal(schedule.DependsOn); err == nil {
+			dependsOn = sql.NullString{String: string(dependsJSON), Valid: true}
+		}
+	}
+	return
+}
+
+// parseScheduleColumns fills in the nullable scheduling fields of a job from
+// the database
+func parseScheduleColumns(schedule *types.JobSchedule, notBefore sql.NullInt64, dependsOn sql.NullString) {
+	if notBefore.Valid {
+		t := time.Unix(notBefore.Int64, 0)
+		schedule.NotBefore = &t
+	}
+	if dependsOn.Valid && dependsOn.String != "" {
+		if err := json.Unmarshal([]byte(dependsOn.String), &schedule.DependsOn); err != nil {
+			log.Warnf("Failed to unmarshal job dependencies: %v", err)
+		}
+	}
+}
+
 // CreateJob inserts a new job into the database
 func (s *Store) CreateJob(jobID, status string, createdAt time.Time, optionsJSON string, retryCount int) error {
 	query := `INSERT INTO jobs (id, status, created_at, options, retry_count) VALUES (?, ?, ?, ?, ?)`
@@ -255,16 +295,18 @@ func (s *Store) UpdateJobError(jobID, errorMsg string) error {
 
 // GetJob retrieves a job by ID
 func (s *Store) GetJob(jobID string) (*types.StoredJob, error) {
-	query := `SELECT id, status, created_at, started_at, completed_at, options, error_message, retry_count
+	query := `SELECT id, status, created_at, started_at, completed_at, options, error_message, retry_count,
+	          priority, not_before, depends_on, sequence
 	          FROM jobs WHERE id = ?`
 
 	var job types.StoredJob
-	var startedAt, completedAt sql.NullInt64
-	var options, errorMsg sql.NullString
+	var startedAt, completedAt, notBefore sql.NullInt64
+	var options, errorMsg, dependsOn sql.NullString
 
 	err := s.db.QueryRow(query, jobID).Scan(
 		&job.ID, &job.Status, &job.CreatedAt,
 		&startedAt, &completedAt, &options, &errorMsg, &job.RetryCount,
+		&job.Schedule.Priority, &notBefore, &dependsOn, &job.Schedule.Sequence,
 	)
 
 	if err == sql.ErrNoRows {
@@ -294,6 +336,7 @@ func (s *Store) GetJob(jobID string) (*types.StoredJob, error) {
 	if errorMsg.Valid {
 		job.ErrorMessage = errorMsg.String
 	}
+	parseScheduleColumns(&job.Schedule, notBefore, dependsOn)
 
 	return &job, nil
 }
@@ -301,7 +344,8 @@ func (s *Store) GetJob(jobID string) (*types.StoredJob, error) {
 // ListJobs retrieves jobs with optional filtering
 func (s *Store) ListJobs(status string, limit, offset int) ([]*types.StoredJob, int, error) {
 	// Build query with filters
-	query := `SELECT id, status, created_at, started_at, completed_at, options, error_message, retry_count FROM jobs`
+	query := `SELECT id, status, created_at, started_at, completed_at, options, error_message, retry_count,
+	          priority, not_before, depends_on, sequence FROM jobs`
 	countQuery := `SELECT COUNT(*) FROM jobs`
 	args := []interface{}{}
 
@@ -331,12 +375,13 @@ func (s *Store) ListJobs(status string, limit, offset int) ([]*types.StoredJob,
 	var jobs []*types.StoredJob
 	for rows.Next() {
 		var job types.StoredJob
-		var startedAt, completedAt sql.NullInt64
-		var options, errorMsg sql.NullString
+		var startedAt, completedAt, notBefore sql.NullInt64
+		var options, errorMsg, dependsOn sql.NullString
 
 		err := rows.Scan(
 			&job.ID, &job.Status, &job.CreatedAt,
 			&startedAt, &completedAt, &options, &errorMsg, &job.RetryCount,
+			&job.Schedule.Priority, &notBefore, &dependsOn, &job.Schedule.Sequence,
 		)
 		if err != nil {
 			return nil, 0, errors.Wrap(err, "failed to scan job row")
@@ -362,6 +407,7 @@ func (s *Store) ListJobs(status string, limit, offset int) ([]*types.StoredJob,
 		if errorMsg.Valid {
 			job.ErrorMessage = errorMsg.String
 		}
+		parseScheduleColumns(&job.Schedule, notBefore, dependsOn)
 
 		jobs = append(jobs, &job)
 	}
@@ -418,6 +464,12 @@ func (s *Store) CreateTransfer(transferData interface{}) error {
 		if rec, ok := t["Recursive"].(bool); ok {
 			transfer.Recursive = rec
 		}
+		if size, ok := t["ExpectedSize"].(*int64); ok {
+			transfer.ExpectedSize = size
+		}
+		if checksum, ok := t["ExpectedChecksum"].(string); ok {
+			transfer.ExpectedChecksum = checksum
+		}
 		if status, ok := t["Status"].(string); ok {
 			transfer.Status = status
 		}
@@ -429,13 +481,13 @@ func (s *Store) CreateTransfer(transferData interface{}) error {
 	}
 
 	query := `INSERT INTO transfers
-	          (id, job_id, operation, source, destination, recursive, status, created_at)
-	          VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	          (id, job_id, operation, source, destination, recursive, status, created_at, expected_size, expected_checksum)
+	          VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
 	_, err := s.db.Exec(query,
 		transfer.ID, transfer.JobID, transfer.Operation,
 		transfer.Source, transfer.Destination, transfer.Recursive,
-		transfer.Status, transfer.CreatedAt,
+		transfer.Status, transfer.CreatedAt, transfer.ExpectedSize, transfer.ExpectedChecksum,
 	)
 
 	if err != nil {
@@ -489,18 +541,20 @@ func (s *Store) UpdateTransferError(transferID, errorMsg string) error {
 // GetTransfer retrieves a transfer by ID
 func (s *Store) GetTransfer(transferID string) (*types.StoredTransfer, error) {
 	query := `SELECT id, job_id, operation, source, destination, recursive, status,
-	          created_at, started_at, completed_at, bytes_transferred, total_bytes, error_message
+	          created_at, started_at, completed_at, bytes_transferred, total_bytes, error_message,
+	          expected_size, expected_checksum
 	          FROM transfers WHERE id = ?`
 
 	var transfer types.StoredTransfer
-	var startedAt, completedAt sql.NullInt64
-	var errorMsg sql.NullString
+	var startedAt, completedAt, expectedSize sql.NullInt64
+	var errorMsg, expectedChecksum sql.NullString
 
 	err := s.db.QueryRow(query, transferID).Scan(
 		&transfer.ID, &transfer.JobID, &transfer.Operation,
 		&transfer.Source, &transfer.Destination, &transfer.Recursive, &transfer.Status,
 		&transfer.CreatedAt, &startedAt, &completedAt,
 		&transfer.BytesTransferred, &transfer.TotalBytes, &errorMsg,
+		&expectedSize, &expectedChecksum,
 	)
 
 	if err == sql.ErrNoRows {
@@ -522,6 +576,10 @@ func (s *Store) GetTransfer(transferID string) (*types.StoredTransfer, error) {
 	if errorMsg.Valid {
 		transfer.ErrorMessage = errorMsg.String
 	}
+	if expectedSize.Valid {
+		transfer.ExpectedSize = &expectedSize.Int64
+	}
+	transfer.ExpectedChecksum = expectedChecksum.String
 
 	return &transfer, nil
 }
@@ -529,7 +587,8 @@ func (s *Store) GetTransfer(transferID string) (*types.StoredTransfer, error) {
 // GetTransfersByJob retrieves all transfers for a job
 func (s *Store) GetTransfersByJob(jobID string) ([]*types.StoredTransfer, error) {
 	query := `SELECT id, job_id, operation, source, destination, recursive, status,
-	          created_at, started_at, completed_at, bytes_transferred, total_bytes, error_message
+	          created_at, started_at, completed_at, bytes_transferred, total_bytes, error_message,
+	          expected_size, expected_checksum
 	          FROM transfers WHERE job_id = ? ORDER BY created_at ASC`
 
 	rows, err := s.db.Query(query, jobID)
@@ -541,14 +600,15 @@ func (s *Store) GetTransfersByJob(jobID string) ([]*types.StoredTransfer, error)
 	var transfers []*types.StoredTransfer
 	for rows.Next() {
 		var transfer types.StoredTransfer
-		var startedAt, completedAt sql.NullInt64
-		var errorMsg sql.NullString
+		var startedAt, completedAt, expectedSize sql.NullInt64
+		var errorMsg, expectedChecksum sql.NullString
 
 		err := rows.Scan(
 			&transfer.ID, &transfer.JobID, &transfer.Operation,
 			&transfer.Source, &transfer.Destination, &transfer.Recursive, &transfer.Status,
 			&transfer.CreatedAt, &startedAt, &completedAt,
 			&transfer.BytesTransferred, &transfer.TotalBytes, &errorMsg,
+			&expectedSize, &expectedChecksum,
 		)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to scan transfer row")
@@ -566,6 +626,10 @@ func (s *Store) GetTransfersByJob(jobID string) ([]*types.StoredTransfer, error)
 		if errorMsg.Valid {
 			transfer.ErrorMessage = errorMsg.String
 		}
+		if expectedSize.Valid {
+			transfer.ExpectedSize = &expectedSize.Int64
+		}
+		transfer.ExpectedChecksum = expectedChecksum.String
 
 		transfers = append(transfers, &transfer)
 	}
@@ -576,8 +640,9 @@ func (s *Store) GetTransfersByJob(jobID string) ([]*types.StoredTransfer, error)
 // GetRecoverableJobs returns jobs that need recovery (pending or running status)
 // GetRecoverableJobs returns all jobs that are incomplete (pending/running)
 func (s *Store) GetRecoverableJobs() ([]*types.StoredJob, error) {
-	query := `SELECT id, status, created_at, started_at, completed_at, options, error_message, retry_count
-	          FROM jobs WHERE status IN ('pending', 'running') ORDER BY created_at ASC`
+	query := `SELECT id, status, created_at, started_at, completed_at, options, error_message, retry_count,
+	          priority, not_before, depends_on, sequence
+	          FROM jobs WHERE status IN ('pending', 'running') ORDER BY sequence ASC, created_at ASC`
 
 	rows, err := s.db.Query(query)
 	if err != nil {
@@ -588,12 +653,13 @@ func (s *Store) GetRecoverableJobs() ([]*types.StoredJob, error) {
 	var jobs []*types.StoredJob
 	for rows.Next() {
 		var job types.StoredJob
-		var startedAt, completedAt sql.NullInt64
-		var options, errorMsg sql.NullString
+		var startedAt, completedAt, notBefore sql.NullInt64
+		var options, errorMsg, dependsOn sql.NullString
 
 		err := rows.Scan(
 			&job.ID, &job.Status, &job.CreatedAt,
 			&startedAt, &completedAt, &options, &errorMsg, &job.RetryCount,
+			&job.Schedule.Priority, &notBefore, &dependsOn, &job.Schedule.Sequence,
 		)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to scan job row")
@@ -619,6 +685,7 @@ func (s *Store) GetRecoverableJobs() ([]*types.StoredJob, error) {
 		if errorMsg.Valid {
 			job.ErrorMessage = errorMsg.String
 		}
+		parseScheduleColumns(&job.Schedule, notBefore, dependsOn)
 
 		jobs = append(jobs, &job)
 	}
@@ -817,6 +884,45 @@ func (s *Store) GetJobHistory(status string, from, to time.Time, limit, offset i
 	return jobs, total, nil
 }
 
+// GetHistoricalJob retrieves an archived job by ID
+func (s *Store) GetHistoricalJob(jobID string) (*types.HistoricalJob, error) {
+	query := `SELECT id, status, created_at, started_at, completed_at, error_message,
+	          transfers_completed, transfers_failed, transfers_total, bytes_transferred, total_bytes, retry_count
+	          FROM job_history WHERE id = ?`
+
+	var job types.HistoricalJob
+	var startedAt, completedAt sql.NullInt64
+	var errorMsg sql.NullString
+
+	err := s.db.QueryRow(query, jobID).Scan(
+		&job.ID, &job.Status, &job.CreatedAt,
+		&startedAt, &completedAt, &errorMsg,
+		&job.TransfersCompleted, &job.TransfersFailed, &job.TransfersTotal,
+		&job.BytesTransferred, &job.TotalBytes, &job.RetryCount,
+	)
+	if err == sql.ErrNoRows {
+		return nil, errors.Errorf("historical job %s not found", jobID)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query historical job")
+	}
+
+	// Convert nullable fields
+	if startedAt.Valid {
+		t := time.Unix(startedAt.Int64, 0)
+		job.StartedAt = &t
+	}
+	if completedAt.Valid {
+		t := time.Unix(completedAt.Int64, 0)
+		job.CompletedAt = &t
+	}
+	if errorMsg.Valid {
+		job.ErrorMessage = errorMsg.String
+	}
+
+	return &job, nil
+}
+
 // PruneHistory deletes historical jobs older than the specified time
 func (s *Store) PruneHistory(olderThan time.Time) (int, error) {
 	// Delete from transfer_history first (will cascade via foreign key)
@@ -186,6 +186,50 @@ func TestCreateAndGetTransfer(t *testing.T) {
 	assert.Equal(t, transfer.Destination, retrieved.Destination)
 }
 
+func TestTransferExpectedChecksum(t *testing.T) {
+	store, _ := setupTestDB(t)
+
+	jobID := "test-job-1"
+	err := store.CreateJob(jobID, "pending", time.Now(), "{}", 0)
+	require.NoError(t, err)
+
+	size := int64(1024)
+	err = store.CreateTransfer(&types.StoredTransfer{
+		ID:               "transfer-1",
+		JobID:            jobID,
+		Operation:        "get",
+		Source:           "/source/path",
+		Destination:      "/dest/path",
+		ExpectedSize:     &size,
+		ExpectedChecksum: "md5:d41d8cd98f00b204e9800998ecf8427e",
+		Status:           "pending",
+		CreatedAt:        time.Now().Unix(),
+	})
+	require.NoError(t, err)
+	err = store.CreateTransfer(map[string]interface{}{
+		"ID":          "transfer-2",
+		"JobID":       jobID,
+		"Operation":   "get",
+		"Source":      "/source/other",
+		"Destination": "/dest/other",
+		"Status":      "pending",
+		"CreatedAt":   time.Now().Unix(),
+	})
+	require.NoError(t, err)
+
+	retrieved, err := store.GetTransfer("transfer-1")
+	require.NoError(t, err)
+	require.NotNil(t, retrieved.ExpectedSize)
+	assert.Equal(t, size, *retrieved.ExpectedSize)
+	assert.Equal(t, "md5:d41d8cd98f00b204e9800998ecf8427e", retrieved.ExpectedChecksum)
+
+	// Transfers without expectations have neither
+	retrieved, err = store.GetTransfer("transfer-2")
+	require.NoError(t, err)
+	assert.Nil(t, retrieved.ExpectedSize)
+	assert.Empty(t, retrieved.ExpectedChecksum)
+}
+
 func TestGetTransfersByJob(t *testing.T) {
 	store, _ := setupTestDB(t)
 
@@ -372,3 +416,64 @@ func TestPruneHistory(t *testing.T) {
 	assert.Equal(t, 2, total)
 	assert.Len(t, jobs, 2)
 }
+
+func TestJobSchedule(t *testing.T) {
+	store, _ := setupTestDB(t)
+
+	notBefore := time.Now().Add(time.Hour).Truncate(time.Second)
+	schedule := types.JobSchedule{
+		Priority:  10,
+		NotBefore: &notBefore,
+		DependsOn: []string{"job-a", "job-b"},
+		Sequence:  42,
+	}
+	transfers := []map[string]interface{}{{
+		"ID":          "transfer-1",
+		"JobID":       "scheduled-job",
+		"Operation":   "prestage",
+		"Source":      "pelican://example.com/test.txt",
+		"Destination": "",
+		"Recursive":   false,
+		"Status":      "pending",
+		"CreatedAt":   time.Now().Unix(),
+	}}
+	require.NoError(t, store.CreateJobWithTransfers("scheduled-job", "pending", time.Now(), "{}", 0, schedule, transfers))
+	require.NoError(t, store.CreateJobWithTransfers("unscheduled-job", "pending", time.Now(), "{}", 0, types.JobSchedule{}, nil))
+
+	job, err := store.GetJob("scheduled-job")
+	require.NoError(t, err)
+	assert.Equal(t, schedule.Priority, job.Schedule.Priority)
+	require.NotNil(t, job.Schedule.NotBefore)
+	assert.True(t, notBefore.Equal(*job.Schedule.NotBefore))
+	assert.Equal(t, schedule.DependsOn, job.Schedule.DependsOn)
+	assert.Equal(t, schedule.Sequence, job.Schedule.Sequence)
+
+	job, err = store.GetJob("unscheduled-job")
+	require.NoError(t, err)
+	assert.Equal(t, types.JobSchedule{}, job.Schedule)
+
+	// Recoverable jobs are returned in submission order
+	jobs, err := store.GetRecoverableJobs()
+	require.NoError(t, err)
+	require.Len(t, jobs, 2)
+	assert.Equal(t, "unscheduled-job", jobs[0].ID)
+	assert.Equal(t, "scheduled-job", jobs[1].ID)
+
+	// Recovery keeps the schedule it is given
+	schedule.Sequence = 1
+	require.NoError(t, store.RecoverJob("scheduled-job", 1, time.Now(), "{}", schedule, transfers))
+	job, err = store.GetJob("scheduled-job")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), job.Schedule.Sequence)
+	assert.Equal(t, schedule.DependsOn, job.Schedule.DependsOn)
+
+	// Archived jobs can be looked up individually
+	require.NoError(t, store.UpdateTransferStatus("transfer-1", "completed"))
+	require.NoError(t, store.UpdateJobStatus("scheduled-job", "completed"))
+	require.NoError(t, store.ArchiveJob("scheduled-job"))
+	historical, err := store.GetHistoricalJob("scheduled-job")
+	require.NoError(t, err)
+	assert.Equal(t, "completed", historical.Status)
+	_, err = store.GetHistoricalJob("unscheduled-job")
+	assert.Error(t, err)
+}
@@ -29,7 +29,7 @@ import (
 type StoreInterface interface {
 	// Job operations
 	CreateJob(jobID, status string, createdAt time.Time, optionsJSON string, retryCount int) error
-	CreateJobWithTransfers(jobID, status string, createdAt time.Time, optionsJSON string, retryCount int, transfers []map[string]interface{}) error
+	CreateJobWithTransfers(jobID, status string, createdAt time.Time, optionsJSON string, retryCount int, schedule types.JobSchedule, transfers []map[string]interface{}) error
 	UpdateJobStatus(jobID, status string) error
 	UpdateJobTimes(jobID string, startedAt, completedAt *time.Time) error
 	UpdateJobError(jobID, errorMsg string) error
@@ -48,11 +48,12 @@ type StoreInterface interface {
 
 	// Recovery operations
 	GetRecoverableJobs() ([]*types.StoredJob, error)
-	RecoverJob(jobID string, retryCount int, createdAt time.Time, optionsJSON string, transfers []map[string]interface{}) error
+	RecoverJob(jobID string, retryCount int, createdAt time.Time, optionsJSON string, schedule types.JobSchedule, transfers []map[string]interface{}) error
 
 	// History operations
 	ArchiveJob(jobID string) error
 	GetJobHistory(status string, from, to time.Time, limit, offset int) ([]*types.HistoricalJob, int, error)
+	GetHistoricalJob(jobID string) (*types.HistoricalJob, error)
 	DeleteJobHistory(jobID string) error
 	PruneHistory(olderThan time.Time) (int, error)
 
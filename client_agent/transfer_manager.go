@@ -42,6 +42,8 @@ type Transfer struct {
 	Source           string
 	Destination      string
 	Recursive        bool
+	ExpectedSize     *int64
+	ExpectedChecksum string
 	Status           string
 	CreatedAt        time.Time
 	StartedAt        *time.Time
@@ -62,10 +64,15 @@ type TransferJob struct {
 	CompletedAt *time.Time
 	Transfers   []*Transfer
 	Options     []client.TransferOption
+	Schedule    JobSchedule
 	Error       error
 	CancelFunc  context.CancelFunc
 	ctx         context.Context
 	wg          sync.WaitGroup
+
+	sequence     int64          // Submission order, breaks ties between jobs of equal priority
+	dependencies []*TransferJob // Unfinished jobs that must complete before this one starts
+	done         chan struct{}  // Closed once the job has finished
 }
 
 // TransferManager manages all transfer jobs and their execution
@@ -75,11 +82,13 @@ type TransferManager struct {
 	store                  StoreInterface
 	mu                     sync.RWMutex
 	maxJobs                int
-	semaphore              chan struct{}
+	queue                  *jobQueue
+	nextSequence           int64
 	ctx                    context.Context
 	cancel                 context.CancelFunc
 	eg                     *errgroup.Group
 	backgroundTasksStarted bool
+	events                 *eventBroker
 }
 
 // NewTransferManager creates a new transfer manager
@@ -110,10 +119,11 @@ func NewTransferManager(ctx context.Context, maxConcurrentJobs int, store StoreI
 		transfers: make(map[string]*Transfer),
 		store:     store,
 		maxJobs:   maxConcurrentJobs,
-		semaphore: make(chan struct{}, maxConcurrentJobs),
+		queue:     newJobQueue(maxConcurrentJobs),
 		ctx:       managerCtx,
 		cancel:    cancel,
 		eg:        eg,
+		events:    newEventBroker(),
 	}
 
 	// Attempt to recover incomplete jobs from database
@@ -129,66 +139,85 @@ func NewTransferManager(ctx context.Context, maxConcurrentJobs int, store StoreI
 func (tm *TransferManager) recoverJobs() {
 	log.Info("Starting job recovery from database...")
 
-	var recoveredCount int
-	for _, status := range []string{StatusPending, StatusRunning} {
-		jobs, statusTotal, err := tm.store.ListJobs(status, 1000, 0)
-		if err != nil {
-			log.Warnf("Failed to get %s jobs: %v", status, err)
-			continue
-		}
-
-		if statusTotal == 0 {
-			continue
-		}
+	// Returned in submission order, which the recovered jobs keep
+	storedJobs, err := tm.store.GetRecoverableJobs()
+	if err != nil {
+		log.Warnf("Failed to get incomplete jobs: %v", err)
+		return
+	}
+	if len(storedJobs) == 0 {
+		log.Info("No jobs to recover")
+		return
+	}
 
-		log.Infof("Found %d incomplete jobs with status %s", statusTotal, status)
+	log.Infof("Found %d incomplete jobs", len(storedJobs))
 
-		// Now we have concrete typed []*StoredJob (from same package)
-		for _, storedJob := range jobs {
-			tm.recoverSingleJob(storedJob.ID)
-			recoveredCount++
+	// Load every job before starting any, so that dependencies between
+	// recovered jobs can be resolved
+	recovered := make([]*TransferJob, 0, len(storedJobs))
+	for _, storedJob := range storedJobs {
+		if job := tm.recoverSingleJob(storedJob.ID); job != nil {
+			recovered = append(recovered, job)
 		}
 	}
 
-	if recoveredCount == 0 {
-		log.Info("No jobs to recover")
-	} else {
-		log.Infof("Job recovery complete: restarted %d incomplete jobs", recoveredCount)
+	for _, job := range recovered {
+		tm.mu.Lock()
+		deps, err := tm.resolveDependencies(job.Schedule.DependsOn)
+		job.dependencies = deps
+		tm.mu.Unlock()
+
+		job.wg.Add(1)
+		tm.eg.Go(func() error {
+			if err != nil {
+				defer job.wg.Done()
+				defer close(job.done)
+				tm.failJobBeforeStart(job, err)
+				return nil
+			}
+			tm.executeJob(job)
+			return nil
+		})
 	}
+
+	log.Infof("Job recovery complete: restarted %d incomplete jobs", len(recovered))
 }
 
-// recoverSingleJob restarts a single interrupted job
-func (tm *TransferManager) recoverSingleJob(jobID string) {
+// recoverSingleJob reloads a single interrupted job so that it can be
+// restarted; returns nil if the job cannot be recovered
+func (tm *TransferManager) recoverSingleJob(jobID string) *TransferJob {
 	log.Infof("Recovering and restarting incomplete job %s", jobID)
 
 	// Get the job from the database
 	storedJob, err := tm.store.GetJob(jobID)
 	if err != nil {
 		log.Warnf("Failed to get job %s for recovery: %v", jobID, err)
-		return
+		return nil
 	}
 
 	// Get transfers for this job
 	storedTransfers, err := tm.store.GetTransfersByJob(jobID)
 	if err != nil {
 		log.Warnf("Failed to get transfers for recovered job %s: %v", jobID, err)
-		return
+		return nil
 	}
 
 	// Convert transfers to TransferRequest format
 	var requests []TransferRequest
 	for _, st := range storedTransfers {
 		requests = append(requests, TransferRequest{
-			Operation:   st.Operation,
-			Source:      st.Source,
-			Destination: st.Destination,
-			Recursive:   st.Recursive,
+			Operation:        st.Operation,
+			Source:           st.Source,
+			Destination:      st.Destination,
+			Recursive:        st.Recursive,
+			ExpectedSize:     st.ExpectedSize,
+			ExpectedChecksum: st.ExpectedChecksum,
 		})
 	}
 
 	if len(requests) == 0 {
 		log.Warnf("No valid transfers found for recovered job %s", jobID)
-		return
+		return nil
 	}
 
 	// Create in-memory job structure
@@ -197,47 +226,60 @@ func (tm *TransferManager) recoverSingleJob(jobID string) {
 	createdAt := time.Now()
 
 	job := &TransferJob{
-		ID:         jobID, // PRESERVE the original job ID
-		Status:     StatusPending,
-		CreatedAt:  createdAt,
-		Transfers:  make([]*Transfer, 0, len(requests)),
-		Options:    nil, // Options are not persisted, so we can't recover them
+		ID:        jobID, // PRESERVE the original job ID
+		Status:    StatusPending,
+		CreatedAt: createdAt,
+		Transfers: make([]*Transfer, 0, len(requests)),
+		Options:   nil, // Options are not persisted, so we can't recover them
+		Schedule: JobSchedule{
+			Priority:  storedJob.Schedule.Priority,
+			NotBefore: storedJob.Schedule.NotBefore,
+			DependsOn: storedJob.Schedule.DependsOn,
+		},
 		CancelFunc: jobCancel,
 		ctx:        jobCtx,
+		done:       make(chan struct{}),
 	}
 
 	// Prepare transfer data for atomic recovery
 	transferData := make([]map[string]interface{}, 0, len(requests))
 	tm.mu.Lock()
+	// Recovered jobs are renumbered in their original order
+	job.sequence = tm.nextSequence
+	tm.nextSequence++
 	for _, req := range requests {
 		transferID := uuid.New().String()
 		transferCtx, transferCancel := context.WithCancel(jobCtx)
 
 		transfer := &Transfer{
-			ID:          transferID,
-			JobID:       jobID,
-			Operation:   req.Operation,
-			Source:      req.Source,
-			Destination: req.Destination,
-			Recursive:   req.Recursive,
-			Status:      StatusPending,
-			CreatedAt:   createdAt,
-			CancelFunc:  transferCancel,
-			ctx:         transferCtx,
+			ID:               transferID,
+			JobID:            jobID,
+			Operation:        req.Operation,
+			Source:           req.Source,
+			Destination:      req.Destination,
+			Recursive:        req.Recursive,
+			Status:           StatusPending,
+			ExpectedSize:     req.ExpectedSize,
+			ExpectedChecksum: req.ExpectedChecksum,
+			CreatedAt:        createdAt,
+			CancelFunc:       transferCancel,
+			ctx:              transferCtx,
 		}
 
 		job.Transfers = append(job.Transfers, transfer)
 		tm.transfers[transferID] = transfer
 
 		transferData = append(transferData, map[string]interface{}{
-			"ID":          transferID,
-			"JobID":       jobID,
-			"Operation":   req.Operation,
-			"Source":      req.Source,
-			"Destination": req.Destination,
-			"Recursive":   req.Recursive,
-			"Status":      StatusPending,
-			"CreatedAt":   createdAt.Unix(),
+			"ID":               transferID,
+			"JobID":            jobID,
+			"Operation":        req.Operation,
+			"Source":           req.Source,
+			"Destination":      req.Destination,
+			"Recursive":        req.Recursive,
+			"ExpectedSize":     req.ExpectedSize,
+			"ExpectedChecksum": req.ExpectedChecksum,
+			"Status":           StatusPending,
+			"CreatedAt":        createdAt.Unix(),
 		})
 	}
 
@@ -247,7 +289,7 @@ func (tm *TransferManager) recoverSingleJob(jobID string) {
 	// Use atomic RecoverJob transaction - deletes old job and creates new one with transfers
 	// All operations succeed or all fail (atomic)
 	optionsJSON := "{}"
-	if err := tm.store.RecoverJob(jobID, newRetryCount, createdAt, optionsJSON, transferData); err != nil {
+	if err := tm.store.RecoverJob(jobID, newRetryCount, createdAt, optionsJSON, storedSchedule(job), transferData); err != nil {
 		log.Errorf("Failed to atomically recover job %s in database: %v", jobID, err)
 		// Clean up in-memory structures on failure
 		tm.mu.Lock()
@@ -256,36 +298,47 @@ func (tm *TransferManager) recoverSingleJob(jobID string) {
 			delete(tm.transfers, t.ID)
 		}
 		tm.mu.Unlock()
-		return
+		return nil
 	}
 
-	log.Infof("Job %s recovered and restarted with %d transfers (retry attempt %d)", jobID, len(requests), newRetryCount)
-
-	// Start the job asynchronously
-	job.wg.Add(1)
-	tm.eg.Go(func() error {
-		tm.executeJob(job)
-		return nil
-	})
+	log.Infof("Job %s recovered with %d transfers (retry attempt %d)", jobID, len(requests), newRetryCount)
+	return job
 }
 
-// CreateJob creates a new transfer job
+// CreateJob creates a new transfer job that runs as soon as a slot is free
 func (tm *TransferManager) CreateJob(requests []TransferRequest, options []client.TransferOption) (*TransferJob, error) {
+	return tm.CreateScheduledJob(requests, options, JobSchedule{})
+}
+
+// CreateScheduledJob creates a new transfer job subject to the given
+// scheduling constraints.  Returns an error wrapping ErrInvalidSchedule if a
+// dependency is unknown or has already failed.
+func (tm *TransferManager) CreateScheduledJob(requests []TransferRequest, options []client.TransferOption, schedule JobSchedule) (*TransferJob, error) {
 	tm.mu.Lock()
 	defer tm.mu.Unlock()
 
+	deps, err := tm.resolveDependencies(schedule.DependsOn)
+	if err != nil {
+		return nil, err
+	}
+
 	jobID := uuid.New().String()
 	jobCtx, jobCancel := context.WithCancel(tm.ctx)
 
 	job := &TransferJob{
-		ID:         jobID,
-		Status:     StatusPending,
-		CreatedAt:  time.Now(),
-		Transfers:  make([]*Transfer, 0, len(requests)),
-		Options:    options,
-		CancelFunc: jobCancel,
-		ctx:        jobCtx,
+		ID:           jobID,
+		Status:       StatusPending,
+		CreatedAt:    time.Now(),
+		Transfers:    make([]*Transfer, 0, len(requests)),
+		Options:      options,
+		Schedule:     schedule,
+		CancelFunc:   jobCancel,
+		ctx:          jobCtx,
+		sequence:     tm.nextSequence,
+		dependencies: deps,
+		done:         make(chan struct{}),
 	}
+	tm.nextSequence++
 
 	tm.jobs[jobID] = job
 
@@ -296,16 +349,18 @@ func (tm *TransferManager) CreateJob(requests []TransferRequest, options []clien
 		transferCtx, transferCancel := context.WithCancel(jobCtx)
 
 		transfer := &Transfer{
-			ID:          transferID,
-			JobID:       jobID,
-			Operation:   req.Operation,
-			Source:      req.Source,
-			Destination: req.Destination,
-			Recursive:   req.Recursive,
-			Status:      StatusPending,
-			CreatedAt:   time.Now(),
-			CancelFunc:  transferCancel,
-			ctx:         transferCtx,
+			ID:               transferID,
+			JobID:            jobID,
+			Operation:        req.Operation,
+			Source:           req.Source,
+			Destination:      req.Destination,
+			Recursive:        req.Recursive,
+			Status:           StatusPending,
+			ExpectedSize:     req.ExpectedSize,
+			ExpectedChecksum: req.ExpectedChecksum,
+			CreatedAt:        time.Now(),
+			CancelFunc:       transferCancel,
+			ctx:              transferCtx,
 		}
 
 		job.Transfers = append(job.Transfers, transfer)
@@ -313,21 +368,23 @@ func (tm *TransferManager) CreateJob(requests []TransferRequest, options []clien
 
 		// Prepare transfer data for atomic database insertion
 		transferData = append(transferData, map[string]interface{}{
-			"ID":          transferID,
-			"JobID":       jobID,
-			"Operation":   req.Operation,
-			"Source":      req.Source,
-			"Destination": req.Destination,
-			"Recursive":   req.Recursive,
-			"Status":      StatusPending,
-			"CreatedAt":   transfer.CreatedAt.Unix(),
+			"ID":               transferID,
+			"JobID":            jobID,
+			"Operation":        req.Operation,
+			"Source":           req.Source,
+			"Destination":      req.Destination,
+			"Recursive":        req.Recursive,
+			"ExpectedSize":     req.ExpectedSize,
+			"ExpectedChecksum": req.ExpectedChecksum,
+			"Status":           StatusPending,
+			"CreatedAt":        transfer.CreatedAt.Unix(),
 		})
 	}
 
 	// Atomically persist job and all transfers to database in a single transaction
 	if tm.store != nil {
 		optionsJSON := "{}"
-		if err := tm.store.CreateJobWithTransfers(jobID, StatusPending, job.CreatedAt, optionsJSON, 0, transferData); err != nil {
+		if err := tm.store.CreateJobWithTransfers(jobID, StatusPending, job.CreatedAt, optionsJSON, 0, storedSchedule(job), transferData); err != nil {
 			log.Errorf("Failed to persist job %s to database: %v", jobID, err)
 			// Clean up in-memory structures on database failure
 			delete(tm.jobs, jobID)
@@ -349,18 +406,26 @@ func (tm *TransferManager) CreateJob(requests []TransferRequest, options []clien
 	return job, nil
 }
 
-// executeJob runs all transfers in a job
+// executeJob runs all transfers in a job once its schedule allows
 func (tm *TransferManager) executeJob(job *TransferJob) {
 	defer job.wg.Done() // Signal job completion
+	defer close(job.done)
 
-	// Acquire semaphore slot
-	select {
-	case tm.semaphore <- struct{}{}:
-		defer func() { <-tm.semaphore }()
-	case <-job.ctx.Done():
+	if err := tm.waitForSchedule(job); err != nil {
+		if job.ctx.Err() != nil {
+			tm.updateJobStatus(job.ID, StatusCancelled, errors.New("job cancelled before execution"))
+		} else {
+			tm.failJobBeforeStart(job, err)
+		}
+		return
+	}
+
+	// Wait for an execution slot; higher priority jobs are served first
+	if err := tm.queue.acquire(job.ctx, job); err != nil {
 		tm.updateJobStatus(job.ID, StatusCancelled, errors.New("job cancelled before execution"))
 		return
 	}
+	defer tm.queue.release()
 
 	// Update job status
 	now := time.Now()
@@ -379,6 +444,8 @@ func (tm *TransferManager) executeJob(job *TransferJob) {
 		}
 	}
 
+	tm.publishJobStatus(job)
+
 	log.Infof("Starting job %s with %d transfers", job.ID, len(job.Transfers))
 
 	// Execute transfers sequentially (could be parallelized in future)
@@ -428,6 +495,8 @@ func (tm *TransferManager) executeJob(job *TransferJob) {
 		}
 	}
 
+	tm.publishJobStatus(job)
+
 	log.Infof("Job %s completed with status %s", job.ID, job.Status)
 }
 
@@ -449,13 +518,29 @@ func (tm *TransferManager) executeTransfer(transfer *Transfer, options []client.
 		}
 	}
 
+	tm.publishTransferStatus(transfer, StatusRunning, nil, nil)
+
 	log.Debugf("Executing transfer %s: %s %s -> %s", transfer.ID, transfer.Operation, transfer.Source, transfer.Destination)
 
 	// Add progress callback to update transfer state during execution
+	var lastProgressEvent atomic.Int64
 	progressCallback := func(path string, downloaded int64, totalSize int64, completed bool) {
 		transfer.BytesTransferred.Store(downloaded)
 		transfer.TotalBytes.Store(totalSize)
 
+		// Throttle the progress events pushed to job subscribers
+		if now := time.Now().UnixNano(); completed || now-lastProgressEvent.Load() >= int64(progressEventInterval) {
+			lastProgressEvent.Store(now)
+			tm.events.publish(JobEvent{
+				Type:             EventTransferProgress,
+				JobID:            transfer.JobID,
+				TransferID:       transfer.ID,
+				Status:           StatusRunning,
+				BytesTransferred: downloaded,
+				TotalBytes:       totalSize,
+			})
+		}
+
 		log.Debugf("Transfer %s progress: %d/%d bytes (%.1f%%)",
 			transfer.ID, downloaded, totalSize,
 			float64(downloaded)/float64(totalSize)*100)
@@ -463,8 +548,17 @@ func (tm *TransferManager) executeTransfer(transfer *Transfer, options []client.
 
 	// Prepend callback to options so it's applied first
 	options = append([]client.TransferOption{client.WithCallback(progressCallback)}, options...)
+	expectedOptions, err := client.ManifestEntry{
+		Source:   transfer.Source,
+		Size:     transfer.ExpectedSize,
+		Checksum: transfer.ExpectedChecksum,
+	}.Options()
+	if err != nil {
+		// Validated when the job was created
+		log.Warnf("Ignoring invalid expected checksum of transfer %s: %v", transfer.ID, err)
+	}
+	options = append(options, expectedOptions...)
 
-	var err error
 	var results []client.TransferResults
 
 	// Execute the appropriate transfer operation
@@ -504,6 +598,8 @@ func (tm *TransferManager) executeTransfer(transfer *Transfer, options []client.
 			}
 		}
 
+		tm.publishTransferStatus(transfer, StatusFailed, results, err)
+
 		log.Errorf("Transfer %s failed: %v", transfer.ID, err)
 		return err
 	}
@@ -531,6 +627,8 @@ func (tm *TransferManager) executeTransfer(transfer *Transfer, options []client.
 		}
 	}
 
+	tm.publishTransferStatus(transfer, StatusCompleted, results, nil)
+
 	log.Debugf("Transfer %s completed successfully: %d bytes", transfer.ID, totalBytes)
 	return nil
 }
@@ -547,6 +645,7 @@ func (tm *TransferManager) cancelRemainingTransfers(job *TransferJob) {
 				now := time.Now()
 				transfer.CompletedAt = &now
 			}
+			tm.publishTransferStatus(transfer, StatusCancelled, nil, nil)
 		}
 	}
 }
@@ -554,18 +653,22 @@ func (tm *TransferManager) cancelRemainingTransfers(job *TransferJob) {
 // updateJobStatus updates a job's status
 func (tm *TransferManager) updateJobStatus(jobID, status string, err error) {
 	tm.mu.Lock()
-	defer tm.mu.Unlock()
-
-	if job, exists := tm.jobs[jobID]; exists {
+	job, exists := tm.jobs[jobID]
+	if exists {
 		job.Status = status
 		if err != nil {
 			job.Error = err
 		}
-		if job.CompletedAt == nil && (status == StatusCompleted || status == StatusFailed || status == StatusCancelled) {
+		if job.CompletedAt == nil && IsTerminalStatus(status) {
 			now := time.Now()
 			job.CompletedAt = &now
 		}
 	}
+	tm.mu.Unlock()
+
+	if exists {
+		tm.publishJobStatus(job)
+	}
 }
 
 // GetJob retrieves a job by ID
@@ -628,6 +731,9 @@ func (tm *TransferManager) CancelJob(jobID string) (int, int, error) {
 		return 0, 0, errors.Errorf("timeout waiting for job %s to cancel after 30 seconds", jobID)
 	}
 
+	// Deferred first so that it runs after the lock is released
+	defer tm.publishJobStatus(job)
+
 	tm.mu.Lock()
 	defer tm.mu.Unlock()
 
@@ -645,6 +751,7 @@ func (tm *TransferManager) CancelJob(jobID string) (int, int, error) {
 					now := time.Now()
 					transfer.CompletedAt = &now
 				}
+				tm.publishTransferStatus(transfer, StatusCancelled, nil, nil)
 
 				// Persist cancellation to database
 				if tm.store != nil {
@@ -793,6 +900,52 @@ func (tm *TransferManager) GetJobProgress(job *TransferJob) *JobProgress {
 	}
 }
 
+// GetJobStatus builds the full status of a job, including all its transfers
+func (tm *TransferManager) GetJobStatus(job *TransferJob) JobStatus {
+	progress := tm.GetJobProgress(job)
+
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+
+	transfers := make([]TransferStatus, len(job.Transfers))
+	for i, transfer := range job.Transfers {
+		status := TransferStatus{
+			TransferID:       transfer.ID,
+			JobID:            transfer.JobID,
+			Operation:        transfer.Operation,
+			Source:           transfer.Source,
+			Destination:      transfer.Destination,
+			Status:           transfer.Status,
+			CreatedAt:        transfer.CreatedAt,
+			StartedAt:        transfer.StartedAt,
+			CompletedAt:      transfer.CompletedAt,
+			BytesTransferred: transfer.BytesTransferred.Load(),
+			TotalBytes:       transfer.TotalBytes.Load(),
+			ExpectedSize:     transfer.ExpectedSize,
+			ExpectedChecksum: transfer.ExpectedChecksum,
+		}
+		if transfer.Error != nil {
+			status.Error = transfer.Error.Error()
+		}
+		transfers[i] = status
+	}
+
+	jobStatus := JobStatus{
+		JobID:       job.ID,
+		Status:      job.Status,
+		CreatedAt:   job.CreatedAt,
+		StartedAt:   job.StartedAt,
+		CompletedAt: job.CompletedAt,
+		Progress:    progress,
+		Transfers:   transfers,
+		JobSchedule: job.Schedule,
+	}
+	if job.Error != nil {
+		jobStatus.Error = job.Error.Error()
+	}
+	return jobStatus
+}
+
 // startBackgroundTasks starts periodic background maintenance tasks
 func (tm *TransferManager) startBackgroundTasks() {
 	if tm.store == nil {
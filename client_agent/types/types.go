@@ -31,6 +31,15 @@ type StoredJob struct {
 	Options      map[string]interface{} // JSON-decoded options
 	ErrorMessage string
 	RetryCount   int // Number of times this job has been retried
+	Schedule     JobSchedule
+}
+
+// JobSchedule holds the scheduling constraints of a job
+type JobSchedule struct {
+	Priority  int        // Higher priority jobs start first
+	NotBefore *time.Time // The job does not start before this time, if set
+	DependsOn []string   // IDs of jobs that must complete successfully before this job starts
+	Sequence  int64      // Submission order, used to break ties between jobs of equal priority
 }
 
 // StoredTransfer represents a transfer stored in the database
@@ -42,6 +51,8 @@ type StoredTransfer struct {
 	Source           string
 	Destination      string
 	Recursive        bool
+	ExpectedSize     *int64 // Size the transferred object must have, if set
+	ExpectedChecksum string // Checksum the transferred object must have, as "<algorithm>:<hex value>"
 	Status           string
 	CreatedAt        int64
 	StartedAt        *time.Time
@@ -144,7 +144,9 @@ Examples:
 
 Shows total inline bytes stored in BadgerDB, total number of metadata
 entries, total bytes claimed by metadata, and per-storage-directory
-breakdown. This is a cheap operation that only scans metadata.
+breakdown. When objects are stored compressed, also shows how many are
+compressed, their content and on-disk sizes, and the compression ratio.
+This is a cheap operation that only scans metadata.
 
 Example:
   pelican cache introspect stats
@@ -912,6 +914,14 @@ func printStats(stats *local_cache.CacheStats) error {
 		w.Flush()
 	}
 
+	if c := stats.Compression; c != nil {
+		fmt.Printf("\nCompression:\n")
+		fmt.Printf("  Compressed objects:  %d\n", c.Objects)
+		fmt.Printf("  Content bytes:       %s (%d bytes)\n", utils.HumanBytes(c.ContentBytes), c.ContentBytes)
+		fmt.Printf("  Stored bytes:        %s (%d bytes)\n", utils.HumanBytes(c.StoredBytes), c.StoredBytes)
+		fmt.Printf("  Ratio:               %.2fx\n", c.Ratio)
+	}
+
 	if len(stats.UsageCounters) > 0 {
 		// Build reverse lookups for human-readable labels.
 		dirName := func(sid uint8) string {
@@ -980,6 +990,25 @@ func printStats(stats *local_cache.CacheStats) error {
 		}
 	}
 
+	if len(stats.EvictionPolicies) > 0 {
+		names := make([]string, 0, len(stats.EvictionPolicies))
+		for name := range stats.EvictionPolicies {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		fmt.Printf("\nEviction Policies:\n")
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(w, "  POLICY\tHITS\tMISSES\tHIT RATIO\tEVICTED\n")
+		for _, name := range names {
+			ps := stats.EvictionPolicies[name]
+			fmt.Fprintf(w, "  %s\t%d\t%d\t%.1f%%\t%d (%s)\n",
+				name, ps.Hits, ps.Misses, 100*ps.HitRatio(),
+				ps.EvictedObjects, utils.HumanBytes(ps.EvictedBytes))
+		}
+		w.Flush()
+	}
+
 	return nil
 }
 
@@ -29,6 +29,8 @@ import (
 
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
 	"github.com/pelicanplatform/pelican/client_agent"
 	"github.com/pelicanplatform/pelican/client_agent/apiclient"
@@ -139,3 +141,30 @@ func ensureClientAgentRunning(ctx context.Context, maxRetries int) (*apiclient.A
 
 	return nil, errors.Errorf("failed to connect to server after %d attempts", maxRetries)
 }
+
+// addJobScheduleFlags adds the flags controlling when an --async job runs
+func addJobScheduleFlags(flagSet *pflag.FlagSet) {
+	flagSet.Int("priority", 0, "When used with --async, the job's priority; pending jobs with a higher priority start first")
+	flagSet.String("not-before", "", "When used with --async, do not start the job before this time (RFC 3339, e.g. 2026-01-02T15:04:05Z) or duration from now (e.g. 30m)")
+	flagSet.StringSlice("depends-on", nil, "When used with --async, only start the job once the given job IDs have completed successfully")
+}
+
+// getJobSchedule builds the schedule of an --async job from the flags added
+// by addJobScheduleFlags
+func getJobSchedule(cmd *cobra.Command) (client_agent.JobSchedule, error) {
+	var schedule client_agent.JobSchedule
+	schedule.Priority, _ = cmd.Flags().GetInt("priority")
+	schedule.DependsOn, _ = cmd.Flags().GetStringSlice("depends-on")
+
+	if notBefore, _ := cmd.Flags().GetString("not-before"); notBefore != "" {
+		if delay, err := time.ParseDuration(notBefore); err == nil {
+			start := time.Now().Add(delay)
+			schedule.NotBefore = &start
+		} else if start, err := time.Parse(time.RFC3339, notBefore); err == nil {
+			schedule.NotBefore = &start
+		} else {
+			return schedule, errors.Errorf("invalid --not-before value %q: must be an RFC 3339 time or a duration", notBefore)
+		}
+	}
+	return schedule, nil
+}
@@ -28,7 +28,7 @@ var (
 	downtimeCmd = &cobra.Command{
 		Use:   "downtime",
 		Short: "Manage server's own downtime periods",
-		Long: `Provide commands to list, create, update, and delete scheduled downtime periods
+		Long: `Provide commands to list, create, update, delete, and import scheduled downtime periods
 for Pelican servers (Origins/Caches). These commands interact with the server's
 administrative API endpoint.`,
 	}
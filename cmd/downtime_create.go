@@ -162,6 +162,27 @@ func createDowntime(cmd *cobra.Command, args []string) error {
 		endTimeMilli = endTime.UnixMilli()
 	}
 
+	// Prompt for Recurrence
+	var recurrence string
+	for {
+		fmt.Print("Enter a recurrence rule to repeat this downtime, e.g. 'FREQ=WEEKLY;BYDAY=TU' (or leave blank for a one-time downtime): ")
+		recurrenceInput, err := reader.ReadString('\n')
+		if err != nil {
+			return errors.Wrap(err, "failed to read recurrence rule")
+		}
+		recurrence = strings.TrimSpace(recurrenceInput)
+		if recurrence == "" {
+			break
+		}
+		rule, err := server_structs.ParseRecurrence(recurrence)
+		if err != nil {
+			fmt.Printf("Invalid recurrence rule: %v. Please try again.\n", err)
+			continue
+		}
+		recurrence = rule.String()
+		break
+	}
+
 	// Build downtime payload
 	downtimePayload := server_structs.Downtime{
 		Class:       server_structs.Class(downtimeClass),
@@ -169,8 +190,12 @@ func createDowntime(cmd *cobra.Command, args []string) error {
 		Severity:    server_structs.Severity(downtimeSeverity),
 		StartTime:   startTime.UnixMilli(),
 		EndTime:     endTimeMilli,
+		Recurrence:  recurrence,
 		// The server will set CreatedBy to "admin", based on the token
 	}
+	if err := downtimePayload.ValidateRecurrence(); err != nil {
+		return errors.Wrap(err, "Invalid recurring downtime")
+	}
 
 	payloadBytes, err := json.Marshal(downtimePayload)
 	if err != nil {
@@ -0,0 +1,215 @@
+//go:build server
+
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/pelicanplatform/pelican/config"
+	"github.com/pelicanplatform/pelican/server_structs"
+	"github.com/pelicanplatform/pelican/web_ui"
+)
+
+var downtimeImportCmd = &cobra.Command{
+	Use:   "import <file.ics | URL | ->",
+	Short: "Import downtime periods from an iCalendar file",
+	Long: `Read the events of an iCalendar (.ics) file, an http(s) URL serving one, or
+standard input ("-"), and create a downtime period on the server for each.
+
+Each event is tracked by its UID, so importing an updated calendar again updates
+the downtimes created by the previous import instead of duplicating them, and
+events marked STATUS:CANCELLED remove their downtime.  Events without an end
+time become indefinite downtimes, and recurring events keep their RRULE.
+Calendars exported from a Pelican server's /api/v1.0/downtimes endpoint keep
+their downtime class and severity; other events are imported as SCHEDULED
+outages.`,
+	Args: cobra.ExactArgs(1),
+	RunE: importDowntime,
+}
+
+func init() {
+	downtimeImportCmd.Flags().Bool("dry-run", false, "Print the downtimes that would be imported without changing the server")
+	downtimeCmd.AddCommand(downtimeImportCmd)
+}
+
+// readCalendar reads a calendar from a file, an http(s) URL or stdin
+func readCalendar(ctx context.Context, location string) ([]byte, error) {
+	if location == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	if !strings.HasPrefix(location, "https://") && !strings.HasPrefix(location, "http://") {
+		return os.ReadFile(location)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create HTTP request")
+	}
+	req.Header.Set("Accept", "text/calendar")
+	req.Header.Set("User-Agent", "pelican-client/"+config.GetVersion())
+	httpClient := &http.Client{Transport: config.GetTransport()}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "HTTP request failed")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("server responded with %s", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func describeImportedDowntime(dt server_structs.ImportedDowntime) string {
+	window := time.UnixMilli(dt.StartTime).UTC().Format(time.RFC3339) + " - "
+	if dt.EndTime == server_structs.IndefiniteEndTime {
+		window += "indefinite"
+	} else {
+		window += time.UnixMilli(dt.EndTime).UTC().Format(time.RFC3339)
+	}
+	if dt.Recurrence != "" {
+		window += ", repeating " + dt.Recurrence
+	}
+	return fmt.Sprintf("%s (%s): %s", dt.UUID, window, dt.Description)
+}
+
+func importDowntime(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	if err := config.InitClient(); err != nil {
+		log.Errorln("Failed to initialize client:", err)
+	}
+
+	apiURL, err := constructDowntimeApiURL(serverURLStr)
+	if err != nil {
+		return err
+	}
+
+	calendar, err := readCalendar(ctx, args[0])
+	if err != nil {
+		return errors.Wrapf(err, "Failed to read calendar %s", args[0])
+	}
+	downtimes, err := server_structs.ParseDowntimesICal(bytes.NewReader(calendar))
+	if err != nil {
+		return errors.Wrapf(err, "Failed to parse calendar %s", args[0])
+	}
+	if len(downtimes) == 0 {
+		fmt.Println("No events found in the calendar.")
+		return nil
+	}
+
+	if dryRun {
+		for _, dt := range downtimes {
+			if dt.Cancelled {
+				fmt.Println("Would delete", dt.UUID)
+			} else {
+				fmt.Println("Would import", describeImportedDowntime(dt))
+			}
+		}
+		return nil
+	}
+
+	tok, err := fetchOrGenerateWebAPIAdminToken(serverURLStr, tokenLocation)
+	if err != nil {
+		return err
+	}
+
+	httpClient := &http.Client{Transport: config.GetTransport()}
+	send := func(method string, dt server_structs.ImportedDowntime, body io.Reader) (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, method, apiURL.String()+"/"+dt.UUID, body)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to create HTTP request")
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+tok)
+		req.AddCookie(&http.Cookie{Name: "login", Value: tok})
+		req.Header.Set("Accept", "application/json")
+		req.Header.Set("User-Agent", "pelican-client/"+config.GetVersion())
+		return httpClient.Do(req)
+	}
+
+	imported, deleted := 0, 0
+	for _, dt := range downtimes {
+		if dt.Cancelled {
+			resp, err := send(http.MethodDelete, dt, nil)
+			if err != nil {
+				return errors.Wrap(err, "HTTP request failed")
+			}
+			_, err = handleAdminApiResponse(resp)
+			resp.Body.Close()
+			// The downtime may never have been imported, or already deleted
+			if err != nil && resp.StatusCode != http.StatusNotFound {
+				return errors.Wrapf(err, "Failed to delete downtime %s for cancelled event %s", dt.UUID, dt.UID)
+			}
+			if err == nil {
+				fmt.Println("Deleted", dt.UUID)
+				deleted++
+			}
+			continue
+		}
+
+		// The server creates the downtime with the given UUID, or updates it if
+		// it was imported before
+		recurrence := dt.Recurrence
+		payload, err := json.Marshal(web_ui.DowntimeInput{
+			ServerName:  dt.ServerName,
+			ServerID:    dt.ServerID,
+			Class:       dt.Class,
+			Description: dt.Description,
+			Severity:    dt.Severity,
+			StartTime:   dt.StartTime,
+			EndTime:     dt.EndTime,
+			Recurrence:  &recurrence,
+		})
+		if err != nil {
+			return errors.Wrap(err, "Failed to marshal downtime payload")
+		}
+		resp, err := send(http.MethodPost, dt, bytes.NewReader(payload))
+		if err != nil {
+			return errors.Wrap(err, "HTTP request failed")
+		}
+		_, err = handleAdminApiResponse(resp)
+		resp.Body.Close()
+		if err != nil {
+			return errors.Wrapf(err, "Failed to import event %s", dt.UID)
+		}
+		fmt.Println("Imported", describeImportedDowntime(dt))
+		imported++
+	}
+
+	fmt.Printf("Imported %d downtime(s) and deleted %d from %s\n", imported, deleted, args[0])
+	return nil
+}
@@ -43,6 +43,9 @@ type downtimeDisplay struct {
 	server_structs.Downtime
 	StartTimeStr string `json:"start_time_str" yaml:"start_time_str"`
 	EndTimeStr   string `json:"end_time_str" yaml:"end_time_str"`
+	// The current or next occurrence of a recurring downtime
+	NextStartTimeStr string `json:"next_start_time_str,omitempty" yaml:"next_start_time_str,omitempty"`
+	NextEndTimeStr   string `json:"next_end_time_str,omitempty" yaml:"next_end_time_str,omitempty"`
 }
 
 var (
@@ -51,7 +54,8 @@ var (
 		Short: "List server's scheduled downtime periods",
 		Long: `List scheduled downtime periods for a Pelican server (Origin/Cache).
   Requires an administrative token for the server.
-  Shows active and future downtimes ('incomplete') by default.`,
+  Shows active and future downtimes ('incomplete') by default.
+  Recurring downtimes also show the window of their current or next occurrence.`,
 		Args:    cobra.NoArgs,
 		RunE:    listDowntime,
 		Aliases: []string{"ls"},
@@ -170,6 +174,7 @@ func printDowntimes(downtimes []server_structs.Downtime, format string) error {
 	})
 
 	// Prepare display data (format times)
+	now := time.Now().UTC().UnixMilli()
 	displayData := make([]downtimeDisplay, len(downtimes))
 	for i, dt := range downtimes {
 		// Convert Unix Milliseconds to human-readable time in UTC
@@ -182,6 +187,12 @@ func printDowntimes(downtimes []server_structs.Downtime, format string) error {
 		} else {
 			displayData[i].EndTimeStr = "Indefinite"
 		}
+		if dt.IsRecurring() {
+			if nextStart, nextEnd, ok := dt.CurrentWindow(now); ok {
+				displayData[i].NextStartTimeStr = time.UnixMilli(nextStart).UTC().Format(time.RFC3339)
+				displayData[i].NextEndTimeStr = time.UnixMilli(nextEnd).UTC().Format(time.RFC3339)
+			}
+		}
 		displayData[i].Downtime = dt
 	}
 
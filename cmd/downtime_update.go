@@ -184,6 +184,32 @@ func updateDowntime(cmd *cobra.Command, args []string) error {
 		updatePayload.EndTime = endTimeMilli
 	}
 
+	// Prompt for new Recurrence
+	for {
+		fmt.Print("Enter new recurrence rule, e.g. 'FREQ=WEEKLY;BYDAY=TU', or 'none' to make the downtime one-time (or leave blank to keep unchanged): ")
+		recurrenceInput, err := reader.ReadString('\n')
+		if err != nil {
+			return errors.Wrap(err, "failed to read recurrence rule")
+		}
+		recurrenceInput = strings.TrimSpace(recurrenceInput)
+		if recurrenceInput == "" {
+			break // leave unchanged
+		}
+		if strings.EqualFold(recurrenceInput, "none") {
+			recurrence := ""
+			updatePayload.Recurrence = &recurrence
+			break
+		}
+		rule, err := server_structs.ParseRecurrence(recurrenceInput)
+		if err != nil {
+			fmt.Printf("Invalid recurrence rule: %v. Please try again.\n", err)
+			continue
+		}
+		recurrence := rule.String()
+		updatePayload.Recurrence = &recurrence
+		break
+	}
+
 	// Marshal update payload.
 	payloadBytes, err := json.Marshal(updatePayload)
 	if err != nil {
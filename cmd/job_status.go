@@ -24,6 +24,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -46,11 +47,14 @@ the status of all transfers within the job and overall progress.`,
 		RunE:         jobStatusMain,
 	}
 
-	jobStatusWatch bool
+	jobStatusWatch  bool
+	jobStatusFollow bool
 )
 
 func init() {
 	jobStatusCmd.Flags().BoolVarP(&jobStatusWatch, "watch", "w", false, "Watch job status until completion")
+	jobStatusCmd.Flags().BoolVar(&jobStatusFollow, "follow", false, "Stream transfer progress and state changes as they happen until the job completes")
+	jobStatusCmd.MarkFlagsMutuallyExclusive("watch", "follow")
 	jobCmd.AddCommand(jobStatusCmd)
 }
 
@@ -69,6 +73,9 @@ func jobStatusMain(cmd *cobra.Command, args []string) error {
 		return errors.Wrap(err, "failed to connect to client agent server")
 	}
 
+	if jobStatusFollow {
+		return followJobStatus(ctx, apiClient, jobID)
+	}
 	if jobStatusWatch {
 		return watchJobStatus(ctx, apiClient, jobID)
 	}
@@ -122,11 +129,83 @@ func watchJobStatus(ctx context.Context, apiClient *apiclient.APIClient, jobID s
 	}
 }
 
+func followJobStatus(ctx context.Context, apiClient *apiclient.APIClient, jobID string) error {
+	err := apiClient.StreamJobEvents(ctx, jobID, func(event client_agent.JobEvent) error {
+		if outputJSON {
+			jsonBytes, err := json.Marshal(event)
+			if err != nil {
+				return errors.Wrap(err, "failed to marshal JSON")
+			}
+			fmt.Println(string(jsonBytes))
+			return nil
+		}
+		printJobEvent(event)
+		return nil
+	})
+	if errors.Is(err, apiclient.ErrEventsNotSupported) {
+		// Older servers only support polling
+		return watchJobStatus(ctx, apiClient, jobID)
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to follow job status")
+	}
+	return nil
+}
+
+func printJobEvent(event client_agent.JobEvent) {
+	timestamp := event.Timestamp.Format(time.TimeOnly)
+	switch event.Type {
+	case client_agent.EventJobStatus:
+		if client_agent.IsTerminalStatus(event.Status) && event.Job != nil {
+			fmt.Println()
+			printJobStatus(event.Job)
+			return
+		}
+		fmt.Printf("%s job %s: %s\n", timestamp, event.JobID, event.Status)
+	case client_agent.EventTransferStatus:
+		fmt.Printf("%s [%s] %s", timestamp, event.Status, event.TransferID)
+		if event.Error != "" {
+			fmt.Printf(": %s", event.Error)
+		}
+		fmt.Println()
+		for _, result := range event.Results {
+			if result.Error != "" {
+				continue
+			}
+			fmt.Printf("    %s: %s", result.Source, utils.HumanBytes(result.TransferredBytes))
+			if result.Endpoint != "" {
+				fmt.Printf(" via %s", result.Endpoint)
+			}
+			fmt.Println()
+		}
+	case client_agent.EventTransferProgress:
+		if event.TotalBytes > 0 {
+			fmt.Printf("%s %s: %s / %s (%.1f%%)\n", timestamp, event.TransferID,
+				utils.HumanBytes(event.BytesTransferred), utils.HumanBytes(event.TotalBytes),
+				float64(event.BytesTransferred)/float64(event.TotalBytes)*100)
+		} else {
+			fmt.Printf("%s %s: %s\n", timestamp, event.TransferID, utils.HumanBytes(event.BytesTransferred))
+		}
+	}
+}
+
 func printJobStatus(status *client_agent.JobStatus) {
 	fmt.Printf("Job ID: %s\n", status.JobID)
 	fmt.Printf("Status: %s\n", status.Status)
 	fmt.Printf("Created: %s\n", status.CreatedAt.Format(time.RFC3339))
 
+	if status.Priority != 0 {
+		fmt.Printf("Priority: %d\n", status.Priority)
+	}
+
+	if status.NotBefore != nil {
+		fmt.Printf("Not Before: %s\n", status.NotBefore.Format(time.RFC3339))
+	}
+
+	if len(status.DependsOn) > 0 {
+		fmt.Printf("Depends On: %s\n", strings.Join(status.DependsOn, ", "))
+	}
+
 	if status.StartedAt != nil {
 		fmt.Printf("Started: %s\n", status.StartedAt.Format(time.RFC3339))
 	}
@@ -41,13 +41,34 @@ is a JSON Web Key Set (JWKS), which can be used for JWT signature verification.`
 		RunE:         keygenMain,
 		SilenceUsage: true,
 	}
+
+	keyRotateCmd = &cobra.Command{
+		Use:   "rotate",
+		Short: "Rotate the issuer key of a Pelican server",
+		Long: `Start a rotation of the server's issuer key. A new key is generated in
+IssuerKeysDirectory and published alongside the active key for
+Server.IssuerKeyRotation.PublishPeriod. The running server then switches to
+signing with the new key, keeps publishing the previous key for
+Server.IssuerKeyRotation.OverlapPeriod so tokens it already issued stay
+valid, and finally moves the previous key to the "retired" subdirectory.
+
+The server carries out the rotation; run this command on the host of the
+server with the same configuration. Use --status to check on the progress
+of a rotation.`,
+		Args:         cobra.NoArgs,
+		RunE:         keyRotateMain,
+		SilenceUsage: true,
+	}
 )
 
 func init() {
 	rootCmd.AddCommand(keyCmd)
 	keyCmd.AddCommand(keyCreateCmd)
+	keyCmd.AddCommand(keyRotateCmd)
 
 	// Attach flags to the `create` sub-command
 	keyCreateCmd.Flags().StringVar(&privateKeyPath, "private-key", "./private-key.pem", "The file path where the generated private key will be saved. If a key already exists at the provided path, it will not be overwritten but will be used to derive a public key")
 	keyCreateCmd.Flags().StringVar(&publicKeyPath, "public-key", "./issuer-pub.jwks", "The file path where the generated public key (derived from the generated private key) will be saved.")
+
+	keyRotateCmd.Flags().Bool("status", false, "Show the state of the issuer key rotation instead of starting one")
 }
@@ -0,0 +1,87 @@
+//go:build server
+
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/pelicanplatform/pelican/config"
+	"github.com/pelicanplatform/pelican/server_structs"
+)
+
+func printKeyRotationState(out io.Writer, state config.KeyRotationState) error {
+	if outputJSON {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(state)
+	}
+
+	fmt.Fprintf(out, "Phase: %s\n", state.Phase)
+	if state.SigningKeyID != "" {
+		fmt.Fprintf(out, "Signing key: %s\n", state.SigningKeyID)
+	}
+	switch state.Phase {
+	case config.KeyRotationPublishing:
+		fmt.Fprintf(out, "Publishing new key %s alongside %s since %s\n", state.NewKeyID, state.OldKeyID, state.StartedAt.Format(time.RFC3339))
+		fmt.Fprintf(out, "Switching to the new key after %s\n", state.SwitchAt.Format(time.RFC3339))
+	case config.KeyRotationOverlap:
+		fmt.Fprintf(out, "Signing with new key %s; previous key %s stays published until %s\n", state.NewKeyID, state.OldKeyID, state.RetireAt.Format(time.RFC3339))
+	}
+	if !state.LastRotation.IsZero() {
+		fmt.Fprintf(out, "Last rotation completed: %s\n", state.LastRotation.Format(time.RFC3339))
+	}
+	return nil
+}
+
+func runKeyRotate(out io.Writer, statusOnly bool) error {
+	if statusOnly {
+		state, err := config.GetKeyRotationState()
+		if err != nil {
+			return err
+		}
+		return printKeyRotationState(out, state)
+	}
+
+	state, err := config.StartIssuerKeyRotation(time.Now())
+	if err != nil {
+		return errors.Wrap(err, "failed to start the issuer key rotation")
+	}
+	return printKeyRotationState(out, state)
+}
+
+func keyRotateMain(cmd *cobra.Command, args []string) error {
+	statusOnly, err := cmd.Flags().GetBool("status")
+	if err != nil {
+		return errors.Wrap(err, "failed to get the value of the --status flag")
+	}
+
+	// The rotation operates on the server's IssuerKeysDirectory, so load the server configuration
+	if err = config.InitServer(cmd.Context(), server_structs.OriginType); err != nil {
+		return errors.Wrap(err, "failed to initialize server configuration")
+	}
+	return runKeyRotate(cmd.OutOrStdout(), statusOnly)
+}
@@ -0,0 +1,65 @@
+//go:build server
+
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pelicanplatform/pelican/config"
+	"github.com/pelicanplatform/pelican/param"
+)
+
+func TestKeyRotate(t *testing.T) {
+	tmpDir := setupTestRun(t)
+	require.NoError(t, param.IssuerKeysDirectory.Set(filepath.Join(tmpDir, "issuer-keys")))
+	activeKey, err := config.GetIssuerPrivateJWK()
+	require.NoError(t, err)
+
+	out := &bytes.Buffer{}
+	require.NoError(t, runKeyRotate(out, true))
+	assert.Equal(t, "Phase: idle\n", out.String())
+
+	out.Reset()
+	require.NoError(t, runKeyRotate(out, false))
+	assert.Contains(t, out.String(), "Phase: publishing\n")
+	assert.Contains(t, out.String(), "Signing key: "+activeKey.KeyID())
+
+	out.Reset()
+	err = runKeyRotate(out, false)
+	assert.ErrorContains(t, err, "already in progress")
+
+	origOutputJSON := outputJSON
+	outputJSON = true
+	t.Cleanup(func() { outputJSON = origOutputJSON })
+	out.Reset()
+	require.NoError(t, runKeyRotate(out, true))
+	state := config.KeyRotationState{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &state))
+	assert.Equal(t, config.KeyRotationPublishing, state.Phase)
+	assert.Equal(t, activeKey.KeyID(), state.OldKeyID)
+	assert.NotEmpty(t, state.NewKeyID)
+}
@@ -23,8 +23,10 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
@@ -44,7 +46,12 @@ var (
 	copyCmd = &cobra.Command{
 		Use:   "copy {source ...} {destination}",
 		Short: "Copy a file to/from a Pelican federation",
-		Run:   copyMain,
+		Long: `Copy objects between the local filesystem and a Pelican federation, or between two
+remote locations.  When the origins holding both ends of a remote-to-remote copy
+support HTTP third-party copies, the destination origin fetches the object directly
+from the source origin; otherwise, the object is streamed through the client
+without being written to local disk.`,
+		Run: copyMain,
 		PreRun: func(cmd *cobra.Command, args []string) {
 			commaFlagsListToViperSlice(cmd, map[string]string{"cache": param.Client_PreferredCaches.GetName()})
 		},
@@ -81,11 +88,14 @@ the client should fallback to discovered caches if all preferred caches fail.`)
 		flagSet.BoolP("version", "v", false, "Print the version and exit")
 		flagSet.Bool("async", false, "Run the transfer asynchronously through the client API server and return a job ID")
 		flagSet.Bool("wait", false, "When used with --async, wait for the job to complete before returning")
+		addJobScheduleFlags(flagSet)
 	} else {
 		flagSet.String("caches", "", "A JSON file containing the list of caches")
 		flagSet.String("methods", "http", "Comma separated list of methods to try, in order")
 		flagSet.Bool("async", false, "Run the transfer asynchronously through the client API server and return a job ID")
 		flagSet.Bool("wait", false, "When used with --async, wait for the job to complete before returning")
+		addJobScheduleFlags(flagSet)
+		addManifestFlags(flagSet)
 		objectCmd.AddCommand(copyCmd)
 	}
 }
@@ -119,6 +129,8 @@ func copyMain(cmd *cobra.Command, args []string) {
 		os.Exit(0)
 	}
 
+	manifestMain(cmd, client.ManifestCopy, args)
+
 	// Check for async mode
 	isAsync, _ := cmd.Flags().GetBool("async")
 	if isAsync {
@@ -176,8 +188,14 @@ func copyMain(cmd *cobra.Command, args []string) {
 			}
 		}
 
+		schedule, err := getJobSchedule(cmd)
+		if err != nil {
+			log.Errorln(err)
+			os.Exit(1)
+		}
+
 		// Create job
-		jobID, err := apiClient.CreateJob(ctx, transfers, options)
+		jobID, err := apiClient.CreateScheduledJob(ctx, transfers, options, schedule)
 		if err != nil {
 			log.Errorln("Failed to create job:", err)
 			os.Exit(1)
@@ -281,7 +299,17 @@ func copyMain(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	if len(source) > 1 {
+	isRemote := func(location string) bool {
+		parsed, err := url.Parse(location)
+		return err == nil && parsed.Scheme != "" && parsed.Scheme != "file"
+	}
+	if len(source) > 1 && isRemote(dest) && !slices.ContainsFunc(source, func(src string) bool { return !isRemote(src) }) {
+		// Copies between remote objects are placed in the destination collection
+		if !strings.HasSuffix(dest, "/") {
+			log.Errorln("The remote destination of several sources must be a collection ending in \"/\"")
+			os.Exit(1)
+		}
+	} else if len(source) > 1 {
 		if destStat, err := os.Stat(dest); err != nil {
 			log.Errorln("Destination does not exist")
 			os.Exit(1)
@@ -63,8 +63,11 @@ the client should fallback to discovered caches if all preferred caches fail.`)
 	flagSet.String("transfer-stats", "", "A path to a file to write transfer statistics to")
 	flagSet.String("pack", "", "Package transfer using remote packing functionality (same as '?pack=' query). Options: auto, tar, tar.gz, tar.xz, zip. Default: auto when flag is provided without an explicit value")
 	flagSet.Bool("direct", false, "Download directly from an origin, bypassing any caches (same as '?directread' query)")
+	flagSet.Int("stripes", 0, "Download large objects in byte ranges fetched concurrently from up to this many caches (overrides Client.StripedDownloadSources; 0 or 1 disables striping)")
 	flagSet.Bool("async", false, "Run the transfer asynchronously through the client API server and return a job ID")
 	flagSet.Bool("wait", false, "When used with --async, wait for the job to complete before returning")
+	addJobScheduleFlags(flagSet)
+	addManifestFlags(flagSet)
 	objectCmd.AddCommand(getCmd)
 }
 
@@ -83,6 +86,8 @@ func getMain(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	manifestMain(cmd, client.ManifestGet, args)
+
 	// Check for async mode
 	isAsync, _ := cmd.Flags().GetBool("async")
 	if isAsync {
@@ -138,8 +143,14 @@ func getMain(cmd *cobra.Command, args []string) {
 			}
 		}
 
+		schedule, err := getJobSchedule(cmd)
+		if err != nil {
+			log.Errorln(err)
+			os.Exit(1)
+		}
+
 		// Create job
-		jobID, err := apiClient.CreateJob(ctx, transfers, options)
+		jobID, err := apiClient.CreateScheduledJob(ctx, transfers, options, schedule)
 		if err != nil {
 			log.Errorln("Failed to create job:", err)
 			os.Exit(1)
@@ -302,6 +313,10 @@ func getMain(cmd *cobra.Command, args []string) {
 			client.WithInPlace(inPlace),
 			client.WithDryRun(dryRun),
 		}
+		if cmd.Flags().Changed("stripes") {
+			stripes, _ := cmd.Flags().GetInt("stripes")
+			options = append(options, client.WithStripedDownload(stripes))
+		}
 		transferResults, err := client.DoGet(ctx, src, dest, isRecursive, options...)
 		if err != nil {
 			attemptErr = err
@@ -0,0 +1,215 @@
+//go:build client
+
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/pelicanplatform/pelican/client"
+	"github.com/pelicanplatform/pelican/client_agent"
+	"github.com/pelicanplatform/pelican/param"
+)
+
+// addManifestFlags adds the flags for driving a transfer from a manifest
+func addManifestFlags(flagSet *pflag.FlagSet) {
+	flagSet.String("manifest", "", `A JSON Lines or CSV file ("-" for stdin) listing the objects to transfer, each with a source,
+destination, and optionally the size and checksum it is expected to have`)
+	flagSet.String("result-manifest", "-", `Where to write the JSON Lines results of a --manifest transfer ("-" for stdout)`)
+}
+
+// Apply the positional destination to the manifest entries without one.  A
+// destination ending in "/" is a collection the entry's source is placed in.
+func applyManifestDestination(entries []client.ManifestEntry, destination string) {
+	for idx := range entries {
+		if entries[idx].Destination != "" {
+			continue
+		}
+		entries[idx].Destination = destination
+		if strings.HasSuffix(destination, "/") {
+			entries[idx].Destination += path.Base(entries[idx].Source)
+		}
+	}
+}
+
+// manifestMain runs the transfer of a --manifest, if one was given, and
+// exits.  Returns if the command has no manifest.
+func manifestMain(cmd *cobra.Command, operation string, args []string) {
+	if manifestPath, _ := cmd.Flags().GetString("manifest"); manifestPath != "" {
+		os.Exit(runManifest(cmd, operation, manifestPath, args))
+	}
+}
+
+// Transfer the entries of a manifest, returning the exit code of the command
+func runManifest(cmd *cobra.Command, operation string, manifestPath string, args []string) int {
+	if len(args) > 1 {
+		log.Errorln("A --manifest transfer takes at most one argument, the destination of the entries without one")
+		return 1
+	}
+	if recursive, _ := cmd.Flags().GetBool("recursive"); recursive {
+		log.Errorln("The --manifest and --recursive flags cannot be used together")
+		return 1
+	}
+	entries, err := client.ReadManifest(manifestPath)
+	if err != nil {
+		log.Errorln("Failed to read manifest:", err)
+		return 1
+	}
+	if len(args) == 1 {
+		applyManifestDestination(entries, args[0])
+	}
+
+	resultPath, _ := cmd.Flags().GetString("result-manifest")
+	var resultWriter io.Writer = os.Stdout
+	if resultPath != "-" {
+		resultFile, err := os.Create(resultPath)
+		if err != nil {
+			log.Errorln("Failed to create result manifest:", err)
+			return 1
+		}
+		defer resultFile.Close()
+		resultWriter = resultFile
+	}
+	encoder := json.NewEncoder(resultWriter)
+	failed := 0
+	report := func(result client.ManifestResult) {
+		if !result.Success {
+			failed++
+			log.Errorf("Failure transferring %s: %s", result.Source, result.Error)
+		}
+		if err := encoder.Encode(result); err != nil {
+			log.Errorln("Failed to write result manifest:", err)
+		}
+	}
+
+	tokenLocation, _ := cmd.Flags().GetString("token")
+	caches, err := getPreferredCaches()
+	if err != nil {
+		log.Errorln("Failed to get preferred caches:", err)
+		return 1
+	}
+
+	if isAsync, _ := cmd.Flags().GetBool("async"); isAsync {
+		err = runAsyncManifest(cmd, operation, entries, tokenLocation, caches, report)
+	} else {
+		pb := newProgressBar()
+		defer pb.shutdown()
+		if fileInfo, _ := os.Stdout.Stat(); resultPath != "-" && (fileInfo.Mode()&os.ModeCharDevice) != 0 &&
+			param.Logging_LogLocation.GetString() == "" && !param.Logging_DisableProgressBars.GetBool() {
+			pb.launchDisplay(cmd.Context())
+		}
+		options := []client.TransferOption{
+			client.WithCallback(pb.callback),
+			client.WithTokenLocation(tokenLocation),
+			client.WithCaches(caches...),
+		}
+		if cmd.Flags().Changed("stripes") {
+			stripes, _ := cmd.Flags().GetInt("stripes")
+			options = append(options, client.WithStripedDownload(stripes))
+		}
+		err = client.DoManifest(cmd.Context(), operation, entries, report, options...)
+	}
+	if err != nil {
+		log.Errorln("Failed to run the manifest:", err)
+		return 1
+	}
+	if failed > 0 {
+		log.Errorf("%d of the %d manifest entries failed", failed, len(entries))
+		return 1
+	}
+	return 0
+}
+
+// Submit the manifest as a single job of the client agent.  With --wait, the
+// results are reported once the job completes.
+func runAsyncManifest(cmd *cobra.Command, operation string, entries []client.ManifestEntry, tokenLocation string, caches []*url.URL, report func(client.ManifestResult)) error {
+	ctx := cmd.Context()
+	apiClient, err := ensureClientAgentRunning(ctx, 5)
+	if err != nil {
+		log.Errorln("You can manually start it with 'pelican client-api serve --daemonize'")
+		return err
+	}
+
+	cacheStrings := make([]string, len(caches))
+	for i, cache := range caches {
+		cacheStrings[i] = cache.String()
+	}
+	transfers := make([]client_agent.TransferRequest, len(entries))
+	for i, entry := range entries {
+		transfers[i] = client_agent.TransferRequest{
+			Operation:        operation,
+			Source:           entry.Source,
+			Destination:      entry.Destination,
+			ExpectedSize:     entry.Size,
+			ExpectedChecksum: entry.Checksum,
+		}
+	}
+	schedule, err := getJobSchedule(cmd)
+	if err != nil {
+		return err
+	}
+	jobID, err := apiClient.CreateScheduledJob(ctx, transfers, client_agent.TransferOptions{Token: tokenLocation, Caches: cacheStrings}, schedule)
+	if err != nil {
+		return err
+	}
+	if shouldWait, _ := cmd.Flags().GetBool("wait"); !shouldWait {
+		fmt.Fprintf(os.Stderr, "Job created: %s\nCheck status with: pelican job status %s\n", jobID, jobID)
+		return nil
+	}
+
+	fmt.Fprintf(os.Stderr, "Job created: %s; waiting for it to complete...\n", jobID)
+	if err := apiClient.WaitForJob(ctx, jobID, 1*time.Hour); err != nil {
+		log.Warningln("Job did not complete successfully:", err)
+	}
+	status, err := apiClient.GetJobStatus(ctx, jobID)
+	if err != nil {
+		return err
+	}
+	for _, transfer := range status.Transfers {
+		result := client.ManifestResult{
+			Source:           transfer.Source,
+			Destination:      transfer.Destination,
+			Success:          transfer.Status == client_agent.StatusCompleted,
+			TransferredBytes: transfer.BytesTransferred,
+			ExpectedSize:     transfer.ExpectedSize,
+			ExpectedChecksum: transfer.ExpectedChecksum,
+			Error:            transfer.Error,
+		}
+		if result.Success {
+			// The agent only completes transfers matching their expected checksum
+			result.Checksum = transfer.ExpectedChecksum
+		} else if result.Error == "" {
+			result.Error = "transfer " + transfer.Status
+		}
+		report(result)
+	}
+	return nil
+}
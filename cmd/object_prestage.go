@@ -56,6 +56,8 @@ the client should fallback to discovered caches if all preferred caches fail.`)
 	flagSet.StringP("token", "t", "", "Token file to use for transfer")
 	flagSet.Bool("async", false, "Run the prestage asynchronously through the client API server and return a job ID")
 	flagSet.Bool("wait", false, "When used with --async, wait for the job to complete before returning")
+	addJobScheduleFlags(flagSet)
+	addManifestFlags(flagSet)
 	objectCmd.AddCommand(prestageCmd)
 }
 
@@ -74,6 +76,8 @@ func prestageMain(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	manifestMain(cmd, client.ManifestPrestage, args)
+
 	// Check for async mode
 	isAsync, _ := cmd.Flags().GetBool("async")
 	if isAsync {
@@ -132,8 +136,14 @@ func prestageMain(cmd *cobra.Command, args []string) {
 			}
 		}
 
+		schedule, err := getJobSchedule(cmd)
+		if err != nil {
+			log.Errorln(err)
+			os.Exit(1)
+		}
+
 		// Create job
-		jobID, err := apiClient.CreateJob(ctx, transfers, options)
+		jobID, err := apiClient.CreateScheduledJob(ctx, transfers, options, schedule)
 		if err != nil {
 			log.Errorln("Failed to create job:", err)
 			os.Exit(1)
@@ -24,10 +24,12 @@ import (
 	"bufio"
 	"crypto/md5"
 	"crypto/sha1"
+	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"hash"
+	"hash/adler32"
 	"hash/crc32"
 	"io"
 	"os"
@@ -64,6 +66,8 @@ func init() {
 	flagSet.String("pack", "", "Package transfer using remote packing functionality (same as '?pack=' query). Options: auto, tar, tar.gz, tar.xz, zip. Default: auto when flag is provided without an explicit value")
 	flagSet.Bool("async", false, "Run the transfer asynchronously through the client API server and return a job ID")
 	flagSet.Bool("wait", false, "When used with --async, wait for the job to complete before returning")
+	addJobScheduleFlags(flagSet)
+	addManifestFlags(flagSet)
 	objectCmd.AddCommand(putCmd)
 }
 
@@ -114,6 +118,10 @@ func verifyFileChecksum(filePath, expectedChecksum string, alg client.ChecksumTy
 	case client.AlgCRC32C:
 		crc32cTable := crc32.MakeTable(crc32.Castagnoli)
 		h = crc32.New(crc32cTable)
+	case client.AlgSHA256:
+		h = sha256.New()
+	case client.AlgAdler32:
+		h = adler32.New()
 	default:
 		return errors.Errorf("unsupported checksum algorithm: %v", alg)
 	}
@@ -145,6 +153,8 @@ func putMain(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	manifestMain(cmd, client.ManifestPut, args)
+
 	// Check for async mode
 	isAsync, _ := cmd.Flags().GetBool("async")
 	if isAsync {
@@ -189,8 +199,14 @@ func putMain(cmd *cobra.Command, args []string) {
 			}
 		}
 
+		schedule, err := getJobSchedule(cmd)
+		if err != nil {
+			log.Errorln(err)
+			os.Exit(1)
+		}
+
 		// Create job
-		jobID, err := apiClient.CreateJob(ctx, transfers, options)
+		jobID, err := apiClient.CreateScheduledJob(ctx, transfers, options, schedule)
 		if err != nil {
 			log.Errorln("Failed to create job:", err)
 			os.Exit(1)
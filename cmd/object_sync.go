@@ -21,9 +21,11 @@
 package main
 
 import (
+	"fmt"
 	"net/url"
 	"os"
 	"strings"
+	"sync"
 
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
@@ -53,6 +55,10 @@ the client should fallback to discovered caches if all preferred caches fail.`)
 	flagSet.StringP("token", "t", "", "Token file to use for transfer")
 	flagSet.Bool("inplace", false, "Write files directly to destination (default: use temporary files)")
 	flagSet.Bool("dry-run", false, "Show what would be synchronized without actually modifying the destination")
+	flagSet.String("compare", client.SyncSize.String(), `How to decide whether an object at the destination is up to date: "exist", "size", "mtime"
+(same size and at least as new as the source), "checksum" (same size and checksum), or "none" to always transfer`)
+	flagSet.Bool("delete", false, "Delete objects at the destination that are not present at the source")
+	flagSet.BoolP("itemize", "i", false, "Print an itemized list of the objects transferred and deleted")
 	objectCmd.AddCommand(syncCmd)
 }
 
@@ -88,6 +94,41 @@ func isPelicanUrl(input string) bool {
 	return true
 }
 
+// Tallies the itemized changes of a synchronization
+type syncReport struct {
+	mu        sync.Mutex
+	transfers int
+	unchanged int
+	deletes   int
+}
+
+// Returns a client.SyncReportFunc recording each change, and printing the
+// transfers and deletions to stdout if itemize is set.
+func (sr *syncReport) record(itemize bool) client.SyncReportFunc {
+	return func(change client.SyncChange) {
+		sr.mu.Lock()
+		defer sr.mu.Unlock()
+		switch change.Action {
+		case client.SyncActionTransfer:
+			sr.transfers++
+		case client.SyncActionSkip:
+			sr.unchanged++
+			return
+		case client.SyncActionDelete:
+			sr.deletes++
+		}
+		if itemize {
+			fmt.Printf("%-8s %-8s %s\n", change.Action, change.Reason, change.Path)
+		}
+	}
+}
+
+func (sr *syncReport) summary() string {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+	return fmt.Sprintf("Synchronization: %d objects transferred, %d unchanged, %d deleted", sr.transfers, sr.unchanged, sr.deletes)
+}
+
 func syncMain(cmd *cobra.Command, args []string) {
 	ctx := cmd.Context()
 
@@ -170,6 +211,21 @@ func syncMain(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	compare, _ := cmd.Flags().GetString("compare")
+	syncLevel, err := client.ParseSyncLevel(compare)
+	if err != nil {
+		log.Errorln(err)
+		os.Exit(1)
+	}
+	syncDelete, _ := cmd.Flags().GetBool("delete")
+	if syncDelete && len(sources) > 1 {
+		// Each source would delete the objects synchronized from the others
+		log.Errorln("The --delete flag may only be used with a single source")
+		os.Exit(1)
+	}
+	itemize, _ := cmd.Flags().GetBool("itemize")
+	report := &syncReport{}
+
 	lastSrc := ""
 
 	dryRun, _ := cmd.Flags().GetBool("dry-run")
@@ -179,7 +235,9 @@ func syncMain(cmd *cobra.Command, args []string) {
 			options := []client.TransferOption{
 				client.WithCallback(pb.callback),
 				client.WithTokenLocation(tokenLocation),
-				client.WithSynchronize(client.SyncSize),
+				client.WithSynchronize(syncLevel),
+				client.WithSyncDelete(syncDelete),
+				client.WithSyncReport(report.record(itemize)),
 				client.WithCaches(caches...),
 				client.WithInPlace(inPlace),
 				client.WithDryRun(dryRun),
@@ -201,7 +259,9 @@ func syncMain(cmd *cobra.Command, args []string) {
 			options := []client.TransferOption{
 				client.WithCallback(pb.callback),
 				client.WithTokenLocation(tokenLocation),
-				client.WithSynchronize(client.SyncSize),
+				client.WithSynchronize(syncLevel),
+				client.WithSyncDelete(syncDelete),
+				client.WithSyncReport(report.record(itemize)),
 				client.WithCaches(caches...),
 				client.WithDryRun(dryRun),
 			}
@@ -212,6 +272,8 @@ func syncMain(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	log.Infoln(report.summary())
+
 	// Exit with failure
 	if err != nil {
 		if handleCredentialPasswordError(err) {
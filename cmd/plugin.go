@@ -863,6 +863,14 @@ func writeTransferErrorMessage(currentError string, transferUrl string) (errMsg
 	return
 }
 
+// checksumAttrName returns the ClassAd attribute name of a checksum algorithm
+// in the ClientChecksums and ServerChecksums ads.  This is its HTTP digest
+// name, less any dashes, which aren't valid in an attribute name (e.g. sha256
+// for the sha-256 digest).
+func checksumAttrName(alg client.ChecksumType) string {
+	return strings.ReplaceAll(client.HttpDigestFromChecksum(alg), "-", "")
+}
+
 // createTransferError creates a transfer error map with developer data
 func createTransferError(err error) (transferError *classad.ClassAd) {
 	transferError = classad.New()
@@ -1030,7 +1038,7 @@ func addDataToClassAd(resultAd *classad.ClassAd, result *client.TransferResults,
 	if len(clientChecksums) > 0 {
 		clientChecksumsData := classad.New()
 		for _, checksum := range clientChecksums {
-			adErr = clientChecksumsData.Set(client.HttpDigestFromChecksum(checksum.Algorithm), hex.EncodeToString(checksum.Value))
+			adErr = clientChecksumsData.Set(checksumAttrName(checksum.Algorithm), hex.EncodeToString(checksum.Value))
 			if adErr != nil {
 				log.Errorf("Failed to set ClientChecksums: %s", adErr)
 			}
@@ -1043,7 +1051,7 @@ func addDataToClassAd(resultAd *classad.ClassAd, result *client.TransferResults,
 	if len(serverChecksums) > 0 {
 		serverChecksumsData := classad.New()
 		for _, checksum := range serverChecksums {
-			adErr = serverChecksumsData.Set(client.HttpDigestFromChecksum(checksum.Algorithm), hex.EncodeToString(checksum.Value))
+			adErr = serverChecksumsData.Set(checksumAttrName(checksum.Algorithm), hex.EncodeToString(checksum.Value))
 			if adErr != nil {
 				log.Errorf("Failed to set ServerChecksums: %s", adErr)
 			}
@@ -1065,6 +1065,34 @@ func TestAddDataToClassAdDirectorDecision(t *testing.T) {
 	})
 }
 
+// Test that every checksum algorithm is reported under a valid attribute name
+func TestAddDataToClassAdChecksums(t *testing.T) {
+	t.Cleanup(test_utils.SetupTestLogging(t))
+
+	resultAd := classad.New()
+	var checksums []client.ChecksumInfo
+	for _, alg := range client.KnownChecksumTypes() {
+		checksums = append(checksums, client.ChecksumInfo{Algorithm: alg, Value: []byte{0x12, 0x34, 0x56, 0x78}})
+	}
+	addDataToClassAd(resultAd, &client.TransferResults{}, nil, 0, checksums, checksums)
+
+	devData, ok := classad.GetAs[*classad.ClassAd](resultAd, "DeveloperData")
+	require.True(t, ok)
+	for _, attr := range []string{"ClientChecksums", "ServerChecksums"} {
+		checksumAd, ok := classad.GetAs[*classad.ClassAd](devData, attr)
+		require.True(t, ok, attr)
+		for _, name := range []string{"md5", "sha", "sha256", "crc32", "crc32c", "adler32"} {
+			value, ok := classad.GetAs[string](checksumAd, name)
+			require.True(t, ok, "%s should have a %s checksum", attr, name)
+			assert.Equal(t, "12345678", value)
+		}
+	}
+
+	// The ad must survive a round trip through its text form
+	_, err := classad.Parse(resultAd.String())
+	require.NoError(t, err)
+}
+
 // Test that DirectorDecision appears in the transfer ad DeveloperData when
 // Plugin.DirectorDecisionPercentage is set to 100 during an actual transfer.
 func TestPluginDirectorDecision(t *testing.T) {
@@ -0,0 +1,505 @@
+//go:build server
+
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/pelicanplatform/pelican/config"
+	"github.com/pelicanplatform/pelican/server_structs"
+)
+
+const (
+	// The API path of the registry web UI
+	registryUIAPIPath = "/api/v1.0/registry_ui"
+
+	registryAdminAuthHelp = `
+  Requests are authenticated with the token in the --token file, e.g. the
+  login token of a registry web UI user.  Without one, an admin token is
+  created with the local issuer key, which only works on the registry host.`
+)
+
+var (
+	registryAdminsCmd = &cobra.Command{
+		Use:   "admins",
+		Short: "Manage the co-admins of a namespace registration",
+		Long: `List, add and remove the users who, along with its owner, manage a
+namespace registration in the registry.  Namespaces are given by their
+registry ID or their prefix.` + registryAdminAuthHelp,
+	}
+
+	registryAdminsListCmd = &cobra.Command{
+		Use:     "list <namespace>",
+		Short:   "List the co-admins of a namespace registration",
+		Args:    cobra.ExactArgs(1),
+		RunE:    listRegistrationAdmins,
+		Aliases: []string{"ls"},
+	}
+
+	registryAdminsAddCmd = &cobra.Command{
+		Use:   "add <namespace> <user>",
+		Short: "Add a co-admin to a namespace registration",
+		Long:  "Add a co-admin to a namespace registration.  Only the owner of the registration and registry admins can add co-admins.",
+		Args:  cobra.ExactArgs(2),
+		RunE:  addRegistrationAdmin,
+	}
+
+	registryAdminsRemoveCmd = &cobra.Command{
+		Use:   "remove <namespace> <user>",
+		Short: "Remove a co-admin from a namespace registration",
+		Args:  cobra.ExactArgs(2),
+		RunE:  removeRegistrationAdmin,
+	}
+
+	registryTransferCmd = &cobra.Command{
+		Use:   "transfer",
+		Short: "Transfer the ownership of a namespace registration",
+		Long: `Request, list, approve and deny transfers of the ownership of namespace
+registrations.  The owner and co-admins of a registration can request its
+transfer to any user, and any user can request a registration to be
+transferred to themselves.  The transfer takes effect once a registry admin
+approves it.` + registryAdminAuthHelp,
+	}
+
+	registryTransferRequestCmd = &cobra.Command{
+		Use:   "request <namespace> <new-owner>",
+		Short: "Request the transfer of a namespace registration to a new owner",
+		Args:  cobra.ExactArgs(2),
+		RunE:  requestRegistrationTransfer,
+	}
+
+	registryTransferListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List ownership transfers",
+		Long: `List the ownership transfers of the namespace given by --namespace, or
+of all namespaces for registry admins.`,
+		Args:    cobra.NoArgs,
+		RunE:    listRegistrationTransfers,
+		Aliases: []string{"ls"},
+	}
+
+	registryTransferApproveCmd = &cobra.Command{
+		Use:   "approve <transfer-id>",
+		Short: "Approve a pending ownership transfer (registry admins only)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return decideRegistrationTransfer(cmd, args[0], "approve")
+		},
+	}
+
+	registryTransferDenyCmd = &cobra.Command{
+		Use:   "deny <transfer-id>",
+		Short: "Deny a pending ownership transfer (registry admins only)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return decideRegistrationTransfer(cmd, args[0], "deny")
+		},
+	}
+
+	registryAuditCmd = &cobra.Command{
+		Use:   "audit",
+		Short: "Show who changed which namespace registrations",
+		Long: `Show the audit log of the namespace given by --namespace, or of all
+namespaces for registry admins, newest entries first.` + registryAdminAuthHelp,
+		Args: cobra.NoArgs,
+		RunE: listRegistryAudit,
+	}
+
+	registryAdminServerURLStr  string
+	registryAdminTokenLocation string
+)
+
+func init() {
+	for _, cmd := range []*cobra.Command{registryAdminsCmd, registryTransferCmd, registryAuditCmd, registrySearchCmd} {
+		cmd.PersistentFlags().StringVarP(&registryAdminServerURLStr, "server", "s", "", "Web URL of the Pelican registry (e.g. https://my-registry.com:8447)")
+		cmd.PersistentFlags().StringVarP(&registryAdminTokenLocation, "token", "t", "", "Path to the token file")
+		registryCmd.AddCommand(cmd)
+	}
+
+	registryAdminsCmd.AddCommand(registryAdminsListCmd)
+	registryAdminsCmd.AddCommand(registryAdminsAddCmd)
+	registryAdminsCmd.AddCommand(registryAdminsRemoveCmd)
+
+	registryTransferListCmd.Flags().String("namespace", "", "Only list the transfers of this namespace (ID or prefix)")
+	registryTransferListCmd.Flags().String("status", "", "Only list transfers with this status ('Pending', 'Approved' or 'Denied')")
+	registryTransferCmd.AddCommand(registryTransferRequestCmd)
+	registryTransferCmd.AddCommand(registryTransferListCmd)
+	registryTransferCmd.AddCommand(registryTransferApproveCmd)
+	registryTransferCmd.AddCommand(registryTransferDenyCmd)
+
+	registryAuditCmd.Flags().String("namespace", "", "Only show the audit log of this namespace (ID or prefix)")
+	registryAuditCmd.Flags().String("user", "", "Only show changes made by this user")
+	registryAuditCmd.Flags().String("prefix", "", "Only show changes of this prefix, including deleted registrations (registry admins only)")
+	registryAuditCmd.Flags().Int("limit", 0, "The maximum number of entries to show (the registry defaults to 100)")
+}
+
+// registryUIClient sends requests to the web UI API of a registry
+type registryUIClient struct {
+	ctx     context.Context
+	baseURL *url.URL
+	token   string
+	client  *http.Client
+}
+
+// Helper function to validate the server URL and construct the registry web UI API URL
+func constructRegistryUIApiURL(serverURLStr string) (*url.URL, error) {
+	if serverURLStr == "" {
+		return nil, errors.New("The --server flag providing the registry's web URL is required")
+	}
+	serverURLStr = strings.TrimSuffix(serverURLStr, "/") // Normalize URL
+	baseURL, err := url.Parse(serverURLStr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Invalid server URL format: %s", serverURLStr)
+	}
+	// A Pelican server must use HTTPS scheme
+	if baseURL.Scheme != "https" {
+		return nil, errors.Errorf("Server URL must have an https scheme: %s", serverURLStr)
+	}
+	if baseURL.Host == "" {
+		return nil, errors.Errorf("Server URL must include a hostname: %s", serverURLStr)
+	}
+	targetURL, err := baseURL.Parse(registryUIAPIPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to construct registry API URL")
+	}
+	return targetURL, nil
+}
+
+// newAnonymousRegistryUIClient returns a client that doesn't authenticate its
+// requests, so the registry treats them as coming from a logged-out user
+func newAnonymousRegistryUIClient(cmd *cobra.Command) (*registryUIClient, error) {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if err := config.InitClient(); err != nil {
+		log.Errorln("Failed to initialize client:", err)
+	}
+
+	baseURL, err := constructRegistryUIApiURL(registryAdminServerURLStr)
+	if err != nil {
+		return nil, err
+	}
+	return &registryUIClient{
+		ctx:     ctx,
+		baseURL: baseURL,
+		client:  &http.Client{Transport: config.GetTransport()},
+	}, nil
+}
+
+func newRegistryUIClient(cmd *cobra.Command) (*registryUIClient, error) {
+	client, err := newAnonymousRegistryUIClient(cmd)
+	if err != nil {
+		return nil, err
+	}
+	client.token, err = fetchOrGenerateWebAPIAdminToken(registryAdminServerURLStr, registryAdminTokenLocation)
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// do sends a request to path, relative to the registry web UI API, and
+// decodes the JSON response into out unless it is nil.
+//
+// Only the bearer token is sent, without a login cookie, so the registry
+// doesn't require a CSRF token for the request.
+func (c *registryUIClient) do(method, path string, query url.Values, body any, out any) error {
+	targetURL := *c.baseURL
+	targetURL.Path += path
+	targetURL.RawQuery = query.Encode()
+
+	var reqBody io.Reader
+	if body != nil {
+		bodyBytes, err := json.Marshal(body)
+		if err != nil {
+			return errors.Wrap(err, "Failed to marshal request body")
+		}
+		reqBody = bytes.NewReader(bodyBytes)
+	}
+
+	log.Debugf("Sending %s request to %s", method, targetURL.String())
+	req, err := http.NewRequestWithContext(c.ctx, method, targetURL.String(), reqBody)
+	if err != nil {
+		return errors.Wrap(err, "Failed to create HTTP request")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "pelican-client/"+config.GetVersion())
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			return errors.New("Request cancelled")
+		}
+		return errors.Wrapf(err, "Failed to execute request to %s", targetURL.String())
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := handleAdminApiResponse(resp)
+	if err != nil {
+		log.Debugf("Raw response body on error: %s", string(bodyBytes))
+		return errors.Wrap(err, "Server request failed")
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(bodyBytes, out); err != nil {
+		log.Debugf("Raw response body on parse error: %s", string(bodyBytes))
+		return errors.Wrap(err, "Failed to parse JSON response from server")
+	}
+	return nil
+}
+
+// resolveNamespaceID returns the registry ID of the namespace given by its ID
+// or its prefix
+func (c *registryUIClient) resolveNamespaceID(namespace string) (int, error) {
+	if id, err := strconv.Atoi(namespace); err == nil {
+		if id <= 0 {
+			return 0, errors.Errorf("Invalid namespace ID %d: it must be a positive integer", id)
+		}
+		return id, nil
+	}
+	if !strings.HasPrefix(namespace, "/") {
+		return 0, errors.Errorf("Invalid namespace %q: give either its registry ID or its prefix", namespace)
+	}
+	registrations := []server_structs.Registration{}
+	if err := c.do(http.MethodGet, "/namespaces", nil, nil, &registrations); err != nil {
+		return 0, err
+	}
+	for _, registration := range registrations {
+		if registration.Prefix == namespace {
+			return registration.ID, nil
+		}
+	}
+	return 0, errors.Errorf("Namespace %s is not registered", namespace)
+}
+
+// printRegistryOutput prints data as YAML, or as JSON if the global --json
+// flag is set
+func printRegistryOutput(cmd *cobra.Command, data any) error {
+	if jsonFlag, _ := cmd.Root().PersistentFlags().GetBool("json"); jsonFlag {
+		jsonData, err := json.MarshalIndent(data, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, "Failed to marshal data to JSON")
+		}
+		fmt.Println(string(jsonData))
+		return nil
+	}
+	yamlData, err := yaml.Marshal(data)
+	if err != nil {
+		return errors.Wrap(err, "Failed to marshal data to YAML")
+	}
+	fmt.Print(string(yamlData))
+	return nil
+}
+
+func listRegistrationAdmins(cmd *cobra.Command, args []string) error {
+	client, err := newRegistryUIClient(cmd)
+	if err != nil {
+		return err
+	}
+	id, err := client.resolveNamespaceID(args[0])
+	if err != nil {
+		return err
+	}
+	admins := []server_structs.RegistrationAdmin{}
+	if err := client.do(http.MethodGet, fmt.Sprintf("/namespaces/%d/admins", id), nil, nil, &admins); err != nil {
+		return err
+	}
+	if len(admins) == 0 {
+		fmt.Println("The namespace has no co-admins.")
+		return nil
+	}
+	return printRegistryOutput(cmd, admins)
+}
+
+func addRegistrationAdmin(cmd *cobra.Command, args []string) error {
+	client, err := newRegistryUIClient(cmd)
+	if err != nil {
+		return err
+	}
+	id, err := client.resolveNamespaceID(args[0])
+	if err != nil {
+		return err
+	}
+	resp := server_structs.SimpleApiResp{}
+	if err := client.do(http.MethodPost, fmt.Sprintf("/namespaces/%d/admins", id), nil, map[string]string{"user_id": args[1]}, &resp); err != nil {
+		return err
+	}
+	fmt.Println(resp.Msg)
+	return nil
+}
+
+func removeRegistrationAdmin(cmd *cobra.Command, args []string) error {
+	client, err := newRegistryUIClient(cmd)
+	if err != nil {
+		return err
+	}
+	id, err := client.resolveNamespaceID(args[0])
+	if err != nil {
+		return err
+	}
+	if err := client.do(http.MethodDelete, fmt.Sprintf("/namespaces/%d/admins/%s", id, url.PathEscape(args[1])), nil, nil, nil); err != nil {
+		return err
+	}
+	fmt.Printf("User %s is no longer an admin of namespace %s\n", args[1], args[0])
+	return nil
+}
+
+func requestRegistrationTransfer(cmd *cobra.Command, args []string) error {
+	client, err := newRegistryUIClient(cmd)
+	if err != nil {
+		return err
+	}
+	id, err := client.resolveNamespaceID(args[0])
+	if err != nil {
+		return err
+	}
+	transfer := server_structs.RegistrationTransfer{}
+	if err := client.do(http.MethodPost, fmt.Sprintf("/namespaces/%d/transfers", id), nil, map[string]string{"to_user_id": args[1]}, &transfer); err != nil {
+		return err
+	}
+	fmt.Printf("Requested transfer %d of namespace %s to %s; it takes effect once a registry admin approves it.\n", transfer.ID, args[0], args[1])
+	return nil
+}
+
+func listRegistrationTransfers(cmd *cobra.Command, args []string) error {
+	namespace, _ := cmd.Flags().GetString("namespace")
+	status, _ := cmd.Flags().GetString("status")
+	if status != "" && !server_structs.IsValidRegStatus(status) {
+		return errors.Errorf("Invalid status %q: must be 'Pending', 'Approved' or 'Denied'", status)
+	}
+
+	client, err := newRegistryUIClient(cmd)
+	if err != nil {
+		return err
+	}
+	transfers := []server_structs.RegistrationTransfer{}
+	if namespace != "" {
+		id, err := client.resolveNamespaceID(namespace)
+		if err != nil {
+			return err
+		}
+		if err := client.do(http.MethodGet, fmt.Sprintf("/namespaces/%d/transfers", id), nil, nil, &transfers); err != nil {
+			return err
+		}
+		if status != "" {
+			filtered := []server_structs.RegistrationTransfer{}
+			for _, transfer := range transfers {
+				if string(transfer.Status) == status {
+					filtered = append(filtered, transfer)
+				}
+			}
+			transfers = filtered
+		}
+	} else {
+		query := url.Values{}
+		if status != "" {
+			query.Set("status", status)
+		}
+		if err := client.do(http.MethodGet, "/transfers", query, nil, &transfers); err != nil {
+			return err
+		}
+	}
+	if len(transfers) == 0 {
+		fmt.Println("No transfers found matching the criteria.")
+		return nil
+	}
+	return printRegistryOutput(cmd, transfers)
+}
+
+func decideRegistrationTransfer(cmd *cobra.Command, transferID, decision string) error {
+	id, err := strconv.Atoi(transferID)
+	if err != nil || id <= 0 {
+		return errors.Errorf("Invalid transfer ID %q: it must be a positive integer", transferID)
+	}
+	client, err := newRegistryUIClient(cmd)
+	if err != nil {
+		return err
+	}
+	transfer := server_structs.RegistrationTransfer{}
+	if err := client.do(http.MethodPatch, fmt.Sprintf("/transfers/%d/%s", id, decision), nil, nil, &transfer); err != nil {
+		return err
+	}
+	fmt.Printf("Transfer %d from %q to %q is now %s\n", transfer.ID, transfer.FromUserID, transfer.ToUserID, transfer.Status.LowerString())
+	return nil
+}
+
+func listRegistryAudit(cmd *cobra.Command, args []string) error {
+	namespace, _ := cmd.Flags().GetString("namespace")
+	user, _ := cmd.Flags().GetString("user")
+	prefix, _ := cmd.Flags().GetString("prefix")
+	limit, _ := cmd.Flags().GetInt("limit")
+	if namespace != "" && prefix != "" {
+		return errors.New("Only one of --namespace and --prefix may be given")
+	}
+
+	client, err := newRegistryUIClient(cmd)
+	if err != nil {
+		return err
+	}
+	query := url.Values{}
+	if user != "" {
+		query.Set("user_id", user)
+	}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	path := "/audit"
+	if namespace != "" {
+		id, err := client.resolveNamespaceID(namespace)
+		if err != nil {
+			return err
+		}
+		path = fmt.Sprintf("/namespaces/%d/audit", id)
+	} else if prefix != "" {
+		query.Set("prefix", prefix)
+	}
+	entries := []server_structs.RegistrationAuditEntry{}
+	if err := client.do(http.MethodGet, path, query, nil, &entries); err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		fmt.Println("No audit entries found matching the criteria.")
+		return nil
+	}
+	return printRegistryOutput(cmd, entries)
+}
@@ -0,0 +1,176 @@
+//go:build server
+
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pelicanplatform/pelican/param"
+	"github.com/pelicanplatform/pelican/server_structs"
+	"github.com/pelicanplatform/pelican/server_utils"
+)
+
+func TestConstructRegistryUIApiURL(t *testing.T) {
+	_, err := constructRegistryUIApiURL("")
+	assert.ErrorContains(t, err, "--server flag")
+	_, err = constructRegistryUIApiURL("http://registry.example.com")
+	assert.ErrorContains(t, err, "https scheme")
+	result, err := constructRegistryUIApiURL("https://registry.example.com:8447/")
+	require.NoError(t, err)
+	assert.Equal(t, "https://registry.example.com:8447/api/v1.0/registry_ui", result.String())
+}
+
+func TestRegistryAdminCommands(t *testing.T) {
+	server_utils.ResetTestState()
+	origServerURL := registryAdminServerURLStr
+	origTokenLocation := registryAdminTokenLocation
+	t.Cleanup(func() {
+		registryAdminServerURLStr = origServerURL
+		registryAdminTokenLocation = origTokenLocation
+		server_utils.ResetTestState()
+	})
+
+	requests := []string{}
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Bearer tokens without a login cookie skip the CSRF check
+		assert.Equal(t, "Bearer user-token", r.Header.Get("Authorization"))
+		_, err := r.Cookie("login")
+		assert.ErrorIs(t, err, http.ErrNoCookie)
+		requests = append(requests, r.Method+" "+r.URL.RequestURI())
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/v1.0/registry_ui/namespaces":
+			_ = json.NewEncoder(w).Encode([]server_structs.Registration{{ID: 3, Prefix: "/foo"}, {ID: 4, Prefix: "/bar"}})
+		case "POST /api/v1.0/registry_ui/namespaces/4/admins":
+			body := map[string]string{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, "helper", body["user_id"])
+			_ = json.NewEncoder(w).Encode(server_structs.SimpleApiResp{Status: server_structs.RespOK, Msg: "added"})
+		case "POST /api/v1.0/registry_ui/namespaces/3/transfers":
+			_ = json.NewEncoder(w).Encode(server_structs.RegistrationTransfer{ID: 7, Status: server_structs.RegPending})
+		case "PATCH /api/v1.0/registry_ui/transfers/7/approve":
+			_ = json.NewEncoder(w).Encode(server_structs.RegistrationTransfer{ID: 7, Status: server_structs.RegApproved})
+		case "GET /api/v1.0/registry_ui/audit":
+			_ = json.NewEncoder(w).Encode([]server_structs.RegistrationAuditEntry{})
+		default:
+			w.WriteHeader(http.StatusForbidden)
+			_ = json.NewEncoder(w).Encode(server_structs.SimpleApiResp{Status: server_structs.RespFailed, Msg: "not allowed"})
+		}
+	}))
+	defer server.Close()
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("user-token"), 0600))
+	require.NoError(t, param.TLSSkipVerify.Set(true))
+	registryAdminServerURLStr = server.URL
+	registryAdminTokenLocation = tokenFile
+
+	require.NoError(t, addRegistrationAdmin(&cobra.Command{}, []string{"/bar", "helper"}))
+	require.NoError(t, requestRegistrationTransfer(&cobra.Command{}, []string{"3", "successor"}))
+	require.NoError(t, decideRegistrationTransfer(&cobra.Command{}, "7", "approve"))
+
+	require.NoError(t, registryAuditCmd.Flags().Set("user", "owner"))
+	t.Cleanup(func() {
+		_ = registryAuditCmd.Flags().Set("user", "")
+	})
+	require.NoError(t, listRegistryAudit(registryAuditCmd, nil))
+
+	err := removeRegistrationAdmin(&cobra.Command{}, []string{"/bar", "helper"})
+	assert.ErrorContains(t, err, "not allowed")
+	err = addRegistrationAdmin(&cobra.Command{}, []string{"/missing", "helper"})
+	assert.ErrorContains(t, err, "not registered")
+
+	assert.Equal(t, []string{
+		"GET /api/v1.0/registry_ui/namespaces",
+		"POST /api/v1.0/registry_ui/namespaces/4/admins",
+		"POST /api/v1.0/registry_ui/namespaces/3/transfers",
+		"PATCH /api/v1.0/registry_ui/transfers/7/approve",
+		"GET /api/v1.0/registry_ui/audit?user_id=owner",
+		"GET /api/v1.0/registry_ui/namespaces",
+		"DELETE /api/v1.0/registry_ui/namespaces/4/admins/helper",
+		"GET /api/v1.0/registry_ui/namespaces",
+	}, requests)
+}
+
+func TestRegistrySearchCommand(t *testing.T) {
+	server_utils.ResetTestState()
+	origServerURL := registryAdminServerURLStr
+	origTokenLocation := registryAdminTokenLocation
+	t.Cleanup(func() {
+		registryAdminServerURLStr = origServerURL
+		registryAdminTokenLocation = origTokenLocation
+		server_utils.ResetTestState()
+	})
+
+	requests := []string{}
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Searches without --token are anonymous
+		assert.Empty(t, r.Header.Get("Authorization"))
+		requests = append(requests, r.URL.RequestURI())
+
+		w.Header().Set("Content-Type", "application/json")
+		page := registrySearchPage{Total: 2}
+		if r.URL.Query().Get("cursor") == "" {
+			page.Namespaces = []server_structs.Registration{{ID: 1, Prefix: "/data/alpha"}}
+			page.NextCursor = "next"
+		} else {
+			page.Namespaces = []server_structs.Registration{{ID: 2, Prefix: "/data/beta"}}
+		}
+		_ = json.NewEncoder(w).Encode(page)
+	}))
+	defer server.Close()
+
+	require.NoError(t, param.TLSSkipVerify.Set(true))
+	registryAdminServerURLStr = server.URL
+	registryAdminTokenLocation = ""
+
+	cmd := &cobra.Command{}
+	cmd.Flags().AddFlagSet(registrySearchCmd.Flags())
+	require.NoError(t, cmd.Flags().Set("prefix", "/data"))
+	require.NoError(t, cmd.Flags().Set("server-type", "namespace"))
+	require.NoError(t, cmd.Flags().Set("limit", "1"))
+	require.NoError(t, cmd.Flags().Set("all", "true"))
+	t.Cleanup(func() {
+		registrySearchCmd.Flags().VisitAll(func(flag *pflag.Flag) {
+			_ = flag.Value.Set(flag.DefValue)
+			flag.Changed = false
+		})
+	})
+	require.NoError(t, searchRegistrations(cmd, nil))
+	assert.Equal(t, []string{
+		"/api/v1.0/registry_ui/namespaces/search?limit=1&prefix=%2Fdata&prefixType=namespace",
+		"/api/v1.0/registry_ui/namespaces/search?cursor=next&limit=1&prefix=%2Fdata&prefixType=namespace",
+	}, requests)
+
+	require.NoError(t, cmd.Flags().Set("field", "department"))
+	assert.ErrorContains(t, searchRegistrations(cmd, nil), "name=value")
+}
@@ -0,0 +1,158 @@
+//go:build server
+
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/pelicanplatform/pelican/server_structs"
+)
+
+// registrySearchPage is a page of namespace search results as returned by
+// the registry
+type registrySearchPage struct {
+	Namespaces []server_structs.Registration `json:"namespaces" yaml:"namespaces"`
+	Total      int                           `json:"total" yaml:"total"`
+	NextCursor string                        `json:"next_cursor,omitempty" yaml:"next_cursor,omitempty"`
+}
+
+var registrySearchCmd = &cobra.Command{
+	Use:   "search",
+	Short: "Search the namespace registrations of a registry",
+	Long: `Search the namespace registrations of a registry by prefix, institution,
+status, server type, custom registration fields and creation date.  Results
+are sorted by prefix unless --sort is given and come in pages of --limit
+registrations; pass the cursor printed after a page to --cursor to get the
+next one, or use --all to fetch every page.
+
+Without --token, the search is anonymous: only approved registrations are
+returned and custom registration fields can't be searched.`,
+	Example: `  pelican registry search -s https://my-registry.com:8447 --prefix /ospool
+  pelican registry search -s https://my-registry.com:8447 -t token --status Pending --server-type origin
+  pelican registry search -s https://my-registry.com:8447 -t token --field department=Physics --all`,
+	Args: cobra.NoArgs,
+	RunE: searchRegistrations,
+}
+
+func init() {
+	flags := registrySearchCmd.Flags()
+	flags.String("prefix", "", "Only return registrations whose prefix contains this string")
+	flags.String("institution", "", "Only return registrations of this institution ID")
+	flags.String("status", "", "Only return registrations with this status ('Pending', 'Approved', 'Denied' or 'Unknown')")
+	flags.String("server-type", "", "Only return registrations of this type ('namespace', 'origin' or 'cache')")
+	flags.StringArray("field", nil, "Only return registrations whose custom registration field has the value, given as name=value (repeatable)")
+	flags.String("created-after", "", "Only return registrations created after this RFC 3339 timestamp or YYYY-MM-DD date")
+	flags.String("sort", "", "Sort by 'prefix' (the default), 'id', 'site_name', 'created_at' or 'updated_at'")
+	flags.String("order", "", "The sort order, 'asc' (the default) or 'desc'")
+	flags.Int("limit", 0, "The number of registrations per page (the registry defaults to 50)")
+	flags.String("cursor", "", "Continue a previous search from this cursor")
+	flags.Bool("all", false, "Fetch all pages of results")
+}
+
+// Build the registry query for the search flags of cmd
+func registrySearchQuery(cmd *cobra.Command) (url.Values, error) {
+	query := url.Values{}
+	for flag, param := range map[string]string{
+		"prefix":        "prefix",
+		"institution":   "institution",
+		"status":        "status",
+		"server-type":   "prefixType",
+		"created-after": "createdAfter",
+		"sort":          "sort",
+		"order":         "order",
+		"cursor":        "cursor",
+	} {
+		if value, _ := cmd.Flags().GetString(flag); value != "" {
+			query.Set(param, value)
+		}
+	}
+	if status := query.Get("status"); status != "" && !server_structs.IsValidRegStatus(status) {
+		return nil, errors.Errorf("Invalid status %q: must be 'Pending', 'Approved', 'Denied' or 'Unknown'", status)
+	}
+	if limit, _ := cmd.Flags().GetInt("limit"); limit != 0 {
+		if limit < 0 {
+			return nil, errors.Errorf("Invalid limit %d: it must be a positive integer", limit)
+		}
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	fields, _ := cmd.Flags().GetStringArray("field")
+	for _, field := range fields {
+		name, value, found := strings.Cut(field, "=")
+		if !found || name == "" {
+			return nil, errors.Errorf("Invalid field %q: it must be given as name=value", field)
+		}
+		query.Set("custom_fields."+name, value)
+	}
+	return query, nil
+}
+
+func searchRegistrations(cmd *cobra.Command, args []string) error {
+	query, err := registrySearchQuery(cmd)
+	if err != nil {
+		return err
+	}
+	fetchAll, _ := cmd.Flags().GetBool("all")
+
+	var client *registryUIClient
+	if registryAdminTokenLocation != "" {
+		client, err = newRegistryUIClient(cmd)
+	} else {
+		client, err = newAnonymousRegistryUIClient(cmd)
+	}
+	if err != nil {
+		return err
+	}
+
+	result := registrySearchPage{Namespaces: []server_structs.Registration{}}
+	for {
+		page := registrySearchPage{}
+		if err := client.do(http.MethodGet, "/namespaces/search", query, nil, &page); err != nil {
+			return err
+		}
+		result.Namespaces = append(result.Namespaces, page.Namespaces...)
+		result.Total = page.Total
+		result.NextCursor = page.NextCursor
+		if !fetchAll || page.NextCursor == "" {
+			break
+		}
+		query.Set("cursor", page.NextCursor)
+	}
+
+	if len(result.Namespaces) == 0 {
+		fmt.Println("No namespaces found matching the criteria.")
+		return nil
+	}
+	if err := printRegistryOutput(cmd, result); err != nil {
+		return err
+	}
+	if result.NextCursor != "" {
+		fmt.Fprintf(os.Stderr, "Showing %d of %d namespaces; pass --cursor %s for the next page\n", len(result.Namespaces), result.Total, result.NextCursor)
+	}
+	return nil
+}
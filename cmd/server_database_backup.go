@@ -32,6 +32,7 @@ import (
 
 	"github.com/pelicanplatform/pelican/config"
 	"github.com/pelicanplatform/pelican/database"
+	dbutils "github.com/pelicanplatform/pelican/database/utils"
 	"github.com/pelicanplatform/pelican/param"
 	"github.com/pelicanplatform/pelican/server_structs"
 )
@@ -40,7 +41,7 @@ var (
 	serverDatabaseCmd = &cobra.Command{
 		Use:   "database",
 		Short: "Manage the Pelican server database",
-		Long: `Provide commands for managing the Pelican server's SQLite database,
+		Long: `Provide commands for managing the Pelican server's database,
 including backup creation, listing, verification, and restoration.`,
 		Aliases: []string{"db"},
 	}
@@ -94,7 +95,10 @@ backup is verified.`,
 		Long: `Restore the server database from a specific backup file. If the
 database already exists, the --force flag must be specified; in that
 case the existing database is renamed with a .pre-restore suffix
-before restoring.
+before restoring. When Server.DbType is postgres, the backup is loaded
+into the configured PostgreSQL database instead, and --force replaces
+the rows already there; create a fresh backup first if you may need
+them again.
 
 If no backup file is specified by path, the most recent backup is
 used when --latest is provided.
@@ -142,13 +146,15 @@ func cliBackupCreate(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// Initialize the database handle so VACUUM INTO works.
-	dbPath := param.Server_DbLocation.GetString()
-	if dbPath == "" {
+	dbType, err := dbutils.ParseDbType(param.Server_DbType.GetString())
+	if err != nil {
+		return err
+	}
+	if dbType == dbutils.DbTypeSQLite && param.Server_DbLocation.GetString() == "" {
 		return errors.New("Server.DbLocation is not configured")
 	}
 
-	// We need the database open for VACUUM INTO. Passing 0 as server type
+	// We need the database open to snapshot it. Passing 0 as server type
 	// opens the database without running any type-specific migrations,
 	// which is appropriate for backup commands that only need read access.
 	if database.ServerDatabase == nil {
@@ -316,16 +322,22 @@ func cliBackupRestore(cmd *cobra.Command, args []string) error {
 		return errors.New("specify a backup file path, or use --latest to restore the most recent backup")
 	}
 
+	// An empty dbPath restores into the configured database, which may be
+	// PostgreSQL rather than a file.
 	dbPath, _ := cmd.Flags().GetString("output")
-	if dbPath == "" {
-		dbPath = param.Server_DbLocation.GetString()
+	target := dbPath
+	if target == "" {
+		target = param.Server_DbLocation.GetString()
+		if dbType, _ := dbutils.ParseDbType(param.Server_DbType.GetString()); dbType == dbutils.DbTypePostgres {
+			target = dbutils.DescribePostgresDSN(param.Server_DbUrl.GetString())
+		}
 	}
 	if err := database.RestoreFromSpecificBackup(dbPath, backupPath, force); err != nil {
 		if outputJSON {
 			result := map[string]interface{}{
 				"status": "failed",
 				"source": backupPath,
-				"target": dbPath,
+				"target": target,
 				"error":  err.Error(),
 			}
 			var noKey *database.ErrNoMatchingKey
@@ -346,14 +358,14 @@ func cliBackupRestore(cmd *cobra.Command, args []string) error {
 		result := map[string]interface{}{
 			"status": "restored",
 			"source": backupPath,
-			"target": dbPath,
+			"target": target,
 		}
 		enc := json.NewEncoder(cmd.OutOrStdout())
 		enc.SetIndent("", "  ")
 		return enc.Encode(result)
 	}
 
-	fmt.Printf("Database restored from %s to %s\n", backupPath, dbPath)
+	fmt.Printf("Database restored from %s to %s\n", backupPath, target)
 	return nil
 }
 
@@ -445,6 +457,9 @@ func cliBackupInfo(cmd *cobra.Command, args []string) error {
 	if meta.DatabasePath != "" {
 		fmt.Printf("%-18s %s\n", "Database Path:", meta.DatabasePath)
 	}
+	if meta.DatabaseType != "" {
+		fmt.Printf("%-18s %s\n", "Database Type:", meta.DatabaseType)
+	}
 	fmt.Printf("%-18s %s/%s\n", "Platform:", meta.GOOS, meta.GOARCH)
 
 	return nil
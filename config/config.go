@@ -1696,6 +1696,12 @@ func SetServerDefaults(v *viper.Viper) error {
 		v.Set(param.Director_AdaptiveSortTruncateConstant.GetName(), 6)
 	}
 
+	if throughputTimeConstant := v.GetDuration(param.Director_ThroughputSortEWMATimeConstant.GetName()); throughputTimeConstant <= 0 {
+		p := param.Director_ThroughputSortEWMATimeConstant
+		log.Warningf("Invalid value of %q for config param %s; must be greater than 0. Resetting to default", throughputTimeConstant.String(), p.GetName())
+		v.Set(p.GetName(), 30*time.Minute)
+	}
+
 	v.SetDefault(param.Monitoring_DataRetentionSize.GetName(), "0B")
 
 	// Setup the audience to use.  We may customize the Origin.URL in the future if it has
@@ -2032,6 +2038,11 @@ func InitServer(ctx context.Context, currentServers server_structs.ServerType) e
 			}
 		}
 
+		if explorationPct := param.Director_ThroughputSortExplorationPercentage.GetInt(); explorationPct < 0 || explorationPct > 100 {
+			return errors.Errorf("invalid value of '%d' for config param %s; must be between 0 and 100",
+				explorationPct, param.Director_ThroughputSortExplorationPercentage.GetName())
+		}
+
 		viper.SetDefault("Federation.DirectorUrl", param.Server_ExternalWebUrl.GetString())
 		minStatRes := param.Director_MinStatResponse.GetInt()
 		maxStatRes := param.Director_MaxStatResponse.GetInt()
@@ -2043,15 +2054,15 @@ func InitServer(ctx context.Context, currentServers server_structs.ServerType) e
 		}
 
 		switch s := (server_structs.SortType)(param.Director_CacheSortMethod.GetString()); s {
-		case server_structs.DistanceType, server_structs.DistanceAndLoadType, server_structs.RandomType, server_structs.AdaptiveType:
+		case server_structs.DistanceType, server_structs.DistanceAndLoadType, server_structs.RandomType, server_structs.AdaptiveType, server_structs.ThroughputType:
 			break
 		case server_structs.SortType(""):
 			if err := param.Director_CacheSortMethod.Set(string(server_structs.DistanceType)); err != nil {
 				return err
 			}
 		default:
-			return errors.New(fmt.Sprintf("invalid Director.CacheSortMethod. Must be one of %q, %q, %q, or %q, but you configured %q.",
-				server_structs.DistanceType, server_structs.DistanceAndLoadType, server_structs.RandomType, server_structs.AdaptiveType, s))
+			return errors.New(fmt.Sprintf("invalid Director.CacheSortMethod. Must be one of %q, %q, %q, %q, or %q, but you configured %q.",
+				server_structs.DistanceType, server_structs.DistanceAndLoadType, server_structs.RandomType, server_structs.AdaptiveType, server_structs.ThroughputType, s))
 		}
 	} else {
 		viper.SetDefault("Federation.DirectorUrl", "")
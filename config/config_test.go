@@ -871,6 +871,27 @@ func TestInitServerUrl(t *testing.T) {
 	})
 }
 
+// The director's throughput sort exploration percentage is only validated
+// on servers running the director.
+func TestThroughputSortExplorationPercentageValidation(t *testing.T) {
+	ctx := testConfigContext(t)
+	t.Cleanup(func() {
+		ResetConfig()
+	})
+	initConfig := func() {
+		ResetConfig()
+		mockFederationRoot(t)
+		require.NoError(t, param.ConfigDir.Set(t.TempDir()))
+		require.NoError(t, param.Director_ThroughputSortExplorationPercentage.Set(150))
+	}
+
+	initConfig()
+	assert.NoError(t, InitServer(ctx, server_structs.RegistryType))
+
+	initConfig()
+	assert.ErrorContains(t, InitServer(ctx, server_structs.DirectorType), param.Director_ThroughputSortExplorationPercentage.GetName())
+}
+
 // Test that the web config override can correctly set the logfile location.
 func TestWebConfigSetsLogFile(t *testing.T) {
 	ResetConfig()
@@ -52,7 +52,8 @@ import (
 type IssuerKeys struct {
 	// CurrentKey is the private key used to sign tokens and payloads. It corresponds to the
 	// private key with the lowest lexicographical filename among the legacy key file
-	// (if present) and all .pem files in IssuerKeyDirectory.
+	// (if present) and all .pem files in IssuerKeyDirectory, unless an issuer key
+	// rotation selected another key.
 	CurrentKey jwk.Key
 
 	// AllKeys holds all valid private keys as a [keyID:key] map, including those from .pem files
@@ -819,6 +820,11 @@ func loadPEMFiles(dir string) (jwk.Key, error) {
 		return newKey, nil
 	}
 
+	// An issuer key rotation in progress (or completed) overrides the lexicographical choice
+	if rotationKey := getRotationSigningKey(dir, latestKeys); rotationKey != nil {
+		firstKey = rotationKey
+	}
+
 	// Save current key and all up-to-date valid private keys and the in-memory issuerKeys
 	newKeys := IssuerKeys{
 		CurrentKey: firstKey,
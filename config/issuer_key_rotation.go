@@ -0,0 +1,371 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/pelicanplatform/pelican/param"
+)
+
+// A rotation of the issuer key moves through the following phases:
+//
+//  1. Publishing: a new key is generated in IssuerKeysDirectory. Both keys are
+//     published, but tokens are still signed with the old key.
+//  2. Overlap: the server signs with the new key while the old key stays published,
+//     so tokens signed before the switch remain valid.
+//  3. Idle: the old key is moved to the "retired" subdirectory and is no longer published.
+//
+// The rotation state is kept in a file next to the keys so that both the running
+// server and `pelican key rotate` can see it, and so it survives restarts.
+type KeyRotationPhase string
+
+// KeyPublishedCheck reports whether the new key of a rotation has been published
+// everywhere it needs to be before the server may start signing with it
+type KeyPublishedCheck func(newKey jwk.Key) (bool, error)
+
+type KeyRotationState struct {
+	Phase KeyRotationPhase `json:"phase"`
+	// The key that was active when the rotation started
+	OldKeyID string `json:"old_key_id,omitempty"`
+	// The key generated by the rotation
+	NewKeyID string `json:"new_key_id,omitempty"`
+	// The key tokens are signed with. It takes precedence over the lexicographical
+	// ordering of the key files and persists after the rotation completes.
+	SigningKeyID string    `json:"signing_key_id,omitempty"`
+	StartedAt    time.Time `json:"started_at,omitzero"`
+	SwitchAt     time.Time `json:"switch_at,omitzero"`
+	RetireAt     time.Time `json:"retire_at,omitzero"`
+	LastRotation time.Time `json:"last_rotation,omitzero"`
+}
+
+const (
+	KeyRotationIdle       KeyRotationPhase = "idle"
+	KeyRotationPublishing KeyRotationPhase = "publishing"
+	KeyRotationOverlap    KeyRotationPhase = "overlap"
+
+	keyRotationStateFile = "key-rotation.json"
+	retiredKeysDir       = "retired"
+)
+
+func getKeyRotationStatePath() (string, error) {
+	dir := param.IssuerKeysDirectory.GetString()
+	if dir == "" {
+		return "", errors.New("issuer key rotation requires IssuerKeysDirectory to be set")
+	}
+	return filepath.Join(dir, keyRotationStateFile), nil
+}
+
+func readKeyRotationState(statePath string) (KeyRotationState, error) {
+	state := KeyRotationState{Phase: KeyRotationIdle}
+	contents, err := os.ReadFile(statePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	} else if err != nil {
+		return state, errors.Wrap(err, "failed to read the issuer key rotation state")
+	}
+	if err = json.Unmarshal(contents, &state); err != nil {
+		return state, errors.Wrapf(err, "failed to parse the issuer key rotation state in %s", statePath)
+	}
+	if state.Phase == "" {
+		state.Phase = KeyRotationIdle
+	}
+	return state, nil
+}
+
+// GetKeyRotationState returns the state of the issuer key rotation, which is idle
+// if no rotation has ever been started
+func GetKeyRotationState() (KeyRotationState, error) {
+	statePath, err := getKeyRotationStatePath()
+	if err != nil {
+		return KeyRotationState{Phase: KeyRotationIdle}, err
+	}
+	return readKeyRotationState(statePath)
+}
+
+// Atomically replace the rotation state file so a concurrent reader never sees a partial write
+func saveKeyRotationState(state KeyRotationState) error {
+	statePath, err := getKeyRotationStatePath()
+	if err != nil {
+		return err
+	}
+	contents, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to serialize the issuer key rotation state")
+	}
+	tmpFile, err := os.CreateTemp(filepath.Dir(statePath), keyRotationStateFile+".*")
+	if err != nil {
+		return errors.Wrap(err, "failed to create the issuer key rotation state file")
+	}
+	defer os.Remove(tmpFile.Name())
+	if _, err = tmpFile.Write(contents); err != nil {
+		tmpFile.Close()
+		return errors.Wrap(err, "failed to write the issuer key rotation state")
+	}
+	if err = tmpFile.Close(); err != nil {
+		return errors.Wrap(err, "failed to write the issuer key rotation state")
+	}
+
+	user, err := GetPelicanUser()
+	if err != nil {
+		return errors.Wrap(err, "failed to get pelican user for setting ownership")
+	}
+	if err = SetOwnershipAndPermissions(tmpFile.Name(), 0640, user); err != nil {
+		return errors.Wrapf(err, "failed to set ownership and permissions for %s", tmpFile.Name())
+	}
+	if err = os.Rename(tmpFile.Name(), statePath); err != nil {
+		return errors.Wrap(err, "failed to save the issuer key rotation state")
+	}
+	return nil
+}
+
+// Return the signing key selected by the rotation state, if it is one of the loaded keys.
+// Errors are only logged so a damaged state file never prevents the server from signing.
+func getRotationSigningKey(dir string, keys map[string]jwk.Key) jwk.Key {
+	if dir == "" {
+		return nil
+	}
+	state, err := readKeyRotationState(filepath.Join(dir, keyRotationStateFile))
+	if err != nil {
+		log.Warningf("Ignoring the issuer key rotation state: %v", err)
+		return nil
+	}
+	if state.SigningKeyID == "" {
+		return nil
+	}
+	key, ok := keys[state.SigningKeyID]
+	if !ok {
+		log.Warningf("Signing key %s selected by the issuer key rotation is not present; falling back to the key with the lowest filename", state.SigningKeyID)
+		return nil
+	}
+	return key
+}
+
+// Find the file holding the private key with the given key ID, either the legacy
+// IssuerKey file or one of the key files in IssuerKeysDirectory
+func findIssuerKeyFile(kid string) (string, error) {
+	candidates := []string{}
+	if issuerKeyPath := param.IssuerKey.GetString(); issuerKeyPath != "" {
+		candidates = append(candidates, issuerKeyPath)
+	}
+	if dir := param.IssuerKeysDirectory.GetString(); dir != "" {
+		entries, err := os.ReadDir(dir)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return "", errors.Wrapf(err, "failed to list the keys in %s", dir)
+		}
+		for _, entry := range entries {
+			if ext := filepath.Ext(entry.Name()); ext == ".pem" || ext == ".jwk" {
+				candidates = append(candidates, filepath.Join(dir, entry.Name()))
+			}
+		}
+	}
+	for _, candidate := range candidates {
+		if fileInfo, err := os.Stat(candidate); err != nil || !fileInfo.Mode().IsRegular() {
+			continue
+		}
+		key, err := LoadSinglePEM(candidate)
+		if err != nil {
+			continue
+		}
+		if key.KeyID() == kid {
+			return candidate, nil
+		}
+	}
+	return "", nil
+}
+
+// StartIssuerKeyRotation generates a new issuer key and begins publishing it
+// alongside the currently active key. The server switches to the new key once
+// Server.IssuerKeyRotation.PublishPeriod has elapsed.
+func StartIssuerKeyRotation(now time.Time) (KeyRotationState, error) {
+	state, err := GetKeyRotationState()
+	if err != nil {
+		return state, err
+	}
+	if state.Phase != KeyRotationIdle {
+		return state, errors.Errorf("an issuer key rotation is already in progress (phase %s)", state.Phase)
+	}
+
+	oldKey, err := loadIssuerPrivateKey(param.IssuerKeysDirectory.GetString())
+	if err != nil {
+		return state, errors.Wrap(err, "failed to load the active issuer key")
+	}
+	newKey, err := GeneratePEM(param.IssuerKeysDirectory.GetString())
+	if err != nil {
+		return state, errors.Wrap(err, "failed to generate a new issuer key")
+	}
+
+	state.Phase = KeyRotationPublishing
+	state.OldKeyID = oldKey.KeyID()
+	state.NewKeyID = newKey.KeyID()
+	state.SigningKeyID = oldKey.KeyID()
+	state.StartedAt = now
+	state.SwitchAt = now.Add(param.Server_IssuerKeyRotation_PublishPeriod.GetDuration())
+	state.RetireAt = state.SwitchAt.Add(param.Server_IssuerKeyRotation_OverlapPeriod.GetDuration())
+	if err = saveKeyRotationState(state); err != nil {
+		return state, err
+	}
+	log.Infof("Started rotating the issuer key from %s to %s; the new key will be used for signing after %s",
+		state.OldKeyID, state.NewKeyID, state.SwitchAt.Format(time.RFC3339))
+	return state, nil
+}
+
+// Decide whether the active issuer key is old enough to be rotated automatically
+func issuerKeyRotationDue(state KeyRotationState, now time.Time) (bool, error) {
+	interval := param.Server_IssuerKeyRotation_Interval.GetDuration()
+	if interval <= 0 {
+		return false, nil
+	}
+	lastRotation := state.LastRotation
+	if lastRotation.IsZero() {
+		// The server has never rotated its key; measure the age from when the key file was written
+		currentKey, err := loadIssuerPrivateKey(param.IssuerKeysDirectory.GetString())
+		if err != nil {
+			return false, err
+		}
+		keyFile, err := findIssuerKeyFile(currentKey.KeyID())
+		if err != nil || keyFile == "" {
+			return false, err
+		}
+		fileInfo, err := os.Stat(keyFile)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to check the age of the issuer key in %s", keyFile)
+		}
+		lastRotation = fileInfo.ModTime()
+	}
+	return !now.Before(lastRotation.Add(interval)), nil
+}
+
+// Move the key file of a retired key out of the directory scanned for issuer keys
+func retireIssuerKey(kid string, now time.Time) error {
+	keyFile, err := findIssuerKeyFile(kid)
+	if err != nil {
+		return err
+	}
+	if keyFile == "" {
+		log.Warningf("The key file of retired issuer key %s no longer exists", kid)
+		return nil
+	}
+	retiredDir := filepath.Join(param.IssuerKeysDirectory.GetString(), retiredKeysDir)
+	if err = createDirForKeys(retiredDir); err != nil {
+		return errors.Wrapf(err, "failed to create directory for retired keys at %s", retiredDir)
+	}
+	retiredFile := filepath.Join(retiredDir, now.UTC().Format("20060102T150405Z")+"_"+filepath.Base(keyFile))
+	if err = os.Rename(keyFile, retiredFile); err != nil {
+		return errors.Wrapf(err, "failed to move retired issuer key %s to %s", keyFile, retiredFile)
+	}
+	log.Infof("Retired issuer key %s; its key file was moved to %s", kid, retiredFile)
+	return nil
+}
+
+// AdvanceIssuerKeyRotation moves the issuer key rotation to its next phase once
+// the current phase has lasted long enough, and starts a new rotation when
+// Server.IssuerKeyRotation.Interval is set and the active key is due for one.
+//
+// If published is not nil, the switch to signing with the new key is postponed until
+// it reports the new key as published. It returns true if the rotation state changed;
+// callers should then refresh the in-memory keys with RefreshKeys.
+func AdvanceIssuerKeyRotation(now time.Time, published KeyPublishedCheck) (bool, error) {
+	// Servers relying on the legacy IssuerKey file have nowhere to rotate keys in
+	if param.IssuerKeysDirectory.GetString() == "" {
+		return false, nil
+	}
+	state, err := GetKeyRotationState()
+	if err != nil {
+		return false, err
+	}
+
+	switch state.Phase {
+	case KeyRotationIdle:
+		due, err := issuerKeyRotationDue(state, now)
+		if err != nil {
+			return false, errors.Wrap(err, "failed to check whether the issuer key is due for rotation")
+		}
+		if !due {
+			return false, nil
+		}
+		if _, err = StartIssuerKeyRotation(now); err != nil {
+			return false, err
+		}
+		return true, nil
+
+	case KeyRotationPublishing:
+		if now.Before(state.SwitchAt) {
+			return false, nil
+		}
+		newKeyFile, err := findIssuerKeyFile(state.NewKeyID)
+		if err != nil {
+			return false, err
+		}
+		if newKeyFile == "" {
+			return false, errors.Errorf("the new issuer key %s of the rotation no longer exists in %s", state.NewKeyID, param.IssuerKeysDirectory.GetString())
+		}
+		newKey, err := LoadSinglePEM(newKeyFile)
+		if err != nil {
+			return false, err
+		}
+		if published != nil {
+			isPublished, err := published(newKey)
+			if err != nil {
+				return false, errors.Wrapf(err, "failed to check whether the new issuer key %s is published", state.NewKeyID)
+			}
+			if !isPublished {
+				log.Infof("Postponing the switch to issuer key %s until it has been published", state.NewKeyID)
+				return false, nil
+			}
+		}
+		state.Phase = KeyRotationOverlap
+		state.SigningKeyID = state.NewKeyID
+		// Count the overlap from the actual switch in case it was postponed
+		state.RetireAt = now.Add(param.Server_IssuerKeyRotation_OverlapPeriod.GetDuration())
+		if err = saveKeyRotationState(state); err != nil {
+			return false, err
+		}
+		log.Infof("Switched to signing with issuer key %s; the previous key %s stays published until %s",
+			state.NewKeyID, state.OldKeyID, state.RetireAt.Format(time.RFC3339))
+		return true, nil
+
+	case KeyRotationOverlap:
+		if now.Before(state.RetireAt) {
+			return false, nil
+		}
+		if err = retireIssuerKey(state.OldKeyID, now); err != nil {
+			return false, err
+		}
+		state = KeyRotationState{
+			Phase:        KeyRotationIdle,
+			SigningKeyID: state.SigningKeyID,
+			LastRotation: now,
+		}
+		if err = saveKeyRotationState(state); err != nil {
+			return false, err
+		}
+		return true, nil
+
+	default:
+		return false, errors.Errorf("unknown issuer key rotation phase %q", state.Phase)
+	}
+}
@@ -0,0 +1,185 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pelicanplatform/pelican/param"
+)
+
+func setupKeyRotationTest(t *testing.T) (string, jwk.Key) {
+	ResetConfig()
+	t.Cleanup(ResetConfig)
+	issuerKeysDir := filepath.Join(t.TempDir(), "issuer-keys")
+	require.NoError(t, param.IssuerKeysDirectory.Set(issuerKeysDir))
+	require.NoError(t, param.Server_IssuerKeyRotation_PublishPeriod.Set(time.Hour))
+	require.NoError(t, param.Server_IssuerKeyRotation_OverlapPeriod.Set(24*time.Hour))
+
+	oldKey, err := GeneratePEM(issuerKeysDir)
+	require.NoError(t, err)
+	_, err = RefreshKeys()
+	require.NoError(t, err)
+	return issuerKeysDir, oldKey
+}
+
+func TestIssuerKeyRotation(t *testing.T) {
+	issuerKeysDir, oldKey := setupKeyRotationTest(t)
+	start := time.Now()
+
+	state, err := StartIssuerKeyRotation(start)
+	require.NoError(t, err)
+	assert.Equal(t, KeyRotationPublishing, state.Phase)
+	assert.Equal(t, oldKey.KeyID(), state.OldKeyID)
+	assert.Equal(t, oldKey.KeyID(), state.SigningKeyID)
+	assert.Equal(t, start.Add(time.Hour), state.SwitchAt)
+	_, err = StartIssuerKeyRotation(start)
+	assert.ErrorContains(t, err, "already in progress")
+
+	// Both keys are published, but the old one still signs
+	changed, err := RefreshKeys()
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Len(t, GetIssuerPrivateKeys(), 2)
+	current, err := GetIssuerPrivateJWK()
+	require.NoError(t, err)
+	assert.Equal(t, oldKey.KeyID(), current.KeyID())
+
+	changed, err = AdvanceIssuerKeyRotation(start.Add(30*time.Minute), nil)
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	// The switch waits until the new key is known to be published
+	notPublished := func(newKey jwk.Key) (bool, error) {
+		assert.Equal(t, state.NewKeyID, newKey.KeyID())
+		return false, nil
+	}
+	changed, err = AdvanceIssuerKeyRotation(start.Add(2*time.Hour), notPublished)
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	switchTime := start.Add(3 * time.Hour)
+	changed, err = AdvanceIssuerKeyRotation(switchTime, func(jwk.Key) (bool, error) { return true, nil })
+	require.NoError(t, err)
+	assert.True(t, changed)
+	state, err = GetKeyRotationState()
+	require.NoError(t, err)
+	assert.Equal(t, KeyRotationOverlap, state.Phase)
+	assert.True(t, switchTime.Add(24*time.Hour).Equal(state.RetireAt))
+
+	// The new key signs even though its file sorts after the old one
+	_, err = RefreshKeys()
+	require.NoError(t, err)
+	current, err = GetIssuerPrivateJWK()
+	require.NoError(t, err)
+	assert.Equal(t, state.NewKeyID, current.KeyID())
+	assert.Len(t, GetIssuerPrivateKeys(), 2)
+
+	changed, err = AdvanceIssuerKeyRotation(switchTime.Add(23*time.Hour), nil)
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	// Retiring moves the old key out of the directory
+	retireTime := switchTime.Add(25 * time.Hour)
+	changed, err = AdvanceIssuerKeyRotation(retireTime, nil)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	changed, err = RefreshKeys()
+	require.NoError(t, err)
+	assert.True(t, changed)
+	keys := GetIssuerPrivateKeys()
+	assert.Len(t, keys, 1)
+	assert.Contains(t, keys, state.NewKeyID)
+
+	retired, err := filepath.Glob(filepath.Join(issuerKeysDir, retiredKeysDir, "*.pem"))
+	require.NoError(t, err)
+	require.Len(t, retired, 1)
+	retiredKey, err := LoadSinglePEM(retired[0])
+	require.NoError(t, err)
+	assert.Equal(t, oldKey.KeyID(), retiredKey.KeyID())
+
+	state, err = GetKeyRotationState()
+	require.NoError(t, err)
+	assert.Equal(t, KeyRotationIdle, state.Phase)
+	assert.Equal(t, current.KeyID(), state.SigningKeyID)
+	assert.True(t, retireTime.Equal(state.LastRotation))
+	assert.Empty(t, state.OldKeyID)
+}
+
+func TestAutomaticIssuerKeyRotation(t *testing.T) {
+	_, oldKey := setupKeyRotationTest(t)
+
+	// Rotation is off unless an interval is set
+	changed, err := AdvanceIssuerKeyRotation(time.Now().Add(1000*time.Hour), nil)
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	require.NoError(t, param.Server_IssuerKeyRotation_Interval.Set(720*time.Hour))
+	changed, err = AdvanceIssuerKeyRotation(time.Now().Add(time.Hour), nil)
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	// The age of a key that was never rotated comes from its file
+	now := time.Now().Add(721 * time.Hour)
+	changed, err = AdvanceIssuerKeyRotation(now, nil)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	state, err := GetKeyRotationState()
+	require.NoError(t, err)
+	assert.Equal(t, KeyRotationPublishing, state.Phase)
+	assert.Equal(t, oldKey.KeyID(), state.OldKeyID)
+
+	// Afterwards, the age is counted from the last rotation
+	state.Phase = KeyRotationIdle
+	state.LastRotation = now
+	require.NoError(t, saveKeyRotationState(state))
+	changed, err = AdvanceIssuerKeyRotation(now.Add(719*time.Hour), nil)
+	require.NoError(t, err)
+	assert.False(t, changed)
+}
+
+func TestKeyRotationStateFallback(t *testing.T) {
+	issuerKeysDir, oldKey := setupKeyRotationTest(t)
+	newKey, err := GeneratePEM(issuerKeysDir)
+	require.NoError(t, err)
+
+	// A signing key that no longer exists falls back to the lowest filename
+	require.NoError(t, saveKeyRotationState(KeyRotationState{Phase: KeyRotationIdle, SigningKeyID: "missing"}))
+	current, err := loadPEMFiles(issuerKeysDir)
+	require.NoError(t, err)
+	assert.Equal(t, oldKey.KeyID(), current.KeyID())
+
+	require.NoError(t, saveKeyRotationState(KeyRotationState{Phase: KeyRotationIdle, SigningKeyID: newKey.KeyID()}))
+	current, err = loadPEMFiles(issuerKeysDir)
+	require.NoError(t, err)
+	assert.Equal(t, newKey.KeyID(), current.KeyID())
+
+	// A damaged state file is ignored
+	require.NoError(t, os.WriteFile(filepath.Join(issuerKeysDir, keyRotationStateFile), []byte("{"), 0640))
+	current, err = loadPEMFiles(issuerKeysDir)
+	require.NoError(t, err)
+	assert.Equal(t, oldKey.KeyID(), current.KeyID())
+}
@@ -50,6 +50,7 @@ import (
 	"golang.org/x/sync/errgroup"
 
 	"github.com/pelicanplatform/pelican/config"
+	"github.com/pelicanplatform/pelican/database/utils"
 	"github.com/pelicanplatform/pelican/param"
 	"github.com/pelicanplatform/pelican/version"
 )
@@ -60,6 +61,7 @@ const (
 	backupTempPrefix = "pelican-db-backup-"
 	backupTempSuffix = ".tmp"
 	vacuumTempPrefix = "pelican-db-vacuum-"
+	dumpTempPrefix   = "pelican-db-dump-"
 
 	// chunkSize is the maximum plaintext size per encrypted chunk.
 	// NaCl secretbox docs recommend not encrypting large messages in a single
@@ -113,8 +115,12 @@ type BackupMetadata struct {
 	PelicanVersion string `json:"pelican_version"`
 	// ServerURL is the external web URL of the server, if configured.
 	ServerURL string `json:"server_url,omitempty"`
-	// DatabasePath is the path to the database that was backed up.
+	// DatabasePath is the path to the database that was backed up, or a
+	// description of the PostgreSQL database without its credentials.
 	DatabasePath string `json:"database_path,omitempty"`
+	// DatabaseType is "sqlite" for a copy of the database file or
+	// "postgres" for a logical dump. Older backups leave it empty.
+	DatabaseType string `json:"database_type,omitempty"`
 	// GOOS is the operating system (e.g., "linux", "darwin").
 	GOOS string `json:"goos"`
 	// GOARCH is the architecture (e.g., "amd64", "arm64").
@@ -168,6 +174,9 @@ func writeBackupMetadata(w io.Writer, meta BackupMetadata) error {
 	if meta.DatabasePath != "" {
 		headers["Database-Path"] = meta.DatabasePath
 	}
+	if meta.DatabaseType != "" {
+		headers["Database-Type"] = meta.DatabaseType
+	}
 	block := &pem.Block{
 		Type:    pemTypeMetadata,
 		Headers: headers,
@@ -203,6 +212,7 @@ func readBackupMetadata(r io.ReadSeeker) (*BackupMetadata, error) {
 		PelicanVersion: block.Headers["Pelican-Version"],
 		ServerURL:      block.Headers["Server-URL"],
 		DatabasePath:   block.Headers["Database-Path"],
+		DatabaseType:   block.Headers["Database-Type"],
 		GOOS:           block.Headers["GOOS"],
 		GOARCH:         block.Headers["GOARCH"],
 	}
@@ -360,15 +370,24 @@ func writeEncryptedKeys(dest io.Writer, dekAndNonce []byte, issuerKeys map[strin
 	return nil
 }
 
-// createBackup creates a compressed and encrypted backup of the SQLite database.
-// It uses VACUUM INTO for an atomic snapshot, then streams the data through
-// gzip compression and chunked NaCl secretbox encryption, writing the result
-// as a sequence of PEM blocks. The final file is written atomically via
-// rename.
+// createBackup creates a compressed and encrypted backup of the server
+// database. A SQLite database is snapshotted atomically with VACUUM INTO; a
+// PostgreSQL database is written out as a logical dump from a single
+// transaction. The snapshot is then streamed through gzip compression and
+// chunked NaCl secretbox encryption, writing the result as a sequence of PEM
+// blocks. The final file is written atomically via rename.
 func createBackup(ctx context.Context) error {
 	dbPath := param.Server_DbLocation.GetString()
 	backupDir := param.Server_DatabaseBackup_Location.GetString()
 
+	dbType := utils.DbTypeSQLite
+	if ServerDatabase != nil {
+		dbType = utils.DbTypeOf(ServerDatabase)
+	}
+	if dbType == utils.DbTypePostgres {
+		dbPath = utils.DescribePostgresDSN(param.Server_DbUrl.GetString())
+	}
+
 	if dbPath == "" {
 		return errors.New("database path is not configured")
 	}
@@ -394,32 +413,28 @@ func createBackup(ctx context.Context) error {
 		return errors.Wrapf(err, "failed to create backup directory %s", backupDir)
 	}
 
-	// Create a temporary file for the VACUUM INTO output.
-	vacuumFile, err := os.CreateTemp(backupDir, vacuumTempPrefix+"*.sqlite")
-	if err != nil {
-		return errors.Wrap(err, "failed to create temporary vacuum file")
-	}
-	vacuumPath := vacuumFile.Name()
-	vacuumFile.Close()
-	os.Remove(vacuumPath) // VACUUM INTO needs the file to not exist
-	defer os.Remove(vacuumPath)
-
-	// Use VACUUM INTO for an atomic database snapshot.
 	sqlDB, err := ServerDatabase.DB()
 	if err != nil {
 		return errors.Wrap(err, "failed to get underlying SQL database")
 	}
 
-	// Escape any single quotes in the path to prevent SQL injection.
-	escapedPath := strings.ReplaceAll(vacuumPath, "'", "''")
-	if _, err := sqlDB.ExecContext(ctx, "VACUUM INTO '"+escapedPath+"'"); err != nil {
-		return errors.Wrap(err, "failed to create database backup via VACUUM INTO")
+	var snapshotPath string
+	if dbType == utils.DbTypePostgres {
+		snapshotPath, err = dumpSnapshot(ctx, sqlDB, dbType, backupDir)
+	} else {
+		snapshotPath, err = vacuumSnapshot(ctx, sqlDB, backupDir)
+	}
+	if snapshotPath != "" {
+		defer os.Remove(snapshotPath)
+	}
+	if err != nil {
+		return err
 	}
 
-	// Open the vacuumed database for streaming.
-	sourceFile, err := os.Open(vacuumPath)
+	// Open the snapshot for streaming.
+	sourceFile, err := os.Open(snapshotPath)
 	if err != nil {
-		return errors.Wrap(err, "failed to open vacuumed database file")
+		return errors.Wrap(err, "failed to open database snapshot")
 	}
 	defer sourceFile.Close()
 
@@ -446,6 +461,7 @@ func createBackup(ctx context.Context) error {
 
 	// Write the metadata PEM block first (human-readable, unencrypted).
 	meta := collectBackupMetadata(dbPath, backupTime)
+	meta.DatabaseType = string(dbType)
 	if err := writeBackupMetadata(tmpBackupFile, meta); err != nil {
 		return errors.Wrap(err, "failed to write backup metadata block")
 	}
@@ -502,6 +518,39 @@ func createBackup(ctx context.Context) error {
 	return nil
 }
 
+// vacuumSnapshot copies the SQLite database into a new temporary file in dir
+// using VACUUM INTO and returns its path.
+func vacuumSnapshot(ctx context.Context, sqlDB *sql.DB, dir string) (string, error) {
+	vacuumFile, err := os.CreateTemp(dir, vacuumTempPrefix+"*.sqlite")
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create temporary vacuum file")
+	}
+	vacuumPath := vacuumFile.Name()
+	vacuumFile.Close()
+	os.Remove(vacuumPath) // VACUUM INTO needs the file to not exist
+
+	// Escape any single quotes in the path to prevent SQL injection.
+	escapedPath := strings.ReplaceAll(vacuumPath, "'", "''")
+	if _, err := sqlDB.ExecContext(ctx, "VACUUM INTO '"+escapedPath+"'"); err != nil {
+		return vacuumPath, errors.Wrap(err, "failed to create database backup via VACUUM INTO")
+	}
+	return vacuumPath, nil
+}
+
+// dumpSnapshot writes a logical dump of the database into a new temporary
+// file in dir and returns its path.
+func dumpSnapshot(ctx context.Context, sqlDB *sql.DB, dbType utils.DbType, dir string) (string, error) {
+	dumpFile, err := os.CreateTemp(dir, dumpTempPrefix+"*.jsonl")
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create temporary dump file")
+	}
+	defer dumpFile.Close()
+	if err := dumpDatabase(ctx, sqlDB, dbType, dumpFile); err != nil {
+		return dumpFile.Name(), errors.Wrap(err, "failed to create logical database dump")
+	}
+	return dumpFile.Name(), errors.Wrap(dumpFile.Close(), "failed to write logical database dump")
+}
+
 // rotateBackups removes old backup files that exceed the configured maximum
 // count. It also removes stale temporary files (from interrupted backups)
 // that are older than tempFileMaxAge.
@@ -522,7 +571,8 @@ func rotateBackups(backupDir string) error {
 		}
 		name := entry.Name()
 		isTemp := strings.HasSuffix(name, backupTempSuffix) ||
-			strings.HasPrefix(name, vacuumTempPrefix)
+			strings.HasPrefix(name, vacuumTempPrefix) ||
+			strings.HasPrefix(name, dumpTempPrefix)
 		if !isTemp {
 			continue
 		}
@@ -688,13 +738,155 @@ func restoreFromBackup(dbPath string) (bool, error) {
 	return false, errors.New("failed to restore database from any available backup")
 }
 
-// restoreFromSingleBackup attempts to restore the database from a single
-// backup file. It streams PEM blocks from the file, decrypts chunks via
-// temporary files, and atomically places the restored database at dbPath.
-func restoreFromSingleBackup(dbPath, backupPath string, issuerKeys map[string]jwk.Key) (bool, error) {
+// restoreLogicalFromBackup loads the most recent usable backup into a newly
+// created PostgreSQL database, falling back to older backups on failure.
+// Returns true if a restore was performed.
+func restoreLogicalFromBackup(ctx context.Context, sqlDB *sql.DB, dbType utils.DbType) (bool, error) {
+	if param.Server_DatabaseBackup_Location.GetString() == "" {
+		return false, nil
+	}
+	backups, err := ListBackups()
+	if err != nil {
+		return false, err
+	}
+	if len(backups) == 0 {
+		return false, nil
+	}
+
+	allKeys := config.GetIssuerPrivateKeys()
+	if len(allKeys) == 0 {
+		return false, errors.New("no issuer keys available for backup decryption")
+	}
+
+	for _, backup := range backups {
+		log.Infof("Attempting to restore database from backup: %s", backup.Path)
+		if err := restoreLogicalFromSingleBackup(ctx, sqlDB, dbType, backup.Path, allKeys, false); err != nil {
+			log.Warnf("Failed to restore from backup %s: %v", backup.Path, err)
+			continue
+		}
+		log.Infof("Database successfully restored from backup: %s", backup.Path)
+		return true, nil
+	}
+
+	return false, errors.New("failed to restore database from any available backup")
+}
+
+// restoreLogicalFromSingleBackup loads a single backup into sqlDB, first
+// emptying the existing tables if replace is set. A backup holding a SQLite
+// database file (e.g. taken before the server moved to PostgreSQL) is
+// converted to a logical dump on the way.
+func restoreLogicalFromSingleBackup(ctx context.Context, sqlDB *sql.DB, dbType utils.DbType, backupPath string, issuerKeys map[string]jwk.Key, replace bool) error {
+	// The decrypted data is as sensitive as the database, so keep it next
+	// to the backups rather than in a shared temporary directory.
+	tmpDir := filepath.Dir(backupPath)
+	plainPath, err := decryptBackup(backupPath, tmpDir, issuerKeys)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(plainPath)
+
+	isDump, err := isLogicalDump(plainPath)
+	if err != nil {
+		return err
+	}
+	if !isDump {
+		if err := verifySQLiteIntegrity(plainPath); err != nil {
+			return errors.Wrap(err, "restored database failed integrity check")
+		}
+		sqliteDB, err := sql.Open("sqlite", plainPath)
+		if err != nil {
+			return errors.Wrap(err, "failed to open restored SQLite database")
+		}
+		dumpPath, err := dumpSnapshot(ctx, sqliteDB, utils.DbTypeSQLite, tmpDir)
+		sqliteDB.Close()
+		if dumpPath != "" {
+			defer os.Remove(dumpPath)
+		}
+		if err != nil {
+			return err
+		}
+		plainPath = dumpPath
+	}
+
+	dumpFile, err := os.Open(plainPath)
+	if err != nil {
+		return err
+	}
+	defer dumpFile.Close()
+	return loadDatabase(ctx, sqlDB, dbType, dumpFile, replace)
+}
+
+// restoreToPostgres restores a backup into the PostgreSQL database
+// configured by Server.DbUrl. If force is set, the rows already in the
+// database are replaced; the replacement is a single transaction, so a
+// failed restore leaves the database untouched.
+func restoreToPostgres(backupPath string, force bool) error {
+	ctx := context.Background()
+	dbDescription := utils.DescribePostgresDSN(param.Server_DbUrl.GetString())
+	db, err := utils.InitPostgresDB(param.Server_DbUrl.GetString(), param.Server_DbPasswordFile.GetString())
+	if err != nil {
+		return err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	defer sqlDB.Close()
+
+	unlock, err := lockPostgresMigrations(ctx, sqlDB)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	hasData, err := databaseHasData(ctx, sqlDB, utils.DbTypePostgres)
+	if err != nil {
+		return errors.Wrap(err, "failed to check whether the database is empty")
+	}
+	if hasData && !force {
+		return fmt.Errorf("%w: %s", ErrDatabaseExists, dbDescription)
+	}
+
+	allKeys := config.GetIssuerPrivateKeys()
+	if len(allKeys) == 0 {
+		return errors.New("no issuer keys available for backup decryption")
+	}
+	if err := restoreLogicalFromSingleBackup(ctx, sqlDB, utils.DbTypePostgres, backupPath, allKeys, hasData); err != nil {
+		return errors.Wrapf(err, "failed to restore from backup %s", backupPath)
+	}
+
+	log.Infof("Database restored from %s to %s", backupPath, dbDescription)
+	return nil
+}
+
+// databaseHasData reports whether any table other than the goose version
+// tables contains a row.
+func databaseHasData(ctx context.Context, sqlDB *sql.DB, dbType utils.DbType) (bool, error) {
+	tables, err := listTables(ctx, sqlDB, dbType, false)
+	if err != nil {
+		return false, err
+	}
+	for _, table := range tables {
+		var exists bool
+		query := "SELECT EXISTS (SELECT 1 FROM " + quoteIdentifier(table) + ")"
+		if err := sqlDB.QueryRowContext(ctx, query).Scan(&exists); err != nil {
+			return false, err
+		}
+		if exists {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// decryptBackup decrypts and decompresses a single backup file into a new
+// temporary file in dir and returns its path; the caller removes it. It
+// streams PEM blocks from the backup and decrypts chunks via temporary
+// files.
+func decryptBackup(backupPath, dir string, issuerKeys map[string]jwk.Key) (string, error) {
 	backupFile, err := os.Open(backupPath)
 	if err != nil {
-		return false, errors.Wrapf(err, "failed to open backup file %s", backupPath)
+		return "", errors.Wrapf(err, "failed to open backup file %s", backupPath)
 	}
 	defer backupFile.Close()
 
@@ -726,7 +918,7 @@ func restoreFromSingleBackup(dbPath, backupPath string, issuerKeys map[string]jw
 			if err == io.EOF {
 				break
 			}
-			return false, errors.Wrap(err, "failed to read PEM block")
+			return "", errors.Wrap(err, "failed to read PEM block")
 		}
 
 		if block.Type == pemTypeKey {
@@ -778,18 +970,13 @@ func restoreFromSingleBackup(dbPath, backupPath string, issuerKeys map[string]jw
 		for i, kb := range keyBlocks {
 			keyIDs[i] = kb.keyID
 		}
-		return false, &ErrNoMatchingKey{RequiredKeyIDs: keyIDs}
+		return "", &ErrNoMatchingKey{RequiredKeyIDs: keyIDs}
 	}
 
 	// Phase 2: decrypt data chunks into a temporary compressed file.
-	dbDir := filepath.Dir(dbPath)
-	if err := os.MkdirAll(dbDir, 0755); err != nil {
-		return false, errors.Wrap(err, "failed to create directory for restored database")
-	}
-
-	compressedTmp, err := os.CreateTemp(dbDir, "pelican-restore-compressed-*.tmp")
+	compressedTmp, err := os.CreateTemp(dir, "pelican-restore-compressed-*.tmp")
 	if err != nil {
-		return false, errors.Wrap(err, "failed to create temp file for compressed data")
+		return "", errors.Wrap(err, "failed to create temp file for compressed data")
 	}
 	compressedTmpPath := compressedTmp.Name()
 	defer os.Remove(compressedTmpPath)
@@ -822,7 +1009,7 @@ func restoreFromSingleBackup(dbPath, backupPath string, issuerKeys map[string]jw
 	if firstDataBlock != nil {
 		if err := decryptChunk(firstDataBlock); err != nil {
 			compressedTmp.Close()
-			return false, errors.Wrap(err, "failed to decrypt first data chunk")
+			return "", errors.Wrap(err, "failed to decrypt first data chunk")
 		}
 	}
 
@@ -834,51 +1021,87 @@ func restoreFromSingleBackup(dbPath, backupPath string, issuerKeys map[string]jw
 				break
 			}
 			compressedTmp.Close()
-			return false, errors.Wrap(err, "failed to read PEM data block")
+			return "", errors.Wrap(err, "failed to read PEM data block")
 		}
 		if block.Type != pemTypeData {
 			continue
 		}
 		if err := decryptChunk(block); err != nil {
 			compressedTmp.Close()
-			return false, errors.Wrap(err, "failed to decrypt data chunk")
+			return "", errors.Wrap(err, "failed to decrypt data chunk")
 		}
 	}
 
 	if err := compressedTmp.Close(); err != nil {
-		return false, errors.Wrap(err, "failed to close compressed temp file")
+		return "", errors.Wrap(err, "failed to close compressed temp file")
 	}
 
-	// Phase 3: decompress into a temporary database file, then rename atomically.
+	// Phase 3: decompress into another temporary file.
 	compressedFile, err := os.Open(compressedTmpPath)
 	if err != nil {
-		return false, errors.Wrap(err, "failed to reopen compressed temp file")
+		return "", errors.Wrap(err, "failed to reopen compressed temp file")
 	}
 	defer compressedFile.Close()
 
 	gzReader, err := gzip.NewReader(compressedFile)
 	if err != nil {
-		return false, errors.Wrap(err, "failed to create gzip reader")
+		return "", errors.Wrap(err, "failed to create gzip reader")
 	}
 	defer gzReader.Close()
 
-	restoredTmp, err := os.CreateTemp(dbDir, "pelican-restore-db-*.tmp")
+	restoredTmp, err := os.CreateTemp(dir, "pelican-restore-db-*.tmp")
 	if err != nil {
-		return false, errors.Wrap(err, "failed to create temp file for restored database")
+		return "", errors.Wrap(err, "failed to create temp file for restored database")
 	}
 	restoredTmpPath := restoredTmp.Name()
-	defer os.Remove(restoredTmpPath)
 
 	if _, err := io.Copy(restoredTmp, gzReader); err != nil {
 		restoredTmp.Close()
-		return false, errors.Wrap(err, "failed to decompress backup data")
+		os.Remove(restoredTmpPath)
+		return "", errors.Wrap(err, "failed to decompress backup data")
 	}
 	if err := restoredTmp.Sync(); err != nil {
 		restoredTmp.Close()
-		return false, errors.Wrap(err, "failed to sync restored database file")
+		os.Remove(restoredTmpPath)
+		return "", errors.Wrap(err, "failed to sync restored database file")
 	}
 	if err := restoredTmp.Close(); err != nil {
-		return false, errors.Wrap(err, "failed to close restored database file")
+		os.Remove(restoredTmpPath)
+		return "", errors.Wrap(err, "failed to close restored database file")
+	}
+
+	return restoredTmpPath, nil
+}
+
+// restoreFromSingleBackup attempts to restore the database from a single
+// backup file, atomically placing the restored database at dbPath. A
+// logical dump (from a server running on PostgreSQL) is loaded into a new
+// SQLite database first.
+func restoreFromSingleBackup(dbPath, backupPath string, issuerKeys map[string]jwk.Key) (bool, error) {
+	dbDir := filepath.Dir(dbPath)
+	if err := os.MkdirAll(dbDir, 0755); err != nil {
+		return false, errors.Wrap(err, "failed to create directory for restored database")
+	}
+
+	restoredTmpPath, err := decryptBackup(backupPath, dbDir, issuerKeys)
+	if err != nil {
+		return false, err
+	}
+	defer os.Remove(restoredTmpPath)
+
+	isDump, err := isLogicalDump(restoredTmpPath)
+	if err != nil {
+		return false, err
+	}
+	if isDump {
+		sqlitePath, err := loadDumpIntoSQLite(restoredTmpPath, dbDir)
+		if sqlitePath != "" {
+			defer os.Remove(sqlitePath)
+		}
+		if err != nil {
+			return false, err
+		}
+		restoredTmpPath = sqlitePath
 	}
 
 	// Verify the restored file is a valid SQLite database before putting it in place.
@@ -899,6 +1122,37 @@ func restoreFromSingleBackup(dbPath, backupPath string, issuerKeys map[string]jw
 	return true, nil
 }
 
+// loadDumpIntoSQLite creates a new SQLite database in dir holding the
+// contents of the logical dump at dumpPath and returns its path.
+func loadDumpIntoSQLite(dumpPath, dir string) (string, error) {
+	sqliteFile, err := os.CreateTemp(dir, "pelican-restore-db-*.sqlite")
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create temp file for restored database")
+	}
+	sqlitePath := sqliteFile.Name()
+	sqliteFile.Close()
+
+	db, err := utils.InitSQLiteDB(sqlitePath)
+	if err != nil {
+		return sqlitePath, err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return sqlitePath, err
+	}
+	defer sqlDB.Close()
+
+	dumpFile, err := os.Open(dumpPath)
+	if err != nil {
+		return sqlitePath, err
+	}
+	defer dumpFile.Close()
+	if err := loadDatabase(context.Background(), sqlDB, utils.DbTypeSQLite, dumpFile, false); err != nil {
+		return sqlitePath, errors.Wrap(err, "failed to load the logical dump")
+	}
+	return sqlitePath, errors.Wrap(sqlDB.Close(), "failed to close restored database")
+}
+
 // verifySQLiteIntegrity opens the file at dbPath as a SQLite database and
 // runs PRAGMA integrity_check to verify it is not corrupted. This prevents
 // restoring a backup that was damaged during storage or decryption.
@@ -943,10 +1197,19 @@ func RestoreFromBackup(dbPath string) (bool, error) {
 }
 
 // RestoreFromSpecificBackup restores the database from a specific backup file.
-// If force is true, the existing database is backed up then overwritten.
+// An empty dbPath selects the configured database, which may be PostgreSQL.
+// If force is true, an existing SQLite database is backed up then
+// overwritten, while the contents of a PostgreSQL database are replaced.
 // Returns an error if the database already exists and force is false.
 func RestoreFromSpecificBackup(dbPath, backupPath string, force bool) error {
 	if dbPath == "" {
+		dbType, err := utils.ParseDbType(param.Server_DbType.GetString())
+		if err != nil {
+			return err
+		}
+		if dbType == utils.DbTypePostgres {
+			return restoreToPostgres(backupPath, force)
+		}
 		dbPath = param.Server_DbLocation.GetString()
 	}
 	if dbPath == "" {
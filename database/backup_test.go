@@ -42,16 +42,11 @@ import (
 	"github.com/pelicanplatform/pelican/param"
 )
 
-// setupTestDBAndKeys creates a temporary database, generates issuer keys,
-// and configures viper params for backup testing.
-func setupTestDBAndKeys(t *testing.T) (dbPath, backupDir, keysDir string) {
+// setupTestIssuerKeys generates an issuer key in keysDir and loads it as
+// the server's issuer key.
+func setupTestIssuerKeys(t *testing.T, keysDir string) {
 	t.Helper()
 
-	tmpDir := t.TempDir()
-	dbPath = filepath.Join(tmpDir, "test.sqlite")
-	backupDir = filepath.Join(tmpDir, "backups")
-	keysDir = filepath.Join(tmpDir, "keys")
-
 	require.NoError(t, os.MkdirAll(keysDir, 0750))
 
 	// Configure keys directory and generate an issuer key
@@ -64,6 +59,19 @@ func setupTestDBAndKeys(t *testing.T) (dbPath, backupDir, keysDir string) {
 	key, err := config.GetIssuerPrivateJWK()
 	require.NoError(t, err)
 	require.NotNil(t, key)
+}
+
+// setupTestDBAndKeys creates a temporary database, generates issuer keys,
+// and configures viper params for backup testing.
+func setupTestDBAndKeys(t *testing.T) (dbPath, backupDir, keysDir string) {
+	t.Helper()
+
+	tmpDir := t.TempDir()
+	dbPath = filepath.Join(tmpDir, "test.sqlite")
+	backupDir = filepath.Join(tmpDir, "backups")
+	keysDir = filepath.Join(tmpDir, "keys")
+
+	setupTestIssuerKeys(t, keysDir)
 
 	// Initialize the database
 	db, err := utils.InitSQLiteDB(dbPath)
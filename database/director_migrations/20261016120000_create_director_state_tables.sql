@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS director_server_ads (
+    url TEXT PRIMARY KEY,
+    advertisement BLOB NOT NULL,
+    expires_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_director_server_ads_expires_at ON director_server_ads(expires_at);
+CREATE TABLE IF NOT EXISTS director_state (
+    key TEXT PRIMARY KEY,
+    value BLOB NOT NULL,
+    updated_at DATETIME NOT NULL
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS director_state;
+DROP INDEX IF EXISTS idx_director_server_ads_expires_at;
+DROP TABLE IF EXISTS director_server_ads;
+-- +goose StatementEnd
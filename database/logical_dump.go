@@ -0,0 +1,614 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package database
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/pelicanplatform/pelican/database/utils"
+)
+
+// A logical dump is a stream of JSON records, one per line: a header,
+// then for each table a record naming its columns followed by one record
+// per row.  Tables are written parents-first so the rows can be inserted
+// with foreign keys enforced.  Unlike a copy of the SQLite file, a dump can
+// be loaded into either database type.
+const (
+	logicalDumpFormat  = "pelican-logical-dump"
+	logicalDumpVersion = 1
+
+	gooseVersionTable = "goose_db_version"
+)
+
+type dumpRecord struct {
+	// Header fields
+	Format  string `json:"format,omitempty"`
+	Version int    `json:"version,omitempty"`
+	Dialect string `json:"dialect,omitempty"`
+	// Migrations lists the migration sets (keys of migrationSets) that had
+	// been applied to the dumped database.
+	Migrations []string `json:"migrations,omitempty"`
+
+	// Table fields
+	Table   string   `json:"table,omitempty"`
+	Columns []string `json:"columns,omitempty"`
+
+	// Row fields
+	Row []dumpValue `json:"row,omitempty"`
+}
+
+// dumpValue holds a single column value; exactly one field is set, or none
+// for NULL.  Keeping the type lets integers, blobs and timestamps survive
+// the trip through JSON.
+type dumpValue struct {
+	Int    *int64     `json:"i,omitempty"`
+	Float  *float64   `json:"f,omitempty"`
+	Bool   *bool      `json:"b,omitempty"`
+	String *string    `json:"s,omitempty"`
+	Bytes  *[]byte    `json:"x,omitempty"`
+	Time   *time.Time `json:"t,omitempty"`
+}
+
+type sqlQueryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+func newDumpValue(value any) (dumpValue, error) {
+	switch v := value.(type) {
+	case nil:
+		return dumpValue{}, nil
+	case int64:
+		return dumpValue{Int: &v}, nil
+	case int32:
+		i := int64(v)
+		return dumpValue{Int: &i}, nil
+	case float64:
+		return dumpValue{Float: &v}, nil
+	case float32:
+		f := float64(v)
+		return dumpValue{Float: &f}, nil
+	case bool:
+		return dumpValue{Bool: &v}, nil
+	case string:
+		return dumpValue{String: &v}, nil
+	case []byte:
+		b := bytes.Clone(v)
+		return dumpValue{Bytes: &b}, nil
+	case time.Time:
+		return dumpValue{Time: &v}, nil
+	}
+	return dumpValue{}, errors.Errorf("unsupported column value type %T", value)
+}
+
+func (v dumpValue) value() any {
+	switch {
+	case v.Int != nil:
+		return *v.Int
+	case v.Float != nil:
+		return *v.Float
+	case v.Bool != nil:
+		return *v.Bool
+	case v.String != nil:
+		return *v.String
+	case v.Bytes != nil:
+		return *v.Bytes
+	case v.Time != nil:
+		return *v.Time
+	}
+	return nil
+}
+
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// migrationSetForTable returns the migration set whose progress is recorded
+// in the given goose version table.
+func migrationSetForTable(table string) (string, bool) {
+	if table == gooseVersionTable {
+		return "universal", true
+	}
+	if prefix, ok := strings.CutSuffix(table, "_"+gooseVersionTable); ok {
+		return prefix, true
+	}
+	return "", false
+}
+
+// listTables returns the names of the tables in the database, sorted.  The
+// goose version tables are only included if includeGoose is set.
+func listTables(ctx context.Context, q sqlQueryer, dbType utils.DbType, includeGoose bool) ([]string, error) {
+	query := `SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%'`
+	if dbType == utils.DbTypePostgres {
+		query = `SELECT table_name FROM information_schema.tables
+			WHERE table_schema = current_schema() AND table_type = 'BASE TABLE'`
+	}
+	rows, err := q.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tables := []string{}
+	for rows.Next() {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			return nil, err
+		}
+		if _, isGoose := migrationSetForTable(table); isGoose && !includeGoose {
+			continue
+		}
+		tables = append(tables, table)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	sort.Strings(tables)
+	return tables, nil
+}
+
+// foreignKeyParents maps each table to the tables its foreign keys reference.
+func foreignKeyParents(ctx context.Context, q sqlQueryer, dbType utils.DbType, tables []string) (map[string][]string, error) {
+	parents := map[string][]string{}
+	scan := func(rows *sql.Rows, child string) error {
+		defer rows.Close()
+		for rows.Next() {
+			var table, parent string
+			if child == "" {
+				if err := rows.Scan(&table, &parent); err != nil {
+					return err
+				}
+			} else {
+				table = child
+				if err := rows.Scan(&parent); err != nil {
+					return err
+				}
+			}
+			parents[table] = append(parents[table], parent)
+		}
+		return rows.Err()
+	}
+
+	if dbType == utils.DbTypePostgres {
+		rows, err := q.QueryContext(ctx, `SELECT tc.table_name, ccu.table_name
+			FROM information_schema.table_constraints tc
+			JOIN information_schema.constraint_column_usage ccu
+				ON tc.constraint_schema = ccu.constraint_schema AND tc.constraint_name = ccu.constraint_name
+			WHERE tc.constraint_type = 'FOREIGN KEY' AND tc.table_schema = current_schema()`)
+		if err != nil {
+			return nil, err
+		}
+		if err := scan(rows, ""); err != nil {
+			return nil, err
+		}
+	} else {
+		for _, table := range tables {
+			rows, err := q.QueryContext(ctx, `SELECT "table" FROM pragma_foreign_key_list(?)`, table)
+			if err != nil {
+				return nil, err
+			}
+			if err := scan(rows, table); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for table := range parents {
+		sort.Strings(parents[table])
+	}
+	return parents, nil
+}
+
+// sortTablesByDependency orders tables so that every table comes after the
+// tables it references.  Self-references are ignored and cycles are broken
+// arbitrarily; our schemas have neither.
+func sortTablesByDependency(tables []string, parents map[string][]string) []string {
+	inSet := make(map[string]bool, len(tables))
+	for _, table := range tables {
+		inSet[table] = true
+	}
+	visited := map[string]bool{}
+	ordered := make([]string, 0, len(tables))
+	var visit func(table string)
+	visit = func(table string) {
+		if visited[table] {
+			return
+		}
+		visited[table] = true
+		for _, parent := range parents[table] {
+			if parent != table && inSet[parent] {
+				visit(parent)
+			}
+		}
+		ordered = append(ordered, table)
+	}
+	for _, table := range tables {
+		visit(table)
+	}
+	return ordered
+}
+
+// dumpDatabase writes a logical dump of every table except the goose
+// version tables to w, reading from a single consistent snapshot.
+func dumpDatabase(ctx context.Context, db *sql.DB, dbType utils.DbType, w io.Writer) error {
+	var txOpts *sql.TxOptions
+	if dbType == utils.DbTypePostgres {
+		txOpts = &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}
+	}
+	tx, err := db.BeginTx(ctx, txOpts)
+	if err != nil {
+		return errors.Wrap(err, "failed to start the dump transaction")
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	allTables, err := listTables(ctx, tx, dbType, true)
+	if err != nil {
+		return errors.Wrap(err, "failed to list tables")
+	}
+	header := dumpRecord{Format: logicalDumpFormat, Version: logicalDumpVersion, Dialect: string(dbType)}
+	tables := []string{}
+	for _, table := range allTables {
+		if set, isGoose := migrationSetForTable(table); isGoose {
+			header.Migrations = append(header.Migrations, set)
+		} else {
+			tables = append(tables, table)
+		}
+	}
+	// The universal migrations must run before the server-specific ones
+	sort.SliceStable(header.Migrations, func(i, j int) bool {
+		return header.Migrations[i] == "universal" && header.Migrations[j] != "universal"
+	})
+
+	parents, err := foreignKeyParents(ctx, tx, dbType, tables)
+	if err != nil {
+		return errors.Wrap(err, "failed to read foreign keys")
+	}
+
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	if err := enc.Encode(header); err != nil {
+		return err
+	}
+	for _, table := range sortTablesByDependency(tables, parents) {
+		if err := dumpTable(ctx, tx, table, enc); err != nil {
+			return errors.Wrapf(err, "failed to dump table %s", table)
+		}
+	}
+	return bw.Flush()
+}
+
+func dumpTable(ctx context.Context, tx *sql.Tx, table string, enc *json.Encoder) error {
+	rows, err := tx.QueryContext(ctx, "SELECT * FROM "+quoteIdentifier(table))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	if err := enc.Encode(dumpRecord{Table: table, Columns: columns}); err != nil {
+		return err
+	}
+
+	values := make([]any, len(columns))
+	dest := make([]any, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return err
+		}
+		record := dumpRecord{Row: make([]dumpValue, len(columns))}
+		for i, value := range values {
+			if record.Row[i], err = newDumpValue(value); err != nil {
+				return errors.Wrapf(err, "column %s", columns[i])
+			}
+		}
+		if err := enc.Encode(record); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// loadDatabase loads a logical dump into db.  The migration sets recorded in
+// the dump are applied first so the schema exists.  If replace is set, the
+// existing rows of every table are deleted; otherwise the tables are
+// expected to be empty.  The data is loaded in a single transaction.
+func loadDatabase(ctx context.Context, db *sql.DB, dbType utils.DbType, r io.Reader, replace bool) error {
+	dec := json.NewDecoder(bufio.NewReader(r))
+	var header dumpRecord
+	if err := dec.Decode(&header); err != nil {
+		return errors.Wrap(err, "failed to read the dump header")
+	}
+	if header.Format != logicalDumpFormat {
+		return errors.New("not a Pelican logical database dump")
+	}
+	if header.Version > logicalDumpVersion {
+		return errors.Errorf("logical dump version %d is newer than the supported version %d", header.Version, logicalDumpVersion)
+	}
+
+	for _, set := range header.Migrations {
+		if _, ok := migrationSets[set]; !ok {
+			log.Warnf("Skipping unknown migration set %q recorded in the database dump", set)
+			continue
+		}
+		if err := runMigrationSet(db, dbType, set); err != nil {
+			return errors.Wrapf(err, "failed to run the %s migrations", set)
+		}
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to start the restore transaction")
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if replace {
+		if err := deleteAllRows(ctx, tx, dbType); err != nil {
+			return err
+		}
+	}
+
+	var (
+		table       string
+		insert      *sql.Stmt
+		columnTypes []string
+		rowCount    int
+	)
+	closeInsert := func() {
+		if insert != nil {
+			insert.Close()
+			log.Debugf("Restored %d rows into table %s", rowCount, table)
+		}
+	}
+	defer closeInsert()
+
+	for {
+		var record dumpRecord
+		if err := dec.Decode(&record); err == io.EOF {
+			break
+		} else if err != nil {
+			return errors.Wrap(err, "failed to read the dump")
+		}
+
+		switch {
+		case record.Table != "":
+			closeInsert()
+			insert, table, rowCount = nil, record.Table, 0
+			if columnTypes, err = destinationColumnTypes(ctx, tx, record.Table, record.Columns); err != nil {
+				return errors.Wrapf(err, "table %s in the dump does not match the database schema", record.Table)
+			}
+			if insert, err = tx.PrepareContext(ctx, insertStatement(dbType, record.Table, record.Columns)); err != nil {
+				return errors.Wrapf(err, "failed to prepare the insert into table %s", record.Table)
+			}
+		case record.Row != nil:
+			if insert == nil {
+				return errors.New("the dump contains a row before any table")
+			}
+			if len(record.Row) != len(columnTypes) {
+				return errors.Errorf("row %d of table %s has %d columns; expected %d", rowCount+1, table, len(record.Row), len(columnTypes))
+			}
+			args := make([]any, len(record.Row))
+			for i, value := range record.Row {
+				if args[i], err = convertColumnValue(value.value(), columnTypes[i]); err != nil {
+					return errors.Wrapf(err, "row %d of table %s", rowCount+1, table)
+				}
+			}
+			if _, err := insert.ExecContext(ctx, args...); err != nil {
+				return errors.Wrapf(err, "failed to insert row %d of table %s", rowCount+1, table)
+			}
+			rowCount++
+		}
+	}
+
+	if dbType == utils.DbTypePostgres {
+		if err := resetPostgresSequences(ctx, tx); err != nil {
+			return err
+		}
+	}
+	return errors.Wrap(tx.Commit(), "failed to commit the restored data")
+}
+
+// deleteAllRows empties every table except the goose version tables,
+// children first.
+func deleteAllRows(ctx context.Context, tx *sql.Tx, dbType utils.DbType) error {
+	tables, err := listTables(ctx, tx, dbType, false)
+	if err != nil {
+		return errors.Wrap(err, "failed to list tables")
+	}
+	parents, err := foreignKeyParents(ctx, tx, dbType, tables)
+	if err != nil {
+		return errors.Wrap(err, "failed to read foreign keys")
+	}
+	ordered := sortTablesByDependency(tables, parents)
+	for i := len(ordered) - 1; i >= 0; i-- {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM "+quoteIdentifier(ordered[i])); err != nil {
+			return errors.Wrapf(err, "failed to empty table %s", ordered[i])
+		}
+	}
+	return nil
+}
+
+// destinationColumnTypes returns the database type name of each column.
+func destinationColumnTypes(ctx context.Context, tx *sql.Tx, table string, columns []string) ([]string, error) {
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = quoteIdentifier(column)
+	}
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf("SELECT %s FROM %s WHERE 1 = 0", strings.Join(quoted, ", "), quoteIdentifier(table)))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(types))
+	for i, columnType := range types {
+		names[i] = strings.ToUpper(columnType.DatabaseTypeName())
+	}
+	return names, nil
+}
+
+func insertStatement(dbType utils.DbType, table string, columns []string) string {
+	quoted := make([]string, len(columns))
+	placeholders := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = quoteIdentifier(column)
+		if dbType == utils.DbTypePostgres {
+			placeholders[i] = "$" + strconv.Itoa(i+1)
+		} else {
+			placeholders[i] = "?"
+		}
+	}
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", quoteIdentifier(table), strings.Join(quoted, ", "), strings.Join(placeholders, ", "))
+}
+
+// dumpTimeLayouts are the formats SQLite stores timestamps in when they
+// come back as text (e.g. the result of DEFAULT CURRENT_TIMESTAMP).
+var dumpTimeLayouts = []string{
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02T15:04:05.999999999-07:00",
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// convertColumnValue adapts a dumped value to the type of the column it is
+// loaded into.  SQLite is loosely typed, so a dump taken from it may hold
+// integers for booleans or text for timestamps, which PostgreSQL rejects.
+func convertColumnValue(value any, columnType string) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+	switch {
+	case strings.HasPrefix(columnType, "BOOL"):
+		switch v := value.(type) {
+		case int64:
+			return v != 0, nil
+		case string:
+			return strconv.ParseBool(v)
+		}
+	case strings.HasPrefix(columnType, "INT") && columnType != "INTERVAL", strings.HasSuffix(columnType, "INT"):
+		switch v := value.(type) {
+		case bool:
+			if v {
+				return int64(1), nil
+			}
+			return int64(0), nil
+		case float64:
+			if v == math.Trunc(v) {
+				return int64(v), nil
+			}
+		case string:
+			return strconv.ParseInt(v, 10, 64)
+		}
+	case strings.HasPrefix(columnType, "TIMESTAMP"), columnType == "DATETIME", columnType == "DATE":
+		if v, ok := value.(string); ok {
+			for _, layout := range dumpTimeLayouts {
+				if t, err := time.Parse(layout, v); err == nil {
+					return t, nil
+				}
+			}
+			return nil, errors.Errorf("cannot parse %q as a timestamp", v)
+		}
+	case columnType == "BYTEA", columnType == "BLOB":
+		if v, ok := value.(string); ok {
+			return []byte(v), nil
+		}
+	case columnType == "TEXT", strings.Contains(columnType, "CHAR"):
+		if v, ok := value.([]byte); ok {
+			return string(v), nil
+		}
+	}
+	return value, nil
+}
+
+// resetPostgresSequences moves every serial column's sequence past the
+// largest restored value; rows inserted with explicit IDs don't advance it.
+func resetPostgresSequences(ctx context.Context, tx *sql.Tx) error {
+	rows, err := tx.QueryContext(ctx, `SELECT table_name, column_name FROM information_schema.columns
+		WHERE table_schema = current_schema() AND column_default LIKE 'nextval(%'`)
+	if err != nil {
+		return errors.Wrap(err, "failed to list serial columns")
+	}
+	type serialColumn struct{ table, column string }
+	serials := []serialColumn{}
+	for rows.Next() {
+		var serial serialColumn
+		if err := rows.Scan(&serial.table, &serial.column); err != nil {
+			rows.Close()
+			return err
+		}
+		serials = append(serials, serial)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, serial := range serials {
+		query := fmt.Sprintf("SELECT setval(pg_get_serial_sequence($1, $2), COALESCE((SELECT MAX(%s) FROM %s), 0) + 1, false)",
+			quoteIdentifier(serial.column), quoteIdentifier(serial.table))
+		if _, err := tx.ExecContext(ctx, query, quoteIdentifier(serial.table), serial.column); err != nil {
+			return errors.Wrapf(err, "failed to reset the sequence of %s.%s", serial.table, serial.column)
+		}
+	}
+	return nil
+}
+
+// isLogicalDump reports whether the decrypted backup payload at path is a
+// logical dump rather than a SQLite database file.
+func isLogicalDump(path string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+	prefix := make([]byte, 1)
+	if _, err := io.ReadFull(f, prefix); err != nil {
+		return false, errors.Wrap(err, "backup payload is empty")
+	}
+	return prefix[0] == '{', nil
+}
@@ -0,0 +1,242 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package database
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/pelicanplatform/pelican/config"
+	"github.com/pelicanplatform/pelican/database/utils"
+)
+
+// newMigratedTestDB opens a database at dbPath with every migration set
+// applied.
+func newMigratedTestDB(t *testing.T, dbPath string) (*gorm.DB, *sql.DB) {
+	t.Helper()
+	db, err := utils.InitSQLiteDB(dbPath)
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		sqlDB.Close()
+	})
+	for _, set := range []string{"universal", "registry", "origin", "director"} {
+		require.NoError(t, runMigrationSet(sqlDB, utils.DbTypeSQLite, set))
+	}
+	return db, sqlDB
+}
+
+// insertDumpTestData fills tables that cover every column type used by the
+// migrations, including rows linked by foreign keys.
+func insertDumpTestData(t *testing.T, db *gorm.DB) {
+	t.Helper()
+	updatedAt := time.Date(2026, 10, 17, 12, 30, 0, 0, time.UTC)
+	for _, stmt := range []struct {
+		query string
+		args  []any
+	}{
+		{`INSERT INTO registrations (prefix, pubkey, identity) VALUES (?, ?, ?)`, []any{"/foo", "{\"keys\":[]}", "alice"}},
+		{`INSERT INTO registrations (prefix, pubkey) VALUES (?, ?)`, []any{"/foo/bar", "{\"keys\":[]}"}},
+		{`INSERT INTO servers (id, name, is_origin, is_cache) VALUES (?, ?, ?, ?)`, []any{"srv1", "origin-1", true, false}},
+		{`INSERT INTO services (server_id, registration_id) VALUES (?, ?)`, []any{"srv1", 2}},
+		{`INSERT INTO downtimes (uuid, created_by, updated_by, server_name, source, class, severity, start_time, end_time)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`, []any{"dt1", "admin", "admin", "origin-1", "origin", "SCHEDULED", "Outage", int64(1760000000000), int64(1760003600000)}},
+		{`INSERT INTO server_master_keys (key_fingerprint, encrypted_master_key) VALUES (?, ?)`, []any{"fp1", []byte{0x00, 0xff, 0x10}}},
+		{`INSERT INTO director_state (key, value, updated_at) VALUES (?, ?, ?)`, []any{"state", []byte("{}"), updatedAt}},
+	} {
+		require.NoError(t, db.Exec(stmt.query, stmt.args...).Error, stmt.query)
+	}
+}
+
+func TestSortTablesByDependency(t *testing.T) {
+	parents := map[string][]string{
+		"services":  {"registrations", "servers"},
+		"endpoints": {"servers"},
+		"servers":   {"servers"},
+	}
+	ordered := sortTablesByDependency([]string{"endpoints", "registrations", "servers", "services"}, parents)
+	assert.Equal(t, []string{"servers", "endpoints", "registrations", "services"}, ordered)
+}
+
+func TestConvertColumnValue(t *testing.T) {
+	ts := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		name       string
+		value      any
+		columnType string
+		expected   any
+	}{
+		{"NullStaysNull", nil, "BOOL", nil},
+		{"IntToBool", int64(1), "BOOL", true},
+		{"BoolToInt", true, "INTEGER", int64(1)},
+		{"TextToTimestamp", "2026-10-17 12:00:00", "TIMESTAMPTZ", ts},
+		{"TextWithZoneToTimestamp", "2026-10-17 12:00:00+00:00", "TIMESTAMPTZ", ts},
+		{"TextToBytea", "abc", "BYTEA", []byte("abc")},
+		{"BlobToText", []byte("abc"), "TEXT", "abc"},
+		{"Unchanged", int64(5), "INT8", int64(5)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			converted, err := convertColumnValue(tc.value, tc.columnType)
+			require.NoError(t, err)
+			if expectedTime, ok := tc.expected.(time.Time); ok {
+				assert.True(t, expectedTime.Equal(converted.(time.Time)))
+			} else {
+				assert.Equal(t, tc.expected, converted)
+			}
+		})
+	}
+
+	_, err := convertColumnValue("yesterday", "TIMESTAMPTZ")
+	assert.Error(t, err)
+}
+
+func TestLogicalDumpRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	srcDB, srcSQL := newMigratedTestDB(t, filepath.Join(t.TempDir(), "src.sqlite"))
+	insertDumpTestData(t, srcDB)
+
+	var dump bytes.Buffer
+	require.NoError(t, dumpDatabase(ctx, srcSQL, utils.DbTypeSQLite, &dump))
+
+	// The destination starts out without any schema; loading the dump runs
+	// the migration sets recorded in it.
+	dstDB, err := utils.InitSQLiteDB(filepath.Join(t.TempDir(), "dst.sqlite"))
+	require.NoError(t, err)
+	dstSQL, err := dstDB.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		dstSQL.Close()
+	})
+	require.NoError(t, loadDatabase(ctx, dstSQL, utils.DbTypeSQLite, bytes.NewReader(dump.Bytes()), false))
+
+	var redump bytes.Buffer
+	require.NoError(t, dumpDatabase(ctx, dstSQL, utils.DbTypeSQLite, &redump))
+	assert.Equal(t, dump.String(), redump.String())
+
+	var key []byte
+	require.NoError(t, dstDB.Raw("SELECT encrypted_master_key FROM server_master_keys WHERE key_fingerprint = ?", "fp1").Row().Scan(&key))
+	assert.Equal(t, []byte{0x00, 0xff, 0x10}, key)
+
+	// New rows keep getting fresh IDs after the restore
+	require.NoError(t, dstDB.Exec("INSERT INTO registrations (prefix, pubkey) VALUES (?, ?)", "/baz", "{}").Error)
+	var id int64
+	require.NoError(t, dstDB.Raw("SELECT id FROM registrations WHERE prefix = ?", "/baz").Row().Scan(&id))
+	assert.Equal(t, int64(3), id)
+
+	t.Run("LoadingTwiceFails", func(t *testing.T) {
+		err := loadDatabase(ctx, dstSQL, utils.DbTypeSQLite, bytes.NewReader(dump.Bytes()), false)
+		assert.Error(t, err)
+	})
+
+	t.Run("ReplaceOverwritesRows", func(t *testing.T) {
+		require.NoError(t, loadDatabase(ctx, dstSQL, utils.DbTypeSQLite, bytes.NewReader(dump.Bytes()), true))
+		var count int64
+		require.NoError(t, dstDB.Raw("SELECT COUNT(*) FROM registrations").Row().Scan(&count))
+		assert.Equal(t, int64(2), count)
+	})
+
+	t.Run("RejectsOtherData", func(t *testing.T) {
+		err := loadDatabase(ctx, dstSQL, utils.DbTypeSQLite, bytes.NewReader([]byte(`{"format":"something-else"}`)), false)
+		assert.Error(t, err)
+	})
+}
+
+// TestRestoreBackupLogically covers the path used to move an existing SQLite
+// server to PostgreSQL: a physical SQLite backup is converted to a logical
+// dump and loaded into another database.
+func TestRestoreBackupLogically(t *testing.T) {
+	config.ResetConfig()
+	t.Cleanup(func() {
+		config.ResetConfig()
+	})
+
+	_, backupDir, _ := setupTestDBAndKeys(t)
+	require.NoError(t, ServerDatabase.Exec("DROP TABLE test_data").Error)
+	sqlDB, err := ServerDatabase.DB()
+	require.NoError(t, err)
+	for _, set := range []string{"universal", "registry"} {
+		require.NoError(t, runMigrationSet(sqlDB, utils.DbTypeSQLite, set))
+	}
+	require.NoError(t, ServerDatabase.Exec(`INSERT INTO registrations (prefix, pubkey) VALUES ('/foo', '{}')`).Error)
+	require.NoError(t, createBackup(context.Background()))
+
+	backups, err := ListBackups()
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+	assert.Equal(t, "sqlite", backups[0].Metadata.DatabaseType)
+
+	dstDB, err := utils.InitSQLiteDB(filepath.Join(t.TempDir(), "dst.sqlite"))
+	require.NoError(t, err)
+	dstSQL, err := dstDB.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		dstSQL.Close()
+	})
+	require.NoError(t, restoreLogicalFromSingleBackup(context.Background(), dstSQL, utils.DbTypeSQLite, backups[0].Path, config.GetIssuerPrivateKeys(), false))
+
+	var prefix string
+	require.NoError(t, dstDB.Raw("SELECT prefix FROM registrations").Row().Scan(&prefix))
+	assert.Equal(t, "/foo", prefix)
+
+	// No decrypted data is left next to the backups
+	entries, err := os.ReadDir(backupDir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestLoadDumpIntoSQLite(t *testing.T) {
+	srcDB, srcSQL := newMigratedTestDB(t, filepath.Join(t.TempDir(), "src.sqlite"))
+	insertDumpTestData(t, srcDB)
+
+	dir := t.TempDir()
+	dumpPath := filepath.Join(dir, "dump.jsonl")
+	dumpFile, err := os.Create(dumpPath)
+	require.NoError(t, err)
+	require.NoError(t, dumpDatabase(context.Background(), srcSQL, utils.DbTypeSQLite, dumpFile))
+	require.NoError(t, dumpFile.Close())
+
+	isDump, err := isLogicalDump(dumpPath)
+	require.NoError(t, err)
+	assert.True(t, isDump)
+
+	sqlitePath, err := loadDumpIntoSQLite(dumpPath, dir)
+	require.NoError(t, err)
+	require.NoError(t, verifySQLiteIntegrity(sqlitePath))
+
+	isDump, err = isLogicalDump(sqlitePath)
+	require.NoError(t, err)
+	assert.False(t, isDump)
+
+	restored, err := sql.Open("sqlite", sqlitePath)
+	require.NoError(t, err)
+	defer restored.Close()
+	var count int
+	require.NoError(t, restored.QueryRow("SELECT COUNT(*) FROM services").Scan(&count))
+	assert.Equal(t, 1, count)
+}
@@ -130,7 +130,8 @@ func DeriveSubKey(masterKey []byte, purpose string, length int) ([]byte, error)
 // SaveMasterKeyRow inserts or replaces an encrypted master key row.
 func SaveMasterKeyRow(ctx context.Context, db *gorm.DB, fingerprint string, encryptedKey []byte) error {
 	return db.WithContext(ctx).Exec(
-		`INSERT OR REPLACE INTO server_master_keys (key_fingerprint, encrypted_master_key) VALUES (?, ?)`,
+		`INSERT INTO server_master_keys (key_fingerprint, encrypted_master_key) VALUES (?, ?)
+		 ON CONFLICT (key_fingerprint) DO UPDATE SET encrypted_master_key = excluded.encrypted_master_key`,
 		fingerprint, encryptedKey,
 	).Error
 }
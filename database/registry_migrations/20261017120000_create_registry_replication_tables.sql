@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Signed changes to registrations and servers, served to peer registries.
+-- Only the latest change of each record is kept.
+CREATE TABLE IF NOT EXISTS registry_change_log (
+    seq INTEGER PRIMARY KEY AUTOINCREMENT,
+    entity TEXT NOT NULL,
+    entity_key TEXT NOT NULL,
+    signed_change TEXT NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_registry_change_log_entity ON registry_change_log(entity, entity_key);
+
+-- The version of the change each record (or its deletion) currently reflects
+CREATE TABLE IF NOT EXISTS registry_entity_versions (
+    entity TEXT NOT NULL,
+    entity_key TEXT NOT NULL,
+    version INTEGER NOT NULL,
+    origin TEXT NOT NULL,
+    deleted BOOLEAN NOT NULL DEFAULT FALSE,
+    PRIMARY KEY (entity, entity_key)
+);
+
+-- How far the change log of each peer registry has been applied
+CREATE TABLE IF NOT EXISTS registry_replication_peers (
+    peer TEXT PRIMARY KEY,
+    last_seq INTEGER NOT NULL DEFAULT 0,
+    caught_up_at DATETIME
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS registry_replication_peers;
+DROP TABLE IF EXISTS registry_entity_versions;
+DROP INDEX IF EXISTS idx_registry_change_log_entity;
+DROP TABLE IF EXISTS registry_change_log;
+-- +goose StatementEnd
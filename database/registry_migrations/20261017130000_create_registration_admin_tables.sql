@@ -0,0 +1,55 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Users, besides the owner in admin_metadata, allowed to manage a registration
+CREATE TABLE IF NOT EXISTS registration_admins (
+    registration_id INTEGER NOT NULL,
+    user_id TEXT NOT NULL,
+    added_by TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (registration_id, user_id),
+    FOREIGN KEY (registration_id) REFERENCES registrations(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_registration_admins_user_id ON registration_admins(user_id);
+
+-- Requests to hand a registration over to another owner, decided by a registry admin
+CREATE TABLE IF NOT EXISTS registration_transfers (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    registration_id INTEGER NOT NULL,
+    from_user_id TEXT NOT NULL,
+    to_user_id TEXT NOT NULL,
+    requested_by TEXT NOT NULL,
+    status TEXT NOT NULL,
+    decided_by TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    decided_at DATETIME,
+    FOREIGN KEY (registration_id) REFERENCES registrations(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_registration_transfers_registration_id ON registration_transfers(registration_id);
+
+-- Who changed what on each registration.  Entries outlive the registration
+-- they refer to, so there is no foreign key.
+CREATE TABLE IF NOT EXISTS registration_audit_log (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    registration_id INTEGER NOT NULL,
+    prefix TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    action TEXT NOT NULL,
+    details TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_registration_audit_log_registration_id ON registration_audit_log(registration_id);
+CREATE INDEX IF NOT EXISTS idx_registration_audit_log_user_id ON registration_audit_log(user_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_registration_audit_log_user_id;
+DROP INDEX IF EXISTS idx_registration_audit_log_registration_id;
+DROP TABLE IF EXISTS registration_audit_log;
+DROP INDEX IF EXISTS idx_registration_transfers_registration_id;
+DROP TABLE IF EXISTS registration_transfers;
+DROP INDEX IF EXISTS idx_registration_admins_user_id;
+DROP TABLE IF EXISTS registration_admins;
+-- +goose StatementEnd
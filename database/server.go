@@ -1,6 +1,7 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"embed"
 	"fmt"
@@ -29,34 +30,93 @@ var EmbedRegistryMigrations embed.FS
 //go:embed origin_migrations/*.sql
 var EmbedOriginMigrations embed.FS
 
+//go:embed director_migrations/*.sql
+var EmbedDirectorMigrations embed.FS
+
+// postgresMigrationLockId is the advisory lock key serializing migrations
+// between replicas sharing a PostgreSQL database ("PELICAN" in ASCII).
+const postgresMigrationLockId int64 = 0x50454c4943414e
+
 type Counter struct {
 	Key   string `gorm:"primaryKey"`
 	Value int    `gorm:"not null;default:0"`
 }
 
-// Initialize a centralized server database and run universal and server-type-specific migrations
-func InitServerDatabase(serverType server_structs.ServerType) error {
+// migrationSet is one of the embedded sets of goose migrations.  Each set
+// records its progress in its own goose version table.
+type migrationSet struct {
+	fs          embed.FS
+	path        string
+	tablePrefix string
+}
 
-	dbPath := param.Server_DbLocation.GetString()
-	log.Debugln("Initializing server database: ", dbPath)
+// migrationSets is keyed by the name recorded in logical dumps; "universal"
+// applies to every server type.
+var migrationSets = map[string]migrationSet{
+	"universal": {EmbedUniversalMigrations, "universal_migrations", ""},
+	"registry":  {EmbedRegistryMigrations, "registry_migrations", "registry"},
+	"origin":    {EmbedOriginMigrations, "origin_migrations", "origin"},
+	"director":  {EmbedDirectorMigrations, "director_migrations", "director"},
+}
 
-	// Attempt to restore from backup if the database file doesn't exist
-	restored, err := RestoreFromBackup(dbPath)
-	if err != nil {
-		log.Warnf("Failed to restore database from backup: %v", err)
-	} else if restored {
-		log.Info("Database restored from backup, proceeding with initialization")
+func runMigrationSet(sqlDB *sql.DB, dbType utils.DbType, name string) error {
+	set, ok := migrationSets[name]
+	if !ok {
+		return errors.Errorf("unknown migration set %q", name)
 	}
+	return utils.MigrateDBWithType(sqlDB, dbType, set.fs, set.path, set.tablePrefix)
+}
 
-	tdb, err := utils.InitSQLiteDB(dbPath)
+// Initialize a centralized server database and run universal and server-type-specific migrations
+func InitServerDatabase(serverType server_structs.ServerType) error {
+	dbType, err := utils.ParseDbType(param.Server_DbType.GetString())
 	if err != nil {
 		return err
 	}
-	ServerDatabase = tdb
 
-	// Enable foreign key constraints for SQLite
-	if err := ServerDatabase.Exec("PRAGMA foreign_keys = ON").Error; err != nil {
-		return errors.Wrap(err, "failed to enable foreign key constraints")
+	// A PostgreSQL database with no tables at all is a fresh install (or a
+	// replacement for a lost database); it is restored from the most recent
+	// backup once the schema exists.
+	restorePostgres := false
+	if dbType == utils.DbTypePostgres {
+		log.Debugln("Initializing server database: ", utils.DescribePostgresDSN(param.Server_DbUrl.GetString()))
+		tdb, err := utils.InitPostgresDB(param.Server_DbUrl.GetString(), param.Server_DbPasswordFile.GetString())
+		if err != nil {
+			return err
+		}
+		ServerDatabase = tdb
+
+		sqlDB, err := ServerDatabase.DB()
+		if err != nil {
+			return err
+		}
+		tables, err := listTables(context.Background(), sqlDB, dbType, true)
+		if err != nil {
+			return errors.Wrap(err, "failed to list the tables in the PostgreSQL database")
+		}
+		restorePostgres = len(tables) == 0
+	} else {
+		dbPath := param.Server_DbLocation.GetString()
+		log.Debugln("Initializing server database: ", dbPath)
+
+		// Attempt to restore from backup if the database file doesn't exist
+		restored, err := RestoreFromBackup(dbPath)
+		if err != nil {
+			log.Warnf("Failed to restore database from backup: %v", err)
+		} else if restored {
+			log.Info("Database restored from backup, proceeding with initialization")
+		}
+
+		tdb, err := utils.InitSQLiteDB(dbPath)
+		if err != nil {
+			return err
+		}
+		ServerDatabase = tdb
+
+		// Enable foreign key constraints for SQLite
+		if err := ServerDatabase.Exec("PRAGMA foreign_keys = ON").Error; err != nil {
+			return errors.Wrap(err, "failed to enable foreign key constraints")
+		}
 	}
 
 	sqlDB, err := ServerDatabase.DB()
@@ -64,16 +124,35 @@ func InitServerDatabase(serverType server_structs.ServerType) error {
 		return err
 	}
 
+	// Several replicas of an HA service may share one PostgreSQL database;
+	// only one of them may run the migrations at a time.
+	if dbType == utils.DbTypePostgres {
+		unlock, err := lockPostgresMigrations(context.Background(), sqlDB)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+	}
+
 	// Always run universal migrations first
-	if err := utils.MigrateDB(sqlDB, EmbedUniversalMigrations, "universal_migrations"); err != nil {
+	if err := runMigrationSet(sqlDB, dbType, "universal"); err != nil {
 		return err
 	}
 
 	// Apply server-type-specific migrations
-	if err := runServerTypeMigrations(sqlDB, serverType); err != nil {
+	if err := runServerTypeMigrations(sqlDB, dbType, serverType); err != nil {
 		return errors.Wrapf(err, "failed to run migrations for server type %s", serverType.String())
 	}
 
+	if restorePostgres {
+		restored, err := restoreLogicalFromBackup(context.Background(), sqlDB, dbType)
+		if err != nil {
+			log.Warnf("Failed to restore database from backup: %v", err)
+		} else if restored {
+			log.Info("Database restored from backup, proceeding with initialization")
+		}
+	}
+
 	// Data cleanup - remove stale entries in the `servers` and `services` tables
 	// They are caused by the damaged foreign key constraint in `services` table and we didn't update webUI to use server deletion API in time.
 	// See https://opensciencegrid.atlassian.net/browse/OPS-438
@@ -85,9 +164,12 @@ func InitServerDatabase(serverType server_structs.ServerType) error {
 
 	// Data migration - this block could be removed after the Registry upgrade is complete
 	if serverType == server_structs.RegistryType {
-		// Run data migration from old registry.sqlite to new pelican.sqlite
-		if err := migrateFromLegacyRegistryDB(); err != nil {
-			log.Warnf("Legacy registry database data migration failed: %v", err)
+		// Run data migration from old registry.sqlite to new pelican.sqlite.
+		// A registry that was moved to PostgreSQL has long since been upgraded.
+		if dbType == utils.DbTypeSQLite {
+			if err := migrateFromLegacyRegistryDB(); err != nil {
+				log.Warnf("Legacy registry database data migration failed: %v", err)
+			}
 		}
 
 		// Migrate existing namespace data to server tables
@@ -100,12 +182,14 @@ func InitServerDatabase(serverType server_structs.ServerType) error {
 	return nil
 }
 
-func runServerTypeMigrations(sqlDB *sql.DB, serverType server_structs.ServerType) error {
+func runServerTypeMigrations(sqlDB *sql.DB, dbType utils.DbType, serverType server_structs.ServerType) error {
 	switch serverType {
 	case server_structs.RegistryType:
-		return utils.MigrateServerSpecificDB(sqlDB, EmbedRegistryMigrations, "registry_migrations", "registry")
+		return runMigrationSet(sqlDB, dbType, "registry")
 	case server_structs.OriginType:
-		return utils.MigrateServerSpecificDB(sqlDB, EmbedOriginMigrations, "origin_migrations", "origin")
+		return runMigrationSet(sqlDB, dbType, "origin")
+	case server_structs.DirectorType:
+		return runMigrationSet(sqlDB, dbType, "director")
 	default:
 		log.Debugf("No specific migrations for server type: %s", serverType.String())
 	}
@@ -113,6 +197,25 @@ func runServerTypeMigrations(sqlDB *sql.DB, serverType server_structs.ServerType
 	return nil
 }
 
+// lockPostgresMigrations takes a session-level advisory lock on a dedicated
+// connection, blocking until any other replica has finished migrating.
+func lockPostgresMigrations(ctx context.Context, sqlDB *sql.DB) (func(), error) {
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get a connection for the migration lock")
+	}
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", postgresMigrationLockId); err != nil {
+		conn.Close()
+		return nil, errors.Wrap(err, "failed to acquire the migration lock")
+	}
+	return func() {
+		if _, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", postgresMigrationLockId); err != nil {
+			log.Warnf("Failed to release the database migration lock: %v", err)
+		}
+		conn.Close()
+	}, nil
+}
+
 func cleanupStaleServerEntries() error {
 	// Identify stale servers via missing/invalid registrations and remove their services and server rows
 	return ServerDatabase.Transaction(func(tx *gorm.DB) error {
@@ -399,17 +502,24 @@ func UpdateDowntime(uuid string, updatedDowntime *server_structs.Downtime) error
 	return nil
 }
 
+// Set the recurrence rule of a downtime entry by UUID.  Unlike UpdateDowntime,
+// this also saves an empty rule, turning the entry into a one-off downtime.
+func SetDowntimeRecurrence(uuid string, recurrence string) error {
+	return ServerDatabase.Model(&server_structs.Downtime{}).Where("uuid = ?", uuid).Update("recurrence", recurrence).Error
+}
+
 // Delete a downtime entry by UUID (hard delete)
 func DeleteDowntime(uuid string) error {
 	return ServerDatabase.Delete(&server_structs.Downtime{}, "uuid = ?", uuid).Error
 }
 
-// Retrieve all downtime entries where EndTime is later than the current UTC time.
+// Retrieve all downtime entries where EndTime is later than the current UTC time,
+// along with the recurring entries that have a current or future occurrence.
 func GetIncompleteDowntimes(source string) ([]server_structs.Downtime, error) {
 	var downtimes []server_structs.Downtime
 	currentTime := time.Now().UTC().UnixMilli()
 
-	query := ServerDatabase.Where("end_time > ? OR end_time = ?", currentTime, server_structs.IndefiniteEndTime)
+	query := ServerDatabase.Where("end_time > ? OR end_time = ? OR recurrence != ''", currentTime, server_structs.IndefiniteEndTime)
 
 	// If a source is provided, append it to the existing query.
 	if source != "" {
@@ -421,7 +531,15 @@ func GetIncompleteDowntimes(source string) ([]server_structs.Downtime, error) {
 		return nil, err
 	}
 
-	return downtimes, nil
+	// Drop the recurring entries whose last occurrence is over
+	incomplete := downtimes[:0]
+	for _, downtime := range downtimes {
+		if _, _, ok := downtime.CurrentWindow(currentTime); ok {
+			incomplete = append(incomplete, downtime)
+		}
+	}
+
+	return incomplete, nil
 }
 
 // Retrieve all downtime entries
@@ -82,6 +82,24 @@ func TestUpdateDowntime(t *testing.T) {
 		assert.Equal(t, "Planned upgrade", retrieved.Description)
 		assert.Equal(t, server_structs.IntermittentOutage, retrieved.Severity)
 	})
+
+	t.Run("set-and-clear-recurrence", func(t *testing.T) {
+		require.NoError(t, SetDowntimeRecurrence(mockDowntime.UUID, "FREQ=WEEKLY;BYDAY=TU"))
+		retrieved, err := GetDowntimeByUUID(mockDowntime.UUID)
+		require.NoError(t, err)
+		assert.Equal(t, "FREQ=WEEKLY;BYDAY=TU", retrieved.Recurrence)
+
+		// Partial updates keep the rule
+		require.NoError(t, UpdateDowntime(mockDowntime.UUID, &server_structs.Downtime{Description: "Patching"}))
+		retrieved, err = GetDowntimeByUUID(mockDowntime.UUID)
+		require.NoError(t, err)
+		assert.Equal(t, "FREQ=WEEKLY;BYDAY=TU", retrieved.Recurrence)
+
+		require.NoError(t, SetDowntimeRecurrence(mockDowntime.UUID, ""))
+		retrieved, err = GetDowntimeByUUID(mockDowntime.UUID)
+		require.NoError(t, err)
+		assert.Empty(t, retrieved.Recurrence)
+	})
 }
 
 func TestGetIncompleteDowntimes(t *testing.T) {
@@ -112,16 +130,31 @@ func TestGetIncompleteDowntimes(t *testing.T) {
 		EndTime:     currentTime - 3600000, // Ended an hour ago
 	}
 
+	// A weekly downtime whose first window is long over
+	recurringDowntime := pastDowntime
+	recurringDowntime.UUID = uuid.NewString()
+	recurringDowntime.Description = "Weekly patch window"
+	recurringDowntime.StartTime -= 30 * 24 * 3600000
+	recurringDowntime.EndTime -= 30 * 24 * 3600000
+	recurringDowntime.Recurrence = "FREQ=WEEKLY"
+	// A daily downtime whose last occurrence is over
+	endedDowntime := recurringDowntime
+	endedDowntime.UUID = uuid.NewString()
+	endedDowntime.Recurrence = "FREQ=DAILY;COUNT=3"
+
 	err := InsertMockDowntime(activeDowntime)
 	require.NoError(t, err)
 	err = InsertMockDowntime(pastDowntime)
 	require.NoError(t, err)
+	require.NoError(t, InsertMockDowntime(recurringDowntime))
+	require.NoError(t, InsertMockDowntime(endedDowntime))
 
 	t.Run("fetch-active-downtimes", func(t *testing.T) {
 		activeEntries, err := GetIncompleteDowntimes("")
 		require.NoError(t, err)
-		assert.Len(t, activeEntries, 1)
-		assert.Equal(t, activeDowntime.UUID, activeEntries[0].UUID)
+		require.Len(t, activeEntries, 2)
+		assert.ElementsMatch(t, []string{activeDowntime.UUID, recurringDowntime.UUID},
+			[]string{activeEntries[0].UUID, activeEntries[1].UUID})
 	})
 
 	t.Run("fetch-specific-downtime-by-uuid", func(t *testing.T) {
@@ -328,7 +361,7 @@ func createDBViaInitSQLiteDB(t *testing.T, dbPath string) (*sql.DB, func()) {
 	sqldb, err := gdb.DB()
 	require.NoError(t, err)
 
-	require.NoError(t, runServerTypeMigrations(sqldb, server_structs.RegistryType))
+	require.NoError(t, runServerTypeMigrations(sqldb, utils.DbTypeSQLite, server_structs.RegistryType))
 
 	cleanup := func() {
 		_ = ShutdownDB()
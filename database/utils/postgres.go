@@ -0,0 +1,410 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package utils
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// DbType identifies the SQL database engine backing a server's database
+type DbType string
+
+const (
+	DbTypeSQLite   DbType = "sqlite"
+	DbTypePostgres DbType = "postgres"
+)
+
+// postgresColumnTypes maps the SQLite column types used by the embedded
+// migrations to their PostgreSQL equivalents.  SQLite integers are always
+// 64-bit, so INTEGER maps to BIGINT.
+var postgresColumnTypes = map[string]string{
+	"INTEGER":  "BIGINT",
+	"DATETIME": "TIMESTAMPTZ",
+	"BLOB":     "BYTEA",
+}
+
+// postgresBooleanColumns lists the columns, as "table.column", that hold
+// booleans but were declared as INTEGER with a 0/1 default in migrations that
+// have already shipped.  SQLite doesn't distinguish the two, but PostgreSQL
+// rejects booleans written to a BIGINT column, so these become BOOLEAN.
+// oidc_clients_new is the table the composite primary key migration rebuilds
+// oidc_clients through.
+var postgresBooleanColumns = map[string]bool{
+	"oidc_clients.public":                     true,
+	"oidc_clients.dynamically_registered":     true,
+	"oidc_clients_new.public":                 true,
+	"oidc_clients_new.dynamically_registered": true,
+	"oidc_access_tokens.active":               true,
+	"oidc_refresh_tokens.active":              true,
+	"oidc_authorization_codes.active":         true,
+	"oidc_pkce_requests.active":               true,
+	"oidc_openid_sessions.active":             true,
+}
+
+// ParseDbType converts the value of Server.DbType into a DbType; an empty
+// string selects SQLite.
+func ParseDbType(dbType string) (DbType, error) {
+	switch strings.ToLower(dbType) {
+	case "", string(DbTypeSQLite):
+		return DbTypeSQLite, nil
+	case string(DbTypePostgres), "postgresql":
+		return DbTypePostgres, nil
+	}
+	return "", errors.Errorf("unknown database type %q; must be %q or %q", dbType, DbTypeSQLite, DbTypePostgres)
+}
+
+// DbTypeOf reports which database engine db is connected to.
+func DbTypeOf(db *gorm.DB) DbType {
+	if db.Dialector.Name() == "postgres" {
+		return DbTypePostgres
+	}
+	return DbTypeSQLite
+}
+
+func (t DbType) gooseDialect() string {
+	if t == DbTypePostgres {
+		return "postgres"
+	}
+	return "sqlite3"
+}
+
+// InitPostgresDB connects to the PostgreSQL database described by dsn, which
+// may be either a URL (postgres://user@host:5432/db?sslmode=verify-full) or a
+// libpq keyword/value string.  If passwordFile is set, the password it
+// contains overrides any in dsn.
+func InitPostgresDB(dsn string, passwordFile string) (*gorm.DB, error) {
+	if dsn == "" {
+		return nil, errors.New("PostgreSQL connection string is empty")
+	}
+
+	// Don't echo the DSN in errors; it may contain a password
+	connConfig, err := pgx.ParseConfig(dsn)
+	if err != nil {
+		return nil, errors.New("failed to parse the PostgreSQL connection string")
+	}
+	if passwordFile != "" {
+		password, err := os.ReadFile(passwordFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read the PostgreSQL password file")
+		}
+		connConfig.Password = strings.TrimSpace(string(password))
+	}
+
+	log.Debugf("Opening connection to PostgreSQL database %s on %s:%d", connConfig.Database, connConfig.Host, connConfig.Port)
+
+	sqlDB := stdlib.OpenDB(*connConfig)
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{Logger: newGormLogger()})
+	if err != nil {
+		sqlDB.Close()
+		return nil, errors.Wrapf(err, "failed to connect to PostgreSQL database %s on %s:%d", connConfig.Database, connConfig.Host, connConfig.Port)
+	}
+	return db, nil
+}
+
+// DescribePostgresDSN returns a printable "host:port/database" description of
+// the database in dsn, leaving out any credentials.
+func DescribePostgresDSN(dsn string) string {
+	connConfig, err := pgx.ParseConfig(dsn)
+	if err != nil {
+		return "postgres"
+	}
+	return fmt.Sprintf("postgres://%s:%d/%s", connConfig.Host, connConfig.Port, connConfig.Database)
+}
+
+// TranslateMigrationToPostgres rewrites a migration written for SQLite so it
+// can be applied to PostgreSQL.
+//
+// Only the column definitions of CREATE TABLE and ALTER TABLE ... ADD COLUMN
+// statements are rewritten: their types are mapped by postgresColumnTypes
+// (INTEGER PRIMARY KEY AUTOINCREMENT becoming BIGSERIAL PRIMARY KEY), and a
+// (strftime('%s', 'now')) default becomes the PostgreSQL epoch.  Comments,
+// string literals and the rest of the statements are left as written.
+func TranslateMigrationToPostgres(migration string) string {
+	tokens := tokenizeSQL(migration)
+
+	// Walk the statements, skipping over whitespace and comments
+	statement := []int{}
+	for idx := range tokens {
+		if tokens[idx].kind == sqlSpace || tokens[idx].kind == sqlComment {
+			continue
+		}
+		if tokens[idx].text == ";" {
+			translateStatement(tokens, statement)
+			statement = statement[:0]
+			continue
+		}
+		statement = append(statement, idx)
+	}
+	translateStatement(tokens, statement)
+
+	var result strings.Builder
+	for _, token := range tokens {
+		result.WriteString(token.text)
+	}
+	return result.String()
+}
+
+type sqlTokenKind int
+
+const (
+	sqlSpace sqlTokenKind = iota
+	sqlComment
+	sqlString     // a '...' literal
+	sqlIdentifier // a "..." or `...` quoted identifier
+	sqlWord       // a keyword, identifier or number
+	sqlPunct
+)
+
+type sqlToken struct {
+	kind sqlTokenKind
+	text string
+}
+
+// tokenizeSQL splits a migration into tokens which, concatenated, give back
+// the migration unchanged
+func tokenizeSQL(sql string) []sqlToken {
+	var tokens []sqlToken
+	isWordChar := func(c byte) bool {
+		return c == '_' || (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+	}
+	for pos := 0; pos < len(sql); {
+		start := pos
+		kind := sqlPunct
+		switch c := sql[pos]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			kind = sqlSpace
+			for pos < len(sql) && strings.IndexByte(" \t\n\r", sql[pos]) >= 0 {
+				pos++
+			}
+		case strings.HasPrefix(sql[pos:], "--"):
+			kind = sqlComment
+			if end := strings.IndexByte(sql[pos:], '\n'); end >= 0 {
+				pos += end
+			} else {
+				pos = len(sql)
+			}
+		case strings.HasPrefix(sql[pos:], "/*"):
+			kind = sqlComment
+			if end := strings.Index(sql[pos+2:], "*/"); end >= 0 {
+				pos += end + 4
+			} else {
+				pos = len(sql)
+			}
+		case c == '\'' || c == '"' || c == '`':
+			kind = sqlIdentifier
+			if c == '\'' {
+				kind = sqlString
+			}
+			// A doubled quote is an escaped quote within the token
+			for pos++; pos < len(sql); pos++ {
+				if sql[pos] == c {
+					if pos+1 < len(sql) && sql[pos+1] == c {
+						pos++
+						continue
+					}
+					pos++
+					break
+				}
+			}
+		case isWordChar(c):
+			kind = sqlWord
+			for pos < len(sql) && isWordChar(sql[pos]) {
+				pos++
+			}
+		default:
+			pos++
+		}
+		tokens = append(tokens, sqlToken{kind: kind, text: sql[start:pos]})
+	}
+	return tokens
+}
+
+// Translate the column definitions of a statement, given as the indices of
+// its tokens other than whitespace and comments
+func translateStatement(tokens []sqlToken, statement []int) {
+	word := func(i int) string {
+		if i >= len(statement) || tokens[statement[i]].kind != sqlWord {
+			return ""
+		}
+		return strings.ToUpper(tokens[statement[i]].text)
+	}
+	name := func(i int) string {
+		if i >= len(statement) {
+			return ""
+		}
+		return strings.ToLower(strings.Trim(tokens[statement[i]].text, "\"`"))
+	}
+
+	switch {
+	case word(0) == "CREATE" && word(1) == "TABLE":
+		// The column definitions are the comma-separated elements within the
+		// parentheses following the table name
+		open := 2
+		for open < len(statement) && tokens[statement[open]].text != "(" {
+			open++
+		}
+		if open >= len(statement) {
+			return
+		}
+		table := name(open - 1)
+		depth := 0
+		element := open + 1
+		for i := open; i < len(statement); i++ {
+			switch tokens[statement[i]].text {
+			case "(":
+				depth++
+			case ")":
+				depth--
+			}
+			if depth == 1 && tokens[statement[i]].text == "," || depth == 0 {
+				translateColumnDefinition(tokens, table, statement[element:i])
+				element = i + 1
+			}
+			if depth == 0 {
+				return
+			}
+		}
+	case word(0) == "ALTER" && word(1) == "TABLE" && word(3) == "ADD":
+		if word(4) == "COLUMN" {
+			translateColumnDefinition(tokens, name(2), statement[5:])
+		} else {
+			translateColumnDefinition(tokens, name(2), statement[4:])
+		}
+	}
+}
+
+// Translate a column definition, given as the indices of its tokens other
+// than whitespace and comments.  Table constraints are left alone.
+func translateColumnDefinition(tokens []sqlToken, table string, definition []int) {
+	if len(definition) < 2 || tokens[definition[0]].kind == sqlPunct || tokens[definition[1]].kind != sqlWord {
+		return
+	}
+	switch strings.ToUpper(tokens[definition[0]].text) {
+	case "CONSTRAINT", "PRIMARY", "UNIQUE", "CHECK", "FOREIGN":
+		return
+	}
+	column := strings.ToLower(strings.Trim(tokens[definition[0]].text, "\"`"))
+	isBoolean := postgresBooleanColumns[table+"."+column]
+
+	columnType := &tokens[definition[1]]
+	if isBoolean {
+		columnType.text = "BOOLEAN"
+	} else if pgType, ok := postgresColumnTypes[strings.ToUpper(columnType.text)]; ok {
+		columnType.text = pgType
+	}
+
+	for i := 2; i < len(definition); i++ {
+		token := &tokens[definition[i]]
+		if token.kind != sqlWord {
+			continue
+		}
+		switch strings.ToUpper(token.text) {
+		case "AUTOINCREMENT":
+			// PostgreSQL numbers the rows with a sequence instead
+			columnType.text = "BIGSERIAL"
+			token.text = ""
+			if prev := definition[i] - 1; tokens[prev].kind == sqlSpace {
+				tokens[prev].text = ""
+			}
+		case "DEFAULT":
+			if i+1 >= len(definition) {
+				continue
+			}
+			value := &tokens[definition[i+1]]
+			if isBoolean && (value.text == "0" || value.text == "1") {
+				value.text = map[string]string{"0": "FALSE", "1": "TRUE"}[value.text]
+			} else if i+8 < len(definition) && value.text == "(" {
+				// (strftime('%s', 'now')) occupies the next eight tokens
+				var expr strings.Builder
+				for _, idx := range definition[i+1 : i+9] {
+					expr.WriteString(tokens[idx].text)
+				}
+				if strings.EqualFold(expr.String(), "(strftime('%s','now'))") {
+					value.text = "(EXTRACT(EPOCH FROM now())::BIGINT)"
+					for idx := definition[i+1] + 1; idx <= definition[i+8]; idx++ {
+						tokens[idx].text = ""
+					}
+				}
+			}
+		}
+	}
+}
+
+// postgresMigrationFS serves the embedded migrations with every .sql file
+// passed through TranslateMigrationToPostgres, so the same migrations run on
+// both database types.
+type postgresMigrationFS struct {
+	fsys fs.FS
+}
+
+func (p postgresMigrationFS) Open(name string) (fs.File, error) {
+	file, err := p.fsys.Open(name)
+	if err != nil || path.Ext(name) != ".sql" {
+		return file, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	contents, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+	translated := TranslateMigrationToPostgres(string(contents))
+	return &translatedFile{
+		Reader: strings.NewReader(translated),
+		info:   translatedFileInfo{FileInfo: info, size: int64(len(translated))},
+	}, nil
+}
+
+type translatedFile struct {
+	*strings.Reader
+	info fs.FileInfo
+}
+
+func (f *translatedFile) Stat() (fs.FileInfo, error) {
+	return f.info, nil
+}
+
+func (f *translatedFile) Close() error {
+	return nil
+}
+
+type translatedFileInfo struct {
+	fs.FileInfo
+	size int64
+}
+
+func (i translatedFileInfo) Size() int64 {
+	return i.size
+}
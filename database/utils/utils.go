@@ -3,6 +3,7 @@ package utils
 import (
 	"database/sql"
 	"embed"
+	"io/fs"
 	"os"
 	"path/filepath"
 
@@ -47,6 +48,20 @@ func InitSQLiteDB(dbPath string) (*gorm.DB, error) {
 
 	dbName := SQLiteDSN(dbPath)
 
+	log.Debugln("Opening connection to sqlite DB", dbName)
+
+	db, err := gorm.Open(sqlite.Open(dbName), &gorm.Config{Logger: newGormLogger()})
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open the database with path: %s", dbPath)
+	}
+
+	return db, nil
+}
+
+// newGormLogger returns a gorm logger that writes through logrus at a level
+// matching Pelican's configured log level.
+func newGormLogger() logger.Interface {
 	globalLogLevel := config.GetEffectiveLogLevel()
 	var ormLevel logger.LogLevel
 	if globalLogLevel == log.DebugLevel || globalLogLevel == log.TraceLevel || globalLogLevel == log.InfoLevel {
@@ -59,23 +74,13 @@ func InitSQLiteDB(dbPath string) (*gorm.DB, error) {
 		ormLevel = logger.Info
 	}
 
-	gormLogger := gormlog.NewGormlog(
+	return gormlog.NewGormlog(
 		gormlog.WithLogrusEntry(log.WithField("component", "gorm")),
 		gormlog.WithGormOptions(gormlog.GormOptions{
 			LogLatency: true,
 			LogLevel:   ormLevel,
 		}),
 	)
-
-	log.Debugln("Opening connection to sqlite DB", dbName)
-
-	db, err := gorm.Open(sqlite.Open(dbName), &gorm.Config{Logger: gormLogger})
-
-	if err != nil {
-		return nil, errors.Wrapf(err, "failed to open the database with path: %s", dbPath)
-	}
-
-	return db, nil
 }
 
 func MigrateDB(sqldb *sql.DB, migrationFS embed.FS, migrationPath string) error {
@@ -83,9 +88,23 @@ func MigrateDB(sqldb *sql.DB, migrationFS embed.FS, migrationPath string) error
 }
 
 func MigrateServerSpecificDB(sqldb *sql.DB, migrationFS embed.FS, migrationPath string, tablePrefix string) error {
-	goose.SetBaseFS(migrationFS)
+	return MigrateDBWithType(sqldb, DbTypeSQLite, migrationFS, migrationPath, tablePrefix)
+}
+
+// MigrateDBWithType applies the embedded migrations to a database of the
+// given type.  The migrations are written for SQLite; for PostgreSQL they
+// are translated on the fly by postgresMigrationFS.
+func MigrateDBWithType(sqldb *sql.DB, dbType DbType, migrationFS fs.FS, migrationPath string, tablePrefix string) error {
+	switch dbType {
+	case DbTypeSQLite:
+		goose.SetBaseFS(migrationFS)
+	case DbTypePostgres:
+		goose.SetBaseFS(postgresMigrationFS{migrationFS})
+	default:
+		return errors.Errorf("unsupported database type %q", dbType)
+	}
 
-	if err := goose.SetDialect("sqlite3"); err != nil {
+	if err := goose.SetDialect(dbType.gooseDialect()); err != nil {
 		return err
 	}
 
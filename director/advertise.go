@@ -151,7 +151,7 @@ func updateDowntimeFromTopology(ctx context.Context) error {
 	// Combine both current and future downtimes into one slice.
 	fetchedTopologyDowntimes := append(downtimeInfo.CurrentDowntimes.Downtimes, downtimeInfo.FutureDowntimes.Downtimes...)
 
-	const timeLayout = "Jan 2, 2006 15:04 PM MST" // see https://pkg.go.dev/time#pkg-constants
+	const timeLayout = server_structs.TopoDowntimeTimeLayout
 	for _, downtime := range fetchedTopologyDowntimes {
 		parsedStartDT, err := time.Parse(timeLayout, downtime.StartTime)
 		if err != nil {
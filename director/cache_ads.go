@@ -522,11 +522,12 @@ func applyServerDowntimes(serverName string, downtimes []server_structs.Downtime
 	// Update cached downtimes
 	serverDowntimes[serverName] = downtimes
 
-	// Determine whether any provided downtime is currently active
+	// Determine whether any provided downtime is currently active; recurring downtimes
+	// are active during any occurrence of their window
 	now := time.Now().UTC().UnixMilli()
 	active := false
 	for _, dt := range downtimes {
-		if dt.StartTime <= now && (dt.EndTime >= now || dt.EndTime == server_structs.IndefiniteEndTime) {
+		if dt.IsActive(now) {
 			active = true
 			break
 		}
@@ -569,7 +570,7 @@ func applyActiveDowntimeFilter(serverName string) {
 
 	now := time.Now().UTC().UnixMilli()
 	for _, dt := range fedList {
-		if dt.StartTime <= now && (dt.EndTime >= now || dt.EndTime == server_structs.IndefiniteEndTime) {
+		if dt.IsActive(now) {
 			// Found an active downtime; apply filter if not already filtered
 			if _, alreadyFiltered := filteredServers[serverName]; !alreadyFiltered {
 				filteredServers[serverName] = tempFiltered
@@ -763,7 +764,7 @@ func applyDowntimeFilters(
 		}
 
 		// If it is an active downtime, add it to the filteredServers map
-		if currentTime >= downtime.StartTime && (currentTime <= downtime.EndTime || downtime.EndTime == -1) {
+		if downtime.IsActive(currentTime) {
 			newFilters[downtime.ServerName] = tempFiltered
 		}
 	}
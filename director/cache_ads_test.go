@@ -557,6 +557,19 @@ func TestApplyDowntimeFilters(t *testing.T) {
 		StartTime:  now - 10_000,
 		EndTime:    now - 5_000,
 	}
+	// A daily downtime whose first window was a week ago
+	recurringDowntime := server_structs.Downtime{
+		ServerName: "recurring",
+		StartTime:  now - 7*24*3600_000 - 1_000,
+		EndTime:    now - 7*24*3600_000 + 1_000,
+		Recurrence: "FREQ=DAILY",
+	}
+	recurringEnded := server_structs.Downtime{
+		ServerName: "recurring-ended",
+		StartTime:  now - 7*24*3600_000 - 1_000,
+		EndTime:    now - 7*24*3600_000 + 1_000,
+		Recurrence: "FREQ=DAILY;COUNT=3",
+	}
 
 	allDowntimes := []server_structs.Downtime{
 		activeDowntime,
@@ -565,6 +578,8 @@ func TestApplyDowntimeFilters(t *testing.T) {
 		indefDowntime,
 		permFilteredDowntime,
 		offlineOnly,
+		recurringDowntime,
+		recurringEnded,
 	}
 
 	currentFilters := map[string]filterType{
@@ -599,6 +614,10 @@ func TestApplyDowntimeFilters(t *testing.T) {
 	// Active downtimes should mark the server as tempFiltered.
 	assert.Equal(t, tempFiltered, newFilters["active-new"])
 	assert.Equal(t, tempFiltered, newFilters["indef"])
+	// Recurring downtimes are active during each occurrence
+	assert.Equal(t, tempFiltered, newFilters["recurring"])
+	_, exists = newFilters["recurring-ended"]
+	assert.False(t, exists)
 
 	// Future downtimes should not mark servers as filtered yet.
 	_, exists = newFilters["future-only"]
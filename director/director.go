@@ -26,6 +26,7 @@ import (
 	"net/http"
 	"net/url"
 	"path"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -40,6 +41,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/pelicanplatform/pelican/config"
@@ -50,6 +52,7 @@ import (
 	"github.com/pelicanplatform/pelican/server_utils"
 	"github.com/pelicanplatform/pelican/token"
 	"github.com/pelicanplatform/pelican/token_scopes"
+	"github.com/pelicanplatform/pelican/tracing"
 	"github.com/pelicanplatform/pelican/utils"
 	"github.com/pelicanplatform/pelican/web_ui"
 )
@@ -404,7 +407,7 @@ func corsHeadersMiddleware(ginCtx *gin.Context) {
 	ginCtx.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 	ginCtx.Writer.Header().Set("Access-Control-Allow-Methods", "GET, PUT, OPTIONS, PROPFIND")
 	ginCtx.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, Depth") // TODO: , X-Pelican-User, X-Pelican-Timeout, X-Pelican-Token-Generation, X-Pelican-Authorization, X-Pelican-Namespace
-	ginCtx.Writer.Header().Set("Access-Control-Expose-Headers", "Content-Type, Authorization, X-Pelican-User, X-Pelican-Timeout, X-Pelican-Token-Generation, X-Pelican-Authorization, X-Pelican-Namespace")
+	ginCtx.Writer.Header().Set("Access-Control-Expose-Headers", "Content-Type, Authorization, X-Pelican-User, X-Pelican-Timeout, X-Pelican-Token-Generation, X-Pelican-Authorization, X-Pelican-Namespace, X-Pelican-Features")
 }
 
 // Generates the X-Pelican-Authorization header (when applicable) for responses that have
@@ -502,10 +505,37 @@ func generateXBrokerHeader(ginCtx *gin.Context, oAds []server_structs.ServerAd)
 	}
 }
 
+// Generate the X-Pelican-Features header, listing the optional features offered
+// by all of the servers in the Link header.  A client can only count on a
+// feature if it doesn't matter which of the servers it uses.
+func generateXFeaturesHeader(ginCtx *gin.Context, sAds []server_structs.ServerAd) {
+	if len(sAds) == 0 {
+		return
+	}
+	sAds = sAds[:min(len(sAds), serverResLimit)]
+	var common []string
+	for _, feature := range sAds[0].Features {
+		offered := true
+		for _, ad := range sAds[1:] {
+			if !slices.Contains(ad.Features, feature) {
+				offered = false
+				break
+			}
+		}
+		if offered {
+			common = append(common, feature)
+		}
+	}
+	if len(common) > 0 {
+		ginCtx.Writer.Header()["X-Pelican-Features"] = []string{strings.Join(common, ", ")}
+	}
+}
+
 // Populate the X-Pelican-JobId header with the request ID. This is used for tracking
 // requests through the system.
 func generateXJobIdHeader(ginCtx *gin.Context, requestId uuid.UUID) {
 	ginCtx.Writer.Header()["X-Pelican-JobId"] = []string{requestId.String()}
+	trace.SpanFromContext(ginCtx.Request.Context()).SetAttributes(tracing.AttrJobId.String(requestId.String()))
 }
 
 // Given a URL and a set of query params, add the query params to the URL. This is
@@ -694,6 +724,7 @@ func generateRedirectResponse(ctx *gin.Context, chosenAds []server_structs.Serve
 	generateXTokenGenHeader(ctx, nsAd)
 	generateXNamespaceHeader(ctx, oAds, nsAd)
 	generateXBrokerHeader(ctx, chosenAds)
+	generateXFeaturesHeader(ctx, chosenAds)
 	generateXJobIdHeader(ctx, requestId)
 
 	redirectURL := getRedirectURL(reqPath, chosenAds[0], !nsAd.Caps.PublicReads)
@@ -1493,6 +1524,7 @@ func finishRegisterServeAd(engineCtx context.Context, ctx *gin.Context, adV2 *se
 		Type:                sType.String(),
 		Caps:                adV2.Caps,
 		RequiredFeatures:    adV2.RequiredFeatures,
+		Features:            adV2.Features,
 		IOLoad:              0.0, // Explicitly set to 0. The sort algorithm takes 0.0 as unknown load
 		Downtimes:           adV2.Downtimes,
 		Status:              adV2.Status,
@@ -1843,6 +1875,7 @@ func RegisterDirectorAPI(ctx context.Context, router *gin.RouterGroup) {
 		directorAPIV1.POST("/registerDirector", serverAdMetricMiddleware, func(gctx *gin.Context) { registerDirectorAd(ctx, egrp, gctx) })
 		directorAPIV1.POST("/registerOrigin", serverAdMetricMiddleware, func(gctx *gin.Context) { registerServerAd(ctx, gctx, server_structs.OriginType) })
 		directorAPIV1.POST("/registerCache", serverAdMetricMiddleware, func(gctx *gin.Context) { registerServerAd(ctx, gctx, server_structs.CacheType) })
+		directorAPIV1.POST("/transferReports", handleTransferReports)
 		directorAPIV1.GET("/getFedToken", getFedToken)
 		directorAPIV1.GET("/listNamespaces", listNamespacesV1)
 		directorAPIV1.GET("/namespaces/prefix/*path", getPrefixByPath)
@@ -103,6 +103,7 @@ func LaunchTTLCache(ctx context.Context, egrp *errgroup.Group) {
 	go clientIpRandAssignmentCache.Start()
 	go clientIpGeoOverrideCache.Start()
 	go directorAds.Start()
+	go throughputCache.Start()
 
 	serverAds.OnEviction(func(ctx context.Context, er ttlcache.EvictionReason, i *ttlcache.Item[string, *server_structs.Advertisement]) {
 		serverAd := i.Value().ServerAd
@@ -176,6 +177,8 @@ func LaunchTTLCache(ctx context.Context, egrp *errgroup.Group) {
 		clientIpRandAssignmentCache.Stop()
 		clientIpGeoOverrideCache.DeleteAll()
 		clientIpGeoOverrideCache.Stop()
+		throughputCache.DeleteAll()
+		throughputCache.Stop()
 		directorAdMutex.Lock()
 		directorAds.DeleteAll()
 		directorAds.Stop()
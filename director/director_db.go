@@ -0,0 +1,253 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package director
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/pelicanplatform/pelican/database"
+	"github.com/pelicanplatform/pelican/param"
+	"github.com/pelicanplatform/pelican/server_structs"
+)
+
+type (
+	// A snapshot of one entry in the serverAds cache.  The advertisement is
+	// stored as JSON (see savedAdvertisement) so that it carries the
+	// director-derived fields, e.g. the EWMA status weight, along with what
+	// the server sent.
+	DirectorServerAd struct {
+		URL           string    `gorm:"primaryKey"`
+		Advertisement []byte    `gorm:"not null"`
+		ExpiresAt     time.Time `gorm:"not null;index"`
+		UpdatedAt     time.Time
+	}
+
+	// A keyed blob of other director state, e.g. the downtime maps.
+	DirectorState struct {
+		Key       string `gorm:"primaryKey"`
+		Value     []byte `gorm:"not null"`
+		UpdatedAt time.Time
+	}
+
+	// The saved form of a server advertisement.  This is marshaled by value:
+	// ServerAd's (pointer-receiver) MarshalJSON writes its URLs as strings that
+	// the default decoder can't read back, and when promoted through
+	// server_structs.Advertisement it drops the namespace ads.
+	savedAdvertisement struct {
+		ServerAd     server_structs.ServerAd        `json:"serverAd"`
+		NamespaceAds []server_structs.NamespaceAdV2 `json:"namespaceAds"`
+	}
+
+	// The in-memory downtime and filter state, as saved to the database.
+	downtimeSnapshot struct {
+		FilteredServers     map[string]filterType                `json:"filteredServers"`
+		ServerDowntimes     map[string][]server_structs.Downtime `json:"serverDowntimes"`
+		TopologyDowntimes   map[string][]server_structs.Downtime `json:"topologyDowntimes"`
+		FederationDowntimes map[string][]server_structs.Downtime `json:"federationDowntimes"`
+	}
+)
+
+const downtimeStateKey = "downtimes"
+
+func (DirectorServerAd) TableName() string {
+	return "director_server_ads"
+}
+
+func (DirectorState) TableName() string {
+	return "director_state"
+}
+
+// Save the current server ads and downtime state to the database, replacing
+// any previous snapshot.
+func snapshotDirectorState(db *gorm.DB) error {
+	now := time.Now()
+	items := serverAds.Items()
+	rows := make([]DirectorServerAd, 0, len(items))
+	for url, item := range items {
+		ad := item.Value()
+		if ad == nil {
+			continue
+		}
+		ad.RLock()
+		adBytes, err := json.Marshal(savedAdvertisement{ServerAd: ad.ServerAd, NamespaceAds: ad.NamespaceAds})
+		ad.RUnlock()
+		if err != nil {
+			log.Warningf("Failed to serialize the advertisement of %s for the state snapshot: %v", url, err)
+			continue
+		}
+		rows = append(rows, DirectorServerAd{URL: url, Advertisement: adBytes, ExpiresAt: item.ExpiresAt(), UpdatedAt: now})
+	}
+
+	downtimeBytes, err := func() ([]byte, error) {
+		filteredServersMutex.RLock()
+		defer filteredServersMutex.RUnlock()
+		return json.Marshal(downtimeSnapshot{
+			FilteredServers:     filteredServers,
+			ServerDowntimes:     serverDowntimes,
+			TopologyDowntimes:   topologyDowntimes,
+			FederationDowntimes: federationDowntimes,
+		})
+	}()
+	if err != nil {
+		return errors.Wrap(err, "failed to serialize the downtime state")
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		// An empty cache is most likely the shutdown path clearing it, so keep
+		// the previous snapshot; its ads are ignored once they expire anyway.
+		if len(rows) > 0 {
+			if err := tx.Where("1 = 1").Delete(&DirectorServerAd{}).Error; err != nil {
+				return errors.Wrap(err, "failed to clear the previous server ad snapshot")
+			}
+			if err := tx.CreateInBatches(rows, 100).Error; err != nil {
+				return errors.Wrap(err, "failed to save the server ads")
+			}
+		}
+		state := DirectorState{Key: downtimeStateKey, Value: downtimeBytes, UpdatedAt: now}
+		if err := tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(&state).Error; err != nil {
+			return errors.Wrap(err, "failed to save the downtime state")
+		}
+		return nil
+	})
+}
+
+// Reload the downtime state and any unexpired server ads from the last
+// snapshot.  State that has been set since startup (e.g. the filters from
+// Director.FilteredServers) takes precedence over the snapshot.  Returns the
+// number of server ads restored.
+func restoreDirectorState(ctx context.Context, db *gorm.DB) (int, error) {
+	var state DirectorState
+	err := db.Where("key = ?", downtimeStateKey).First(&state).Error
+	if err == nil {
+		var snap downtimeSnapshot
+		if err := json.Unmarshal(state.Value, &snap); err != nil {
+			log.Warningf("Ignoring the saved downtime state because it could not be parsed: %v", err)
+		} else {
+			restoreDowntimes(snap)
+		}
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, errors.Wrap(err, "failed to load the saved downtime state")
+	}
+
+	var rows []DirectorServerAd
+	if err := db.Where("expires_at > ?", time.Now()).Find(&rows).Error; err != nil {
+		return 0, errors.Wrap(err, "failed to load the saved server ads")
+	}
+	restored := 0
+	for _, row := range rows {
+		saved := savedAdvertisement{}
+		if err := json.Unmarshal(row.Advertisement, &saved); err != nil {
+			log.Warningf("Ignoring the saved advertisement of %s because it could not be parsed: %v", row.URL, err)
+			continue
+		}
+		ad := server_structs.Advertisement{ServerAd: saved.ServerAd, NamespaceAds: saved.NamespaceAds}
+		if serverAds.Has(row.URL) {
+			continue
+		}
+		// Seed the cache with the saved ad so that recordAd continues its EWMA
+		// status weight, then record it as if it had just been advertised; this
+		// sets up the stat and health test utilities for the server.
+		ttl := time.Until(row.ExpiresAt)
+		if ttl <= 0 {
+			continue
+		}
+		ad.Expiration = row.ExpiresAt
+		serverAds.Set(row.URL, &ad, ttl)
+		recordAd(ctx, ad.ServerAd, &ad.NamespaceAds)
+		restored++
+	}
+	return restored, nil
+}
+
+func restoreDowntimes(snap downtimeSnapshot) {
+	filteredServersMutex.Lock()
+	defer filteredServersMutex.Unlock()
+
+	for name, fType := range snap.FilteredServers {
+		switch fType {
+		case permFiltered, shutdownFiltered:
+			// Permanent filters come from the current configuration, and
+			// shutdown filters only last for the server's drain period.
+			continue
+		case tempAllowed:
+			// An admin re-enabled a server from Director.FilteredServers
+			if filteredServers[name] == permFiltered {
+				filteredServers[name] = fType
+			}
+		default:
+			if _, ok := filteredServers[name]; !ok {
+				filteredServers[name] = fType
+			}
+		}
+	}
+	restoreMap := func(dst map[string][]server_structs.Downtime, src map[string][]server_structs.Downtime) {
+		for name, downtimes := range src {
+			if _, ok := dst[name]; !ok {
+				dst[name] = downtimes
+			}
+		}
+	}
+	restoreMap(serverDowntimes, snap.ServerDowntimes)
+	restoreMap(topologyDowntimes, snap.TopologyDowntimes)
+	restoreMap(federationDowntimes, snap.FederationDowntimes)
+}
+
+// Restore the director's state from its database and launch a goroutine that
+// periodically saves it, per Director.StateSnapshotInterval.  The state is
+// saved one final time when the context is cancelled.
+func LaunchStatePersistence(ctx context.Context, egrp *errgroup.Group) {
+	interval := param.Director_StateSnapshotInterval.GetDuration()
+	db := database.ServerDatabase
+	if interval <= 0 || db == nil {
+		log.Debugln("Director state persistence is disabled")
+		return
+	}
+
+	if restored, err := restoreDirectorState(ctx, db); err != nil {
+		log.Errorf("Failed to restore the director state from the database: %v", err)
+	} else if restored > 0 {
+		log.Infof("Restored %d server advertisements saved before the director restarted", restored)
+	}
+
+	egrp.Go(func() error {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := snapshotDirectorState(db); err != nil {
+					log.Warningf("Failed to save the director state: %v", err)
+				}
+			case <-ctx.Done():
+				if err := snapshotDirectorState(db); err != nil {
+					log.Warningf("Failed to save the director state at shutdown: %v", err)
+				}
+				return nil
+			}
+		}
+	})
+}
@@ -0,0 +1,145 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package director
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/jellydator/ttlcache/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/pelicanplatform/pelican/database"
+	dbutils "github.com/pelicanplatform/pelican/database/utils"
+	"github.com/pelicanplatform/pelican/server_structs"
+	"github.com/pelicanplatform/pelican/test_utils"
+)
+
+func setupMockDirectorDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	require.NoError(t, dbutils.MigrateServerSpecificDB(sqlDB, database.EmbedDirectorMigrations, "director_migrations", "director"))
+	t.Cleanup(func() { _ = sqlDB.Close() })
+	return db
+}
+
+func resetDirectorStateForTest() {
+	serverAds.DeleteAll()
+	filteredServersMutex.Lock()
+	filteredServers = map[string]filterType{}
+	serverDowntimes = make(map[string][]server_structs.Downtime)
+	topologyDowntimes = make(map[string][]server_structs.Downtime)
+	federationDowntimes = make(map[string][]server_structs.Downtime)
+	filteredServersMutex.Unlock()
+}
+
+func TestDirectorStateSnapshot(t *testing.T) {
+	t.Cleanup(test_utils.SetupTestLogging(t))
+	db := setupMockDirectorDB(t)
+	resetDirectorStateForTest()
+	t.Cleanup(func() {
+		resetDirectorStateForTest()
+		shutdownStatUtils()
+	})
+
+	newAd := func(name string) *server_structs.Advertisement {
+		ad := &server_structs.Advertisement{
+			ServerAd: server_structs.ServerAd{
+				URL:                    url.URL{Scheme: "https", Host: name + ".example.com:8443"},
+				Type:                   server_structs.CacheType.String(),
+				DisableDirectorTest:    true,
+				StatusWeight:           0.5,
+				StatusWeightLastUpdate: time.Now().Unix(),
+				Status:                 "warning",
+			},
+			NamespaceAds: []server_structs.NamespaceAdV2{{Path: "/foo"}},
+		}
+		ad.Initialize(name)
+		return ad
+	}
+	live := newAd("live")
+	serverAds.Set(live.URL.String(), live, 10*time.Minute)
+	expiring := newAd("expiring")
+	serverAds.Set(expiring.URL.String(), expiring, time.Second)
+
+	downtime := server_structs.Downtime{UUID: "dt-1", ServerName: "live", StartTime: time.Now().UnixMilli(), EndTime: time.Now().Add(time.Hour).UnixMilli()}
+	filteredServersMutex.Lock()
+	filteredServers["live"] = serverFiltered
+	filteredServers["admin-disabled"] = tempFiltered
+	filteredServers["config-disabled"] = permFiltered
+	filteredServers["draining"] = shutdownFiltered
+	serverDowntimes["live"] = []server_structs.Downtime{downtime}
+	filteredServersMutex.Unlock()
+
+	require.NoError(t, snapshotDirectorState(db))
+
+	// Simulate a restart after the short-lived ad has expired.
+	time.Sleep(1100 * time.Millisecond)
+	resetDirectorStateForTest()
+	filteredServers["config-disabled"] = permFiltered
+
+	restored, err := restoreDirectorState(context.Background(), db)
+	require.NoError(t, err)
+	assert.Equal(t, 1, restored)
+
+	item := serverAds.Get(live.URL.String())
+	require.NotNil(t, item)
+	assert.Equal(t, "live", item.Value().Name)
+	assert.Equal(t, []server_structs.NamespaceAdV2{{Path: "/foo"}}, item.Value().NamespaceAds)
+	// The EWMA status weight continues from the saved value rather than restarting
+	assert.InDelta(t, 0.5, item.Value().StatusWeight, 0.05)
+	assert.WithinDuration(t, time.Now().Add(10*time.Minute), item.ExpiresAt(), 5*time.Second)
+	assert.False(t, serverAds.Has(expiring.URL.String()))
+
+	filteredServersMutex.RLock()
+	defer filteredServersMutex.RUnlock()
+	assert.Equal(t, serverFiltered, filteredServers["live"])
+	assert.Equal(t, tempFiltered, filteredServers["admin-disabled"])
+	assert.Equal(t, permFiltered, filteredServers["config-disabled"])
+	assert.NotContains(t, filteredServers, "draining")
+	require.Len(t, serverDowntimes["live"], 1)
+	assert.Equal(t, "dt-1", serverDowntimes["live"][0].UUID)
+}
+
+func TestDirectorStateSnapshotKeepsAdsWhenCacheEmpty(t *testing.T) {
+	t.Cleanup(test_utils.SetupTestLogging(t))
+	db := setupMockDirectorDB(t)
+	resetDirectorStateForTest()
+	t.Cleanup(resetDirectorStateForTest)
+
+	ad := &server_structs.Advertisement{ServerAd: server_structs.ServerAd{URL: url.URL{Scheme: "https", Host: "origin.example.com"}}}
+	ad.Initialize("origin")
+	serverAds.Set(ad.URL.String(), ad, ttlcache.DefaultTTL)
+	require.NoError(t, snapshotDirectorState(db))
+
+	// The shutdown path clears the cache, possibly before the final snapshot
+	serverAds.DeleteAll()
+	require.NoError(t, snapshotDirectorState(db))
+
+	var count int64
+	require.NoError(t, db.Model(&DirectorServerAd{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
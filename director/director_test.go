@@ -2307,6 +2307,26 @@ func TestHeaderGenFuncs(t *testing.T) {
 		assert.Contains(t, cPub.Writer.Header().Get("X-Pelican-Namespace"), "require-token=false")
 		assert.NotContains(t, cPub.Writer.Header().Get("X-Pelican-Namespace"), "collections-url")
 	})
+
+	t.Run("test-x-pel-features", func(t *testing.T) {
+		tpcAd := server_structs.ServerAd{Features: []string{server_structs.FeatureThirdPartyCopy, "Other"}}
+
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		generateXFeaturesHeader(c, []server_structs.ServerAd{tpcAd, {Features: []string{server_structs.FeatureThirdPartyCopy}}})
+		assert.Equal(t, server_structs.FeatureThirdPartyCopy, c.Writer.Header().Get("X-Pelican-Features"))
+
+		// A feature is only advertised if every server offers it
+		c, _ = gin.CreateTestContext(httptest.NewRecorder())
+		generateXFeaturesHeader(c, []server_structs.ServerAd{tpcAd, {}})
+		assert.Empty(t, c.Writer.Header().Values("X-Pelican-Features"))
+
+		c, _ = gin.CreateTestContext(httptest.NewRecorder())
+		generateXFeaturesHeader(c, []server_structs.ServerAd{tpcAd})
+		var xPelFeatures server_structs.XPelFeatures
+		require.NoError(t, xPelFeatures.ParseRawResponse(&http.Response{Header: c.Writer.Header()}))
+		assert.Equal(t, []string{server_structs.FeatureThirdPartyCopy, "Other"}, xPelFeatures.Features)
+		assert.True(t, xPelFeatures.Has(server_structs.FeatureThirdPartyCopy))
+	})
 }
 
 func TestGetHealthTestFile(t *testing.T) {
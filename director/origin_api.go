@@ -103,52 +103,67 @@ func checkNamespaceStatus(prefix string, registryWebUrlStr string) (bool, error)
 	return resBody.Approved, nil
 }
 
-// Given a token and a location in the namespace to advertise in,
-// see if the entity is authorized to advertise an origin for the
-// namespace
-func verifyAdvertiseToken(ctx context.Context, token, namespace string) (bool, error) {
-	issuerUrl, err := registry_jwks.GetNSIssuerURL(namespace)
+// Fetch the public keys of a namespace from the registry at registryUrlStr,
+// returning adminApprovalErr if the namespace isn't approved there
+func getNamespaceKeys(ctx context.Context, registryUrlStr, namespace string) (jwk.Set, error) {
+	issuerUrl, err := registry_jwks.GetNSIssuerURLAt(registryUrlStr, namespace)
 	if err != nil {
-		return false, errors.Wrap(err, "failed to get issuer for namespace "+namespace)
+		return nil, errors.Wrap(err, "failed to get issuer for namespace "+namespace)
 	}
 
 	keyLoc, err := registry_jwks.GetJWKSURLFromIssuerURL(issuerUrl)
 	if err != nil {
-		return false, errors.Wrap(err, "failed to get JWKS URL from the issuer URL at "+issuerUrl)
-	}
-
-	fedInfo, err := config.GetFederation(ctx)
-	if err != nil {
-		return false, err
+		return nil, errors.Wrap(err, "failed to get JWKS URL from the issuer URL at "+issuerUrl)
 	}
-	regUrlStr := fedInfo.RegistryEndpoint
 
-	approved, err := checkNamespaceStatus(namespace, regUrlStr)
+	approved, err := checkNamespaceStatus(namespace, registryUrlStr)
 	if err != nil {
-		return false, errors.Wrap(err, "failed to check namespace approval status")
+		return nil, errors.Wrap(err, "failed to check namespace approval status")
 	}
 	if !approved {
 		adminApprovalErr = errors.New(namespace + " has not been approved by an administrator")
-		return false, adminApprovalErr
+		return nil, adminApprovalErr
 	}
 
 	log.Debugln("Attempting to fetch keys from ", keyLoc)
-	var keyset jwk.Set
-
 	item := namespaceKeys.Get(keyLoc)
 	if item != nil {
 		if !item.IsExpired() {
-			keyset = item.Value()
+			return item.Value(), nil
 		}
 	}
 
-	if keyset == nil {
-		tr := config.GetTransport()
-		keyset, err = utils.GetJwks(ctx, tr, keyLoc)
-		if err != nil {
-			return false, errors.Wrapf(err, "failed to get jwks at %s", keyLoc)
+	tr := config.GetTransport()
+	keyset, err := utils.GetJwks(ctx, tr, keyLoc)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get jwks at %s", keyLoc)
+	}
+	namespaceKeys.Set(keyLoc, keyset, param.Director_AdvertisementTTL.GetDuration())
+	return keyset, nil
+}
+
+// Given a token and a location in the namespace to advertise in,
+// see if the entity is authorized to advertise an origin for the
+// namespace.  The namespace keys are looked up at the federation's
+// registry, falling back to its replicas in order if it can't be reached.
+func verifyAdvertiseToken(ctx context.Context, token, namespace string) (bool, error) {
+	registryUrls, err := registry_jwks.GetRegistryEndpoints(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var keyset jwk.Set
+	for idx, registryUrlStr := range registryUrls {
+		keyset, err = getNamespaceKeys(ctx, registryUrlStr, namespace)
+		if err == nil || err == adminApprovalErr {
+			break
+		}
+		if idx < len(registryUrls)-1 {
+			log.Warningf("Failed to look up the keys of namespace %s at registry %s, trying the next registry: %v", namespace, registryUrlStr, err)
 		}
-		namespaceKeys.Set(keyLoc, keyset, param.Director_AdvertisementTTL.GetDuration())
+	}
+	if err != nil {
+		return false, err
 	}
 
 	tok, err := jwt.Parse([]byte(token), jwt.WithKeySet(keyset), jwt.WithValidate(true))
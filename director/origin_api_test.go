@@ -140,6 +140,68 @@ func TestVerifyAdvertiseToken(t *testing.T) {
 	assert.NoError(t, err, "Incorrect scope name should not throw and error")
 }
 
+func TestVerifyAdvertiseTokenRegistryReplica(t *testing.T) {
+	t.Cleanup(test_utils.SetupTestLogging(t))
+	ctx, cancel, egrp := test_utils.TestContext(context.Background(), t)
+	defer func() { require.NoError(t, egrp.Wait()) }()
+	defer cancel()
+
+	server_utils.ResetTestState()
+	t.Cleanup(server_utils.ResetTestState)
+	require.NoError(t, param.IssuerKeysDirectory.Set(filepath.Join(t.TempDir(), "t-issuer-keys")))
+	require.NoError(t, param.ConfigDir.Set(t.TempDir()))
+
+	// The replica serves the approval status and the keys of the namespace
+	var kSet jwk.Set
+	replica := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch {
+		case req.Method == "POST" && req.URL.Path == "/api/v1.0/registry/checkNamespaceStatus":
+			resByte, _ := json.Marshal(server_structs.CheckNamespaceStatusRes{Approved: true})
+			_, _ = w.Write(resByte)
+		case req.URL.Path == "/api/v1.0/registry/replica-namespace/.well-known/issuer.jwks":
+			resByte, _ := json.Marshal(kSet)
+			_, _ = w.Write(resByte)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer replica.Close()
+
+	// The main registry is down
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+	fedInfo := pelican_url.FederationDiscovery{RegistryEndpoint: down.URL}
+	test_utils.MockFederationRoot(t, &fedInfo, nil)
+	require.NoError(t, param.Federation_RegistryReplicaUrls.Set([]string{replica.URL}))
+	require.NoError(t, initServerForTest(t, ctx, server_structs.DirectorType))
+
+	var err error
+	kSet, err = config.GetIssuerPublicJWKS()
+	require.NoError(t, err)
+
+	issuerUrl, err := registry_jwks.GetNSIssuerURL("/replica-namespace")
+	require.NoError(t, err)
+	advTokenCfg := token.NewWLCGToken()
+	advTokenCfg.Lifetime = time.Minute
+	advTokenCfg.Issuer = issuerUrl
+	advTokenCfg.Subject = "origin"
+	advTokenCfg.AddAudiences("https://director-url.org")
+	advTokenCfg.AddScopes(token_scopes.Pelican_Advertise)
+	tok, err := advTokenCfg.CreateToken()
+	require.NoError(t, err)
+
+	ok, err := verifyAdvertiseToken(ctx, tok, "/replica-namespace")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	// Without the replica, the keys can't be looked up
+	namespaceKeys.DeleteAll()
+	require.NoError(t, param.Federation_RegistryReplicaUrls.Set([]string{}))
+	ok, err = verifyAdvertiseToken(ctx, tok, "/replica-namespace")
+	assert.Error(t, err)
+	assert.False(t, ok)
+}
+
 func TestNamespaceKeysCacheEviction(t *testing.T) {
 	t.Cleanup(test_utils.SetupTestLogging(t))
 	t.Run("evict-after-expire-time", func(t *testing.T) {
@@ -31,12 +31,15 @@ import (
 	"github.com/jellydator/ttlcache/v3"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/pelicanplatform/pelican/features"
 	"github.com/pelicanplatform/pelican/metrics"
 	"github.com/pelicanplatform/pelican/param"
 	"github.com/pelicanplatform/pelican/pelican_url"
 	"github.com/pelicanplatform/pelican/server_structs"
+	"github.com/pelicanplatform/pelican/tracing"
 	"github.com/pelicanplatform/pelican/utils"
 )
 
@@ -79,16 +82,28 @@ const (
 //   - adaptive:  sort serverAds based on rules discussed in these places:
 //   - https://github.com/PelicanPlatform/pelican/discussions/1198
 //   - https://docs.google.com/document/d/e/2PACX-1vQg9biPzp3RbC5qVuJFvgMZHgIM-nw92JzjHkGl-h7djeNNXa68ckv2rAqtXEDYe8QvXL3oX0Fr0-bp/pub
+//   - throughput: sort serverAds by the throughput clients on the same network reported from them, with
+//     occasional exploration of less-measured servers
 //
 // Note that if the client IP isn't overridden and MaxMind cannot resolve accurate coordinates for it, the client's
 // coordinate is randomly assigned within the contiguous US and cached for re-use. This means that distance-based sorts
 // will be effectively random the first time, but subsequent requests within a short time period will still likely
 // generate cache hits.
-func sortServerAds(ctx context.Context, ginCtx *gin.Context, clientAddr netip.Addr, ads []server_structs.ServerAd, nsAd server_structs.NamespaceAdV2, requestId uuid.UUID, isOriginSort bool, precomputedAvailMap map[string]bool, redirectInfo *server_structs.RedirectInfo) ([]server_structs.ServerAd, error) {
+func sortServerAds(ctx context.Context, ginCtx *gin.Context, clientAddr netip.Addr, ads []server_structs.ServerAd, nsAd server_structs.NamespaceAdV2, requestId uuid.UUID, isOriginSort bool, precomputedAvailMap map[string]bool, redirectInfo *server_structs.RedirectInfo) (result []server_structs.ServerAd, err error) {
 	sortMethod := server_structs.SortType(param.Director_CacheSortMethod.GetString())
 	redirectInfo.DirectorSortMethod = sortMethod.String()
 	redirectInfo.ClientInfo.IpAddr = clientAddr.String()
 
+	ctx, span := tracing.StartSpan(ctx, "director.sortServerAds",
+		attribute.String("pelican.sort_method", sortMethod.String()),
+		attribute.Int("pelican.ad_count", len(ads)),
+		attribute.Bool("pelican.origin_sort", isOriginSort),
+		tracing.AttrJobId.String(requestId.String()),
+	)
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
 	sortContext := SortContext{
 		Ctx:             ctx,
 		ClientAddr:      clientAddr,
@@ -109,6 +124,8 @@ func sortServerAds(ctx context.Context, ginCtx *gin.Context, clientAddr netip.Ad
 		sortAlg = &AdaptiveSort{}
 	case server_structs.RandomType:
 		sortAlg = &RandomSort{}
+	case server_structs.ThroughputType:
+		sortAlg = &ThroughputSort{}
 	default:
 		// Never say never, but this should never get hit because we validate the value on Director startup.
 		// The only real way to get here is through writing bad unit tests.
@@ -117,6 +134,7 @@ func sortServerAds(ctx context.Context, ginCtx *gin.Context, clientAddr netip.Ad
 
 	sortedAds, err := sortAlg.Sort(ads, sortContext)
 	if err != nil {
+		span.AddEvent("sort failed; falling back", trace.WithAttributes(attribute.String("exception.message", err.Error())))
 		// Use fallbacks that are less likely to produce errors (Distance, then Random)
 		var fallbackMethod server_structs.SortType
 		if sortMethod != server_structs.DistanceType && sortMethod != server_structs.RandomType {
@@ -282,6 +300,17 @@ func allPredicatesPass(ctx *gin.Context, ad copyAd, preds ...AdPredicate) bool {
 	return true
 }
 
+// Get the verb to match servers against for the request.  Resumable uploads
+// look up their progress with a HEAD request carrying the upload ID; it is part
+// of writing the object, so it needs an origin accepting writes rather than one
+// holding the object.
+func getRequestVerb(ctx *gin.Context) string {
+	if ctx.Request.Method == http.MethodHead && ctx.Request.Header.Get(utils.ResumableUploadIdHeader) != "" {
+		return http.MethodPut
+	}
+	return ctx.Request.Method
+}
+
 // ORIGIN FILTERING PREDICATES
 
 // Filter out origins that don't support the incoming request verb. For example,
@@ -455,7 +484,7 @@ func getSortedAds(ctx *gin.Context, requestId uuid.UUID) (sortedOrigins, sortedC
 	// as a function over the coupling of server+namespace.
 	reqPath := getObjectPathFromRequest(ctx)
 	reqParams := getRequestParameters(ctx.Request)
-	reqVerb := ctx.Request.Method
+	reqVerb := getRequestVerb(ctx)
 	originAds, cacheAds := getAdsForPath(reqPath)
 
 	// If there are no matching origin ads, then we also assume no caches should be serving the object as shutting
@@ -580,7 +609,7 @@ func getSortedAds(ctx *gin.Context, requestId uuid.UUID) (sortedOrigins, sortedC
 	}
 
 	// Finally, sort everything as needed
-	pCtx := context.WithValue(context.Background(), ProjectContextKey{},
+	pCtx := context.WithValue(tracing.Detach(ctx.Request.Context()), ProjectContextKey{},
 		utils.ExtractProjectFromUserAgent(ctx.Request.Header.Values("User-Agent")))
 	var wg sync.WaitGroup
 	var lastError error
@@ -29,6 +29,7 @@ import (
 
 	"github.com/pelicanplatform/pelican/param"
 	"github.com/pelicanplatform/pelican/server_structs"
+	"github.com/pelicanplatform/pelican/utils"
 )
 
 type (
@@ -527,6 +528,84 @@ func (as *AdaptiveSort) Sort(sAds []server_structs.ServerAd, sCtx SortContext) (
 	return finalWeights.GetSortedAds(workingSet, smSortStochastic), nil
 }
 
+// A sort that ranks servers by the throughput clients on the requesting client's
+// network have measured from them (see throughput.go).  Servers without measurements
+// are imputed the median throughput, and a fraction of requests (set by
+// Director.ThroughputSortExplorationPercentage) move the least-measured server to the
+// front so its measurements stay current.  Falls back to DistanceSort when none of
+// the servers have been measured from the client's network.
+type ThroughputSort struct{}
+
+func (ts *ThroughputSort) Type() server_structs.SortType {
+	return server_structs.ThroughputType
+}
+func (ts *ThroughputSort) String() string {
+	return string(server_structs.ThroughputType)
+}
+func (ts *ThroughputSort) Sort(sAds []server_structs.ServerAd, sCtx SortContext) ([]server_structs.ServerAd, error) {
+	subnet, ok := utils.ApplyIPMask(sCtx.ClientAddr.String())
+	if !ok {
+		return (&DistanceSort{}).Sort(sAds, sCtx)
+	}
+
+	stats := make([]throughputStats, len(sAds))
+	maxThroughput := 0.0
+	for idx, ad := range sAds {
+		if s, found := getThroughput(subnet, ad.URL.String()); found {
+			stats[idx] = s
+			maxThroughput = max(maxThroughput, s.throughput())
+		}
+	}
+	if maxThroughput <= 0 {
+		return (&DistanceSort{}).Sort(sAds, sCtx)
+	}
+
+	// Normalize by the fastest server so weights are comparable to the other (0,1] weights
+	tWeights := computeWeights(sAds, func(idx int, _ server_structs.ServerAd) (float64, bool) {
+		if t := stats[idx].throughput(); t > 0 {
+			return t / maxThroughput, true
+		}
+		return 0, false
+	})
+	sWeights := computeWeights(sAds, func(_ int, ad server_structs.ServerAd) (float64, bool) {
+		return statusWeightFn(ad.StatusWeight)
+	})
+
+	sCtx.RedirectInfo.ServersInfo = make(map[string]*server_structs.ServerRedirectInfo)
+	finalWeights := make(SwapMaps, len(sAds))
+	for idx := range sAds {
+		finalWeights[idx] = SwapMap{tWeights[idx].Weight * sWeights[idx].Weight, idx}
+
+		// populate the RedirectInfo
+		thisServer := &server_structs.ServerRedirectInfo{}
+		thisServer.RedirectWeights.ThroughputWeight = tWeights[idx].Weight
+		thisServer.RedirectWeights.StatusWeight = sWeights[idx].Weight
+		thisServer.Coordinate = sAds[idx].Coordinate
+		sCtx.RedirectInfo.ServersInfo[sAds[idx].URL.String()] = thisServer
+	}
+
+	sorted := finalWeights.GetSortedAds(sAds, smSortDescending)
+	explorePct := param.Director_ThroughputSortExplorationPercentage.GetInt()
+	if len(sorted) > 1 && explorePct > 0 && rand.Intn(100) < explorePct {
+		// Explore: promote the server with the fewest measurements, preferring
+		// the higher-ranked one on ties.
+		samples := make(map[string]int, len(sAds))
+		for idx, ad := range sAds {
+			samples[ad.URL.String()] = stats[idx].Samples
+		}
+		pick := 0
+		for idx := 1; idx < len(sorted); idx++ {
+			if samples[sorted[idx].URL.String()] < samples[sorted[pick].URL.String()] {
+				pick = idx
+			}
+		}
+		explored := sorted[pick]
+		copy(sorted[1:pick+1], sorted[:pick])
+		sorted[0] = explored
+	}
+	return sorted, nil
+}
+
 ///////////////////////////
 // OTHER MISC SORT STUFF //
 ///////////////////////////
@@ -34,6 +34,7 @@ import (
 	"github.com/pelicanplatform/pelican/pelican_url"
 	"github.com/pelicanplatform/pelican/server_structs"
 	"github.com/pelicanplatform/pelican/test_utils"
+	"github.com/pelicanplatform/pelican/utils"
 )
 
 func hasServerAdWithName(ads []copyAd, name string) bool {
@@ -407,6 +408,24 @@ func TestOriginSupportsVerb(t *testing.T) {
 	}
 }
 
+func TestGetRequestVerb(t *testing.T) {
+	newCtx := func(method string, header http.Header) *gin.Context {
+		req, err := http.NewRequest(method, "/foo/bar", nil)
+		require.NoError(t, err)
+		for key, values := range header {
+			req.Header[key] = values
+		}
+		return &gin.Context{Request: req}
+	}
+
+	assert.Equal(t, http.MethodHead, getRequestVerb(newCtx(http.MethodHead, nil)))
+	assert.Equal(t, http.MethodGet, getRequestVerb(newCtx(http.MethodGet, nil)))
+	// Looking up the progress of a resumable upload is matched like a write
+	uploadHeader := http.Header{utils.ResumableUploadIdHeader: {"upload1"}}
+	assert.Equal(t, http.MethodPut, getRequestVerb(newCtx(http.MethodHead, uploadHeader)))
+	assert.Equal(t, http.MethodGet, getRequestVerb(newCtx(http.MethodGet, uploadHeader)))
+}
+
 func TestOriginSupportsQuery(t *testing.T) {
 	t.Cleanup(test_utils.SetupTestLogging(t))
 	testCases := []struct {
@@ -34,12 +34,15 @@ import (
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 
 	"github.com/pelicanplatform/pelican/config"
 	"github.com/pelicanplatform/pelican/metrics"
 	"github.com/pelicanplatform/pelican/param"
 	"github.com/pelicanplatform/pelican/pelican_url"
 	"github.com/pelicanplatform/pelican/server_structs"
+	"github.com/pelicanplatform/pelican/tracing"
 	"github.com/pelicanplatform/pelican/version"
 )
 
@@ -211,9 +214,16 @@ func NewObjectStat() *ObjectStat {
 }
 
 // Implementation of sending a HEAD request to an origin for an object
-func (stat *ObjectStat) sendHeadReq(ctx context.Context, objectName string, dataUrl url.URL, digest bool, token string, timeout time.Duration) (*objectMetadata, error) {
+func (stat *ObjectStat) sendHeadReq(ctx context.Context, objectName string, dataUrl url.URL, digest bool, token string, timeout time.Duration) (meta *objectMetadata, err error) {
 	client := config.GetClient()
 	reqUrl := dataUrl.JoinPath(objectName)
+	ctx, span := tracing.StartClientSpan(ctx, "director.statServer",
+		attribute.String("server.address", dataUrl.Host),
+		attribute.String("url.path", reqUrl.Path),
+	)
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
 	ctx, cancelFunc := context.WithTimeout(ctx, timeout)
 	defer cancelFunc()
 	req, err := http.NewRequestWithContext(ctx, http.MethodHead, reqUrl.String(), nil)
@@ -228,6 +238,7 @@ func (stat *ObjectStat) sendHeadReq(ctx context.Context, objectName string, data
 		req.Header.Set("Want-Digest", "crc32c")
 	}
 	req.Header.Set("User-Agent", "pelican-director/"+version.GetVersion())
+	tracing.InjectHeaders(ctx, req.Header)
 
 	res, err := client.Do(req)
 	if err != nil {
@@ -338,6 +349,18 @@ func (stat *ObjectStat) queryServersForObject(ctx context.Context, objectName st
 		option(&cfg)
 	}
 
+	ctx, span := tracing.StartSpan(ctx, "director.stat",
+		attribute.String("url.path", objectName),
+		attribute.String("pelican.server_type", sType.String()),
+	)
+	defer func() {
+		span.SetAttributes(attribute.String("pelican.stat_status", string(qResult.Status)))
+		if qResult.Status == queryFailed {
+			span.SetStatus(codes.Error, qResult.Msg)
+		}
+		span.End()
+	}()
+
 	ads := []server_structs.ServerAd{}
 
 	// Use the provided originAds and cacheAds if available
@@ -596,7 +619,7 @@ func shouldStatCaches(ctx *gin.Context, cAds, oAds []server_structs.ServerAd, be
 		reqParams.Has(pelican_url.QueryDirectRead) || // The client explicitly asked for a direct read from origin; no need to stat caches
 		!param.Director_CheckCachePresence.GetBool() || // The Director is configured to not to stat caches
 		len(cAds) == 0 || // There are no caches to stat
-		ctx.Request.Method == http.MethodPut || ctx.Request.Method == http.MethodDelete || ctx.Request.Method == "PROPFIND" || // The request is of a type where stats are irrelevant
+		getRequestVerb(ctx) == http.MethodPut || ctx.Request.Method == http.MethodDelete || ctx.Request.Method == "PROPFIND" || // The request is of a type where stats are irrelevant
 		!bestNSAd.Caps.PublicReads && reqParams.Get("authz") == "" || // We lack auth to succeed in stating caches
 		(isOriginRequest(ctx) && !requiresCacheChaining(ctx, oAds)) { // The request is an origin request and we don't need to chain caches
 		return false
@@ -611,7 +634,7 @@ func shouldStatOrigins(ctx *gin.Context, cAds, oAds []server_structs.ServerAd, b
 	if reqParams.Has(pelican_url.QuerySkipStat) || // The client indicates they want to avoid stats
 		!param.Director_CheckOriginPresence.GetBool() || // The Director is configured to not to stat origins
 		len(oAds) == 0 || // There are no origins to stat
-		ctx.Request.Method == http.MethodPut || ctx.Request.Method == http.MethodDelete || ctx.Request.Method == "PROPFIND" || // The request is of a type where stats are irrelevant
+		getRequestVerb(ctx) == http.MethodPut || ctx.Request.Method == http.MethodDelete || ctx.Request.Method == "PROPFIND" || // The request is of a type where stats are irrelevant
 		param.Director_AssumePresenceAtSingleOrigin.GetBool() && len(oAds) == 1 || // The Director is configured to assume presence at a single origin
 		!bestNSAd.Caps.PublicReads && reqParams.Get("authz") == "" || // We lack auth to succeed in stating origins
 		(isCacheRequest(ctx) && len(cAds) > 0) { // The incoming request is for a cache, and we won't need to fall back to origins because of missing caches
@@ -685,7 +708,7 @@ func generateAvailabilityMaps(ctx *gin.Context, origins, caches []server_structs
 		cAdsToQuery = nil
 	}
 
-	qr := q.Query(context.Background(), reqPath, st, 1, len(oAdsToQuery)+len(cAdsToQuery),
+	qr := q.Query(tracing.Detach(ctx.Request.Context()), reqPath, st, 1, len(oAdsToQuery)+len(cAdsToQuery),
 		withOriginAds(oAdsToQuery), withCacheAds(cAdsToQuery), WithToken(reqParams.Get("authz")))
 
 	if qr.Status == queryFailed {
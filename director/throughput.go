@@ -0,0 +1,219 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package director
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jellydator/ttlcache/v3"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/pelicanplatform/pelican/param"
+	"github.com/pelicanplatform/pelican/server_structs"
+	"github.com/pelicanplatform/pelican/utils"
+)
+
+type (
+	// Identifies the throughput measured from one client network to one server.
+	// The subnet is the client's masked IP (see utils.ApplyIPMask) so that
+	// clients sharing a site network share measurements.
+	throughputKey struct {
+		Subnet    string
+		ServerURL string
+	}
+
+	// Exponentially-decayed throughput measurements for one throughputKey.
+	// Bytes and seconds are decayed separately so that the estimate is a
+	// time-weighted aggregate throughput rather than an average of per-transfer
+	// rates, which would let many small transfers swamp a few large ones.
+	throughputStats struct {
+		Bytes   float64   // Decayed sum of bytes transferred
+		Seconds float64   // Decayed sum of transfer durations
+		Samples int       // Number of reports folded into the stats
+		Updated time.Time // Time of the most recent report
+	}
+)
+
+const (
+	// Reports claiming more than this many bytes per second are discarded
+	// as bogus (100 Gbps).
+	maxThroughputBytesPerSecond = 12.5e9
+	// The largest request body accepted by the transfer report endpoint.
+	maxTransferReportBodyBytes = 256 * 1024
+	// Measurements not refreshed in this long are forgotten.
+	throughputStatsTTL = 24 * time.Hour
+)
+
+var (
+	// Per-(client subnet, server) throughput measurements reported by clients.
+	// throughputMutex serializes the read-modify-write of each update.
+	throughputCache = ttlcache.New(
+		ttlcache.WithTTL[throughputKey, throughputStats](throughputStatsTTL),
+		ttlcache.WithCapacity[throughputKey, throughputStats](100_000),
+	)
+	throughputMutex sync.Mutex
+)
+
+// Returns the throughput estimate in bytes per second.
+func (ts throughputStats) throughput() float64 {
+	if ts.Seconds <= 0 {
+		return 0
+	}
+	return ts.Bytes / ts.Seconds
+}
+
+// Fold a new transfer measurement into the stats, first decaying the existing
+// measurements by exp(-dt/tau), where tau is Director.ThroughputSortEWMATimeConstant.
+func (ts throughputStats) update(bytes int64, duration time.Duration, now time.Time) throughputStats {
+	if ts.Samples > 0 {
+		tau := param.Director_ThroughputSortEWMATimeConstant.GetDuration()
+		if dt := now.Sub(ts.Updated); dt > 0 && tau > 0 {
+			decay := math.Exp(-dt.Seconds() / tau.Seconds())
+			ts.Bytes *= decay
+			ts.Seconds *= decay
+		}
+	}
+	ts.Bytes += float64(bytes)
+	ts.Seconds += duration.Seconds()
+	ts.Samples++
+	ts.Updated = now
+	return ts
+}
+
+// Record a client-reported transfer from the given client subnet to the server.
+func recordThroughput(subnet, serverURL string, bytes int64, duration time.Duration) {
+	key := throughputKey{Subnet: subnet, ServerURL: serverURL}
+	throughputMutex.Lock()
+	defer throughputMutex.Unlock()
+	var stats throughputStats
+	if item := throughputCache.Get(key); item != nil {
+		stats = item.Value()
+	}
+	throughputCache.Set(key, stats.update(bytes, duration, time.Now()), ttlcache.DefaultTTL)
+}
+
+// Get the throughput measurements from the given client subnet to the server, if any.
+func getThroughput(subnet, serverURL string) (throughputStats, bool) {
+	item := throughputCache.Get(throughputKey{Subnet: subnet, ServerURL: serverURL}, ttlcache.WithDisableTouchOnHit[throughputKey, throughputStats]())
+	if item == nil {
+		return throughputStats{}, false
+	}
+	return item.Value(), true
+}
+
+// Validate a client's transfer report, returning the failure reason if it
+// should be ignored.
+func validateTransferReport(report server_structs.TransferReport) (reason string, ok bool) {
+	if report.Bytes < server_structs.MinTransferReportBytes {
+		return "transfer too small", false
+	}
+	if report.Duration <= 0 || report.TimeToFirstByte < 0 || report.TimeToFirstByte > report.Duration {
+		return "invalid duration", false
+	}
+	if float64(report.Bytes)/report.Duration.Seconds() > maxThroughputBytesPerSecond {
+		return "implausible throughput", false
+	}
+	return "", true
+}
+
+// Map the hosts of all known servers to their canonical URL (the key used
+// in the server ads cache and by the sort algorithms).  Servers may be
+// reached through either their URL or their auth URL.
+func getServerURLsByHost() map[string]string {
+	hosts := make(map[string]string)
+	for _, item := range serverAds.Items() {
+		ad := item.Value()
+		if ad == nil {
+			continue
+		}
+		canonical := ad.URL.String()
+		hosts[ad.URL.Host] = canonical
+		if ad.AuthURL.Host != "" {
+			if _, exists := hosts[ad.AuthURL.Host]; !exists {
+				hosts[ad.AuthURL.Host] = canonical
+			}
+		}
+	}
+	return hosts
+}
+
+// Handle the transfer reports clients send after downloading objects.
+//
+// Reports are unauthenticated; to limit the damage a misbehaving client can
+// do, measurements are only applied to the sender's own subnet and only for
+// servers the director knows about.
+func handleTransferReports(ctx *gin.Context) {
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxTransferReportBodyBytes)
+	var req server_structs.TransferReportRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, server_structs.SimpleApiResp{
+			Status: server_structs.RespFailed,
+			Msg:    "Invalid transfer report: " + err.Error(),
+		})
+		return
+	}
+	if len(req.Reports) > server_structs.MaxTransferReportsPerRequest {
+		ctx.JSON(http.StatusBadRequest, server_structs.SimpleApiResp{
+			Status: server_structs.RespFailed,
+			Msg:    "Too many transfer reports in one request",
+		})
+		return
+	}
+
+	subnet, ok := utils.ApplyIPMask(utils.ClientIPAddr(ctx).String())
+	if !ok {
+		ctx.JSON(http.StatusBadRequest, server_structs.SimpleApiResp{
+			Status: server_structs.RespFailed,
+			Msg:    "Unable to determine the client's network",
+		})
+		return
+	}
+
+	serverURLs := getServerURLsByHost()
+	accepted := 0
+	for _, report := range req.Reports {
+		if reason, ok := validateTransferReport(report); !ok {
+			log.Tracef("Ignoring transfer report from %s for %s: %s", subnet, report.ServerURL, reason)
+			continue
+		}
+		reported, err := url.Parse(report.ServerURL)
+		if err != nil || reported.Host == "" {
+			log.Tracef("Ignoring transfer report from %s with invalid server URL %q", subnet, report.ServerURL)
+			continue
+		}
+		serverURL, known := serverURLs[reported.Host]
+		if !known {
+			log.Tracef("Ignoring transfer report from %s for unknown server %s", subnet, report.ServerURL)
+			continue
+		}
+		recordThroughput(subnet, serverURL, report.Bytes, report.Duration)
+		accepted++
+	}
+
+	ctx.JSON(http.StatusOK, server_structs.SimpleApiResp{
+		Status: server_structs.RespOK,
+		Msg:    fmt.Sprintf("Accepted %d of %d transfer reports", accepted, len(req.Reports)),
+	})
+}
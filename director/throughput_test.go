@@ -0,0 +1,182 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package director
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jellydator/ttlcache/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pelicanplatform/pelican/param"
+	"github.com/pelicanplatform/pelican/server_structs"
+	"github.com/pelicanplatform/pelican/server_utils"
+	"github.com/pelicanplatform/pelican/test_utils"
+)
+
+func setupThroughputTest(t *testing.T) {
+	server_utils.ResetTestState()
+	throughputCache.DeleteAll()
+	require.NoError(t, param.Director_ThroughputSortEWMATimeConstant.Set(time.Minute))
+	require.NoError(t, param.Director_ThroughputSortExplorationPercentage.Set(0))
+	t.Cleanup(func() {
+		throughputCache.DeleteAll()
+		serverAds.DeleteAll()
+		server_utils.ResetTestState()
+	})
+}
+
+func TestThroughputStatsUpdate(t *testing.T) {
+	t.Cleanup(test_utils.SetupTestLogging(t))
+	setupThroughputTest(t)
+
+	now := time.Now()
+	var stats throughputStats
+	stats = stats.update(100<<20, 10*time.Second, now)
+	assert.InDelta(t, float64(10<<20), stats.throughput(), 1e-6)
+	assert.Equal(t, 1, stats.Samples)
+
+	// A measurement one time constant later carries about e times the weight
+	// of the first one.
+	stats = stats.update(400<<20, 10*time.Second, now.Add(time.Minute))
+	decay := math.Exp(-1)
+	expected := (decay*float64(100<<20) + float64(400<<20)) / (decay*10 + 10)
+	assert.InDelta(t, expected, stats.throughput(), 1e-3)
+	assert.Equal(t, 2, stats.Samples)
+
+	assert.Zero(t, throughputStats{}.throughput())
+}
+
+func TestHandleTransferReports(t *testing.T) {
+	t.Cleanup(test_utils.SetupTestLogging(t))
+	setupThroughputTest(t)
+	gin.SetMode(gin.TestMode)
+
+	cacheAd := server_structs.ServerAd{
+		URL:     url.URL{Scheme: "https", Host: "cache.example.com:8443"},
+		AuthURL: url.URL{Scheme: "https", Host: "cache-auth.example.com:8444"},
+		Type:    server_structs.CacheType.String(),
+	}
+	cacheAd.Initialize("cache")
+	serverAds.Set(cacheAd.URL.String(), &server_structs.Advertisement{ServerAd: cacheAd}, ttlcache.DefaultTTL)
+
+	post := func(t *testing.T, req server_structs.TransferReportRequest) *httptest.ResponseRecorder {
+		body, err := json.Marshal(req)
+		require.NoError(t, err)
+		w := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(w)
+		ctx.Request = httptest.NewRequest(http.MethodPost, "/api/v1.0/director/transferReports", bytes.NewReader(body))
+		ctx.Request.RemoteAddr = "192.0.2.10:4321"
+		ctx.Request.Header.Set("Content-Type", "application/json")
+		handleTransferReports(ctx)
+		return w
+	}
+
+	w := post(t, server_structs.TransferReportRequest{Reports: []server_structs.TransferReport{
+		{ServerURL: "https://cache.example.com:8443", Bytes: 10 << 20, Duration: time.Second},
+		{ServerURL: "https://cache-auth.example.com:8444", Bytes: 30 << 20, Duration: time.Second},
+		// Ignored: too small, invalid duration, unknown server and implausibly fast
+		{ServerURL: "https://cache.example.com:8443", Bytes: 1024, Duration: time.Second},
+		{ServerURL: "https://cache.example.com:8443", Bytes: 10 << 20, Duration: 0},
+		{ServerURL: "https://unknown.example.com", Bytes: 10 << 20, Duration: time.Second},
+		{ServerURL: "https://cache.example.com:8443", Bytes: 1 << 40, Duration: time.Millisecond},
+	}})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "Accepted 2 of 6")
+
+	// Reports apply to the sender's /24 only
+	stats, found := getThroughput("192.0.2.0", cacheAd.URL.String())
+	require.True(t, found)
+	assert.Equal(t, 2, stats.Samples)
+	assert.InDelta(t, float64(20<<20), stats.throughput(), 1e3)
+	_, found = getThroughput("198.51.100.0", cacheAd.URL.String())
+	assert.False(t, found)
+
+	tooMany := server_structs.TransferReportRequest{Reports: make([]server_structs.TransferReport, server_structs.MaxTransferReportsPerRequest+1)}
+	w = post(t, tooMany)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestThroughputSortAlg(t *testing.T) {
+	t.Cleanup(test_utils.SetupTestLogging(t))
+	setupThroughputTest(t)
+	setupOverrideCache(t) // will map 192.168.1.4 --> Discovery building's lat/long
+
+	clientAddr := netip.MustParseAddr("192.168.1.4")
+	subnet := "192.168.1.0"
+	ads := []server_structs.ServerAd{
+		getAdBase("Chicago", 41.8781, -87.6298),
+		getAdBase("LA", 34.0522, -118.2437),
+		getAdBase("NY", 40.7128, -74.0060),
+	}
+	for i := range ads {
+		ads[i].StatusWeight = 1.0
+	}
+	newCtx := func() SortContext {
+		return SortContext{ClientAddr: clientAddr, RedirectInfo: &server_structs.RedirectInfo{}}
+	}
+	names := func(ads []server_structs.ServerAd) []string {
+		out := make([]string, len(ads))
+		for i, ad := range ads {
+			out[i] = ad.Name
+		}
+		return out
+	}
+
+	t.Run("no-measurements-falls-back-to-distance", func(t *testing.T) {
+		sorted, err := (&ThroughputSort{}).Sort(ads, newCtx())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Chicago", "NY", "LA"}, names(sorted))
+	})
+
+	recordThroughput(subnet, ads[1].URL.String(), 100<<20, time.Second) // LA is fastest
+	recordThroughput(subnet, ads[0].URL.String(), 10<<20, time.Second)
+	recordThroughput(subnet, ads[0].URL.String(), 10<<20, time.Second)
+	// Measurements from another network don't apply
+	recordThroughput("10.0.0.0", ads[2].URL.String(), 1000<<20, time.Second)
+
+	t.Run("sorts-by-throughput", func(t *testing.T) {
+		sCtx := newCtx()
+		sorted, err := (&ThroughputSort{}).Sort(ads, sCtx)
+		require.NoError(t, err)
+		// NY is unmeasured and gets the median of the measured weights
+		assert.Equal(t, []string{"LA", "NY", "Chicago"}, names(sorted))
+		assert.InDelta(t, 1.0, sCtx.RedirectInfo.ServersInfo[ads[1].URL.String()].RedirectWeights.ThroughputWeight, 1e-9)
+		assert.InDelta(t, 0.1, sCtx.RedirectInfo.ServersInfo[ads[0].URL.String()].RedirectWeights.ThroughputWeight, 1e-9)
+		assert.InDelta(t, 0.55, sCtx.RedirectInfo.ServersInfo[ads[2].URL.String()].RedirectWeights.ThroughputWeight, 1e-9)
+	})
+
+	t.Run("exploration-promotes-least-measured", func(t *testing.T) {
+		require.NoError(t, param.Director_ThroughputSortExplorationPercentage.Set(100))
+		t.Cleanup(func() { require.NoError(t, param.Director_ThroughputSortExplorationPercentage.Set(0)) })
+		sorted, err := (&ThroughputSort{}).Sort(ads, newCtx())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"NY", "LA", "Chicago"}, names(sorted))
+	})
+}
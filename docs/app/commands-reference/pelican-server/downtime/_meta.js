@@ -1,6 +1,7 @@
 export default {
     "create": "pelican-server downtime create",
     "delete": "pelican-server downtime delete",
+    "import": "pelican-server downtime import",
     "list": "pelican-server downtime list",
     "update": "pelican-server downtime update",
 }
@@ -1,3 +1,4 @@
 export default {
     "create": "pelican-server key create",
+    "rotate": "pelican-server key rotate",
 }
@@ -1,3 +1,4 @@
 export default {
+    "search": "pelican-server registry search",
     "serve": "pelican-server registry serve",
 }
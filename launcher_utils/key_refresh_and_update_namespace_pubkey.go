@@ -33,89 +33,156 @@ import (
 	"github.com/pelicanplatform/pelican/registry/registry_client"
 	"github.com/pelicanplatform/pelican/server_structs"
 	"github.com/pelicanplatform/pelican/server_utils"
+	"github.com/pelicanplatform/pelican/utils/registry_jwks"
 )
 
-func updateNamespacesPubKeyPrep(ctx context.Context, prefixes []string) (jwk.Key, string, error) {
+// updateNamespacesPubKeyPrep returns the issuer key of the server and the public key
+// update endpoints of the federation's registry followed by those of its replicas.
+func updateNamespacesPubKeyPrep(ctx context.Context, prefixes []string) (jwk.Key, []string, error) {
 	// Validate the namespace format
 	for _, prefix := range prefixes {
 		if prefix == "" {
 			err := errors.New("Invalid empty prefix for public key update")
-			return nil, "", err
+			return nil, nil, err
 		}
 		if prefix[0] != '/' {
 			err := errors.New("Prefix specified for public key update must start with a '/'")
-			return nil, "", err
+			return nil, nil, err
 		}
 	}
 
 	// Generate the endpoint url that can update the public key of prefixes
 	fedInfo, err := config.GetFederation(ctx)
 	if err != nil {
-		return nil, "", err
+		return nil, nil, err
 	}
-	registryEndpoint := fedInfo.RegistryEndpoint
-	if registryEndpoint == "" {
+	if fedInfo.RegistryEndpoint == "" {
 		err = errors.New("No registry endpoint specified; try passing the `-f` flag specifying the federation name")
-		return nil, "", err
+		return nil, nil, err
 	}
 
-	prefixPubKeyUpdateUrl, err := url.JoinPath(registryEndpoint, "api", "v1.0", "registry", "updateNamespacesPubKey")
+	registryEndpoints, err := registry_jwks.GetRegistryEndpoints(ctx)
 	if err != nil {
-		err = errors.Wrap(err, "Failed to construct public key update endpoint URL: %v")
-		return nil, "", err
+		return nil, nil, err
+	}
+	prefixPubKeyUpdateUrls := make([]string, 0, len(registryEndpoints))
+	for _, endpoint := range registryEndpoints {
+		prefixPubKeyUpdateUrl, err := url.JoinPath(endpoint, "api", "v1.0", "registry", "updateNamespacesPubKey")
+		if err != nil {
+			err = errors.Wrapf(err, "Failed to construct public key update endpoint URL for registry %s", endpoint)
+			return nil, nil, err
+		}
+		prefixPubKeyUpdateUrls = append(prefixPubKeyUpdateUrls, prefixPubKeyUpdateUrl)
 	}
 
 	// Obtain server's issuer private key
 	key, err := config.GetIssuerPrivateJWK()
 	if err != nil {
 		err = errors.Wrap(err, "Failed to obtain server's issuer private key")
-		return nil, "", err
+		return nil, nil, err
 	}
 
-	return key, prefixPubKeyUpdateUrl, nil
+	return key, prefixPubKeyUpdateUrls, nil
 }
 
+// updateNamespacesPubKey registers the server's current public key for the prefixes,
+// trying the replicas of the registry in order if it can't be reached.
 func updateNamespacesPubKey(ctx context.Context, prefixes []string) error {
 	siteName := param.Xrootd_Sitename.GetString()
 
-	key, url, err := updateNamespacesPubKeyPrep(ctx, prefixes)
+	key, urls, err := updateNamespacesPubKeyPrep(ctx, prefixes)
 	if err != nil {
 		return err
 	}
-	if err = registry_client.NamespacesPubKeyUpdate(key, prefixes, siteName, url); err != nil {
-		return err
+	for idx, url := range urls {
+		if err = registry_client.NamespacesPubKeyUpdate(key, prefixes, siteName, url); err == nil {
+			return nil
+		}
+		if idx < len(urls)-1 {
+			log.Warningf("Failed to update the public key of namespace(s) at %s, trying the next registry: %v", url, err)
+		}
 	}
-	return nil
+	return err
 }
 
-func triggerNamespacesPubKeyUpdate(ctx context.Context) error {
+// getOriginPrefixes returns the origin's own namespace followed by the namespaces it exports
+func getOriginPrefixes() (originNs string, exportNs []string, err error) {
 	extUrlStr := param.Server_ExternalWebUrl.GetString()
 	extUrl, _ := url.Parse(extUrlStr)
-	namespace := server_structs.GetOriginNs(extUrl.Host)
-	if err := updateNamespacesPubKey(ctx, []string{namespace}); err != nil {
-		log.Errorf("Error updating the public key of the registered origin namespace %s: %v", namespace, err)
-	}
+	originNs = server_structs.GetOriginNs(extUrl.Host)
 
 	originExports, err := server_utils.GetOriginExports()
 	if err != nil {
-		return err
+		return
 	}
-	originExportsNs := make([]string, len(originExports))
+	exportNs = make([]string, len(originExports))
 	for i, export := range originExports {
-		originExportsNs[i] = export.FederationPrefix
+		exportNs[i] = export.FederationPrefix
 	}
+	return
+}
+
+func triggerNamespacesPubKeyUpdate(ctx context.Context) error {
+	namespace, originExportsNs, err := getOriginPrefixes()
+	if err := updateNamespacesPubKey(ctx, []string{namespace}); err != nil {
+		log.Errorf("Error updating the public key of the registered origin namespace %s: %v", namespace, err)
+	}
+	if err != nil {
+		return err
+	}
+
 	if err := updateNamespacesPubKey(ctx, originExportsNs); err != nil {
 		log.Errorf("Error updating the public key of origin-exported namespace(s): %v", err)
 	}
 	return nil
 }
 
+// originKeyPublishedCheck confirms the registry lists the new key of an issuer key rotation
+// for every namespace of the origin before the origin starts signing with it; otherwise
+// tokens signed by the new key would fail verification. If the registry doesn't have
+// the key yet, the origin's public keys are pushed again and the switch is postponed.
+func originKeyPublishedCheck(ctx context.Context) config.KeyPublishedCheck {
+	return func(newKey jwk.Key) (bool, error) {
+		fedInfo, err := config.GetFederation(ctx)
+		if err != nil {
+			return false, err
+		}
+		if fedInfo.RegistryEndpoint == "" {
+			return false, errors.New("no registry endpoint is known to check the new key against")
+		}
+		registryUrl, err := url.JoinPath(fedInfo.RegistryEndpoint, "api", "v1.0", "registry")
+		if err != nil {
+			return false, errors.Wrap(err, "failed to construct the registry URL")
+		}
+		originNs, exportNs, err := getOriginPrefixes()
+		if err != nil {
+			return false, err
+		}
+		for _, prefix := range append([]string{originNs}, exportNs...) {
+			status, err := keyIsRegistered(newKey, registryUrl, prefix)
+			if err != nil {
+				return false, errors.Wrapf(err, "failed to check the registered keys of namespace %s", prefix)
+			}
+			// Namespaces that aren't registered yet will be registered with the new key
+			if status == keyMismatch {
+				log.Infof("The registry doesn't list issuer key %s for namespace %s yet; updating the registered public keys", newKey.KeyID(), prefix)
+				if err = triggerNamespacesPubKeyUpdate(ctx); err != nil {
+					return false, err
+				}
+				return false, nil
+			}
+		}
+		return true, nil
+	}
+}
+
 // KeyChangeCallback is a callback function type that is called when issuer keys change.
 // The callback receives the context and should return an error if the operation fails.
 type KeyChangeCallback func(ctx context.Context) error
 
 // Check the directory containing .pem files regularly, load new private key(s)
 // For origin server, if new file(s) are detected, then register the new public key
+// Any issuer key rotation in progress is advanced on the same schedule.
 // Optional callbacks can be provided to be invoked when keys change.
 func LaunchIssuerKeysDirRefresh(ctx context.Context, egrp *errgroup.Group, modules server_structs.ServerType, callbacks ...KeyChangeCallback) {
 	server_utils.LaunchWatcherMaintenance(
@@ -124,6 +191,15 @@ func LaunchIssuerKeysDirRefresh(ctx context.Context, egrp *errgroup.Group, modul
 		"private key refresh and registration",
 		time.Minute,
 		func( /*notifyEvent*/ bool) error {
+			// Generate, switch to or retire keys if an issuer key rotation is due
+			var publishedCheck config.KeyPublishedCheck
+			if modules.IsEnabled(server_structs.OriginType) {
+				publishedCheck = originKeyPublishedCheck(ctx)
+			}
+			if _, err := config.AdvanceIssuerKeyRotation(time.Now(), publishedCheck); err != nil {
+				log.Errorf("Failed to advance the issuer key rotation: %v", err)
+			}
+
 			// Refresh the disk to pick up any new private key
 			keysChanged, err := config.RefreshKeys()
 			if err != nil {
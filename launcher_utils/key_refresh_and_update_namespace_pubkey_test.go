@@ -0,0 +1,117 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package launcher_utils
+
+import (
+	"context"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pelicanplatform/pelican/config"
+	"github.com/pelicanplatform/pelican/database"
+	"github.com/pelicanplatform/pelican/param"
+	"github.com/pelicanplatform/pelican/registry"
+	"github.com/pelicanplatform/pelican/server_structs"
+	"github.com/pelicanplatform/pelican/server_utils"
+	"github.com/pelicanplatform/pelican/test_utils"
+)
+
+func TestOriginKeyRotation(t *testing.T) {
+	t.Cleanup(test_utils.SetupTestLogging(t))
+	t.Cleanup(func() {
+		server_utils.ResetTestState()
+	})
+	tempConfigDir := t.TempDir()
+
+	ctx, cancel, egrp := test_utils.TestContext(context.Background(), t)
+	defer func() { require.NoError(t, egrp.Wait()) }()
+	defer cancel()
+
+	server_utils.ResetTestState()
+	require.NoError(t, param.ConfigDir.Set(tempConfigDir))
+	test_utils.MockFederationRoot(t, nil, nil)
+	require.NoError(t, param.IssuerKeysDirectory.Set(filepath.Join(tempConfigDir, "issuer-keys")))
+	require.NoError(t, param.Server_DbLocation.Set(filepath.Join(tempConfigDir, "test.sql")))
+	require.NoError(t, database.InitServerDatabase(server_structs.RegistryType))
+	defer func() {
+		require.NoError(t, database.ShutdownDB())
+	}()
+
+	gin.SetMode(gin.TestMode)
+	engine := gin.Default()
+	registry.RegisterRegistryAPI(engine.Group("/"))
+	svr := httptest.NewServer(engine)
+	defer svr.Close()
+
+	require.NoError(t, param.Set(param.Federation_RegistryUrl, svr.URL))
+	require.NoError(t, param.Origin_StorageType.Set(string(server_structs.OriginStoragePosix)))
+	require.NoError(t, param.Origin_FederationPrefix.Set("/test123"))
+	require.NoError(t, param.Origin_StoragePrefix.Set(t.TempDir()))
+	require.NoError(t, config.InitServer(ctx, server_structs.OriginType))
+
+	oldKey, err := config.GetIssuerPrivateJWK()
+	require.NoError(t, err)
+	require.NoError(t, registerNamespaceImpl(oldKey, "/test123", "mock_site_name", []string{svr.URL + "/api/v1.0/registry"}))
+
+	state, err := config.StartIssuerKeyRotation(time.Now())
+	require.NoError(t, err)
+	_, err = config.RefreshKeys()
+	require.NoError(t, err)
+	newKey := config.GetIssuerPrivateKeys()[state.NewKeyID]
+	require.NotNil(t, newKey)
+
+	// The registry doesn't know the new key yet, so the check pushes it and holds off the switch
+	published := originKeyPublishedCheck(ctx)
+	isPublished, err := published(newKey)
+	require.NoError(t, err)
+	assert.False(t, isPublished)
+	isPublished, err = published(newKey)
+	require.NoError(t, err)
+	assert.True(t, isPublished)
+
+	changed, err := config.AdvanceIssuerKeyRotation(state.SwitchAt, published)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	_, err = config.RefreshKeys()
+	require.NoError(t, err)
+	current, err := config.GetIssuerPrivateJWK()
+	require.NoError(t, err)
+	assert.Equal(t, state.NewKeyID, current.KeyID())
+
+	// Once the old key is retired, the registry is updated to only the new key
+	changed, err = config.AdvanceIssuerKeyRotation(state.SwitchAt.Add(param.Server_IssuerKeyRotation_OverlapPeriod.GetDuration()), published)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	keysChanged, err := config.RefreshKeys()
+	require.NoError(t, err)
+	assert.True(t, keysChanged)
+	require.NoError(t, updateNamespacesPubKey(ctx, []string{"/test123"}))
+	status, err := keyIsRegistered(oldKey, svr.URL+"/api/v1.0/registry", "/test123")
+	require.NoError(t, err)
+	assert.Equal(t, keyMismatch, status)
+	status, err = keyIsRegistered(newKey, svr.URL+"/api/v1.0/registry", "/test123")
+	require.NoError(t, err)
+	assert.Equal(t, keyMatch, status)
+}
@@ -40,6 +40,7 @@ import (
 	"github.com/pelicanplatform/pelican/param"
 	"github.com/pelicanplatform/pelican/registry/registry_client"
 	"github.com/pelicanplatform/pelican/server_structs"
+	"github.com/pelicanplatform/pelican/utils/registry_jwks"
 )
 
 type (
@@ -199,7 +200,10 @@ func keyIsRegistered(privkey jwk.Key, registryUrlStr string, prefix string) (key
 	}
 }
 
-func registerNamespacePrep(ctx context.Context, prefix string) (key jwk.Key, registrationUrl string, isRegistered bool, err error) {
+// registerNamespacePrep returns the issuer key of the server, the registration endpoints
+// of the federation's registry followed by those of its replicas, and whether the key is
+// already registered for the prefix.
+func registerNamespacePrep(ctx context.Context, prefix string) (key jwk.Key, registrationUrls []string, isRegistered bool, err error) {
 	// TODO: We eventually want to be able to export multiple prefixes; at that point, we'll
 	// refactor to loop around all the namespaces
 	if prefix == "" {
@@ -221,11 +225,19 @@ func registerNamespacePrep(ctx context.Context, prefix string) (key jwk.Key, reg
 		return
 	}
 
-	registrationUrl, err = url.JoinPath(registryEndpoint, "api", "v1.0", "registry")
+	registryEndpoints, err := registry_jwks.GetRegistryEndpoints(ctx)
 	if err != nil {
-		err = errors.Wrap(err, "Failed to construct registration endpoint URL: %v")
 		return
 	}
+	for _, endpoint := range registryEndpoints {
+		var registrationUrl string
+		registrationUrl, err = url.JoinPath(endpoint, "api", "v1.0", "registry")
+		if err != nil {
+			err = errors.Wrapf(err, "Failed to construct registration endpoint URL for registry %s", endpoint)
+			return
+		}
+		registrationUrls = append(registrationUrls, registrationUrl)
+	}
 	key, err = config.GetIssuerPrivateJWK()
 	if err != nil {
 		err = errors.Wrap(err, "failed to load the origin's JWK")
@@ -237,12 +249,20 @@ func registerNamespacePrep(ctx context.Context, prefix string) (key jwk.Key, reg
 			return
 		}
 	}
-	keyStatus, err := keyIsRegistered(key, registrationUrl, prefix)
+	var status keyStatus
+	for idx, registrationUrl := range registrationUrls {
+		if status, err = keyIsRegistered(key, registrationUrl, prefix); err == nil {
+			break
+		}
+		if idx < len(registrationUrls)-1 {
+			log.Warningf("Failed to check the registration of namespace %s at %s, trying the next registry: %v", prefix, registrationUrl, err)
+		}
+	}
 	if err != nil {
 		err = errors.Wrap(err, "Failed to determine whether namespace is already registered")
 		return
 	}
-	switch keyStatus {
+	switch status {
 	case keyMatch:
 		isRegistered = true
 		return
@@ -255,8 +275,18 @@ func registerNamespacePrep(ctx context.Context, prefix string) (key jwk.Key, reg
 	return
 }
 
-func registerNamespaceImpl(key jwk.Key, prefix string, siteName string, registrationEndpointURL string) error {
-	if err := registry_client.NamespaceRegister(key, registrationEndpointURL, "", prefix, siteName); err != nil {
+// registerNamespaceImpl registers the prefix under the key, trying the replicas of the
+// registry in order if it can't be reached.
+func registerNamespaceImpl(key jwk.Key, prefix string, siteName string, registrationEndpointURLs []string) (err error) {
+	for idx, registrationEndpointURL := range registrationEndpointURLs {
+		if err = registry_client.NamespaceRegister(key, registrationEndpointURL, "", prefix, siteName); err == nil {
+			break
+		}
+		if idx < len(registrationEndpointURLs)-1 {
+			log.Warningf("Failed to register prefix %s at %s, trying the next registry: %v", prefix, registrationEndpointURL, err)
+		}
+	}
+	if err != nil {
 		metrics.SetComponentHealthStatus(metrics.OriginCache_Registry, metrics.StatusCritical, fmt.Sprintf("XRootD server failed to register its namespace %s at the registry: %v", prefix, err))
 		return errors.Wrapf(err, "Failed to register prefix %s", prefix)
 	}
@@ -276,7 +306,7 @@ func RegisterNamespaceWithRetry(ctx context.Context, egrp *errgroup.Group, prefi
 		return errors.Errorf("Server name isn't set. Please set the name via %s", param.Xrootd_Sitename.GetName())
 	}
 
-	key, url, isRegistered, err := registerNamespacePrep(ctx, prefix)
+	key, urls, isRegistered, err := registerNamespacePrep(ctx, prefix)
 	if err != nil {
 		return err
 	}
@@ -289,7 +319,7 @@ func RegisterNamespaceWithRetry(ctx context.Context, egrp *errgroup.Group, prefi
 		return nil
 	}
 
-	if err = registerNamespaceImpl(key, prefix, siteName, url); err == nil {
+	if err = registerNamespaceImpl(key, prefix, siteName, urls); err == nil {
 		return nil
 	}
 	log.Errorf("Failed to register with namespace service: %v; will automatically retry in 10 seconds\n", err)
@@ -302,7 +332,7 @@ func RegisterNamespaceWithRetry(ctx context.Context, egrp *errgroup.Group, prefi
 		for {
 			select {
 			case <-ticker.C:
-				if err := registerNamespaceImpl(key, prefix, siteName, url); err == nil {
+				if err := registerNamespaceImpl(key, prefix, siteName, urls); err == nil {
 					if err := origin.FetchAndSetRegStatus(prefix); err != nil {
 						log.Errorf("failed to fetch registration status for the prefix %s: %v", prefix, err)
 					}
@@ -105,11 +105,11 @@ func TestRegistration(t *testing.T) {
 
 	// Test registration succeeds
 	prefix := param.Origin_FederationPrefix.GetString()
-	key, registerURL, isRegistered, err := registerNamespacePrep(ctx, prefix)
+	key, registerURLs, isRegistered, err := registerNamespacePrep(ctx, prefix)
 	require.NoError(t, err)
 	assert.False(t, isRegistered)
-	assert.Equal(t, registerURL, svr.URL+"/api/v1.0/registry")
-	err = registerNamespaceImpl(key, prefix, "mock_site_name", registerURL)
+	assert.Equal(t, []string{svr.URL + "/api/v1.0/registry"}, registerURLs)
+	err = registerNamespaceImpl(key, prefix, "mock_site_name", registerURLs)
 	require.NoError(t, err)
 
 	// Test we can query for the new key
@@ -164,9 +164,9 @@ func TestRegistration(t *testing.T) {
 
 	// Redo the namespace prep, ensure that isRegistered is true
 	prefix = param.Origin_FederationPrefix.GetString()
-	_, registerURL, isRegistered, err = registerNamespacePrep(ctx, prefix)
+	_, registerURLs, isRegistered, err = registerNamespacePrep(ctx, prefix)
 	assert.True(t, isRegistered)
-	assert.Equal(t, svr.URL+"/api/v1.0/registry", registerURL)
+	assert.Equal(t, []string{svr.URL + "/api/v1.0/registry"}, registerURLs)
 	assert.NoError(t, err)
 }
 
@@ -267,11 +267,11 @@ func TestMultiKeysRegistration(t *testing.T) {
 
 	// Test registration succeeds
 	prefix := param.Origin_FederationPrefix.GetString()
-	key, registerURL, isRegistered, err := registerNamespacePrep(ctx, prefix)
+	key, registerURLs, isRegistered, err := registerNamespacePrep(ctx, prefix)
 	require.NoError(t, err)
 	assert.False(t, isRegistered)
-	assert.Equal(t, registerURL, svr.URL+"/api/v1.0/registry")
-	err = registerNamespaceImpl(key, prefix, "mock_site_name", registerURL)
+	assert.Equal(t, []string{svr.URL + "/api/v1.0/registry"}, registerURLs)
+	err = registerNamespaceImpl(key, prefix, "mock_site_name", registerURLs)
 	require.NoError(t, err)
 
 	// Test we can query for the new key
@@ -326,8 +326,66 @@ func TestMultiKeysRegistration(t *testing.T) {
 
 	// Redo the namespace prep, ensure that isRegistered is true
 	prefix = param.Origin_FederationPrefix.GetString()
-	_, registerURL, isRegistered, err = registerNamespacePrep(ctx, prefix)
+	_, registerURLs, isRegistered, err = registerNamespacePrep(ctx, prefix)
 	require.NoError(t, err)
 	assert.True(t, isRegistered)
-	assert.Equal(t, svr.URL+"/api/v1.0/registry", registerURL)
+	assert.Equal(t, []string{svr.URL + "/api/v1.0/registry"}, registerURLs)
+}
+
+func TestRegistrationWithRegistryReplica(t *testing.T) {
+	t.Cleanup(test_utils.SetupTestLogging(t))
+	t.Cleanup(func() {
+		server_utils.ResetTestState()
+	})
+	tempConfigDir := t.TempDir()
+
+	ctx, cancel, egrp := test_utils.TestContext(context.Background(), t)
+	defer func() { require.NoError(t, egrp.Wait()) }()
+	defer cancel()
+
+	server_utils.ResetTestState()
+	require.NoError(t, param.ConfigDir.Set(tempConfigDir))
+	test_utils.MockFederationRoot(t, nil, nil)
+	require.NoError(t, param.IssuerKeysDirectory.Set(filepath.Join(tempConfigDir, "issuer-keys")))
+	require.NoError(t, param.Server_DbLocation.Set(filepath.Join(tempConfigDir, "test.sql")))
+	require.NoError(t, database.InitServerDatabase(server_structs.RegistryType))
+	defer func() {
+		require.NoError(t, database.ShutdownDB())
+	}()
+
+	gin.SetMode(gin.TestMode)
+	engine := gin.Default()
+	registry.RegisterRegistryAPI(engine.Group("/"))
+	replica := httptest.NewServer(engine)
+	defer replica.Close()
+
+	// The main registry is down
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+	require.NoError(t, param.Set(param.Federation_RegistryUrl, down.URL))
+	require.NoError(t, param.Federation_RegistryReplicaUrls.Set([]string{replica.URL}))
+	require.NoError(t, config.InitServer(ctx, server_structs.OriginType))
+
+	_, urls, err := updateNamespacesPubKeyPrep(ctx, []string{"/test123"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		down.URL + "/api/v1.0/registry/updateNamespacesPubKey",
+		replica.URL + "/api/v1.0/registry/updateNamespacesPubKey",
+	}, urls)
+
+	// New registrations also fail over to the replica
+	key, registerURLs, isRegistered, err := registerNamespacePrep(ctx, "/test123")
+	require.NoError(t, err)
+	assert.False(t, isRegistered)
+	assert.Equal(t, []string{down.URL + "/api/v1.0/registry", replica.URL + "/api/v1.0/registry"}, registerURLs)
+	require.NoError(t, registerNamespaceImpl(key, "/test123", "mock_site_name", registerURLs))
+
+	_, _, isRegistered, err = registerNamespacePrep(ctx, "/test123")
+	require.NoError(t, err)
+	assert.True(t, isRegistered)
+
+	assert.NoError(t, updateNamespacesPubKey(ctx, []string{"/test123"}))
+
+	require.NoError(t, param.Federation_RegistryReplicaUrls.Set([]string{}))
+	assert.Error(t, updateNamespacesPubKey(ctx, []string{"/test123"}))
 }
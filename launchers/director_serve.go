@@ -54,6 +54,8 @@ func DirectorServe(ctx context.Context, engine *gin.Engine, egrp *errgroup.Group
 
 	director.ConfigFilteredServers()
 
+	director.LaunchStatePersistence(ctx, egrp)
+
 	director.PeriodicFedDowntimeReload(ctx, egrp)
 
 	director.LaunchServerIOQuery(ctx, egrp)
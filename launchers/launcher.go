@@ -26,6 +26,7 @@ import (
 	"net/url"
 	"os"
 	"os/signal"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
@@ -47,6 +48,7 @@ import (
 	"github.com/pelicanplatform/pelican/param"
 	"github.com/pelicanplatform/pelican/server_structs"
 	"github.com/pelicanplatform/pelican/server_utils"
+	"github.com/pelicanplatform/pelican/tracing"
 	"github.com/pelicanplatform/pelican/web_ui"
 )
 
@@ -80,6 +82,11 @@ func LaunchModules(ctx context.Context, modules server_structs.ServerType) (serv
 		return
 	}
 
+	if err = tracing.InitTracing(ctx, egrp, "pelican-"+strings.Join(config.GetEnabledServerString(true), "-")); err != nil {
+		err = errors.Wrap(err, "Failure when configuring tracing")
+		return
+	}
+
 	// After config is loaded, check if director should enable broker
 	if modules.IsEnabled(server_structs.DirectorType) && param.Director_EnableBroker.GetBool() {
 		modules.Set(server_structs.BrokerType)
@@ -55,8 +55,7 @@ func OriginServe(ctx context.Context, engine *gin.Engine, egrp *errgroup.Group,
 	}
 
 	// Determine if we should use XRootD or native HTTP server
-	storageType := param.Origin_StorageType.GetString()
-	useXRootD := storageType != string(server_structs.OriginStoragePosixv2) && storageType != string(server_structs.OriginStorageSSH)
+	useXRootD := server_utils.OriginUsesXRootD()
 
 	if useXRootD {
 		metrics.SetComponentHealthStatus(metrics.OriginCache_XRootD, metrics.StatusWarning, "XRootD is initializing")
@@ -67,7 +66,7 @@ func OriginServe(ctx context.Context, engine *gin.Engine, egrp *errgroup.Group,
 			return nil, err
 		}
 	} else {
-		log.Info("Initializing POSIXv2 origin backend")
+		log.Infof("Initializing native %s origin backend", param.Origin_StorageType.GetString())
 		// For non-XRootD backends, start the shoveler if enabled so that
 		// internally-generated monitoring packets reach the message queue.
 		if param.Shoveler_Enable.GetBool() {
@@ -226,7 +225,7 @@ func OriginServeFinish(ctx context.Context, egrp *errgroup.Group, engine *gin.En
 
 	// Handle POSIXv2 and SSH-specific initialization now that the web server is running
 	storageType := param.Origin_StorageType.GetString()
-	useXRootD := storageType != string(server_structs.OriginStoragePosixv2) && storageType != string(server_structs.OriginStorageSSH)
+	useXRootD := server_utils.OriginUsesXRootD()
 	if !useXRootD {
 		// For SSH backend, initialize the SSH connection before setting up handlers
 		if storageType == string(server_structs.OriginStorageSSH) {
@@ -262,6 +261,7 @@ func OriginServeFinish(ctx context.Context, egrp *errgroup.Group, engine *gin.En
 		if err := origin_serve.InitializeHandlers(ctx, originExports); err != nil {
 			return errors.Wrap(err, "failed to initialize origin_serve handlers")
 		}
+		origin_serve.LaunchUploadStagingCleanup(ctx, egrp)
 
 		directorEnabled := modules.IsEnabled(server_structs.DirectorType)
 		if err := origin_serve.RegisterHandlers(engine, directorEnabled); err != nil {
@@ -75,6 +75,11 @@ func RegistryServe(ctx context.Context, engine *gin.Engine, egrp *errgroup.Group
 	// Launch registry prometheus metrics
 	registry.LaunchRegistryMetrics(ctx, egrp)
 
+	// Exchange registration changes with other registry instances
+	if err := registry.LaunchRegistryReplication(ctx, egrp); err != nil {
+		return errors.Wrap(err, "unable to start registry replication")
+	}
+
 	egrp.Go(func() error {
 		<-ctx.Done()
 		return database.ShutdownDB()
@@ -0,0 +1,648 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package local_cache
+
+// Transparent block compression
+//
+// Text, CSV and similar objects often shrink considerably when compressed.
+// When compression is enabled, the consistency checker's data scan rewrites
+// each verified object in compressed form:
+//
+//   - Every block is compressed with zstd before it is sealed, and is
+//     stored compressed only if that makes it smaller; other blocks keep
+//     their original ciphertext.
+//   - The object is only rewritten if its blocks shrink by at least
+//     compressionMinSavingsPercent, judged first on a sample of its
+//     leading blocks and then on the result.
+//   - The compressed files are written next to the originals under
+//     "<hash>.z" (and "<hash>.z-N" for chunks), the metadata switches to
+//     them in one transaction, the originals are removed and the usage
+//     charge is reduced to the new size.
+//
+// A compressed data file starts with a header giving the stored length of
+// every block, followed by the blocks back to back:
+//
+//	"PCZ1" | block count (uint32) | stored length per block (uint16) | blocks
+//
+// A block whose stored length is its plaintext length plus AuthTagSize is
+// stored as is; a shorter one is compressed.  The lengths let readers find
+// any block without touching its predecessors, so range reads are still
+// served from block boundaries.
+//
+// Compressed blocks are sealed with the object's DEK under a nonce from a
+// separate domain (see compressedNonceDomain), so a block's compressed
+// form never shares a nonce with the block as originally written.
+//
+// Compressed objects are read-only: WriteBlocks and NewBlockWriter refuse
+// them, and auto-repair removes a corrupt compressed object instead of
+// patching it.
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/dgraph-io/badger/v4"
+	ristretto "github.com/dgraph-io/ristretto/v2"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// CompressionCodec identifies how an object's blocks are compressed.
+type CompressionCodec uint8
+
+const (
+	CompressionNone CompressionCodec = 0
+	CompressionZstd CompressionCodec = 1
+)
+
+const (
+	// compressedFileSuffix is appended to the name of a data file to name
+	// its compressed rewrite (before any chunk suffix).
+	compressedFileSuffix = ".z"
+
+	// compressedMagic starts every compressed data file.
+	compressedMagic = "PCZ1"
+
+	// compressedHeaderFixedSize is the size of the magic and block count
+	// that precede the per-block lengths.
+	compressedHeaderFixedSize = 8
+
+	// compressedIndexStride is the number of blocks between the file
+	// offsets kept by compressedIndex.
+	compressedIndexStride = 64
+
+	// compressionSampleBlocks is the number of leading blocks compressed
+	// to judge whether rewriting an object is worthwhile.
+	compressionSampleBlocks = 64
+
+	// compressionMinSavingsPercent is the minimum space saving, in percent
+	// of the on-disk size, for an object to be stored compressed.
+	compressionMinSavingsPercent = 10
+
+	// compressedNonceDomain is XOR-ed into the first byte of a block's
+	// nonce when sealing its compressed form.  Block nonces otherwise only
+	// vary in their last four bytes (see BlockEncryptor.blockNonce).
+	compressedNonceDomain = 0x01
+)
+
+// errCompressedObject is returned when writing to an object whose data
+// files have been compressed.
+var errCompressedObject = errors.New("object data is compressed and cannot be modified")
+
+// String returns the codec's name.
+func (c CompressionCodec) String() string {
+	switch c {
+	case CompressionNone:
+		return "none"
+	case CompressionZstd:
+		return "zstd"
+	default:
+		return "unknown"
+	}
+}
+
+// The zstd encoder and decoder are safe for concurrent EncodeAll and
+// DecodeAll calls, so one of each is shared.  The checksum is left out
+// since every block is authenticated by AES-GCM.
+var (
+	zstdEncoder = sync.OnceValues(func() (*zstd.Encoder, error) {
+		return zstd.NewWriter(nil, zstd.WithEncoderCRC(false), zstd.WithSingleSegment(true))
+	})
+	zstdDecoder = sync.OnceValues(func() (*zstd.Decoder, error) {
+		return zstd.NewReader(nil, zstd.WithDecoderConcurrency(0))
+	})
+)
+
+// sealCompressedBlock encrypts the compressed form of block blockNum and
+// appends the result to dst.  To seal in place, pass data[:0] as dst.
+func (be *BlockEncryptor) sealCompressedBlock(dst []byte, blockNum uint32, data []byte) []byte {
+	var nonce [NonceSize]byte
+	be.blockNonce(&nonce, blockNum)
+	nonce[0] ^= compressedNonceDomain
+	return be.gcm.Seal(dst, nonce[:], data, nil)
+}
+
+// openCompressedBlock decrypts a block sealed by sealCompressedBlock and
+// appends the compressed data to dst.  To decrypt in place, pass
+// sealed[:0] as dst.
+func (be *BlockEncryptor) openCompressedBlock(dst []byte, blockNum uint32, sealed []byte) ([]byte, error) {
+	var nonce [NonceSize]byte
+	be.blockNonce(&nonce, blockNum)
+	nonce[0] ^= compressedNonceDomain
+	return be.gcm.Open(dst, nonce[:], sealed, nil)
+}
+
+// blockDataLen returns the plaintext size of block blockNum of a data file
+// holding contentLength bytes.  Only the last block may be short.
+func blockDataLen(contentLength int64, blockNum uint32) int {
+	return int(min(contentLength-int64(blockNum)*BlockDataSize, BlockDataSize))
+}
+
+// compressedHeaderSize returns the header size of a compressed data file
+// holding the given number of blocks.
+func compressedHeaderSize(blocks uint32) int64 {
+	return compressedHeaderFixedSize + 2*int64(blocks)
+}
+
+// compressedIndex locates the blocks of a compressed data file.
+type compressedIndex struct {
+	lengths     []uint16 // Stored length of each block
+	checkpoints []int64  // File offset of every compressedIndexStride'th block
+}
+
+// offset returns the file offset of block blockNum.  Passing the block
+// count returns the end of the data.
+func (ix *compressedIndex) offset(blockNum uint32) int64 {
+	first := blockNum / compressedIndexStride * compressedIndexStride
+	offset := ix.checkpoints[first/compressedIndexStride]
+	for block := first; block < blockNum; block++ {
+		offset += int64(ix.lengths[block])
+	}
+	return offset
+}
+
+// readCompressedIndex reads and validates the header of a compressed data
+// file holding contentLength bytes.
+func readCompressedIndex(file *os.File, contentLength int64) (*compressedIndex, error) {
+	blocks := CalculateBlockCount(contentLength)
+	header := make([]byte, compressedHeaderSize(blocks))
+	if n, err := file.ReadAt(header, 0); n < len(header) {
+		if err == nil || err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, errors.Wrap(err, "failed to read compressed file header")
+	}
+	if string(header[:len(compressedMagic)]) != compressedMagic {
+		return nil, errors.New("not a compressed data file")
+	}
+	if count := binary.LittleEndian.Uint32(header[len(compressedMagic):]); count != blocks {
+		return nil, errors.Errorf("compressed data file holds %d blocks, expected %d", count, blocks)
+	}
+
+	ix := &compressedIndex{
+		lengths:     make([]uint16, blocks),
+		checkpoints: make([]int64, 0, blocks/compressedIndexStride+1),
+	}
+	offset := int64(len(header))
+	for block := uint32(0); block <= blocks; block++ {
+		if block%compressedIndexStride == 0 {
+			ix.checkpoints = append(ix.checkpoints, offset)
+		}
+		if block == blocks {
+			break
+		}
+		length := binary.LittleEndian.Uint16(header[compressedHeaderFixedSize+2*int(block):])
+		if int(length) <= AuthTagSize || int(length) > blockDataLen(contentLength, block)+AuthTagSize {
+			return nil, errors.Errorf("invalid stored length %d for block %d", length, block)
+		}
+		ix.lengths[block] = length
+		offset += int64(length)
+	}
+	return ix, nil
+}
+
+// compressedIndex returns the block index of a compressed data file
+// holding contentLength bytes, reading it on first use.
+func (rc *refCountedFile) compressedIndex(contentLength int64) (*compressedIndex, error) {
+	rc.indexOnce.Do(func() {
+		rc.index, rc.indexErr = readCompressedIndex(rc.f, contentLength)
+	})
+	return rc.index, rc.indexErr
+}
+
+// openStoredBlock decrypts block blockNum of a compressed data file,
+// decompressing it if necessary, and appends its dataLen bytes of
+// plaintext to dst.  A compressed block is decrypted in place, so stored
+// is overwritten.
+func openStoredBlock(encryptor *BlockEncryptor, dst []byte, blockNum uint32, stored []byte, dataLen int) ([]byte, error) {
+	if len(stored) == dataLen+AuthTagSize {
+		plain, err := encryptor.DecryptBlockTo(dst, blockNum, stored)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decrypt block %d", blockNum)
+		}
+		return plain, nil
+	}
+
+	packed, err := encryptor.openCompressedBlock(stored[:0], blockNum, stored)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decrypt block %d", blockNum)
+	}
+	decoder, err := zstdDecoder()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create zstd decoder")
+	}
+	plain, err := decoder.DecodeAll(packed, dst)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decompress block %d", blockNum)
+	}
+	if len(plain)-len(dst) != dataLen {
+		return nil, errors.Errorf("block %d decompressed to %d bytes, expected %d", blockNum, len(plain)-len(dst), dataLen)
+	}
+	return plain, nil
+}
+
+// decryptCompressedBlocksFromFile is the counterpart of
+// decryptBlocksFromFile for compressed data files: it reads, decrypts and
+// decompresses the blocks covering [startOffset, startOffset+len(dst))
+// into dst.
+func decryptCompressedBlocksFromFile(rc *refCountedFile, contentLength int64, encryptor *BlockEncryptor, dst []byte, startOffset int64, ptCache *ristretto.Cache[uint64, []byte], instanceHash InstanceHash, globalBlockNum0 uint32) (int, error) {
+	endOffset := min(startOffset+int64(len(dst)), contentLength)
+	if endOffset <= startOffset {
+		return 0, nil
+	}
+
+	index, err := rc.compressedIndex(contentLength)
+	if err != nil {
+		return 0, err
+	}
+
+	startBlock := ContentOffsetToBlock(startOffset)
+	endBlock := ContentOffsetToBlock(endOffset - 1)
+	offsetWithinFirstBlock := ContentOffsetWithinBlock(startOffset)
+
+	resultLen := int(endOffset - startOffset)
+	resultPos := 0
+
+	// Stored blocks are never larger than BlockTotalSize, so a batch of
+	// DefaultReadBatchBlocks always fits the pooled read buffer.
+	bp := readBufPool.Get().(*[]byte)
+	readBuf := *bp
+	defer readBufPool.Put(bp)
+
+	// Blocks only partly copied to dst are decoded here.
+	var partial []byte
+
+	for batchStart := startBlock; batchStart <= endBlock; batchStart += uint32(DefaultReadBatchBlocks) {
+		batchEnd := min(batchStart+uint32(DefaultReadBatchBlocks)-1, endBlock)
+
+		fileOffset := index.offset(batchStart)
+		readSize := int(index.offset(batchEnd+1) - fileOffset)
+		n, err := rc.File().ReadAt(readBuf[:readSize], fileOffset)
+		if err != nil && err != io.EOF {
+			return 0, errors.Wrapf(err, "failed to read blocks %d-%d", batchStart, batchEnd)
+		}
+		if n < readSize {
+			return 0, errors.Errorf("short read on blocks %d-%d: got %d bytes, expected %d", globalBlockNum0+batchStart, globalBlockNum0+batchEnd, n, readSize)
+		}
+
+		readPos := 0
+		for block := batchStart; block <= batchEnd; block++ {
+			stored := readBuf[readPos : readPos+int(index.lengths[block])]
+			readPos += len(stored)
+			globalBlock := globalBlockNum0 + block
+			blockDataSize := blockDataLen(contentLength, block)
+
+			dataStart := 0
+			if block == startBlock {
+				dataStart = offsetWithinFirstBlock
+			}
+			dataEnd := min(blockDataSize, dataStart+resultLen-resultPos)
+
+			var plain []byte
+			if ptCache != nil {
+				plain, _ = ptCache.Get(ptCacheKey(instanceHash, globalBlock))
+			}
+			if plain == nil {
+				var target []byte
+				if dataStart == 0 && dataEnd == blockDataSize {
+					// Full block: decode directly into dst.
+					target = dst[resultPos : resultPos : resultPos+blockDataSize]
+				} else {
+					if partial == nil {
+						partial = make([]byte, BlockDataSize)
+					}
+					target = partial[:0]
+				}
+				plain, err = openStoredBlock(encryptor, target, globalBlock, stored, blockDataSize)
+				if err != nil {
+					return 0, err
+				}
+				if ptCache != nil {
+					entry := make([]byte, len(plain))
+					copy(entry, plain)
+					ptCache.Set(ptCacheKey(instanceHash, globalBlock), entry, int64(BlockDataSize))
+				}
+			}
+
+			copy(dst[resultPos:], plain[dataStart:dataEnd])
+			resultPos += dataEnd - dataStart
+		}
+	}
+
+	return resultPos, nil
+}
+
+// identifyCorruptCompressedBlocks is the IdentifyCorruptBlocks probe for
+// compressed objects.  Blocks whose chunk file cannot be opened or whose
+// index is unreadable are reported as corrupt.
+func (sm *StorageManager) identifyCorruptCompressedBlocks(instanceHash InstanceHash, meta *CacheMetadata, encryptor *BlockEncryptor, blockState *ObjectBlockState, startBlock, endBlock uint32) []uint32 {
+	var openChunks chunkTracker
+	defer openChunks.releaseAll()
+
+	stored := make([]byte, BlockTotalSize)
+	plain := make([]byte, 0, BlockDataSize)
+	var corrupt []uint32
+
+	for block := startBlock; block <= endBlock; block++ {
+		if !blockState.Contains(block) {
+			continue
+		}
+
+		chunkIdx := ContentOffsetToChunk(int64(block)*BlockDataSize, meta.ChunkSizeCode)
+		chunkStart, chunkEnd := GetChunkRange(meta.ContentLength, meta.ChunkSizeCode, chunkIdx)
+		chunkContentLen := chunkEnd - chunkStart + 1
+		localBlock := block - ContentOffsetToBlock(chunkStart)
+
+		rc, ok := openChunks.get(chunkIdx)
+		if !ok {
+			var err error
+			rc, err = sm.getChunkFile(instanceHash, meta, chunkIdx)
+			if err != nil {
+				corrupt = append(corrupt, block)
+				continue
+			}
+			openChunks.set(chunkIdx, rc)
+		}
+		index, err := rc.compressedIndex(chunkContentLen)
+		if err != nil {
+			corrupt = append(corrupt, block)
+			continue
+		}
+
+		buf := stored[:index.lengths[localBlock]]
+		if n, _ := rc.File().ReadAt(buf, index.offset(localBlock)); n < len(buf) {
+			corrupt = append(corrupt, block)
+			continue
+		}
+		if _, err := openStoredBlock(encryptor, plain[:0], block, buf, blockDataLen(chunkContentLen, localBlock)); err != nil {
+			corrupt = append(corrupt, block)
+		}
+	}
+
+	return corrupt
+}
+
+// forEachSealedBlock reads the first maxBlocks sealed blocks of an
+// uncompressed data file holding contentLength bytes and calls fn for
+// each.  The sealed slice is only valid during the call.
+func forEachSealedBlock(file *os.File, contentLength int64, maxBlocks uint32, fn func(blockNum uint32, sealed []byte) error) error {
+	blocks := min(CalculateBlockCount(contentLength), maxBlocks)
+
+	bp := readBufPool.Get().(*[]byte)
+	readBuf := *bp
+	defer readBufPool.Put(bp)
+
+	for batchStart := uint32(0); batchStart < blocks; batchStart += uint32(DefaultReadBatchBlocks) {
+		batchEnd := min(batchStart+uint32(DefaultReadBatchBlocks), blocks) // exclusive
+		fileOffset := BlockOffset(batchStart)
+		readSize := int(BlockOffset(batchEnd-1) + int64(blockDataLen(contentLength, batchEnd-1)+AuthTagSize) - fileOffset)
+		if n, err := file.ReadAt(readBuf[:readSize], fileOffset); n < readSize {
+			if err == nil || err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return errors.Wrapf(err, "failed to read blocks %d-%d", batchStart, batchEnd-1)
+		}
+
+		readPos := 0
+		for block := batchStart; block < batchEnd; block++ {
+			size := blockDataLen(contentLength, block) + AuthTagSize
+			if err := fn(block, readBuf[readPos:readPos+size]); err != nil {
+				return err
+			}
+			readPos += size
+		}
+	}
+	return nil
+}
+
+// compressBlock returns the stored form of a sealed block: the block
+// compressed and sealed again if that makes it smaller, otherwise sealed
+// unchanged.  plainBuf and packedBuf are scratch space of BlockDataSize
+// and BlockTotalSize bytes; the result may alias packedBuf or sealed.
+func compressBlock(encryptor *BlockEncryptor, blockNum uint32, sealed, plainBuf, packedBuf []byte) ([]byte, error) {
+	plain, err := encryptor.DecryptBlockTo(plainBuf[:0], blockNum, sealed)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decrypt block %d", blockNum)
+	}
+	encoder, err := zstdEncoder()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create zstd encoder")
+	}
+	packed := encoder.EncodeAll(plain, packedBuf[:0])
+	if len(packed) >= len(plain) {
+		return sealed, nil
+	}
+	return encryptor.sealCompressedBlock(packed[:0], blockNum, packed), nil
+}
+
+// compressDataFile writes the compressed rewrite of the uncompressed data
+// file src, holding contentLength bytes, to dst.  globalBlockNum0 is the
+// global block number of the file's first block.  Returns the size of the
+// written file.
+func compressDataFile(src, dst string, contentLength int64, encryptor *BlockEncryptor, globalBlockNum0 uint32) (int64, error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to open data file")
+	}
+	defer in.Close()
+
+	out, err := createFile(dst)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to create compressed data file")
+	}
+	defer out.Close()
+
+	blocks := CalculateBlockCount(contentLength)
+	header := make([]byte, compressedHeaderSize(blocks))
+	copy(header, compressedMagic)
+	binary.LittleEndian.PutUint32(header[len(compressedMagic):], blocks)
+
+	w := bufio.NewWriterSize(io.NewOffsetWriter(out, int64(len(header))), int(writeBatchBlocks)*BlockTotalSize)
+	plainBuf := make([]byte, BlockDataSize)
+	packedBuf := make([]byte, BlockTotalSize)
+	size := int64(len(header))
+	err = forEachSealedBlock(in, contentLength, blocks, func(block uint32, sealed []byte) error {
+		stored, err := compressBlock(encryptor, globalBlockNum0+block, sealed, plainBuf, packedBuf)
+		if err != nil {
+			return err
+		}
+		binary.LittleEndian.PutUint16(header[compressedHeaderFixedSize+2*int(block):], uint16(len(stored)))
+		size += int64(len(stored))
+		_, err = w.Write(stored)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	if err := w.Flush(); err != nil {
+		return 0, errors.Wrap(err, "failed to write compressed blocks")
+	}
+	if _, err := out.WriteAt(header, 0); err != nil {
+		return 0, errors.Wrap(err, "failed to write compressed file header")
+	}
+	// The original is removed once the metadata points here.
+	if err := out.Sync(); err != nil {
+		return 0, errors.Wrap(err, "failed to sync compressed data file")
+	}
+	return size, nil
+}
+
+// sampleCompression reports whether compressing the object looks
+// worthwhile, judging by its first compressionSampleBlocks blocks.
+func (sm *StorageManager) sampleCompression(instanceHash InstanceHash, meta *CacheMetadata, encryptor *BlockEncryptor) (bool, error) {
+	file, err := os.Open(sm.getChunkPath(meta.StorageID, instanceHash, 0))
+	if err != nil {
+		return false, errors.Wrap(err, "failed to open object file")
+	}
+	defer file.Close()
+
+	plainBuf := make([]byte, BlockDataSize)
+	packedBuf := make([]byte, BlockTotalSize)
+	var original, stored int64
+	chunkContentLen := ChunkContentLength(meta.ContentLength, meta.ChunkSizeCode, 0)
+	err = forEachSealedBlock(file, chunkContentLen, compressionSampleBlocks, func(block uint32, sealed []byte) error {
+		out, err := compressBlock(encryptor, block, sealed, plainBuf, packedBuf)
+		if err != nil {
+			return err
+		}
+		original += int64(len(sealed))
+		stored += int64(len(out))
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return stored*100 <= original*(100-compressionMinSavingsPercent), nil
+}
+
+// CompressObject rewrites the data files of a completed disk object with
+// compressed blocks, if that saves at least compressionMinSavingsPercent
+// of its on-disk size.  Inline, incomplete, deduplicated and already
+// compressed objects are left untouched.  Returns true if the object was
+// compressed.
+func (sm *StorageManager) CompressObject(instanceHash InstanceHash) (bool, error) {
+	sm.rewriteMu.Lock()
+	defer sm.rewriteMu.Unlock()
+
+	meta, err := sm.db.GetMetadata(instanceHash)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get metadata")
+	}
+	if meta == nil || !meta.IsDisk() || meta.Completed.IsZero() || meta.ContentLength <= 0 || meta.ContentHash != "" || meta.IsCompressed() {
+		return false, nil
+	}
+	chunkCount := meta.ChunkCount()
+	for chunkIdx := 0; chunkIdx < chunkCount; chunkIdx++ {
+		if !meta.IsChunkAllocated(chunkIdx) {
+			return false, nil
+		}
+	}
+
+	dc, err := sm.getDiskCrypto(instanceHash)
+	if err != nil {
+		return false, err
+	}
+	encryptor := dc.encryptor
+
+	if worthwhile, err := sm.sampleCompression(instanceHash, meta, encryptor); err != nil || !worthwhile {
+		return false, err
+	}
+
+	var written []string
+	removeWritten := func() {
+		for _, path := range written {
+			if err := removeFileWithRetry(path); err != nil {
+				log.Warnf("Failed to remove compressed data file %s: %v", path, err)
+			}
+		}
+	}
+
+	storedSizes := make([]int64, chunkCount)
+	for chunkIdx := 0; chunkIdx < chunkCount; chunkIdx++ {
+		storageID := meta.GetChunkStorageID(chunkIdx)
+		chunkStart, chunkEnd := GetChunkRange(meta.ContentLength, meta.ChunkSizeCode, chunkIdx)
+		dst := sm.getCompressedChunkPath(storageID, instanceHash, chunkIdx)
+		written = append(written, dst)
+		size, err := compressDataFile(sm.getChunkPath(storageID, instanceHash, chunkIdx), dst,
+			chunkEnd-chunkStart+1, encryptor, ContentOffsetToBlock(chunkStart))
+		if err != nil {
+			removeWritten()
+			return false, errors.Wrapf(err, "failed to compress chunk %d", chunkIdx)
+		}
+		storedSizes[chunkIdx] = size
+	}
+
+	compressed := *meta
+	compressed.Compression = CompressionZstd
+	compressed.StoredSizes = storedSizes
+	if compressed.StoredSize()*100 > meta.StoredSize()*(100-compressionMinSavingsPercent) {
+		removeWritten()
+		return false, nil
+	}
+
+	// Readers wait until the originals are gone and the cached metadata
+	// names the compressed files.
+	lock := sm.lockDataFiles(instanceHash)
+	lock.Lock()
+	defer lock.Unlock()
+
+	if err := sm.db.SetCompression(instanceHash, meta, CompressionZstd, storedSizes); err != nil {
+		removeWritten()
+		if errors.Is(err, errContentChanged) {
+			return false, nil
+		}
+		return false, errors.Wrap(err, "failed to record compression")
+	}
+
+	// Readers now use the compressed files; drop the originals and the
+	// usage charged for the space saved.
+	sm.invalidateObjectCaches(instanceHash, chunkCount)
+	sm.deleteChunkFiles(instanceHash, meta)
+	after := compressed.PerDirectoryBytes()
+	for sid, bytes := range meta.PerDirectoryBytes() {
+		if err := sm.db.AddUsage(sid, meta.NamespaceID, after[sid]-bytes); err != nil {
+			log.Warnf("Failed to decrease usage for storage %d namespace %d: %v", sid, meta.NamespaceID, err)
+		}
+	}
+	log.Debugf("Compressed %s from %d to %d bytes", instanceHash, meta.StoredSize(), compressed.StoredSize())
+	return true, nil
+}
+
+// SetCompression records that the data files of instanceHash have been
+// rewritten with the given codec, and the on-disk size of each chunk
+// file.  Returns errContentChanged if the metadata no longer matches
+// expected.
+func (cdb *CacheDB) SetCompression(instanceHash InstanceHash, expected *CacheMetadata, codec CompressionCodec, storedSizes []int64) error {
+	return cdb.db.Update(func(txn *badger.Txn) error {
+		meta, err := getMetadataInTxn(txn, instanceHash)
+		if err != nil {
+			return err
+		}
+		if !sameData(meta, expected) {
+			return errContentChanged
+		}
+		meta.Compression = codec
+		meta.StoredSizes = storedSizes
+		return setMetadataInTxn(txn, instanceHash, meta)
+	})
+}
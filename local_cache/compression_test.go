@@ -0,0 +1,292 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package local_cache
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+)
+
+// compressibleTestData returns size bytes of CSV-like text.
+func compressibleTestData(size int) []byte {
+	var sb strings.Builder
+	for i := 0; sb.Len() < size; i++ {
+		fmt.Fprintf(&sb, "%d,sensor-%d,%.3f,OK\n", i, i%17, float64(i%1000)/7)
+	}
+	return []byte(sb.String()[:size])
+}
+
+func TestCompressObject(t *testing.T) {
+	db, storage, sid := setupDedupStorage(t)
+
+	data := compressibleTestData(10*BlockDataSize + 123)
+	fileSize := CalculateFileSize(int64(len(data)))
+	h := createDedupObject(t, db, storage, 1, sid, 1, data, false)
+
+	ok, err := storage.CompressObject(h)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	meta, err := storage.GetMetadata(h)
+	require.NoError(t, err)
+	require.True(t, meta.IsCompressed())
+	stored := meta.StoredSize()
+	assert.Less(t, stored, fileSize)
+
+	// The compressed file replaces the original.
+	_, err = os.Stat(storage.getObjectPathForDir(sid, h))
+	assert.True(t, os.IsNotExist(err), "original data file should be removed")
+	info, err := os.Stat(storage.getDataFilePath(meta, h, 0))
+	require.NoError(t, err)
+	assert.Equal(t, stored, info.Size())
+
+	// Usage is reduced to the compressed size.
+	usage, err := db.GetUsage(sid, 1)
+	require.NoError(t, err)
+	assert.Equal(t, stored, usage)
+
+	got, err := storage.ReadBlocks(h, 0, len(data))
+	require.NoError(t, err)
+	assert.Equal(t, data, got)
+
+	t.Run("RangeReads", func(t *testing.T) {
+		for _, r := range []struct{ off, length int }{
+			{0, 10},
+			{BlockDataSize - 5, 10},
+			{3*BlockDataSize + 17, 2 * BlockDataSize},
+			{len(data) - 50, 50},
+		} {
+			got, err := storage.ReadBlocks(h, int64(r.off), r.length)
+			require.NoError(t, err)
+			assert.Equal(t, data[r.off:r.off+r.length], got, "range %d+%d", r.off, r.length)
+		}
+	})
+
+	t.Run("ObjectReader", func(t *testing.T) {
+		reader, err := storage.NewObjectReader(h)
+		require.NoError(t, err)
+		defer reader.Close()
+		got, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		assert.Equal(t, data, got)
+	})
+
+	t.Run("Idempotent", func(t *testing.T) {
+		ok, err := storage.CompressObject(h)
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("WritesRefused", func(t *testing.T) {
+		err := storage.WriteBlocks(h, 0, data[:BlockDataSize])
+		assert.ErrorIs(t, err, errCompressedObject)
+	})
+
+	t.Run("CorruptBlock", func(t *testing.T) {
+		corrupt, err := storage.IdentifyCorruptBlocks(h, 0, CalculateBlockCount(meta.ContentLength)-1)
+		require.NoError(t, err)
+		assert.Empty(t, corrupt)
+
+		// Flip a byte in the last stored block.
+		path := storage.getDataFilePath(meta, h, 0)
+		f, err := os.OpenFile(path, os.O_RDWR, 0)
+		require.NoError(t, err)
+		buf := make([]byte, 1)
+		_, err = f.ReadAt(buf, stored-1)
+		require.NoError(t, err)
+		buf[0] ^= 0xff
+		_, err = f.WriteAt(buf, stored-1)
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+
+		corrupt, err = storage.IdentifyCorruptBlocks(h, 0, CalculateBlockCount(meta.ContentLength)-1)
+		require.NoError(t, err)
+		assert.Equal(t, []uint32{CalculateBlockCount(meta.ContentLength) - 1}, corrupt)
+	})
+
+	t.Run("Eviction", func(t *testing.T) {
+		evicted, freed, err := storage.EvictByLRU(sid, 1, 0, 0)
+		require.NoError(t, err)
+		require.Len(t, evicted, 1)
+		assert.Equal(t, uint64(stored), freed)
+		_, err = os.Stat(storage.getDataFilePath(meta, h, 0))
+		assert.True(t, os.IsNotExist(err), "compressed data file should be removed")
+		usage, err := db.GetUsage(sid, 1)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), usage)
+	})
+}
+
+func TestCompressObjectIncompressible(t *testing.T) {
+	db, storage, sid := setupDedupStorage(t)
+
+	data := make([]byte, 4*BlockDataSize)
+	_, err := rand.Read(data)
+	require.NoError(t, err)
+	h := createDedupObject(t, db, storage, 1, sid, 1, data, false)
+
+	ok, err := storage.CompressObject(h)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	meta, err := storage.GetMetadata(h)
+	require.NoError(t, err)
+	assert.False(t, meta.IsCompressed())
+	_, err = os.Stat(storage.getObjectPathForDir(sid, h))
+	assert.NoError(t, err, "original data file should be untouched")
+	_, err = os.Stat(storage.getCompressedChunkPath(sid, h, 0))
+	assert.True(t, os.IsNotExist(err), "no compressed file should be left behind")
+}
+
+func TestCompressChunkedObject(t *testing.T) {
+	InitIssuerKeyForTests(t)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	db, err := NewCacheDB(ctx, t.TempDir())
+	require.NoError(t, err)
+	defer db.Close()
+
+	egrp, _ := errgroup.WithContext(ctx)
+	storage, err := NewStorageManager(db, []string{t.TempDir(), t.TempDir()}, 0, egrp)
+	require.NoError(t, err)
+	defer storage.Close()
+
+	chunkSizeBytes := int64(2 * 1024 * 1024)
+	chunkSizeCode := BytesToChunkSizeCode(uint64(chunkSizeBytes))
+	actualChunkSize := int64(ChunkSizeCodeToBytes(chunkSizeCode))
+	data := compressibleTestData(int(actualChunkSize*2 + 300*1024))
+
+	instanceHash := InstanceHash(fmt.Sprintf("%064x", 0xc0ffee))
+	meta, err := storage.InitLazyChunkedStorage(ctx, instanceHash, int64(len(data)), chunkSizeCode)
+	require.NoError(t, err)
+	chunkCount := CalculateChunkCount(int64(len(data)), chunkSizeCode)
+	for i := 0; i < chunkCount; i++ {
+		meta, err = storage.AllocateChunk(ctx, instanceHash, meta, i)
+		require.NoError(t, err)
+	}
+	require.NoError(t, storage.WriteBlocks(instanceHash, 0, data))
+	meta.Completed = time.Now()
+	require.NoError(t, storage.SetMetadata(instanceHash, meta))
+	reader, err := storage.NewObjectReader(instanceHash)
+	require.NoError(t, err)
+	defer reader.Close()
+
+	ok, err := storage.CompressObject(instanceHash)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	meta, err = storage.GetMetadata(instanceHash)
+	require.NoError(t, err)
+	require.Len(t, meta.StoredSizes, chunkCount)
+	for chunkIdx := 0; chunkIdx < chunkCount; chunkIdx++ {
+		info, err := os.Stat(storage.getDataFilePath(meta, instanceHash, chunkIdx))
+		require.NoError(t, err, "compressed chunk %d", chunkIdx)
+		assert.Equal(t, meta.StoredSizes[chunkIdx], info.Size())
+		_, err = os.Stat(storage.getChunkPath(meta.GetChunkStorageID(chunkIdx), instanceHash, chunkIdx))
+		assert.True(t, os.IsNotExist(err), "original chunk %d should be removed", chunkIdx)
+	}
+
+	got, err := storage.ReadBlocks(instanceHash, 0, len(data))
+	require.NoError(t, err)
+	assert.Equal(t, data, got)
+
+	boundary := actualChunkSize - 1000
+	got, err = storage.ReadBlocks(instanceHash, boundary, 2000)
+	require.NoError(t, err)
+	assert.Equal(t, data[boundary:boundary+2000], got)
+
+	// The reader opened before compression still reads the object now
+	// that the original chunk files are gone.
+	got, err = io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, data, got)
+}
+
+// TestConsistencyCheckerCompression verifies that the data scan compresses
+// verified objects ahead of deduplicating them, and that the metadata scan
+// keeps the compressed files and charges their compressed size.
+func TestConsistencyCheckerCompression(t *testing.T) {
+	db, storage, sid := setupDedupStorage(t)
+
+	data := compressibleTestData(20*BlockDataSize + 9)
+	h1 := createDedupObject(t, db, storage, 1, sid, 1, data, true)
+	h2 := createDedupObject(t, db, storage, 2, sid, 1, data, true)
+	random := make([]byte, 3*BlockDataSize)
+	_, err := rand.Read(random)
+	require.NoError(t, err)
+	plain := createDedupObject(t, db, storage, 3, sid, 1, random, true)
+
+	cc := NewConsistencyChecker(db, storage, ConsistencyConfig{
+		MinAgeForCleanup: 0,
+		Deduplicate:      true,
+		Compress:         true,
+	})
+	ctx := context.Background()
+	require.NoError(t, cc.RunDataScan(ctx, nil))
+
+	meta1, err := storage.GetMetadata(h1)
+	require.NoError(t, err)
+	meta2, err := storage.GetMetadata(h2)
+	require.NoError(t, err)
+	plainMeta, err := storage.GetMetadata(plain)
+	require.NoError(t, err)
+	require.True(t, meta1.IsCompressed())
+	assert.False(t, plainMeta.IsCompressed())
+	require.NotEmpty(t, meta1.ContentHash)
+	assert.Equal(t, meta1.ContentHash, meta2.ContentHash)
+	assert.True(t, meta2.IsCompressed())
+
+	require.NoError(t, cc.RunMetadataScan(ctx, nil))
+	stats := cc.GetStats()
+	assert.Equal(t, int64(0), stats.OrphanedFiles)
+	assert.Equal(t, int64(0), stats.OrphanedDBEntries)
+
+	_, err = os.Stat(storage.getDataFilePath(meta1, meta1.DataHash(h1), 0))
+	require.NoError(t, err, "metadata scan must keep compressed shared content")
+
+	usage, err := db.GetUsage(sid, 1)
+	require.NoError(t, err)
+	assert.Equal(t, meta1.StoredSize()+plainMeta.StoredSize(), usage)
+
+	for _, h := range []InstanceHash{h1, h2, plain} {
+		valid, err := cc.VerifyObject(h)
+		require.NoError(t, err)
+		assert.True(t, valid)
+	}
+
+	api := &IntrospectAPIOpen{db: db, storage: storage}
+	cacheStats, err := api.GetCacheStats()
+	require.NoError(t, err)
+	require.NotNil(t, cacheStats.Compression)
+	assert.Equal(t, int64(2), cacheStats.Compression.Objects)
+	assert.Equal(t, 2*int64(len(data)), cacheStats.Compression.ContentBytes)
+	assert.Greater(t, cacheStats.Compression.Ratio, 1.0)
+}
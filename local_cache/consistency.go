@@ -27,6 +27,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"hash"
+	"hash/adler32"
 	"hash/crc32"
 	"io/fs"
 	"math/rand"
@@ -117,6 +118,8 @@ type ConsistencyChecker struct {
 	dataScanBytesPerSec int64          // Max bytes per second for data scanning
 	minAgeForCleanup    time.Duration  // Minimum age before cleanup to avoid races
 	checksumTypes       []ChecksumType // Checksum algorithms to calculate/verify
+	deduplicate         bool           // Share data of verified objects with identical content
+	compress            bool           // Store verified objects compressed
 
 	// Statistics
 	stats   ConsistencyStats
@@ -142,6 +145,13 @@ type ConsistencyConfig struct {
 	// ChecksumTypes specifies which checksums to calculate and verify.
 	// When empty, defaults to []ChecksumType{ChecksumSHA256}.
 	ChecksumTypes []ChecksumType
+	// Deduplicate enables content-addressed deduplication of objects
+	// whose SHA-256 checksum is known after a data scan verifies them.
+	// Objects lacking a SHA-256 checksum have one computed during the scan.
+	Deduplicate bool
+	// Compress enables rewriting verified objects with compressed blocks
+	// when that saves enough space.
+	Compress bool
 }
 
 // ConsistencyStats holds statistics from consistency checks
@@ -192,6 +202,8 @@ func NewConsistencyChecker(db *CacheDB, storage *StorageManager, config Consiste
 		dataScanBytesPerSec: config.DataScanBytesPerSec,
 		minAgeForCleanup:    config.MinAgeForCleanup,
 		checksumTypes:       checksumTypes,
+		deduplicate:         config.Deduplicate,
+		compress:            config.Compress,
 		stopCh:              make(chan struct{}),
 	}
 }
@@ -324,6 +336,7 @@ func (cc *ConsistencyChecker) RunMetadataScan(ctx context.Context, progressCh ch
 		size         int64
 		chunkIndex   int       // 0 for base file, 1+ for chunk suffix files (-2, -3, etc.)
 		storageID    StorageID // Which storage directory this file is in
+		compressed   bool      // Compressed data file (".z" suffix)
 	}
 	fileChan := make(chan fileInfo, 100)
 	walkErr := make(chan error, 1)
@@ -363,12 +376,14 @@ func (cc *ConsistencyChecker) RunMetadataScan(ctx context.Context, progressCh ch
 				hash = strings.ReplaceAll(hash, "\\", "")
 
 				// Parse the filename to extract base hash and any chunk index
-				// Files can be: <64-hex-hash> (chunk 0) or <64-hex-hash>-N (chunk N-1)
+				// Files can be: <64-hex-hash> (chunk 0) or <64-hex-hash>-N (chunk N-1),
+				// with compressed data files carrying a ".z" before any chunk suffix
 				baseHash, chunkIndex, ok := ParseChunkFilename(hash)
 				if !ok {
 					return nil
 				}
-				instanceHash := baseHash
+				trimmed, compressed := strings.CutSuffix(string(baseHash), compressedFileSuffix)
+				instanceHash := InstanceHash(trimmed)
 
 				// Validate instance hash format: must be 64 hex characters (SHA256)
 				if len(instanceHash) != 64 {
@@ -403,6 +418,7 @@ func (cc *ConsistencyChecker) RunMetadataScan(ctx context.Context, progressCh ch
 					size:         info.Size(),
 					chunkIndex:   chunkIndex,
 					storageID:    storageID,
+					compressed:   compressed,
 				}:
 				case <-ctx.Done():
 					return ctx.Err()
@@ -523,10 +539,15 @@ func (cc *ConsistencyChecker) RunMetadataScan(ctx context.Context, progressCh ch
 			// their actual on-disk size: CalculateFileSize(ContentLength)
 			// for disk objects (which accounts for the 16-byte MAC per
 			// 4080-byte block), or ContentLength for inline objects.
-			if meta.ContentLength > 0 {
+			// Compressed objects are charged the size of their files.
+			// Deduplicated objects are charged once per content record
+			// after the scan.
+			if meta.ContentLength > 0 && meta.ContentHash == "" {
 				uk := StorageUsageKey{StorageID: meta.StorageID, NamespaceID: meta.NamespaceID}
 				if meta.StorageID == StorageIDInline {
 					usageDuringScan[uk] += meta.ContentLength
+				} else if meta.IsCompressed() {
+					usageDuringScan[uk] += meta.StoredSize()
 				} else {
 					usageDuringScan[uk] += CalculateFileSize(meta.ContentLength)
 				}
@@ -541,9 +562,11 @@ func (cc *ConsistencyChecker) RunMetadataScan(ctx context.Context, progressCh ch
 			dbEntriesScanned++
 			entriesThisTransaction++
 
-			// Process all files that are less than current DB entry (orphaned files)
+			// Process all files that are less than current DB entry (orphaned
+			// files, unless they hold shared content, which has no metadata
+			// entry of its own)
 			for fileOk && currentFile.instanceHash < instanceHash {
-				if len(deletions) < maxDeletionsPerTx {
+				if len(deletions) < maxDeletionsPerTx && !cc.isSharedContent(currentFile.instanceHash) {
 					deletions = append(deletions, deleteAction{
 						instanceHash: currentFile.instanceHash,
 						isFile:       true,
@@ -561,7 +584,19 @@ func (cc *ConsistencyChecker) RunMetadataScan(ctx context.Context, progressCh ch
 			for fileOk && currentFile.instanceHash == instanceHash {
 				filesScanned++
 
-				if meta.IsDisk() {
+				if meta.IsDisk() && currentFile.compressed != meta.IsCompressed() {
+					// Data file in the format the object does not use,
+					// e.g. left behind by an interrupted compression
+					if len(deletions) < maxDeletionsPerTx {
+						deletions = append(deletions, deleteAction{
+							instanceHash: currentFile.instanceHash,
+							isFile:       true,
+							path:         currentFile.path,
+							size:         currentFile.size,
+							chunkIndex:   currentFile.chunkIndex,
+						})
+					}
+				} else if meta.IsDisk() {
 					// DB entry expects disk storage - verify chunk validity
 					if meta.IsChunked() {
 						// For chunked objects, verify chunk index is within range
@@ -823,7 +858,7 @@ func (cc *ConsistencyChecker) RunMetadataScan(ctx context.Context, progressCh ch
 	// Process any remaining files (all are orphaned).
 	// Like deletions above, skip if a walk error occurred.
 	for fileOk {
-		if !hadWalkError.Load() {
+		if !hadWalkError.Load() && !cc.isSharedContent(currentFile.instanceHash) {
 			// Re-check file (might have been created after scan start)
 			if info, err := os.Stat(currentFile.path); err == nil {
 				if !info.ModTime().After(scanStartTime) {
@@ -860,6 +895,19 @@ func (cc *ConsistencyChecker) RunMetadataScan(ctx context.Context, progressCh ch
 	log.Infof("Metadata scan complete in %v: scanned %d DB entries and %d files, found %d orphaned DB entries and %d orphaned files (%s)",
 		scanDuration, dbEntriesScanned, filesScanned, orphanedDBEntries, orphanedFiles, utils.HumanBytes(orphanedBytes))
 
+	// Charge each shared copy of deduplicated content once, to the
+	// namespace its record names.
+	err := cc.db.ScanContent(func(_ ContentHash, rec *ContentRecord) error {
+		for sid, bytes := range rec.Layout().PerDirectoryBytes() {
+			usageDuringScan[StorageUsageKey{StorageID: sid, NamespaceID: rec.NamespaceID}] += bytes
+		}
+		return nil
+	})
+	if err != nil {
+		sl.WithError(err).Warn("Failed to scan content records; skipping usage reconciliation")
+		return nil
+	}
+
 	// Reconcile the stored usage counters against the running totals
 	// accumulated during the metadata scan above.  This avoids a second
 	// full-table scan of the metadata and block-state tables.
@@ -1289,6 +1337,8 @@ func (cc *ConsistencyChecker) verifyObjectChecksum(
 			return err
 		}
 		*objectsVerified++
+		cc.compressObject(instanceHash, meta)
+		cc.deduplicateObject(instanceHash, meta)
 		return nil
 	}
 
@@ -1302,6 +1352,14 @@ func (cc *ConsistencyChecker) verifyObjectChecksum(
 		hashers[i] = h
 	}
 
+	// Deduplication identifies content by SHA-256; compute it in the
+	// same pass if the object does not have one yet.
+	var contentHasher hash.Hash
+	if cc.deduplicate && meta.IsDisk() && meta.ContentHash == "" && !hasChecksumType(meta.Checksums, ChecksumSHA256) {
+		contentHasher = sha256.New()
+		hashers = append(hashers, contentHasher)
+	}
+
 	// Read all data through storage manager and hash in one pass
 	verified, err := cc.hashObjectData(ctx, instanceHash, meta, bytesLimiter, hashers)
 	if err != nil {
@@ -1324,9 +1382,53 @@ func (cc *ConsistencyChecker) verifyObjectChecksum(
 
 	*bytesVerified += verified
 	*objectsVerified++
+
+	if contentHasher != nil {
+		checksumMeta := &CacheMetadata{Checksums: []Checksum{{
+			Type:  ChecksumSHA256,
+			Value: contentHasher.Sum(nil),
+		}}}
+		if err := cc.db.MergeMetadata(instanceHash, checksumMeta); err != nil {
+			return errors.Wrap(err, "failed to store checksum")
+		}
+	}
+	cc.compressObject(instanceHash, meta)
+	cc.deduplicateObject(instanceHash, meta)
 	return nil
 }
 
+// hasChecksumType reports whether checksums includes one of type ct.
+func hasChecksumType(checksums []Checksum, ct ChecksumType) bool {
+	for _, cksum := range checksums {
+		if cksum.Type == ct {
+			return true
+		}
+	}
+	return false
+}
+
+// compressObject rewrites a verified disk object's data in compressed
+// form, when compression is enabled.
+func (cc *ConsistencyChecker) compressObject(instanceHash InstanceHash, meta *CacheMetadata) {
+	if !cc.compress || !meta.IsDisk() || meta.ContentHash != "" || meta.IsCompressed() {
+		return
+	}
+	if _, err := cc.storage.CompressObject(instanceHash); err != nil {
+		log.Warnf("Failed to compress object %s: %v", instanceHash, err)
+	}
+}
+
+// deduplicateObject shares a verified disk object's data with other
+// instances holding identical content, when deduplication is enabled.
+func (cc *ConsistencyChecker) deduplicateObject(instanceHash InstanceHash, meta *CacheMetadata) {
+	if !cc.deduplicate || !meta.IsDisk() || meta.ContentHash != "" {
+		return
+	}
+	if _, err := cc.storage.DeduplicateObject(instanceHash); err != nil {
+		log.Warnf("Failed to deduplicate object %s: %v", instanceHash, err)
+	}
+}
+
 // hashObjectData reads all object data through the storage manager and
 // writes it to every hasher.  Returns the number of bytes hashed.
 // This is shared between verifyObjectChecksum and calculateAndStoreChecksums
@@ -1454,6 +1556,8 @@ func (cc *ConsistencyChecker) createHasher(checksumType ChecksumType) (hash.Hash
 		return crc32.NewIEEE(), nil
 	case ChecksumCRC32C:
 		return crc32.New(crc32.MakeTable(crc32.Castagnoli)), nil
+	case ChecksumAdler32:
+		return adler32.New(), nil
 	default:
 		return nil, errors.Errorf("unknown checksum type: %d", checksumType)
 	}
@@ -1481,8 +1585,7 @@ func (cc *ConsistencyChecker) VerifyObject(instanceHash InstanceHash) (bool, err
 			if !meta.IsChunkAllocated(chunkIdx) {
 				continue
 			}
-			storageID := meta.GetChunkStorageID(chunkIdx)
-			chunkPath := cc.storage.getChunkPath(storageID, instanceHash, chunkIdx)
+			chunkPath := cc.storage.getDataFilePath(meta, meta.DataHash(instanceHash), chunkIdx)
 			if _, err := os.Stat(chunkPath); os.IsNotExist(err) {
 				return false, nil
 			}
@@ -1542,6 +1645,8 @@ func ParseChecksumHeader(headerValue string, headerType string) *Checksum {
 		checksumType = ChecksumSHA256
 	case "x-checksum-crc32", "crc32":
 		checksumType = ChecksumCRC32
+	case "x-checksum-adler32", "adler32":
+		checksumType = ChecksumAdler32
 	default:
 		return nil
 	}
@@ -1591,8 +1696,7 @@ func (cc *ConsistencyChecker) allChunkFilesExist(meta *CacheMetadata, instanceHa
 		if !meta.IsChunkAllocated(chunkIdx) {
 			continue
 		}
-		storageID := meta.GetChunkStorageID(chunkIdx)
-		chunkPath := cc.storage.getChunkPath(storageID, instanceHash, chunkIdx)
+		chunkPath := cc.storage.getDataFilePath(meta, meta.DataHash(instanceHash), chunkIdx)
 		if _, err := os.Stat(chunkPath); os.IsNotExist(err) {
 			return false
 		}
@@ -1600,6 +1704,18 @@ func (cc *ConsistencyChecker) allChunkFilesExist(meta *CacheMetadata, instanceHa
 	return true
 }
 
+// isSharedContent reports whether files named by hash hold the shared
+// copy of deduplicated content rather than a single instance's data.
+func (cc *ConsistencyChecker) isSharedContent(hash InstanceHash) bool {
+	rec, err := cc.db.GetContentRecord(ContentHash(hash))
+	if err != nil {
+		// Err on the side of keeping the file.
+		log.Warnf("Failed to look up content record %s: %v", hash, err)
+		return true
+	}
+	return rec != nil
+}
+
 // removeOrphanedChunkFiles removes chunk files (chunks 1+) associated with a base file.
 // This is called when an orphaned base file (chunk 0) is being deleted.
 // Because chunks may be lazily allocated (non-sequential), we list the parent
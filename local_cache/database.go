@@ -38,6 +38,7 @@ import (
 	"crypto/rand"
 	"encoding/binary"
 	"fmt"
+	"math"
 	"math/big"
 	"os"
 	"path/filepath"
@@ -346,6 +347,7 @@ func (cdb *CacheDB) SetMetadata(instanceHash InstanceHash, meta *CacheMetadata)
 //     Completed): keep the later of existing vs incoming.
 //   - Additive (Checksums): union by ChecksumType; if both sides provide the
 //     same Type, prefer the OriginVerified entry.
+//   - Max-count (AccessCount): keep the larger of existing vs incoming.
 //   - Last-writer-wins (ContentType, ContentLength, VaryHeaders, CCFlags,
 //     CCMaxAge): incoming replaces existing when the incoming value is non-zero /
 //     non-empty.
@@ -418,6 +420,11 @@ func mergeMetadataFields(existing, incoming *CacheMetadata) error {
 		existing.Completed = incoming.Completed
 	}
 
+	// --- Max-count ---
+	if incoming.AccessCount > existing.AccessCount {
+		existing.AccessCount = incoming.AccessCount
+	}
+
 	// --- Additive: Checksums ---
 	existing.Checksums = mergeChecksums(existing.Checksums, incoming.Checksums)
 
@@ -1104,7 +1111,18 @@ func (cdb *CacheDB) SetInlineData(instanceHash InstanceHash, encryptedData []byt
 // Uses debouncing: only updates if last access was more than debounceTime ago
 // This is optimized to avoid iteration by storing the last access time in metadata
 func (cdb *CacheDB) UpdateLRU(instanceHash InstanceHash, debounceTime time.Duration) error {
-	return cdb.db.Update(func(txn *badger.Txn) error {
+	_, err := cdb.RecordAccess(instanceHash, 1, debounceTime)
+	return err
+}
+
+// RecordAccess adds hits to an object's access count and moves it to the
+// front of the LRU index.  Like UpdateLRU, the write is debounced: if the
+// last recorded access is more recent than debounceTime, nothing is
+// written and false is returned so the caller can carry the hits forward
+// to a later call.
+func (cdb *CacheDB) RecordAccess(instanceHash InstanceHash, hits uint32, debounceTime time.Duration) (bool, error) {
+	written := false
+	err := cdb.db.Update(func(txn *badger.Txn) error {
 		// Get metadata to find prefixID and last access time
 		item, err := txn.Get(MetaKey(instanceHash))
 		if err != nil {
@@ -1143,14 +1161,112 @@ func (cdb *CacheDB) UpdateLRU(instanceHash InstanceHash, debounceTime time.Durat
 			return errors.Wrap(err, "failed to set new LRU key")
 		}
 
-		// Update metadata with new access time
+		// Update metadata with new access time and count, saturating
+		// rather than wrapping the counter.
 		meta.LastAccessTime = now
+		if meta.AccessCount > math.MaxUint32-hits {
+			meta.AccessCount = math.MaxUint32
+		} else {
+			meta.AccessCount += hits
+		}
 		metaData, err := msgpack.Marshal(&meta)
 		if err != nil {
 			return errors.Wrap(err, "failed to marshal updated metadata")
 		}
-		return txn.Set(MetaKey(instanceHash), metaData)
+		if err := txn.Set(MetaKey(instanceHash), metaData); err != nil {
+			return err
+		}
+		written = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return written, nil
+}
+
+// EvictionCandidates returns up to limit objects whose base lives in the
+// given storage directory and namespace, least recently used first.
+// Eviction policies other than LRU rank this window of candidates.
+func (cdb *CacheDB) EvictionCandidates(storageID StorageID, namespaceID NamespaceID, limit int) ([]EvictionCandidate, error) {
+	var candidates []EvictionCandidate
+	err := cdb.db.View(func(txn *badger.Txn) error {
+		lruPrefix := []byte(fmt.Sprintf("%s%d:%d:", PrefixLRU, storageID, namespaceID))
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		for it.Seek(lruPrefix); it.ValidForPrefix(lruPrefix); it.Next() {
+			if limit > 0 && len(candidates) >= limit {
+				break
+			}
+			_, _, _, hash, err := ParseLRUKey(it.Item().Key())
+			if err != nil {
+				continue
+			}
+			meta, err := getMetadataInTxn(txn, hash)
+			if err != nil || meta == nil {
+				continue
+			}
+			candidates = append(candidates, newEvictionCandidate(hash, meta))
+		}
+		return nil
 	})
+	return candidates, err
+}
+
+// AddPolicyStats adds delta to the persisted counters of an eviction
+// policy.
+func (cdb *CacheDB) AddPolicyStats(policy string, delta EvictionPolicyStats) error {
+	return cdb.db.Update(func(txn *badger.Txn) error {
+		var stats EvictionPolicyStats
+		item, err := txn.Get(PolicyStatsKey(policy))
+		if err == nil {
+			if err := item.Value(func(val []byte) error {
+				return msgpack.Unmarshal(val, &stats)
+			}); err != nil {
+				return errors.Wrap(err, "failed to unmarshal policy stats")
+			}
+		} else if !errors.Is(err, badger.ErrKeyNotFound) {
+			return errors.Wrap(err, "failed to read policy stats")
+		}
+
+		stats.Hits += delta.Hits
+		stats.Misses += delta.Misses
+		stats.EvictedObjects += delta.EvictedObjects
+		stats.EvictedBytes += delta.EvictedBytes
+
+		data, err := msgpack.Marshal(&stats)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal policy stats")
+		}
+		return txn.Set(PolicyStatsKey(policy), data)
+	})
+}
+
+// GetPolicyStats returns the persisted counters of every eviction policy
+// that has recorded activity, keyed by policy name.
+func (cdb *CacheDB) GetPolicyStats() (map[string]EvictionPolicyStats, error) {
+	result := make(map[string]EvictionPolicyStats)
+	err := cdb.db.View(func(txn *badger.Txn) error {
+		prefix := []byte(PrefixPolicyStats)
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+
+		for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+			var stats EvictionPolicyStats
+			if err := it.Item().Value(func(val []byte) error {
+				return msgpack.Unmarshal(val, &stats)
+			}); err != nil {
+				return errors.Wrap(err, "failed to unmarshal policy stats")
+			}
+			result[string(it.Item().Key()[len(prefix):])] = stats
+		}
+		return nil
+	})
+	return result, err
 }
 
 // --- Usage Counter Operations ---
@@ -1371,13 +1487,24 @@ func (cdb *CacheDB) ComputeActualUsage() (map[StorageUsageKey]int64, error) {
 
 			// Both completed and in-progress objects are charged at their
 			// full ContentLength, matching the upfront-charge model where
-			// usage is reserved at file-creation time.
-			if meta.ContentLength > 0 {
+			// usage is reserved at file-creation time.  Deduplicated
+			// objects are charged once per content record below.
+			if meta.ContentLength > 0 && meta.ContentHash == "" {
 				actual[key] += meta.ContentLength
 			}
 		}
 		return nil
 	})
+	if err != nil {
+		return actual, err
+	}
+
+	err = cdb.ScanContent(func(_ ContentHash, rec *ContentRecord) error {
+		if rec.ContentLength > 0 {
+			actual[StorageUsageKey{StorageID: rec.StorageID, NamespaceID: rec.NamespaceID}] += rec.ContentLength
+		}
+		return nil
+	})
 
 	return actual, err
 }
@@ -1470,38 +1597,120 @@ func deleteObjectInTxn(txn *badger.Txn, salt []byte, instanceHash InstanceHash)
 // counters.  Usage is decremented via AddUsage after the transaction
 // commits so that the write cannot conflict.
 func (cdb *CacheDB) DeleteObject(instanceHash InstanceHash) error {
-	var meta *CacheMetadata
+	_, err := cdb.deleteObject(instanceHash)
+	return err
+}
+
+// deleteObject is DeleteObject, additionally returning the layout of the
+// data that is no longer referenced and whose files should be removed.
+// The result is nil when the object did not exist or when its data is
+// still shared with other instances.
+func (cdb *CacheDB) deleteObject(instanceHash InstanceHash) (*CacheMetadata, error) {
+	var freed *CacheMetadata
+	usageDeltas := make(map[StorageUsageKey]int64)
 	err := cdb.db.Update(func(txn *badger.Txn) error {
-		var txnErr error
-		meta, txnErr = deleteObjectInTxn(txn, cdb.salt, instanceHash)
+		meta, txnErr := deleteObjectInTxn(txn, cdb.salt, instanceHash)
+		if txnErr != nil {
+			return txnErr
+		}
+		freed, txnErr = releaseContentInTxn(txn, meta, usageDeltas)
 		return txnErr
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
-	if meta != nil {
-		// Deduct the on-disk size (content + per-block MAC overhead)
-		// to match what was charged in InitDiskStorage / AllocateChunk.
-		// Inline objects have no MAC overhead.
-		for sid, bytes := range meta.PerDirectoryBytes() {
-			if err := cdb.AddUsage(sid, meta.NamespaceID, -bytes); err != nil {
-				log.Warnf("Failed to decrease usage for storage %d namespace %d: %v",
-					sid, meta.NamespaceID, err)
+	cdb.applyUsageDeltas(usageDeltas)
+	return freed, nil
+}
+
+// applyUsageDeltas applies accumulated usage adjustments via AddUsage.
+// Call it after the transaction that produced them commits so that the
+// writes cannot conflict.
+func (cdb *CacheDB) applyUsageDeltas(usageDeltas map[StorageUsageKey]int64) {
+	for key, delta := range usageDeltas {
+		if err := cdb.AddUsage(key.StorageID, key.NamespaceID, delta); err != nil {
+			log.Warnf("Failed to adjust usage for storage %d namespace %d: %v",
+				key.StorageID, key.NamespaceID, err)
+		}
+	}
+}
+
+// releaseContentInTxn drops a deleted object's claim on its data.  For an
+// object that owns its data, the data is freed outright.  For a
+// deduplicated object, the reference count on the shared ContentRecord is
+// decremented and the data is freed only when the last reference goes.
+//
+// The on-disk size (content + per-block MAC overhead) of freed data is
+// deducted from usageDeltas, matching what was charged in InitDiskStorage /
+// AllocateChunk; inline objects have no MAC overhead.  Returns the layout
+// of the freed data (its DataHash names the files to remove), or nil if
+// nothing was freed.
+func releaseContentInTxn(txn *badger.Txn, meta *CacheMetadata, usageDeltas map[StorageUsageKey]int64) (*CacheMetadata, error) {
+	if meta == nil {
+		return nil, nil
+	}
+
+	freed := meta
+	if meta.ContentHash != "" {
+		rec, err := getContentRecordInTxn(txn, meta.ContentHash)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read content record")
+		}
+		if rec == nil {
+			// The record is gone (e.g. a purged storage directory);
+			// nothing remains to release.
+			return nil, nil
+		}
+		if rec.Refs[meta.NamespaceID] > 0 {
+			rec.Refs[meta.NamespaceID]--
+		}
+		if rec.Refs[meta.NamespaceID] == 0 {
+			delete(rec.Refs, meta.NamespaceID)
+		}
+
+		if len(rec.Refs) > 0 {
+			// Still shared.  If the namespace that pays for the data
+			// just let go of it, hand the charge to a remaining one.
+			if _, ok := rec.Refs[rec.NamespaceID]; !ok {
+				next := rec.nextChargedNamespace()
+				for sid, bytes := range rec.Layout().PerDirectoryBytes() {
+					usageDeltas[StorageUsageKey{StorageID: sid, NamespaceID: rec.NamespaceID}] -= bytes
+					usageDeltas[StorageUsageKey{StorageID: sid, NamespaceID: next}] += bytes
+				}
+				rec.NamespaceID = next
 			}
+			return nil, setContentRecordInTxn(txn, meta.ContentHash, rec)
 		}
+
+		if err := txn.Delete(ContentKey(meta.ContentHash)); err != nil && !errors.Is(err, badger.ErrKeyNotFound) {
+			return nil, errors.Wrap(err, "failed to delete content record")
+		}
+		freed = rec.Layout()
+		freed.ContentHash = meta.ContentHash
 	}
-	return nil
+
+	for sid, bytes := range freed.PerDirectoryBytes() {
+		usageDeltas[StorageUsageKey{StorageID: sid, NamespaceID: freed.NamespaceID}] -= bytes
+	}
+	return freed, nil
 }
 
 // evictedObject holds the information needed to clean up the filesystem
 // after the DB transaction commits.
+//
+// The layout fields describe the data that was freed.  When an evicted
+// object's data is still shared with other instances, nothing is freed:
+// storageID is StorageIDInline and contentLen is zero.
 type evictedObject struct {
 	instanceHash   InstanceHash
+	dataHash       InstanceHash // Names the freed data files (see CacheMetadata.DataHash)
 	storageID      StorageID
 	contentLen     int64
 	namespaceID    NamespaceID
 	chunkSizeCode  ChunkSizeCode   // For chunked objects
 	chunkLocations []ChunkLocation // Locations of chunks 1, 2, ...
+	compression    CompressionCodec
+	storedSizes    []int64 // On-disk size of each chunk file, for compressed objects
 }
 
 // EvictByLRU evicts objects from a storage+namespace combination, draining
@@ -1514,6 +1723,22 @@ type evictedObject struct {
 // to go one object over the byte threshold so that progress is always
 // made even when only large objects remain.
 func (cdb *CacheDB) EvictByLRU(storageID StorageID, namespaceID NamespaceID, maxObjects int, maxBytes int64) ([]evictedObject, error) {
+	return cdb.evict(storageID, namespaceID, nil, maxObjects, maxBytes)
+}
+
+// EvictInOrder is like EvictByLRU, but evicts the given objects in the
+// given order (as ranked by an EvictionPolicy) instead of walking the LRU
+// index.  Purge-first items are still drained first.  Objects that have
+// disappeared, or no longer belong to the storage+namespace, are skipped.
+func (cdb *CacheDB) EvictInOrder(storageID StorageID, namespaceID NamespaceID, order []InstanceHash, maxObjects int, maxBytes int64) ([]evictedObject, error) {
+	if order == nil {
+		order = []InstanceHash{}
+	}
+	return cdb.evict(storageID, namespaceID, order, maxObjects, maxBytes)
+}
+
+// evict implements EvictByLRU (order == nil) and EvictInOrder.
+func (cdb *CacheDB) evict(storageID StorageID, namespaceID NamespaceID, order []InstanceHash, maxObjects int, maxBytes int64) ([]evictedObject, error) {
 	var evicted []evictedObject
 	usageDeltas := make(map[StorageUsageKey]int64)
 
@@ -1541,27 +1766,40 @@ func (cdb *CacheDB) EvictByLRU(storageID StorageID, namespaceID NamespaceID, max
 			if meta == nil {
 				return
 			}
-			evicted = append(evicted, evictedObject{
-				instanceHash:   hash,
-				storageID:      meta.StorageID,
-				contentLen:     meta.ContentLength,
-				namespaceID:    meta.NamespaceID,
-				chunkSizeCode:  meta.ChunkSizeCode,
-				chunkLocations: meta.ChunkLocations,
-			})
-			// For chunked objects, decrement usage from each storage
-			// based on the on-disk bytes it holds.  For
-			// non-chunked objects this returns a single entry for the
-			// base StorageID with CalculateFileSize(ContentLength).
-			for sid, bytes := range meta.PerDirectoryBytes() {
-				key := StorageUsageKey{StorageID: sid, NamespaceID: meta.NamespaceID}
-				usageDeltas[key] -= bytes
+			// Release the object's data.  For chunked objects, usage is
+			// decremented from each storage based on the on-disk bytes
+			// it holds.  For non-chunked objects this is a single entry
+			// for the base StorageID with CalculateFileSize(ContentLength),
+			// or the size of the compressed file for compressed objects.
+			// Data still shared with other instances frees nothing.
+			freed, err := releaseContentInTxn(txn, meta, usageDeltas)
+			if err != nil {
+				log.Warnf("Failed to release data of object %s during eviction: %v", hash, err)
+				return
 			}
-			if meta.StorageID == StorageIDInline {
-				freedBytes += meta.ContentLength
-			} else {
-				freedBytes += CalculateFileSize(meta.ContentLength)
+			obj := evictedObject{
+				instanceHash: hash,
+				dataHash:     hash,
+				namespaceID:  meta.NamespaceID,
+			}
+			if freed != nil {
+				obj.dataHash = freed.DataHash(hash)
+				obj.storageID = freed.StorageID
+				obj.contentLen = freed.ContentLength
+				obj.chunkSizeCode = freed.ChunkSizeCode
+				obj.chunkLocations = freed.ChunkLocations
+				obj.compression = freed.Compression
+				obj.storedSizes = freed.StoredSizes
+				switch {
+				case freed.StorageID == StorageIDInline:
+					freedBytes += freed.ContentLength
+				case freed.IsCompressed():
+					freedBytes += freed.StoredSize()
+				default:
+					freedBytes += CalculateFileSize(freed.ContentLength)
+				}
 			}
+			evicted = append(evicted, obj)
 		}
 
 		// objectUsesDir reports whether an object touches the given
@@ -1621,9 +1859,27 @@ func (cdb *CacheDB) EvictByLRU(storageID StorageID, namespaceID NamespaceID, max
 			}
 		}
 
+		// Phase 2, policy order: evict the ranked objects that still
+		// belong to the requested storage+namespace.
+		if order != nil {
+			for _, hash := range order {
+				if limitReached() {
+					break
+				}
+				meta, err := getMetadataInTxn(txn, hash)
+				if err != nil || meta == nil {
+					continue
+				}
+				if meta.NamespaceID != namespaceID || !objectUsesDir(meta, storageID) {
+					continue
+				}
+				evictOne(hash)
+			}
+		}
+
 		// Phase 2: walk the LRU index for the requested storage+namespace.
 		// This finds objects whose base (chunk 0) is in storageID.
-		if !limitReached() {
+		if order == nil && !limitReached() {
 			lruPrefix := []byte(fmt.Sprintf("%s%d:%d:", PrefixLRU, storageID, namespaceID))
 			opts := badger.DefaultIteratorOptions
 			opts.PrefetchValues = false
@@ -1700,12 +1956,10 @@ func (cdb *CacheDB) EvictByLRU(storageID StorageID, namespaceID NamespaceID, max
 	})
 
 	// Apply accumulated usage decrements via MergeOperator (outside
-	// the eviction transaction so they cannot cause conflicts).
-	for key, delta := range usageDeltas {
-		if err := cdb.AddUsage(key.StorageID, key.NamespaceID, delta); err != nil {
-			log.Warnf("Failed to decrease usage for storage %d namespace %d: %v",
-				key.StorageID, key.NamespaceID, err)
-		}
+	// the eviction transaction so they cannot cause conflicts).  On a
+	// failed transaction nothing was deleted, so nothing is applied.
+	if err == nil {
+		cdb.applyUsageDeltas(usageDeltas)
 	}
 
 	return evicted, err
@@ -1967,9 +2221,17 @@ func (cdb *CacheDB) PurgeStorageID(storageID StorageID) error {
 		}
 
 		err = cdb.db.Update(func(txn *badger.Txn) error {
+			// Usage counters for this storageID are dropped wholesale
+			// below, so the per-object deltas are not needed.
+			discardedDeltas := make(map[StorageUsageKey]int64)
 			for _, hash := range hashes {
-				if _, err := deleteObjectInTxn(txn, cdb.salt, hash); err != nil {
+				meta, err := deleteObjectInTxn(txn, cdb.salt, hash)
+				if err != nil {
 					log.Warnf("Failed to delete object %s during storage purge: %v", hash, err)
+					continue
+				}
+				if _, err := releaseContentInTxn(txn, meta, discardedDeltas); err != nil {
+					log.Warnf("Failed to release data of object %s during storage purge: %v", hash, err)
 				}
 			}
 			return nil
@@ -2015,6 +2277,27 @@ func (cdb *CacheDB) PurgeStorageID(storageID StorageID) error {
 			}
 		}
 
+		// Delete any content records whose data lived in this storageID.
+		contentPrefix := []byte(PrefixContent)
+		contentIt := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer contentIt.Close()
+
+		var contentKeys [][]byte
+		for contentIt.Seek(contentPrefix); contentIt.ValidForPrefix(contentPrefix); contentIt.Next() {
+			var rec ContentRecord
+			if err := contentIt.Item().Value(func(val []byte) error {
+				return msgpack.Unmarshal(val, &rec)
+			}); err != nil || rec.StorageID != storageID {
+				continue
+			}
+			contentKeys = append(contentKeys, contentIt.Item().KeyCopy(nil))
+		}
+		for _, key := range contentKeys {
+			if err := txn.Delete(key); err != nil {
+				log.Warnf("Failed to delete content record during purge: %v", err)
+			}
+		}
+
 		// Delete the disk mapping entry.
 		return txn.Delete(DiskMappingKey(storageID))
 	})
@@ -0,0 +1,370 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package local_cache
+
+// Content-addressed deduplication
+//
+// The same bytes are often published under several namespaces or paths
+// (e.g. mirrored software releases).  Each of those is a distinct
+// instance, but once an instance's SHA-256 checksum is known its data can
+// be shared with every other instance holding the same content:
+//
+//   - The first completed instance with a given checksum has its data
+//     files renamed from the instance hash to the content hash, and a
+//     ContentRecord (cd:<content_hash>) is created with one reference.
+//   - Each further instance adopts the record's layout and DataKey, its
+//     own files are deleted and its usage charge refunded, and the
+//     record's reference count is incremented.
+//   - Deleting or evicting an instance releases its reference (see
+//     releaseContentInTxn); the shared files are removed only when the
+//     last reference goes.
+//
+// Only SHA-256 is trusted to identify content.  Checksums stored in
+// metadata are either computed by the cache over the stored bytes or
+// verified by the transfer client, so they describe the data on disk.
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/dgraph-io/badger/v4"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// errContentChanged is returned when an object's metadata changed between
+// the start of deduplication and its commit; the attempt is abandoned.
+var errContentChanged = errors.New("object changed during deduplication")
+
+// dedupChecksum returns the checksum used to identify meta's content, or
+// nil if the object cannot be deduplicated.
+func dedupChecksum(meta *CacheMetadata) *Checksum {
+	if meta == nil || !meta.IsDisk() || meta.Completed.IsZero() || meta.ContentLength <= 0 || meta.ContentHash != "" {
+		return nil
+	}
+	for i := range meta.Checksums {
+		if meta.Checksums[i].Type == ChecksumSHA256 && len(meta.Checksums[i].Value) > 0 {
+			return &meta.Checksums[i]
+		}
+	}
+	return nil
+}
+
+// nextChargedNamespace picks the namespace that takes over the usage
+// charge for the shared data.  The lowest ID is chosen so the outcome is
+// deterministic.
+func (r *ContentRecord) nextChargedNamespace() NamespaceID {
+	ids := make([]NamespaceID, 0, len(r.Refs))
+	for ns := range r.Refs {
+		ids = append(ids, ns)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids[0]
+}
+
+// getContentRecordInTxn reads a ContentRecord within an existing
+// transaction.  Returns nil (not an error) if the key does not exist.
+func getContentRecordInTxn(txn *badger.Txn, contentHash ContentHash) (*ContentRecord, error) {
+	item, err := txn.Get(ContentKey(contentHash))
+	if err != nil {
+		if errors.Is(err, badger.ErrKeyNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var rec ContentRecord
+	err = item.Value(func(val []byte) error {
+		return msgpack.Unmarshal(val, &rec)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal content record")
+	}
+	if rec.Refs == nil {
+		rec.Refs = make(map[NamespaceID]uint32)
+	}
+	return &rec, nil
+}
+
+// setContentRecordInTxn stores a ContentRecord within an existing transaction.
+func setContentRecordInTxn(txn *badger.Txn, contentHash ContentHash, rec *ContentRecord) error {
+	data, err := msgpack.Marshal(rec)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal content record")
+	}
+	return txn.Set(ContentKey(contentHash), data)
+}
+
+// setMetadataInTxn stores metadata within an existing transaction.
+func setMetadataInTxn(txn *badger.Txn, instanceHash InstanceHash, meta *CacheMetadata) error {
+	data, err := msgpack.Marshal(meta)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal metadata")
+	}
+	return txn.Set(MetaKey(instanceHash), data)
+}
+
+// GetContentRecord retrieves the record for contentHash, or nil if the
+// content is not stored.
+func (cdb *CacheDB) GetContentRecord(contentHash ContentHash) (*ContentRecord, error) {
+	var rec *ContentRecord
+	err := cdb.db.View(func(txn *badger.Txn) error {
+		var err error
+		rec, err = getContentRecordInTxn(txn, contentHash)
+		return err
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get content record")
+	}
+	return rec, nil
+}
+
+// ScanContent iterates over all content records.
+func (cdb *CacheDB) ScanContent(fn func(contentHash ContentHash, rec *ContentRecord) error) error {
+	return cdb.db.View(func(txn *badger.Txn) error {
+		prefix := []byte(PrefixContent)
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+
+		for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+			item := it.Item()
+			contentHash := ContentHash(item.Key()[len(PrefixContent):])
+			var rec ContentRecord
+			err := item.Value(func(val []byte) error {
+				return msgpack.Unmarshal(val, &rec)
+			})
+			if err != nil {
+				log.Warnf("Failed to unmarshal content record %s: %v", contentHash, err)
+				continue
+			}
+			if err := fn(contentHash, &rec); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// sameData reports whether meta still describes the data that expected
+// described when deduplication started.
+func sameData(meta, expected *CacheMetadata) bool {
+	return meta != nil &&
+		meta.ContentHash == "" &&
+		!meta.Completed.IsZero() &&
+		meta.ContentLength == expected.ContentLength &&
+		meta.StorageID == expected.StorageID &&
+		meta.Compression == expected.Compression &&
+		bytes.Equal(meta.DataKey, expected.DataKey)
+}
+
+// CreateContent registers the data of instanceHash as the first copy of
+// contentHash, with one reference.  The caller must already have renamed
+// the object's files to their content-addressed names.  Returns
+// errContentChanged if the metadata no longer matches expected or if the
+// content was registered concurrently.
+func (cdb *CacheDB) CreateContent(instanceHash InstanceHash, contentHash ContentHash, expected *CacheMetadata) error {
+	return cdb.db.Update(func(txn *badger.Txn) error {
+		meta, err := getMetadataInTxn(txn, instanceHash)
+		if err != nil {
+			return err
+		}
+		if !sameData(meta, expected) {
+			return errContentChanged
+		}
+		existing, err := getContentRecordInTxn(txn, contentHash)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			return errContentChanged
+		}
+
+		rec := &ContentRecord{
+			Refs:           map[NamespaceID]uint32{meta.NamespaceID: 1},
+			NamespaceID:    meta.NamespaceID,
+			StorageID:      meta.StorageID,
+			ContentLength:  meta.ContentLength,
+			ChunkSizeCode:  meta.ChunkSizeCode,
+			ChunkLocations: meta.ChunkLocations,
+			DataKey:        meta.DataKey,
+			Compression:    meta.Compression,
+			StoredSizes:    meta.StoredSizes,
+		}
+		if err := setContentRecordInTxn(txn, contentHash, rec); err != nil {
+			return err
+		}
+		meta.ContentHash = contentHash
+		return setMetadataInTxn(txn, instanceHash, meta)
+	})
+}
+
+// ReferenceContent points instanceHash at the existing copy of
+// contentHash: the object adopts the record's layout and DataKey and the
+// record gains a reference.  The object's own files are left for the
+// caller to remove, and its usage charge for the caller to refund.
+// Returns errContentChanged if the metadata no longer matches expected
+// or the record no longer exists.
+func (cdb *CacheDB) ReferenceContent(instanceHash InstanceHash, contentHash ContentHash, expected *CacheMetadata) error {
+	return cdb.db.Update(func(txn *badger.Txn) error {
+		meta, err := getMetadataInTxn(txn, instanceHash)
+		if err != nil {
+			return err
+		}
+		if !sameData(meta, expected) {
+			return errContentChanged
+		}
+		rec, err := getContentRecordInTxn(txn, contentHash)
+		if err != nil {
+			return err
+		}
+		if rec == nil || rec.ContentLength != meta.ContentLength {
+			return errContentChanged
+		}
+
+		// The LRU key embeds the StorageID; move it to the shared copy's.
+		if !meta.LastAccessTime.IsZero() && meta.StorageID != rec.StorageID {
+			oldKey := LRUKey(meta.StorageID, meta.NamespaceID, meta.LastAccessTime, instanceHash)
+			if err := txn.Delete(oldKey); err != nil && !errors.Is(err, badger.ErrKeyNotFound) {
+				return errors.Wrap(err, "failed to delete old LRU key")
+			}
+			newKey := LRUKey(rec.StorageID, meta.NamespaceID, meta.LastAccessTime, instanceHash)
+			if err := txn.Set(newKey, nil); err != nil {
+				return errors.Wrap(err, "failed to set new LRU key")
+			}
+		}
+
+		rec.Refs[meta.NamespaceID]++
+		if err := setContentRecordInTxn(txn, contentHash, rec); err != nil {
+			return err
+		}
+
+		meta.ContentHash = contentHash
+		meta.StorageID = rec.StorageID
+		meta.ChunkSizeCode = rec.ChunkSizeCode
+		meta.ChunkLocations = rec.ChunkLocations
+		meta.DataKey = rec.DataKey
+		meta.Compression = rec.Compression
+		meta.StoredSizes = rec.StoredSizes
+		return setMetadataInTxn(txn, instanceHash, meta)
+	})
+}
+
+// DeduplicateObject shares the data of a completed disk object with any
+// other instance holding identical content, or registers it as the shared
+// copy if it is the first.  Objects without a SHA-256 checksum, inline
+// objects and objects that are already deduplicated are left untouched.
+// Returns true if the object now references shared content.
+func (sm *StorageManager) DeduplicateObject(instanceHash InstanceHash) (bool, error) {
+	sm.rewriteMu.Lock()
+	defer sm.rewriteMu.Unlock()
+
+	meta, err := sm.db.GetMetadata(instanceHash)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get metadata")
+	}
+	checksum := dedupChecksum(meta)
+	if checksum == nil {
+		return false, nil
+	}
+	contentHash := ComputeContentHash(sm.db.Salt(), *checksum)
+
+	rec, err := sm.db.GetContentRecord(contentHash)
+	if err != nil {
+		return false, err
+	}
+
+	// Readers wait while the object's data files are renamed or deleted
+	// and its cached metadata replaced, so none is left holding
+	// metadata that names files which are gone.
+	lock := sm.lockDataFiles(instanceHash)
+	lock.Lock()
+	defer lock.Unlock()
+
+	if rec == nil {
+		if err := sm.renameDataFiles(meta, instanceHash, InstanceHash(contentHash)); err != nil {
+			return false, err
+		}
+		if err := sm.db.CreateContent(instanceHash, contentHash, meta); err != nil {
+			if renameErr := sm.renameDataFiles(meta, InstanceHash(contentHash), instanceHash); renameErr != nil {
+				log.Warnf("Failed to restore data files of %s after failed deduplication: %v", instanceHash, renameErr)
+			}
+			if errors.Is(err, errContentChanged) {
+				return false, nil
+			}
+			return false, errors.Wrap(err, "failed to create content record")
+		}
+		sm.invalidateObjectCaches(instanceHash, meta.ChunkCount())
+		log.Debugf("Registered %s as shared content %s", instanceHash, contentHash)
+		return true, nil
+	}
+
+	if rec.ContentLength != meta.ContentLength {
+		return false, nil
+	}
+	if err := sm.db.ReferenceContent(instanceHash, contentHash, meta); err != nil {
+		if errors.Is(err, errContentChanged) {
+			return false, nil
+		}
+		return false, errors.Wrap(err, "failed to reference content record")
+	}
+
+	// The object now reads the shared copy; drop its own data and the
+	// usage that was charged for it.
+	sm.invalidateObjectCaches(instanceHash, meta.ChunkCount())
+	sm.deleteChunkFiles(instanceHash, meta)
+	for sid, bytes := range meta.PerDirectoryBytes() {
+		if err := sm.db.AddUsage(sid, meta.NamespaceID, -bytes); err != nil {
+			log.Warnf("Failed to decrease usage for storage %d namespace %d: %v", sid, meta.NamespaceID, err)
+		}
+	}
+	log.Debugf("Deduplicated %s against shared content %s", instanceHash, contentHash)
+	return true, nil
+}
+
+// renameDataFiles renames each allocated chunk file of meta from the
+// names derived from from to those derived from to.  On failure, files
+// already renamed are moved back.
+func (sm *StorageManager) renameDataFiles(meta *CacheMetadata, from, to InstanceHash) error {
+	var renamed []int
+	for chunkIdx := 0; chunkIdx < meta.ChunkCount(); chunkIdx++ {
+		if !meta.IsChunkAllocated(chunkIdx) {
+			continue
+		}
+		src := sm.getDataFilePath(meta, from, chunkIdx)
+		dst := sm.getDataFilePath(meta, to, chunkIdx)
+		// The destination's parent directory may not exist yet.
+		err := os.MkdirAll(filepath.Dir(dst), 0750)
+		if err == nil {
+			err = os.Rename(src, dst)
+		}
+		if err != nil {
+			for _, idx := range renamed {
+				if undoErr := os.Rename(sm.getDataFilePath(meta, to, idx), sm.getDataFilePath(meta, from, idx)); undoErr != nil {
+					log.Warnf("Failed to restore chunk %d of %s: %v", idx, from, undoErr)
+				}
+			}
+			return errors.Wrapf(err, "failed to rename chunk %d file", chunkIdx)
+		}
+		renamed = append(renamed, chunkIdx)
+	}
+	return nil
+}
@@ -0,0 +1,338 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package local_cache
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+)
+
+// setupDedupStorage creates a single-directory storage manager for the
+// deduplication tests.
+func setupDedupStorage(t *testing.T) (*CacheDB, *StorageManager, StorageID) {
+	InitIssuerKeyForTests(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	db, err := NewCacheDB(ctx, t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	egrp, _ := errgroup.WithContext(ctx)
+	storage, err := NewStorageManager(db, []string{t.TempDir()}, 0, egrp)
+	require.NoError(t, err)
+	t.Cleanup(storage.Close)
+
+	return db, storage, storage.DirIDs()[0]
+}
+
+// createDedupObject stores data as a completed disk object in the given
+// namespace.  When withSHA256 is set, the object's SHA-256 checksum is
+// recorded as the consistency checker would.
+func createDedupObject(t *testing.T, db *CacheDB, storage *StorageManager, i int, sid StorageID, ns NamespaceID, data []byte, withSHA256 bool) InstanceHash {
+	instanceHash := InstanceHash(fmt.Sprintf("%064x", i))
+	meta, err := storage.InitDiskStorage(context.Background(), instanceHash, int64(len(data)), sid, ns)
+	require.NoError(t, err)
+	meta.ETag = fmt.Sprintf("etag-%d", i)
+	meta.SourceURL = fmt.Sprintf("pelican://example.com/ns%d/obj-%d", ns, i)
+	require.NoError(t, storage.SetMetadata(instanceHash, meta))
+	require.NoError(t, storage.WriteBlocks(instanceHash, 0, data))
+	require.NoError(t, db.UpdateLRU(instanceHash, 0))
+
+	if withSHA256 {
+		sum := sha256.Sum256(data)
+		require.NoError(t, db.MergeMetadata(instanceHash, &CacheMetadata{
+			Checksums: []Checksum{{Type: ChecksumSHA256, Value: sum[:]}},
+		}))
+	}
+
+	complete, err := storage.IsComplete(instanceHash)
+	require.NoError(t, err)
+	require.True(t, complete)
+	return instanceHash
+}
+
+func dedupTestData(size int, seed byte) []byte {
+	data := make([]byte, size)
+	for i := range data {
+		data[i] = byte(i) ^ seed
+	}
+	return data
+}
+
+func TestDeduplicateObject(t *testing.T) {
+	db, storage, sid := setupDedupStorage(t)
+
+	data := dedupTestData(3*BlockDataSize+100, 0x5a)
+	fileSize := CalculateFileSize(int64(len(data)))
+
+	h1 := createDedupObject(t, db, storage, 1, sid, 1, data, true)
+	h2 := createDedupObject(t, db, storage, 2, sid, 2, data, true)
+	other := createDedupObject(t, db, storage, 3, sid, 2, dedupTestData(len(data), 0x11), true)
+
+	ok, err := storage.DeduplicateObject(h1)
+	require.NoError(t, err)
+	require.True(t, ok)
+	ok, err = storage.DeduplicateObject(h2)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// Already-deduplicated objects are left alone.
+	ok, err = storage.DeduplicateObject(h2)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	meta1, err := storage.GetMetadata(h1)
+	require.NoError(t, err)
+	meta2, err := storage.GetMetadata(h2)
+	require.NoError(t, err)
+	require.NotEmpty(t, meta1.ContentHash)
+	assert.Equal(t, meta1.ContentHash, meta2.ContentHash)
+	assert.Equal(t, meta1.DataKey, meta2.DataKey)
+
+	// One physical copy under the content hash; the instance files are gone.
+	contentPath := storage.getObjectPathForDir(sid, meta1.DataHash(h1))
+	_, err = os.Stat(contentPath)
+	assert.NoError(t, err)
+	for _, h := range []InstanceHash{h1, h2} {
+		_, err = os.Stat(storage.getObjectPathForDir(sid, h))
+		assert.True(t, os.IsNotExist(err), "instance file of %s should be gone", h)
+	}
+
+	rec, err := db.GetContentRecord(meta1.ContentHash)
+	require.NoError(t, err)
+	require.NotNil(t, rec)
+	assert.Equal(t, uint32(2), rec.RefCount())
+	assert.Equal(t, NamespaceID(1), rec.NamespaceID)
+
+	// Both instances read the shared data.
+	for _, h := range []InstanceHash{h1, h2} {
+		got, err := storage.ReadBlocks(h, 0, len(data))
+		require.NoError(t, err)
+		assert.Equal(t, data, got)
+	}
+
+	// The shared copy is charged once, to the first namespace.
+	usage1, err := db.GetUsage(sid, 1)
+	require.NoError(t, err)
+	assert.Equal(t, fileSize, usage1)
+	usage2, err := db.GetUsage(sid, 2)
+	require.NoError(t, err)
+	assert.Equal(t, fileSize, usage2, "namespace 2 pays only for its distinct object")
+
+	// The distinct object is registered but shares nothing.
+	ok, err = storage.DeduplicateObject(other)
+	require.NoError(t, err)
+	require.True(t, ok)
+	otherMeta, err := storage.GetMetadata(other)
+	require.NoError(t, err)
+	assert.NotEqual(t, meta1.ContentHash, otherMeta.ContentHash)
+}
+
+// Readers racing with deduplication must never see the object's data
+// files disappear from under them.
+func TestDeduplicateObjectWhileReading(t *testing.T) {
+	db, storage, sid := setupDedupStorage(t)
+
+	data := dedupTestData(3*BlockDataSize+100, 0x3c)
+	h1 := createDedupObject(t, db, storage, 1, sid, 1, data, true)
+	h2 := createDedupObject(t, db, storage, 2, sid, 2, data, true)
+
+	// A reader opened before deduplication keeps reading its own files.
+	reader, err := storage.NewObjectReader(h2)
+	require.NoError(t, err)
+	defer reader.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	readers, _ := errgroup.WithContext(ctx)
+	for i := 0; i < 4; i++ {
+		readers.Go(func() error {
+			for ctx.Err() == nil {
+				for _, h := range []InstanceHash{h1, h2} {
+					got, err := storage.ReadBlocks(h, 0, len(data))
+					if err != nil {
+						return err
+					}
+					if !bytes.Equal(data, got) {
+						return fmt.Errorf("read wrong data from %s", h)
+					}
+				}
+			}
+			return nil
+		})
+	}
+
+	ok, err := storage.DeduplicateObject(h1)
+	require.NoError(t, err)
+	require.True(t, ok)
+	ok, err = storage.DeduplicateObject(h2)
+	require.NoError(t, err)
+	require.True(t, ok)
+	cancel()
+	require.NoError(t, readers.Wait())
+
+	got, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, data, got)
+}
+
+func TestDeduplicateObjectWithoutSHA256(t *testing.T) {
+	db, storage, sid := setupDedupStorage(t)
+
+	h := createDedupObject(t, db, storage, 1, sid, 1, dedupTestData(2*BlockDataSize, 1), false)
+	ok, err := storage.DeduplicateObject(h)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	_, err = os.Stat(storage.getObjectPathForDir(sid, h))
+	assert.NoError(t, err, "instance file should be untouched")
+}
+
+// TestDeduplicatedEviction verifies that evicting or deleting instances
+// frees the shared data only when the last reference goes, and that the
+// usage charge follows the remaining references.
+func TestDeduplicatedEviction(t *testing.T) {
+	db, storage, sid := setupDedupStorage(t)
+
+	data := dedupTestData(5*BlockDataSize, 0x33)
+	fileSize := CalculateFileSize(int64(len(data)))
+
+	h1 := createDedupObject(t, db, storage, 1, sid, 1, data, true)
+	h2 := createDedupObject(t, db, storage, 2, sid, 2, data, true)
+	h3 := createDedupObject(t, db, storage, 3, sid, 2, data, true)
+	for _, h := range []InstanceHash{h1, h2, h3} {
+		ok, err := storage.DeduplicateObject(h)
+		require.NoError(t, err)
+		require.True(t, ok)
+	}
+	meta, err := storage.GetMetadata(h1)
+	require.NoError(t, err)
+	contentHash := meta.ContentHash
+	contentPath := storage.getObjectPathForDir(sid, meta.DataHash(h1))
+
+	// Evicting the charged namespace's only reference frees nothing and
+	// moves the charge to namespace 2.
+	evicted, freed, err := storage.EvictByLRU(sid, 1, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, evicted, 1)
+	assert.Equal(t, h1, evicted[0].instanceHash)
+	assert.Equal(t, uint64(0), freed)
+	_, err = os.Stat(contentPath)
+	require.NoError(t, err, "shared data must survive while referenced")
+
+	usage1, err := db.GetUsage(sid, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), usage1)
+	usage2, err := db.GetUsage(sid, 2)
+	require.NoError(t, err)
+	assert.Equal(t, fileSize, usage2)
+
+	got, err := storage.ReadBlocks(h2, 0, len(data))
+	require.NoError(t, err)
+	assert.Equal(t, data, got)
+
+	// Deleting one of the two remaining references still keeps the data.
+	require.NoError(t, storage.Delete(h2))
+	_, err = os.Stat(contentPath)
+	require.NoError(t, err)
+	rec, err := db.GetContentRecord(contentHash)
+	require.NoError(t, err)
+	require.NotNil(t, rec)
+	assert.Equal(t, uint32(1), rec.RefCount())
+
+	// Evicting the last reference frees the data.
+	evicted, freed, err = storage.EvictByLRU(sid, 2, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, evicted, 1)
+	assert.Equal(t, uint64(fileSize), freed)
+	_, err = os.Stat(contentPath)
+	assert.True(t, os.IsNotExist(err), "shared data should be removed with the last reference")
+
+	rec, err = db.GetContentRecord(contentHash)
+	require.NoError(t, err)
+	assert.Nil(t, rec)
+	usage2, err = db.GetUsage(sid, 2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), usage2)
+}
+
+// TestConsistencyCheckerDeduplication verifies that the data scan
+// deduplicates objects (computing SHA-256 where missing) and that the
+// metadata scan keeps shared content files and their usage.
+func TestConsistencyCheckerDeduplication(t *testing.T) {
+	db, storage, sid := setupDedupStorage(t)
+
+	data := dedupTestData(4*BlockDataSize+7, 0x42)
+	fileSize := CalculateFileSize(int64(len(data)))
+
+	// One object with no checksums at all, one with only an MD5.
+	h1 := createDedupObject(t, db, storage, 1, sid, 1, data, false)
+	h2 := createDedupObject(t, db, storage, 2, sid, 2, data, false)
+	md5Sum := md5.Sum(data)
+	require.NoError(t, db.MergeMetadata(h2, &CacheMetadata{
+		Checksums: []Checksum{{Type: ChecksumMD5, Value: md5Sum[:]}},
+	}))
+
+	cc := NewConsistencyChecker(db, storage, ConsistencyConfig{
+		MinAgeForCleanup: 0,
+		Deduplicate:      true,
+	})
+	ctx := context.Background()
+	require.NoError(t, cc.RunDataScan(ctx, nil))
+
+	meta1, err := storage.GetMetadata(h1)
+	require.NoError(t, err)
+	meta2, err := storage.GetMetadata(h2)
+	require.NoError(t, err)
+	require.NotEmpty(t, meta1.ContentHash)
+	assert.Equal(t, meta1.ContentHash, meta2.ContentHash)
+
+	require.NoError(t, cc.RunMetadataScan(ctx, nil))
+	stats := cc.GetStats()
+	assert.Equal(t, int64(0), stats.OrphanedFiles)
+	assert.Equal(t, int64(0), stats.OrphanedDBEntries)
+
+	_, err = os.Stat(storage.getObjectPathForDir(sid, meta1.DataHash(h1)))
+	require.NoError(t, err, "metadata scan must not remove shared content")
+
+	usage1, err := db.GetUsage(sid, 1)
+	require.NoError(t, err)
+	assert.Equal(t, fileSize, usage1)
+	usage2, err := db.GetUsage(sid, 2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), usage2)
+
+	// Both objects still verify against the shared data.
+	for _, h := range []InstanceHash{h1, h2} {
+		valid, err := cc.VerifyObject(h)
+		require.NoError(t, err)
+		assert.True(t, valid)
+	}
+}
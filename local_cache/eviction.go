@@ -72,6 +72,32 @@ type EvictionManager struct {
 	evicting        bool
 	evictChan       chan struct{}
 	evictRunCounter atomic.Uint64
+
+	// Eviction policies.  A namespace's policy overrides its storage
+	// directory's, which overrides defaultPolicy.  dirPolicies and
+	// nsPolicyByPrefix are read-only after construction; nsPolicies is
+	// filled in as namespace IDs are bound (see BindNamespace).
+	defaultPolicy    EvictionPolicy
+	dirPolicies      map[StorageID]EvictionPolicy
+	nsPolicyByPrefix map[string]EvictionPolicy
+	nsPoliciesMu     sync.RWMutex
+	nsPolicies       map[NamespaceID]EvictionPolicy
+
+	// Namespace quotas and reservations, bound to namespace IDs the same
+	// way as nsPolicies (see BindNamespace).
+	nsQuotaByPrefix map[string]NamespaceQuota
+	nsQuotasMu      sync.RWMutex
+	nsQuotas        map[NamespaceID]NamespaceQuota
+
+	// Per-policy counters not yet flushed to the database, keyed by
+	// policy name.
+	policyStatsMu sync.Mutex
+	policyStats   map[string]EvictionPolicyStats
+
+	// Accesses whose database write was debounced, carried forward to
+	// the next write for the object (see RecordAccess).
+	accessMu    sync.Mutex
+	pendingHits map[InstanceHash]uint32
 }
 
 // dirEvictionLimits holds the size limits for a single storage directory.
@@ -86,15 +112,28 @@ type EvictionConfig struct {
 	// DirConfigs maps storageID to its eviction limits.
 	// Each entry describes one storage directory.
 	DirConfigs map[StorageID]EvictionDirConfig
+
+	// DefaultPolicy orders eviction within a namespace unless overridden
+	// per directory or per namespace.  nil means LRU.
+	DefaultPolicy EvictionPolicy
+
+	// NamespacePolicies maps namespace prefixes (e.g. "/foo") to the
+	// policy used for that namespace in every directory.
+	NamespacePolicies map[string]EvictionPolicy
+
+	// NamespaceQuotas maps namespace prefixes to their space limits
+	// across all directories.
+	NamespaceQuotas map[string]NamespaceQuota
 }
 
 // EvictionDirConfig holds per-directory eviction configuration.
 type EvictionDirConfig struct {
-	MaxSize             uint64 // Maximum cache size in bytes for this directory
-	HighWaterPercentage int    // Percentage at which eviction starts (0 = default 90)
-	LowWaterPercentage  int    // Percentage at which eviction stops  (0 = default 80)
-	HighWaterBytes      uint64 // Absolute byte threshold (overrides percentage when > 0)
-	LowWaterBytes       uint64 // Absolute byte threshold (overrides percentage when > 0)
+	MaxSize             uint64         // Maximum cache size in bytes for this directory
+	HighWaterPercentage int            // Percentage at which eviction starts (0 = default 90)
+	LowWaterPercentage  int            // Percentage at which eviction stops  (0 = default 80)
+	HighWaterBytes      uint64         // Absolute byte threshold (overrides percentage when > 0)
+	LowWaterBytes       uint64         // Absolute byte threshold (overrides percentage when > 0)
+	Policy              EvictionPolicy // Overrides EvictionConfig.DefaultPolicy when non-nil
 }
 
 // NewEvictionManager creates a new eviction manager
@@ -154,15 +193,48 @@ func NewEvictionManager(db *CacheDB, storage *StorageManager, config EvictionCon
 	}
 	sort.Slice(dirIDs, func(i, j int) bool { return dirIDs[i] < dirIDs[j] })
 
+	defaultPolicy := config.DefaultPolicy
+	if defaultPolicy == nil {
+		defaultPolicy = &lruPolicy{}
+	}
+	dirPolicies := make(map[StorageID]EvictionPolicy)
+	for id, dcfg := range config.DirConfigs {
+		if dcfg.Policy != nil {
+			dirPolicies[id] = dcfg.Policy
+		}
+	}
+
 	em := &EvictionManager{
-		db:        db,
-		storage:   storage,
-		dirLimits: dirLimits,
-		dirUsage:  dirUsage,
-		dirIDs:    dirIDs,
-		evictChan: make(chan struct{}, 1),
+		db:               db,
+		storage:          storage,
+		dirLimits:        dirLimits,
+		dirUsage:         dirUsage,
+		dirIDs:           dirIDs,
+		evictChan:        make(chan struct{}, 1),
+		defaultPolicy:    defaultPolicy,
+		dirPolicies:      dirPolicies,
+		nsPolicyByPrefix: config.NamespacePolicies,
+		nsPolicies:       make(map[NamespaceID]EvictionPolicy),
+		nsQuotaByPrefix:  config.NamespaceQuotas,
+		nsQuotas:         make(map[NamespaceID]NamespaceQuota),
+		policyStats:      make(map[string]EvictionPolicyStats),
+		pendingHits:      make(map[InstanceHash]uint32),
 	}
 	em.rebuildRRTable()
+
+	// Reservations are only guaranteed if they fit below the point where
+	// eviction stops.
+	var reserved, lowWater uint64
+	for _, q := range config.NamespaceQuotas {
+		reserved += q.ReservedBytes
+	}
+	for _, limits := range dirLimits {
+		lowWater += limits.lowWater
+	}
+	if reserved > lowWater {
+		log.Warnf("Namespace reservations total %s but the cache's low-water marks total only %s; reservations cannot all be honored",
+			utils.HumanBytes(reserved), utils.HumanBytes(lowWater))
+	}
 	return em
 }
 
@@ -184,9 +256,11 @@ func (em *EvictionManager) evictionLoop(ctx context.Context) error {
 	for {
 		select {
 		case <-ctx.Done():
+			em.flushPolicyStats()
 			return nil
 		case <-ticker.C:
 			em.checkAndEvict()
+			em.flushPolicyStats()
 		case <-em.evictChan:
 			em.checkAndEvict()
 		}
@@ -330,6 +404,10 @@ func (em *EvictionManager) checkAndEvict() {
 	var totalEvictedObjects atomic.Int64
 	var totalConflicts atomic.Int64
 
+	quotaFreed, quotaCount := em.enforceQuotas(rl)
+	totalEvictedBytes.Add(quotaFreed)
+	totalEvictedObjects.Add(int64(quotaCount))
+
 	var wg sync.WaitGroup
 	for sid, limits := range em.dirLimits {
 		wg.Add(1)
@@ -355,11 +433,11 @@ func (em *EvictionManager) checkAndEvict() {
 				}
 
 				if targetUsage <= 0 {
-					rl.WithFields(log.Fields{"storageID": sid}).Warn("No namespace with positive usage found")
+					rl.WithFields(log.Fields{"storageID": sid}).Warn("No namespace with evictable usage found; remaining usage is reserved")
 					break
 				}
 
-				overhead := dirUsage - int64(limits.lowWater)
+				overhead := min(dirUsage-int64(limits.lowWater), targetUsage)
 				rl.WithFields(log.Fields{
 					"storageID":   targetKey.StorageID,
 					"namespaceID": targetKey.NamespaceID,
@@ -402,17 +480,28 @@ func (em *EvictionManager) checkAndEvict() {
 	}
 }
 
-// findGreediestNamespaceInDir finds the namespace with highest usage
-// within a specific storage directory.
+// findGreediestNamespaceInDir finds the namespace with the highest
+// evictable usage within a specific storage directory.  Usage covered by
+// a namespace's reservation is not evictable; the returned usage is the
+// most that may be evicted from the namespace.
 func (em *EvictionManager) findGreediestNamespaceInDir(storageID StorageID) (StorageUsageKey, int64, error) {
-	nsUsage, err := em.db.GetDirUsage(storageID)
+	allUsage, err := em.db.GetAllUsage()
 	if err != nil {
 		return StorageUsageKey{}, 0, errors.Wrap(err, "failed to get namespace usage")
 	}
+	totals := make(map[NamespaceID]int64)
+	for key, usage := range allUsage {
+		totals[key.NamespaceID] += usage
+	}
 
 	var bestNS NamespaceID
 	var bestUsage int64
-	for ns, usage := range nsUsage {
+	for key, usage := range allUsage {
+		if key.StorageID != storageID {
+			continue
+		}
+		ns := key.NamespaceID
+		usage = em.evictableUsage(ns, usage, totals[ns])
 		if usage > bestUsage {
 			bestUsage = usage
 			bestNS = ns
@@ -437,6 +526,8 @@ func (em *EvictionManager) findGreediestNamespaceInDir(storageID StorageID) (Sto
 // Returns total bytes freed, number of objects evicted, number of conflicts, and any
 // non-retryable error.
 func (em *EvictionManager) evictFromNamespace(rl *log.Entry, storageID StorageID, namespaceID NamespaceID, maxObjects int, maxBytes int64) (totalFreed uint64, totalCount int, conflicts int, err error) {
+	policy := em.policyFor(storageID, namespaceID)
+
 	// batchCaps defines the decreasing batch sizes used on successive
 	// conflict retries.  The first attempt uses the caller's original
 	// limits; subsequent retries cap maxObjects to reduce the transaction
@@ -451,13 +542,19 @@ func (em *EvictionManager) evictFromNamespace(rl *log.Entry, storageID StorageID
 
 		var evicted []evictedObject
 		var freed uint64
-		evicted, freed, err = em.storage.EvictByLRU(storageID, namespaceID, effMaxObjects, maxBytes)
+		var candidates map[InstanceHash]EvictionCandidate
+		if _, isLRU := policy.(*lruPolicy); isLRU {
+			evicted, freed, err = em.storage.EvictByLRU(storageID, namespaceID, effMaxObjects, maxBytes)
+		} else {
+			evicted, freed, candidates, err = em.evictByPolicy(policy, storageID, namespaceID, effMaxObjects, maxBytes)
+		}
 
 		if err != nil && errors.Is(err, badger.ErrConflict) {
 			conflicts++
 			fields := log.Fields{
 				"storageID":   storageID,
 				"namespaceID": namespaceID,
+				"policy":      policy.Name(),
 				"attempt":     attempt + 1,
 				"batchCap":    fmt.Sprintf("%d", effMaxObjects),
 			}
@@ -478,6 +575,7 @@ func (em *EvictionManager) evictFromNamespace(rl *log.Entry, storageID StorageID
 		}
 
 		em.noteEvicted(evicted)
+		em.notePolicyEvictions(policy, storageID, evicted, freed, candidates)
 
 		for _, obj := range evicted {
 			rl.WithFields(log.Fields{
@@ -494,6 +592,51 @@ func (em *EvictionManager) evictFromNamespace(rl *log.Entry, storageID StorageID
 	return 0, 0, conflicts, err
 }
 
+// evictByPolicy ranks a window of the namespace's least-recently-used
+// objects in storageID with policy and evicts them in that order.  The
+// ranked candidates are returned, keyed by instance hash, so the policy
+// can be told which of them went.
+func (em *EvictionManager) evictByPolicy(policy EvictionPolicy, storageID StorageID, namespaceID NamespaceID, maxObjects int, maxBytes int64) ([]evictedObject, uint64, map[InstanceHash]EvictionCandidate, error) {
+	candidates, err := em.db.EvictionCandidates(storageID, namespaceID, policyCandidateWindow)
+	if err != nil {
+		return nil, 0, nil, errors.Wrap(err, "failed to list eviction candidates")
+	}
+	policy.Order(storageID, candidates)
+
+	order := make([]InstanceHash, len(candidates))
+	byHash := make(map[InstanceHash]EvictionCandidate, len(candidates))
+	for i, c := range candidates {
+		order[i] = c.InstanceHash
+		byHash[c.InstanceHash] = c
+	}
+	evicted, freed, err := em.storage.EvictInOrder(storageID, namespaceID, order, maxObjects, maxBytes)
+	return evicted, freed, byHash, err
+}
+
+// notePolicyEvictions records a committed eviction batch in the policy's
+// counters and reports the evicted candidates back to the policy.
+// Purge-first and cross-directory objects are not candidates and are
+// only counted.
+func (em *EvictionManager) notePolicyEvictions(policy EvictionPolicy, storageID StorageID, evicted []evictedObject, freed uint64, candidates map[InstanceHash]EvictionCandidate) {
+	if len(evicted) == 0 {
+		return
+	}
+	em.addPolicyStats(policy.Name(), EvictionPolicyStats{
+		EvictedObjects: uint64(len(evicted)),
+		EvictedBytes:   freed,
+	})
+	if candidates == nil {
+		return
+	}
+	reported := make([]EvictionCandidate, 0, len(evicted))
+	for _, obj := range evicted {
+		if c, ok := candidates[obj.instanceHash]; ok {
+			reported = append(reported, c)
+		}
+	}
+	policy.Evicted(storageID, reported)
+}
+
 // noteEvicted adjusts the per-directory in-memory atomic counters after
 // a batch of objects has been removed from the DB.  The DB-level usage
 // counters were already decremented inside the transaction; this keeps
@@ -519,24 +662,132 @@ func (em *EvictionManager) noteEvicted(evicted []evictedObject) {
 			counter.Add(-freed)
 		}
 	}
+
+	em.accessMu.Lock()
+	for _, obj := range evicted {
+		delete(em.pendingHits, obj.instanceHash)
+	}
+	em.accessMu.Unlock()
 }
 
-// RecordAccess records an access to an object, updating LRU
-func (em *EvictionManager) RecordAccess(instanceHash InstanceHash) error {
-	// Use 10 minute debounce as specified in design doc
-	return em.db.UpdateLRU(instanceHash, 10*time.Minute)
+// RecordAccess records an access to an object, updating its LRU position
+// and access count.  hit reports whether the object was already cached
+// and is counted towards the hit ratio of the object's eviction policy.
+//
+// The database write is debounced to one per object per 10 minutes (as
+// specified in the design doc); accesses in between are held in memory
+// and added to the count on the next write.
+func (em *EvictionManager) RecordAccess(instanceHash InstanceHash, meta *CacheMetadata, hit bool) error {
+	if meta != nil {
+		policy := em.policyFor(meta.StorageID, meta.NamespaceID)
+		if hit {
+			em.addPolicyStats(policy.Name(), EvictionPolicyStats{Hits: 1})
+		} else {
+			em.addPolicyStats(policy.Name(), EvictionPolicyStats{Misses: 1})
+			policy.Missed(meta.StorageID, instanceHash)
+		}
+	}
+
+	em.accessMu.Lock()
+	hits := em.pendingHits[instanceHash] + 1
+	delete(em.pendingHits, instanceHash)
+	em.accessMu.Unlock()
+
+	written, err := em.db.RecordAccess(instanceHash, hits, 10*time.Minute)
+	if err != nil || !written {
+		em.accessMu.Lock()
+		em.pendingHits[instanceHash] += hits
+		em.accessMu.Unlock()
+	}
+	return err
+}
+
+// BindNamespace associates a namespace ID with its prefix so that the
+// namespace's configured eviction policy and quota, if any, apply to it.
+func (em *EvictionManager) BindNamespace(prefix string, namespaceID NamespaceID) {
+	if policy, ok := em.nsPolicyByPrefix[prefix]; ok {
+		em.nsPoliciesMu.Lock()
+		em.nsPolicies[namespaceID] = policy
+		em.nsPoliciesMu.Unlock()
+	}
+	if quota, ok := em.nsQuotaByPrefix[prefix]; ok {
+		em.nsQuotasMu.Lock()
+		em.nsQuotas[namespaceID] = quota
+		em.nsQuotasMu.Unlock()
+	}
+}
+
+// policyFor returns the eviction policy governing a namespace's objects
+// in a storage directory.
+func (em *EvictionManager) policyFor(storageID StorageID, namespaceID NamespaceID) EvictionPolicy {
+	em.nsPoliciesMu.RLock()
+	policy, ok := em.nsPolicies[namespaceID]
+	em.nsPoliciesMu.RUnlock()
+	if ok {
+		return policy
+	}
+	if policy, ok := em.dirPolicies[storageID]; ok {
+		return policy
+	}
+	return em.defaultPolicy
+}
+
+// addPolicyStats adds delta to the in-memory counters of a policy.
+func (em *EvictionManager) addPolicyStats(policy string, delta EvictionPolicyStats) {
+	em.policyStatsMu.Lock()
+	defer em.policyStatsMu.Unlock()
+	stats := em.policyStats[policy]
+	stats.Hits += delta.Hits
+	stats.Misses += delta.Misses
+	stats.EvictedObjects += delta.EvictedObjects
+	stats.EvictedBytes += delta.EvictedBytes
+	em.policyStats[policy] = stats
+}
+
+// flushPolicyStats moves the in-memory policy counters into the database.
+// Counters that fail to persist are kept for the next flush.
+func (em *EvictionManager) flushPolicyStats() {
+	em.policyStatsMu.Lock()
+	pending := em.policyStats
+	em.policyStats = make(map[string]EvictionPolicyStats, len(pending))
+	em.policyStatsMu.Unlock()
+
+	for policy, delta := range pending {
+		if err := em.db.AddPolicyStats(policy, delta); err != nil {
+			log.Debugf("Failed to persist eviction policy stats for %s: %v", policy, err)
+			em.addPolicyStats(policy, delta)
+		}
+	}
+}
+
+// GetPolicyStats returns the cumulative counters of every eviction policy
+// that has recorded activity, including counts not yet flushed to the
+// database.
+func (em *EvictionManager) GetPolicyStats() (map[string]EvictionPolicyStats, error) {
+	em.flushPolicyStats()
+	return em.db.GetPolicyStats()
 }
 
 // GetStats returns eviction manager statistics
 func (em *EvictionManager) GetStats() EvictionStats {
 	usage, _ := em.db.GetAllUsage()
 
+	policyStats, err := em.GetPolicyStats()
+	if err != nil {
+		log.Debugf("Failed to read eviction policy stats: %v", err)
+	}
+
 	dirStats := make(map[StorageID]DirEvictionStats, len(em.dirLimits))
 	for id, limits := range em.dirLimits {
+		policy := em.defaultPolicy
+		if p, ok := em.dirPolicies[id]; ok {
+			policy = p
+		}
 		dirStats[id] = DirEvictionStats{
 			MaxSize:   limits.maxSize,
 			HighWater: limits.highWater,
 			LowWater:  limits.lowWater,
+			Policy:    policy.Name(),
 		}
 	}
 
@@ -544,6 +795,7 @@ func (em *EvictionManager) GetStats() EvictionStats {
 		TotalUsage:     em.GetTotalUsage(),
 		DirStats:       dirStats,
 		NamespaceUsage: usage,
+		PolicyStats:    policyStats,
 	}
 }
 
@@ -552,6 +804,7 @@ type EvictionStats struct {
 	TotalUsage     uint64
 	DirStats       map[StorageID]DirEvictionStats
 	NamespaceUsage map[StorageUsageKey]int64
+	PolicyStats    map[string]EvictionPolicyStats
 }
 
 // DirEvictionStats contains per-directory eviction statistics
@@ -559,6 +812,7 @@ type DirEvictionStats struct {
 	MaxSize   uint64
 	HighWater uint64
 	LowWater  uint64
+	Policy    string // Eviction policy of the directory (namespaces may override)
 }
 
 // HasSpace returns true if there's room for more data in at least one
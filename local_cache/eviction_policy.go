@@ -0,0 +1,310 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package local_cache
+
+// Pluggable eviction policies
+//
+// Eviction policies decide which of a namespace's objects leave a storage
+// directory first once the EvictionManager has picked the namespace to
+// evict from.  LRU walks the on-disk LRU index directly; every other
+// policy ranks a window of the least-recently-used candidates (see
+// CacheDB.EvictionCandidates) using the access counts, sizes, and
+// freshness recorded in each object's metadata.
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Names of the built-in eviction policies.
+const (
+	EvictionPolicyLRU  = "lru"
+	EvictionPolicyLFU  = "lfu"
+	EvictionPolicyGDSF = "gdsf"
+	EvictionPolicyARC  = "arc"
+	EvictionPolicyTTL  = "ttl"
+)
+
+// policyCandidateWindow bounds the number of least-recently-used objects
+// a non-LRU policy ranks in one eviction batch.  Objects outside the
+// window are considered on later batches once the window drains.
+const policyCandidateWindow = 4096
+
+// arcGhostCapacity bounds the number of recently evicted objects the ARC
+// policy remembers for adapting its recency/frequency balance.
+const arcGhostCapacity = 8192
+
+// EvictionCandidate summarizes an object for ranking by an EvictionPolicy.
+type EvictionCandidate struct {
+	InstanceHash InstanceHash
+	Size         int64     // Content length in bytes
+	LastAccess   time.Time // Last recorded access
+	AccessCount  uint32    // Number of recorded accesses
+	Expires      time.Time // End of the freshness lifetime; zero if unknown
+}
+
+func newEvictionCandidate(instanceHash InstanceHash, meta *CacheMetadata) EvictionCandidate {
+	expires := meta.Expires
+	if expires.IsZero() {
+		expires = meta.ComputeExpires()
+	}
+	return EvictionCandidate{
+		InstanceHash: instanceHash,
+		Size:         meta.ContentLength,
+		LastAccess:   meta.LastAccessTime,
+		AccessCount:  meta.AccessCount,
+		Expires:      expires,
+	}
+}
+
+// EvictionPolicy ranks eviction candidates.  A single policy instance may
+// serve several storage directories and namespaces concurrently, so any
+// adaptive state must be keyed by storage ID and synchronized.
+type EvictionPolicy interface {
+	// Name returns the policy's configuration name.
+	Name() string
+	// Order sorts candidates, which arrive least recently used first,
+	// into eviction order for the given storage directory.
+	Order(storageID StorageID, candidates []EvictionCandidate)
+	// Evicted reports candidates that were evicted from storageID.
+	Evicted(storageID StorageID, evicted []EvictionCandidate)
+	// Missed reports a cache miss for an object that will be stored in
+	// storageID.
+	Missed(storageID StorageID, instanceHash InstanceHash)
+}
+
+// NewEvictionPolicy returns a new instance of the named built-in policy.
+// Names are case-insensitive; the empty string selects LRU.
+func NewEvictionPolicy(name string) (EvictionPolicy, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", EvictionPolicyLRU:
+		return &lruPolicy{}, nil
+	case EvictionPolicyLFU:
+		return &lfuPolicy{}, nil
+	case EvictionPolicyGDSF:
+		return &gdsfPolicy{}, nil
+	case EvictionPolicyARC:
+		return newARCPolicy(), nil
+	case EvictionPolicyTTL:
+		return &ttlPolicy{}, nil
+	default:
+		return nil, errors.Errorf("unknown eviction policy %q; expected one of %s, %s, %s, %s, %s",
+			name, EvictionPolicyLRU, EvictionPolicyLFU, EvictionPolicyGDSF, EvictionPolicyARC, EvictionPolicyTTL)
+	}
+}
+
+// lruPolicy evicts the least recently used objects first.  The eviction
+// manager bypasses Order for this policy and walks the LRU index.
+type lruPolicy struct{}
+
+func (p *lruPolicy) Name() string { return EvictionPolicyLRU }
+
+func (p *lruPolicy) Order(_ StorageID, candidates []EvictionCandidate) {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].LastAccess.Before(candidates[j].LastAccess)
+	})
+}
+
+func (p *lruPolicy) Evicted(StorageID, []EvictionCandidate) {}
+
+func (p *lruPolicy) Missed(StorageID, InstanceHash) {}
+
+// lfuPolicy evicts the least frequently used objects first, breaking ties
+// by recency.
+type lfuPolicy struct{}
+
+func (p *lfuPolicy) Name() string { return EvictionPolicyLFU }
+
+func (p *lfuPolicy) Order(_ StorageID, candidates []EvictionCandidate) {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].AccessCount < candidates[j].AccessCount
+	})
+}
+
+func (p *lfuPolicy) Evicted(StorageID, []EvictionCandidate) {}
+
+func (p *lfuPolicy) Missed(StorageID, InstanceHash) {}
+
+// gdsfPolicy is a Greedy-Dual-Size-Frequency variant with uniform cost:
+// an object's priority is frequency/size, and the lowest priority is
+// evicted first, so many small, popular objects are favored over a few
+// large ones.  Classic GDSF ages priorities with an inflation value; here
+// aging comes from ranking only the least-recently-used window, which
+// objects that stop being accessed eventually drift into.
+type gdsfPolicy struct{}
+
+func (p *gdsfPolicy) Name() string { return EvictionPolicyGDSF }
+
+func gdsfPriority(c *EvictionCandidate) float64 {
+	freq := float64(c.AccessCount)
+	if freq < 1 {
+		freq = 1
+	}
+	size := float64(c.Size)
+	if size < 1 {
+		size = 1
+	}
+	return freq / size
+}
+
+func (p *gdsfPolicy) Order(_ StorageID, candidates []EvictionCandidate) {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return gdsfPriority(&candidates[i]) < gdsfPriority(&candidates[j])
+	})
+}
+
+func (p *gdsfPolicy) Evicted(StorageID, []EvictionCandidate) {}
+
+func (p *gdsfPolicy) Missed(StorageID, InstanceHash) {}
+
+// arcPolicy is an adaptation of the Adaptive Replacement Cache to the
+// persistent cache.  Candidates accessed once form the recency list and
+// the rest the frequency list; each list is evicted in LRU order.  The
+// target share of the recency list adapts per directory: a miss on an
+// object recently evicted from the recency list grows the target, and a
+// miss on one evicted from the frequency list shrinks it.
+type arcPolicy struct {
+	mu     sync.Mutex
+	target map[StorageID]float64 // Target recency share of candidate bytes, 0..1
+
+	// Ghost entries remember which list recently evicted objects came
+	// from.  ghostOrder is a ring buffer bounding the map's size; each
+	// ghost records its slot so a stale slot never drops a newer ghost.
+	ghosts     map[InstanceHash]arcGhost
+	ghostOrder []InstanceHash
+	ghostNext  int
+}
+
+type arcGhost struct {
+	storageID StorageID
+	frequent  bool
+	slot      int // Index of the entry in ghostOrder
+}
+
+// arcAdaptStep is how far one ghost hit moves the recency target.
+const arcAdaptStep = 0.05
+
+func newARCPolicy() *arcPolicy {
+	return &arcPolicy{
+		target:     make(map[StorageID]float64),
+		ghosts:     make(map[InstanceHash]arcGhost),
+		ghostOrder: make([]InstanceHash, arcGhostCapacity),
+	}
+}
+
+func (p *arcPolicy) Name() string { return EvictionPolicyARC }
+
+func (p *arcPolicy) targetFor(storageID StorageID) float64 {
+	if t, ok := p.target[storageID]; ok {
+		return t
+	}
+	return 0.5
+}
+
+func (p *arcPolicy) Order(storageID StorageID, candidates []EvictionCandidate) {
+	p.mu.Lock()
+	target := p.targetFor(storageID)
+	p.mu.Unlock()
+
+	var recentBytes, totalBytes int64
+	for _, c := range candidates {
+		totalBytes += c.Size
+		if c.AccessCount <= 1 {
+			recentBytes += c.Size
+		}
+	}
+	// Evict from whichever list exceeds its target share first.
+	recentFirst := totalBytes > 0 && float64(recentBytes) > target*float64(totalBytes)
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		ri, rj := candidates[i].AccessCount <= 1, candidates[j].AccessCount <= 1
+		if ri == rj {
+			return false
+		}
+		return ri == recentFirst
+	})
+}
+
+func (p *arcPolicy) Evicted(storageID StorageID, evicted []EvictionCandidate) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, c := range evicted {
+		if old := p.ghostOrder[p.ghostNext]; old != "" {
+			if ghost, ok := p.ghosts[old]; ok && ghost.slot == p.ghostNext {
+				delete(p.ghosts, old)
+			}
+		}
+		// An object evicted again moves to the newest slot; its old slot
+		// is left to expire without touching the new ghost.
+		p.ghostOrder[p.ghostNext] = c.InstanceHash
+		p.ghosts[c.InstanceHash] = arcGhost{storageID: storageID, frequent: c.AccessCount > 1, slot: p.ghostNext}
+		p.ghostNext = (p.ghostNext + 1) % len(p.ghostOrder)
+	}
+}
+
+func (p *arcPolicy) Missed(_ StorageID, instanceHash InstanceHash) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	ghost, ok := p.ghosts[instanceHash]
+	if !ok {
+		return
+	}
+	delete(p.ghosts, instanceHash)
+	p.ghostOrder[ghost.slot] = ""
+
+	t := p.targetFor(ghost.storageID)
+	if ghost.frequent {
+		t -= arcAdaptStep
+	} else {
+		t += arcAdaptStep
+	}
+	p.target[ghost.storageID] = min(max(t, 0), 1)
+}
+
+// ttlPolicy evicts objects whose freshness lifetime has ended first,
+// soonest-expired first, then falls back to LRU order.  Stale objects
+// would need revalidation with the origin before being served anyway.
+type ttlPolicy struct{}
+
+func (p *ttlPolicy) Name() string { return EvictionPolicyTTL }
+
+func (p *ttlPolicy) Order(_ StorageID, candidates []EvictionCandidate) {
+	now := time.Now()
+	expired := func(c *EvictionCandidate) bool {
+		return !c.Expires.IsZero() && c.Expires.Before(now)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		ei, ej := expired(&candidates[i]), expired(&candidates[j])
+		if ei != ej {
+			return ei
+		}
+		if ei {
+			return candidates[i].Expires.Before(candidates[j].Expires)
+		}
+		return false
+	})
+}
+
+func (p *ttlPolicy) Evicted(StorageID, []EvictionCandidate) {}
+
+func (p *ttlPolicy) Missed(StorageID, InstanceHash) {}
@@ -0,0 +1,223 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package local_cache
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func candidateHashes(candidates []EvictionCandidate) []InstanceHash {
+	hashes := make([]InstanceHash, len(candidates))
+	for i, c := range candidates {
+		hashes[i] = c.InstanceHash
+	}
+	return hashes
+}
+
+func TestNewEvictionPolicy(t *testing.T) {
+	for _, name := range []string{"", "LRU", "lfu", " gdsf ", "arc", "ttl"} {
+		p, err := NewEvictionPolicy(name)
+		require.NoError(t, err, name)
+		assert.NotEmpty(t, p.Name())
+	}
+	_, err := NewEvictionPolicy("mru")
+	assert.Error(t, err)
+}
+
+func TestEvictionPolicyOrder(t *testing.T) {
+	now := time.Now()
+	// Candidates arrive least recently used first.
+	base := []EvictionCandidate{
+		{InstanceHash: "old-big-hot", Size: 1 << 30, AccessCount: 50, LastAccess: now.Add(-3 * time.Hour), Expires: now.Add(time.Hour)},
+		{InstanceHash: "mid-small-cold", Size: 1 << 10, AccessCount: 1, LastAccess: now.Add(-2 * time.Hour), Expires: now.Add(-time.Minute)},
+		{InstanceHash: "new-small-warm", Size: 1 << 10, AccessCount: 5, LastAccess: now.Add(-time.Hour), Expires: now.Add(-time.Hour)},
+	}
+
+	tests := []struct {
+		policy string
+		want   []InstanceHash
+	}{
+		{EvictionPolicyLRU, []InstanceHash{"old-big-hot", "mid-small-cold", "new-small-warm"}},
+		{EvictionPolicyLFU, []InstanceHash{"mid-small-cold", "new-small-warm", "old-big-hot"}},
+		{EvictionPolicyGDSF, []InstanceHash{"old-big-hot", "mid-small-cold", "new-small-warm"}},
+		{EvictionPolicyTTL, []InstanceHash{"new-small-warm", "mid-small-cold", "old-big-hot"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.policy, func(t *testing.T) {
+			p, err := NewEvictionPolicy(tc.policy)
+			require.NoError(t, err)
+			candidates := append([]EvictionCandidate(nil), base...)
+			p.Order(StorageIDFirstDisk, candidates)
+			assert.Equal(t, tc.want, candidateHashes(candidates))
+		})
+	}
+}
+
+func TestARCPolicyAdapts(t *testing.T) {
+	p := newARCPolicy()
+	candidates := func() []EvictionCandidate {
+		return []EvictionCandidate{
+			{InstanceHash: "frequent", Size: 100, AccessCount: 4},
+			{InstanceHash: "recent", Size: 100, AccessCount: 1},
+		}
+	}
+
+	// With the default even split neither list exceeds its share, so the
+	// frequency list goes first in LRU order.
+	c := candidates()
+	p.Order(StorageIDFirstDisk, c)
+	assert.Equal(t, InstanceHash("frequent"), c[0].InstanceHash)
+
+	// Misses on objects evicted from the frequency list shrink the
+	// recency target until recently-added objects are evicted first.
+	for i := 0; i < 5; i++ {
+		hash := InstanceHash(fmt.Sprintf("ghost-%d", i))
+		p.Evicted(StorageIDFirstDisk, []EvictionCandidate{{InstanceHash: hash, AccessCount: 3}})
+		p.Missed(StorageIDFirstDisk, hash)
+	}
+	c = candidates()
+	p.Order(StorageIDFirstDisk, c)
+	assert.Equal(t, InstanceHash("recent"), c[0].InstanceHash)
+
+	// Other directories keep their own target.
+	c = candidates()
+	p.Order(StorageIDFirstDisk+1, c)
+	assert.Equal(t, InstanceHash("frequent"), c[0].InstanceHash)
+}
+
+func TestARCPolicyGhostSlots(t *testing.T) {
+	p := newARCPolicy()
+	p.ghostOrder = make([]InstanceHash, 3)
+	evict := func(hash InstanceHash) {
+		p.Evicted(StorageIDFirstDisk, []EvictionCandidate{{InstanceHash: hash, AccessCount: 1}})
+	}
+
+	// A ghost consumed by a miss frees its slot; when the object is
+	// evicted again, wrapping around the ring must not drop the new ghost.
+	evict("a")
+	p.Missed(StorageIDFirstDisk, "a")
+	evict("b")
+	evict("a")
+	evict("c")
+	assert.Contains(t, p.ghosts, InstanceHash("a"))
+	assert.Contains(t, p.ghosts, InstanceHash("b"))
+	assert.Contains(t, p.ghosts, InstanceHash("c"))
+
+	// Likewise for an object evicted twice without a miss in between.
+	p = newARCPolicy()
+	p.ghostOrder = make([]InstanceHash, 3)
+	evict("a")
+	evict("a")
+	evict("b")
+	evict("c")
+	assert.Contains(t, p.ghosts, InstanceHash("a"))
+	assert.Len(t, p.ghosts, 3)
+
+	// The oldest ghost still goes once the ring is full.
+	evict("d")
+	assert.NotContains(t, p.ghosts, InstanceHash("a"))
+	assert.Len(t, p.ghosts, 3)
+}
+
+func TestEvictByPolicy(t *testing.T) {
+	db, storage, sid := setupDedupStorage(t)
+
+	// Object 1 is the least recently used but is accessed repeatedly;
+	// LFU should evict object 2 first.
+	h1 := createDedupObject(t, db, storage, 1, sid, 1, dedupTestData(1000, 1), false)
+	h2 := createDedupObject(t, db, storage, 2, sid, 1, dedupTestData(1000, 2), false)
+	for i := 0; i < 5; i++ {
+		written, err := db.RecordAccess(h1, 1, 0)
+		require.NoError(t, err)
+		require.True(t, written)
+	}
+	// Touch object 2 so object 1 is the least recently used.
+	_, err := db.RecordAccess(h2, 1, 0)
+	require.NoError(t, err)
+	meta1, err := storage.GetMetadata(h1)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(6), meta1.AccessCount)
+
+	lfu, err := NewEvictionPolicy(EvictionPolicyLFU)
+	require.NoError(t, err)
+	em := NewEvictionManager(db, storage, EvictionConfig{
+		DirConfigs: map[StorageID]EvictionDirConfig{
+			sid: {MaxSize: 1 << 20, Policy: lfu},
+		},
+	})
+
+	_, count, _, err := em.evictFromNamespace(log.NewEntry(log.StandardLogger()), sid, 1, 1, 0)
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+
+	meta1, err = storage.GetMetadata(h1)
+	require.NoError(t, err)
+	assert.NotNil(t, meta1, "frequently used object should survive")
+	meta2, err := storage.GetMetadata(h2)
+	require.NoError(t, err)
+	assert.Nil(t, meta2, "least frequently used object should be evicted")
+
+	stats, err := em.GetPolicyStats()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), stats[EvictionPolicyLFU].EvictedObjects)
+}
+
+func TestPolicyStatsRecording(t *testing.T) {
+	db, storage, sid := setupDedupStorage(t)
+	h := createDedupObject(t, db, storage, 1, sid, 7, dedupTestData(100, 1), false)
+	meta, err := storage.GetMetadata(h)
+	require.NoError(t, err)
+
+	gdsf, err := NewEvictionPolicy(EvictionPolicyGDSF)
+	require.NoError(t, err)
+	em := NewEvictionManager(db, storage, EvictionConfig{
+		DirConfigs: map[StorageID]EvictionDirConfig{
+			sid: {MaxSize: 1 << 20},
+		},
+		NamespacePolicies: map[string]EvictionPolicy{"/ligo": gdsf},
+	})
+	em.BindNamespace("/ligo", 7)
+
+	require.NoError(t, em.RecordAccess(h, meta, false))
+	require.NoError(t, em.RecordAccess(h, meta, true))
+	require.NoError(t, em.RecordAccess(h, meta, true))
+
+	stats, err := em.GetPolicyStats()
+	require.NoError(t, err)
+	require.Contains(t, stats, EvictionPolicyGDSF)
+	assert.Equal(t, uint64(2), stats[EvictionPolicyGDSF].Hits)
+	assert.Equal(t, uint64(1), stats[EvictionPolicyGDSF].Misses)
+	assert.InDelta(t, 2.0/3.0, stats[EvictionPolicyGDSF].HitRatio(), 1e-9)
+	assert.NotContains(t, stats, EvictionPolicyLRU)
+
+	// The object was just created, so the writes were debounced and the
+	// accesses are carried forward to the next write.
+	meta, err = storage.GetMetadata(h)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1), meta.AccessCount)
+	em.accessMu.Lock()
+	assert.Equal(t, uint32(3), em.pendingHits[h])
+	em.accessMu.Unlock()
+}
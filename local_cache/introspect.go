@@ -67,6 +67,8 @@ type ObjectDetails struct {
 	Checksums     []ChecksumInfo    `json:"checksums,omitempty"`
 	BlockSummary  *BlockSummary     `json:"block_summary,omitempty"` // nil for inline storage
 	ChunkSummary  *ChunkInfoSummary `json:"chunk_info,omitempty"`    // nil for non-chunked
+	Compression   string            `json:"compression,omitempty"`   // Codec of compressed objects
+	StoredBytes   int64             `json:"stored_bytes,omitempty"`  // On-disk size of compressed objects
 }
 
 // ChecksumInfo describes a stored checksum.
@@ -101,13 +103,15 @@ type ChecksumStatus struct {
 
 // CacheStats contains aggregate size statistics about the cache.
 type CacheStats struct {
-	TotalInlineBytes     int64                       `json:"total_inline_bytes"`
-	TotalMetadataEntries int64                       `json:"total_metadata_entries"`
-	TotalBytesMetadata   int64                       `json:"total_bytes_metadata"`     // Sum of ContentLength from metadata entries
-	UsageCounters        map[string]int64            `json:"usage_counters,omitempty"` // Pre-computed usage from u: prefix keys
-	StorageBreakdown     map[string]*StorageDirStats `json:"storage_breakdown,omitempty"`
-	DirPaths             map[uint8]string            `json:"dir_paths,omitempty"`       // StorageID → directory path
-	NamespaceNames       map[uint32]string           `json:"namespace_names,omitempty"` // NamespaceID → prefix string
+	TotalInlineBytes     int64                          `json:"total_inline_bytes"`
+	TotalMetadataEntries int64                          `json:"total_metadata_entries"`
+	TotalBytesMetadata   int64                          `json:"total_bytes_metadata"`     // Sum of ContentLength from metadata entries
+	UsageCounters        map[string]int64               `json:"usage_counters,omitempty"` // Pre-computed usage from u: prefix keys
+	StorageBreakdown     map[string]*StorageDirStats    `json:"storage_breakdown,omitempty"`
+	DirPaths             map[uint8]string               `json:"dir_paths,omitempty"`         // StorageID → directory path
+	NamespaceNames       map[uint32]string              `json:"namespace_names,omitempty"`   // NamespaceID → prefix string
+	EvictionPolicies     map[string]EvictionPolicyStats `json:"eviction_policies,omitempty"` // Policy name → hit/eviction counters
+	Compression          *CompressionStats              `json:"compression,omitempty"`       // nil if no object is compressed
 }
 
 // StorageDirStats holds per-storage-directory statistics.
@@ -121,6 +125,35 @@ type StorageDirStats struct {
 	OnDiskBytes int64 `json:"on_disk_bytes"` // Actual bytes on disk (content + per-block MAC overhead)
 }
 
+// CompressionStats summarizes the space saved by compressed objects.
+type CompressionStats struct {
+	Objects      int64   `json:"objects"`       // Number of compressed objects
+	ContentBytes int64   `json:"content_bytes"` // Sum of ContentLength of compressed objects
+	StoredBytes  int64   `json:"stored_bytes"`  // Bytes their data files occupy on disk
+	Ratio        float64 `json:"ratio"`         // ContentBytes / StoredBytes
+}
+
+// addOnDisk accounts for an on-disk object in its directory's statistics
+// and, if it is compressed, in the compression statistics.
+func (s *CacheStats) addOnDisk(ds *StorageDirStats, meta *CacheMetadata) {
+	ds.OnDiskCount++
+	if !meta.IsCompressed() {
+		ds.OnDiskBytes += CalculateFileSize(meta.ContentLength)
+		return
+	}
+	stored := meta.StoredSize()
+	ds.OnDiskBytes += stored
+	if s.Compression == nil {
+		s.Compression = &CompressionStats{}
+	}
+	s.Compression.Objects++
+	s.Compression.ContentBytes += meta.ContentLength
+	s.Compression.StoredBytes += stored
+	if s.Compression.StoredBytes > 0 {
+		s.Compression.Ratio = float64(s.Compression.ContentBytes) / float64(s.Compression.StoredBytes)
+	}
+}
+
 // DiskUsageResult contains the result of an expensive disk walk.
 type DiskUsageResult struct {
 	TotalBytesOnDisk int64                   `json:"total_bytes_on_disk"`
@@ -329,6 +362,10 @@ func (api *IntrospectAPIOpen) GetObjectDetails(instanceHash string) (*ObjectDeta
 		StorageID:     uint8(meta.StorageID),
 		LastValidated: meta.LastValidated,
 	}
+	if meta.IsCompressed() {
+		details.Compression = meta.Compression.String()
+		details.StoredBytes = meta.StoredSize()
+	}
 
 	// Extract cache-control as string
 	cc := meta.GetCacheDirectives()
@@ -643,8 +680,7 @@ func (api *IntrospectAPIOpen) GetCacheStats() (*CacheStats, error) {
 			ds.InlineCount++
 			ds.InlineBytes += meta.ContentLength
 		} else {
-			ds.OnDiskCount++
-			ds.OnDiskBytes += CalculateFileSize(meta.ContentLength)
+			stats.addOnDisk(ds, meta)
 		}
 		return nil
 	})
@@ -682,6 +718,9 @@ func (api *IntrospectAPIOpen) GetCacheStats() (*CacheStats, error) {
 			stats.NamespaceNames[uint32(id)] = prefix
 		}
 	}
+	if policyStats, err := api.db.GetPolicyStats(); err == nil && len(policyStats) > 0 {
+		stats.EvictionPolicies = policyStats
+	}
 
 	return stats, nil
 }
@@ -809,6 +848,8 @@ func checksumTypeString(ct ChecksumType) string {
 		return "CRC32"
 	case ChecksumCRC32C:
 		return "CRC32C"
+	case ChecksumAdler32:
+		return "ADLER32"
 	default:
 		return fmt.Sprintf("unknown(%d)", ct)
 	}
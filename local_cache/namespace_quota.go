@@ -0,0 +1,245 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package local_cache
+
+// Per-namespace quotas and reservations
+//
+// A namespace's quota caps the bytes its objects may occupy, summed over
+// every storage directory; it is enforced when a new disk object is
+// admitted (see decisionWriter.SetDiskMode) by first evicting the
+// namespace's own objects.  A reservation guarantees a namespace a
+// minimum share: watermark-driven eviction never takes a namespace below
+// its reservation, so one busy namespace cannot flush another's working
+// set.
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/pelicanplatform/pelican/param"
+	"github.com/pelicanplatform/pelican/utils"
+)
+
+// ErrNamespaceQuotaExceeded is returned when an object cannot be admitted
+// to the cache without exceeding its namespace's quota.
+var ErrNamespaceQuotaExceeded = errors.New("namespace quota exceeded")
+
+// NamespaceQuota holds the space limits of one namespace, in on-disk
+// bytes summed over all storage directories.
+type NamespaceQuota struct {
+	MaxBytes      uint64 // Most space the namespace may use (0 = unlimited)
+	ReservedBytes uint64 // Space protected from eviction (0 = none)
+}
+
+// ParseNamespaceQuotas reads a namespace quota map (LocalCache.NamespaceQuotas
+// or Cache.NamespaceQuotas) keyed by namespace prefix.  Sizes may be given
+// as strings like "500GB" or as byte counts.
+//
+// Example YAML:
+//
+//	NamespaceQuotas:
+//	  /ligo:
+//	    MaxSize: 2TB
+//	    Reservation: 500GB
+//
+// Returns nil (not an error) when the key is unset or empty.
+func ParseNamespaceQuotas(p param.ObjectParam) (map[string]NamespaceQuota, error) {
+	if !p.IsSet() {
+		return nil, nil
+	}
+	var raw map[string]struct {
+		MaxSize     string
+		Reservation string
+	}
+	if err := p.Unmarshal(&raw); err != nil {
+		return nil, fmt.Errorf("%s: %w", p.GetName(), err)
+	}
+	if len(raw) == 0 {
+		return nil, nil
+	}
+
+	quotas := make(map[string]NamespaceQuota, len(raw))
+	for prefix, entry := range raw {
+		var q NamespaceQuota
+		var err error
+		if q.MaxBytes, err = parseQuotaSize(entry.MaxSize); err != nil {
+			return nil, fmt.Errorf("%s[%s].MaxSize: %w", p.GetName(), prefix, err)
+		}
+		if q.ReservedBytes, err = parseQuotaSize(entry.Reservation); err != nil {
+			return nil, fmt.Errorf("%s[%s].Reservation: %w", p.GetName(), prefix, err)
+		}
+		if q.MaxBytes > 0 && q.ReservedBytes > q.MaxBytes {
+			return nil, fmt.Errorf("%s[%s]: Reservation (%s) exceeds MaxSize (%s)", p.GetName(), prefix,
+				utils.HumanBytes(q.ReservedBytes), utils.HumanBytes(q.MaxBytes))
+		}
+		quotas[extractNamespacePrefix(prefix)] = q
+	}
+	return quotas, nil
+}
+
+func parseQuotaSize(s string) (uint64, error) {
+	if s == "" || s == "0" {
+		return 0, nil
+	}
+	return utils.ParseBytes(s)
+}
+
+// quotaFor returns the quota bound to a namespace, if any.
+func (em *EvictionManager) quotaFor(namespaceID NamespaceID) (NamespaceQuota, bool) {
+	em.nsQuotasMu.RLock()
+	defer em.nsQuotasMu.RUnlock()
+	q, ok := em.nsQuotas[namespaceID]
+	return q, ok
+}
+
+// namespaceUsage returns a namespace's usage summed over all storage
+// directories.
+func (em *EvictionManager) namespaceUsage(namespaceID NamespaceID) int64 {
+	var total int64
+	for _, sid := range em.dirIDs {
+		usage, err := em.db.GetUsage(sid, namespaceID)
+		if err != nil {
+			log.Warnf("Failed to get usage for storage %d namespace %d: %v", sid, namespaceID, err)
+			continue
+		}
+		total += usage
+	}
+	return total
+}
+
+// FitsQuota reports whether an object of the given content length could
+// ever be cached within its namespace's quota.  Objects that do not fit
+// should be served without being stored.
+func (em *EvictionManager) FitsQuota(namespaceID NamespaceID, contentLength int64) bool {
+	q, ok := em.quotaFor(namespaceID)
+	if !ok || q.MaxBytes == 0 || contentLength <= 0 {
+		return true
+	}
+	return uint64(CalculateFileSize(contentLength)) <= q.MaxBytes
+}
+
+// AdmitObject makes room within a namespace's quota for a new disk object
+// of the given content length (-1 if unknown), evicting the namespace's
+// own objects if needed.  Returns an error wrapping
+// ErrNamespaceQuotaExceeded if the object cannot fit.
+func (em *EvictionManager) AdmitObject(namespaceID NamespaceID, contentLength int64) error {
+	q, ok := em.quotaFor(namespaceID)
+	if !ok || q.MaxBytes == 0 {
+		return nil
+	}
+	var size int64
+	if contentLength > 0 {
+		size = CalculateFileSize(contentLength)
+	}
+	if uint64(size) > q.MaxBytes {
+		return errors.Wrapf(ErrNamespaceQuotaExceeded, "object of %s is larger than the %s quota of namespace %d",
+			utils.HumanBytes(uint64(size)), utils.HumanBytes(q.MaxBytes), namespaceID)
+	}
+
+	excess := em.namespaceUsage(namespaceID) + size - int64(q.MaxBytes)
+	if excess <= 0 {
+		return nil
+	}
+	rl := log.WithField("namespaceID", namespaceID)
+	if freed, _ := em.evictNamespaceExcess(rl, namespaceID, excess); int64(freed) < excess {
+		return errors.Wrapf(ErrNamespaceQuotaExceeded, "could only free %s of the %s needed in namespace %d",
+			utils.HumanBytes(freed), utils.HumanBytes(uint64(excess)), namespaceID)
+	}
+	return nil
+}
+
+// evictNamespaceExcess evicts up to needBytes of a namespace's objects,
+// starting with the storage directory where the namespace uses the most
+// space.  Returns the bytes freed and the number of objects evicted.
+func (em *EvictionManager) evictNamespaceExcess(rl *log.Entry, namespaceID NamespaceID, needBytes int64) (uint64, int) {
+	type dirShare struct {
+		sid   StorageID
+		usage int64
+	}
+	shares := make([]dirShare, 0, len(em.dirIDs))
+	for _, sid := range em.dirIDs {
+		if usage, err := em.db.GetUsage(sid, namespaceID); err == nil && usage > 0 {
+			shares = append(shares, dirShare{sid, usage})
+		}
+	}
+	sort.Slice(shares, func(i, j int) bool { return shares[i].usage > shares[j].usage })
+
+	var totalFreed uint64
+	var totalCount int
+	for _, share := range shares {
+		remaining := needBytes - int64(totalFreed)
+		if remaining <= 0 {
+			break
+		}
+		freed, count, _, err := em.evictFromNamespace(rl, share.sid, namespaceID, 0, remaining)
+		if err != nil {
+			rl.WithField("storageID", share.sid).WithError(err).Warn("Error evicting over-quota namespace")
+		}
+		totalFreed += freed
+		totalCount += count
+	}
+	return totalFreed, totalCount
+}
+
+// enforceQuotas evicts from every namespace whose usage exceeds its
+// quota, e.g. after the quota was lowered or an object of unknown size
+// outgrew it.
+func (em *EvictionManager) enforceQuotas(rl *log.Entry) (uint64, int) {
+	em.nsQuotasMu.RLock()
+	quotas := make(map[NamespaceID]NamespaceQuota, len(em.nsQuotas))
+	for ns, q := range em.nsQuotas {
+		quotas[ns] = q
+	}
+	em.nsQuotasMu.RUnlock()
+
+	var totalFreed uint64
+	var totalCount int
+	for ns, q := range quotas {
+		if q.MaxBytes == 0 {
+			continue
+		}
+		excess := em.namespaceUsage(ns) - int64(q.MaxBytes)
+		if excess <= 0 {
+			continue
+		}
+		rl.WithFields(log.Fields{
+			"namespaceID": ns,
+			"quota":       utils.HumanBytes(q.MaxBytes),
+			"needToFree":  utils.HumanBytes(uint64(excess)),
+		}).Info("Namespace over quota; evicting")
+		freed, count := em.evictNamespaceExcess(rl, ns, excess)
+		totalFreed += freed
+		totalCount += count
+	}
+	return totalFreed, totalCount
+}
+
+// evictableUsage returns how much of a namespace's usage in one storage
+// directory may be evicted without taking the namespace below its
+// reservation.  totalUsage is the namespace's usage over all directories.
+func (em *EvictionManager) evictableUsage(namespaceID NamespaceID, dirUsage, totalUsage int64) int64 {
+	q, ok := em.quotaFor(namespaceID)
+	if !ok || q.ReservedBytes == 0 {
+		return dirUsage
+	}
+	return min(dirUsage, totalUsage-int64(q.ReservedBytes))
+}
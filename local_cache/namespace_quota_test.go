@@ -0,0 +1,166 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package local_cache
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pelicanplatform/pelican/param"
+)
+
+func TestParseNamespaceQuotas(t *testing.T) {
+	t.Cleanup(func() { _ = param.LocalCache_NamespaceQuotas.Set(nil) })
+
+	require.NoError(t, param.LocalCache_NamespaceQuotas.Set(map[string]interface{}{
+		"/ligo":          map[string]interface{}{"MaxSize": "2GB", "Reservation": "512MB"},
+		"/osg/public":    map[string]interface{}{"maxsize": 4096},
+		"/reserved-only": map[string]interface{}{"Reservation": "1MB"},
+	}))
+	quotas, err := ParseNamespaceQuotas(param.LocalCache_NamespaceQuotas)
+	require.NoError(t, err)
+	assert.Equal(t, NamespaceQuota{MaxBytes: 2 << 30, ReservedBytes: 512 << 20}, quotas["/ligo"])
+	assert.Equal(t, NamespaceQuota{MaxBytes: 4096}, quotas["/osg"])
+	assert.Equal(t, NamespaceQuota{ReservedBytes: 1 << 20}, quotas["/reserved-only"])
+
+	require.NoError(t, param.LocalCache_NamespaceQuotas.Set(map[string]interface{}{
+		"/ligo": map[string]interface{}{"MaxSize": "1MB", "Reservation": "2MB"},
+	}))
+	_, err = ParseNamespaceQuotas(param.LocalCache_NamespaceQuotas)
+	assert.ErrorContains(t, err, "exceeds MaxSize")
+}
+
+func TestAdmitObjectEvictsOwnNamespace(t *testing.T) {
+	db, storage, sid := setupDedupStorage(t)
+
+	const objSize = 10000
+	fileSize := uint64(CalculateFileSize(objSize))
+	h1 := createDedupObject(t, db, storage, 1, sid, 1, dedupTestData(objSize, 1), false)
+	h2 := createDedupObject(t, db, storage, 2, sid, 1, dedupTestData(objSize, 2), false)
+	other := createDedupObject(t, db, storage, 3, sid, 2, dedupTestData(objSize, 3), false)
+
+	em := NewEvictionManager(db, storage, EvictionConfig{
+		DirConfigs: map[StorageID]EvictionDirConfig{
+			sid: {MaxSize: 1 << 30},
+		},
+		NamespaceQuotas: map[string]NamespaceQuota{"/quota": {MaxBytes: 2 * fileSize}},
+	})
+	em.BindNamespace("/quota", 1)
+
+	assert.True(t, em.FitsQuota(1, objSize))
+	assert.False(t, em.FitsQuota(1, 3*objSize))
+	assert.True(t, em.FitsQuota(2, 3*objSize), "namespaces without a quota are unlimited")
+	assert.ErrorIs(t, em.AdmitObject(1, 3*objSize), ErrNamespaceQuotaExceeded)
+
+	// Admitting a third object evicts the namespace's least recently used
+	// object and leaves other namespaces alone.
+	require.NoError(t, em.AdmitObject(1, objSize))
+	meta, err := storage.GetMetadata(h1)
+	require.NoError(t, err)
+	assert.Nil(t, meta)
+	meta, err = storage.GetMetadata(h2)
+	require.NoError(t, err)
+	assert.NotNil(t, meta)
+	meta, err = storage.GetMetadata(other)
+	require.NoError(t, err)
+	assert.NotNil(t, meta)
+	assert.Equal(t, int64(fileSize), em.namespaceUsage(1))
+
+	// Room is left for exactly one more object.
+	require.NoError(t, em.AdmitObject(1, objSize))
+	meta, err = storage.GetMetadata(h2)
+	require.NoError(t, err)
+	assert.NotNil(t, meta)
+}
+
+func TestDiskModeQuotaFailureLeavesWriterUndecided(t *testing.T) {
+	db, storage, sid := setupDedupStorage(t)
+
+	const objSize = 10000
+	em := NewEvictionManager(db, storage, EvictionConfig{
+		DirConfigs: map[StorageID]EvictionDirConfig{
+			sid: {MaxSize: 1 << 30},
+		},
+		NamespaceQuotas: map[string]NamespaceQuota{"/quota": {MaxBytes: uint64(CalculateFileSize(objSize))}},
+	})
+	em.BindNamespace("/quota", 1)
+	pc := &PersistentCache{storage: storage, eviction: em}
+	dw := newDecisionWriter(pc, &persistentDownload{instanceHash: "quota-object", namespaceID: 1})
+
+	_, err := dw.Write([]byte("head"))
+	require.NoError(t, err)
+	assert.ErrorIs(t, dw.SetDiskMode(context.Background(), 3*objSize), ErrNamespaceQuotaExceeded)
+	meta, err := storage.GetMetadata("quota-object")
+	require.NoError(t, err)
+	assert.Nil(t, meta, "nothing should be stored for an object that was not admitted")
+
+	// The writer can still switch to streaming without losing any data.
+	pr, pw := io.Pipe()
+	assert.Equal(t, []byte("head"), dw.SetPipeMode(pw))
+	go func() {
+		_, _ = dw.Write([]byte("tail"))
+		_ = dw.Close()
+	}()
+	rest, err := io.ReadAll(pr)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("tail"), rest)
+}
+
+func TestEvictionRespectsReservations(t *testing.T) {
+	db, storage, sid := setupDedupStorage(t)
+
+	const objSize = 10000
+	fileSize := uint64(CalculateFileSize(objSize))
+	for i := 1; i <= 3; i++ {
+		createDedupObject(t, db, storage, i, sid, 1, dedupTestData(objSize, byte(i)), false)
+	}
+	createDedupObject(t, db, storage, 4, sid, 2, dedupTestData(objSize, 4), false)
+
+	em := NewEvictionManager(db, storage, EvictionConfig{
+		DirConfigs: map[StorageID]EvictionDirConfig{
+			sid: {MaxSize: 1 << 30},
+		},
+		NamespaceQuotas: map[string]NamespaceQuota{"/reserved": {ReservedBytes: 2 * fileSize}},
+	})
+
+	// Unbound, namespace 1 is simply the greediest.
+	key, usage, err := em.findGreediestNamespaceInDir(sid)
+	require.NoError(t, err)
+	assert.Equal(t, NamespaceID(1), key.NamespaceID)
+	assert.Equal(t, int64(3*fileSize), usage)
+
+	// Once its reservation applies, only the unreserved object in
+	// namespace 1 is evictable, and namespace 2 ties with it.
+	em.BindNamespace("/reserved", 1)
+	_, usage, err = em.findGreediestNamespaceInDir(sid)
+	require.NoError(t, err)
+	assert.Equal(t, int64(fileSize), usage)
+
+	_, _, _, err = em.evictFromNamespace(log.NewEntry(log.StandardLogger()), sid, 1, 0, usage)
+	require.NoError(t, err)
+	key, usage, err = em.findGreediestNamespaceInDir(sid)
+	require.NoError(t, err)
+	assert.Equal(t, NamespaceID(2), key.NamespaceID)
+	assert.Equal(t, int64(fileSize), usage)
+}
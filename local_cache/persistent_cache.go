@@ -53,6 +53,8 @@ import (
 
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/sync/errgroup"
 	"golang.org/x/sync/singleflight"
 
@@ -61,10 +63,12 @@ import (
 	"github.com/pelicanplatform/pelican/param"
 	"github.com/pelicanplatform/pelican/server_structs"
 	"github.com/pelicanplatform/pelican/token_scopes"
+	"github.com/pelicanplatform/pelican/tracing"
 	"github.com/pelicanplatform/pelican/utils"
 )
 
-// ErrNoStore is returned when the origin sends Cache-Control: no-store (or private).
+// ErrNoStore is returned when the origin sends Cache-Control: no-store (or private),
+// or when the object is larger than its namespace's quota (see NamespaceQuota).
 // The response data is streamed via persistentDownload.noStoreReader (an io.Pipe)
 // directly to the first caller without persisting to the cache.
 var ErrNoStore = errors.New("origin response has Cache-Control: no-store")
@@ -96,9 +100,11 @@ func clientChecksumsToCache(result *client.TransferResults) []Checksum {
 	}
 
 	algMap := map[client.ChecksumType]ChecksumType{
-		client.AlgMD5:   ChecksumMD5,
-		client.AlgSHA1:  ChecksumSHA1,
-		client.AlgCRC32: ChecksumCRC32,
+		client.AlgMD5:     ChecksumMD5,
+		client.AlgSHA1:    ChecksumSHA1,
+		client.AlgSHA256:  ChecksumSHA256,
+		client.AlgCRC32:   ChecksumCRC32,
+		client.AlgAdler32: ChecksumAdler32,
 		// CRC32C is supported by the client but not yet used in local cache verification.
 		// Store it anyway so it's available for future use.
 		client.AlgCRC32C: ChecksumCRC32C,
@@ -331,6 +337,20 @@ type PersistentCacheConfig struct {
 	HighWaterMarkPercentage int
 	LowWaterMarkPercentage  int
 
+	// EvictionPolicy names the default eviction policy (see
+	// NewEvictionPolicy); empty means use the configured parameter.
+	// StorageDirConfig.EvictionPolicy overrides it per directory.
+	EvictionPolicy string
+
+	// NamespaceEvictionPolicies maps namespace prefixes to eviction
+	// policy names, overriding the directory and default policies.  nil
+	// means use the configured parameter.
+	NamespaceEvictionPolicies map[string]string
+
+	// NamespaceQuotas maps namespace prefixes to their space limits and
+	// reservations.  nil means use the configured parameter.
+	NamespaceQuotas map[string]NamespaceQuota
+
 	// InlineStorageMaxBytes sets the maximum size for objects stored
 	// inline in BadgerDB.  Objects at or below this threshold are stored
 	// inline; larger objects go to disk.  0 means use the default (4096).
@@ -413,6 +433,68 @@ func NewPersistentCache(ctx context.Context, egrp *errgroup.Group, cfg Persisten
 		defaultLWP = 80
 	}
 
+	// Resolve eviction policies.  Each policy name maps to a single
+	// shared instance so that adaptive policies see all of the activity
+	// they govern.
+	policies := make(map[string]EvictionPolicy)
+	policyByName := func(name string) (EvictionPolicy, error) {
+		p, err := NewEvictionPolicy(name)
+		if err != nil {
+			return nil, err
+		}
+		if shared, ok := policies[p.Name()]; ok {
+			return shared, nil
+		}
+		policies[p.Name()] = p
+		return p, nil
+	}
+	defaultPolicyName := cfg.EvictionPolicy
+	nsPolicyNames := cfg.NamespaceEvictionPolicies
+	switch cfg.Mode {
+	case CacheModeServer:
+		if defaultPolicyName == "" {
+			defaultPolicyName = param.Cache_EvictionPolicy.GetString()
+		}
+		if nsPolicyNames == nil && param.Cache_NamespaceEvictionPolicies.IsSet() {
+			if err := param.Cache_NamespaceEvictionPolicies.Unmarshal(&nsPolicyNames); err != nil {
+				return nil, errors.Wrap(err, "failed to parse Cache.NamespaceEvictionPolicies")
+			}
+		}
+	default:
+		if defaultPolicyName == "" {
+			defaultPolicyName = param.LocalCache_EvictionPolicy.GetString()
+		}
+		if nsPolicyNames == nil && param.LocalCache_NamespaceEvictionPolicies.IsSet() {
+			if err := param.LocalCache_NamespaceEvictionPolicies.Unmarshal(&nsPolicyNames); err != nil {
+				return nil, errors.Wrap(err, "failed to parse LocalCache.NamespaceEvictionPolicies")
+			}
+		}
+	}
+	defaultPolicy, err := policyByName(defaultPolicyName)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid default eviction policy")
+	}
+	nsPolicies := make(map[string]EvictionPolicy, len(nsPolicyNames))
+	for prefix, name := range nsPolicyNames {
+		policy, err := policyByName(name)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid eviction policy for namespace %q", prefix)
+		}
+		nsPolicies[extractNamespacePrefix(prefix)] = policy
+	}
+
+	nsQuotas := cfg.NamespaceQuotas
+	if nsQuotas == nil {
+		quotaParam := param.LocalCache_NamespaceQuotas
+		if cfg.Mode == CacheModeServer {
+			quotaParam = param.Cache_NamespaceQuotas
+		}
+		var err error
+		if nsQuotas, err = ParseNamespaceQuotas(quotaParam); err != nil {
+			return nil, err
+		}
+	}
+
 	// Build storage dirs and eviction dir configs.
 	// If StorageDirs is configured, use them.  Otherwise fall back to the
 	// legacy single-dir config (BaseDir + MaxSize).
@@ -507,18 +589,31 @@ func NewPersistentCache(ctx context.Context, egrp *errgroup.Group, cfg Persisten
 			lwp = defaultLWP
 		}
 
+		var dirPolicy EvictionPolicy
+		if sd.EvictionPolicy != "" {
+			dirPolicy, err = policyByName(sd.EvictionPolicy)
+			if err != nil {
+				db.Close()
+				return nil, errors.Wrapf(err, "invalid eviction policy for storage dir %q", sd.Path)
+			}
+		}
+
 		evictionDirCfgs[id] = EvictionDirConfig{
 			MaxSize:             maxSz,
 			HighWaterPercentage: hwp,
 			LowWaterPercentage:  lwp,
 			HighWaterBytes:      defaultHWBytes,
 			LowWaterBytes:       defaultLWBytes,
+			Policy:              dirPolicy,
 		}
 	}
 
 	// Initialize eviction manager
 	eviction := NewEvictionManager(db, storage, EvictionConfig{
-		DirConfigs: evictionDirCfgs,
+		DirConfigs:        evictionDirCfgs,
+		DefaultPolicy:     defaultPolicy,
+		NamespacePolicies: nsPolicies,
+		NamespaceQuotas:   nsQuotas,
 	})
 
 	// Wire chunk allocation to use the eviction manager's weighted
@@ -526,9 +621,22 @@ func NewPersistentCache(ctx context.Context, egrp *errgroup.Group, cfg Persisten
 	// Safe: this runs during single-threaded init, before any downloads.
 	storage.chooseDir = eviction.ChooseDiskStorage
 
-	// Initialize consistency checker
+	// Initialize consistency checker.  Deduplication and compression are
+	// driven by its data scan, which computes the SHA-256 checksums
+	// deduplication relies on and verifies objects before rewriting them.
+	var deduplicate, compress bool
+	switch cfg.Mode {
+	case CacheModeServer:
+		deduplicate = param.Cache_EnableDeduplication.GetBool()
+		compress = param.Cache_EnableCompression.GetBool()
+	default:
+		deduplicate = param.LocalCache_EnableDeduplication.GetBool()
+		compress = param.LocalCache_EnableCompression.GetBool()
+	}
 	consistency := NewConsistencyChecker(db, storage, ConsistencyConfig{
 		MinAgeForCleanup: -1, // Use default grace period
+		Deduplicate:      deduplicate,
+		Compress:         compress,
 	})
 
 	// Get federation info
@@ -603,6 +711,9 @@ func NewPersistentCache(ctx context.Context, egrp *errgroup.Group, cfg Persisten
 	} else if len(nsMap) > 0 {
 		pc.namespaceMap = nsMap
 		pc.nextNamespaceID.Store(uint32(maxID))
+		for prefix, id := range nsMap {
+			eviction.BindNamespace(prefix, id)
+		}
 		log.Infof("Restored %d namespace mappings (max ID %d)", len(nsMap), maxID)
 	}
 
@@ -809,6 +920,7 @@ func (pc *PersistentCache) resolveObject(
 	}
 
 	var dl *persistentDownload
+	hit := meta != nil && meta.ContentLength >= 0
 
 	// Treat metadata with ContentLength < 0 the same as a cache miss.
 	// An in-progress chunked-encoding download stores metadata early
@@ -878,7 +990,7 @@ func (pc *PersistentCache) resolveObject(
 		}
 	}
 
-	if err := pc.eviction.RecordAccess(instanceHash); err != nil {
+	if err := pc.eviction.RecordAccess(instanceHash, meta, hit); err != nil {
 		log.Debugf("Failed to record access for %s: %v", instanceHash, err)
 	}
 
@@ -1637,7 +1749,15 @@ func (pc *PersistentCache) downloadObject(ctx context.Context, pelicanURL string
 // performDownload actually downloads the object without a prior stat.
 // It starts the download and uses early metadata from response headers to decide
 // between inline (small files) and disk-based (large files) storage.
-func (pc *PersistentCache) performDownload(ctx context.Context, dl *persistentDownload, token string) error {
+func (pc *PersistentCache) performDownload(ctx context.Context, dl *persistentDownload, token string) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "cache.fetch", attribute.String("url.full", dl.sourceURL))
+	if reqId, ok := client.RequestIdFromContext(ctx); ok {
+		span.SetAttributes(tracing.AttrJobId.String(reqId))
+	}
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
 	sourceURL, err := url.Parse(dl.sourceURL)
 	if err != nil {
 		return errors.Wrap(err, "invalid source URL")
@@ -1701,7 +1821,8 @@ func (pc *PersistentCache) performDownload(ctx context.Context, dl *persistentDo
 	// per-download contexts.  The fetcher's idle timeout (in
 	// AdoptTransfer) can also cancel this individual context when no
 	// clients remain.
-	dlCtx, dlCancel := context.WithCancel(pc.downloadCtx)
+	// The span is carried over so the origin fetch is part of the trace.
+	dlCtx, dlCancel := context.WithCancel(trace.ContextWithSpan(pc.downloadCtx, span))
 	dl.cancelFn = dlCancel
 	transferOpts := []client.TransferOption{
 		client.WithToken(userToken),
@@ -1728,6 +1849,7 @@ func (pc *PersistentCache) performDownload(ctx context.Context, dl *persistentDo
 	// Wait for metadata or transfer completion
 	var metadata client.TransferMetadata
 	var metadataReceived bool
+	var earlyResult *client.TransferResults
 
 	// Channel to receive transfer results.
 	// This goroutine terminates when tc.Results() is closed by tc.Close().
@@ -1761,10 +1883,10 @@ func (pc *PersistentCache) performDownload(ctx context.Context, dl *persistentDo
 			"etag":       etag,
 			"url":        sourceURL.String(),
 		}).Debug("Received early metadata")
-	case result := <-resultChan:
+	case earlyResult = <-resultChan:
 		// Transfer completed before we got metadata (shouldn't happen for successful transfers)
-		if result != nil && result.Error != nil {
-			return result.Error
+		if earlyResult != nil && earlyResult.Error != nil {
+			return earlyResult.Error
 		}
 		// If we got here without metadata, the transfer completed very quickly
 		// Check the decision writer's buffer for size
@@ -1785,8 +1907,18 @@ func (pc *PersistentCache) performDownload(ctx context.Context, dl *persistentDo
 		// Parse Cache-Control directives to decide whether to persist
 		ccDirectives := ParseCacheControl(dl.cacheControl)
 
+		// Objects too large for their namespace's quota are served like
+		// no-store responses rather than evicting the whole namespace.
+		storable := ccDirectives.ShouldStore()
+		if !storable {
+			log.Debugf("performDownload: Origin sent Cache-Control %q — will not persist", dl.cacheControl)
+		} else if !pc.eviction.FitsQuota(dl.namespaceID, metadata.ObjectSize) {
+			log.Debugf("performDownload: %s (%d bytes) exceeds its namespace quota — will not persist", dl.sourceURL, metadata.ObjectSize)
+			storable = false
+		}
+
 		// Check if object with this ETag already exists (only relevant for storable responses)
-		if ccDirectives.ShouldStore() {
+		if storable {
 			existingMeta, err := pc.storage.GetMetadata(dl.instanceHash)
 			if err != nil {
 				log.Warnf("Failed to check existing metadata: %v", err)
@@ -1805,56 +1937,8 @@ func (pc *PersistentCache) performDownload(ctx context.Context, dl *persistentDo
 				return nil
 			}
 		} else {
-			// Origin says not to store — stream directly to the caller via
-			// an io.Pipe instead of buffering the entire response in memory
-			// (which could OOM on large objects).
-			log.Debugf("performDownload: Origin sent Cache-Control %q — will not persist", dl.cacheControl)
-
-			pr, pw := io.Pipe()
-			buffered := dw.SetPipeMode(pw)
-
-			// Combine any data buffered before the decision with the pipe.
-			// This avoids writing into the pipe before a consumer is reading
-			// (io.Pipe is unbuffered, so that would deadlock).
-			var noStoreReader io.ReadCloser
-			if len(buffered) > 0 {
-				noStoreReader = &multiReadCloser{
-					Reader: io.MultiReader(bytes.NewReader(buffered), pr),
-					close:  pr.Close,
-				}
-			} else {
-				noStoreReader = pr
-			}
-
-			dl.noStoreReader = noStoreReader
-			dl.noStoreMeta = &CacheMetadata{
-				ETag:          dl.etag,
-				LastModified:  dl.lastModified,
-				ContentType:   "application/octet-stream",
-				ContentLength: metadata.ObjectSize,
-				SourceURL:     dl.sourceURL,
-				NamespaceID:   dl.namespaceID,
-			}
-			dl.noStoreMeta.SetCacheControl(dl.cacheControl)
-
-			// Spawn a background goroutine to finish receiving the transfer
-			// and close the pipe writer when done.  The caller reads from pr.
 			tcHandedOff = true
-			pc.downloadWg.Add(1)
-			pc.egrp.Go(func() error {
-				defer pc.downloadWg.Done()
-				defer tc.Close()
-				defer close(dl.completionDone)
-				result := <-resultChan
-				if result != nil && result.Error != nil {
-					pw.CloseWithError(result.Error)
-				} else {
-					pw.Close()
-				}
-				return nil
-			})
-
-			return ErrNoStore
+			return pc.streamWithoutStoring(dl, dw, tc, resultChan, metadata.ObjectSize)
 		}
 
 		// Make storage decision based on size.
@@ -1868,7 +1952,11 @@ func (pc *PersistentCache) performDownload(ctx context.Context, dl *persistentDo
 				// Buffer reached InlineMaxBytes while transfer is still
 				// ongoing → disk mode with unknown final size.
 				log.Debugf("performDownload: Unknown size reached threshold — using disk mode")
-				if err := dw.SetDiskMode(ctx, -1); err != nil {
+				if err := dw.SetDiskMode(ctx, -1); errors.Is(err, ErrNamespaceQuotaExceeded) {
+					log.Debugf("performDownload: %s does not fit its namespace quota — will not persist: %v", dl.sourceURL, err)
+					tcHandedOff = true
+					return pc.streamWithoutStoring(dl, dw, tc, resultChan, -1)
+				} else if err != nil {
 					return errors.Wrap(err, "failed to set disk mode for unknown size")
 				}
 				sharedState, err := pc.storage.GetSharedBlockState(dl.instanceHash)
@@ -1936,7 +2024,11 @@ func (pc *PersistentCache) performDownload(ctx context.Context, dl *persistentDo
 			}
 		} else {
 			// Large file - use disk storage
-			if err := dw.SetDiskMode(ctx, metadata.ObjectSize); err != nil {
+			if err := dw.SetDiskMode(ctx, metadata.ObjectSize); errors.Is(err, ErrNamespaceQuotaExceeded) {
+				log.Debugf("performDownload: %s does not fit its namespace quota — will not persist: %v", dl.sourceURL, err)
+				tcHandedOff = true
+				return pc.streamWithoutStoring(dl, dw, tc, resultChan, metadata.ObjectSize)
+			} else if err != nil {
 				return errors.Wrap(err, "failed to set disk mode")
 			}
 			// Mark the shared block state as having an active download so
@@ -1959,7 +2051,14 @@ func (pc *PersistentCache) performDownload(ctx context.Context, dl *persistentDo
 				return errors.Wrap(err, "failed to set inline mode")
 			}
 		} else {
-			if err := dw.SetDiskMode(ctx, bufLen); err != nil {
+			if err := dw.SetDiskMode(ctx, bufLen); errors.Is(err, ErrNamespaceQuotaExceeded) {
+				log.Debugf("performDownload: %s does not fit its namespace quota — will not persist: %v", dl.sourceURL, err)
+				// The transfer has finished; replay its result
+				finished := make(chan *client.TransferResults, 1)
+				finished <- earlyResult
+				tcHandedOff = true
+				return pc.streamWithoutStoring(dl, dw, tc, finished, bufLen)
+			} else if err != nil {
 				return errors.Wrap(err, "failed to set disk mode")
 			}
 			sharedState, err := pc.storage.GetSharedBlockState(dl.instanceHash)
@@ -2026,6 +2125,59 @@ func (pc *PersistentCache) performDownload(ctx context.Context, dl *persistentDo
 	return nil
 }
 
+// streamWithoutStoring hands a download to the caller without persisting
+// it, as for no-store responses: data flows through an io.Pipe instead of
+// being buffered in memory (which could OOM on large objects).  It takes
+// ownership of tc and returns ErrNoStore, with the reader and metadata
+// left in dl.noStoreReader and dl.noStoreMeta.
+func (pc *PersistentCache) streamWithoutStoring(dl *persistentDownload, dw *decisionWriter, tc *client.TransferClient,
+	resultChan <-chan *client.TransferResults, contentLength int64) error {
+	pr, pw := io.Pipe()
+	buffered := dw.SetPipeMode(pw)
+
+	// Combine any data buffered before the decision with the pipe.
+	// This avoids writing into the pipe before a consumer is reading
+	// (io.Pipe is unbuffered, so that would deadlock).
+	var noStoreReader io.ReadCloser
+	if len(buffered) > 0 {
+		noStoreReader = &multiReadCloser{
+			Reader: io.MultiReader(bytes.NewReader(buffered), pr),
+			close:  pr.Close,
+		}
+	} else {
+		noStoreReader = pr
+	}
+
+	dl.noStoreReader = noStoreReader
+	dl.noStoreMeta = &CacheMetadata{
+		ETag:          dl.etag,
+		LastModified:  dl.lastModified,
+		ContentType:   "application/octet-stream",
+		ContentLength: contentLength,
+		SourceURL:     dl.sourceURL,
+		NamespaceID:   dl.namespaceID,
+	}
+	dl.noStoreMeta.SetCacheControl(dl.cacheControl)
+
+	// Spawn a background goroutine to finish receiving the transfer
+	// and close the pipe writer when done.  The caller reads from pr.
+	pc.downloadWg.Add(1)
+	pc.egrp.Go(func() error {
+		defer pc.downloadWg.Done()
+		defer tc.Close()
+		defer close(dl.completionDone)
+		result := <-resultChan
+		if result != nil && result.Error != nil {
+			pw.CloseWithError(result.Error)
+		} else {
+			pw.Close()
+		}
+		return nil
+	})
+
+	return ErrNoStore
+}
+
 // multiReadCloser combines an io.Reader (e.g. io.MultiReader) with a
 // close function.  This is used to prepend buffered data before a pipe
 // reader while still allowing Close() to clean up the pipe.
@@ -2240,11 +2392,19 @@ func (w *decisionWriter) SetInlineMode(ctx context.Context, size int64) error {
 	return nil
 }
 
-// SetDiskMode configures the writer for disk-based block storage
+// SetDiskMode configures the writer for disk-based block storage.  If the
+// object cannot be admitted to its namespace's quota, the writer is left
+// undecided and an ErrNamespaceQuotaExceeded error is returned so the
+// caller can stream the object without storing it.
 func (w *decisionWriter) SetDiskMode(ctx context.Context, size int64) error {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
+	// Make room within the namespace's quota before charging any usage.
+	if err := w.pc.eviction.AdmitObject(w.dl.namespaceID, size); err != nil {
+		return err
+	}
+
 	w.ctx = ctx
 	w.size = size
 
@@ -2510,6 +2670,7 @@ func (pc *PersistentCache) getNamespaceID(objectPath string) NamespaceID {
 
 	id := NamespaceID(pc.nextNamespaceID.Add(1))
 	pc.namespaceMap[prefix] = id
+	pc.eviction.BindNamespace(prefix, id)
 
 	// Persist the mapping so it survives restarts
 	if err := pc.db.SetNamespaceMapping(prefix, id); err != nil {
@@ -2616,6 +2777,7 @@ func (pc *PersistentCache) GetStats() PersistentCacheStats {
 		TotalUsage:       evictStats.TotalUsage,
 		DirStats:         evictStats.DirStats,
 		NamespaceUsage:   nsUsage,
+		PolicyStats:      evictStats.PolicyStats,
 		ConsistencyStats: consistStats,
 	}
 }
@@ -2625,5 +2787,6 @@ type PersistentCacheStats struct {
 	TotalUsage       uint64
 	DirStats         map[StorageID]DirEvictionStats
 	NamespaceUsage   map[string]int64
+	PolicyStats      map[string]EvictionPolicyStats
 	ConsistencyStats ConsistencyStats
 }
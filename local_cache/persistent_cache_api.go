@@ -49,6 +49,8 @@ import (
 	"github.com/pelicanplatform/pelican/server_structs"
 	"github.com/pelicanplatform/pelican/token"
 	"github.com/pelicanplatform/pelican/token_scopes"
+	"github.com/pelicanplatform/pelican/tracing"
+	"github.com/pelicanplatform/pelican/utils"
 	"github.com/pelicanplatform/pelican/web_ui"
 )
 
@@ -94,6 +96,9 @@ func formatDigestHeader(checksums []Checksum) string {
 		case ChecksumCRC32C:
 			algName = "crc32c"
 			encodedVal = hex.EncodeToString(ck.Value)
+		case ChecksumAdler32:
+			algName = "adler32"
+			encodedVal = hex.EncodeToString(ck.Value)
 		default:
 			continue // skip unknown checksum types
 		}
@@ -304,8 +309,11 @@ func (pc *PersistentCache) serveObject(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Handle write-through requests (PUT, DELETE) - proxy to origin
-	if r.Method == "PUT" || r.Method == "DELETE" {
+	// Handle write-through requests (PUT, DELETE) - proxy to origin.
+	// A HEAD with an upload ID looks up the progress of a resumable upload
+	// and is answered by the origin as well.
+	if r.Method == "PUT" || r.Method == "DELETE" ||
+		(r.Method == "HEAD" && r.Header.Get(utils.ResumableUploadIdHeader) != "") {
 		pc.proxyWrite(w, r, objectPath, bearerToken)
 		return
 	}
@@ -433,8 +441,9 @@ func (pc *PersistentCache) serveObject(w http.ResponseWriter, r *http.Request) {
 		// download path can propagate it to the origin via
 		// X-Pelican-JobId.  We deliberately do NOT derive from
 		// r.Context() because the reader outlives the HTTP handler
-		// when the background download continues.
-		dlCtx := context.Background()
+		// when the background download continues; only its trace
+		// span is carried over.
+		dlCtx := tracing.Detach(r.Context())
 		if requestId != "" {
 			dlCtx = client.ContextWithRequestId(dlCtx, requestId)
 		}
@@ -766,7 +775,8 @@ func (pc *PersistentCache) proxyPropfind(w http.ResponseWriter, r *http.Request,
 	}
 }
 
-// proxyWrite forwards a PUT or DELETE request to the origin server (write-through).
+// proxyWrite forwards a PUT or DELETE request to the origin server (write-through),
+// as well as the HEAD requests resumable uploads use to look up their progress.
 // On success, any locally-cached copy of the object is invalidated so that
 // subsequent GETs retrieve the new version from the origin.
 //
@@ -784,7 +794,8 @@ func (pc *PersistentCache) proxyPropfind(w http.ResponseWriter, r *http.Request,
 func (pc *PersistentCache) proxyWrite(w http.ResponseWriter, r *http.Request, objectPath string, bearerToken string) {
 	reqLog := requestLogger(r, objectPath)
 
-	// Check authorization — PUT requires storage.create, DELETE requires storage.modify
+	// Check authorization — PUT (and upload progress HEADs) require storage.create,
+	// DELETE requires storage.modify
 	var requiredScope token_scopes.TokenScope
 	if r.Method == "DELETE" {
 		requiredScope = token_scopes.Wlcg_Storage_Modify
@@ -862,6 +873,13 @@ func (pc *PersistentCache) proxyWrite(w http.ResponseWriter, r *http.Request, ob
 	if jobId := r.Header.Get("X-Pelican-JobId"); jobId != "" {
 		proxyReq.Header.Set("X-Pelican-JobId", jobId)
 	}
+	// Forward the headers of resumable uploads; each chunk is its own
+	// request, and the origin's 308 responses are passed back as-is
+	for _, hdr := range []string{"Content-Range", utils.ResumableUploadIdHeader} {
+		if v := r.Header.Get(hdr); v != "" {
+			proxyReq.Header.Set(hdr, v)
+		}
+	}
 
 	// The director 307-redirects to the origin (a different host), so Go's
 	// default redirect policy strips the Authorization header.  Use a
@@ -906,7 +924,7 @@ func (pc *PersistentCache) proxyWrite(w http.ResponseWriter, r *http.Request, ob
 	// If the origin responded with an ETag, only the stale instance is
 	// deleted and the ETag mapping is updated to point to the (future)
 	// new instance; otherwise the entire ETag mapping is removed.
-	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 && r.Method != "HEAD" {
 		pc.invalidateCachedObject(objectPath, resp.Header.Get("ETag"))
 	}
 
@@ -1055,7 +1073,7 @@ func (pc *PersistentCache) LaunchListener(ctx context.Context, egrp *errgroup.Gr
 	}
 
 	srv := http.Server{
-		Handler: http.HandlerFunc(handler),
+		Handler: tracing.HTTPMiddleware(http.HandlerFunc(handler)),
 	}
 	egrp.Go(func() error {
 		return srv.Serve(listener)
@@ -1561,6 +1579,10 @@ func (pc *PersistentCache) introspectMetadataHandler(c *gin.Context) {
 		StorageID:     uint8(meta.StorageID),
 		LastValidated: meta.LastValidated,
 	}
+	if meta.IsCompressed() {
+		details.Compression = meta.Compression.String()
+		details.StoredBytes = meta.StoredSize()
+	}
 
 	cc := meta.GetCacheDirectives()
 	if cc.HasDirectives() {
@@ -1742,8 +1764,7 @@ func (pc *PersistentCache) GetCacheStats() (*CacheStats, error) {
 			ds.InlineCount++
 			ds.InlineBytes += meta.ContentLength
 		} else {
-			ds.OnDiskCount++
-			ds.OnDiskBytes += CalculateFileSize(meta.ContentLength)
+			stats.addOnDisk(ds, meta)
 		}
 		return nil
 	}); err != nil {
@@ -1780,6 +1801,9 @@ func (pc *PersistentCache) GetCacheStats() (*CacheStats, error) {
 			stats.NamespaceNames[uint32(id)] = prefix
 		}
 	}
+	if policyStats, err := pc.eviction.GetPolicyStats(); err == nil && len(policyStats) > 0 {
+		stats.EvictionPolicies = policyStats
+	}
 
 	return stats, nil
 }
@@ -1808,8 +1832,7 @@ func (pc *PersistentCache) introspectStatsHandler(c *gin.Context) {
 			ds.InlineCount++
 			ds.InlineBytes += meta.ContentLength
 		} else {
-			ds.OnDiskCount++
-			ds.OnDiskBytes += CalculateFileSize(meta.ContentLength)
+			stats.addOnDisk(ds, meta)
 		}
 		return nil
 	})
@@ -1849,6 +1872,9 @@ func (pc *PersistentCache) introspectStatsHandler(c *gin.Context) {
 			stats.NamespaceNames[uint32(id)] = prefix
 		}
 	}
+	if policyStats, err := pc.eviction.GetPolicyStats(); err == nil && len(policyStats) > 0 {
+		stats.EvictionPolicies = policyStats
+	}
 
 	c.JSON(http.StatusOK, stats)
 }
@@ -19,9 +19,12 @@
 package local_cache
 
 import (
+	"crypto/sha256"
+	"encoding/base64"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestIsFederationAllowed(t *testing.T) {
@@ -59,3 +62,23 @@ func TestIsFederationAllowed(t *testing.T) {
 		assert.True(t, isFederationAllowed("allowed.example.com:443", primary, list))
 	})
 }
+
+func TestFormatDigestHeader(t *testing.T) {
+	content := []byte("test file content")
+	sha256Sum := sha256.Sum256(content)
+
+	cc := &ConsistencyChecker{}
+	hasher, err := cc.createHasher(ChecksumAdler32)
+	require.NoError(t, err)
+	_, err = hasher.Write(content)
+	require.NoError(t, err)
+
+	assert.Empty(t, formatDigestHeader(nil))
+	assert.Equal(t,
+		"sha-256="+base64.StdEncoding.EncodeToString(sha256Sum[:])+", adler32=3a9e069c",
+		formatDigestHeader([]Checksum{
+			{Type: ChecksumSHA256, Value: sha256Sum[:]},
+			{Type: ChecksumAdler32, Value: hasher.Sum(nil)},
+			{Type: ChecksumType(255), Value: []byte{0x01}},
+		}))
+}
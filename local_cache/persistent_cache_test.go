@@ -1066,8 +1066,8 @@ func TestEvictionManager(t *testing.T) {
 	})
 
 	// Test recording access
-	require.NoError(t, eviction.RecordAccess("instance_hash_1"))
-	require.NoError(t, eviction.RecordAccess("instance_hash_2"))
+	require.NoError(t, eviction.RecordAccess("instance_hash_1", nil, true))
+	require.NoError(t, eviction.RecordAccess("instance_hash_2", nil, true))
 
 	// Test adding usage via AddUsage (MergeOperator-backed)
 	require.NoError(t, seedUsage(db, StorageIDFirstDisk, 1, 100000))
@@ -155,6 +155,7 @@ type RangeReader struct {
 	// Cached at construction so Read calls bypass TTL cache lookups.
 	encryptor *BlockEncryptor // nil for inline objects
 	file      *refCountedFile // chunk 0 / non-chunked FD; nil for inline
+	fileMeta  *CacheMetadata  // Layout of the data file behind file
 
 	// Fetch callback for missing blocks
 	fetchBlocks func(ctx context.Context, startBlock, endBlock uint32) error
@@ -229,6 +230,10 @@ func NewRangeReader(
 	// Cache crypto + FD for disk-backed objects so that Read calls
 	// bypass the TTL cache lookups performed by ReadBlocksInto.
 	if meta.IsDisk() {
+		lock := storage.lockDataFiles(instanceHash)
+		lock.RLock()
+		defer lock.RUnlock()
+
 		dc, err := storage.getDiskCrypto(instanceHash)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to get disk crypto")
@@ -236,7 +241,7 @@ func NewRangeReader(
 		rr.encryptor = dc.encryptor
 
 		if !meta.IsChunked() {
-			rc, err := storage.getFile(instanceHash, meta.StorageID)
+			rc, err := storage.getChunkFile(instanceHash, dc.meta, 0)
 			if err != nil {
 				if !errors.Is(err, os.ErrNotExist) {
 					return nil, errors.Wrap(err, "failed to open object file")
@@ -245,6 +250,7 @@ func NewRangeReader(
 				// first read triggers auto-repair instead of failing here.
 			} else {
 				rr.file = rc
+				rr.fileMeta = dc.meta
 			}
 		}
 	}
@@ -449,6 +455,17 @@ func (rr *RangeReader) repairAndRetry(ctx context.Context, startBlock, endBlock
 		return data, nil
 	}
 
+	// Compressed data files cannot be patched block by block; drop the
+	// object so that the next request downloads it again.
+	if dc, err := rr.storage.getDiskCrypto(rr.instanceHash); err == nil && dc.meta.IsCompressed() {
+		log.Warnf("Auto-repair: detected %d corrupt block(s) in compressed object %s; removing it from the cache",
+			len(corrupt), rr.instanceHash)
+		if err := rr.storage.Delete(rr.instanceHash); err != nil {
+			log.Warnf("Failed to delete corrupt object %s: %v", rr.instanceHash, err)
+		}
+		return nil, errors.Wrap(origErr, "auto-repair: compressed object is corrupt")
+	}
+
 	// Cap the number of blocks we repair in one pass.  When the file is
 	// missing, IdentifyCorruptBlocks returns EVERY block in the bitmap,
 	// which could be millions for a large object.  We limit to ~32 MB so
@@ -508,9 +525,15 @@ func (rr *RangeReader) repairAndRetry(ctx context.Context, startBlock, endBlock
 
 	// Re-open the file handle if it was nil (file was recreated on disk).
 	if rr.file == nil && !rr.meta.IsChunked() {
-		if rc, err := rr.storage.getFile(rr.instanceHash, rr.meta.StorageID); err == nil {
-			rr.file = rc
+		lock := rr.storage.lockDataFiles(rr.instanceHash)
+		lock.RLock()
+		if dc, err := rr.storage.getDiskCrypto(rr.instanceHash); err == nil {
+			if rc, err := rr.storage.getChunkFile(rr.instanceHash, dc.meta, 0); err == nil {
+				rr.file = rc
+				rr.fileMeta = dc.meta
+			}
 		}
+		lock.RUnlock()
 	}
 
 	return data, nil
@@ -563,7 +586,7 @@ func (rr *RangeReader) readDiskDirect(dst []byte, off int64) (int, error) {
 		return 0, errors.New("object file is missing from disk")
 	}
 
-	return decryptBlocksFromFile(rr.file.File(), meta.ContentLength, encryptor, dst[:actualLen], off, rr.storage.ptCache, rr.instanceHash, 0)
+	return rr.storage.readDataFileInto(rr.file, rr.fileMeta, meta.ContentLength, encryptor, dst[:actualLen], off, rr.instanceHash, 0)
 }
 
 // Close closes the range reader
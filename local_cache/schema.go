@@ -42,6 +42,11 @@ type ObjectHash string
 // accidental confusion with ObjectHash or arbitrary strings.
 type InstanceHash string
 
+// ContentHash is an HMAC-SHA-256 digest of an object's strong checksum.
+// It identifies a physical copy of the object's bytes that may be shared
+// by several instances with identical content.
+type ContentHash string
+
 // Key prefixes for BadgerDB
 const (
 	// PrefixMeta stores CacheMetadata (headers, validation info, storage mode)
@@ -62,6 +67,10 @@ const (
 	PrefixETag = "e:"
 	// PrefixNamespace stores namespace prefix -> ID mappings: n:<prefix> -> uint32
 	PrefixNamespace = "n:"
+	// PrefixContent stores deduplicated content records: cd:<content_hash> -> ContentRecord
+	PrefixContent = "cd:"
+	// PrefixPolicyStats stores per-eviction-policy counters: ps:<policy> -> EvictionPolicyStats
+	PrefixPolicyStats = "ps:"
 	// KeySalt is the single DB key that stores the random salt used when
 	// hashing object/instance names.  The salt prevents an attacker with
 	// DB access from correlating hashes with known URLs.
@@ -104,6 +113,10 @@ type StorageDirConfig struct {
 	// LowWaterMarkPercentage overrides the global low-water mark for this
 	// directory.  0 means use the global default.
 	LowWaterMarkPercentage int
+	// EvictionPolicy overrides the global eviction policy for this
+	// directory (see NewEvictionPolicy).  Empty means use the global
+	// default.
+	EvictionPolicy string
 }
 
 // ParseStorageDirsConfig reads the LocalCache.StorageDirs setting from Viper
@@ -123,6 +136,7 @@ type StorageDirConfig struct {
 //     MaxSize: 500GB
 //     HighWaterMarkPercentage: 95
 //     LowWaterMarkPercentage: 85
+//     EvictionPolicy: gdsf
 //     - Path: /mnt/cache2
 //     MaxSize: 2TB
 //
@@ -265,6 +279,23 @@ func parseStorageDirEntry(idx int, m map[string]interface{}) (StorageDirConfig,
 		}
 	}
 
+	// EvictionPolicy (optional)
+	if _, ok := m["EvictionPolicy"]; !ok {
+		if v, ok := m["evictionpolicy"]; ok {
+			m["EvictionPolicy"] = v
+		}
+	}
+	if v, ok := m["EvictionPolicy"]; ok && v != nil {
+		s, ok := v.(string)
+		if !ok {
+			return cfg, fmt.Errorf("LocalCache.StorageDirs[%d].EvictionPolicy: expected a string, got %T", idx, v)
+		}
+		if _, err := NewEvictionPolicy(s); err != nil {
+			return cfg, fmt.Errorf("LocalCache.StorageDirs[%d].EvictionPolicy: %w", idx, err)
+		}
+		cfg.EvictionPolicy = s
+	}
+
 	return cfg, nil
 }
 
@@ -298,11 +329,12 @@ const (
 type ChecksumType uint8
 
 const (
-	ChecksumMD5    ChecksumType = 0
-	ChecksumSHA1   ChecksumType = 1
-	ChecksumSHA256 ChecksumType = 2
-	ChecksumCRC32  ChecksumType = 3
-	ChecksumCRC32C ChecksumType = 4
+	ChecksumMD5     ChecksumType = 0
+	ChecksumSHA1    ChecksumType = 1
+	ChecksumSHA256  ChecksumType = 2
+	ChecksumCRC32   ChecksumType = 3
+	ChecksumCRC32C  ChecksumType = 4
+	ChecksumAdler32 ChecksumType = 5
 )
 
 // Checksum holds a checksum type and its value
@@ -324,6 +356,7 @@ type Checksum struct {
 //   - Max-time: LastModified, LastValidated, LastAccessTime, Expires,
 //     Completed — only advance forward (keep the later timestamp).
 //   - Additive: Checksums — union by algorithm; prefer OriginVerified.
+//   - Max-count: AccessCount — only grows (keep the larger count).
 //   - Last-writer-wins: ContentType, ContentLength, VaryHeaders,
 //     CCFlags, CCMaxAge — the incoming value always replaces the old one.
 //   - Set-once: ETag, SourceURL, DataKey, StorageID, NamespaceID,
@@ -333,6 +366,12 @@ type Checksum struct {
 //     hash; a changed ETag produces a different instance.  Chunking
 //     fields are set-once because changing them would invalidate
 //     existing chunk files.
+//   - Deduplication: ContentHash is never changed by a merge.  It is set,
+//     together with the storage fields it redirects, inside the
+//     transactions of CacheDB.CreateContent and CacheDB.ReferenceContent.
+//   - Compression: Compression and StoredSizes are never changed by a
+//     merge.  They are set inside the transactions of
+//     CacheDB.SetCompression and CacheDB.ReferenceContent.
 type CacheMetadata struct {
 	// Validation fields
 	ETag          string     `msgpack:"etag"`         // HTTP ETag header
@@ -372,6 +411,94 @@ type CacheMetadata struct {
 
 	// LRU tracking
 	LastAccessTime time.Time `msgpack:"la"` // Last access time for LRU index
+
+	// Access-frequency tracking for the LFU, GDSF, and ARC eviction
+	// policies.  Counts accesses recorded via CacheDB.RecordAccess.
+	AccessCount uint32 `msgpack:"ac,omitempty"`
+
+	// Deduplication.  When set, the object's data lives in the shared
+	// files named by ContentHash rather than in files named by the
+	// instance hash; the storage and DataKey fields above describe the
+	// shared copy (see ContentRecord).
+	ContentHash ContentHash `msgpack:"chash,omitempty"`
+
+	// Compression.  When set, the data files hold compressed blocks (see
+	// compression.go) and StoredSizes records the on-disk size of each
+	// chunk file, since it no longer follows from ContentLength.
+	Compression CompressionCodec `msgpack:"cmp,omitempty"`
+	StoredSizes []int64          `msgpack:"ssz,omitempty"`
+}
+
+// DataHash returns the hash that names this object's data files on disk:
+// the content hash for deduplicated objects, otherwise instanceHash.
+func (m *CacheMetadata) DataHash(instanceHash InstanceHash) InstanceHash {
+	if m.ContentHash != "" {
+		return InstanceHash(m.ContentHash)
+	}
+	return instanceHash
+}
+
+// ContentRecord describes a physical copy of object data shared by one or
+// more instances with identical content.  The layout fields mirror those
+// of CacheMetadata and are copied into each referencing instance.
+//
+// The on-disk usage of the shared copy is charged once, to NamespaceID.
+// Refs counts the referencing instances per namespace so that the charge
+// can be handed to a remaining namespace when the charged one lets go.
+type ContentRecord struct {
+	Refs           map[NamespaceID]uint32 `msgpack:"refs"`
+	NamespaceID    NamespaceID            `msgpack:"ns"`
+	StorageID      StorageID              `msgpack:"sid"`
+	ContentLength  int64                  `msgpack:"cl"`
+	ChunkSizeCode  ChunkSizeCode          `msgpack:"csc,omitempty"`
+	ChunkLocations []ChunkLocation        `msgpack:"chl,omitempty"`
+	DataKey        []byte                 `msgpack:"key"`
+	Compression    CompressionCodec       `msgpack:"cmp,omitempty"`
+	StoredSizes    []int64                `msgpack:"ssz,omitempty"`
+}
+
+// RefCount returns the total number of instances referencing the content.
+func (r *ContentRecord) RefCount() uint32 {
+	var total uint32
+	for _, n := range r.Refs {
+		total += n
+	}
+	return total
+}
+
+// Layout returns metadata describing the shared copy's files, suitable
+// for PerDirectoryBytes and file deletion.
+func (r *ContentRecord) Layout() *CacheMetadata {
+	return &CacheMetadata{
+		StorageID:      r.StorageID,
+		NamespaceID:    r.NamespaceID,
+		ContentLength:  r.ContentLength,
+		ChunkSizeCode:  r.ChunkSizeCode,
+		ChunkLocations: r.ChunkLocations,
+		DataKey:        r.DataKey,
+		Compression:    r.Compression,
+		StoredSizes:    r.StoredSizes,
+	}
+}
+
+// EvictionPolicyStats holds the cumulative counters for one eviction
+// policy.  Hits and misses are attributed to the policy governing the
+// accessed object's storage directory and namespace.
+type EvictionPolicyStats struct {
+	Hits           uint64 `msgpack:"h" json:"hits"`
+	Misses         uint64 `msgpack:"m" json:"misses"`
+	EvictedObjects uint64 `msgpack:"eo" json:"evicted_objects"`
+	EvictedBytes   uint64 `msgpack:"eb" json:"evicted_bytes"`
+}
+
+// HitRatio returns the fraction of lookups served from the cache, or 0
+// when no lookups have been recorded.
+func (s EvictionPolicyStats) HitRatio() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
 }
 
 // IsInline returns true when the object data is stored directly in BadgerDB.
@@ -479,7 +606,8 @@ func (m *CacheMetadata) AllStorageIDs() []StorageID {
 // bytes that live in each storage directory.  For non-chunked objects the
 // entire ContentLength is attributed to the base StorageID.  For chunked
 // objects the byte count is split according to each chunk's assigned
-// directory.  Unallocated chunks (StorageID 0) are skipped.
+// directory.  Unallocated chunks (StorageID 0) are skipped.  Compressed
+// objects are attributed the recorded sizes of their data files.
 func (m *CacheMetadata) PerDirectoryBytes() map[StorageID]int64 {
 	result := make(map[StorageID]int64)
 	if !m.IsChunked() {
@@ -487,7 +615,7 @@ func (m *CacheMetadata) PerDirectoryBytes() map[StorageID]int64 {
 			if m.StorageID == StorageIDInline {
 				result[m.StorageID] = m.ContentLength
 			} else {
-				result[m.StorageID] = CalculateFileSize(m.ContentLength)
+				result[m.StorageID] = m.storedChunkSize(0, m.ContentLength)
 			}
 		}
 		return result
@@ -496,11 +624,37 @@ func (m *CacheMetadata) PerDirectoryBytes() map[StorageID]int64 {
 		if ci.StorageID == StorageIDInline {
 			continue // unallocated
 		}
-		result[ci.StorageID] += CalculateFileSize(ci.Size)
+		result[ci.StorageID] += m.storedChunkSize(ci.Index, ci.Size)
 	}
 	return result
 }
 
+// storedChunkSize returns the on-disk size of the data file of chunk
+// chunkIndex, which holds contentLength bytes of content.
+func (m *CacheMetadata) storedChunkSize(chunkIndex int, contentLength int64) int64 {
+	if m.IsCompressed() && chunkIndex < len(m.StoredSizes) {
+		return m.StoredSizes[chunkIndex]
+	}
+	return CalculateFileSize(contentLength)
+}
+
+// StoredSize returns the number of bytes the object's data occupies:
+// ContentLength for inline objects, otherwise the total size of its
+// allocated data files.
+func (m *CacheMetadata) StoredSize() int64 {
+	var total int64
+	for _, bytes := range m.PerDirectoryBytes() {
+		total += bytes
+	}
+	return total
+}
+
+// IsCompressed returns true when the object's data files hold compressed
+// blocks.
+func (m *CacheMetadata) IsCompressed() bool {
+	return m.Compression != CompressionNone
+}
+
 // SetCacheControl parses a Cache-Control header and stores the directives efficiently
 func (m *CacheMetadata) SetCacheControl(header string) {
 	if header == "" {
@@ -665,6 +819,15 @@ func ComputeInstanceHash(salt []byte, etag string, objectHash ObjectHash) Instan
 	return InstanceHash(hex.EncodeToString(h.Sum(nil)))
 }
 
+// ComputeContentHash computes HMAC-SHA-256(salt, type + ":" + checksum).
+// This identifies a physical copy of object data by its strong checksum.
+func ComputeContentHash(salt []byte, checksum Checksum) ContentHash {
+	h := hmac.New(sha256.New, salt)
+	h.Write([]byte{byte(checksum.Type), ':'})
+	h.Write(checksum.Value)
+	return ContentHash(hex.EncodeToString(h.Sum(nil)))
+}
+
 // normalizeURL normalizes a pelican URL for consistent hashing
 func normalizeURL(pelicanURL string) string {
 	// Parse the URL
@@ -709,6 +872,16 @@ func ETagKey(objectHash ObjectHash) []byte {
 	return []byte(PrefixETag + string(objectHash))
 }
 
+// ContentKey returns the BadgerDB key for a deduplicated content record
+func ContentKey(contentHash ContentHash) []byte {
+	return []byte(PrefixContent + string(contentHash))
+}
+
+// PolicyStatsKey returns the BadgerDB key for an eviction policy's counters
+func PolicyStatsKey(policy string) []byte {
+	return []byte(PrefixPolicyStats + policy)
+}
+
 // NamespaceKey returns the BadgerDB key for a namespace prefix mapping
 func NamespaceKey(prefix string) []byte {
 	return []byte(PrefixNamespace + prefix)
@@ -21,6 +21,7 @@ package local_cache
 import (
 	"context"
 	"encoding/binary"
+	"hash/fnv"
 	"io"
 	"os"
 	"path/filepath"
@@ -99,6 +100,12 @@ type refCountedFile struct {
 	_    noCopy
 	f    *os.File
 	refs atomic.Int32
+
+	// Block index of a compressed data file, read on first use (see
+	// compressedIndex).
+	indexOnce sync.Once
+	index     *compressedIndex
+	indexErr  error
 }
 
 // noCopy may be added to structs which must not be copied after the first
@@ -238,9 +245,12 @@ func ptCacheKey(h InstanceHash, block uint32) uint64 {
 }
 
 // chunkFileKey identifies a specific chunk file in the FD cache.
+// compressed distinguishes the compressed rewrite of a chunk from the
+// original file while both exist.
 type chunkFileKey struct {
 	instanceHash InstanceHash
 	chunkIndex   int
+	compressed   bool
 }
 
 // smallChunkLimit is the threshold below which chunk file tracking uses
@@ -355,8 +365,25 @@ type StorageManager struct {
 	// overwrites this with EvictionManager.ChooseDiskStorage (weighted
 	// by free space) before any concurrent access begins.
 	chooseDir func() StorageID
+
+	// rewriteMu serializes DeduplicateObject and CompressObject, which
+	// replace the data files of completed objects.  Among other things,
+	// this ensures two instances with the same content cannot both
+	// register themselves as the shared copy.
+	rewriteMu sync.Mutex
+
+	// dataFileLocks make readers wait while DeduplicateObject or
+	// CompressObject renames or deletes an object's data files.  Readers
+	// hold the read lock of the object's stripe (see lockDataFiles) from
+	// looking up its metadata until they have opened the files it names;
+	// an already-open file stays readable after it is renamed or deleted.
+	dataFileLocks [dataFileLockStripes]sync.RWMutex
 }
 
+// dataFileLockStripes is the number of locks the objects' data files are
+// spread over; see StorageManager.dataFileLocks.
+const dataFileLockStripes = 64
+
 // StorageDirInfo describes a configured storage directory at runtime.
 type StorageDirInfo struct {
 	StorageID  StorageID
@@ -694,41 +721,6 @@ func (sm *StorageManager) getDiskCrypto(instanceHash InstanceHash) (*diskCryptoE
 	return entry, nil
 }
 
-// getFile returns a reference-counted, cached file descriptor for the
-// given disk-backed object, opening the file on cache miss.
-//
-// The returned *refCountedFile has one additional reference held on
-// behalf of the caller.  The caller MUST call Release() when I/O is
-// complete.  All I/O must use ReadAt / WriteAt (offset-based,
-// concurrency-safe).
-func (sm *StorageManager) getFile(instanceHash InstanceHash, storageID StorageID) (*refCountedFile, error) {
-	key := chunkFileKey{instanceHash: instanceHash, chunkIndex: 0}
-	if sm.fdCacheMaxSize > 0 {
-		if item := sm.openFiles.Get(key); item != nil {
-			rc := item.Value()
-			if rc.Acquire() {
-				return rc, nil
-			}
-			// Ref count already at zero (being closed) — fall through to open a new one.
-		}
-	}
-
-	objectPath := sm.getObjectPathForDir(storageID, instanceHash)
-
-	file, err := os.OpenFile(objectPath, os.O_RDWR, 0600)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to open object file")
-	}
-
-	rc := newRefCountedFile(file)
-	if sm.fdCacheMaxSize > 0 {
-		// The cache takes its own reference.
-		rc.Acquire()
-		sm.openFiles.Set(key, rc, ttlcache.DefaultTTL)
-	}
-	return rc, nil
-}
-
 // invalidateObjectCaches removes all in-memory cached state for an object:
 // block state, disk crypto, and file descriptors.  chunkCount is the number
 // of chunk files (1 for non-chunked objects).  Call this when an object is
@@ -738,9 +730,19 @@ func (sm *StorageManager) invalidateObjectCaches(instanceHash InstanceHash, chun
 	sm.diskCrypto.Delete(instanceHash)
 	for i := 0; i < chunkCount; i++ {
 		sm.openFiles.Delete(chunkFileKey{instanceHash: instanceHash, chunkIndex: i})
+		sm.openFiles.Delete(chunkFileKey{instanceHash: instanceHash, chunkIndex: i, compressed: true})
 	}
 }
 
+// lockDataFiles returns the lock guarding the names of instanceHash's data
+// files against DeduplicateObject and CompressObject.  Callers must not
+// take it again while holding it, as objects share stripes.
+func (sm *StorageManager) lockDataFiles(instanceHash InstanceHash) *sync.RWMutex {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(instanceHash))
+	return &sm.dataFileLocks[h.Sum32()%dataFileLockStripes]
+}
+
 // getObjectPathForDir returns the full path for an object in a specific directory.
 func (sm *StorageManager) getObjectPathForDir(storageID StorageID, instanceHash InstanceHash) string {
 	dir, ok := sm.dirs[storageID]
@@ -769,48 +771,71 @@ func (sm *StorageManager) getChunkPath(storageID StorageID, instanceHash Instanc
 	return GetChunkPath(basePath, chunkIndex)
 }
 
-// getChunkFile returns a reference-counted file descriptor for a specific chunk.
-// chunkIndex is 0-based.  The caller MUST call Release() when done.
-// Returns an error if the chunk is not allocated (StorageID = 0).
+// getCompressedChunkPath returns the filesystem path for the compressed
+// rewrite of a specific chunk of an object (see compression.go).
+func (sm *StorageManager) getCompressedChunkPath(storageID StorageID, instanceHash InstanceHash, chunkIndex int) string {
+	basePath := sm.getObjectPathForDir(storageID, instanceHash) + compressedFileSuffix
+	return GetChunkPath(basePath, chunkIndex)
+}
+
+// getDataFilePath returns the path of the data file of chunk chunkIndex
+// for an object laid out as described by meta, whose files are named by
+// dataHash (see CacheMetadata.DataHash).  Compressed objects use the
+// names of their compressed rewrite.
+func (sm *StorageManager) getDataFilePath(meta *CacheMetadata, dataHash InstanceHash, chunkIndex int) string {
+	storageID := meta.GetChunkStorageID(chunkIndex)
+	if meta.IsCompressed() {
+		return sm.getCompressedChunkPath(storageID, dataHash, chunkIndex)
+	}
+	return sm.getChunkPath(storageID, dataHash, chunkIndex)
+}
+
+// getChunkFile returns a reference-counted, cached file descriptor for a
+// specific chunk, opening the file on cache miss.  chunkIndex is 0-based;
+// non-chunked objects only have chunk 0.  Returns an error if the chunk
+// is not allocated (StorageID = 0).
+//
+// The returned *refCountedFile has one additional reference held on
+// behalf of the caller.  The caller MUST call Release() when I/O is
+// complete.  All I/O must use ReadAt / WriteAt (offset-based,
+// concurrency-safe).
 func (sm *StorageManager) getChunkFile(instanceHash InstanceHash, meta *CacheMetadata, chunkIndex int) (*refCountedFile, error) {
 	if !meta.IsChunked() || chunkIndex == 0 {
-		// Non-chunked objects or chunk 0 use the base file path
-		storageID := meta.StorageID
-		if storageID == StorageIDInline {
+		if meta.StorageID == StorageIDInline {
 			if meta.IsChunked() {
 				return nil, errors.New("chunk 0 is not yet allocated")
 			}
 			return nil, errors.New("cannot get file for inline storage")
 		}
-		return sm.getFile(instanceHash, storageID)
-	}
-
-	// For chunks > 0, check if allocated
-	if !meta.IsChunkAllocated(chunkIndex) {
+		chunkIndex = 0
+	} else if !meta.IsChunkAllocated(chunkIndex) {
 		return nil, errors.Errorf("chunk %d is not allocated", chunkIndex)
 	}
 
 	// Look up in the unified FD cache.
-	key := chunkFileKey{instanceHash: instanceHash, chunkIndex: chunkIndex}
+	dataHash := meta.DataHash(instanceHash)
+	key := chunkFileKey{instanceHash: dataHash, chunkIndex: chunkIndex, compressed: meta.IsCompressed()}
 	if sm.fdCacheMaxSize > 0 {
 		if item := sm.openFiles.Get(key); item != nil {
 			rc := item.Value()
 			if rc.Acquire() {
 				return rc, nil
 			}
+			// Ref count already at zero (being closed) — fall through to open a new one.
 		}
 	}
 
-	storageID := meta.GetChunkStorageID(chunkIndex)
-	chunkPath := sm.getChunkPath(storageID, instanceHash, chunkIndex)
-
-	file, err := os.OpenFile(chunkPath, os.O_RDWR, 0600)
+	file, err := os.OpenFile(sm.getDataFilePath(meta, dataHash, chunkIndex), os.O_RDWR, 0600)
 	if err != nil {
+		if chunkIndex == 0 {
+			return nil, errors.Wrap(err, "failed to open object file")
+		}
 		return nil, errors.Wrapf(err, "failed to open chunk %d file", chunkIndex)
 	}
 
 	rc := newRefCountedFile(file)
 	if sm.fdCacheMaxSize > 0 {
+		// The cache takes its own reference.
 		rc.Acquire()
 		sm.openFiles.Set(key, rc, ttlcache.DefaultTTL)
 	}
@@ -1112,7 +1137,8 @@ func (sm *StorageManager) AllocateChunk(
 	storageID := sm.chooseDir()
 
 	// Create the chunk file
-	chunkPath := sm.getChunkPath(storageID, instanceHash, chunkIndex)
+	dataHash := meta.DataHash(instanceHash)
+	chunkPath := sm.getChunkPath(storageID, dataHash, chunkIndex)
 	file, err := createFile(chunkPath)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to create chunk %d file", chunkIndex)
@@ -1142,7 +1168,7 @@ func (sm *StorageManager) AllocateChunk(
 	rc := newRefCountedFile(file)
 	if sm.fdCacheMaxSize > 0 {
 		rc.Acquire()
-		sm.openFiles.Set(chunkFileKey{instanceHash: instanceHash, chunkIndex: chunkIndex}, rc, ttlcache.DefaultTTL)
+		sm.openFiles.Set(chunkFileKey{instanceHash: dataHash, chunkIndex: chunkIndex}, rc, ttlcache.DefaultTTL)
 	}
 	rc.Release()
 
@@ -1189,6 +1215,9 @@ func (sm *StorageManager) writeBlocks(instanceHash InstanceHash, meta *CacheMeta
 	// diskCryptoEntry (which is shared/read-only).
 	localMeta := *meta
 	meta = &localMeta
+	if meta.IsCompressed() {
+		return errCompressedObject
+	}
 
 	startBlock := ContentOffsetToBlock(startOffset)
 
@@ -1218,7 +1247,7 @@ func (sm *StorageManager) writeBlocks(instanceHash InstanceHash, meta *CacheMeta
 			// File was removed from disk (e.g. corruption auto-repair).
 			// Recreate it so the write can proceed.
 			storageID := meta.GetChunkStorageID(chunkIdx)
-			chunkPath := sm.getChunkPath(storageID, instanceHash, chunkIdx)
+			chunkPath := sm.getChunkPath(storageID, meta.DataHash(instanceHash), chunkIdx)
 			chunkContentLen := ChunkContentLength(meta.ContentLength, meta.ChunkSizeCode, chunkIdx)
 			fileSize := CalculateFileSize(chunkContentLen)
 			f, createErr := createFile(chunkPath)
@@ -1377,6 +1406,10 @@ func (sm *StorageManager) checkAndMarkComplete(instanceHash InstanceHash, meta *
 // ReadBlocks reads and decrypts blocks from disk storage.
 // It uses the shared ObjectBlockState to check block availability.
 func (sm *StorageManager) ReadBlocks(instanceHash InstanceHash, startOffset int64, length int) ([]byte, error) {
+	lock := sm.lockDataFiles(instanceHash)
+	lock.RLock()
+	defer lock.RUnlock()
+
 	// Get cached metadata + encryptor (avoids DB lookup and DEK decrypt on hot path)
 	dc, err := sm.getDiskCrypto(instanceHash)
 	if err != nil {
@@ -1414,6 +1447,10 @@ func (sm *StorageManager) ReadBlocks(instanceHash InstanceHash, startOffset int6
 // to dst.  This avoids the result allocation and copy that ReadBlocks
 // performs.  dst must be large enough to hold the requested data.
 func (sm *StorageManager) ReadBlocksInto(dst []byte, instanceHash InstanceHash, startOffset int64) (int, error) {
+	lock := sm.lockDataFiles(instanceHash)
+	lock.RLock()
+	defer lock.RUnlock()
+
 	dc, err := sm.getDiskCrypto(instanceHash)
 	if err != nil {
 		return 0, err
@@ -1447,6 +1484,17 @@ func (sm *StorageManager) ReadBlocksInto(dst []byte, instanceHash InstanceHash,
 	return sm.readBlocksChunkedInto(instanceHash, meta, encryptor, dst[:actualLen], startOffset)
 }
 
+// readDataFileInto decrypts blocks of the data file rc into dst, starting
+// at content offset startOffset within the file, in whichever format meta
+// says the object's data files are stored.  See decryptBlocksFromFile for
+// the remaining arguments.
+func (sm *StorageManager) readDataFileInto(rc *refCountedFile, meta *CacheMetadata, contentLength int64, encryptor *BlockEncryptor, dst []byte, startOffset int64, instanceHash InstanceHash, globalBlockNum0 uint32) (int, error) {
+	if meta.IsCompressed() {
+		return decryptCompressedBlocksFromFile(rc, contentLength, encryptor, dst, startOffset, sm.ptCache, instanceHash, globalBlockNum0)
+	}
+	return decryptBlocksFromFile(rc.File(), contentLength, encryptor, dst, startOffset, sm.ptCache, instanceHash, globalBlockNum0)
+}
+
 // decryptBlocksFromFile reads and decrypts blocks from a single file
 // directly into dst.  It uses a pooled read buffer for the encrypted
 // disk I/O.  globalBlockNum0 is the global block number corresponding to
@@ -1616,7 +1664,7 @@ func (sm *StorageManager) readBlocksChunked(instanceHash InstanceHash, meta *Cac
 
 // readBlocksChunkedInto reads blocks from a chunked object directly into dst.
 // It iterates over the chunks that overlap the requested range and delegates
-// each chunk's I/O to decryptBlocksFromFile.
+// each chunk's I/O to readDataFileInto.
 func (sm *StorageManager) readBlocksChunkedInto(instanceHash InstanceHash, meta *CacheMetadata, encryptor *BlockEncryptor, dst []byte, startOffset int64) (int, error) {
 	endOffset := startOffset + int64(len(dst))
 	if endOffset > meta.ContentLength {
@@ -1637,16 +1685,14 @@ func (sm *StorageManager) readBlocksChunkedInto(instanceHash InstanceHash, meta
 		chunkContentLen := chunkEnd - chunkStart + 1
 
 		// Get (or open) the file for this chunk.
-		var file *os.File
-		if rc, ok := openChunks.get(chunkIdx); ok {
-			file = rc.File()
-		} else {
-			rc, err := sm.getChunkFile(instanceHash, meta, chunkIdx)
+		rc, ok := openChunks.get(chunkIdx)
+		if !ok {
+			var err error
+			rc, err = sm.getChunkFile(instanceHash, meta, chunkIdx)
 			if err != nil {
 				return 0, errors.Wrapf(err, "failed to open chunk %d", chunkIdx)
 			}
 			openChunks.set(chunkIdx, rc)
-			file = rc.File()
 		}
 
 		// How many bytes to read from this chunk.
@@ -1656,9 +1702,9 @@ func (sm *StorageManager) readBlocksChunkedInto(instanceHash InstanceHash, meta
 		// Global block number of local block 0 in this chunk file.
 		globalBlockNum0 := ContentOffsetToBlock(chunkStart)
 
-		n, err := decryptBlocksFromFile(file, chunkContentLen, encryptor,
+		n, err := sm.readDataFileInto(rc, meta, chunkContentLen, encryptor,
 			dst[resultPos:resultPos+int(readLen)], chunkLocalOffset,
-			sm.ptCache, instanceHash, globalBlockNum0)
+			instanceHash, globalBlockNum0)
 		if err != nil {
 			return 0, err
 		}
@@ -1675,6 +1721,10 @@ func (sm *StorageManager) readBlocksChunkedInto(instanceHash InstanceHash, meta
 // on-disk data is too short.  Blocks that are not in the downloaded bitmap are
 // skipped.  A missing or unopenable file returns all requested blocks.
 func (sm *StorageManager) IdentifyCorruptBlocks(instanceHash InstanceHash, startBlock, endBlock uint32) ([]uint32, error) {
+	lock := sm.lockDataFiles(instanceHash)
+	lock.RLock()
+	defer lock.RUnlock()
+
 	dc, err := sm.getDiskCrypto(instanceHash)
 	if err != nil {
 		return nil, err
@@ -1687,9 +1737,13 @@ func (sm *StorageManager) IdentifyCorruptBlocks(instanceHash InstanceHash, start
 		return nil, errors.Wrap(err, "failed to get block state")
 	}
 
+	if meta.IsCompressed() {
+		return sm.identifyCorruptCompressedBlocks(instanceHash, meta, encryptor, blockState, startBlock, endBlock), nil
+	}
+
 	// Try the cached FD first; fall back to a direct open so we can
 	// detect "file missing" as a special case.
-	rc, fileErr := sm.getFile(instanceHash, meta.StorageID)
+	rc, fileErr := sm.getChunkFile(instanceHash, meta, 0)
 	if fileErr != nil {
 		// File missing entirely — every block the bitmap thinks is present
 		// is corrupt, not just those in the requested [startBlock, endBlock]
@@ -1819,8 +1873,10 @@ func (sm *StorageManager) Delete(instanceHash InstanceHash) error {
 		return errors.Wrap(err, "failed to get metadata")
 	}
 
-	// Delete from database (handles inline data, block state, LRU)
-	if err := sm.db.DeleteObject(instanceHash); err != nil {
+	// Delete from database (handles inline data, block state, LRU).
+	// freed describes the data no longer referenced by any instance.
+	freed, err := sm.db.deleteObject(instanceHash)
+	if err != nil {
 		return errors.Wrap(err, "failed to delete database entries")
 	}
 
@@ -1831,25 +1887,30 @@ func (sm *StorageManager) Delete(instanceHash InstanceHash) error {
 	}
 	sm.invalidateObjectCaches(instanceHash, chunkCount)
 
-	// If stored on disk, delete all chunk files
-	if meta != nil && meta.IsDisk() {
-		sm.deleteChunkFiles(instanceHash, meta.ContentLength, meta.StorageID, meta.ChunkSizeCode, meta.ChunkLocations)
+	// If stored on disk and no longer shared, delete all chunk files
+	if freed != nil && freed.IsDisk() {
+		sm.deleteChunkFiles(freed.DataHash(instanceHash), freed)
 	}
 
 	return nil
 }
 
-// deleteChunkFiles removes all chunk files for an object from disk.
-func (sm *StorageManager) deleteChunkFiles(instanceHash InstanceHash, contentLen int64, baseStorageID StorageID, chunkSizeCode ChunkSizeCode, chunkLocations []ChunkLocation) {
-	chunkCount := CalculateChunkCount(contentLen, chunkSizeCode)
+// deleteChunkFiles removes all chunk files of an object laid out as
+// described by layout from disk.  dataHash names the files; see
+// CacheMetadata.DataHash.
+func (sm *StorageManager) deleteChunkFiles(dataHash InstanceHash, layout *CacheMetadata) {
+	chunkCount := layout.ChunkCount()
 	for chunkIdx := 0; chunkIdx < chunkCount; chunkIdx++ {
+		// Drop any cached descriptor before unlinking the file.
+		sm.openFiles.Delete(chunkFileKey{instanceHash: dataHash, chunkIndex: chunkIdx, compressed: layout.IsCompressed()})
+
 		var storageID StorageID
 		if chunkIdx == 0 {
-			storageID = baseStorageID
-		} else if chunkIdx-1 < len(chunkLocations) {
-			storageID = chunkLocations[chunkIdx-1].StorageID
+			storageID = layout.StorageID
+		} else if chunkIdx-1 < len(layout.ChunkLocations) {
+			storageID = layout.ChunkLocations[chunkIdx-1].StorageID
 		} else {
-			storageID = baseStorageID // fallback
+			storageID = layout.StorageID // fallback
 		}
 
 		// Skip unallocated chunks (lazy allocation: StorageID 0 means no file was created)
@@ -1857,7 +1918,10 @@ func (sm *StorageManager) deleteChunkFiles(instanceHash InstanceHash, contentLen
 			continue
 		}
 
-		chunkPath := sm.getChunkPath(storageID, instanceHash, chunkIdx)
+		chunkPath := sm.getChunkPath(storageID, dataHash, chunkIdx)
+		if layout.IsCompressed() {
+			chunkPath = sm.getCompressedChunkPath(storageID, dataHash, chunkIdx)
+		}
 		if err := removeFileWithRetry(chunkPath); err != nil && !os.IsNotExist(err) {
 			log.Warnf("Failed to delete chunk %d file %s: %v", chunkIdx, chunkPath, err)
 		}
@@ -1879,7 +1943,23 @@ func (sm *StorageManager) EvictByLRU(storageID StorageID, namespaceID NamespaceI
 		// so the caller can log which objects were involved in a conflict.
 		return evicted, 0, errors.Wrap(err, "failed to evict objects by LRU")
 	}
+	return evicted, sm.removeEvicted(evicted), nil
+}
+
+// EvictInOrder is like EvictByLRU, but evicts objects in the order given
+// by an EvictionPolicy (see CacheDB.EvictInOrder).
+func (sm *StorageManager) EvictInOrder(storageID StorageID, namespaceID NamespaceID, order []InstanceHash, maxObjects int, maxBytes int64) ([]evictedObject, uint64, error) {
+	evicted, err := sm.db.EvictInOrder(storageID, namespaceID, order, maxObjects, maxBytes)
+	if err != nil {
+		return evicted, 0, errors.Wrap(err, "failed to evict objects in policy order")
+	}
+	return evicted, sm.removeEvicted(evicted), nil
+}
 
+// removeEvicted drops the in-memory state and data files of objects whose
+// DB entries were removed by an eviction transaction.  Returns the total
+// on-disk bytes freed.
+func (sm *StorageManager) removeEvicted(evicted []evictedObject) uint64 {
 	var totalFreed uint64
 	for _, obj := range evicted {
 		// Use PerDirectoryBytes to compute the actual on-disk size
@@ -1890,6 +1970,8 @@ func (sm *StorageManager) EvictByLRU(storageID StorageID, namespaceID NamespaceI
 			ContentLength:  obj.contentLen,
 			ChunkSizeCode:  obj.chunkSizeCode,
 			ChunkLocations: obj.chunkLocations,
+			Compression:    obj.compression,
+			StoredSizes:    obj.storedSizes,
 		}
 		for _, bytes := range meta.PerDirectoryBytes() {
 			totalFreed += uint64(bytes)
@@ -1898,13 +1980,14 @@ func (sm *StorageManager) EvictByLRU(storageID StorageID, namespaceID NamespaceI
 		// Remove all in-memory cached state for this object.
 		sm.invalidateObjectCaches(obj.instanceHash, CalculateChunkCount(obj.contentLen, obj.chunkSizeCode))
 
-		// Delete all chunk files from disk
+		// Delete all chunk files from disk (none when the data is
+		// still shared with other instances)
 		if obj.storageID != StorageIDInline {
-			sm.deleteChunkFiles(obj.instanceHash, obj.contentLen, obj.storageID, obj.chunkSizeCode, obj.chunkLocations)
+			sm.deleteChunkFiles(obj.dataHash, meta)
 		}
 	}
 
-	return evicted, totalFreed, nil
+	return totalFreed
 }
 
 // GetObjectSize returns the content length of a cached object
@@ -1984,11 +2067,18 @@ func (sm *StorageManager) NewObjectReader(instanceHash InstanceHash) (*ObjectRea
 		}
 		reader.inlineData = data
 	} else {
+		// Hold off rewrites of the data files until the reader has opened
+		// the ones named by the metadata it keeps.
+		lock := sm.lockDataFiles(instanceHash)
+		lock.RLock()
+		defer lock.RUnlock()
+
 		// Cache the encryptor so Read/ReadAt skip the getDiskCrypto TTL lookup.
 		dc, err := sm.getDiskCrypto(instanceHash)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to get disk crypto")
 		}
+		reader.meta = dc.meta
 		reader.encryptor = dc.encryptor
 
 		// Cache the block state so Read/ReadAt skip the GetSharedBlockState TTL lookup.
@@ -2002,9 +2092,9 @@ func (sm *StorageManager) NewObjectReader(instanceHash InstanceHash) (*ObjectRea
 		// underlying FD alive for the lifetime of the reader (even if the
 		// cache evicts the entry) and shares the handle with concurrent
 		// ReadBlocks callers.
-		rc, err := sm.getFile(instanceHash, meta.StorageID)
+		rc, err := sm.getChunkFile(instanceHash, dc.meta, 0)
 		if err != nil {
-			return nil, errors.Wrap(err, "failed to open object file")
+			return nil, err
 		}
 		reader.file = rc
 	}
@@ -2018,12 +2108,12 @@ func (sm *StorageManager) NewObjectReader(instanceHash InstanceHash) (*ObjectRea
 // GetSharedBlockState, getFile) and eliminates the intermediate buffer
 // allocation + copy.
 //
-// For chunked objects the method falls back to sm.readBlocksChunked (which
-// still benefits from the cached encryptor/blockState) because those reads
-// need to open multiple chunk files.
+// For chunked objects the method falls back to sm.ReadBlocksInto because
+// those reads open chunk files as they reach them, which must go through
+// the object's current metadata in case DeduplicateObject or CompressObject
+// has since replaced them.
 func (r *ObjectReader) readDiskDirect(dst []byte, off int64) (int, error) {
 	meta := r.meta
-	encryptor := r.encryptor
 	length := len(dst)
 
 	endOffset := off + int64(length)
@@ -2050,7 +2140,7 @@ func (r *ObjectReader) readDiskDirect(dst []byte, off int64) (int, error) {
 	if meta.IsChunked() {
 		// Chunked objects need to open multiple chunk files; use the
 		// "into" variant that writes directly into dst with pooled readBuf.
-		return r.sm.readBlocksChunkedInto(r.instanceHash, meta, encryptor, dst[:actualLen], off)
+		return r.sm.ReadBlocksInto(dst[:actualLen], r.instanceHash, off)
 	}
 
 	// Non-chunked fast path: read directly into dst using r.file.
@@ -2058,10 +2148,10 @@ func (r *ObjectReader) readDiskDirect(dst []byte, off int64) (int, error) {
 }
 
 // readSimpleInto reads and decrypts blocks from a non-chunked object directly
-// into dst.  It delegates to decryptBlocksFromFile which uses a pooled read
+// into dst.  It delegates to readDataFileInto which uses a pooled read
 // buffer.
 func (r *ObjectReader) readSimpleInto(dst []byte, off int64) (int, error) {
-	return decryptBlocksFromFile(r.file.File(), r.meta.ContentLength, r.encryptor, dst, off, r.sm.ptCache, r.instanceHash, 0)
+	return r.sm.readDataFileInto(r.file, r.meta, r.meta.ContentLength, r.encryptor, dst, off, r.instanceHash, 0)
 }
 
 // Read implements io.Reader
@@ -2296,6 +2386,9 @@ func (sm *StorageManager) NewBlockWriter(instanceHash InstanceHash, startBlock u
 	metaCopy := *dc.meta
 	meta := &metaCopy
 	encryptor := dc.encryptor
+	if meta.IsCompressed() {
+		return nil, errCompressedObject
+	}
 
 	// Get the shared block state so we can update it after each write
 	sharedState, err := sm.GetSharedBlockState(instanceHash)
@@ -2304,7 +2397,7 @@ func (sm *StorageManager) NewBlockWriter(instanceHash InstanceHash, startBlock u
 	}
 
 	// Open the file for read/write, creating it and its parent directory if necessary.
-	objectPath := sm.getObjectPathForDir(meta.StorageID, instanceHash)
+	objectPath := sm.getObjectPathForDir(meta.StorageID, meta.DataHash(instanceHash))
 	file, err := os.OpenFile(objectPath, os.O_RDWR|os.O_CREATE, 0600)
 	if errors.Is(err, os.ErrNotExist) {
 		if mkdirErr := os.MkdirAll(filepath.Dir(objectPath), 0750); mkdirErr != nil {
@@ -2542,7 +2635,7 @@ func (bw *BlockWriter) Close() error {
 	// the cache and then Release the writer's ref.
 	if bw.sm.fdCacheMaxSize > 0 {
 		bw.file.Acquire() // for the cache
-		bw.sm.openFiles.Set(chunkFileKey{instanceHash: bw.instanceHash, chunkIndex: 0}, bw.file, ttlcache.DefaultTTL)
+		bw.sm.openFiles.Set(chunkFileKey{instanceHash: bw.meta.DataHash(bw.instanceHash), chunkIndex: 0}, bw.file, ttlcache.DefaultTTL)
 	}
 	bw.file.Release() // writer's ref
 
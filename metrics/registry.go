@@ -14,3 +14,13 @@ var PelicanOSDFInstitutions = promauto.NewGauge(prometheus.GaugeOpts{
 	Name: "pelican_osdf_institution_count",
 	Help: "Total number of contributing institutions",
 })
+
+var PelicanRegistryReplicationLag = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "pelican_registry_replication_lag_seconds",
+	Help: "Seconds since the registry last had all changes from the change log of a peer registry.",
+}, []string{"peer"})
+
+var PelicanRegistryReplicationChanges = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "pelican_registry_replication_changes_total",
+	Help: "The number of changes from a peer registry applied to the registry's records.",
+}, []string{"peer"})
@@ -29,6 +29,8 @@ import (
 // These metrics use a "backend" label to differentiate between storage implementations
 // backend="xrootd" for XRootD OSS layer
 // backend="posixv2" for POSIXv2 native implementation
+// backend="s3" for the native S3 implementation
+// backend="https" for the native HTTPS-passthrough implementation
 
 var (
 	// Operation counters with backend label
@@ -358,6 +360,8 @@ var (
 const (
 	BackendXRootD  = "xrootd"
 	BackendPOSIXv2 = "posixv2"
+	BackendS3      = "s3"
+	BackendHTTPS   = "https"
 )
 
 // SlowOperationThreshold defines the duration above which an operation is considered "slow"
@@ -77,6 +77,21 @@ func (server *OriginServer) GetRequiredFeatures() []features.Feature {
 	return requiredFeatures
 }
 
+// Determine the optional features the origin offers to clients.  Unlike the
+// required features, these place no limitations on the other servers; the
+// director passes them on to clients so they can make use of them.
+func (server *OriginServer) GetFeatures() []string {
+	var features []string
+
+	// Both XRootD (via its TPC handler) and the natively-served origins
+	// accept third-party copies
+	if param.Origin_EnableThirdPartyCopy.GetBool() {
+		features = append(features, server_structs.FeatureThirdPartyCopy)
+	}
+
+	return features
+}
+
 func (server *OriginServer) CreateAdvertisement(name, id, originUrlStr, originWebUrl string, downtimes []server_structs.Downtime) (*server_structs.OriginAdvertiseV2, error) {
 	isGlobusBackend := param.Origin_StorageType.GetString() == string(server_structs.OriginStorageGlobus)
 	// Here we instantiate the namespaceAd slice, but we still need to define the namespace
@@ -178,13 +193,13 @@ func (server *OriginServer) CreateAdvertisement(name, id, originUrlStr, originWe
 	// Get the overall health status as reported by the origin.
 	status := metrics.GetHealthStatus().OverallStatus
 
-	// For POSIXv2 and SSH origins co-located with a director, DataURL (which becomes
+	// For natively-served origins (POSIXv2, SSH, native S3) co-located with a director, DataURL (which becomes
 	// ServerAd.URL) should have the /api/v1.0/origin/data prefix so the director redirects
 	// to the right endpoint. When the origin is standalone, older clients cannot handle
 	// non-empty resource paths, so we advertise the base URL.
 	// WebURL stays as the base server URL for web browser access.
 	dataUrlToAdvertise := originUrlStr
-	if (ost == server_structs.OriginStoragePosixv2 || ost == server_structs.OriginStorageSSH) && config.IsServerEnabled(server_structs.DirectorType) {
+	if !server_utils.OriginUsesXRootD() && config.IsServerEnabled(server_structs.DirectorType) {
 		if parsedUrl, err := url.Parse(originUrlStr); err == nil {
 			parsedUrl.Path = "/api/v1.0/origin/data"
 			dataUrlToAdvertise = parsedUrl.String()
@@ -217,6 +232,7 @@ func (server *OriginServer) CreateAdvertisement(name, id, originUrlStr, originWe
 		StorageType:         ost,
 		DisableDirectorTest: !param.Origin_DirectorTest.GetBool(),
 		RequiredFeatures:    featureNames,
+		Features:            server.GetFeatures(),
 		Status:              status,
 		Downtimes:           downtimes,
 	}
@@ -25,6 +25,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/pelicanplatform/pelican/param"
+	"github.com/pelicanplatform/pelican/server_structs"
 	"github.com/pelicanplatform/pelican/server_utils"
 )
 
@@ -71,3 +72,15 @@ func TestGetRequiredFeatures(t *testing.T) {
 		})
 	}
 }
+
+func TestGetFeatures(t *testing.T) {
+	server_utils.ResetTestState()
+	defer server_utils.ResetTestState()
+
+	oServer := &OriginServer{}
+	require.NoError(t, param.Origin_EnableThirdPartyCopy.Set(true))
+	assert.Equal(t, []string{server_structs.FeatureThirdPartyCopy}, oServer.GetFeatures())
+
+	require.NoError(t, param.Origin_EnableThirdPartyCopy.Set(false))
+	assert.Empty(t, oServer.GetFeatures())
+}
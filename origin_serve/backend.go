@@ -19,6 +19,7 @@
 package origin_serve
 
 import (
+	"context"
 	"os"
 	"strings"
 
@@ -61,25 +62,8 @@ type xattrChecksumAdapter struct {
 
 // GetDigests parses the Want-Digest header, opens an os.Root confined to
 // storagePrefix, and returns RFC 3230 formatted digest strings.
-func (a *xattrChecksumAdapter) GetDigests(relativePath string, wantDigest string) ([]string, error) {
-	// Parse wantDigest into ChecksumType values
-	var types []ChecksumType
-	for _, alg := range strings.Split(wantDigest, ",") {
-		alg = strings.TrimSpace(strings.ToLower(alg))
-		switch alg {
-		case "md5":
-			types = append(types, ChecksumTypeMD5)
-		case "sha", "sha-1", "sha1":
-			types = append(types, ChecksumTypeSHA1)
-		case "crc32":
-			types = append(types, ChecksumTypeCRC32)
-		case "crc32c":
-			types = append(types, ChecksumTypeCRC32C)
-		default:
-			continue
-		}
-	}
-
+func (a *xattrChecksumAdapter) GetDigests(_ context.Context, relativePath string, wantDigest string) ([]string, error) {
+	types := parseWantDigest(wantDigest)
 	if len(types) == 0 {
 		return nil, nil
 	}
@@ -100,3 +84,31 @@ func (a *xattrChecksumAdapter) GetDigests(relativePath string, wantDigest string
 	xc := &XattrChecksummer{}
 	return xc.GetChecksumsRFC3230(root, normalizedPath, types)
 }
+
+// parseWantDigest converts a Want-Digest header value (comma-separated
+// algorithm names, optionally with q-values) into the checksum types
+// this package knows how to produce.  Unknown algorithms are skipped.
+func parseWantDigest(wantDigest string) []ChecksumType {
+	var types []ChecksumType
+	for _, alg := range strings.Split(wantDigest, ",") {
+		alg, _, _ = strings.Cut(alg, ";")
+		alg = strings.TrimSpace(strings.ToLower(alg))
+		switch alg {
+		case "md5":
+			types = append(types, ChecksumTypeMD5)
+		case "sha", "sha-1", "sha1":
+			types = append(types, ChecksumTypeSHA1)
+		case "crc32":
+			types = append(types, ChecksumTypeCRC32)
+		case "crc32c":
+			types = append(types, ChecksumTypeCRC32C)
+		case "sha-256", "sha256":
+			types = append(types, ChecksumTypeSHA256)
+		case "adler32":
+			types = append(types, ChecksumTypeAdler32)
+		default:
+			continue
+		}
+	}
+	return types
+}
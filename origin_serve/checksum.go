@@ -28,9 +28,11 @@ package origin_serve
 import (
 	"crypto/md5"
 	"crypto/sha1"
+	"crypto/sha256"
 	"encoding/base64"
 	"encoding/binary"
 	"fmt"
+	"hash/adler32"
 	"hash/crc32"
 	"io"
 	"os"
@@ -58,16 +60,20 @@ type (
 )
 
 const (
-	ChecksumTypeMD5    ChecksumType = "md5"
-	ChecksumTypeSHA1   ChecksumType = "sha1"
-	ChecksumTypeCRC32  ChecksumType = "crc32"
-	ChecksumTypeCRC32C ChecksumType = "crc32c"
+	ChecksumTypeMD5     ChecksumType = "md5"
+	ChecksumTypeSHA1    ChecksumType = "sha1"
+	ChecksumTypeCRC32   ChecksumType = "crc32"
+	ChecksumTypeCRC32C  ChecksumType = "crc32c"
+	ChecksumTypeSHA256  ChecksumType = "sha256"
+	ChecksumTypeAdler32 ChecksumType = "adler32"
 
 	// Extended attribute names for checksums (XRootD format)
-	xattrMD5    = "user.XrdCks.md5"
-	xattrSHA1   = "user.XrdCks.sha1"
-	xattrCRC32  = "user.XrdCks.crc32"
-	xattrCRC32C = "user.XrdCks.crc32c"
+	xattrMD5     = "user.XrdCks.md5"
+	xattrSHA1    = "user.XrdCks.sha1"
+	xattrCRC32   = "user.XrdCks.crc32"
+	xattrCRC32C  = "user.XrdCks.crc32c"
+	xattrSHA256  = "user.XrdCks.sha256"
+	xattrAdler32 = "user.XrdCks.adler32"
 )
 
 var globalChecksummer Checksummer
@@ -88,7 +94,7 @@ func GetChecksummer() Checksummer {
 // isValidChecksumType checks if a checksum type string is valid
 func isValidChecksumType(checksumType ChecksumType) bool {
 	switch checksumType {
-	case ChecksumTypeMD5, ChecksumTypeSHA1, ChecksumTypeCRC32, ChecksumTypeCRC32C:
+	case ChecksumTypeMD5, ChecksumTypeSHA1, ChecksumTypeCRC32, ChecksumTypeCRC32C, ChecksumTypeSHA256, ChecksumTypeAdler32:
 		return true
 	default:
 		return false
@@ -122,7 +128,7 @@ func (xc *XattrChecksummer) GetChecksum(root *os.Root, filename string, checksum
 }
 
 // GetChecksumRFC3230 retrieves the checksum in RFC 3230 format (algorithm=value)
-// MD5 and SHA1 are base64-encoded, CRC32 is hex-encoded
+// MD5, SHA1 and SHA-256 are base64-encoded, CRC32 and Adler-32 are hex-encoded
 // Uses the provided os.Root to ensure all file operations stay within the root directory
 func (xc *XattrChecksummer) GetChecksumRFC3230(root *os.Root, filename string, checksumType ChecksumType) (string, error) {
 	checksum, err := xc.GetChecksum(root, filename, checksumType)
@@ -140,6 +146,10 @@ func (xc *XattrChecksummer) GetChecksumRFC3230(root *os.Root, filename string, c
 		return "crc32=" + checksum, nil
 	case ChecksumTypeCRC32C:
 		return "crc32c=" + checksum, nil
+	case ChecksumTypeSHA256:
+		return "sha-256=" + checksum, nil
+	case ChecksumTypeAdler32:
+		return "adler32=" + checksum, nil
 	default:
 		return "", errors.Errorf("unsupported checksum type for RFC 3230: %s", checksumType)
 	}
@@ -195,6 +205,10 @@ func getXattrName(checksumType ChecksumType) string {
 		return xattrCRC32
 	case ChecksumTypeCRC32C:
 		return xattrCRC32C
+	case ChecksumTypeSHA256:
+		return xattrSHA256
+	case ChecksumTypeAdler32:
+		return xattrAdler32
 	default:
 		return ""
 	}
@@ -242,6 +256,22 @@ func computeChecksumBytes(root *os.Root, filename string, checksumType ChecksumT
 		binary.BigEndian.PutUint32(val, hash.Sum32())
 		return val, nil
 
+	case ChecksumTypeSHA256:
+		hash := sha256.New()
+		if _, err := io.Copy(hash, file); err != nil {
+			return nil, errors.Wrap(err, "failed to compute SHA256 checksum")
+		}
+		return hash.Sum(nil), nil
+
+	case ChecksumTypeAdler32:
+		hash := adler32.New()
+		if _, err := io.Copy(hash, file); err != nil {
+			return nil, errors.Wrap(err, "failed to compute Adler32 checksum")
+		}
+		val := make([]byte, 4)
+		binary.BigEndian.PutUint32(val, hash.Sum32())
+		return val, nil
+
 	default:
 		return nil, errors.Errorf("unsupported checksum type: %s", checksumType)
 	}
@@ -366,6 +396,28 @@ func computeAndStoreChecksums(root *os.Root, filename string, types []ChecksumTy
 				},
 			})
 			writers = append(writers, h)
+
+		case ChecksumTypeSHA256:
+			h := sha256.New()
+			hashes = append(hashes, hashResult{
+				checksumType: t,
+				hash:         h,
+				finalize:     func() []byte { return h.Sum(nil) },
+			})
+			writers = append(writers, h)
+
+		case ChecksumTypeAdler32:
+			h := adler32.New()
+			hashes = append(hashes, hashResult{
+				checksumType: t,
+				hash:         h,
+				finalize: func() []byte {
+					val := make([]byte, 4)
+					binary.BigEndian.PutUint32(val, h.Sum32())
+					return val
+				},
+			})
+			writers = append(writers, h)
 		}
 	}
 
@@ -444,6 +496,10 @@ func defaultChecksumTypes() []ChecksumType {
 			result = append(result, ChecksumTypeCRC32)
 		case "crc32c":
 			result = append(result, ChecksumTypeCRC32C)
+		case "sha256":
+			result = append(result, ChecksumTypeSHA256)
+		case "adler32":
+			result = append(result, ChecksumTypeAdler32)
 		}
 	}
 	return result
@@ -452,9 +508,9 @@ func defaultChecksumTypes() []ChecksumType {
 // rfc3230Value converts raw checksum bytes into RFC 3230 value string for the given algorithm.
 func rfc3230Value(t ChecksumType, bytes []byte) string {
 	switch t {
-	case ChecksumTypeMD5, ChecksumTypeSHA1:
+	case ChecksumTypeMD5, ChecksumTypeSHA1, ChecksumTypeSHA256:
 		return base64.StdEncoding.EncodeToString(bytes)
-	case ChecksumTypeCRC32, ChecksumTypeCRC32C:
+	case ChecksumTypeCRC32, ChecksumTypeCRC32C, ChecksumTypeAdler32:
 		// Represent as lowercase hex
 		return fmt.Sprintf("%08x", binary.BigEndian.Uint32(bytes))
 	default:
@@ -473,6 +529,10 @@ func formatRFC3230(t ChecksumType, bytes []byte) string {
 		return "crc32=" + rfc3230Value(t, bytes)
 	case ChecksumTypeCRC32C:
 		return "crc32c=" + rfc3230Value(t, bytes)
+	case ChecksumTypeSHA256:
+		return "sha-256=" + rfc3230Value(t, bytes)
+	case ChecksumTypeAdler32:
+		return "adler32=" + rfc3230Value(t, bytes)
 	default:
 		return ""
 	}
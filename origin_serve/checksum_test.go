@@ -21,6 +21,7 @@
 package origin_serve
 
 import (
+	"crypto/sha256"
 	"encoding/base64"
 	"hash/crc32"
 	"io"
@@ -271,6 +272,30 @@ func TestRFC3230DigestFormat(t *testing.T) {
 				return len(parts[1]) == 8 && isHexString(parts[1])
 			},
 		},
+		{
+			name:         "SHA256 base64 encoding",
+			checksumType: ChecksumTypeSHA256,
+			prefix:       "sha-256=",
+			validate: func(value string) bool {
+				_, encoded, _ := strings.Cut(value, "=")
+				// SHA256 should be base64-encoded (32 bytes -> 44 base64 chars)
+				decoded, err := base64.StdEncoding.DecodeString(encoded)
+				return err == nil && len(decoded) == 32
+			},
+		},
+		{
+			name:         "Adler32 hex encoding",
+			checksumType: ChecksumTypeAdler32,
+			prefix:       "adler32=",
+			validate: func(value string) bool {
+				parts := strings.Split(value, "=")
+				if len(parts) != 2 {
+					return false
+				}
+				// Adler32 should be 8-digit hex
+				return len(parts[1]) == 8 && isHexString(parts[1])
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -360,6 +385,7 @@ func isHexString(s string) bool {
 
 // TestRFC3230ValueFormatting verifies rfc3230Value helper function
 func TestRFC3230ValueFormatting(t *testing.T) {
+	emptySHA256 := sha256.Sum256(nil)
 	tests := []struct {
 		name     string
 		algType  ChecksumType
@@ -378,6 +404,18 @@ func TestRFC3230ValueFormatting(t *testing.T) {
 			bytes:    []byte{0x12, 0x34, 0x56, 0x78},
 			expected: "12345678",
 		},
+		{
+			name:     "SHA256 base64",
+			algType:  ChecksumTypeSHA256,
+			bytes:    emptySHA256[:],
+			expected: "47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU=",
+		},
+		{
+			name:     "Adler32 hex",
+			algType:  ChecksumTypeAdler32,
+			bytes:    []byte{0x00, 0x00, 0x00, 0x01},
+			expected: "00000001",
+		},
 	}
 
 	for _, tt := range tests {
@@ -443,6 +481,8 @@ func TestChecksumTypeStringConversion(t *testing.T) {
 		{ChecksumTypeSHA1, "sha1"},
 		{ChecksumTypeCRC32, "crc32"},
 		{ChecksumTypeCRC32C, "crc32c"},
+		{ChecksumTypeSHA256, "sha256"},
+		{ChecksumTypeAdler32, "adler32"},
 	}
 
 	for _, tt := range tests {
@@ -454,7 +494,7 @@ func TestChecksumTypeStringConversion(t *testing.T) {
 
 // TestIsValidChecksumType verifies checksum type validation
 func TestIsValidChecksumType(t *testing.T) {
-	validTypes := []ChecksumType{ChecksumTypeMD5, ChecksumTypeSHA1, ChecksumTypeCRC32, ChecksumTypeCRC32C}
+	validTypes := []ChecksumType{ChecksumTypeMD5, ChecksumTypeSHA1, ChecksumTypeCRC32, ChecksumTypeCRC32C, ChecksumTypeSHA256, ChecksumTypeAdler32}
 	invalidTypes := []string{"md5c", "sha2", "crc", "unknown", "MD5", "SHA1"}
 
 	// Test valid types
@@ -499,3 +539,11 @@ func TestMergeWithDefault(t *testing.T) {
 	}
 	assert.True(t, foundDefault, "Default checksum type should be in merged list")
 }
+
+// TestParseWantDigest verifies the Want-Digest algorithm names map to checksum types
+func TestParseWantDigest(t *testing.T) {
+	assert.Equal(t,
+		[]ChecksumType{ChecksumTypeSHA256, ChecksumTypeAdler32, ChecksumTypeSHA256, ChecksumTypeCRC32C},
+		parseWantDigest("SHA-256;q=1, adler32;q=0.5, sha256, unknown, crc32c"))
+	assert.Empty(t, parseWantDigest("sha-512"))
+}
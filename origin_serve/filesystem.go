@@ -29,6 +29,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/spf13/afero"
 	"golang.org/x/net/webdav"
@@ -79,6 +80,7 @@ type (
 
 const (
 	userInfoKey contextKey = iota
+	uploadBodyKey
 )
 
 // setUserInfo stores user info in context
@@ -95,6 +97,67 @@ func getUserInfo(ctx context.Context) *userInfo {
 	return ui
 }
 
+// uploadBody wraps the body of a PUT so that backends streaming the data
+// to remote storage can tell, once the WebDAV handler closes the file,
+// whether the client's data arrived in full.  The handler reads the body
+// and closes the file on the same goroutine, so no locking is needed.
+type uploadBody struct {
+	io.ReadCloser
+	size int64 // declared Content-Length, or -1 if unknown
+	read int64
+	eof  bool
+	err  error
+}
+
+func (b *uploadBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.read += int64(n)
+	if err == io.EOF {
+		b.eof = true
+	} else if err != nil && b.err == nil {
+		b.err = err
+	}
+	return n, err
+}
+
+// setUploadBody wraps the body of req and records it in the request
+// context for uploadSize and uploadBodyError.
+func setUploadBody(req *http.Request) *http.Request {
+	body := &uploadBody{ReadCloser: req.Body, size: req.ContentLength}
+	req = req.WithContext(context.WithValue(req.Context(), uploadBodyKey, body))
+	req.Body = body
+	return req
+}
+
+// uploadSize returns the declared size of the upload carried by ctx, or
+// -1 if it is unknown.
+func uploadSize(ctx context.Context) int64 {
+	if body, ok := ctx.Value(uploadBodyKey).(*uploadBody); ok {
+		return body.size
+	}
+	return -1
+}
+
+// uploadBodyError returns a non-nil error if the upload carried by ctx
+// was not read to completion, either because reading the client's body
+// failed or because the handler stopped copying early.  Contexts without
+// a tracked body report no error.
+func uploadBodyError(ctx context.Context) error {
+	body, ok := ctx.Value(uploadBodyKey).(*uploadBody)
+	if !ok {
+		return nil
+	}
+	switch {
+	case body.err != nil:
+		return errors.Wrap(body.err, "failed to read upload body")
+	case !body.eof:
+		return errors.Errorf("upload body incomplete after %d bytes", body.read)
+	case body.size >= 0 && body.read != body.size:
+		return errors.Errorf("upload body has %d bytes, expected %d", body.read, body.size)
+	}
+	return nil
+}
+
 // usernameFromContext extracts the authenticated username from the context.
 // Returns an empty string when no user information is present.
 func usernameFromContext(ctx context.Context) string {
@@ -121,11 +184,17 @@ type operationMetrics struct {
 //
 //	defer trackOperation(opMetrics, username)()
 func trackOperation(om operationMetrics, username string) func() {
+	return trackBackendOperation(om, metrics.BackendPOSIXv2, username)
+}
+
+// trackBackendOperation is trackOperation for backends other than POSIXv2;
+// the backend argument becomes the metrics' backend label.
+func trackBackendOperation(om operationMetrics, backend, username string) func() {
 	start := time.Now()
 
 	// Increment operation counter
 	if om.total != nil {
-		om.total.WithLabelValues(metrics.BackendPOSIXv2, username).Inc()
+		om.total.WithLabelValues(backend, username).Inc()
 	}
 
 	return func() {
@@ -134,16 +203,16 @@ func trackOperation(om operationMetrics, username string) func() {
 
 		// Record operation timing
 		if om.timeHistogram != nil {
-			om.timeHistogram.WithLabelValues(metrics.BackendPOSIXv2, username).Observe(elapsedSec)
+			om.timeHistogram.WithLabelValues(backend, username).Observe(elapsedSec)
 		}
 
 		// Track slow operations (>2s)
 		if elapsed >= metrics.SlowOperationThreshold {
 			if om.slowTotal != nil {
-				om.slowTotal.WithLabelValues(metrics.BackendPOSIXv2, username).Inc()
+				om.slowTotal.WithLabelValues(backend, username).Inc()
 			}
 			if om.slowHistogram != nil {
-				om.slowHistogram.WithLabelValues(metrics.BackendPOSIXv2, username).Observe(elapsedSec)
+				om.slowHistogram.WithLabelValues(backend, username).Observe(elapsedSec)
 			}
 		}
 	}
@@ -343,7 +412,7 @@ func (af *aferoFile) Readdir(count int) ([]os.FileInfo, error) {
 		if err != nil {
 			return nil, err
 		}
-		af.dirEntries = entries
+		af.dirEntries = hideUploadStaging(entries)
 		af.dirOffset = 0
 	}
 
@@ -34,6 +34,8 @@ import (
 	"github.com/gin-gonic/gin"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/afero"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 	"golang.org/x/net/webdav"
 
 	"github.com/pelicanplatform/pelican/config"
@@ -44,6 +46,7 @@ import (
 	"github.com/pelicanplatform/pelican/server_utils"
 	"github.com/pelicanplatform/pelican/ssh_posixv2"
 	"github.com/pelicanplatform/pelican/token_scopes"
+	"github.com/pelicanplatform/pelican/tracing"
 	"github.com/pelicanplatform/pelican/utils"
 )
 
@@ -51,6 +54,7 @@ var (
 	backends           map[string]server_utils.OriginBackend
 	webdavHandlers     map[string]*webdav.Handler
 	exportPrefixMap    map[string]string // Maps federation prefix to storage prefix
+	resumableExports   map[string]bool   // Federation prefixes accepting resumable uploads
 	handlersRegistered bool              // Tracks whether handlers have been registered
 )
 
@@ -186,6 +190,7 @@ func ResetHandlers() {
 	backends = nil
 	webdavHandlers = nil
 	exportPrefixMap = nil
+	resumableExports = nil
 	handlersRegistered = false
 }
 
@@ -273,6 +278,14 @@ func authMiddleware() gin.HandlerFunc {
 
 		tokens := extractTokens(c.Request)
 		action := getActionFromMethod(c.Request.Method)
+		tpc, _ := thirdPartyCopyMode(c.Request)
+		if c.Request.Method == http.MethodHead && c.Request.Header.Get(utils.ResumableUploadIdHeader) != "" {
+			// Looking up the progress of an upload is part of writing the object
+			action = token_scopes.Wlcg_Storage_Create
+		} else if tpc == tpcPush {
+			// Pushing an object to another server only reads it from the origin
+			action = token_scopes.Wlcg_Storage_Read
+		}
 		resource := c.Request.URL.Path
 		// Strip the /api/v1.0/origin/data prefix if present
 		// This happens when the director is co-located with the origin
@@ -290,10 +303,12 @@ func authMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		// Check for public reads first
+		// Check for public reads first.  Third-party copies have the origin
+		// contact another server on the client's behalf, so they always need
+		// a token, even when they only read the object.
 		isPublicRead := false
 		exports := ac.exports.Load()
-		if exports != nil && action == token_scopes.Wlcg_Storage_Read {
+		if exports != nil && action == token_scopes.Wlcg_Storage_Read && tpc == tpcNone {
 			for _, export := range *exports {
 				if export.Capabilities.PublicReads && hasPathPrefix(resource, export.FederationPrefix) {
 					isPublicRead = true
@@ -559,6 +574,7 @@ func InitializeHandlers(ctx context.Context, exports []server_utils.OriginExport
 	backends = make(map[string]server_utils.OriginBackend)
 	webdavHandlers = make(map[string]*webdav.Handler)
 	exportPrefixMap = make(map[string]string) // Initialize the global map
+	resumableExports = make(map[string]bool)
 
 	// Get optional rate limit for testing
 	readRateLimit := param.Origin_TransferRateLimit.GetByteRate()
@@ -587,6 +603,20 @@ func InitializeHandlers(ctx context.Context, exports []server_utils.OriginExport
 				return fmt.Errorf("failed to create SSH backend for %s: %w", export.FederationPrefix, err)
 			}
 			backend = sshBackend
+		case server_structs.OriginStorageS3:
+			s3Backend, err := newS3Backend(export, param.Origin_S3ServiceUrl.GetString(),
+				param.Origin_S3Region.GetString(), param.Origin_S3UrlStyle.GetString())
+			if err != nil {
+				return fmt.Errorf("failed to create S3 backend for %s: %w", export.FederationPrefix, err)
+			}
+			backend = s3Backend
+		case server_structs.OriginStorageHTTPS:
+			httpsBackend, err := newHTTPSBackend(export, param.Origin_HttpServiceUrl.GetString(),
+				param.Origin_HttpAuthTokenFile.GetString(), param.Origin_HttpTokenPassthrough.GetBool())
+			if err != nil {
+				return fmt.Errorf("failed to create HTTPS backend for %s: %w", export.FederationPrefix, err)
+			}
+			backend = httpsBackend
 		default:
 			// Use local filesystem (POSIXv2)
 			// Create a filesystem for this export with auto-directory creation
@@ -641,7 +671,15 @@ func InitializeHandlers(ctx context.Context, exports []server_utils.OriginExport
 
 		backends[export.FederationPrefix] = backend
 		webdavHandlers[export.FederationPrefix] = handler
-		exportPrefixMap[export.FederationPrefix] = export.StoragePrefix
+		// S3 and HTTPS exports have no local storage root; leaving the
+		// prefix empty makes the GET/PUT ETag helpers defer to the WebDAV
+		// handler, which takes ETags from the backend's own file info.
+		if storageType != server_structs.OriginStorageS3 && storageType != server_structs.OriginStorageHTTPS {
+			exportPrefixMap[export.FederationPrefix] = export.StoragePrefix
+		}
+		// Resumable uploads stage chunks in a file that is written at
+		// arbitrary offsets, which only local storage supports
+		resumableExports[export.FederationPrefix] = storageType == server_structs.OriginStoragePosixv2
 		log.Infof("Initialized WebDAV handler for %s -> %s (storage: %s)", export.FederationPrefix, export.StoragePrefix, storageType)
 	}
 
@@ -687,6 +725,21 @@ func RegisterHandlers(engine *gin.Engine, directorEnabled bool) error {
 
 		// Create a handler function for all requests
 		handleRequest := func(c *gin.Context) {
+			spanCtx, span := tracing.StartSpan(c.Request.Context(), "origin.serve",
+				attribute.String("pelican.export_prefix", prefix),
+				attribute.String("url.path", c.Param("path")),
+				attribute.String("http.request.method", c.Request.Method),
+			)
+			c.Request = c.Request.WithContext(spanCtx)
+			defer func() {
+				status := c.Writer.Status()
+				span.SetAttributes(attribute.Int("http.response.status_code", status))
+				if status >= http.StatusInternalServerError {
+					span.SetStatus(codes.Error, http.StatusText(status))
+				}
+				span.End()
+			}()
+
 			// Ask the backend whether it can serve requests right now.
 			if err := backend.CheckAvailability(); err != nil {
 				statusCode := http.StatusServiceUnavailable
@@ -699,13 +752,21 @@ func RegisterHandlers(engine *gin.Engine, directorEnabled bool) error {
 
 			// Get the path relative to the export (strip the federation prefix)
 			wildcardPath := c.Param("path")
+			if isUploadStaging(wildcardPath) {
+				// Staging files of unfinished uploads are not objects
+				c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "object not found"})
+				return
+			}
 
 			// Stash client tracing headers (X-Pelican-JobId,
 			// X-Pelican-Timeout) in the request context so backends
 			// that forward requests can propagate them.
 			req := server_utils.StashPelicanHeaders(c.Request)
 
-			if c.Request.Method == http.MethodHead {
+			if c.Request.Method == http.MethodHead && c.Request.Header.Get(utils.ResumableUploadIdHeader) != "" {
+				// Clients look up where to resume an upload before sending chunks
+				handleResumableUploadStatus(c, handler, wildcardPath, resumableStoragePrefix(prefix))
+			} else if c.Request.Method == http.MethodHead {
 				// For HEAD requests, pass the original request to the WebDAV handler
 				// (it needs the full URL so its Prefix stripping works correctly).
 				// wildcardPath is used only for checksum lookup on the filesystem.
@@ -713,9 +774,15 @@ func RegisterHandlers(engine *gin.Engine, directorEnabled bool) error {
 			} else if c.Request.Method == http.MethodGet {
 				// For GET requests, add ETag header based on file metadata
 				handleGetWithETag(c, handler, req, wildcardPath, exportPrefixMap[prefix])
+			} else if c.Request.Method == http.MethodPut && c.Request.Header.Get("Content-Range") != "" {
+				// Chunks of resumable uploads are staged until the object is complete
+				handleResumablePut(c, handler, req, wildcardPath, resumableStoragePrefix(prefix))
 			} else if c.Request.Method == http.MethodPut {
 				// For PUT requests, return ETag of the newly written file
 				handlePutWithETag(c, handler, req, wildcardPath, exportPrefixMap[prefix])
+			} else if mode, remote := thirdPartyCopyMode(req); mode != tpcNone {
+				// COPY requests naming another server are HTTP third-party copies
+				handleThirdPartyCopy(c, handler, mode, remote, wildcardPath)
 			} else {
 				// For all other methods (including PROPFIND), pass the original request
 				// to the WebDAV handler. The handler's Prefix field ensures it strips
@@ -756,7 +823,7 @@ func handleHeadWithChecksum(c *gin.Context, handler *webdav.Handler, req *http.R
 	// Ask the backend for digest values.  Backends that do not support
 	// checksums (e.g. SSH) return a nil Checksummer.
 	if cs := backend.Checksummer(); cs != nil {
-		digests, err := cs.GetDigests(relativePath, wantDigest)
+		digests, err := cs.GetDigests(req.Context(), relativePath, wantDigest)
 		if err != nil {
 			log.Debugf("Failed to compute checksums for %s: %v", relativePath, err)
 		} else if len(digests) > 0 {
@@ -784,28 +851,37 @@ func computeETag(modTime int64, size int64) string {
 // we have had a chance to stat the new file.
 func handlePutWithETag(c *gin.Context, handler *webdav.Handler, req *http.Request, relativePath string, storagePrefix string) {
 	dw := &deferredHeaderWriter{ResponseWriter: c.Writer}
-	handler.ServeHTTP(dw, req)
+	handler.ServeHTTP(dw, setUploadBody(req))
 
 	// On success (2xx), stat the written file and compute ETag.
 	if dw.code >= 200 && dw.code < 300 {
-		root, err := os.OpenRoot(storagePrefix)
-		if err == nil {
-			defer root.Close()
-			normalizedPath := strings.TrimPrefix(relativePath, "/")
-			if normalizedPath == "" {
-				normalizedPath = "."
-			}
-			if info, statErr := root.Stat(normalizedPath); statErr == nil {
-				etag := computeETag(info.ModTime().UnixNano(), info.Size())
-				dw.Header().Set("ETag", etag)
-			}
-		}
+		setUploadETag(dw.Header(), relativePath, storagePrefix)
 	}
 
 	// Flush the deferred status code (and any body) to the client.
 	dw.Flush()
 }
 
+// setUploadETag sets the ETag header of a newly written file, if the export
+// has local storage to stat it in
+func setUploadETag(header http.Header, relativePath string, storagePrefix string) {
+	if storagePrefix == "" {
+		return
+	}
+	root, err := os.OpenRoot(storagePrefix)
+	if err != nil {
+		return
+	}
+	defer root.Close()
+	normalizedPath := strings.TrimPrefix(relativePath, "/")
+	if normalizedPath == "" {
+		normalizedPath = "."
+	}
+	if info, statErr := root.Stat(normalizedPath); statErr == nil {
+		header.Set("ETag", computeETag(info.ModTime().UnixNano(), info.Size()))
+	}
+}
+
 // deferredHeaderWriter wraps http.ResponseWriter to defer the WriteHeader call
 // until Flush is invoked.  This lets callers inspect / amend headers after the
 // upstream handler has finished but before the response is sent to the client.
@@ -846,6 +922,12 @@ func (d *deferredHeaderWriter) Flush() {
 // - If-None-Match compares ETags (strong or weak comparison depending on method)
 // - If-Modified-Since compares modification times (only for GET/HEAD)
 func handleGetWithETag(c *gin.Context, handler *webdav.Handler, req *http.Request, relativePath string, storagePrefix string) {
+	// Backends without local storage supply their own ETags
+	if storagePrefix == "" {
+		handler.ServeHTTP(c.Writer, req)
+		return
+	}
+
 	// Use os.Root to prevent symlink attacks
 	root, err := os.OpenRoot(storagePrefix)
 	if err != nil {
@@ -0,0 +1,759 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package origin_serve
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/webdav"
+
+	"github.com/pelicanplatform/pelican/config"
+	"github.com/pelicanplatform/pelican/metrics"
+	"github.com/pelicanplatform/pelican/server_utils"
+	"github.com/pelicanplatform/pelican/tracing"
+)
+
+// ---------------------------------------------------------------------------
+// httpsBackend — OriginBackend proxying to an upstream HTTP(S) server
+// ---------------------------------------------------------------------------
+
+// davPropfindBody requests only the properties needed to build an
+// os.FileInfo, keeping upstream PROPFIND responses small.
+const davPropfindBody = `<?xml version="1.0" encoding="utf-8"?>
+<D:propfind xmlns:D="DAV:"><D:prop>
+<D:resourcetype/><D:getcontentlength/><D:getlastmodified/><D:getetag/><D:getcontenttype/>
+</D:prop></D:propfind>`
+
+// httpsBackend serves an export by forwarding each filesystem operation
+// to an upstream HTTP(S) server rooted at Origin.HttpServiceUrl plus the
+// export's storage prefix.  Reads become ranged GETs, metadata and
+// listings come from WebDAV PROPFIND (falling back to HEAD for servers
+// that do not speak WebDAV), and writes are streamed as PUTs.
+//
+// Upstream requests carry the configured token from tokenFile when set;
+// otherwise, if passthrough is enabled, they carry the client's own
+// Authorization header.
+type httpsBackend struct {
+	client      *http.Client
+	baseUrl     *url.URL
+	tokenFile   string
+	passthrough bool
+}
+
+// newHTTPSBackend creates an HTTPS backend for the given export.
+// serviceUrl and tokenFile come from Origin.HttpServiceUrl and
+// Origin.HttpAuthTokenFile; passthrough from Origin.HttpTokenPassthrough.
+func newHTTPSBackend(export server_utils.OriginExport, serviceUrl, tokenFile string, passthrough bool) (*httpsBackend, error) {
+	if serviceUrl == "" {
+		return nil, errors.New("an HTTP service URL is required for HTTPS exports")
+	}
+	baseUrl, err := url.Parse(strings.TrimSuffix(serviceUrl, "/"))
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to parse HTTP service URL %s", serviceUrl)
+	}
+	if baseUrl.Scheme != "http" && baseUrl.Scheme != "https" {
+		return nil, errors.Errorf("HTTP service URL %s must use the http or https scheme", serviceUrl)
+	}
+	baseUrl.Path = strings.TrimSuffix(baseUrl.Path+path.Clean("/"+export.StoragePrefix), "/")
+	baseUrl.RawPath = ""
+
+	return &httpsBackend{
+		client:      &http.Client{Transport: config.GetTransport()},
+		baseUrl:     baseUrl,
+		tokenFile:   tokenFile,
+		passthrough: passthrough,
+	}, nil
+}
+
+func (b *httpsBackend) CheckAvailability() error      { return nil }
+func (b *httpsBackend) FileSystem() webdav.FileSystem { return b }
+func (b *httpsBackend) Checksummer() server_utils.OriginChecksummer {
+	return &httpsChecksummer{backend: b}
+}
+
+// upstreamUrl returns the upstream URL for a WebDAV path.
+func (b *httpsBackend) upstreamUrl(name string) *url.URL {
+	u := *b.baseUrl
+	u.Path += path.Clean("/" + name)
+	return &u
+}
+
+// newRequest builds an upstream request for name, attaching credentials
+// and the stashed Pelican tracing headers from ctx.
+func (b *httpsBackend) newRequest(ctx context.Context, method, name string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, b.upstreamUrl(name).String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	tracing.InjectHeaders(ctx, req.Header)
+	h := server_utils.PelicanHeadersFromContext(ctx)
+	if b.tokenFile != "" {
+		// Re-read on every request so that tokens refreshed on disk are
+		// picked up without restarting the origin.
+		tok, err := os.ReadFile(b.tokenFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read upstream token file %s", b.tokenFile)
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(tok)))
+	} else if b.passthrough && h != nil && h.Authorization != "" {
+		req.Header.Set("Authorization", h.Authorization)
+	}
+	if h != nil {
+		if h.JobId != "" {
+			req.Header.Set("X-Pelican-JobId", h.JobId)
+		}
+		if h.Timeout != "" {
+			req.Header.Set("X-Pelican-Timeout", h.Timeout)
+		}
+	}
+	return req, nil
+}
+
+// translateHTTPStatus maps an upstream status code onto the os errors
+// the WebDAV handler understands.  It returns nil for 2xx responses.
+func translateHTTPStatus(resp *http.Response) error {
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusNotFound, resp.StatusCode == http.StatusConflict:
+		// 409 means a missing parent collection; the WebDAV handler
+		// turns os.ErrNotExist from a write back into 409.
+		return os.ErrNotExist
+	case resp.StatusCode == http.StatusUnauthorized, resp.StatusCode == http.StatusForbidden:
+		return os.ErrPermission
+	default:
+		return fmt.Errorf("upstream %s %s returned %s", resp.Request.Method, resp.Request.URL.Redacted(), resp.Status)
+	}
+}
+
+// do sends req and returns the response if it has a 2xx status;
+// otherwise the body is drained and a translated error returned.
+func (b *httpsBackend) do(req *http.Request) (*http.Response, error) {
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := translateHTTPStatus(resp); err != nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		return nil, err
+	}
+	return resp, nil
+}
+
+// Mkdir issues an MKCOL for name.
+func (b *httpsBackend) Mkdir(ctx context.Context, name string, perm os.FileMode) error {
+	defer trackBackendOperation(operationMetrics{
+		total:         metrics.StorageMkdirsTotal,
+		timeHistogram: metrics.StorageMkdirTime,
+		slowTotal:     metrics.StorageSlowMkdirsTotal,
+		slowHistogram: metrics.StorageSlowMkdirTime,
+	}, metrics.BackendHTTPS, usernameFromContext(ctx))()
+
+	req, err := b.newRequest(ctx, "MKCOL", name, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusMethodNotAllowed {
+		// RFC 4918: MKCOL on an existing resource fails with 405.
+		return os.ErrExist
+	}
+	return translateHTTPStatus(resp)
+}
+
+// OpenFile returns a lazily-read handle for reads, or a handle that
+// streams writes into an upstream PUT.
+func (b *httpsBackend) OpenFile(ctx context.Context, name string, flag int, perm os.FileMode) (webdav.File, error) {
+	defer trackBackendOperation(operationMetrics{
+		total:         metrics.StorageOpensTotal,
+		timeHistogram: metrics.StorageOpenTime,
+		slowTotal:     metrics.StorageSlowOpensTotal,
+		slowHistogram: metrics.StorageSlowOpenTime,
+	}, metrics.BackendHTTPS, usernameFromContext(ctx))()
+
+	if flag&(os.O_WRONLY|os.O_RDWR|os.O_CREATE|os.O_TRUNC) != 0 {
+		// As with the POSIX backend, a write to a collection or into a
+		// missing parent must surface as "not exist" so the WebDAV
+		// handler returns 409.  The upstream's own 409 would only arrive
+		// once the body had been streamed.
+		if info, err := b.Stat(ctx, name); err == nil && info.IsDir() {
+			return nil, os.ErrNotExist
+		}
+		if parent, err := b.Stat(ctx, path.Dir(path.Clean("/"+name))); err != nil || !parent.IsDir() {
+			return nil, os.ErrNotExist
+		}
+		return b.startUpload(ctx, name)
+	}
+
+	info, err := b.Stat(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return &httpsFile{
+		backend: b,
+		ctx:     ctx,
+		name:    name,
+		info:    info.(*httpsFileInfo),
+	}, nil
+}
+
+// RemoveAll issues a DELETE for name.  WebDAV servers remove
+// collections recursively.
+func (b *httpsBackend) RemoveAll(ctx context.Context, name string) error {
+	defer trackBackendOperation(operationMetrics{
+		total:         metrics.StorageUnlinksTotal,
+		timeHistogram: metrics.StorageUnlinkTime,
+		slowTotal:     metrics.StorageSlowUnlinksTotal,
+		slowHistogram: metrics.StorageSlowUnlinkTime,
+	}, metrics.BackendHTTPS, usernameFromContext(ctx))()
+
+	req, err := b.newRequest(ctx, http.MethodDelete, name, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := b.do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Rename issues a MOVE from oldName to newName.
+func (b *httpsBackend) Rename(ctx context.Context, oldName, newName string) error {
+	defer trackBackendOperation(operationMetrics{
+		total:         metrics.StorageRenamesTotal,
+		timeHistogram: metrics.StorageRenameTime,
+		slowTotal:     metrics.StorageSlowRenamesTotal,
+		slowHistogram: metrics.StorageSlowRenameTime,
+	}, metrics.BackendHTTPS, usernameFromContext(ctx))()
+
+	req, err := b.newRequest(ctx, "MOVE", oldName, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Destination", b.upstreamUrl(newName).String())
+	req.Header.Set("Overwrite", "T")
+	resp, err := b.do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Stat issues a Depth: 0 PROPFIND for name.  Upstreams that reject
+// PROPFIND are plain HTTP servers; for those a HEAD request is used and
+// every resource is reported as a file.
+func (b *httpsBackend) Stat(ctx context.Context, name string) (os.FileInfo, error) {
+	defer trackBackendOperation(operationMetrics{
+		total:         metrics.StorageStatsTotal,
+		timeHistogram: metrics.StorageStatTime,
+		slowTotal:     metrics.StorageSlowStatsTotal,
+		slowHistogram: metrics.StorageSlowStatTime,
+	}, metrics.BackendHTTPS, usernameFromContext(ctx))()
+
+	info, children, err := b.propfind(ctx, name, "0")
+	if errors.Is(err, webdav.ErrNotImplemented) {
+		return b.head(ctx, name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if info == nil {
+		// Tolerate servers whose href does not round-trip exactly;
+		// a Depth: 0 response only ever describes the target.
+		if len(children) == 0 {
+			return nil, os.ErrNotExist
+		}
+		info = children[0]
+	}
+	info.name = path.Base(path.Clean("/" + name))
+	return info, nil
+}
+
+// head builds a file info from the headers of a HEAD response.
+func (b *httpsBackend) head(ctx context.Context, name string) (*httpsFileInfo, error) {
+	req, err := b.newRequest(ctx, http.MethodHead, name, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := b.do(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	info := &httpsFileInfo{
+		name:        path.Base(path.Clean("/" + name)),
+		size:        resp.ContentLength,
+		etag:        resp.Header.Get("ETag"),
+		contentType: resp.Header.Get("Content-Type"),
+	}
+	if info.size < 0 {
+		info.size = 0
+	}
+	if t, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+		info.modTime = t
+	} else {
+		info.modTime = time.Now()
+	}
+	return info, nil
+}
+
+// davMultistatus is the subset of a WebDAV multistatus response used to
+// build file infos.
+type davMultistatus struct {
+	Responses []struct {
+		Href     string `xml:"DAV: href"`
+		Propstat []struct {
+			Status string `xml:"DAV: status"`
+			Prop   struct {
+				ResourceType struct {
+					Collection *struct{} `xml:"DAV: collection"`
+				} `xml:"DAV: resourcetype"`
+				ContentLength string `xml:"DAV: getcontentlength"`
+				LastModified  string `xml:"DAV: getlastmodified"`
+				ETag          string `xml:"DAV: getetag"`
+				ContentType   string `xml:"DAV: getcontenttype"`
+			} `xml:"DAV: prop"`
+		} `xml:"DAV: propstat"`
+	} `xml:"DAV: response"`
+}
+
+// propfind issues a PROPFIND with the given depth and returns the file
+// info for name itself (nil if the response did not include it) along
+// with one file info per other entry.  It returns
+// webdav.ErrNotImplemented when the upstream does not support PROPFIND.
+func (b *httpsBackend) propfind(ctx context.Context, name, depth string) (self *httpsFileInfo, children []*httpsFileInfo, err error) {
+	req, err := b.newRequest(ctx, "PROPFIND", name, strings.NewReader(davPropfindBody))
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Depth", depth)
+	req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusNotImplemented {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil, nil, webdav.ErrNotImplemented
+	}
+	if err := translateHTTPStatus(resp); err != nil {
+		return nil, nil, err
+	}
+
+	var ms davMultistatus
+	if err := xml.NewDecoder(resp.Body).Decode(&ms); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to parse upstream PROPFIND response")
+	}
+
+	selfPath := strings.TrimSuffix(b.upstreamUrl(name).Path, "/")
+	for _, r := range ms.Responses {
+		hrefUrl, err := url.Parse(r.Href)
+		if err != nil {
+			log.Debugf("Skipping unparseable href %q in upstream PROPFIND response", r.Href)
+			continue
+		}
+		hrefPath := strings.TrimSuffix(hrefUrl.Path, "/")
+		info := &httpsFileInfo{name: path.Base(hrefPath), modTime: time.Now()}
+		for _, ps := range r.Propstat {
+			if fields := strings.Fields(ps.Status); len(fields) < 2 || fields[1] != "200" {
+				continue
+			}
+			info.isDir = ps.Prop.ResourceType.Collection != nil
+			if size, err := strconv.ParseInt(ps.Prop.ContentLength, 10, 64); err == nil {
+				info.size = size
+			}
+			if t, err := http.ParseTime(ps.Prop.LastModified); err == nil {
+				info.modTime = t
+			}
+			info.etag = ps.Prop.ETag
+			info.contentType = ps.Prop.ContentType
+		}
+		if hrefPath == selfPath {
+			self = info
+		} else {
+			children = append(children, info)
+		}
+	}
+	return self, children, nil
+}
+
+// listDir returns the immediate children of a collection.
+func (b *httpsBackend) listDir(ctx context.Context, name string) ([]os.FileInfo, error) {
+	_, children, err := b.propfind(ctx, name, "1")
+	if errors.Is(err, webdav.ErrNotImplemented) {
+		return nil, errors.New("upstream server does not support directory listings")
+	}
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]os.FileInfo, 0, len(children))
+	for _, info := range children {
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// startUpload begins a streaming PUT for name.  Data passed to Write is
+// piped into the request body; Close waits for the upstream response and
+// records the returned ETag.
+func (b *httpsBackend) startUpload(ctx context.Context, name string) (*httpsUploadFile, error) {
+	pr, pw := io.Pipe()
+	req, err := b.newRequest(ctx, http.MethodPut, name, pr)
+	if err != nil {
+		return nil, err
+	}
+	// Forward the client's declared length so the upstream can also
+	// reject a body that ends early.
+	if size := uploadSize(ctx); size > 0 {
+		req.ContentLength = size
+	}
+	f := &httpsUploadFile{
+		ctx:  ctx,
+		pw:   pw,
+		done: make(chan struct{}),
+		info: &httpsFileInfo{name: path.Base(name), modTime: time.Now()},
+	}
+	go func() {
+		defer close(f.done)
+		resp, err := b.do(req)
+		if err != nil {
+			f.err = err
+			pr.CloseWithError(err)
+			metrics.StorageOpenErrorsTotal.WithLabelValues(metrics.BackendHTTPS, usernameFromContext(ctx)).Inc()
+			log.Debugf("Upstream PUT of %s failed: %v", name, err)
+			return
+		}
+		resp.Body.Close()
+		f.info.etag = resp.Header.Get("ETag")
+	}()
+	return f, nil
+}
+
+// ---------------------------------------------------------------------------
+// httpsFile — read handle for an upstream resource
+// ---------------------------------------------------------------------------
+
+// httpsFile reads an upstream resource with ranged GETs.  The first Read
+// after a Seek opens a GET starting at the current offset; sequential
+// reads then stream from that single response.
+type httpsFile struct {
+	backend *httpsBackend
+	ctx     context.Context
+	name    string
+	info    *httpsFileInfo
+
+	body   io.ReadCloser
+	offset int64
+
+	dirEntries []os.FileInfo
+	dirOffset  int
+	dirLoaded  bool
+}
+
+func (f *httpsFile) Close() error {
+	if f.body != nil {
+		err := f.body.Close()
+		f.body = nil
+		return err
+	}
+	return nil
+}
+
+// openBody starts a GET from the current offset.  Upstreams that ignore
+// the Range header answer 200 with the whole resource, in which case
+// the bytes before the offset are discarded.
+func (f *httpsFile) openBody() error {
+	req, err := f.backend.newRequest(f.ctx, http.MethodGet, f.name, nil)
+	if err != nil {
+		return err
+	}
+	if f.offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", f.offset))
+	}
+	resp, err := f.backend.do(req)
+	if err != nil {
+		return err
+	}
+	if f.offset > 0 && resp.StatusCode != http.StatusPartialContent {
+		if _, err := io.CopyN(io.Discard, resp.Body, f.offset); err != nil {
+			resp.Body.Close()
+			return err
+		}
+	}
+	f.body = resp.Body
+	return nil
+}
+
+func (f *httpsFile) Read(p []byte) (int, error) {
+	if f.info.isDir {
+		return 0, errors.New("is a directory")
+	}
+	if f.offset >= f.info.size {
+		return 0, io.EOF
+	}
+	if f.body == nil {
+		if err := f.openBody(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := f.body.Read(p)
+	f.offset += int64(n)
+	return n, err
+}
+
+func (f *httpsFile) Seek(offset int64, whence int) (int64, error) {
+	var newOffset int64
+	switch whence {
+	case io.SeekStart:
+		newOffset = offset
+	case io.SeekCurrent:
+		newOffset = f.offset + offset
+	case io.SeekEnd:
+		newOffset = f.info.size + offset
+	default:
+		return 0, fmt.Errorf("invalid whence: %d", whence)
+	}
+	if newOffset < 0 {
+		return 0, errors.New("negative position")
+	}
+	if newOffset != f.offset && f.body != nil {
+		f.body.Close()
+		f.body = nil
+	}
+	f.offset = newOffset
+	return newOffset, nil
+}
+
+func (f *httpsFile) Write(p []byte) (int, error) {
+	return 0, errors.New("file not opened for writing")
+}
+
+func (f *httpsFile) Readdir(count int) ([]os.FileInfo, error) {
+	if !f.info.isDir {
+		return nil, errors.New("not a directory")
+	}
+	if !f.dirLoaded {
+		entries, err := f.backend.listDir(f.ctx, f.name)
+		if err != nil {
+			return nil, err
+		}
+		f.dirEntries = hideUploadStaging(entries)
+		f.dirLoaded = true
+	}
+
+	if count <= 0 {
+		result := f.dirEntries[f.dirOffset:]
+		f.dirOffset = len(f.dirEntries)
+		return result, nil
+	}
+	remaining := len(f.dirEntries) - f.dirOffset
+	if remaining == 0 {
+		return nil, io.EOF
+	}
+	count = min(count, remaining)
+	result := f.dirEntries[f.dirOffset : f.dirOffset+count]
+	f.dirOffset += count
+	return result, nil
+}
+
+func (f *httpsFile) Stat() (os.FileInfo, error) {
+	return f.info, nil
+}
+
+// ---------------------------------------------------------------------------
+// httpsUploadFile — write handle streaming into an upstream PUT
+// ---------------------------------------------------------------------------
+
+// httpsUploadFile is returned by OpenFile for writes.  As with S3
+// uploads, Stat answers from local state while the PUT is in flight and
+// Close fills in the upstream ETag on the same FileInfo.
+type httpsUploadFile struct {
+	ctx  context.Context
+	pw   *io.PipeWriter
+	done chan struct{}
+	err  error
+
+	closeOnce sync.Once
+	info      *httpsFileInfo
+}
+
+func (f *httpsUploadFile) Write(p []byte) (int, error) {
+	n, err := f.pw.Write(p)
+	f.info.size += int64(n)
+	f.info.modTime = time.Now()
+	return n, err
+}
+
+// Close completes the upstream PUT.  If the client's body was not
+// received in full, the pipe is closed with an error instead so the
+// request is aborted rather than ending as a short, successful upload.
+func (f *httpsUploadFile) Close() error {
+	f.closeOnce.Do(func() {
+		if err := uploadBodyError(f.ctx); err != nil {
+			f.pw.CloseWithError(err)
+			<-f.done
+			if f.err == nil {
+				f.err = err
+			}
+			return
+		}
+		f.pw.Close()
+		<-f.done
+	})
+	return f.err
+}
+
+func (f *httpsUploadFile) Read(p []byte) (int, error) {
+	return 0, errors.New("file not opened for reading")
+}
+
+func (f *httpsUploadFile) Seek(offset int64, whence int) (int64, error) {
+	return 0, errors.New("seek not supported on upstream uploads")
+}
+
+func (f *httpsUploadFile) Readdir(count int) ([]os.FileInfo, error) {
+	return nil, errors.New("not a directory")
+}
+
+func (f *httpsUploadFile) Stat() (os.FileInfo, error) {
+	return f.info, nil
+}
+
+// ---------------------------------------------------------------------------
+// httpsFileInfo
+// ---------------------------------------------------------------------------
+
+// httpsFileInfo implements os.FileInfo for upstream resources, along
+// with webdav.ETager and webdav.ContentTyper so the upstream's metadata
+// is reused instead of re-reading the content.
+type httpsFileInfo struct {
+	name        string
+	size        int64
+	modTime     time.Time
+	isDir       bool
+	etag        string
+	contentType string
+}
+
+func (fi *httpsFileInfo) Name() string       { return fi.name }
+func (fi *httpsFileInfo) Size() int64        { return fi.size }
+func (fi *httpsFileInfo) ModTime() time.Time { return fi.modTime }
+func (fi *httpsFileInfo) IsDir() bool        { return fi.isDir }
+func (fi *httpsFileInfo) Sys() interface{}   { return nil }
+
+func (fi *httpsFileInfo) Mode() os.FileMode {
+	if fi.isDir {
+		return os.ModeDir | 0755
+	}
+	return 0644
+}
+
+// ETag returns the upstream ETag, if it supplied one.
+func (fi *httpsFileInfo) ETag(ctx context.Context) (string, error) {
+	if fi.etag == "" {
+		return "", webdav.ErrNotImplemented
+	}
+	return fi.etag, nil
+}
+
+// ContentType returns the upstream Content-Type, falling back to the
+// file extension so the WebDAV handler does not sniff the content.
+func (fi *httpsFileInfo) ContentType(ctx context.Context) (string, error) {
+	if fi.contentType != "" {
+		return fi.contentType, nil
+	}
+	if ctype := mime.TypeByExtension(path.Ext(fi.name)); ctype != "" {
+		return ctype, nil
+	}
+	return "application/octet-stream", nil
+}
+
+// ---------------------------------------------------------------------------
+// httpsChecksummer — digests from the upstream server
+// ---------------------------------------------------------------------------
+
+// httpsChecksummer forwards the client's Want-Digest header in a HEAD
+// request and relays the upstream's RFC 3230 Digest values for the
+// requested algorithms.
+type httpsChecksummer struct {
+	backend *httpsBackend
+}
+
+func (c *httpsChecksummer) GetDigests(ctx context.Context, relativePath string, wantDigest string) ([]string, error) {
+	wanted := make(map[string]bool)
+	for _, alg := range strings.Split(wantDigest, ",") {
+		alg, _, _ = strings.Cut(alg, ";")
+		if alg = strings.TrimSpace(strings.ToLower(alg)); alg != "" {
+			wanted[alg] = true
+		}
+	}
+	if len(wanted) == 0 {
+		return nil, nil
+	}
+
+	req, err := c.backend.newRequest(ctx, http.MethodHead, relativePath, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Want-Digest", wantDigest)
+	resp, err := c.backend.do(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	var digests []string
+	for _, header := range resp.Header.Values("Digest") {
+		for _, digest := range strings.Split(header, ",") {
+			digest = strings.TrimSpace(digest)
+			alg, value, ok := strings.Cut(digest, "=")
+			if !ok || value == "" || !wanted[strings.ToLower(alg)] {
+				continue
+			}
+			digests = append(digests, strings.ToLower(alg)+"="+value)
+		}
+	}
+	return digests, nil
+}
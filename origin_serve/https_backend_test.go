@@ -0,0 +1,277 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package origin_serve
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/webdav"
+
+	"github.com/pelicanplatform/pelican/server_utils"
+)
+
+// fakeUpstream is a WebDAV server over a temporary directory that
+// records the requests it receives and, when requiredAuth is set,
+// rejects requests lacking that Authorization header.
+type fakeUpstream struct {
+	dir          string
+	requiredAuth string
+	plainHTTP    bool
+	digest       string
+	dav          *webdav.Handler
+
+	mu       sync.Mutex
+	requests []*http.Request
+}
+
+func (u *fakeUpstream) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	u.mu.Lock()
+	u.requests = append(u.requests, r.Clone(context.Background()))
+	u.mu.Unlock()
+
+	if u.requiredAuth != "" && r.Header.Get("Authorization") != u.requiredAuth {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if u.plainHTTP && r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if r.Method == http.MethodHead && r.Header.Get("Want-Digest") != "" && u.digest != "" {
+		w.Header().Set("Digest", u.digest)
+	}
+	if r.Method == http.MethodPut {
+		// Like a real server, only store a PUT whose body arrived in full.
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(data))
+	}
+	u.dav.ServeHTTP(w, r)
+}
+
+func (u *fakeUpstream) methods() []string {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	var methods []string
+	for _, r := range u.requests {
+		methods = append(methods, r.Method+" "+r.URL.Path)
+	}
+	return methods
+}
+
+func setupHTTPSBackend(t *testing.T, tokenFile string, passthrough bool) (*fakeUpstream, *httpsBackend, *webdav.Handler) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "store", "dir"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "store", "dir", "hello.txt"), []byte("hello from upstream"), 0644))
+
+	upstream := &fakeUpstream{
+		dir: dir,
+		dav: &webdav.Handler{FileSystem: webdav.Dir(dir), LockSystem: webdav.NewMemLS()},
+	}
+	srv := httptest.NewServer(upstream)
+	t.Cleanup(srv.Close)
+
+	backend, err := newHTTPSBackend(server_utils.OriginExport{
+		FederationPrefix: "/https",
+		StoragePrefix:    "/store",
+	}, srv.URL+"/", tokenFile, passthrough)
+	require.NoError(t, err)
+
+	handler := &webdav.Handler{
+		FileSystem: backend.FileSystem(),
+		LockSystem: webdav.NewMemLS(),
+	}
+	return upstream, backend, handler
+}
+
+// withPelicanHeaders wraps handler so requests carry stashed
+// PelicanHeaders, as the origin's request handler does.
+func withPelicanHeaders(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler.ServeHTTP(w, server_utils.StashPelicanHeaders(r))
+	})
+}
+
+func TestHTTPSBackendGet(t *testing.T) {
+	upstream, _, handler := setupHTTPSBackend(t, "", false)
+
+	t.Run("full-object", func(t *testing.T) {
+		rec := doWebdav(handler, http.MethodGet, "/dir/hello.txt", nil, nil)
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "hello from upstream", rec.Body.String())
+		assert.NotEmpty(t, rec.Header().Get("ETag"))
+		assert.Equal(t, "text/plain; charset=utf-8", rec.Header().Get("Content-Type"))
+	})
+
+	t.Run("ranged", func(t *testing.T) {
+		rec := doWebdav(handler, http.MethodGet, "/dir/hello.txt", nil, map[string]string{"Range": "bytes=6-9"})
+		require.Equal(t, http.StatusPartialContent, rec.Code)
+		assert.Equal(t, "from", rec.Body.String())
+
+		upstream.mu.Lock()
+		last := upstream.requests[len(upstream.requests)-1]
+		upstream.mu.Unlock()
+		assert.Equal(t, http.MethodGet, last.Method)
+		assert.Equal(t, "bytes=6-", last.Header.Get("Range"))
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		rec := doWebdav(handler, http.MethodGet, "/dir/missing.txt", nil, nil)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestHTTPSBackendPropfind(t *testing.T) {
+	_, _, handler := setupHTTPSBackend(t, "", false)
+
+	rec := doWebdav(handler, "PROPFIND", "/dir/", nil, map[string]string{"Depth": "1"})
+	require.Equal(t, http.StatusMultiStatus, rec.Code)
+	body := rec.Body.String()
+	assert.Contains(t, body, "<D:href>/dir/</D:href>")
+	assert.Contains(t, body, "<D:href>/dir/hello.txt</D:href>")
+	assert.Contains(t, body, "<D:getcontentlength>19</D:getcontentlength>")
+}
+
+func TestHTTPSBackendWrites(t *testing.T) {
+	upstream, _, handler := setupHTTPSBackend(t, "", false)
+
+	rec := doWebdav(handler, "MKCOL", "/newdir", nil, nil)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	rec = doWebdav(handler, "MKCOL", "/newdir", nil, nil)
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+
+	rec = doWebdav(handler, http.MethodPut, "/newdir/file.bin", strings.NewReader("uploaded data"), nil)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	assert.NotEmpty(t, rec.Header().Get("ETag"))
+	data, err := os.ReadFile(filepath.Join(upstream.dir, "store", "newdir", "file.bin"))
+	require.NoError(t, err)
+	assert.Equal(t, "uploaded data", string(data))
+
+	// A PUT whose parent does not exist upstream is a conflict.
+	rec = doWebdav(handler, http.MethodPut, "/nodir/file.bin", strings.NewReader("x"), nil)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	rec = doWebdav(handler, "MOVE", "/newdir/file.bin", nil, map[string]string{"Destination": "/newdir/moved.bin"})
+	require.Equal(t, http.StatusCreated, rec.Code)
+	_, err = os.Stat(filepath.Join(upstream.dir, "store", "newdir", "moved.bin"))
+	assert.NoError(t, err)
+
+	rec = doWebdav(handler, http.MethodDelete, "/newdir", nil, nil)
+	require.Equal(t, http.StatusNoContent, rec.Code)
+	_, err = os.Stat(filepath.Join(upstream.dir, "store", "newdir"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestHTTPSBackendFailedUpload(t *testing.T) {
+	upstream, _, handler := setupHTTPSBackend(t, "", false)
+	target := filepath.Join(upstream.dir, "store", "dir", "hello.txt")
+
+	t.Run("failed-body", func(t *testing.T) {
+		body := io.MultiReader(strings.NewReader("partial"), iotest.ErrReader(io.ErrUnexpectedEOF))
+		req := httptest.NewRequest(http.MethodPut, "/dir/hello.txt", body)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, setUploadBody(req))
+		assert.NotEqual(t, http.StatusCreated, rec.Code)
+		data, err := os.ReadFile(target)
+		require.NoError(t, err)
+		assert.Equal(t, "hello from upstream", string(data))
+	})
+
+	t.Run("short-body", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPut, "/dir/hello.txt", strings.NewReader("short"))
+		req.ContentLength = 100
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, setUploadBody(req))
+		assert.NotEqual(t, http.StatusCreated, rec.Code)
+		data, err := os.ReadFile(target)
+		require.NoError(t, err)
+		assert.Equal(t, "hello from upstream", string(data))
+	})
+}
+
+func TestHTTPSBackendAuth(t *testing.T) {
+	t.Run("token-file", func(t *testing.T) {
+		tokenFile := filepath.Join(t.TempDir(), "token")
+		require.NoError(t, os.WriteFile(tokenFile, []byte("configured-token\n"), 0600))
+		upstream, _, handler := setupHTTPSBackend(t, tokenFile, true)
+		upstream.requiredAuth = "Bearer configured-token"
+
+		// The configured credential wins over the client's token.
+		rec := doWebdav(withPelicanHeaders(handler), http.MethodGet, "/dir/hello.txt", nil,
+			map[string]string{"Authorization": "Bearer client-token"})
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("passthrough", func(t *testing.T) {
+		upstream, _, handler := setupHTTPSBackend(t, "", true)
+		upstream.requiredAuth = "Bearer client-token"
+
+		rec := doWebdav(withPelicanHeaders(handler), http.MethodGet, "/dir/hello.txt?access_token=client-token", nil, nil)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("no-passthrough", func(t *testing.T) {
+		upstream, _, handler := setupHTTPSBackend(t, "", false)
+		upstream.requiredAuth = "Bearer client-token"
+
+		rec := doWebdav(withPelicanHeaders(handler), http.MethodGet, "/dir/hello.txt", nil,
+			map[string]string{"Authorization": "Bearer client-token"})
+		assert.NotEqual(t, http.StatusOK, rec.Code)
+		for _, r := range upstream.requests {
+			assert.Empty(t, r.Header.Get("Authorization"))
+		}
+	})
+}
+
+func TestHTTPSBackendPlainHTTP(t *testing.T) {
+	upstream, _, handler := setupHTTPSBackend(t, "", false)
+	upstream.plainHTTP = true
+
+	rec := doWebdav(handler, http.MethodGet, "/dir/hello.txt", nil, map[string]string{"Range": "bytes=6-9"})
+	require.Equal(t, http.StatusPartialContent, rec.Code)
+	assert.Equal(t, "from", rec.Body.String())
+	assert.Contains(t, upstream.methods(), "HEAD /store/dir/hello.txt")
+}
+
+func TestHTTPSBackendChecksummer(t *testing.T) {
+	upstream, backend, _ := setupHTTPSBackend(t, "", false)
+	upstream.digest = "md5=XUFAKrxLKna5cZ2REBfFkg==, adler32=0a2b03c4"
+
+	digests, err := backend.Checksummer().GetDigests(context.Background(), "/dir/hello.txt", "md5")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"md5=XUFAKrxLKna5cZ2REBfFkg=="}, digests)
+
+	_, err = backend.Checksummer().GetDigests(context.Background(), "/dir/missing.txt", "md5")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
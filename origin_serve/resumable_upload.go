@@ -0,0 +1,355 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package origin_serve
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/webdav"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/pelicanplatform/pelican/param"
+	"github.com/pelicanplatform/pelican/utils"
+)
+
+// Resumable uploads send an object as a series of PUT requests, each carrying
+// a `Content-Range: bytes <first>-<last>/<total>` header and the
+// X-Pelican-Upload-Id of the upload.  Chunks are appended to a hidden staging
+// file next to the object.  Until the last byte arrives, the origin answers
+// with 308 (Resume Incomplete) and the number of bytes received in the
+// X-Pelican-Upload-Offset header; the chunk completing the upload moves the
+// staging file over the object and gets the usual 201 Created and ETag.
+//
+// Before sending chunks, clients ask for the received length with a HEAD
+// request carrying the upload ID.  Origins without resumable uploads answer
+// it without the offset header, so clients fall back to a single PUT without
+// ever sending them a chunk, which they would store as the whole object.
+//
+// A chunk may start anywhere up to the received length, so a client that lost
+// a response can resend the chunk, and a chunk cut off by a network failure
+// keeps the bytes that made it to disk.
+
+var (
+	// Upload IDs are chosen by the client; keep them safe to log and hash
+	uploadIdRegex = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+	// Serializes the requests of each staging file
+	uploadLocks   = map[string]*uploadLock{}
+	uploadLocksMu sync.Mutex
+)
+
+type uploadLock struct {
+	sync.Mutex
+	refs int
+}
+
+// contentRange is a parsed `Content-Range: bytes <first>-<last>/<total>` header
+type contentRange struct {
+	first int64
+	last  int64
+	total int64
+}
+
+func lockUpload(stagingPath string) func() {
+	uploadLocksMu.Lock()
+	lock, ok := uploadLocks[stagingPath]
+	if !ok {
+		lock = &uploadLock{}
+		uploadLocks[stagingPath] = lock
+	}
+	lock.refs++
+	uploadLocksMu.Unlock()
+
+	lock.Lock()
+	return func() {
+		lock.Unlock()
+		uploadLocksMu.Lock()
+		lock.refs--
+		if lock.refs == 0 {
+			delete(uploadLocks, stagingPath)
+		}
+		uploadLocksMu.Unlock()
+	}
+}
+
+func parseContentRange(header string) (cr contentRange, err error) {
+	spec, found := strings.CutPrefix(header, "bytes ")
+	if !found {
+		return cr, fmt.Errorf("unsupported range unit in %q", header)
+	}
+	byteRange, totalStr, found := strings.Cut(spec, "/")
+	if !found {
+		return cr, fmt.Errorf("missing total length in %q", header)
+	}
+	if cr.total, err = strconv.ParseInt(totalStr, 10, 64); err != nil || cr.total <= 0 {
+		return cr, fmt.Errorf("invalid total length in %q", header)
+	}
+	firstStr, lastStr, found := strings.Cut(byteRange, "-")
+	if !found {
+		return cr, fmt.Errorf("invalid byte range in %q", header)
+	}
+	cr.first, err = strconv.ParseInt(firstStr, 10, 64)
+	if err != nil || cr.first < 0 {
+		return cr, fmt.Errorf("invalid byte range in %q", header)
+	}
+	cr.last, err = strconv.ParseInt(lastStr, 10, 64)
+	if err != nil || cr.last < cr.first || cr.last >= cr.total {
+		return cr, fmt.Errorf("invalid byte range in %q", header)
+	}
+	return cr, nil
+}
+
+// The staging file of an upload lives next to the object so that completing
+// the upload is a rename within the same directory.  Its name depends on both
+// the object and the upload ID, so an upload ID can't be used to complete a
+// different object.
+func uploadStagingPath(relativePath, uploadId string) string {
+	sum := sha256.Sum256([]byte(relativePath + "\x00" + uploadId))
+	return path.Join(path.Dir(relativePath), utils.ResumableUploadStagingPrefix+hex.EncodeToString(sum[:16]))
+}
+
+// isUploadStaging reports whether name is the path of an upload staging file.
+// Staging files are not part of the namespace: they are never listed or served.
+func isUploadStaging(name string) bool {
+	return strings.HasPrefix(path.Base(name), utils.ResumableUploadStagingPrefix)
+}
+
+// hideUploadStaging removes upload staging files from a directory listing
+func hideUploadStaging(infos []os.FileInfo) []os.FileInfo {
+	return slices.DeleteFunc(infos, func(info os.FileInfo) bool {
+		return isUploadStaging(info.Name())
+	})
+}
+
+// LaunchUploadStagingCleanup periodically removes the staging files of
+// resumable uploads that have not been written to for Origin.UploadStagingExpiry,
+// as clients that abandon an upload leave them behind.  It must be called
+// after InitializeHandlers.
+func LaunchUploadStagingCleanup(ctx context.Context, egrp *errgroup.Group) {
+	expiry := param.Origin_UploadStagingExpiry.GetDuration()
+	if expiry <= 0 {
+		log.Debug("Expiry of abandoned upload staging files is disabled")
+		return
+	}
+	var storagePrefixes []string
+	for federationPrefix := range resumableExports {
+		if storagePrefix := resumableStoragePrefix(federationPrefix); storagePrefix != "" {
+			storagePrefixes = append(storagePrefixes, storagePrefix)
+		}
+	}
+	if len(storagePrefixes) == 0 {
+		return
+	}
+
+	interval := max(expiry/4, time.Minute)
+	egrp.Go(func() error {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			for _, storagePrefix := range storagePrefixes {
+				expireUploadStaging(storagePrefix, expiry)
+			}
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+			}
+		}
+	})
+}
+
+// expireUploadStaging removes the upload staging files under storagePrefix
+// that have not been written to for longer than expiry
+func expireUploadStaging(storagePrefix string, expiry time.Duration) {
+	root, err := os.OpenRoot(storagePrefix)
+	if err != nil {
+		log.Warningf("Failed to open %s to expire abandoned uploads: %v", storagePrefix, err)
+		return
+	}
+	defer root.Close()
+
+	err = fs.WalkDir(root.FS(), ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			// Keep going; an unreadable directory has no staging files we can remove
+			log.Debugf("Skipping %s while expiring abandoned uploads: %v", name, err)
+			return nil
+		}
+		if entry.IsDir() || !isUploadStaging(name) {
+			return nil
+		}
+		// Hold the upload's lock so a chunk being written can't race the removal
+		unlock := lockUpload("/" + name)
+		defer unlock()
+		info, err := root.Stat(name)
+		if err != nil || time.Since(info.ModTime()) < expiry {
+			return nil
+		}
+		if err := root.Remove(name); err != nil && !os.IsNotExist(err) {
+			log.Warningf("Failed to remove abandoned upload staging file %s: %v", path.Join(storagePrefix, name), err)
+			return nil
+		}
+		log.Debugf("Removed upload staging file %s, last written %s", path.Join(storagePrefix, name), info.ModTime().Format(time.RFC3339))
+		return nil
+	})
+	if err != nil {
+		log.Warningf("Failed to expire abandoned uploads under %s: %v", storagePrefix, err)
+	}
+}
+
+// Get the storage prefix of an export for resumable uploads, or an empty string
+// if the export doesn't accept them
+func resumableStoragePrefix(federationPrefix string) string {
+	if !resumableExports[federationPrefix] {
+		return ""
+	}
+	return exportPrefixMap[federationPrefix]
+}
+
+// Look up the number of bytes received for an upload
+func uploadReceived(c *gin.Context, fs webdav.FileSystem, stagingPath string) (int64, bool) {
+	info, err := fs.Stat(c.Request.Context(), stagingPath)
+	if err == nil {
+		return info.Size(), true
+	} else if os.IsNotExist(err) {
+		return 0, true
+	}
+	log.Errorf("Failed to stat upload staging file %s: %v", stagingPath, err)
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to look up the upload"})
+	return 0, false
+}
+
+// Report the received length of an upload that is not yet complete
+func writeResumeIncomplete(c *gin.Context, received int64) {
+	c.Header(utils.ResumableUploadOffsetHeader, strconv.FormatInt(received, 10))
+	c.Status(http.StatusPermanentRedirect)
+}
+
+// handleResumableUploadStatus answers a HEAD request carrying an upload ID
+// with the number of bytes the origin has received for the upload
+func handleResumableUploadStatus(c *gin.Context, handler *webdav.Handler, relativePath string, storagePrefix string) {
+	if storagePrefix == "" {
+		// Without the offset header, the client uploads the object in a single PUT
+		c.Status(http.StatusNotImplemented)
+		return
+	}
+	uploadId := c.Request.Header.Get(utils.ResumableUploadIdHeader)
+	if !uploadIdRegex.MatchString(uploadId) {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	stagingPath := uploadStagingPath(relativePath, uploadId)
+	unlock := lockUpload(stagingPath)
+	defer unlock()
+	if received, ok := uploadReceived(c, handler.FileSystem, stagingPath); ok {
+		c.Header(utils.ResumableUploadOffsetHeader, strconv.FormatInt(received, 10))
+		c.Status(http.StatusOK)
+	}
+}
+
+// handleResumablePut handles a PUT request belonging to a resumable upload,
+// i.e. one with a Content-Range header.
+//
+// Resumable uploads need a seekable staging file, so they are only offered by
+// exports with local storage; the others answer 501 and the client falls back
+// to a single PUT.
+func handleResumablePut(c *gin.Context, handler *webdav.Handler, req *http.Request, relativePath string, storagePrefix string) {
+	if storagePrefix == "" {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "resumable uploads are not supported by this origin's storage backend"})
+		return
+	}
+	uploadId := req.Header.Get(utils.ResumableUploadIdHeader)
+	if !uploadIdRegex.MatchString(uploadId) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "resumable uploads require a valid " + utils.ResumableUploadIdHeader + " header"})
+		return
+	}
+	cr, err := parseContentRange(req.Header.Get("Content-Range"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := req.Context()
+	fs := handler.FileSystem
+	stagingPath := uploadStagingPath(relativePath, uploadId)
+	unlock := lockUpload(stagingPath)
+	defer unlock()
+
+	received, ok := uploadReceived(c, fs, stagingPath)
+	if !ok {
+		return
+	}
+	if cr.first > received || received > cr.total {
+		// The chunk would leave a hole; tell the client where to resume
+		c.Header(utils.ResumableUploadOffsetHeader, strconv.FormatInt(received, 10))
+		c.JSON(http.StatusRequestedRangeNotSatisfiable, gin.H{"error": fmt.Sprintf("the upload has %d bytes; chunks must start at or before that offset", received)})
+		return
+	}
+
+	file, err := fs.OpenFile(ctx, stagingPath, os.O_RDWR|os.O_CREATE, 0666)
+	if err != nil {
+		log.Errorf("Failed to open staging file of upload %s to %s: %v", uploadId, relativePath, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to open the upload"})
+		return
+	}
+	chunkLen := cr.last - cr.first + 1
+	written := int64(0)
+	_, err = file.Seek(cr.first, io.SeekStart)
+	if err == nil {
+		written, err = io.Copy(file, io.LimitReader(req.Body, chunkLen))
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	received = max(received, cr.first+written)
+	if err != nil {
+		log.Warningf("Upload %s to %s was interrupted after %d bytes: %v", uploadId, relativePath, received, err)
+		// Whatever made it to disk stays, so the client can resume from there
+		writeResumeIncomplete(c, received)
+		return
+	}
+	if written < chunkLen || received < cr.total {
+		writeResumeIncomplete(c, received)
+		return
+	}
+
+	if err := fs.Rename(ctx, stagingPath, relativePath); err != nil {
+		log.Errorf("Failed to move the completed upload %s into place at %s: %v", uploadId, relativePath, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to complete the upload"})
+		return
+	}
+	log.Debugf("Completed resumable upload %s of %d bytes to %s", uploadId, cr.total, relativePath)
+	setUploadETag(c.Writer.Header(), relativePath, storagePrefix)
+	c.Status(http.StatusCreated)
+}
@@ -0,0 +1,201 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package origin_serve
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/webdav"
+
+	"github.com/pelicanplatform/pelican/server_utils"
+	"github.com/pelicanplatform/pelican/utils"
+)
+
+// Fails after handing out the first n bytes, like a dropped connection
+type truncatedReader struct {
+	data []byte
+	n    int
+}
+
+func (r *truncatedReader) Read(p []byte) (int, error) {
+	if r.n == 0 {
+		return 0, errors.New("connection reset")
+	}
+	n := copy(p, r.data[:min(r.n, len(r.data))])
+	r.data = r.data[n:]
+	r.n -= n
+	return n, nil
+}
+
+func TestParseContentRange(t *testing.T) {
+	cr, err := parseContentRange("bytes 0-99/200")
+	require.NoError(t, err)
+	assert.Equal(t, contentRange{first: 0, last: 99, total: 200}, cr)
+
+	for _, header := range []string{"items 0-1/2", "bytes 0-1", "bytes */200", "bytes 5-1/10", "bytes 0-10/10", "bytes 0-1/0", "bytes a-b/10"} {
+		_, err := parseContentRange(header)
+		assert.Error(t, err, header)
+	}
+}
+
+func TestResumablePut(t *testing.T) {
+	storageDir := t.TempDir()
+	osRootFs, err := server_utils.NewOsRootFs(storageDir)
+	require.NoError(t, err)
+	handler := &webdav.Handler{
+		FileSystem: newAferoFileSystem(newAutoCreateDirFs(osRootFs), "", nil),
+		LockSystem: webdav.NewMemLS(),
+	}
+
+	gin.SetMode(gin.TestMode)
+	newRouter := func(storagePrefix string) *gin.Engine {
+		router := gin.New()
+		router.HEAD("/*path", func(c *gin.Context) {
+			handleResumableUploadStatus(c, handler, c.Param("path"), storagePrefix)
+		})
+		router.PUT("/*path", func(c *gin.Context) {
+			handleResumablePut(c, handler, c.Request, c.Param("path"), storagePrefix)
+		})
+		return router
+	}
+	router := newRouter(storageDir)
+
+	data := bytes.Repeat([]byte("0123456789"), 100)
+	total := int64(len(data))
+	put := func(uploadId string, first, last int64, body io.Reader) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, "/dir/object", body)
+		req.Header.Set(utils.ResumableUploadIdHeader, uploadId)
+		req.Header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", first, last, total))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	status := func(uploadId string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodHead, "/dir/object", nil)
+		req.Header.Set(utils.ResumableUploadIdHeader, uploadId)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	offset := func(w *httptest.ResponseRecorder) string {
+		return w.Header().Get(utils.ResumableUploadOffsetHeader)
+	}
+
+	// Nothing received yet
+	w := status("upload1")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "0", offset(w))
+
+	w = put("upload1", 0, 399, bytes.NewReader(data[:400]))
+	assert.Equal(t, http.StatusPermanentRedirect, w.Code)
+	assert.Equal(t, "400", offset(w))
+
+	// A chunk cut off by a failure keeps the bytes that arrived
+	w = put("upload1", 400, 799, &truncatedReader{data: data[400:800], n: 150})
+	assert.Equal(t, http.StatusPermanentRedirect, w.Code)
+	assert.Equal(t, "550", offset(w))
+	assert.Equal(t, "550", offset(status("upload1")))
+
+	// Chunks can't leave holes, and other uploads don't see the staged data
+	w = put("upload1", 600, 999, bytes.NewReader(data[600:]))
+	assert.Equal(t, http.StatusRequestedRangeNotSatisfiable, w.Code)
+	assert.Equal(t, "550", offset(w))
+	assert.Equal(t, "0", offset(status("upload2")))
+	_, err = os.Stat(filepath.Join(storageDir, "dir", "object"))
+	assert.True(t, os.IsNotExist(err))
+
+	// Resending bytes the origin already has is harmless
+	w = put("upload1", 500, 999, bytes.NewReader(data[500:]))
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.NotEmpty(t, w.Header().Get("ETag"))
+	written, err := os.ReadFile(filepath.Join(storageDir, "dir", "object"))
+	require.NoError(t, err)
+	assert.Equal(t, data, written)
+	staged, err := filepath.Glob(filepath.Join(storageDir, "dir", utils.ResumableUploadStagingPrefix+"*"))
+	require.NoError(t, err)
+	assert.Empty(t, staged)
+
+	// Invalid requests
+	assert.Equal(t, http.StatusBadRequest, status("").Code)
+	assert.Equal(t, http.StatusBadRequest, status("../etc").Code)
+	assert.Equal(t, http.StatusBadRequest, put("upload3", 0, total, http.NoBody).Code)
+
+	// Backends without local storage don't offer resumable uploads
+	router = newRouter("")
+	w = status("upload4")
+	assert.Equal(t, http.StatusNotImplemented, w.Code)
+	assert.Empty(t, offset(w))
+	assert.Equal(t, http.StatusNotImplemented, put("upload4", 0, 9, bytes.NewReader(data[:10])).Code)
+}
+
+func TestUploadStagingHidden(t *testing.T) {
+	server, storageDir, cleanup := setupE2ETestServer(t)
+	defer cleanup()
+	stagingName := utils.ResumableUploadStagingPrefix + "0123456789abcdef"
+	require.NoError(t, os.WriteFile(filepath.Join(storageDir, stagingName), []byte("partial"), 0644))
+
+	// Staging files can't be downloaded
+	resp, err := http.Get(server.URL + "/test/" + stagingName)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	// ... nor are they listed
+	handler := webdavHandlers["/test"]
+	req := httptest.NewRequest("PROPFIND", "/test/", nil)
+	req.Header.Set("Depth", "1")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	require.Equal(t, http.StatusMultiStatus, w.Code)
+	assert.Contains(t, w.Body.String(), "test.txt")
+	assert.NotContains(t, w.Body.String(), utils.ResumableUploadStagingPrefix)
+}
+
+func TestExpireUploadStaging(t *testing.T) {
+	storageDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(storageDir, "dir"), 0755))
+	abandoned := filepath.Join(storageDir, "dir", utils.ResumableUploadStagingPrefix+"abandoned")
+	active := filepath.Join(storageDir, "dir", utils.ResumableUploadStagingPrefix+"active")
+	oldObject := filepath.Join(storageDir, "dir", "object")
+	for _, name := range []string{abandoned, active, oldObject} {
+		require.NoError(t, os.WriteFile(name, []byte("data"), 0644))
+	}
+	old := time.Now().Add(-2 * time.Hour)
+	require.NoError(t, os.Chtimes(abandoned, old, old))
+	require.NoError(t, os.Chtimes(oldObject, old, old))
+
+	expireUploadStaging(storageDir, time.Hour)
+
+	_, err := os.Stat(abandoned)
+	assert.True(t, os.IsNotExist(err), "abandoned staging file should be removed")
+	assert.FileExists(t, active)
+	assert.FileExists(t, oldObject)
+}
@@ -0,0 +1,805 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package origin_serve
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/webdav"
+
+	"github.com/pelicanplatform/pelican/config"
+	"github.com/pelicanplatform/pelican/metrics"
+	"github.com/pelicanplatform/pelican/server_utils"
+)
+
+// ---------------------------------------------------------------------------
+// s3Backend — OriginBackend for S3 object storage
+// ---------------------------------------------------------------------------
+
+const (
+	// s3UploadPartSize is the minimum multipart chunk size used for PUTs.
+	// When the client declares a Content-Length the part size grows so
+	// the object fits in S3's 10,000 part limit; without one, uploads are
+	// limited to ~160 GiB.  At most s3UploadConcurrency parts are
+	// buffered in memory.
+	s3UploadPartSize = 16 * 1024 * 1024
+
+	// s3UploadConcurrency is the number of parts uploaded in parallel
+	// for a single PUT.
+	s3UploadConcurrency = 4
+
+	// s3DeleteBatchSize is the maximum number of keys accepted by a
+	// single DeleteObjects call.
+	s3DeleteBatchSize = 1000
+)
+
+// s3Backend serves an export from an S3 bucket.  It implements
+// webdav.FileSystem directly on top of the S3 API: GETs become ranged
+// GetObject calls, PUTs are streamed through a multipart upload, and
+// directory listings are synthesised from ListObjectsV2 with a "/"
+// delimiter.
+//
+// When no bucket is configured for the export, the first path component
+// of every request selects the bucket (mirroring the XRootD S3 plugin's
+// behaviour for path-style service URLs).
+type s3Backend struct {
+	client   *s3.S3
+	uploader *s3manager.Uploader
+	bucket   string
+}
+
+// newS3Backend creates an S3 backend for the given export.  serviceUrl,
+// region and urlStyle come from the origin-wide Origin.S3* parameters;
+// the bucket and credentials come from the export itself.
+func newS3Backend(export server_utils.OriginExport, serviceUrl, region, urlStyle string) (*s3Backend, error) {
+	if serviceUrl == "" {
+		return nil, errors.New("an S3 service URL is required for S3 exports")
+	}
+	if region == "" {
+		// Most non-AWS S3 implementations ignore the region but the
+		// request signer still needs one.
+		region = "us-east-1"
+	}
+
+	creds := credentials.AnonymousCredentials
+	if export.S3AccessKeyfile != "" && export.S3SecretKeyfile != "" {
+		accessKey, err := os.ReadFile(export.S3AccessKeyfile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read S3 access key file %s", export.S3AccessKeyfile)
+		}
+		secretKey, err := os.ReadFile(export.S3SecretKeyfile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read S3 secret key file %s", export.S3SecretKeyfile)
+		}
+		creds = credentials.NewStaticCredentials(strings.TrimSpace(string(accessKey)), strings.TrimSpace(string(secretKey)), "")
+	}
+
+	sess, err := session.NewSession(aws.NewConfig().
+		WithEndpoint(serviceUrl).
+		WithRegion(region).
+		WithS3ForcePathStyle(urlStyle != "virtual").
+		WithCredentials(creds).
+		WithHTTPClient(&http.Client{Transport: config.GetTransport()}))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create S3 session")
+	}
+
+	client := s3.New(sess)
+	uploader := s3manager.NewUploaderWithClient(client, func(u *s3manager.Uploader) {
+		u.PartSize = s3UploadPartSize
+		u.Concurrency = s3UploadConcurrency
+	})
+
+	return &s3Backend{
+		client:   client,
+		uploader: uploader,
+		bucket:   export.S3Bucket,
+	}, nil
+}
+
+func (b *s3Backend) CheckAvailability() error      { return nil }
+func (b *s3Backend) FileSystem() webdav.FileSystem { return b }
+func (b *s3Backend) Checksummer() server_utils.OriginChecksummer {
+	return &s3Checksummer{backend: b}
+}
+
+// resolve maps a WebDAV path onto a bucket and object key.  The key has
+// no leading or trailing slash; an empty key refers to the bucket root.
+// An empty bucket is only returned in bucket-less mode for the export
+// root.
+func (b *s3Backend) resolve(name string) (bucket, key string) {
+	clean := strings.TrimPrefix(path.Clean("/"+name), "/")
+	if b.bucket != "" {
+		return b.bucket, clean
+	}
+	bucket, key, _ = strings.Cut(clean, "/")
+	return bucket, key
+}
+
+// translateS3Error maps S3 API errors onto the os errors the WebDAV
+// handler understands so that missing objects produce 404s and denied
+// requests produce 403s.
+func translateS3Error(err error) error {
+	if err == nil {
+		return nil
+	}
+	if reqErr, ok := err.(awserr.RequestFailure); ok {
+		switch reqErr.StatusCode() {
+		case http.StatusNotFound:
+			return os.ErrNotExist
+		case http.StatusForbidden:
+			return os.ErrPermission
+		}
+	}
+	if aerr, ok := err.(awserr.Error); ok {
+		switch aerr.Code() {
+		case s3.ErrCodeNoSuchKey, s3.ErrCodeNoSuchBucket, "NotFound":
+			return os.ErrNotExist
+		case "AccessDenied":
+			return os.ErrPermission
+		}
+	}
+	return err
+}
+
+// Mkdir creates a zero-length "directory marker" object so that empty
+// collections remain visible in listings.
+func (b *s3Backend) Mkdir(ctx context.Context, name string, perm os.FileMode) error {
+	defer trackBackendOperation(operationMetrics{
+		total:         metrics.StorageMkdirsTotal,
+		timeHistogram: metrics.StorageMkdirTime,
+		slowTotal:     metrics.StorageSlowMkdirsTotal,
+		slowHistogram: metrics.StorageSlowMkdirTime,
+	}, metrics.BackendS3, usernameFromContext(ctx))()
+
+	bucket, key := b.resolve(name)
+	if bucket == "" || key == "" {
+		// Buckets are provisioned out of band, never through the origin.
+		return os.ErrPermission
+	}
+	_, err := b.client.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key + "/"),
+		Body:   strings.NewReader(""),
+	})
+	return translateS3Error(err)
+}
+
+// OpenFile returns a lazily-read object handle for reads, or a handle
+// that streams writes into a multipart upload.
+func (b *s3Backend) OpenFile(ctx context.Context, name string, flag int, perm os.FileMode) (webdav.File, error) {
+	username := usernameFromContext(ctx)
+	defer trackBackendOperation(operationMetrics{
+		total:         metrics.StorageOpensTotal,
+		timeHistogram: metrics.StorageOpenTime,
+		slowTotal:     metrics.StorageSlowOpensTotal,
+		slowHistogram: metrics.StorageSlowOpenTime,
+	}, metrics.BackendS3, username)()
+
+	bucket, key := b.resolve(name)
+	if flag&(os.O_WRONLY|os.O_RDWR|os.O_CREATE|os.O_TRUNC) != 0 {
+		// As with the POSIX backend, a write to a collection must
+		// surface as "not exist" so the WebDAV handler returns 409.
+		if bucket == "" || key == "" {
+			return nil, os.ErrNotExist
+		}
+		if info, err := b.Stat(ctx, name); err == nil && info.IsDir() {
+			return nil, os.ErrNotExist
+		}
+		return b.startUpload(ctx, name, bucket, key), nil
+	}
+
+	info, err := b.Stat(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return &s3File{
+		backend: b,
+		ctx:     ctx,
+		bucket:  bucket,
+		key:     key,
+		info:    info.(*s3FileInfo),
+	}, nil
+}
+
+// RemoveAll deletes the object at name along with every object beneath
+// it when name refers to a directory.
+func (b *s3Backend) RemoveAll(ctx context.Context, name string) error {
+	defer trackBackendOperation(operationMetrics{
+		total:         metrics.StorageUnlinksTotal,
+		timeHistogram: metrics.StorageUnlinkTime,
+		slowTotal:     metrics.StorageSlowUnlinksTotal,
+		slowHistogram: metrics.StorageSlowUnlinkTime,
+	}, metrics.BackendS3, usernameFromContext(ctx))()
+
+	bucket, key := b.resolve(name)
+	if bucket == "" || key == "" {
+		return os.ErrPermission
+	}
+
+	keys, err := b.listKeys(ctx, bucket, key+"/")
+	if err != nil {
+		return err
+	}
+	keys = append(keys, key)
+	for start := 0; start < len(keys); start += s3DeleteBatchSize {
+		end := min(start+s3DeleteBatchSize, len(keys))
+		objects := make([]*s3.ObjectIdentifier, 0, end-start)
+		for _, k := range keys[start:end] {
+			objects = append(objects, &s3.ObjectIdentifier{Key: aws.String(k)})
+		}
+		out, err := b.client.DeleteObjectsWithContext(ctx, &s3.DeleteObjectsInput{
+			Bucket: aws.String(bucket),
+			Delete: &s3.Delete{Objects: objects, Quiet: aws.Bool(true)},
+		})
+		if err != nil {
+			return translateS3Error(err)
+		}
+		if len(out.Errors) > 0 {
+			return fmt.Errorf("failed to delete %s: %s", aws.StringValue(out.Errors[0].Key), aws.StringValue(out.Errors[0].Message))
+		}
+	}
+	return nil
+}
+
+// Rename copies the object (or every object beneath a directory) to the
+// new name and then removes the originals.  S3 has no native rename.
+func (b *s3Backend) Rename(ctx context.Context, oldName, newName string) error {
+	defer trackBackendOperation(operationMetrics{
+		total:         metrics.StorageRenamesTotal,
+		timeHistogram: metrics.StorageRenameTime,
+		slowTotal:     metrics.StorageSlowRenamesTotal,
+		slowHistogram: metrics.StorageSlowRenameTime,
+	}, metrics.BackendS3, usernameFromContext(ctx))()
+
+	srcBucket, srcKey := b.resolve(oldName)
+	dstBucket, dstKey := b.resolve(newName)
+	if srcBucket == "" || srcKey == "" || dstBucket == "" || dstKey == "" {
+		return os.ErrPermission
+	}
+
+	info, err := b.Stat(ctx, oldName)
+	if err != nil {
+		return err
+	}
+
+	var moves [][2]string
+	if info.IsDir() {
+		keys, err := b.listKeys(ctx, srcBucket, srcKey+"/")
+		if err != nil {
+			return err
+		}
+		for _, k := range keys {
+			moves = append(moves, [2]string{k, dstKey + "/" + strings.TrimPrefix(k, srcKey+"/")})
+		}
+	} else {
+		moves = append(moves, [2]string{srcKey, dstKey})
+	}
+
+	for _, mv := range moves {
+		source := (&url.URL{Path: srcBucket + "/" + mv[0]}).EscapedPath()
+		if _, err := b.client.CopyObjectWithContext(ctx, &s3.CopyObjectInput{
+			Bucket:     aws.String(dstBucket),
+			Key:        aws.String(mv[1]),
+			CopySource: aws.String(source),
+		}); err != nil {
+			return translateS3Error(err)
+		}
+	}
+	return b.RemoveAll(ctx, oldName)
+}
+
+// Stat issues a HeadObject for the key.  When no such object exists the
+// key is treated as a directory if any object lives beneath it.
+func (b *s3Backend) Stat(ctx context.Context, name string) (os.FileInfo, error) {
+	defer trackBackendOperation(operationMetrics{
+		total:         metrics.StorageStatsTotal,
+		timeHistogram: metrics.StorageStatTime,
+		slowTotal:     metrics.StorageSlowStatsTotal,
+		slowHistogram: metrics.StorageSlowStatTime,
+	}, metrics.BackendS3, usernameFromContext(ctx))()
+
+	bucket, key := b.resolve(name)
+	base := path.Base(path.Clean("/" + name))
+	if bucket == "" {
+		return newS3DirInfo(base), nil
+	}
+	if key == "" {
+		if b.bucket == "" {
+			// Bucket-less mode: the first path component must name a bucket.
+			if _, err := b.client.HeadBucketWithContext(ctx, &s3.HeadBucketInput{Bucket: aws.String(bucket)}); err != nil {
+				return nil, translateS3Error(err)
+			}
+		}
+		return newS3DirInfo(base), nil
+	}
+
+	head, err := b.client.HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err == nil {
+		return &s3FileInfo{
+			name:        base,
+			size:        aws.Int64Value(head.ContentLength),
+			modTime:     aws.TimeValue(head.LastModified),
+			etag:        aws.StringValue(head.ETag),
+			contentType: aws.StringValue(head.ContentType),
+		}, nil
+	}
+	if err = translateS3Error(err); !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	list, err := b.client.ListObjectsV2WithContext(ctx, &s3.ListObjectsV2Input{
+		Bucket:  aws.String(bucket),
+		Prefix:  aws.String(key + "/"),
+		MaxKeys: aws.Int64(1),
+	})
+	if err != nil {
+		return nil, translateS3Error(err)
+	}
+	if len(list.Contents) == 0 {
+		return nil, os.ErrNotExist
+	}
+	return newS3DirInfo(base), nil
+}
+
+// listKeys returns every object key beginning with prefix.
+func (b *s3Backend) listKeys(ctx context.Context, bucket, prefix string) ([]string, error) {
+	var keys []string
+	err := b.client.ListObjectsV2PagesWithContext(ctx, &s3.ListObjectsV2Input{
+		Bucket: aws.String(bucket),
+		Prefix: aws.String(prefix),
+	}, func(page *s3.ListObjectsV2Output, _ bool) bool {
+		for _, obj := range page.Contents {
+			keys = append(keys, aws.StringValue(obj.Key))
+		}
+		return true
+	})
+	return keys, translateS3Error(err)
+}
+
+// listDir returns the immediate children of a directory, using the "/"
+// delimiter so that nested objects collapse into common prefixes.
+func (b *s3Backend) listDir(ctx context.Context, bucket, key string) ([]os.FileInfo, error) {
+	var infos []os.FileInfo
+	if bucket == "" {
+		out, err := b.client.ListBucketsWithContext(ctx, &s3.ListBucketsInput{})
+		if err != nil {
+			return nil, translateS3Error(err)
+		}
+		for _, bkt := range out.Buckets {
+			info := newS3DirInfo(aws.StringValue(bkt.Name))
+			info.modTime = aws.TimeValue(bkt.CreationDate)
+			infos = append(infos, info)
+		}
+		return infos, nil
+	}
+
+	prefix := ""
+	if key != "" {
+		prefix = key + "/"
+	}
+	err := b.client.ListObjectsV2PagesWithContext(ctx, &s3.ListObjectsV2Input{
+		Bucket:    aws.String(bucket),
+		Prefix:    aws.String(prefix),
+		Delimiter: aws.String("/"),
+	}, func(page *s3.ListObjectsV2Output, _ bool) bool {
+		for _, cp := range page.CommonPrefixes {
+			name := strings.TrimSuffix(strings.TrimPrefix(aws.StringValue(cp.Prefix), prefix), "/")
+			if name != "" {
+				infos = append(infos, newS3DirInfo(name))
+			}
+		}
+		for _, obj := range page.Contents {
+			name := strings.TrimPrefix(aws.StringValue(obj.Key), prefix)
+			if name == "" {
+				continue // The directory's own marker object
+			}
+			infos = append(infos, &s3FileInfo{
+				name:    name,
+				size:    aws.Int64Value(obj.Size),
+				modTime: aws.TimeValue(obj.LastModified),
+				etag:    aws.StringValue(obj.ETag),
+			})
+		}
+		return true
+	})
+	if err != nil {
+		return nil, translateS3Error(err)
+	}
+	return infos, nil
+}
+
+// startUpload begins a streaming multipart upload for key.  Data passed
+// to Write is piped into the uploader; Close waits for the upload to
+// complete and records the resulting ETag.
+func (b *s3Backend) startUpload(ctx context.Context, name, bucket, key string) *s3UploadFile {
+	pr, pw := io.Pipe()
+	f := &s3UploadFile{
+		ctx:  ctx,
+		pw:   pw,
+		done: make(chan struct{}),
+		info: &s3FileInfo{name: path.Base(name), modTime: time.Now()},
+	}
+	partSize := s3PartSize(uploadSize(ctx))
+	go func() {
+		defer close(f.done)
+		out, err := b.uploader.UploadWithContext(ctx, &s3manager.UploadInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(key),
+			Body:   pr,
+		}, func(u *s3manager.Uploader) {
+			u.PartSize = partSize
+		})
+		if err != nil {
+			f.err = translateS3Error(err)
+			pr.CloseWithError(f.err)
+			metrics.StorageOpenErrorsTotal.WithLabelValues(metrics.BackendS3, usernameFromContext(ctx)).Inc()
+			log.Debugf("S3 upload of %s/%s failed: %v", bucket, key, err)
+			return
+		}
+		f.info.etag = aws.StringValue(out.ETag)
+	}()
+	return f
+}
+
+// s3PartSize returns the multipart chunk size for an upload of size
+// bytes (-1 if unknown): s3UploadPartSize, or larger if needed to stay
+// within the uploader's part limit, rounded up to a whole MiB.
+func s3PartSize(size int64) int64 {
+	partSize := int64(s3UploadPartSize)
+	if size <= 0 {
+		return partSize
+	}
+	if needed := (size + s3manager.MaxUploadParts - 1) / s3manager.MaxUploadParts; needed > partSize {
+		const mib = 1024 * 1024
+		partSize = (needed + mib - 1) / mib * mib
+	}
+	return partSize
+}
+
+// ---------------------------------------------------------------------------
+// s3File — read handle for an S3 object or directory
+// ---------------------------------------------------------------------------
+
+// s3File reads an object with ranged GETs.  The first Read after a Seek
+// opens a GetObject starting at the current offset; sequential reads
+// then stream from that single response.
+type s3File struct {
+	backend *s3Backend
+	ctx     context.Context
+	bucket  string
+	key     string
+	info    *s3FileInfo
+
+	body   io.ReadCloser
+	offset int64
+
+	dirEntries []os.FileInfo
+	dirOffset  int
+	dirLoaded  bool
+}
+
+func (f *s3File) Close() error {
+	if f.body != nil {
+		err := f.body.Close()
+		f.body = nil
+		return err
+	}
+	return nil
+}
+
+func (f *s3File) Read(p []byte) (int, error) {
+	if f.info.isDir {
+		return 0, errors.New("is a directory")
+	}
+	if f.offset >= f.info.size {
+		return 0, io.EOF
+	}
+	if f.body == nil {
+		out, err := f.backend.client.GetObjectWithContext(f.ctx, &s3.GetObjectInput{
+			Bucket: aws.String(f.bucket),
+			Key:    aws.String(f.key),
+			Range:  aws.String(fmt.Sprintf("bytes=%d-", f.offset)),
+		})
+		if err != nil {
+			return 0, translateS3Error(err)
+		}
+		f.body = out.Body
+	}
+	n, err := f.body.Read(p)
+	f.offset += int64(n)
+	return n, err
+}
+
+func (f *s3File) Seek(offset int64, whence int) (int64, error) {
+	var newOffset int64
+	switch whence {
+	case io.SeekStart:
+		newOffset = offset
+	case io.SeekCurrent:
+		newOffset = f.offset + offset
+	case io.SeekEnd:
+		newOffset = f.info.size + offset
+	default:
+		return 0, fmt.Errorf("invalid whence: %d", whence)
+	}
+	if newOffset < 0 {
+		return 0, errors.New("negative position")
+	}
+	if newOffset != f.offset && f.body != nil {
+		f.body.Close()
+		f.body = nil
+	}
+	f.offset = newOffset
+	return newOffset, nil
+}
+
+func (f *s3File) Write(p []byte) (int, error) {
+	return 0, errors.New("file not opened for writing")
+}
+
+func (f *s3File) Readdir(count int) ([]os.FileInfo, error) {
+	if !f.info.isDir {
+		return nil, errors.New("not a directory")
+	}
+	if !f.dirLoaded {
+		entries, err := f.backend.listDir(f.ctx, f.bucket, f.key)
+		if err != nil {
+			return nil, err
+		}
+		f.dirEntries = hideUploadStaging(entries)
+		f.dirLoaded = true
+	}
+
+	if count <= 0 {
+		result := f.dirEntries[f.dirOffset:]
+		f.dirOffset = len(f.dirEntries)
+		return result, nil
+	}
+	remaining := len(f.dirEntries) - f.dirOffset
+	if remaining == 0 {
+		return nil, io.EOF
+	}
+	count = min(count, remaining)
+	result := f.dirEntries[f.dirOffset : f.dirOffset+count]
+	f.dirOffset += count
+	return result, nil
+}
+
+func (f *s3File) Stat() (os.FileInfo, error) {
+	return f.info, nil
+}
+
+// ---------------------------------------------------------------------------
+// s3UploadFile — write handle streaming into a multipart upload
+// ---------------------------------------------------------------------------
+
+// s3UploadFile is returned by OpenFile for writes.  The WebDAV handler
+// calls Stat between the final Write and Close; because the upload has
+// not finished at that point, Stat answers from local state and Close
+// fills in the object's ETag on the same FileInfo once S3 responds.
+type s3UploadFile struct {
+	ctx  context.Context
+	pw   *io.PipeWriter
+	done chan struct{}
+	err  error
+
+	closeOnce sync.Once
+	info      *s3FileInfo
+}
+
+func (f *s3UploadFile) Write(p []byte) (int, error) {
+	n, err := f.pw.Write(p)
+	f.info.size += int64(n)
+	f.info.modTime = time.Now()
+	return n, err
+}
+
+// Close completes the upload.  If the client's body was not received in
+// full, the pipe is closed with an error instead so the uploader aborts
+// the multipart upload rather than committing a truncated object.
+func (f *s3UploadFile) Close() error {
+	f.closeOnce.Do(func() {
+		if err := uploadBodyError(f.ctx); err != nil {
+			f.pw.CloseWithError(err)
+			<-f.done
+			if f.err == nil {
+				f.err = err
+			}
+			return
+		}
+		f.pw.Close()
+		<-f.done
+	})
+	return f.err
+}
+
+func (f *s3UploadFile) Read(p []byte) (int, error) {
+	return 0, errors.New("file not opened for reading")
+}
+
+func (f *s3UploadFile) Seek(offset int64, whence int) (int64, error) {
+	return 0, errors.New("seek not supported on S3 uploads")
+}
+
+func (f *s3UploadFile) Readdir(count int) ([]os.FileInfo, error) {
+	return nil, errors.New("not a directory")
+}
+
+func (f *s3UploadFile) Stat() (os.FileInfo, error) {
+	return f.info, nil
+}
+
+// ---------------------------------------------------------------------------
+// s3FileInfo
+// ---------------------------------------------------------------------------
+
+// s3FileInfo implements os.FileInfo for S3 objects and synthesised
+// directories.  It also implements webdav.ETager and webdav.ContentTyper
+// so the WebDAV handler reuses S3's metadata instead of re-reading the
+// object.
+type s3FileInfo struct {
+	name        string
+	size        int64
+	modTime     time.Time
+	isDir       bool
+	etag        string
+	contentType string
+}
+
+func newS3DirInfo(name string) *s3FileInfo {
+	return &s3FileInfo{name: name, modTime: time.Now(), isDir: true}
+}
+
+func (fi *s3FileInfo) Name() string       { return fi.name }
+func (fi *s3FileInfo) Size() int64        { return fi.size }
+func (fi *s3FileInfo) ModTime() time.Time { return fi.modTime }
+func (fi *s3FileInfo) IsDir() bool        { return fi.isDir }
+func (fi *s3FileInfo) Sys() interface{}   { return nil }
+
+func (fi *s3FileInfo) Mode() os.FileMode {
+	if fi.isDir {
+		return os.ModeDir | 0755
+	}
+	return 0644
+}
+
+// ETag returns the object's S3 ETag.
+func (fi *s3FileInfo) ETag(ctx context.Context) (string, error) {
+	if fi.etag == "" {
+		return "", webdav.ErrNotImplemented
+	}
+	return fi.etag, nil
+}
+
+// ContentType returns the stored Content-Type, falling back to the file
+// extension.  This avoids the WebDAV handler sniffing the first 512
+// bytes, which would cost an extra GetObject per request.
+func (fi *s3FileInfo) ContentType(ctx context.Context) (string, error) {
+	if fi.contentType != "" {
+		return fi.contentType, nil
+	}
+	if ctype := mime.TypeByExtension(path.Ext(fi.name)); ctype != "" {
+		return ctype, nil
+	}
+	return "application/octet-stream", nil
+}
+
+// ---------------------------------------------------------------------------
+// s3Checksummer — digests from S3 object metadata
+// ---------------------------------------------------------------------------
+
+// s3Checksummer answers Want-Digest requests from object metadata
+// without reading the data.  MD5 is derived from the ETag of objects
+// uploaded in a single part; CRC32, CRC32C, SHA-1 and SHA-256 are available
+// when the object was written with S3 additional checksums.  S3 has no
+// Adler-32 checksums.
+type s3Checksummer struct {
+	backend *s3Backend
+}
+
+func (c *s3Checksummer) GetDigests(ctx context.Context, relativePath string, wantDigest string) ([]string, error) {
+	types := parseWantDigest(wantDigest)
+	if len(types) == 0 {
+		return nil, nil
+	}
+
+	bucket, key := c.backend.resolve(relativePath)
+	if bucket == "" || key == "" {
+		return nil, nil
+	}
+	head, err := c.backend.client.HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+		Bucket:       aws.String(bucket),
+		Key:          aws.String(key),
+		ChecksumMode: aws.String(s3.ChecksumModeEnabled),
+	})
+	if err != nil {
+		return nil, translateS3Error(err)
+	}
+
+	var digests []string
+	for _, t := range types {
+		var raw []byte
+		switch t {
+		case ChecksumTypeMD5:
+			raw = md5FromETag(aws.StringValue(head.ETag))
+		case ChecksumTypeCRC32:
+			raw = decodeS3Checksum(head.ChecksumCRC32)
+		case ChecksumTypeCRC32C:
+			raw = decodeS3Checksum(head.ChecksumCRC32C)
+		case ChecksumTypeSHA1:
+			raw = decodeS3Checksum(head.ChecksumSHA1)
+		case ChecksumTypeSHA256:
+			raw = decodeS3Checksum(head.ChecksumSHA256)
+		}
+		if raw != nil {
+			digests = append(digests, formatRFC3230(t, raw))
+		}
+	}
+	return digests, nil
+}
+
+// md5FromETag returns the MD5 encoded in an S3 ETag, or nil if the ETag
+// is not a plain MD5 (multipart uploads use "<hash>-<parts>").
+func md5FromETag(etag string) []byte {
+	etag = strings.Trim(etag, `"`)
+	if len(etag) != 32 {
+		return nil
+	}
+	raw, err := hex.DecodeString(etag)
+	if err != nil {
+		return nil
+	}
+	return raw
+}
+
+// decodeS3Checksum decodes one of the base64 x-amz-checksum-* values.
+func decodeS3Checksum(value *string) []byte {
+	if value == nil || *value == "" {
+		return nil
+	}
+	raw, err := base64.StdEncoding.DecodeString(*value)
+	if err != nil {
+		return nil
+	}
+	return raw
+}
@@ -0,0 +1,543 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package origin_serve
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/webdav"
+
+	"github.com/pelicanplatform/pelican/server_utils"
+)
+
+// fakeS3Object is a single object held by fakeS3.
+type fakeS3Object struct {
+	data    []byte
+	etag    string
+	modTime time.Time
+}
+
+// fakeS3 is a minimal in-process, path-style S3 stand-in implementing
+// the subset of the API used by s3Backend: object HEAD/GET/PUT/DELETE,
+// CopyObject, DeleteObjects, ListObjectsV2, ListBuckets, HeadBucket and
+// multipart uploads.  Authentication is not checked.
+type fakeS3 struct {
+	mu      sync.Mutex
+	buckets map[string]map[string]*fakeS3Object
+	uploads map[string]map[int][]byte
+	nextId  int
+
+	// Requests records "METHOD path?query" for every request received.
+	requests []string
+}
+
+func newFakeS3(buckets ...string) *fakeS3 {
+	f := &fakeS3{
+		buckets: make(map[string]map[string]*fakeS3Object),
+		uploads: make(map[string]map[int][]byte),
+	}
+	for _, b := range buckets {
+		f.buckets[b] = make(map[string]*fakeS3Object)
+	}
+	return f
+}
+
+func (f *fakeS3) put(bucket, key string, data []byte) {
+	sum := md5.Sum(data)
+	f.buckets[bucket][key] = &fakeS3Object{data: data, etag: `"` + hex.EncodeToString(sum[:]) + `"`, modTime: time.Now().UTC()}
+}
+
+func (f *fakeS3) writeError(w http.ResponseWriter, status int, code string) {
+	w.WriteHeader(status)
+	fmt.Fprintf(w, "<Error><Code>%s</Code><Message>%s</Message></Error>", code, code)
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests = append(f.requests, r.Method+" "+r.URL.Path+"?"+r.URL.RawQuery)
+
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	query := r.URL.Query()
+	if bucket == "" {
+		f.listBuckets(w)
+		return
+	}
+	objects, ok := f.buckets[bucket]
+	if !ok {
+		f.writeError(w, http.StatusNotFound, "NoSuchBucket")
+		return
+	}
+
+	if key == "" {
+		switch {
+		case r.Method == http.MethodHead:
+			w.WriteHeader(http.StatusOK)
+		case r.Method == http.MethodGet && query.Get("list-type") == "2":
+			f.listObjectsV2(w, bucket, objects, query)
+		case r.Method == http.MethodPost && query.Has("delete"):
+			f.deleteObjects(w, r, objects)
+		default:
+			f.writeError(w, http.StatusNotImplemented, "NotImplemented")
+		}
+		return
+	}
+
+	switch r.Method {
+	case http.MethodHead, http.MethodGet:
+		obj, ok := objects[key]
+		if !ok {
+			f.writeError(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		data := obj.data
+		status := http.StatusOK
+		if rng := r.Header.Get("Range"); rng != "" {
+			spec := strings.TrimPrefix(rng, "bytes=")
+			startStr, endStr, _ := strings.Cut(spec, "-")
+			start, _ := strconv.Atoi(startStr)
+			end := len(data) - 1
+			if endStr != "" {
+				end, _ = strconv.Atoi(endStr)
+			}
+			if start >= len(data) {
+				f.writeError(w, http.StatusRequestedRangeNotSatisfiable, "InvalidRange")
+				return
+			}
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end, len(data)))
+			data = data[start : end+1]
+			status = http.StatusPartialContent
+		}
+		w.Header().Set("ETag", obj.etag)
+		w.Header().Set("Last-Modified", obj.modTime.Format(http.TimeFormat))
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		w.WriteHeader(status)
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(data)
+		}
+	case http.MethodPut:
+		if src := r.Header.Get("X-Amz-Copy-Source"); src != "" {
+			unescaped, _ := url.PathUnescape(src)
+			srcBucket, srcKey, _ := strings.Cut(strings.TrimPrefix(unescaped, "/"), "/")
+			obj, ok := f.buckets[srcBucket][srcKey]
+			if !ok {
+				f.writeError(w, http.StatusNotFound, "NoSuchKey")
+				return
+			}
+			f.put(bucket, key, append([]byte(nil), obj.data...))
+			fmt.Fprintf(w, "<CopyObjectResult><ETag>%s</ETag></CopyObjectResult>", obj.etag)
+			return
+		}
+		data, _ := io.ReadAll(r.Body)
+		if uploadId := query.Get("uploadId"); uploadId != "" {
+			part, _ := strconv.Atoi(query.Get("partNumber"))
+			f.uploads[uploadId][part] = data
+			sum := md5.Sum(data)
+			w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:])+`"`)
+			return
+		}
+		f.put(bucket, key, data)
+		w.Header().Set("ETag", objects[key].etag)
+	case http.MethodPost:
+		if query.Has("uploads") {
+			f.nextId++
+			uploadId := strconv.Itoa(f.nextId)
+			f.uploads[uploadId] = make(map[int][]byte)
+			fmt.Fprintf(w, "<InitiateMultipartUploadResult><Bucket>%s</Bucket><Key>%s</Key><UploadId>%s</UploadId></InitiateMultipartUploadResult>", bucket, key, uploadId)
+			return
+		}
+		uploadId := query.Get("uploadId")
+		parts := f.uploads[uploadId]
+		numbers := make([]int, 0, len(parts))
+		for n := range parts {
+			numbers = append(numbers, n)
+		}
+		sort.Ints(numbers)
+		var data []byte
+		for _, n := range numbers {
+			data = append(data, parts[n]...)
+		}
+		delete(f.uploads, uploadId)
+		f.put(bucket, key, data)
+		// Real S3 multipart ETags are not an MD5 of the content
+		objects[key].etag = fmt.Sprintf(`"%s-%d"`, strings.Trim(objects[key].etag, `"`), len(numbers))
+		fmt.Fprintf(w, "<CompleteMultipartUploadResult><Bucket>%s</Bucket><Key>%s</Key><ETag>%s</ETag></CompleteMultipartUploadResult>", bucket, key, objects[key].etag)
+	case http.MethodDelete:
+		if uploadId := query.Get("uploadId"); uploadId != "" {
+			delete(f.uploads, uploadId)
+		} else {
+			delete(objects, key)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (f *fakeS3) listBuckets(w http.ResponseWriter) {
+	names := make([]string, 0, len(f.buckets))
+	for name := range f.buckets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Fprint(w, "<ListAllMyBucketsResult><Buckets>")
+	for _, name := range names {
+		fmt.Fprintf(w, "<Bucket><Name>%s</Name><CreationDate>2026-01-01T00:00:00.000Z</CreationDate></Bucket>", name)
+	}
+	fmt.Fprint(w, "</Buckets></ListAllMyBucketsResult>")
+}
+
+func (f *fakeS3) listObjectsV2(w http.ResponseWriter, bucket string, objects map[string]*fakeS3Object, query url.Values) {
+	type content struct {
+		Key          string
+		LastModified string
+		ETag         string
+		Size         int
+	}
+	type commonPrefix struct {
+		Prefix string
+	}
+	type result struct {
+		XMLName               xml.Name `xml:"ListBucketResult"`
+		Name                  string
+		Prefix                string
+		KeyCount              int
+		IsTruncated           bool
+		NextContinuationToken string         `xml:",omitempty"`
+		Contents              []content      `xml:"Contents"`
+		CommonPrefixes        []commonPrefix `xml:"CommonPrefixes"`
+	}
+
+	prefix := query.Get("prefix")
+	delimiter := query.Get("delimiter")
+	after := query.Get("continuation-token")
+	maxKeys := 1000
+	if mk := query.Get("max-keys"); mk != "" {
+		maxKeys, _ = strconv.Atoi(mk)
+	}
+
+	keys := make([]string, 0, len(objects))
+	for k := range objects {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	res := result{Name: bucket, Prefix: prefix}
+	seen := make(map[string]bool)
+	for _, k := range keys {
+		if !strings.HasPrefix(k, prefix) || k <= after {
+			continue
+		}
+		if res.KeyCount >= maxKeys {
+			res.IsTruncated = true
+			break
+		}
+		rest := strings.TrimPrefix(k, prefix)
+		if delimiter != "" {
+			if idx := strings.Index(rest, delimiter); idx >= 0 {
+				cp := prefix + rest[:idx+len(delimiter)]
+				if !seen[cp] {
+					seen[cp] = true
+					res.CommonPrefixes = append(res.CommonPrefixes, commonPrefix{Prefix: cp})
+					res.KeyCount++
+				}
+				res.NextContinuationToken = k
+				continue
+			}
+		}
+		obj := objects[k]
+		res.Contents = append(res.Contents, content{
+			Key:          k,
+			LastModified: obj.modTime.Format("2006-01-02T15:04:05.000Z"),
+			ETag:         obj.etag,
+			Size:         len(obj.data),
+		})
+		res.KeyCount++
+		res.NextContinuationToken = k
+	}
+	if !res.IsTruncated {
+		res.NextContinuationToken = ""
+	}
+	_ = xml.NewEncoder(w).Encode(res)
+}
+
+func (f *fakeS3) deleteObjects(w http.ResponseWriter, r *http.Request, objects map[string]*fakeS3Object) {
+	var req struct {
+		Objects []struct {
+			Key string
+		} `xml:"Object"`
+	}
+	body, _ := io.ReadAll(r.Body)
+	if err := xml.Unmarshal(body, &req); err != nil {
+		f.writeError(w, http.StatusBadRequest, "MalformedXML")
+		return
+	}
+	for _, obj := range req.Objects {
+		delete(objects, obj.Key)
+	}
+	fmt.Fprint(w, "<DeleteResult></DeleteResult>")
+}
+
+// setupS3Backend starts a fake S3 server holding the given buckets and
+// returns a WebDAV handler backed by an s3Backend pointed at it.
+func setupS3Backend(t *testing.T, exportBucket string, buckets ...string) (*fakeS3, *s3Backend, *webdav.Handler) {
+	fake := newFakeS3(buckets...)
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+
+	backend, err := newS3Backend(server_utils.OriginExport{
+		FederationPrefix: "/s3",
+		S3Bucket:         exportBucket,
+	}, srv.URL, "us-east-1", "path")
+	require.NoError(t, err)
+
+	handler := &webdav.Handler{
+		FileSystem: backend.FileSystem(),
+		LockSystem: webdav.NewMemLS(),
+	}
+	return fake, backend, handler
+}
+
+func doWebdav(handler http.Handler, method, target string, body io.Reader, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, body)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestS3BackendGet(t *testing.T) {
+	fake, _, handler := setupS3Backend(t, "test-bucket", "test-bucket")
+	content := []byte("hello from the S3 backend")
+	fake.put("test-bucket", "dir/hello.txt", content)
+
+	t.Run("full-object", func(t *testing.T) {
+		rec := doWebdav(handler, http.MethodGet, "/dir/hello.txt", nil, nil)
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, content, rec.Body.Bytes())
+		assert.Equal(t, fake.buckets["test-bucket"]["dir/hello.txt"].etag, rec.Header().Get("ETag"))
+		assert.Equal(t, "text/plain; charset=utf-8", rec.Header().Get("Content-Type"))
+	})
+
+	t.Run("ranged", func(t *testing.T) {
+		fake.requests = nil
+		rec := doWebdav(handler, http.MethodGet, "/dir/hello.txt", nil, map[string]string{"Range": "bytes=6-9"})
+		require.Equal(t, http.StatusPartialContent, rec.Code)
+		assert.Equal(t, "from", rec.Body.String())
+		// The object body must be fetched with a ranged GET rather than
+		// downloading the object and discarding the prefix.
+		gets := 0
+		for _, r := range fake.requests {
+			if strings.HasPrefix(r, "GET /test-bucket/dir/hello.txt") {
+				gets++
+			}
+		}
+		assert.Equal(t, 1, gets)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		rec := doWebdav(handler, http.MethodGet, "/dir/missing.txt", nil, nil)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestS3BackendPut(t *testing.T) {
+	fake, backend, handler := setupS3Backend(t, "test-bucket", "test-bucket")
+
+	t.Run("single-part", func(t *testing.T) {
+		content := []byte("small object")
+		rec := doWebdav(handler, http.MethodPut, "/uploads/small.txt", bytes.NewReader(content), nil)
+		require.Equal(t, http.StatusCreated, rec.Code)
+		obj := fake.buckets["test-bucket"]["uploads/small.txt"]
+		require.NotNil(t, obj)
+		assert.Equal(t, content, obj.data)
+		assert.Equal(t, obj.etag, rec.Header().Get("ETag"))
+	})
+
+	t.Run("multipart", func(t *testing.T) {
+		content := bytes.Repeat([]byte("0123456789abcdef"), (s3UploadPartSize+1024)/16)
+		rec := doWebdav(handler, http.MethodPut, "/uploads/large.bin", bytes.NewReader(content), nil)
+		require.Equal(t, http.StatusCreated, rec.Code)
+		obj := fake.buckets["test-bucket"]["uploads/large.bin"]
+		require.NotNil(t, obj)
+		assert.Equal(t, content, obj.data)
+		assert.True(t, strings.HasSuffix(obj.etag, `-2"`), "expected a two-part upload, got ETag %s", obj.etag)
+		assert.Equal(t, obj.etag, rec.Header().Get("ETag"))
+
+		// Multipart ETags are not content MD5s, so no md5 digest is offered
+		digests, err := backend.Checksummer().GetDigests(context.Background(), "/uploads/large.bin", "md5")
+		require.NoError(t, err)
+		assert.Empty(t, digests)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		rec := doWebdav(handler, http.MethodPut, "/uploads/empty", bytes.NewReader(nil), nil)
+		require.Equal(t, http.StatusCreated, rec.Code)
+		obj := fake.buckets["test-bucket"]["uploads/empty"]
+		require.NotNil(t, obj)
+		assert.Empty(t, obj.data)
+	})
+
+	t.Run("onto-directory", func(t *testing.T) {
+		rec := doWebdav(handler, http.MethodPut, "/uploads", bytes.NewReader([]byte("x")), nil)
+		assert.Equal(t, http.StatusConflict, rec.Code)
+	})
+
+	t.Run("failed-body", func(t *testing.T) {
+		// A client that disconnects mid-upload must not leave a truncated
+		// object behind, for either a single PutObject or a multipart
+		// upload.
+		fake.put("test-bucket", "uploads/existing.bin", []byte("original"))
+		for _, size := range []int{1024, s3UploadPartSize + 1024} {
+			body := io.MultiReader(bytes.NewReader(make([]byte, size)), iotest.ErrReader(io.ErrUnexpectedEOF))
+			req := httptest.NewRequest(http.MethodPut, "/uploads/existing.bin", body)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, setUploadBody(req))
+			assert.NotEqual(t, http.StatusCreated, rec.Code)
+			obj := fake.buckets["test-bucket"]["uploads/existing.bin"]
+			require.NotNil(t, obj)
+			assert.Equal(t, []byte("original"), obj.data)
+		}
+		assert.Empty(t, fake.uploads, "multipart upload should have been aborted")
+	})
+
+	t.Run("short-body", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPut, "/uploads/short.bin", bytes.NewReader([]byte("short")))
+		req.ContentLength = 100
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, setUploadBody(req))
+		assert.NotEqual(t, http.StatusCreated, rec.Code)
+		assert.Nil(t, fake.buckets["test-bucket"]["uploads/short.bin"])
+	})
+}
+
+func TestS3PartSize(t *testing.T) {
+	const mib = 1024 * 1024
+	assert.Equal(t, int64(s3UploadPartSize), s3PartSize(-1))
+	assert.Equal(t, int64(s3UploadPartSize), s3PartSize(0))
+	assert.Equal(t, int64(s3UploadPartSize), s3PartSize(100*1024*mib))
+
+	// A 1 TiB object needs parts just over 100 MiB to fit in 10,000 parts
+	size := int64(1024 * 1024 * mib)
+	partSize := s3PartSize(size)
+	assert.Zero(t, partSize%mib)
+	assert.LessOrEqual(t, (size+partSize-1)/partSize, int64(s3manager.MaxUploadParts))
+	assert.Equal(t, int64(105*mib), partSize)
+}
+
+func TestS3BackendChecksummer(t *testing.T) {
+	fake, backend, _ := setupS3Backend(t, "test-bucket", "test-bucket")
+	content := []byte("checksum me")
+	fake.put("test-bucket", "file", content)
+
+	digests, err := backend.Checksummer().GetDigests(context.Background(), "/file", "md5, crc32c")
+	require.NoError(t, err)
+	sum := md5.Sum(content)
+	// crc32c is skipped since the object carries no additional checksums
+	assert.Equal(t, []string{"md5=" + base64.StdEncoding.EncodeToString(sum[:])}, digests)
+
+	_, err = backend.Checksummer().GetDigests(context.Background(), "/missing", "md5")
+	assert.Error(t, err)
+}
+
+func TestS3BackendPropfind(t *testing.T) {
+	fake, _, handler := setupS3Backend(t, "test-bucket", "test-bucket")
+	fake.put("test-bucket", "data/a.txt", []byte("a"))
+	fake.put("test-bucket", "data/b.txt", []byte("bb"))
+	fake.put("test-bucket", "data/sub/c.txt", []byte("ccc"))
+	fake.put("test-bucket", "other.txt", []byte("o"))
+
+	rec := doWebdav(handler, "PROPFIND", "/data", nil, map[string]string{"Depth": "1"})
+	require.Equal(t, http.StatusMultiStatus, rec.Code)
+	body := rec.Body.String()
+	assert.Contains(t, body, "<D:href>/data/</D:href>")
+	assert.Contains(t, body, "<D:href>/data/a.txt</D:href>")
+	assert.Contains(t, body, "<D:href>/data/b.txt</D:href>")
+	assert.Contains(t, body, "<D:href>/data/sub/</D:href>")
+	assert.NotContains(t, body, "c.txt")
+	assert.NotContains(t, body, "other.txt")
+	assert.Contains(t, body, "<D:getcontentlength>2</D:getcontentlength>")
+
+	rec = doWebdav(handler, "PROPFIND", "/nonexistent", nil, map[string]string{"Depth": "1"})
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestS3BackendMkcolDeleteMove(t *testing.T) {
+	fake, _, handler := setupS3Backend(t, "test-bucket", "test-bucket")
+	fake.put("test-bucket", "tree/one", []byte("1"))
+	fake.put("test-bucket", "tree/nested/two", []byte("2"))
+	fake.put("test-bucket", "keep", []byte("k"))
+
+	rec := doWebdav(handler, "MKCOL", "/newdir", nil, nil)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	assert.Contains(t, fake.buckets["test-bucket"], "newdir/")
+
+	// The empty directory is visible through its marker object
+	rec = doWebdav(handler, "PROPFIND", "/newdir", nil, map[string]string{"Depth": "0"})
+	assert.Equal(t, http.StatusMultiStatus, rec.Code)
+
+	rec = doWebdav(handler, "MOVE", "/tree", nil, map[string]string{"Destination": "/moved"})
+	require.Equal(t, http.StatusCreated, rec.Code)
+	assert.Contains(t, fake.buckets["test-bucket"], "moved/one")
+	assert.Contains(t, fake.buckets["test-bucket"], "moved/nested/two")
+	assert.NotContains(t, fake.buckets["test-bucket"], "tree/one")
+
+	rec = doWebdav(handler, http.MethodDelete, "/moved", nil, nil)
+	require.Equal(t, http.StatusNoContent, rec.Code)
+	assert.NotContains(t, fake.buckets["test-bucket"], "moved/one")
+	assert.NotContains(t, fake.buckets["test-bucket"], "moved/nested/two")
+	assert.Contains(t, fake.buckets["test-bucket"], "keep")
+}
+
+func TestS3BackendBucketlessMode(t *testing.T) {
+	fake, _, handler := setupS3Backend(t, "", "bucket-one", "bucket-two")
+	fake.put("bucket-two", "obj.txt", []byte("in bucket two"))
+
+	rec := doWebdav(handler, http.MethodGet, "/bucket-two/obj.txt", nil, nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "in bucket two", rec.Body.String())
+
+	rec = doWebdav(handler, "PROPFIND", "/", nil, map[string]string{"Depth": "1"})
+	require.Equal(t, http.StatusMultiStatus, rec.Code)
+	assert.Contains(t, rec.Body.String(), "<D:href>/bucket-one/</D:href>")
+	assert.Contains(t, rec.Body.String(), "<D:href>/bucket-two/</D:href>")
+
+	rec = doWebdav(handler, http.MethodGet, "/no-such-bucket/obj.txt", nil, nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
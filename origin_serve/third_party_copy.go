@@ -0,0 +1,464 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package origin_serve
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/webdav"
+
+	"github.com/pelicanplatform/pelican/config"
+	"github.com/pelicanplatform/pelican/param"
+)
+
+// Third-party copies (TPC) move an object between the origin and another
+// server without the data passing through the client, following the WLCG
+// HTTP-TPC specification:
+//
+//   - In a pull, the client sends COPY to the destination with the URL of the
+//     object in the Source header, and the origin GETs it into its storage.
+//   - In a push, the client sends COPY to the origin holding the object with
+//     the URL to write in the Destination header, and the origin PUTs it there.
+//
+// Credentials for the other server travel in headers prefixed with
+// TransferHeader, which the origin forwards with the prefix stripped; e.g.,
+// TransferHeaderAuthorization is sent on as Authorization.  Once the remote
+// server has been reached, the origin answers 202 Accepted and streams
+// performance markers with the number of bytes moved so far, ending the body
+// with a "success:" or "failure:" line.
+//
+// A COPY whose Destination is on the origin itself is a plain WebDAV copy
+// and is left to the WebDAV handler.
+//
+// Since the origin makes requests on the client's behalf, third-party copies
+// always need a token (see authMiddleware) and may only reach the servers
+// permitted by Origin.ThirdPartyCopyAllowedHosts and
+// Origin.ThirdPartyCopyDeniedHosts.
+
+type tpcMode int
+
+const (
+	tpcNone tpcMode = iota
+	tpcPull
+	tpcPush
+)
+
+const tpcTransferHeaderPrefix = "Transferheader"
+
+// How often performance markers are sent during a third-party copy
+var tpcMarkerInterval = 5 * time.Second
+
+// countingReader tallies the bytes read through it for performance markers
+type countingReader struct {
+	reader io.Reader
+	count  *atomic.Int64
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.reader.Read(p)
+	cr.count.Add(int64(n))
+	return n, err
+}
+
+// Determine whether a request is a third-party pull or push and, if so, the
+// URL of the remote object
+func thirdPartyCopyMode(r *http.Request) (tpcMode, string) {
+	if r.Method != "COPY" {
+		return tpcNone, ""
+	}
+	if source := r.Header.Get("Source"); source != "" {
+		return tpcPull, source
+	}
+	destination := r.Header.Get("Destination")
+	if destUrl, err := url.Parse(destination); err == nil && destUrl.IsAbs() && destUrl.Host != r.Host {
+		return tpcPush, destination
+	}
+	return tpcNone, ""
+}
+
+// A tpcHostRule matches remote servers by hostname, domain or address
+type tpcHostRule struct {
+	host    string     // an exact hostname
+	domain  string     // a domain suffix, from a "*.example.org" entry
+	network *net.IPNet // an address or CIDR range
+}
+
+// tpcHostPolicy decides which remote servers a third-party copy may contact
+type tpcHostPolicy struct {
+	allowed []tpcHostRule
+	denied  []tpcHostRule
+	dialer  *net.Dialer
+}
+
+func parseTpcHostRules(entries []string) ([]tpcHostRule, error) {
+	rules := make([]tpcHostRule, 0, len(entries))
+	for _, entry := range entries {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if strings.Contains(entry, "/") {
+			_, network, err := net.ParseCIDR(entry)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid CIDR range %q", entry)
+			}
+			rules = append(rules, tpcHostRule{network: network})
+		} else if ip := net.ParseIP(entry); ip != nil {
+			bits := 8 * len(ip.To16())
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 32
+			}
+			rules = append(rules, tpcHostRule{network: &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}})
+		} else if domain, found := strings.CutPrefix(entry, "*."); found {
+			rules = append(rules, tpcHostRule{domain: "." + domain})
+		} else {
+			rules = append(rules, tpcHostRule{host: entry})
+		}
+	}
+	return rules, nil
+}
+
+// Build the host policy of third-party copies from the origin's configuration
+func newTpcHostPolicy() (*tpcHostPolicy, error) {
+	allowed, err := parseTpcHostRules(param.Origin_ThirdPartyCopyAllowedHosts.GetStringSlice())
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid Origin.ThirdPartyCopyAllowedHosts")
+	}
+	denied, err := parseTpcHostRules(param.Origin_ThirdPartyCopyDeniedHosts.GetStringSlice())
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid Origin.ThirdPartyCopyDeniedHosts")
+	}
+	return &tpcHostPolicy{
+		allowed: allowed,
+		denied:  denied,
+		dialer: &net.Dialer{
+			Timeout:   param.Transport_DialerTimeout.GetDuration(),
+			KeepAlive: param.Transport_DialerKeepAlive.GetDuration(),
+		},
+	}, nil
+}
+
+func matchesHostName(rules []tpcHostRule, hostname string) bool {
+	hostname = strings.TrimSuffix(strings.ToLower(hostname), ".")
+	for _, rule := range rules {
+		if (rule.host != "" && rule.host == hostname) || (rule.domain != "" && strings.HasSuffix(hostname, rule.domain)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Whether the rules name the hostname exactly, rather than through a domain
+func namesHost(rules []tpcHostRule, hostname string) bool {
+	hostname = strings.TrimSuffix(strings.ToLower(hostname), ".")
+	for _, rule := range rules {
+		if rule.host != "" && rule.host == hostname {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAddress(rules []tpcHostRule, ip net.IP) bool {
+	for _, rule := range rules {
+		if rule.network != nil && rule.network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// Whether an address reaches the origin's own host or its internal network.
+// Connecting to the unspecified address reaches the local host, too.
+func isInternalAddress(ip net.IP) bool {
+	return ip.IsUnspecified() || ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast()
+}
+
+// Check the hostname of a remote server.  Addresses are checked when
+// connecting, by checkAddress.
+func (p *tpcHostPolicy) checkHostName(hostname string) error {
+	if matchesHostName(p.denied, hostname) {
+		return errors.Errorf("third-party copies with %s are not permitted", hostname)
+	}
+	if ip := net.ParseIP(hostname); ip != nil {
+		return p.checkAddress(hostname, ip)
+	}
+	return nil
+}
+
+// Check an address of a remote server.  Internal addresses are refused
+// whatever the deny list says, unless the allow list names the server or
+// the address explicitly.
+func (p *tpcHostPolicy) checkAddress(hostname string, ip net.IP) error {
+	named := namesHost(p.allowed, hostname) || matchesAddress(p.allowed, ip)
+	allowed := len(p.allowed) == 0 || named || matchesHostName(p.allowed, hostname)
+	if matchesAddress(p.denied, ip) || !allowed || (isInternalAddress(ip) && !named) {
+		return errors.Errorf("third-party copies with %s (%s) are not permitted", hostname, ip)
+	}
+	return nil
+}
+
+// Connect to a remote server, only using the addresses the policy permits.
+// Resolving the hostname here, rather than checking the address after the
+// fact, ensures the address checked is the one connected to.
+func (p *tpcHostPolicy) dialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	hostname, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	if err = p.checkHostName(hostname); err != nil {
+		return nil, err
+	}
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, hostname)
+	if err != nil {
+		return nil, err
+	}
+	err = errors.Errorf("no addresses found for %s", hostname)
+	for _, ipAddr := range addrs {
+		if err = p.checkAddress(hostname, ipAddr.IP); err != nil {
+			continue
+		}
+		var conn net.Conn
+		if conn, err = p.dialer.DialContext(ctx, network, net.JoinHostPort(ipAddr.IP.String(), port)); err == nil {
+			return conn, nil
+		}
+	}
+	return nil, err
+}
+
+// Create the client for the requests of a third-party copy.  It doesn't use
+// a proxy, so that the policy sees the addresses of the remote servers, and
+// checks the servers it is redirected to as well.
+func (p *tpcHostPolicy) client() *http.Client {
+	transport := config.GetTransport().Clone()
+	transport.Proxy = nil
+	transport.DialContext = p.dialContext
+	return &http.Client{
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return errors.New("stopped after 10 redirects")
+			}
+			return p.checkHostName(req.URL.Hostname())
+		},
+	}
+}
+
+// Collect the TransferHeader-prefixed headers of a request, to be sent to
+// the remote server of a third-party copy without their prefix
+func tpcTransferHeaders(r *http.Request) http.Header {
+	headers := make(http.Header)
+	for name, values := range r.Header {
+		canonical := http.CanonicalHeaderKey(name)
+		if len(canonical) > len(tpcTransferHeaderPrefix) && strings.HasPrefix(canonical, tpcTransferHeaderPrefix) {
+			headers[http.CanonicalHeaderKey(canonical[len(tpcTransferHeaderPrefix):])] = values
+		}
+	}
+	return headers
+}
+
+// Write a performance marker reporting the bytes moved by a third-party copy
+func writePerfMarker(w io.Writer, transferred int64) {
+	fmt.Fprintf(w, "Perf Marker\n\tTimestamp: %d\n\tStripe Index: 0\n\tStripe Bytes Transferred: %d\n\tTotal Stripe Count: 1\nEnd\n",
+		time.Now().Unix(), transferred)
+}
+
+// handleThirdPartyCopy handles a COPY request pulling the object at relativePath
+// from, or pushing it to, the remote URL
+func handleThirdPartyCopy(c *gin.Context, handler *webdav.Handler, mode tpcMode, remote string, relativePath string) {
+	if !param.Origin_EnableThirdPartyCopy.GetBool() {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "third-party copies are disabled on this origin"})
+		return
+	}
+	remoteUrl, err := url.Parse(remote)
+	if err != nil || (remoteUrl.Scheme != "https" && remoteUrl.Scheme != "http") || remoteUrl.Host == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid third-party copy URL %q", remote)})
+		return
+	}
+	if credential := c.Request.Header.Get("Credential"); credential != "" && credential != "none" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "delegated credentials are not supported; pass the token of the remote server in the TransferHeaderAuthorization header"})
+		return
+	}
+	policy, err := newTpcHostPolicy()
+	if err != nil {
+		log.Errorf("Failed to set up a third-party copy: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "the origin's third-party copy configuration is invalid"})
+		return
+	}
+	if err := policy.checkHostName(remoteUrl.Hostname()); err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	client := policy.client()
+	defer client.CloseIdleConnections()
+
+	if mode == tpcPull {
+		pullObject(c, client, handler.FileSystem, remoteUrl, relativePath)
+	} else {
+		pushObject(c, client, handler.FileSystem, remoteUrl, relativePath)
+	}
+}
+
+// Pull the object at source into the origin's storage.  The object is written
+// to a staging file next to it and only replaces any existing object once it
+// has been received in full.
+func pullObject(c *gin.Context, client *http.Client, fs webdav.FileSystem, source *url.URL, relativePath string) {
+	ctx := c.Request.Context()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source.String(), nil)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.Header = tpcTransferHeaders(c.Request)
+	resp, err := client.Do(req)
+	if err != nil {
+		log.Warningf("Third-party copy failed to reach the source %s: %v", source.Redacted(), err)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "failed to reach the source: " + err.Error()})
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		c.JSON(http.StatusBadGateway, gin.H{"error": fmt.Sprintf("the source responded with %s", resp.Status)})
+		return
+	}
+
+	stagingPath := uploadStagingPath(relativePath, "tpc-"+uuid.NewString())
+	file, err := fs.OpenFile(ctx, stagingPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0666)
+	if err != nil {
+		log.Errorf("Failed to open a staging file for a third-party copy to %s: %v", relativePath, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to open the object for writing"})
+		return
+	}
+	runThirdPartyCopy(c, "pull", source, relativePath, func(transferred *atomic.Int64) error {
+		_, err := io.Copy(file, &countingReader{reader: resp.Body, count: transferred})
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err == nil && resp.ContentLength >= 0 && transferred.Load() != resp.ContentLength {
+			err = errors.Errorf("received %d of the %d bytes of the object", transferred.Load(), resp.ContentLength)
+		}
+		if err == nil {
+			err = fs.Rename(ctx, stagingPath, relativePath)
+		}
+		if err != nil {
+			// Only the staging file is removed, so any existing object is left
+			// untouched; the request context may be gone
+			if rmErr := fs.RemoveAll(context.Background(), stagingPath); rmErr != nil {
+				log.Warningf("Failed to remove %s after a failed third-party copy: %v", stagingPath, rmErr)
+			}
+		}
+		return err
+	})
+}
+
+// Push the object in the origin's storage to destination
+func pushObject(c *gin.Context, client *http.Client, fs webdav.FileSystem, destination *url.URL, relativePath string) {
+	ctx := c.Request.Context()
+	file, err := fs.OpenFile(ctx, relativePath, os.O_RDONLY, 0)
+	if err != nil {
+		if os.IsNotExist(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "object not found"})
+		} else {
+			log.Errorf("Failed to open %s for a third-party copy: %v", relativePath, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to open the object"})
+		}
+		return
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		log.Errorf("Failed to stat %s for a third-party copy: %v", relativePath, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to open the object"})
+		return
+	} else if info.IsDir() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "collections cannot be pushed"})
+		return
+	}
+
+	runThirdPartyCopy(c, "push", destination, relativePath, func(transferred *atomic.Int64) error {
+		var body io.Reader = http.NoBody
+		if info.Size() > 0 {
+			body = &countingReader{reader: file, count: transferred}
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPut, destination.String(), body)
+		if err != nil {
+			return err
+		}
+		req.Header = tpcTransferHeaders(c.Request)
+		req.ContentLength = info.Size()
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return errors.Errorf("the destination responded with %s", resp.Status)
+		}
+		return nil
+	})
+}
+
+// Run the transfer of a third-party copy, streaming performance markers to
+// the client until it completes
+func runThirdPartyCopy(c *gin.Context, direction string, remote *url.URL, relativePath string, transfer func(transferred *atomic.Int64) error) {
+	var transferred atomic.Int64
+	done := make(chan error, 1)
+	go func() {
+		done <- transfer(&transferred)
+	}()
+
+	c.Header("Content-Type", "text/plain")
+	c.Status(http.StatusAccepted)
+	c.Writer.WriteHeaderNow()
+	c.Writer.Flush()
+
+	ticker := time.NewTicker(tpcMarkerInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			writePerfMarker(c.Writer, transferred.Load())
+			c.Writer.Flush()
+		case err := <-done:
+			writePerfMarker(c.Writer, transferred.Load())
+			if err != nil {
+				log.Warningf("Third-party %s of %s with %s failed after %d bytes: %v", direction, relativePath, remote.Redacted(), transferred.Load(), err)
+				fmt.Fprintf(c.Writer, "failure: %s\n", strings.ReplaceAll(err.Error(), "\n", " "))
+			} else {
+				log.Debugf("Third-party %s of %s with %s moved %d bytes", direction, relativePath, remote.Redacted(), transferred.Load())
+				fmt.Fprintln(c.Writer, "success: Created")
+			}
+			c.Writer.Flush()
+			return
+		}
+	}
+}
@@ -0,0 +1,305 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package origin_serve
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/webdav"
+
+	"github.com/pelicanplatform/pelican/config"
+	"github.com/pelicanplatform/pelican/param"
+	"github.com/pelicanplatform/pelican/server_utils"
+	"github.com/pelicanplatform/pelican/utils"
+)
+
+func TestThirdPartyCopyMode(t *testing.T) {
+	newCopy := func(headers map[string]string) *http.Request {
+		req := httptest.NewRequest("COPY", "https://origin.example.com/test/object", nil)
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		return req
+	}
+
+	mode, remote := thirdPartyCopyMode(newCopy(map[string]string{"Source": "https://other.example.com/obj"}))
+	assert.Equal(t, tpcPull, mode)
+	assert.Equal(t, "https://other.example.com/obj", remote)
+
+	mode, remote = thirdPartyCopyMode(newCopy(map[string]string{"Destination": "https://other.example.com/obj"}))
+	assert.Equal(t, tpcPush, mode)
+	assert.Equal(t, "https://other.example.com/obj", remote)
+
+	// Copies within the origin are WebDAV copies
+	mode, _ = thirdPartyCopyMode(newCopy(map[string]string{"Destination": "/test/copy"}))
+	assert.Equal(t, tpcNone, mode)
+	mode, _ = thirdPartyCopyMode(newCopy(map[string]string{"Destination": "https://origin.example.com/test/copy"}))
+	assert.Equal(t, tpcNone, mode)
+	mode, _ = thirdPartyCopyMode(httptest.NewRequest(http.MethodGet, "/test/object", nil))
+	assert.Equal(t, tpcNone, mode)
+}
+
+func TestTpcTransferHeaders(t *testing.T) {
+	req := httptest.NewRequest("COPY", "/test/object", nil)
+	req.Header.Set("TransferHeaderAuthorization", "Bearer remote")
+	req.Header.Set("transferheaderx-pelican-jobid", "job1")
+	req.Header.Set("Authorization", "Bearer local")
+	req.Header.Set("TransferHeader", "empty")
+
+	assert.Equal(t, http.Header{
+		"Authorization":   {"Bearer remote"},
+		"X-Pelican-Jobid": {"job1"},
+	}, tpcTransferHeaders(req))
+}
+
+func TestTpcHostPolicy(t *testing.T) {
+	config.ResetConfig()
+	t.Cleanup(config.ResetConfig)
+	require.NoError(t, param.Origin_ThirdPartyCopyDeniedHosts.Set([]string{"127.0.0.0/8", "10.0.0.0/8", "bad.example.org"}))
+
+	policy, err := newTpcHostPolicy()
+	require.NoError(t, err)
+	assert.NoError(t, policy.checkHostName("origin.example.org"))
+	assert.Error(t, policy.checkHostName("bad.example.org"))
+	assert.Error(t, policy.checkHostName("127.0.0.1"))
+	assert.Error(t, policy.checkAddress("internal.example.org", net.ParseIP("10.1.2.3")))
+	assert.NoError(t, policy.checkAddress("origin.example.org", net.ParseIP("192.0.2.1")))
+
+	// Internal addresses are refused even when the deny list misses them
+	for _, addr := range []string{"0.0.0.0", "::", "::1", "192.168.1.1", "169.254.169.254", "fe80::1"} {
+		assert.Error(t, policy.checkHostName(addr), addr)
+		assert.Error(t, policy.checkAddress("origin.example.org", net.ParseIP(addr)), addr)
+	}
+
+	// With an allow list, a server must match it by name or address
+	require.NoError(t, param.Origin_ThirdPartyCopyAllowedHosts.Set([]string{"*.example.org", "192.0.2.0/24", "10.0.0.1", "storage.internal", "192.168.1.0/24"}))
+	policy, err = newTpcHostPolicy()
+	require.NoError(t, err)
+	assert.NoError(t, policy.checkAddress("origin.example.org", net.ParseIP("198.51.100.7")))
+	assert.NoError(t, policy.checkAddress("origin.example.com", net.ParseIP("192.0.2.7")))
+	assert.Error(t, policy.checkAddress("origin.example.com", net.ParseIP("198.51.100.7")))
+	assert.Error(t, policy.checkHostName("bad.example.org"), "the deny list takes precedence")
+	assert.Error(t, policy.checkHostName("10.0.0.1"), "the deny list takes precedence")
+
+	// Internal addresses are only permitted when the allow list names them
+	assert.NoError(t, policy.checkHostName("192.168.1.1"))
+	assert.NoError(t, policy.checkAddress("storage.internal", net.ParseIP("172.16.0.5")))
+	assert.Error(t, policy.checkAddress("origin.example.org", net.ParseIP("172.16.0.5")), "a domain wildcard doesn't name the server")
+	assert.Error(t, policy.checkHostName("0.0.0.0"))
+
+	require.NoError(t, param.Origin_ThirdPartyCopyAllowedHosts.Set([]string{"10.0.0.0/33"}))
+	_, err = newTpcHostPolicy()
+	assert.Error(t, err)
+}
+
+func TestThirdPartyCopy(t *testing.T) {
+	config.ResetConfig()
+	t.Cleanup(config.ResetConfig)
+	config.SetBaseDefaultsInConfig(viper.GetViper())
+	_, err := param.Refresh()
+	require.NoError(t, err)
+	require.NoError(t, param.Origin_EnableThirdPartyCopy.Set(true))
+
+	storageDir := t.TempDir()
+	osRootFs, err := server_utils.NewOsRootFs(storageDir)
+	require.NoError(t, err)
+	handler := &webdav.Handler{
+		FileSystem: newAferoFileSystem(newAutoCreateDirFs(osRootFs), "", nil),
+		LockSystem: webdav.NewMemLS(),
+	}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Handle("COPY", "/*path", func(c *gin.Context) {
+		mode, remote := thirdPartyCopyMode(c.Request)
+		handleThirdPartyCopy(c, handler, mode, remote, c.Param("path"))
+	})
+
+	data := bytes.Repeat([]byte("0123456789"), 100)
+	var pushed []byte
+	remote := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer remote" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/remote/object":
+			if r.Method == http.MethodGet {
+				_, _ = w.Write(data)
+			} else {
+				pushed, _ = io.ReadAll(r.Body)
+				w.WriteHeader(http.StatusCreated)
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(remote.Close)
+
+	tpc := func(path string, headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("COPY", path, nil)
+		req.Header.Set("TransferHeaderAuthorization", "Bearer remote")
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("unspecified-address", func(t *testing.T) {
+		// Connecting to 0.0.0.0 reaches the origin's own host
+		require.Contains(t, param.Origin_ThirdPartyCopyDeniedHosts.GetStringSlice(), "0.0.0.0/8")
+		remoteUrl, err := url.Parse(remote.URL)
+		require.NoError(t, err)
+		unspecified := "http://0.0.0.0:" + remoteUrl.Port() + "/remote/object"
+		w := tpc("/dir/unspecified", map[string]string{"Source": unspecified})
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		_, err = os.Stat(filepath.Join(storageDir, "dir", "unspecified"))
+		assert.True(t, os.IsNotExist(err))
+
+		// Internal addresses are refused even when the deny list misses them
+		require.NoError(t, param.Origin_ThirdPartyCopyDeniedHosts.Set([]string{}))
+		w = tpc("/dir/unspecified", map[string]string{"Source": unspecified})
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		w = tpc("/dir/unspecified", map[string]string{"Source": remote.URL + "/remote/object"})
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	// The remote servers of the remaining tests listen on the loopback interface
+	require.NoError(t, param.Origin_ThirdPartyCopyDeniedHosts.Set([]string{}))
+	require.NoError(t, param.Origin_ThirdPartyCopyAllowedHosts.Set([]string{"127.0.0.1"}))
+
+	t.Run("pull", func(t *testing.T) {
+		w := tpc("/dir/pulled", map[string]string{"Source": remote.URL + "/remote/object"})
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		assert.Contains(t, w.Body.String(), "Stripe Bytes Transferred: 1000\n")
+		assert.Contains(t, w.Body.String(), "success: Created\n")
+		written, err := os.ReadFile(filepath.Join(storageDir, "dir", "pulled"))
+		require.NoError(t, err)
+		assert.Equal(t, data, written)
+	})
+
+	t.Run("pull-failure-keeps-object", func(t *testing.T) {
+		// A source that sends less than it promised must not clobber the existing object
+		short := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Length", "2000")
+			_, _ = w.Write(data)
+		}))
+		t.Cleanup(short.Close)
+		w := tpc("/dir/pulled", map[string]string{"Source": short.URL + "/remote/object"})
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		assert.Contains(t, w.Body.String(), "failure: ")
+		written, err := os.ReadFile(filepath.Join(storageDir, "dir", "pulled"))
+		require.NoError(t, err)
+		assert.Equal(t, data, written)
+		staged, err := filepath.Glob(filepath.Join(storageDir, "dir", utils.ResumableUploadStagingPrefix+"*"))
+		require.NoError(t, err)
+		assert.Empty(t, staged)
+	})
+
+	t.Run("denied-host", func(t *testing.T) {
+		require.NoError(t, param.Origin_ThirdPartyCopyDeniedHosts.Set([]string{"127.0.0.0/8", "::1/128"}))
+		t.Cleanup(func() { require.NoError(t, param.Origin_ThirdPartyCopyDeniedHosts.Set([]string{})) })
+		w := tpc("/dir/denied", map[string]string{"Source": remote.URL + "/remote/object"})
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		w = tpc("/dir/pulled", map[string]string{"Destination": remote.URL + "/remote/object"})
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		// Hostnames are checked against the addresses they resolve to
+		w = tpc("/dir/denied", map[string]string{"Source": strings.Replace(remote.URL, "127.0.0.1", "localhost", 1) + "/remote/object"})
+		assert.Equal(t, http.StatusBadGateway, w.Code)
+		assert.Contains(t, w.Body.String(), "not permitted")
+	})
+
+	t.Run("pull-missing-source", func(t *testing.T) {
+		w := tpc("/dir/missing", map[string]string{"Source": remote.URL + "/remote/missing"})
+		assert.Equal(t, http.StatusBadGateway, w.Code)
+		assert.Contains(t, w.Body.String(), "404")
+		_, err := os.Stat(filepath.Join(storageDir, "dir", "missing"))
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("push", func(t *testing.T) {
+		w := tpc("/dir/pulled", map[string]string{"Destination": remote.URL + "/remote/object"})
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		assert.Contains(t, w.Body.String(), "success: Created\n")
+		assert.Equal(t, data, pushed)
+	})
+
+	t.Run("push-rejected", func(t *testing.T) {
+		// Failures after the transfer started are reported at the end of the body
+		w := tpc("/dir/pulled", map[string]string{"Destination": remote.URL + "/remote/elsewhere"})
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		assert.Contains(t, w.Body.String(), "failure: the destination responded with 404")
+	})
+
+	t.Run("push-missing-object", func(t *testing.T) {
+		w := tpc("/dir/missing", map[string]string{"Destination": remote.URL + "/remote/object"})
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		w := tpc("/dir/pulled", map[string]string{"Source": "file:///etc/passwd"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		w = tpc("/dir/pulled", map[string]string{"Source": remote.URL + "/remote/object", "Credential": "gridsite"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		require.NoError(t, param.Origin_EnableThirdPartyCopy.Set(false))
+		t.Cleanup(func() { require.NoError(t, param.Origin_EnableThirdPartyCopy.Set(true)) })
+		w := tpc("/dir/pulled", map[string]string{"Source": remote.URL + "/remote/object"})
+		assert.Equal(t, http.StatusNotImplemented, w.Code)
+	})
+}
+
+func TestThirdPartyCopyRequiresToken(t *testing.T) {
+	config.ResetConfig()
+	t.Cleanup(config.ResetConfig)
+	require.NoError(t, param.Origin_EnableThirdPartyCopy.Set(true))
+	server, _, cleanup := setupE2ETestServer(t)
+	t.Cleanup(cleanup)
+
+	// The export allows public reads, but pushing an object still needs a token
+	req, err := http.NewRequest("COPY", server.URL+"/test/test.txt", nil)
+	require.NoError(t, err)
+	req.Header.Set("Destination", "http://192.0.2.1/object")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	resp, err = http.Get(server.URL + "/test/test.txt")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
@@ -108,6 +108,8 @@ var runtimeConfigurableMap = map[string]bool{
 	"Cache.DirectorTest": false,
 	"Cache.DisableClientX509": false,
 	"Cache.EnableBroker": false,
+	"Cache.EnableCompression": false,
+	"Cache.EnableDeduplication": false,
 	"Cache.EnableEvictionMonitoring": false,
 	"Cache.EnableLotman": false,
 	"Cache.EnableOIDC": false,
@@ -118,6 +120,7 @@ var runtimeConfigurableMap = map[string]bool{
 	"Cache.EnableVoms": false,
 	"Cache.EvictionMonitoringInterval": false,
 	"Cache.EvictionMonitoringMaxDepth": false,
+	"Cache.EvictionPolicy": false,
 	"Cache.ExportLocation": false,
 	"Cache.FedTokenLocation": false,
 	"Cache.FilesBaseSize": false,
@@ -129,7 +132,9 @@ var runtimeConfigurableMap = map[string]bool{
 	"Cache.MemoryCacheSize": false,
 	"Cache.MetaLocations": false,
 	"Cache.MinDirectorRefreshInterval": false,
+	"Cache.NamespaceEvictionPolicies": false,
 	"Cache.NamespaceLocation": false,
+	"Cache.NamespaceQuotas": false,
 	"Cache.PSSOrigin": false,
 	"Cache.PermittedNamespaces": false,
 	"Cache.Port": false,
@@ -146,6 +151,7 @@ var runtimeConfigurableMap = map[string]bool{
 	"Client.DirectorRetries": false,
 	"Client.DisableHttpProxy": false,
 	"Client.DisableProxyFallback": false,
+	"Client.DisableTransferReports": false,
 	"Client.EnableOverwrites": false,
 	"Client.IsPlugin": false,
 	"Client.MaximumDownloadSpeed": false,
@@ -154,6 +160,11 @@ var runtimeConfigurableMap = map[string]bool{
 	"Client.SlowTransferRampupTime": false,
 	"Client.SlowTransferWindow": false,
 	"Client.StoppedTransferTimeout": false,
+	"Client.StripedDownloadSources": false,
+	"Client.StripedDownloadStripeSize": false,
+	"Client.StripedDownloadThreshold": false,
+	"Client.UploadChunkSize": false,
+	"Client.UploadStateDirectory": false,
 	"Client.WorkerCount": false,
 	"ClientAgent.DbLocation": false,
 	"ClientAgent.HistoryRetentionDays": false,
@@ -195,14 +206,18 @@ var runtimeConfigurableMap = map[string]bool{
 	"Director.RegistryQueryInterval": false,
 	"Director.StatConcurrencyLimit": false,
 	"Director.StatTimeout": false,
+	"Director.StateSnapshotInterval": false,
 	"Director.SupportContactEmail": false,
 	"Director.SupportContactUrl": false,
+	"Director.ThroughputSortEWMATimeConstant": false,
+	"Director.ThroughputSortExplorationPercentage": false,
 	"DisableHttpProxy": false,
 	"DisableProxyFallback": false,
 	"Federation.BrokerUrl": false,
 	"Federation.DirectorUrl": false,
 	"Federation.DiscoveryUrl": false,
 	"Federation.JwkUrl": false,
+	"Federation.RegistryReplicaUrls": false,
 	"Federation.RegistryUrl": false,
 	"Federation.TopologyDowntimeUrl": false,
 	"Federation.TopologyNamespaceUrl": false,
@@ -235,11 +250,16 @@ var runtimeConfigurableMap = map[string]bool{
 	"LocalCache.ChunkSize": false,
 	"LocalCache.DataLocation": false,
 	"LocalCache.DefaultMaxAge": false,
+	"LocalCache.EnableCompression": false,
+	"LocalCache.EnableDeduplication": false,
+	"LocalCache.EvictionPolicy": false,
 	"LocalCache.FDCacheSize": false,
 	"LocalCache.HighWaterMarkPercentage": false,
 	"LocalCache.LowWaterMarkPercentage": false,
 	"LocalCache.MaxConcurrentPrefetch": false,
 	"LocalCache.MemoryCacheSize": false,
+	"LocalCache.NamespaceEvictionPolicies": false,
+	"LocalCache.NamespaceQuotas": false,
 	"LocalCache.PrefetchTimeout": false,
 	"LocalCache.RevalidationJitter": false,
 	"LocalCache.RunLocation": false,
@@ -276,9 +296,19 @@ var runtimeConfigurableMap = map[string]bool{
 	"Lotman.PolicyDefinitions": false,
 	"MinimumDownloadSpeed": false,
 	"Monitoring.AggregatePrefixes": false,
+	"Monitoring.AlertEmailRecipients": false,
+	"Monitoring.AlertEmailSender": false,
+	"Monitoring.AlertEvaluationInterval": false,
+	"Monitoring.AlertRepeatInterval": false,
+	"Monitoring.AlertRuleFiles": false,
+	"Monitoring.AlertSmtpPasswordFile": false,
+	"Monitoring.AlertSmtpServer": false,
+	"Monitoring.AlertSmtpUsername": false,
+	"Monitoring.AlertWebhookUrl": false,
 	"Monitoring.DataLocation": false,
 	"Monitoring.DataRetention": false,
 	"Monitoring.DataRetentionSize": false,
+	"Monitoring.EnableBuiltinAlertRules": false,
 	"Monitoring.EnablePrometheus": false,
 	"Monitoring.LabelLimit": false,
 	"Monitoring.LabelNameLengthLimit": false,
@@ -323,9 +353,11 @@ var runtimeConfigurableMap = map[string]bool{
 	"Origin.EnableIssuer": false,
 	"Origin.EnableListings": false,
 	"Origin.EnableMacaroons": false,
+	"Origin.EnableNativeBackend": false,
 	"Origin.EnableOIDC": false,
 	"Origin.EnablePublicReads": false,
 	"Origin.EnableReads": false,
+	"Origin.EnableThirdPartyCopy": false,
 	"Origin.EnableVoms": false,
 	"Origin.EnableWrite": false,
 	"Origin.EnableWrites": false,
@@ -342,6 +374,7 @@ var runtimeConfigurableMap = map[string]bool{
 	"Origin.GlobusTransferTokenFile": false,
 	"Origin.HttpAuthTokenFile": false,
 	"Origin.HttpServiceUrl": false,
+	"Origin.HttpTokenPassthrough": false,
 	"Origin.IssuerMode": false,
 	"Origin.Mode": false,
 	"Origin.Multiuser": false,
@@ -391,8 +424,11 @@ var runtimeConfigurableMap = map[string]bool{
 	"Origin.StoragePrefix": false,
 	"Origin.StorageType": false,
 	"Origin.SupportedChecksumTypes": false,
+	"Origin.ThirdPartyCopyAllowedHosts": false,
+	"Origin.ThirdPartyCopyDeniedHosts": false,
 	"Origin.TokenAudience": false,
 	"Origin.TransferRateLimit": false,
+	"Origin.UploadStagingExpiry": false,
 	"Origin.UploadTempLocation": false,
 	"Origin.Url": false,
 	"Origin.UserMapfileRefreshInterval": false,
@@ -406,6 +442,8 @@ var runtimeConfigurableMap = map[string]bool{
 	"Registry.Institutions": false,
 	"Registry.InstitutionsUrl": false,
 	"Registry.InstitutionsUrlReloadMinutes": false,
+	"Registry.ReplicationInterval": false,
+	"Registry.ReplicationPeers": false,
 	"Registry.RequireCacheApproval": false,
 	"Registry.RequireKeyChaining": false,
 	"Registry.RequireOriginApproval": false,
@@ -417,6 +455,9 @@ var runtimeConfigurableMap = map[string]bool{
 	"Server.DatabaseBackup.Location": false,
 	"Server.DatabaseBackup.MaxCount": false,
 	"Server.DbLocation": false,
+	"Server.DbPasswordFile": false,
+	"Server.DbType": false,
+	"Server.DbUrl": false,
 	"Server.DirectorUrls": false,
 	"Server.DropPrivileges": false,
 	"Server.EnablePKCS11": false,
@@ -427,6 +468,9 @@ var runtimeConfigurableMap = map[string]bool{
 	"Server.Hostname": false,
 	"Server.IssuerHostname": false,
 	"Server.IssuerJwks": false,
+	"Server.IssuerKeyRotation.Interval": false,
+	"Server.IssuerKeyRotation.OverlapPeriod": false,
+	"Server.IssuerKeyRotation.PublishPeriod": false,
 	"Server.IssuerPort": false,
 	"Server.IssuerUrl": false,
 	"Server.Modules": false,
@@ -475,6 +519,8 @@ var runtimeConfigurableMap = map[string]bool{
 	"Topology.DisableDowntime": false,
 	"Topology.DisableOriginX509": false,
 	"Topology.DisableOrigins": false,
+	"Tracing.Endpoint": false,
+	"Tracing.SamplingPercentage": false,
 	"Transport.BrokerEndpointCacheTTL": false,
 	"Transport.DialerKeepAlive": false,
 	"Transport.DialerTimeout": false,
@@ -541,6 +587,7 @@ var stringAccessors = map[string]func(*Config) string{
 	"Cache.ClientStatisticsLocation": func(c *Config) string { return c.Cache.ClientStatisticsLocation },
 	"Cache.DataLocation": func(c *Config) string { return c.Cache.DataLocation },
 	"Cache.DbLocation": func(c *Config) string { return c.Cache.DbLocation },
+	"Cache.EvictionPolicy": func(c *Config) string { return c.Cache.EvictionPolicy },
 	"Cache.ExportLocation": func(c *Config) string { return c.Cache.ExportLocation },
 	"Cache.FedTokenLocation": func(c *Config) string { return c.Cache.FedTokenLocation },
 	"Cache.FilesBaseSize": func(c *Config) string { return c.Cache.FilesBaseSize },
@@ -561,6 +608,7 @@ var stringAccessors = map[string]func(*Config) string{
 	"ClientAgent.PidFile": func(c *Config) string { return c.ClientAgent.PidFile },
 	"ClientAgent.Socket": func(c *Config) string { return c.ClientAgent.Socket },
 	"Client.CredentialFile": func(c *Config) string { return c.Client.CredentialFile },
+	"Client.UploadStateDirectory": func(c *Config) string { return c.Client.UploadStateDirectory },
 	"Director.AdvertiseUrl": func(c *Config) string { return c.Director.AdvertiseUrl },
 	"Director.CacheSortMethod": func(c *Config) string { return c.Director.CacheSortMethod },
 	"Director.DbLocation": func(c *Config) string { return c.Director.DbLocation },
@@ -589,6 +637,7 @@ var stringAccessors = map[string]func(*Config) string{
 	"Issuer.TomcatLocation": func(c *Config) string { return c.Issuer.TomcatLocation },
 	"LocalCache.ChunkSize": func(c *Config) string { return c.LocalCache.ChunkSize },
 	"LocalCache.DataLocation": func(c *Config) string { return c.LocalCache.DataLocation },
+	"LocalCache.EvictionPolicy": func(c *Config) string { return c.LocalCache.EvictionPolicy },
 	"LocalCache.MemoryCacheSize": func(c *Config) string { return c.LocalCache.MemoryCacheSize },
 	"LocalCache.RunLocation": func(c *Config) string { return c.LocalCache.RunLocation },
 	"LocalCache.Size": func(c *Config) string { return c.LocalCache.Size },
@@ -615,6 +664,11 @@ var stringAccessors = map[string]func(*Config) string{
 	"Lotman.EnabledPolicy": func(c *Config) string { return c.Lotman.EnabledPolicy },
 	"Lotman.LibLocation": func(c *Config) string { return c.Lotman.LibLocation },
 	"Lotman.LotHome": func(c *Config) string { return c.Lotman.LotHome },
+	"Monitoring.AlertEmailSender": func(c *Config) string { return c.Monitoring.AlertEmailSender },
+	"Monitoring.AlertSmtpPasswordFile": func(c *Config) string { return c.Monitoring.AlertSmtpPasswordFile },
+	"Monitoring.AlertSmtpServer": func(c *Config) string { return c.Monitoring.AlertSmtpServer },
+	"Monitoring.AlertSmtpUsername": func(c *Config) string { return c.Monitoring.AlertSmtpUsername },
+	"Monitoring.AlertWebhookUrl": func(c *Config) string { return c.Monitoring.AlertWebhookUrl },
 	"Monitoring.DataLocation": func(c *Config) string { return c.Monitoring.DataLocation },
 	"Monitoring.DataRetentionSize": func(c *Config) string { return c.Monitoring.DataRetentionSize },
 	"OIDC.AuthorizationEndpoint": func(c *Config) string { return c.OIDC.AuthorizationEndpoint },
@@ -678,6 +732,9 @@ var stringAccessors = map[string]func(*Config) string{
 	"RuntimeDir": func(c *Config) string { return c.RuntimeDir },
 	"Server.DatabaseBackup.Location": func(c *Config) string { return c.Server.DatabaseBackup.Location },
 	"Server.DbLocation": func(c *Config) string { return c.Server.DbLocation },
+	"Server.DbPasswordFile": func(c *Config) string { return c.Server.DbPasswordFile },
+	"Server.DbType": func(c *Config) string { return c.Server.DbType },
+	"Server.DbUrl": func(c *Config) string { return c.Server.DbUrl },
 	"Server.ExternalWebUrl": func(c *Config) string { return c.Server.ExternalWebUrl },
 	"Server.Hostname": func(c *Config) string { return c.Server.Hostname },
 	"Server.IssuerHostname": func(c *Config) string { return c.Server.IssuerHostname },
@@ -708,6 +765,7 @@ var stringAccessors = map[string]func(*Config) string{
 	"StagePlugin.MountPrefix": func(c *Config) string { return c.StagePlugin.MountPrefix },
 	"StagePlugin.OriginPrefix": func(c *Config) string { return c.StagePlugin.OriginPrefix },
 	"StagePlugin.ShadowOriginPrefix": func(c *Config) string { return c.StagePlugin.ShadowOriginPrefix },
+	"Tracing.Endpoint": func(c *Config) string { return c.Tracing.Endpoint },
 	"Xrootd.Authfile": func(c *Config) string { return c.Xrootd.Authfile },
 	"Xrootd.ConfigFile": func(c *Config) string { return c.Xrootd.ConfigFile },
 	"Xrootd.DetailedMonitoringHost": func(c *Config) string { return c.Xrootd.DetailedMonitoringHost },
@@ -760,9 +818,12 @@ var stringSliceAccessors = map[string]func(*Config) []string{
 	"Director.CacheResponseHostnames": func(c *Config) []string { return c.Director.CacheResponseHostnames },
 	"Director.FilteredServers": func(c *Config) []string { return c.Director.FilteredServers },
 	"Director.OriginResponseHostnames": func(c *Config) []string { return c.Director.OriginResponseHostnames },
+	"Federation.RegistryReplicaUrls": func(c *Config) []string { return c.Federation.RegistryReplicaUrls },
 	"Issuer.GroupRequirements": func(c *Config) []string { return c.Issuer.GroupRequirements },
 	"Issuer.RedirectUris": func(c *Config) []string { return c.Issuer.RedirectUris },
 	"Monitoring.AggregatePrefixes": func(c *Config) []string { return c.Monitoring.AggregatePrefixes },
+	"Monitoring.AlertEmailRecipients": func(c *Config) []string { return c.Monitoring.AlertEmailRecipients },
+	"Monitoring.AlertRuleFiles": func(c *Config) []string { return c.Monitoring.AlertRuleFiles },
 	"OIDC.Scopes": func(c *Config) []string { return c.OIDC.Scopes },
 	"Origin.DefaultChecksumTypes": func(c *Config) []string { return c.Origin.DefaultChecksumTypes },
 	"Origin.ExportVolumes": func(c *Config) []string { return c.Origin.ExportVolumes },
@@ -770,7 +831,10 @@ var stringSliceAccessors = map[string]func(*Config) []string{
 	"Origin.SSH.RemotePelicanBinaryOverrides": func(c *Config) []string { return c.Origin.SSH.RemotePelicanBinaryOverrides },
 	"Origin.ScitokensRestrictedPaths": func(c *Config) []string { return c.Origin.ScitokensRestrictedPaths },
 	"Origin.SupportedChecksumTypes": func(c *Config) []string { return c.Origin.SupportedChecksumTypes },
+	"Origin.ThirdPartyCopyAllowedHosts": func(c *Config) []string { return c.Origin.ThirdPartyCopyAllowedHosts },
+	"Origin.ThirdPartyCopyDeniedHosts": func(c *Config) []string { return c.Origin.ThirdPartyCopyDeniedHosts },
 	"Registry.AdminUsers": func(c *Config) []string { return c.Registry.AdminUsers },
+	"Registry.ReplicationPeers": func(c *Config) []string { return c.Registry.ReplicationPeers },
 	"Server.AdminGroups": func(c *Config) []string { return c.Server.AdminGroups },
 	"Server.DirectorUrls": func(c *Config) []string { return c.Server.DirectorUrls },
 	"Server.Modules": func(c *Config) []string { return c.Server.Modules },
@@ -818,12 +882,17 @@ var intAccessors = map[string]func(*Config) int{
 	"Client.DirectorRetries": func(c *Config) int { return c.Client.DirectorRetries },
 	"Client.MaximumDownloadSpeed": func(c *Config) int { return c.Client.MaximumDownloadSpeed },
 	"Client.MinimumDownloadSpeed": func(c *Config) int { return c.Client.MinimumDownloadSpeed },
+	"Client.StripedDownloadSources": func(c *Config) int { return c.Client.StripedDownloadSources },
+	"Client.StripedDownloadStripeSize": func(c *Config) int { return c.Client.StripedDownloadStripeSize },
+	"Client.StripedDownloadThreshold": func(c *Config) int { return c.Client.StripedDownloadThreshold },
+	"Client.UploadChunkSize": func(c *Config) int { return c.Client.UploadChunkSize },
 	"Client.WorkerCount": func(c *Config) int { return c.Client.WorkerCount },
 	"Director.AdaptiveSortTruncateConstant": func(c *Config) int { return c.Director.AdaptiveSortTruncateConstant },
 	"Director.CachePresenceCapacity": func(c *Config) int { return c.Director.CachePresenceCapacity },
 	"Director.MaxStatResponse": func(c *Config) int { return c.Director.MaxStatResponse },
 	"Director.MinStatResponse": func(c *Config) int { return c.Director.MinStatResponse },
 	"Director.StatConcurrencyLimit": func(c *Config) int { return c.Director.StatConcurrencyLimit },
+	"Director.ThroughputSortExplorationPercentage": func(c *Config) int { return c.Director.ThroughputSortExplorationPercentage },
 	"LocalCache.FDCacheSize": func(c *Config) int { return c.LocalCache.FDCacheSize },
 	"LocalCache.HighWaterMarkPercentage": func(c *Config) int { return c.LocalCache.HighWaterMarkPercentage },
 	"LocalCache.LowWaterMarkPercentage": func(c *Config) int { return c.LocalCache.LowWaterMarkPercentage },
@@ -853,6 +922,7 @@ var intAccessors = map[string]func(*Config) int{
 	"Server.WebPort": func(c *Config) int { return c.Server.WebPort },
 	"Shoveler.PortHigher": func(c *Config) int { return c.Shoveler.PortHigher },
 	"Shoveler.PortLower": func(c *Config) int { return c.Shoveler.PortLower },
+	"Tracing.SamplingPercentage": func(c *Config) int { return c.Tracing.SamplingPercentage },
 	"Transport.MaxIdleConns": func(c *Config) int { return c.Transport.MaxIdleConns },
 	"Xrootd.DetailedMonitoringPort": func(c *Config) int { return c.Xrootd.DetailedMonitoringPort },
 	"Xrootd.LocalMonitoringPort": func(c *Config) int { return c.Xrootd.LocalMonitoringPort },
@@ -935,6 +1005,8 @@ var boolAccessors = map[string]func(*Config) bool{
 	"Cache.DirectorTest": func(c *Config) bool { return c.Cache.DirectorTest },
 	"Cache.DisableClientX509": func(c *Config) bool { return c.Cache.DisableClientX509 },
 	"Cache.EnableBroker": func(c *Config) bool { return c.Cache.EnableBroker },
+	"Cache.EnableCompression": func(c *Config) bool { return c.Cache.EnableCompression },
+	"Cache.EnableDeduplication": func(c *Config) bool { return c.Cache.EnableDeduplication },
 	"Cache.EnableEvictionMonitoring": func(c *Config) bool { return c.Cache.EnableEvictionMonitoring },
 	"Cache.EnableLotman": func(c *Config) bool { return c.Cache.EnableLotman },
 	"Cache.EnableOIDC": func(c *Config) bool { return c.Cache.EnableOIDC },
@@ -947,6 +1019,7 @@ var boolAccessors = map[string]func(*Config) bool{
 	"Client.AssumeDirectorServerHeader": func(c *Config) bool { return c.Client.AssumeDirectorServerHeader },
 	"Client.DisableHttpProxy": func(c *Config) bool { return c.Client.DisableHttpProxy },
 	"Client.DisableProxyFallback": func(c *Config) bool { return c.Client.DisableProxyFallback },
+	"Client.DisableTransferReports": func(c *Config) bool { return c.Client.DisableTransferReports },
 	"Client.EnableOverwrites": func(c *Config) bool { return c.Client.EnableOverwrites },
 	"Client.IsPlugin": func(c *Config) bool { return c.Client.IsPlugin },
 	"Debug": func(c *Config) bool { return c.Debug },
@@ -963,8 +1036,11 @@ var boolAccessors = map[string]func(*Config) bool{
 	"DisableProxyFallback": func(c *Config) bool { return c.DisableProxyFallback },
 	"Issuer.OIDCPreferClaimsFromIDToken": func(c *Config) bool { return c.Issuer.OIDCPreferClaimsFromIDToken },
 	"Issuer.UserStripDomain": func(c *Config) bool { return c.Issuer.UserStripDomain },
+	"LocalCache.EnableCompression": func(c *Config) bool { return c.LocalCache.EnableCompression },
+	"LocalCache.EnableDeduplication": func(c *Config) bool { return c.LocalCache.EnableDeduplication },
 	"Logging.DisableProgressBars": func(c *Config) bool { return c.Logging.DisableProgressBars },
 	"Lotman.EnableAPI": func(c *Config) bool { return c.Lotman.EnableAPI },
+	"Monitoring.EnableBuiltinAlertRules": func(c *Config) bool { return c.Monitoring.EnableBuiltinAlertRules },
 	"Monitoring.EnablePrometheus": func(c *Config) bool { return c.Monitoring.EnablePrometheus },
 	"Monitoring.MetricAuthorization": func(c *Config) bool { return c.Monitoring.MetricAuthorization },
 	"Monitoring.PromQLAuthorization": func(c *Config) bool { return c.Monitoring.PromQLAuthorization },
@@ -980,12 +1056,15 @@ var boolAccessors = map[string]func(*Config) bool{
 	"Origin.EnableIssuer": func(c *Config) bool { return c.Origin.EnableIssuer },
 	"Origin.EnableListings": func(c *Config) bool { return c.Origin.EnableListings },
 	"Origin.EnableMacaroons": func(c *Config) bool { return c.Origin.EnableMacaroons },
+	"Origin.EnableNativeBackend": func(c *Config) bool { return c.Origin.EnableNativeBackend },
 	"Origin.EnableOIDC": func(c *Config) bool { return c.Origin.EnableOIDC },
 	"Origin.EnablePublicReads": func(c *Config) bool { return c.Origin.EnablePublicReads },
 	"Origin.EnableReads": func(c *Config) bool { return c.Origin.EnableReads },
+	"Origin.EnableThirdPartyCopy": func(c *Config) bool { return c.Origin.EnableThirdPartyCopy },
 	"Origin.EnableVoms": func(c *Config) bool { return c.Origin.EnableVoms },
 	"Origin.EnableWrite": func(c *Config) bool { return c.Origin.EnableWrite },
 	"Origin.EnableWrites": func(c *Config) bool { return c.Origin.EnableWrites },
+	"Origin.HttpTokenPassthrough": func(c *Config) bool { return c.Origin.HttpTokenPassthrough },
 	"Origin.Multiuser": func(c *Config) bool { return c.Origin.Multiuser },
 	"Origin.SSH.AutoAddHostKey": func(c *Config) bool { return c.Origin.SSH.AutoAddHostKey },
 	"Origin.SSH.TunnelCallback": func(c *Config) bool { return c.Origin.SSH.TunnelCallback },
@@ -1060,6 +1139,8 @@ var durationAccessors = map[string]func(*Config) time.Duration{
 	"Director.OriginCacheHealthTestInterval": func(c *Config) time.Duration { return c.Director.OriginCacheHealthTestInterval },
 	"Director.RegistryQueryInterval": func(c *Config) time.Duration { return c.Director.RegistryQueryInterval },
 	"Director.StatTimeout": func(c *Config) time.Duration { return c.Director.StatTimeout },
+	"Director.StateSnapshotInterval": func(c *Config) time.Duration { return c.Director.StateSnapshotInterval },
+	"Director.ThroughputSortEWMATimeConstant": func(c *Config) time.Duration { return c.Director.ThroughputSortEWMATimeConstant },
 	"Federation.TopologyReloadInterval": func(c *Config) time.Duration { return c.Federation.TopologyReloadInterval },
 	"Issuer.DynamicClientStaleTimeout": func(c *Config) time.Duration { return c.Issuer.DynamicClientStaleTimeout },
 	"Issuer.DynamicClientUnusedTimeout": func(c *Config) time.Duration { return c.Issuer.DynamicClientUnusedTimeout },
@@ -1069,6 +1150,8 @@ var durationAccessors = map[string]func(*Config) time.Duration{
 	"Logging.Client.ProgressInterval": func(c *Config) time.Duration { return c.Logging.Client.ProgressInterval },
 	"Lotman.DefaultLotDeletionLifetime": func(c *Config) time.Duration { return c.Lotman.DefaultLotDeletionLifetime },
 	"Lotman.DefaultLotExpirationLifetime": func(c *Config) time.Duration { return c.Lotman.DefaultLotExpirationLifetime },
+	"Monitoring.AlertEvaluationInterval": func(c *Config) time.Duration { return c.Monitoring.AlertEvaluationInterval },
+	"Monitoring.AlertRepeatInterval": func(c *Config) time.Duration { return c.Monitoring.AlertRepeatInterval },
 	"Monitoring.DataRetention": func(c *Config) time.Duration { return c.Monitoring.DataRetention },
 	"Monitoring.StorageHealthCheckInterval": func(c *Config) time.Duration { return c.Monitoring.StorageHealthCheckInterval },
 	"Monitoring.TokenExpiresIn": func(c *Config) time.Duration { return c.Monitoring.TokenExpiresIn },
@@ -1082,11 +1165,16 @@ var durationAccessors = map[string]func(*Config) time.Duration{
 	"Origin.SSH.SessionEstablishTimeout": func(c *Config) time.Duration { return c.Origin.SSH.SessionEstablishTimeout },
 	"Origin.SelfTestInterval": func(c *Config) time.Duration { return c.Origin.SelfTestInterval },
 	"Origin.SelfTestMaxAge": func(c *Config) time.Duration { return c.Origin.SelfTestMaxAge },
+	"Origin.UploadStagingExpiry": func(c *Config) time.Duration { return c.Origin.UploadStagingExpiry },
 	"Origin.UserMapfileRefreshInterval": func(c *Config) time.Duration { return c.Origin.UserMapfileRefreshInterval },
 	"Registry.InstitutionsUrlReloadMinutes": func(c *Config) time.Duration { return c.Registry.InstitutionsUrlReloadMinutes },
+	"Registry.ReplicationInterval": func(c *Config) time.Duration { return c.Registry.ReplicationInterval },
 	"Server.AdLifetime": func(c *Config) time.Duration { return c.Server.AdLifetime },
 	"Server.AdvertisementInterval": func(c *Config) time.Duration { return c.Server.AdvertisementInterval },
 	"Server.DatabaseBackup.Frequency": func(c *Config) time.Duration { return c.Server.DatabaseBackup.Frequency },
+	"Server.IssuerKeyRotation.Interval": func(c *Config) time.Duration { return c.Server.IssuerKeyRotation.Interval },
+	"Server.IssuerKeyRotation.OverlapPeriod": func(c *Config) time.Duration { return c.Server.IssuerKeyRotation.OverlapPeriod },
+	"Server.IssuerKeyRotation.PublishPeriod": func(c *Config) time.Duration { return c.Server.IssuerKeyRotation.PublishPeriod },
 	"Server.RegistrationRetryInterval": func(c *Config) time.Duration { return c.Server.RegistrationRetryInterval },
 	"Server.StartupTimeout": func(c *Config) time.Duration { return c.Server.StartupTimeout },
 	"Transport.BrokerEndpointCacheTTL": func(c *Config) time.Duration { return c.Transport.BrokerEndpointCacheTTL },
@@ -1203,6 +1291,8 @@ var allParameterNames = []string{
 	"Cache.DirectorTest",
 	"Cache.DisableClientX509",
 	"Cache.EnableBroker",
+	"Cache.EnableCompression",
+	"Cache.EnableDeduplication",
 	"Cache.EnableEvictionMonitoring",
 	"Cache.EnableLotman",
 	"Cache.EnableOIDC",
@@ -1213,6 +1303,7 @@ var allParameterNames = []string{
 	"Cache.EnableVoms",
 	"Cache.EvictionMonitoringInterval",
 	"Cache.EvictionMonitoringMaxDepth",
+	"Cache.EvictionPolicy",
 	"Cache.ExportLocation",
 	"Cache.FedTokenLocation",
 	"Cache.FilesBaseSize",
@@ -1224,7 +1315,9 @@ var allParameterNames = []string{
 	"Cache.MemoryCacheSize",
 	"Cache.MetaLocations",
 	"Cache.MinDirectorRefreshInterval",
+	"Cache.NamespaceEvictionPolicies",
 	"Cache.NamespaceLocation",
+	"Cache.NamespaceQuotas",
 	"Cache.PSSOrigin",
 	"Cache.PermittedNamespaces",
 	"Cache.Port",
@@ -1241,6 +1334,7 @@ var allParameterNames = []string{
 	"Client.DirectorRetries",
 	"Client.DisableHttpProxy",
 	"Client.DisableProxyFallback",
+	"Client.DisableTransferReports",
 	"Client.EnableOverwrites",
 	"Client.IsPlugin",
 	"Client.MaximumDownloadSpeed",
@@ -1249,6 +1343,11 @@ var allParameterNames = []string{
 	"Client.SlowTransferRampupTime",
 	"Client.SlowTransferWindow",
 	"Client.StoppedTransferTimeout",
+	"Client.StripedDownloadSources",
+	"Client.StripedDownloadStripeSize",
+	"Client.StripedDownloadThreshold",
+	"Client.UploadChunkSize",
+	"Client.UploadStateDirectory",
 	"Client.WorkerCount",
 	"ClientAgent.DbLocation",
 	"ClientAgent.HistoryRetentionDays",
@@ -1290,14 +1389,18 @@ var allParameterNames = []string{
 	"Director.RegistryQueryInterval",
 	"Director.StatConcurrencyLimit",
 	"Director.StatTimeout",
+	"Director.StateSnapshotInterval",
 	"Director.SupportContactEmail",
 	"Director.SupportContactUrl",
+	"Director.ThroughputSortEWMATimeConstant",
+	"Director.ThroughputSortExplorationPercentage",
 	"DisableHttpProxy",
 	"DisableProxyFallback",
 	"Federation.BrokerUrl",
 	"Federation.DirectorUrl",
 	"Federation.DiscoveryUrl",
 	"Federation.JwkUrl",
+	"Federation.RegistryReplicaUrls",
 	"Federation.RegistryUrl",
 	"Federation.TopologyDowntimeUrl",
 	"Federation.TopologyNamespaceUrl",
@@ -1330,11 +1433,16 @@ var allParameterNames = []string{
 	"LocalCache.ChunkSize",
 	"LocalCache.DataLocation",
 	"LocalCache.DefaultMaxAge",
+	"LocalCache.EnableCompression",
+	"LocalCache.EnableDeduplication",
+	"LocalCache.EvictionPolicy",
 	"LocalCache.FDCacheSize",
 	"LocalCache.HighWaterMarkPercentage",
 	"LocalCache.LowWaterMarkPercentage",
 	"LocalCache.MaxConcurrentPrefetch",
 	"LocalCache.MemoryCacheSize",
+	"LocalCache.NamespaceEvictionPolicies",
+	"LocalCache.NamespaceQuotas",
 	"LocalCache.PrefetchTimeout",
 	"LocalCache.RevalidationJitter",
 	"LocalCache.RunLocation",
@@ -1371,9 +1479,19 @@ var allParameterNames = []string{
 	"Lotman.PolicyDefinitions",
 	"MinimumDownloadSpeed",
 	"Monitoring.AggregatePrefixes",
+	"Monitoring.AlertEmailRecipients",
+	"Monitoring.AlertEmailSender",
+	"Monitoring.AlertEvaluationInterval",
+	"Monitoring.AlertRepeatInterval",
+	"Monitoring.AlertRuleFiles",
+	"Monitoring.AlertSmtpPasswordFile",
+	"Monitoring.AlertSmtpServer",
+	"Monitoring.AlertSmtpUsername",
+	"Monitoring.AlertWebhookUrl",
 	"Monitoring.DataLocation",
 	"Monitoring.DataRetention",
 	"Monitoring.DataRetentionSize",
+	"Monitoring.EnableBuiltinAlertRules",
 	"Monitoring.EnablePrometheus",
 	"Monitoring.LabelLimit",
 	"Monitoring.LabelNameLengthLimit",
@@ -1418,9 +1536,11 @@ var allParameterNames = []string{
 	"Origin.EnableIssuer",
 	"Origin.EnableListings",
 	"Origin.EnableMacaroons",
+	"Origin.EnableNativeBackend",
 	"Origin.EnableOIDC",
 	"Origin.EnablePublicReads",
 	"Origin.EnableReads",
+	"Origin.EnableThirdPartyCopy",
 	"Origin.EnableVoms",
 	"Origin.EnableWrite",
 	"Origin.EnableWrites",
@@ -1437,6 +1557,7 @@ var allParameterNames = []string{
 	"Origin.GlobusTransferTokenFile",
 	"Origin.HttpAuthTokenFile",
 	"Origin.HttpServiceUrl",
+	"Origin.HttpTokenPassthrough",
 	"Origin.IssuerMode",
 	"Origin.Mode",
 	"Origin.Multiuser",
@@ -1486,8 +1607,11 @@ var allParameterNames = []string{
 	"Origin.StoragePrefix",
 	"Origin.StorageType",
 	"Origin.SupportedChecksumTypes",
+	"Origin.ThirdPartyCopyAllowedHosts",
+	"Origin.ThirdPartyCopyDeniedHosts",
 	"Origin.TokenAudience",
 	"Origin.TransferRateLimit",
+	"Origin.UploadStagingExpiry",
 	"Origin.UploadTempLocation",
 	"Origin.Url",
 	"Origin.UserMapfileRefreshInterval",
@@ -1501,6 +1625,8 @@ var allParameterNames = []string{
 	"Registry.Institutions",
 	"Registry.InstitutionsUrl",
 	"Registry.InstitutionsUrlReloadMinutes",
+	"Registry.ReplicationInterval",
+	"Registry.ReplicationPeers",
 	"Registry.RequireCacheApproval",
 	"Registry.RequireKeyChaining",
 	"Registry.RequireOriginApproval",
@@ -1512,6 +1638,9 @@ var allParameterNames = []string{
 	"Server.DatabaseBackup.Location",
 	"Server.DatabaseBackup.MaxCount",
 	"Server.DbLocation",
+	"Server.DbPasswordFile",
+	"Server.DbType",
+	"Server.DbUrl",
 	"Server.DirectorUrls",
 	"Server.DropPrivileges",
 	"Server.EnablePKCS11",
@@ -1522,6 +1651,9 @@ var allParameterNames = []string{
 	"Server.Hostname",
 	"Server.IssuerHostname",
 	"Server.IssuerJwks",
+	"Server.IssuerKeyRotation.Interval",
+	"Server.IssuerKeyRotation.OverlapPeriod",
+	"Server.IssuerKeyRotation.PublishPeriod",
 	"Server.IssuerPort",
 	"Server.IssuerUrl",
 	"Server.Modules",
@@ -1570,6 +1702,8 @@ var allParameterNames = []string{
 	"Topology.DisableDowntime",
 	"Topology.DisableOriginX509",
 	"Topology.DisableOrigins",
+	"Tracing.Endpoint",
+	"Tracing.SamplingPercentage",
 	"Transport.BrokerEndpointCacheTTL",
 	"Transport.DialerKeepAlive",
 	"Transport.DialerTimeout",
@@ -1609,6 +1743,7 @@ var (
 	Cache_ClientStatisticsLocation = StringParam{"Cache.ClientStatisticsLocation"}
 	Cache_DataLocation = StringParam{"Cache.DataLocation"}
 	Cache_DbLocation = StringParam{"Cache.DbLocation"}
+	Cache_EvictionPolicy = StringParam{"Cache.EvictionPolicy"}
 	Cache_ExportLocation = StringParam{"Cache.ExportLocation"}
 	Cache_FedTokenLocation = StringParam{"Cache.FedTokenLocation"}
 	Cache_FilesBaseSize = StringParam{"Cache.FilesBaseSize"}
@@ -1629,6 +1764,7 @@ var (
 	ClientAgent_PidFile = StringParam{"ClientAgent.PidFile"}
 	ClientAgent_Socket = StringParam{"ClientAgent.Socket"}
 	Client_CredentialFile = StringParam{"Client.CredentialFile"}
+	Client_UploadStateDirectory = StringParam{"Client.UploadStateDirectory"}
 	Director_AdvertiseUrl = StringParam{"Director.AdvertiseUrl"}
 	Director_CacheSortMethod = StringParam{"Director.CacheSortMethod"}
 	Director_DbLocation = StringParam{"Director.DbLocation"}
@@ -1657,6 +1793,7 @@ var (
 	Issuer_TomcatLocation = StringParam{"Issuer.TomcatLocation"}
 	LocalCache_ChunkSize = StringParam{"LocalCache.ChunkSize"}
 	LocalCache_DataLocation = StringParam{"LocalCache.DataLocation"}
+	LocalCache_EvictionPolicy = StringParam{"LocalCache.EvictionPolicy"}
 	LocalCache_MemoryCacheSize = StringParam{"LocalCache.MemoryCacheSize"}
 	LocalCache_RunLocation = StringParam{"LocalCache.RunLocation"}
 	LocalCache_Size = StringParam{"LocalCache.Size"}
@@ -1683,6 +1820,11 @@ var (
 	Lotman_EnabledPolicy = StringParam{"Lotman.EnabledPolicy"}
 	Lotman_LibLocation = StringParam{"Lotman.LibLocation"}
 	Lotman_LotHome = StringParam{"Lotman.LotHome"}
+	Monitoring_AlertEmailSender = StringParam{"Monitoring.AlertEmailSender"}
+	Monitoring_AlertSmtpPasswordFile = StringParam{"Monitoring.AlertSmtpPasswordFile"}
+	Monitoring_AlertSmtpServer = StringParam{"Monitoring.AlertSmtpServer"}
+	Monitoring_AlertSmtpUsername = StringParam{"Monitoring.AlertSmtpUsername"}
+	Monitoring_AlertWebhookUrl = StringParam{"Monitoring.AlertWebhookUrl"}
 	Monitoring_DataLocation = StringParam{"Monitoring.DataLocation"}
 	Monitoring_DataRetentionSize = StringParam{"Monitoring.DataRetentionSize"}
 	OIDC_AuthorizationEndpoint = StringParam{"OIDC.AuthorizationEndpoint"}
@@ -1746,6 +1888,9 @@ var (
 	RuntimeDir = StringParam{"RuntimeDir"}
 	Server_DatabaseBackup_Location = StringParam{"Server.DatabaseBackup.Location"}
 	Server_DbLocation = StringParam{"Server.DbLocation"}
+	Server_DbPasswordFile = StringParam{"Server.DbPasswordFile"}
+	Server_DbType = StringParam{"Server.DbType"}
+	Server_DbUrl = StringParam{"Server.DbUrl"}
 	Server_ExternalWebUrl = StringParam{"Server.ExternalWebUrl"}
 	Server_Hostname = StringParam{"Server.Hostname"}
 	Server_IssuerHostname = StringParam{"Server.IssuerHostname"}
@@ -1776,6 +1921,7 @@ var (
 	StagePlugin_MountPrefix = StringParam{"StagePlugin.MountPrefix"}
 	StagePlugin_OriginPrefix = StringParam{"StagePlugin.OriginPrefix"}
 	StagePlugin_ShadowOriginPrefix = StringParam{"StagePlugin.ShadowOriginPrefix"}
+	Tracing_Endpoint = StringParam{"Tracing.Endpoint"}
 	Xrootd_Authfile = StringParam{"Xrootd.Authfile"}
 	Xrootd_ConfigFile = StringParam{"Xrootd.ConfigFile"}
 	Xrootd_DetailedMonitoringHost = StringParam{"Xrootd.DetailedMonitoringHost"}
@@ -1800,9 +1946,12 @@ var (
 	Director_CacheResponseHostnames = StringSliceParam{"Director.CacheResponseHostnames"}
 	Director_FilteredServers = StringSliceParam{"Director.FilteredServers"}
 	Director_OriginResponseHostnames = StringSliceParam{"Director.OriginResponseHostnames"}
+	Federation_RegistryReplicaUrls = StringSliceParam{"Federation.RegistryReplicaUrls"}
 	Issuer_GroupRequirements = StringSliceParam{"Issuer.GroupRequirements"}
 	Issuer_RedirectUris = StringSliceParam{"Issuer.RedirectUris"}
 	Monitoring_AggregatePrefixes = StringSliceParam{"Monitoring.AggregatePrefixes"}
+	Monitoring_AlertEmailRecipients = StringSliceParam{"Monitoring.AlertEmailRecipients"}
+	Monitoring_AlertRuleFiles = StringSliceParam{"Monitoring.AlertRuleFiles"}
 	OIDC_Scopes = StringSliceParam{"OIDC.Scopes"}
 	Origin_DefaultChecksumTypes = StringSliceParam{"Origin.DefaultChecksumTypes"}
 	Origin_ExportVolumes = StringSliceParam{"Origin.ExportVolumes"}
@@ -1810,7 +1959,10 @@ var (
 	Origin_SSH_RemotePelicanBinaryOverrides = StringSliceParam{"Origin.SSH.RemotePelicanBinaryOverrides"}
 	Origin_ScitokensRestrictedPaths = StringSliceParam{"Origin.ScitokensRestrictedPaths"}
 	Origin_SupportedChecksumTypes = StringSliceParam{"Origin.SupportedChecksumTypes"}
+	Origin_ThirdPartyCopyAllowedHosts = StringSliceParam{"Origin.ThirdPartyCopyAllowedHosts"}
+	Origin_ThirdPartyCopyDeniedHosts = StringSliceParam{"Origin.ThirdPartyCopyDeniedHosts"}
 	Registry_AdminUsers = StringSliceParam{"Registry.AdminUsers"}
+	Registry_ReplicationPeers = StringSliceParam{"Registry.ReplicationPeers"}
 	Server_AdminGroups = StringSliceParam{"Server.AdminGroups"}
 	Server_DirectorUrls = StringSliceParam{"Server.DirectorUrls"}
 	Server_Modules = StringSliceParam{"Server.Modules"}
@@ -1830,12 +1982,17 @@ var (
 	Client_DirectorRetries = IntParam{"Client.DirectorRetries"}
 	Client_MaximumDownloadSpeed = IntParam{"Client.MaximumDownloadSpeed"}
 	Client_MinimumDownloadSpeed = IntParam{"Client.MinimumDownloadSpeed"}
+	Client_StripedDownloadSources = IntParam{"Client.StripedDownloadSources"}
+	Client_StripedDownloadStripeSize = IntParam{"Client.StripedDownloadStripeSize"}
+	Client_StripedDownloadThreshold = IntParam{"Client.StripedDownloadThreshold"}
+	Client_UploadChunkSize = IntParam{"Client.UploadChunkSize"}
 	Client_WorkerCount = IntParam{"Client.WorkerCount"}
 	Director_AdaptiveSortTruncateConstant = IntParam{"Director.AdaptiveSortTruncateConstant"}
 	Director_CachePresenceCapacity = IntParam{"Director.CachePresenceCapacity"}
 	Director_MaxStatResponse = IntParam{"Director.MaxStatResponse"}
 	Director_MinStatResponse = IntParam{"Director.MinStatResponse"}
 	Director_StatConcurrencyLimit = IntParam{"Director.StatConcurrencyLimit"}
+	Director_ThroughputSortExplorationPercentage = IntParam{"Director.ThroughputSortExplorationPercentage"}
 	LocalCache_FDCacheSize = IntParam{"LocalCache.FDCacheSize"}
 	LocalCache_HighWaterMarkPercentage = IntParam{"LocalCache.HighWaterMarkPercentage"}
 	LocalCache_LowWaterMarkPercentage = IntParam{"LocalCache.LowWaterMarkPercentage"}
@@ -1865,6 +2022,7 @@ var (
 	Server_WebPort = IntParam{"Server.WebPort"}
 	Shoveler_PortHigher = IntParam{"Shoveler.PortHigher"}
 	Shoveler_PortLower = IntParam{"Shoveler.PortLower"}
+	Tracing_SamplingPercentage = IntParam{"Tracing.SamplingPercentage"}
 	Transport_MaxIdleConns = IntParam{"Transport.MaxIdleConns"}
 	Xrootd_DetailedMonitoringPort = IntParam{"Xrootd.DetailedMonitoringPort"}
 	Xrootd_LocalMonitoringPort = IntParam{"Xrootd.LocalMonitoringPort"}
@@ -1882,6 +2040,8 @@ var (
 	Cache_DirectorTest = BoolParam{"Cache.DirectorTest"}
 	Cache_DisableClientX509 = BoolParam{"Cache.DisableClientX509"}
 	Cache_EnableBroker = BoolParam{"Cache.EnableBroker"}
+	Cache_EnableCompression = BoolParam{"Cache.EnableCompression"}
+	Cache_EnableDeduplication = BoolParam{"Cache.EnableDeduplication"}
 	Cache_EnableEvictionMonitoring = BoolParam{"Cache.EnableEvictionMonitoring"}
 	Cache_EnableLotman = BoolParam{"Cache.EnableLotman"}
 	Cache_EnableOIDC = BoolParam{"Cache.EnableOIDC"}
@@ -1894,6 +2054,7 @@ var (
 	Client_AssumeDirectorServerHeader = BoolParam{"Client.AssumeDirectorServerHeader"}
 	Client_DisableHttpProxy = BoolParam{"Client.DisableHttpProxy"}
 	Client_DisableProxyFallback = BoolParam{"Client.DisableProxyFallback"}
+	Client_DisableTransferReports = BoolParam{"Client.DisableTransferReports"}
 	Client_EnableOverwrites = BoolParam{"Client.EnableOverwrites"}
 	Client_IsPlugin = BoolParam{"Client.IsPlugin"}
 	Debug = BoolParam{"Debug"}
@@ -1910,8 +2071,11 @@ var (
 	DisableProxyFallback = BoolParam{"DisableProxyFallback"}
 	Issuer_OIDCPreferClaimsFromIDToken = BoolParam{"Issuer.OIDCPreferClaimsFromIDToken"}
 	Issuer_UserStripDomain = BoolParam{"Issuer.UserStripDomain"}
+	LocalCache_EnableCompression = BoolParam{"LocalCache.EnableCompression"}
+	LocalCache_EnableDeduplication = BoolParam{"LocalCache.EnableDeduplication"}
 	Logging_DisableProgressBars = BoolParam{"Logging.DisableProgressBars"}
 	Lotman_EnableAPI = BoolParam{"Lotman.EnableAPI"}
+	Monitoring_EnableBuiltinAlertRules = BoolParam{"Monitoring.EnableBuiltinAlertRules"}
 	Monitoring_EnablePrometheus = BoolParam{"Monitoring.EnablePrometheus"}
 	Monitoring_MetricAuthorization = BoolParam{"Monitoring.MetricAuthorization"}
 	Monitoring_PromQLAuthorization = BoolParam{"Monitoring.PromQLAuthorization"}
@@ -1927,12 +2091,15 @@ var (
 	Origin_EnableIssuer = BoolParam{"Origin.EnableIssuer"}
 	Origin_EnableListings = BoolParam{"Origin.EnableListings"}
 	Origin_EnableMacaroons = BoolParam{"Origin.EnableMacaroons"}
+	Origin_EnableNativeBackend = BoolParam{"Origin.EnableNativeBackend"}
 	Origin_EnableOIDC = BoolParam{"Origin.EnableOIDC"}
 	Origin_EnablePublicReads = BoolParam{"Origin.EnablePublicReads"}
 	Origin_EnableReads = BoolParam{"Origin.EnableReads"}
+	Origin_EnableThirdPartyCopy = BoolParam{"Origin.EnableThirdPartyCopy"}
 	Origin_EnableVoms = BoolParam{"Origin.EnableVoms"}
 	Origin_EnableWrite = BoolParam{"Origin.EnableWrite"}
 	Origin_EnableWrites = BoolParam{"Origin.EnableWrites"}
+	Origin_HttpTokenPassthrough = BoolParam{"Origin.HttpTokenPassthrough"}
 	Origin_Multiuser = BoolParam{"Origin.Multiuser"}
 	Origin_SSH_AutoAddHostKey = BoolParam{"Origin.SSH.AutoAddHostKey"}
 	Origin_SSH_TunnelCallback = BoolParam{"Origin.SSH.TunnelCallback"}
@@ -1979,6 +2146,8 @@ var (
 	Director_OriginCacheHealthTestInterval = DurationParam{"Director.OriginCacheHealthTestInterval"}
 	Director_RegistryQueryInterval = DurationParam{"Director.RegistryQueryInterval"}
 	Director_StatTimeout = DurationParam{"Director.StatTimeout"}
+	Director_StateSnapshotInterval = DurationParam{"Director.StateSnapshotInterval"}
+	Director_ThroughputSortEWMATimeConstant = DurationParam{"Director.ThroughputSortEWMATimeConstant"}
 	Federation_TopologyReloadInterval = DurationParam{"Federation.TopologyReloadInterval"}
 	Issuer_DynamicClientStaleTimeout = DurationParam{"Issuer.DynamicClientStaleTimeout"}
 	Issuer_DynamicClientUnusedTimeout = DurationParam{"Issuer.DynamicClientUnusedTimeout"}
@@ -1988,6 +2157,8 @@ var (
 	Logging_Client_ProgressInterval = DurationParam{"Logging.Client.ProgressInterval"}
 	Lotman_DefaultLotDeletionLifetime = DurationParam{"Lotman.DefaultLotDeletionLifetime"}
 	Lotman_DefaultLotExpirationLifetime = DurationParam{"Lotman.DefaultLotExpirationLifetime"}
+	Monitoring_AlertEvaluationInterval = DurationParam{"Monitoring.AlertEvaluationInterval"}
+	Monitoring_AlertRepeatInterval = DurationParam{"Monitoring.AlertRepeatInterval"}
 	Monitoring_DataRetention = DurationParam{"Monitoring.DataRetention"}
 	Monitoring_StorageHealthCheckInterval = DurationParam{"Monitoring.StorageHealthCheckInterval"}
 	Monitoring_TokenExpiresIn = DurationParam{"Monitoring.TokenExpiresIn"}
@@ -2001,11 +2172,16 @@ var (
 	Origin_SSH_SessionEstablishTimeout = DurationParam{"Origin.SSH.SessionEstablishTimeout"}
 	Origin_SelfTestInterval = DurationParam{"Origin.SelfTestInterval"}
 	Origin_SelfTestMaxAge = DurationParam{"Origin.SelfTestMaxAge"}
+	Origin_UploadStagingExpiry = DurationParam{"Origin.UploadStagingExpiry"}
 	Origin_UserMapfileRefreshInterval = DurationParam{"Origin.UserMapfileRefreshInterval"}
 	Registry_InstitutionsUrlReloadMinutes = DurationParam{"Registry.InstitutionsUrlReloadMinutes"}
+	Registry_ReplicationInterval = DurationParam{"Registry.ReplicationInterval"}
 	Server_AdLifetime = DurationParam{"Server.AdLifetime"}
 	Server_AdvertisementInterval = DurationParam{"Server.AdvertisementInterval"}
 	Server_DatabaseBackup_Frequency = DurationParam{"Server.DatabaseBackup.Frequency"}
+	Server_IssuerKeyRotation_Interval = DurationParam{"Server.IssuerKeyRotation.Interval"}
+	Server_IssuerKeyRotation_OverlapPeriod = DurationParam{"Server.IssuerKeyRotation.OverlapPeriod"}
+	Server_IssuerKeyRotation_PublishPeriod = DurationParam{"Server.IssuerKeyRotation.PublishPeriod"}
 	Server_RegistrationRetryInterval = DurationParam{"Server.RegistrationRetryInterval"}
 	Server_StartupTimeout = DurationParam{"Server.StartupTimeout"}
 	Transport_BrokerEndpointCacheTTL = DurationParam{"Transport.BrokerEndpointCacheTTL"}
@@ -2023,9 +2199,13 @@ var (
 )
 
 var (
+	Cache_NamespaceEvictionPolicies = ObjectParam{"Cache.NamespaceEvictionPolicies"}
+	Cache_NamespaceQuotas = ObjectParam{"Cache.NamespaceQuotas"}
 	GeoIPOverrides = ObjectParam{"GeoIPOverrides"}
 	Issuer_AuthorizationTemplates = ObjectParam{"Issuer.AuthorizationTemplates"}
 	Issuer_OIDCAuthenticationRequirements = ObjectParam{"Issuer.OIDCAuthenticationRequirements"}
+	LocalCache_NamespaceEvictionPolicies = ObjectParam{"LocalCache.NamespaceEvictionPolicies"}
+	LocalCache_NamespaceQuotas = ObjectParam{"LocalCache.NamespaceQuotas"}
 	LocalCache_StorageDirs = ObjectParam{"LocalCache.StorageDirs"}
 	Lotman_PolicyDefinitions = ObjectParam{"Lotman.PolicyDefinitions"}
 	Origin_Exports = ObjectParam{"Origin.Exports"}
@@ -2055,6 +2235,7 @@ func init() {
 		"Cache.ClientStatisticsLocation": Cache_ClientStatisticsLocation,
 		"Cache.DataLocation": Cache_DataLocation,
 		"Cache.DbLocation": Cache_DbLocation,
+		"Cache.EvictionPolicy": Cache_EvictionPolicy,
 		"Cache.ExportLocation": Cache_ExportLocation,
 		"Cache.FedTokenLocation": Cache_FedTokenLocation,
 		"Cache.FilesBaseSize": Cache_FilesBaseSize,
@@ -2075,6 +2256,7 @@ func init() {
 		"ClientAgent.PidFile": ClientAgent_PidFile,
 		"ClientAgent.Socket": ClientAgent_Socket,
 		"Client.CredentialFile": Client_CredentialFile,
+		"Client.UploadStateDirectory": Client_UploadStateDirectory,
 		"Director.AdvertiseUrl": Director_AdvertiseUrl,
 		"Director.CacheSortMethod": Director_CacheSortMethod,
 		"Director.DbLocation": Director_DbLocation,
@@ -2103,6 +2285,7 @@ func init() {
 		"Issuer.TomcatLocation": Issuer_TomcatLocation,
 		"LocalCache.ChunkSize": LocalCache_ChunkSize,
 		"LocalCache.DataLocation": LocalCache_DataLocation,
+		"LocalCache.EvictionPolicy": LocalCache_EvictionPolicy,
 		"LocalCache.MemoryCacheSize": LocalCache_MemoryCacheSize,
 		"LocalCache.RunLocation": LocalCache_RunLocation,
 		"LocalCache.Size": LocalCache_Size,
@@ -2129,6 +2312,11 @@ func init() {
 		"Lotman.EnabledPolicy": Lotman_EnabledPolicy,
 		"Lotman.LibLocation": Lotman_LibLocation,
 		"Lotman.LotHome": Lotman_LotHome,
+		"Monitoring.AlertEmailSender": Monitoring_AlertEmailSender,
+		"Monitoring.AlertSmtpPasswordFile": Monitoring_AlertSmtpPasswordFile,
+		"Monitoring.AlertSmtpServer": Monitoring_AlertSmtpServer,
+		"Monitoring.AlertSmtpUsername": Monitoring_AlertSmtpUsername,
+		"Monitoring.AlertWebhookUrl": Monitoring_AlertWebhookUrl,
 		"Monitoring.DataLocation": Monitoring_DataLocation,
 		"Monitoring.DataRetentionSize": Monitoring_DataRetentionSize,
 		"OIDC.AuthorizationEndpoint": OIDC_AuthorizationEndpoint,
@@ -2192,6 +2380,9 @@ func init() {
 		"RuntimeDir": RuntimeDir,
 		"Server.DatabaseBackup.Location": Server_DatabaseBackup_Location,
 		"Server.DbLocation": Server_DbLocation,
+		"Server.DbPasswordFile": Server_DbPasswordFile,
+		"Server.DbType": Server_DbType,
+		"Server.DbUrl": Server_DbUrl,
 		"Server.ExternalWebUrl": Server_ExternalWebUrl,
 		"Server.Hostname": Server_Hostname,
 		"Server.IssuerHostname": Server_IssuerHostname,
@@ -2222,6 +2413,7 @@ func init() {
 		"StagePlugin.MountPrefix": StagePlugin_MountPrefix,
 		"StagePlugin.OriginPrefix": StagePlugin_OriginPrefix,
 		"StagePlugin.ShadowOriginPrefix": StagePlugin_ShadowOriginPrefix,
+		"Tracing.Endpoint": Tracing_Endpoint,
 		"Xrootd.Authfile": Xrootd_Authfile,
 		"Xrootd.ConfigFile": Xrootd_ConfigFile,
 		"Xrootd.DetailedMonitoringHost": Xrootd_DetailedMonitoringHost,
@@ -2243,9 +2435,12 @@ func init() {
 		"Director.CacheResponseHostnames": Director_CacheResponseHostnames,
 		"Director.FilteredServers": Director_FilteredServers,
 		"Director.OriginResponseHostnames": Director_OriginResponseHostnames,
+		"Federation.RegistryReplicaUrls": Federation_RegistryReplicaUrls,
 		"Issuer.GroupRequirements": Issuer_GroupRequirements,
 		"Issuer.RedirectUris": Issuer_RedirectUris,
 		"Monitoring.AggregatePrefixes": Monitoring_AggregatePrefixes,
+		"Monitoring.AlertEmailRecipients": Monitoring_AlertEmailRecipients,
+		"Monitoring.AlertRuleFiles": Monitoring_AlertRuleFiles,
 		"OIDC.Scopes": OIDC_Scopes,
 		"Origin.DefaultChecksumTypes": Origin_DefaultChecksumTypes,
 		"Origin.ExportVolumes": Origin_ExportVolumes,
@@ -2253,7 +2448,10 @@ func init() {
 		"Origin.SSH.RemotePelicanBinaryOverrides": Origin_SSH_RemotePelicanBinaryOverrides,
 		"Origin.ScitokensRestrictedPaths": Origin_ScitokensRestrictedPaths,
 		"Origin.SupportedChecksumTypes": Origin_SupportedChecksumTypes,
+		"Origin.ThirdPartyCopyAllowedHosts": Origin_ThirdPartyCopyAllowedHosts,
+		"Origin.ThirdPartyCopyDeniedHosts": Origin_ThirdPartyCopyDeniedHosts,
 		"Registry.AdminUsers": Registry_AdminUsers,
+		"Registry.ReplicationPeers": Registry_ReplicationPeers,
 		"Server.AdminGroups": Server_AdminGroups,
 		"Server.DirectorUrls": Server_DirectorUrls,
 		"Server.Modules": Server_Modules,
@@ -2270,12 +2468,17 @@ func init() {
 		"Client.DirectorRetries": Client_DirectorRetries,
 		"Client.MaximumDownloadSpeed": Client_MaximumDownloadSpeed,
 		"Client.MinimumDownloadSpeed": Client_MinimumDownloadSpeed,
+		"Client.StripedDownloadSources": Client_StripedDownloadSources,
+		"Client.StripedDownloadStripeSize": Client_StripedDownloadStripeSize,
+		"Client.StripedDownloadThreshold": Client_StripedDownloadThreshold,
+		"Client.UploadChunkSize": Client_UploadChunkSize,
 		"Client.WorkerCount": Client_WorkerCount,
 		"Director.AdaptiveSortTruncateConstant": Director_AdaptiveSortTruncateConstant,
 		"Director.CachePresenceCapacity": Director_CachePresenceCapacity,
 		"Director.MaxStatResponse": Director_MaxStatResponse,
 		"Director.MinStatResponse": Director_MinStatResponse,
 		"Director.StatConcurrencyLimit": Director_StatConcurrencyLimit,
+		"Director.ThroughputSortExplorationPercentage": Director_ThroughputSortExplorationPercentage,
 		"LocalCache.FDCacheSize": LocalCache_FDCacheSize,
 		"LocalCache.HighWaterMarkPercentage": LocalCache_HighWaterMarkPercentage,
 		"LocalCache.LowWaterMarkPercentage": LocalCache_LowWaterMarkPercentage,
@@ -2305,6 +2508,7 @@ func init() {
 		"Server.WebPort": Server_WebPort,
 		"Shoveler.PortHigher": Shoveler_PortHigher,
 		"Shoveler.PortLower": Shoveler_PortLower,
+		"Tracing.SamplingPercentage": Tracing_SamplingPercentage,
 		"Transport.MaxIdleConns": Transport_MaxIdleConns,
 		"Xrootd.DetailedMonitoringPort": Xrootd_DetailedMonitoringPort,
 		"Xrootd.LocalMonitoringPort": Xrootd_LocalMonitoringPort,
@@ -2316,6 +2520,8 @@ func init() {
 		"Cache.DirectorTest": Cache_DirectorTest,
 		"Cache.DisableClientX509": Cache_DisableClientX509,
 		"Cache.EnableBroker": Cache_EnableBroker,
+		"Cache.EnableCompression": Cache_EnableCompression,
+		"Cache.EnableDeduplication": Cache_EnableDeduplication,
 		"Cache.EnableEvictionMonitoring": Cache_EnableEvictionMonitoring,
 		"Cache.EnableLotman": Cache_EnableLotman,
 		"Cache.EnableOIDC": Cache_EnableOIDC,
@@ -2328,6 +2534,7 @@ func init() {
 		"Client.AssumeDirectorServerHeader": Client_AssumeDirectorServerHeader,
 		"Client.DisableHttpProxy": Client_DisableHttpProxy,
 		"Client.DisableProxyFallback": Client_DisableProxyFallback,
+		"Client.DisableTransferReports": Client_DisableTransferReports,
 		"Client.EnableOverwrites": Client_EnableOverwrites,
 		"Client.IsPlugin": Client_IsPlugin,
 		"Debug": Debug,
@@ -2344,8 +2551,11 @@ func init() {
 		"DisableProxyFallback": DisableProxyFallback,
 		"Issuer.OIDCPreferClaimsFromIDToken": Issuer_OIDCPreferClaimsFromIDToken,
 		"Issuer.UserStripDomain": Issuer_UserStripDomain,
+		"LocalCache.EnableCompression": LocalCache_EnableCompression,
+		"LocalCache.EnableDeduplication": LocalCache_EnableDeduplication,
 		"Logging.DisableProgressBars": Logging_DisableProgressBars,
 		"Lotman.EnableAPI": Lotman_EnableAPI,
+		"Monitoring.EnableBuiltinAlertRules": Monitoring_EnableBuiltinAlertRules,
 		"Monitoring.EnablePrometheus": Monitoring_EnablePrometheus,
 		"Monitoring.MetricAuthorization": Monitoring_MetricAuthorization,
 		"Monitoring.PromQLAuthorization": Monitoring_PromQLAuthorization,
@@ -2361,12 +2571,15 @@ func init() {
 		"Origin.EnableIssuer": Origin_EnableIssuer,
 		"Origin.EnableListings": Origin_EnableListings,
 		"Origin.EnableMacaroons": Origin_EnableMacaroons,
+		"Origin.EnableNativeBackend": Origin_EnableNativeBackend,
 		"Origin.EnableOIDC": Origin_EnableOIDC,
 		"Origin.EnablePublicReads": Origin_EnablePublicReads,
 		"Origin.EnableReads": Origin_EnableReads,
+		"Origin.EnableThirdPartyCopy": Origin_EnableThirdPartyCopy,
 		"Origin.EnableVoms": Origin_EnableVoms,
 		"Origin.EnableWrite": Origin_EnableWrite,
 		"Origin.EnableWrites": Origin_EnableWrites,
+		"Origin.HttpTokenPassthrough": Origin_HttpTokenPassthrough,
 		"Origin.Multiuser": Origin_Multiuser,
 		"Origin.SSH.AutoAddHostKey": Origin_SSH_AutoAddHostKey,
 		"Origin.SSH.TunnelCallback": Origin_SSH_TunnelCallback,
@@ -2410,6 +2623,8 @@ func init() {
 		"Director.OriginCacheHealthTestInterval": Director_OriginCacheHealthTestInterval,
 		"Director.RegistryQueryInterval": Director_RegistryQueryInterval,
 		"Director.StatTimeout": Director_StatTimeout,
+		"Director.StateSnapshotInterval": Director_StateSnapshotInterval,
+		"Director.ThroughputSortEWMATimeConstant": Director_ThroughputSortEWMATimeConstant,
 		"Federation.TopologyReloadInterval": Federation_TopologyReloadInterval,
 		"Issuer.DynamicClientStaleTimeout": Issuer_DynamicClientStaleTimeout,
 		"Issuer.DynamicClientUnusedTimeout": Issuer_DynamicClientUnusedTimeout,
@@ -2419,6 +2634,8 @@ func init() {
 		"Logging.Client.ProgressInterval": Logging_Client_ProgressInterval,
 		"Lotman.DefaultLotDeletionLifetime": Lotman_DefaultLotDeletionLifetime,
 		"Lotman.DefaultLotExpirationLifetime": Lotman_DefaultLotExpirationLifetime,
+		"Monitoring.AlertEvaluationInterval": Monitoring_AlertEvaluationInterval,
+		"Monitoring.AlertRepeatInterval": Monitoring_AlertRepeatInterval,
 		"Monitoring.DataRetention": Monitoring_DataRetention,
 		"Monitoring.StorageHealthCheckInterval": Monitoring_StorageHealthCheckInterval,
 		"Monitoring.TokenExpiresIn": Monitoring_TokenExpiresIn,
@@ -2432,11 +2649,16 @@ func init() {
 		"Origin.SSH.SessionEstablishTimeout": Origin_SSH_SessionEstablishTimeout,
 		"Origin.SelfTestInterval": Origin_SelfTestInterval,
 		"Origin.SelfTestMaxAge": Origin_SelfTestMaxAge,
+		"Origin.UploadStagingExpiry": Origin_UploadStagingExpiry,
 		"Origin.UserMapfileRefreshInterval": Origin_UserMapfileRefreshInterval,
 		"Registry.InstitutionsUrlReloadMinutes": Registry_InstitutionsUrlReloadMinutes,
+		"Registry.ReplicationInterval": Registry_ReplicationInterval,
 		"Server.AdLifetime": Server_AdLifetime,
 		"Server.AdvertisementInterval": Server_AdvertisementInterval,
 		"Server.DatabaseBackup.Frequency": Server_DatabaseBackup_Frequency,
+		"Server.IssuerKeyRotation.Interval": Server_IssuerKeyRotation_Interval,
+		"Server.IssuerKeyRotation.OverlapPeriod": Server_IssuerKeyRotation_OverlapPeriod,
+		"Server.IssuerKeyRotation.PublishPeriod": Server_IssuerKeyRotation_PublishPeriod,
 		"Server.RegistrationRetryInterval": Server_RegistrationRetryInterval,
 		"Server.StartupTimeout": Server_StartupTimeout,
 		"Transport.BrokerEndpointCacheTTL": Transport_BrokerEndpointCacheTTL,
@@ -2451,9 +2673,13 @@ func init() {
 		"Xrootd.HttpMaxDelay": Xrootd_HttpMaxDelay,
 		"Xrootd.MaxStartupWait": Xrootd_MaxStartupWait,
 		"Xrootd.ShutdownTimeout": Xrootd_ShutdownTimeout,
+		"Cache.NamespaceEvictionPolicies": Cache_NamespaceEvictionPolicies,
+		"Cache.NamespaceQuotas": Cache_NamespaceQuotas,
 		"GeoIPOverrides": GeoIPOverrides,
 		"Issuer.AuthorizationTemplates": Issuer_AuthorizationTemplates,
 		"Issuer.OIDCAuthenticationRequirements": Issuer_OIDCAuthenticationRequirements,
+		"LocalCache.NamespaceEvictionPolicies": LocalCache_NamespaceEvictionPolicies,
+		"LocalCache.NamespaceQuotas": LocalCache_NamespaceQuotas,
 		"LocalCache.StorageDirs": LocalCache_StorageDirs,
 		"Lotman.PolicyDefinitions": Lotman_PolicyDefinitions,
 		"Origin.Exports": Origin_Exports,
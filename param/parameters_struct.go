@@ -39,6 +39,8 @@ type Config struct {
 		DirectorTest bool `mapstructure:"directortest" yaml:"DirectorTest"`
 		DisableClientX509 bool `mapstructure:"disableclientx509" yaml:"DisableClientX509"`
 		EnableBroker bool `mapstructure:"enablebroker" yaml:"EnableBroker"`
+		EnableCompression bool `mapstructure:"enablecompression" yaml:"EnableCompression"`
+		EnableDeduplication bool `mapstructure:"enablededuplication" yaml:"EnableDeduplication"`
 		EnableEvictionMonitoring bool `mapstructure:"enableevictionmonitoring" yaml:"EnableEvictionMonitoring"`
 		EnableLotman bool `mapstructure:"enablelotman" yaml:"EnableLotman"`
 		EnableOIDC bool `mapstructure:"enableoidc" yaml:"EnableOIDC"`
@@ -49,6 +51,7 @@ type Config struct {
 		EnableVoms bool `mapstructure:"enablevoms" yaml:"EnableVoms"`
 		EvictionMonitoringInterval time.Duration `mapstructure:"evictionmonitoringinterval" yaml:"EvictionMonitoringInterval"`
 		EvictionMonitoringMaxDepth int `mapstructure:"evictionmonitoringmaxdepth" yaml:"EvictionMonitoringMaxDepth"`
+		EvictionPolicy string `mapstructure:"evictionpolicy" yaml:"EvictionPolicy"`
 		ExportLocation string `mapstructure:"exportlocation" yaml:"ExportLocation"`
 		FedTokenLocation string `mapstructure:"fedtokenlocation" yaml:"FedTokenLocation"`
 		FilesBaseSize string `mapstructure:"filesbasesize" yaml:"FilesBaseSize"`
@@ -60,7 +63,9 @@ type Config struct {
 		MemoryCacheSize string `mapstructure:"memorycachesize" yaml:"MemoryCacheSize"`
 		MetaLocations []string `mapstructure:"metalocations" yaml:"MetaLocations"`
 		MinDirectorRefreshInterval time.Duration `mapstructure:"mindirectorrefreshinterval" yaml:"MinDirectorRefreshInterval"`
+		NamespaceEvictionPolicies any `mapstructure:"namespaceevictionpolicies" yaml:"NamespaceEvictionPolicies"`
 		NamespaceLocation string `mapstructure:"namespacelocation" yaml:"NamespaceLocation"`
+		NamespaceQuotas any `mapstructure:"namespacequotas" yaml:"NamespaceQuotas"`
 		PSSOrigin string `mapstructure:"pssorigin" yaml:"PSSOrigin"`
 		PermittedNamespaces []string `mapstructure:"permittednamespaces" yaml:"PermittedNamespaces"`
 		Port int `mapstructure:"port" yaml:"Port"`
@@ -79,6 +84,7 @@ type Config struct {
 		DirectorRetries int `mapstructure:"directorretries" yaml:"DirectorRetries"`
 		DisableHttpProxy bool `mapstructure:"disablehttpproxy" yaml:"DisableHttpProxy"`
 		DisableProxyFallback bool `mapstructure:"disableproxyfallback" yaml:"DisableProxyFallback"`
+		DisableTransferReports bool `mapstructure:"disabletransferreports" yaml:"DisableTransferReports"`
 		EnableOverwrites bool `mapstructure:"enableoverwrites" yaml:"EnableOverwrites"`
 		IsPlugin bool `mapstructure:"isplugin" yaml:"IsPlugin"`
 		MaximumDownloadSpeed int `mapstructure:"maximumdownloadspeed" yaml:"MaximumDownloadSpeed"`
@@ -87,6 +93,11 @@ type Config struct {
 		SlowTransferRampupTime time.Duration `mapstructure:"slowtransferrampuptime" yaml:"SlowTransferRampupTime"`
 		SlowTransferWindow time.Duration `mapstructure:"slowtransferwindow" yaml:"SlowTransferWindow"`
 		StoppedTransferTimeout time.Duration `mapstructure:"stoppedtransfertimeout" yaml:"StoppedTransferTimeout"`
+		StripedDownloadSources int `mapstructure:"stripeddownloadsources" yaml:"StripedDownloadSources"`
+		StripedDownloadStripeSize int `mapstructure:"stripeddownloadstripesize" yaml:"StripedDownloadStripeSize"`
+		StripedDownloadThreshold int `mapstructure:"stripeddownloadthreshold" yaml:"StripedDownloadThreshold"`
+		UploadChunkSize int `mapstructure:"uploadchunksize" yaml:"UploadChunkSize"`
+		UploadStateDirectory string `mapstructure:"uploadstatedirectory" yaml:"UploadStateDirectory"`
 		WorkerCount int `mapstructure:"workercount" yaml:"WorkerCount"`
 	} `mapstructure:"client" yaml:"Client"`
 	ClientAgent struct {
@@ -133,8 +144,11 @@ type Config struct {
 		RegistryQueryInterval time.Duration `mapstructure:"registryqueryinterval" yaml:"RegistryQueryInterval"`
 		StatConcurrencyLimit int `mapstructure:"statconcurrencylimit" yaml:"StatConcurrencyLimit"`
 		StatTimeout time.Duration `mapstructure:"stattimeout" yaml:"StatTimeout"`
+		StateSnapshotInterval time.Duration `mapstructure:"statesnapshotinterval" yaml:"StateSnapshotInterval"`
 		SupportContactEmail string `mapstructure:"supportcontactemail" yaml:"SupportContactEmail"`
 		SupportContactUrl string `mapstructure:"supportcontacturl" yaml:"SupportContactUrl"`
+		ThroughputSortEWMATimeConstant time.Duration `mapstructure:"throughputsortewmatimeconstant" yaml:"ThroughputSortEWMATimeConstant"`
+		ThroughputSortExplorationPercentage int `mapstructure:"throughputsortexplorationpercentage" yaml:"ThroughputSortExplorationPercentage"`
 	} `mapstructure:"director" yaml:"Director"`
 	DisableHttpProxy bool `mapstructure:"disablehttpproxy" yaml:"DisableHttpProxy"`
 	DisableProxyFallback bool `mapstructure:"disableproxyfallback" yaml:"DisableProxyFallback"`
@@ -143,6 +157,7 @@ type Config struct {
 		DirectorUrl string `mapstructure:"directorurl" yaml:"DirectorUrl"`
 		DiscoveryUrl string `mapstructure:"discoveryurl" yaml:"DiscoveryUrl"`
 		JwkUrl string `mapstructure:"jwkurl" yaml:"JwkUrl"`
+		RegistryReplicaUrls []string `mapstructure:"registryreplicaurls" yaml:"RegistryReplicaUrls"`
 		RegistryUrl string `mapstructure:"registryurl" yaml:"RegistryUrl"`
 		TopologyDowntimeUrl string `mapstructure:"topologydowntimeurl" yaml:"TopologyDowntimeUrl"`
 		TopologyNamespaceUrl string `mapstructure:"topologynamespaceurl" yaml:"TopologyNamespaceUrl"`
@@ -179,11 +194,16 @@ type Config struct {
 		ChunkSize string `mapstructure:"chunksize" yaml:"ChunkSize"`
 		DataLocation string `mapstructure:"datalocation" yaml:"DataLocation"`
 		DefaultMaxAge time.Duration `mapstructure:"defaultmaxage" yaml:"DefaultMaxAge"`
+		EnableCompression bool `mapstructure:"enablecompression" yaml:"EnableCompression"`
+		EnableDeduplication bool `mapstructure:"enablededuplication" yaml:"EnableDeduplication"`
+		EvictionPolicy string `mapstructure:"evictionpolicy" yaml:"EvictionPolicy"`
 		FDCacheSize int `mapstructure:"fdcachesize" yaml:"FDCacheSize"`
 		HighWaterMarkPercentage int `mapstructure:"highwatermarkpercentage" yaml:"HighWaterMarkPercentage"`
 		LowWaterMarkPercentage int `mapstructure:"lowwatermarkpercentage" yaml:"LowWaterMarkPercentage"`
 		MaxConcurrentPrefetch int `mapstructure:"maxconcurrentprefetch" yaml:"MaxConcurrentPrefetch"`
 		MemoryCacheSize string `mapstructure:"memorycachesize" yaml:"MemoryCacheSize"`
+		NamespaceEvictionPolicies any `mapstructure:"namespaceevictionpolicies" yaml:"NamespaceEvictionPolicies"`
+		NamespaceQuotas any `mapstructure:"namespacequotas" yaml:"NamespaceQuotas"`
 		PrefetchTimeout time.Duration `mapstructure:"prefetchtimeout" yaml:"PrefetchTimeout"`
 		RevalidationJitter int `mapstructure:"revalidationjitter" yaml:"RevalidationJitter"`
 		RunLocation string `mapstructure:"runlocation" yaml:"RunLocation"`
@@ -232,9 +252,19 @@ type Config struct {
 	MinimumDownloadSpeed int `mapstructure:"minimumdownloadspeed" yaml:"MinimumDownloadSpeed"`
 	Monitoring struct {
 		AggregatePrefixes []string `mapstructure:"aggregateprefixes" yaml:"AggregatePrefixes"`
+		AlertEmailRecipients []string `mapstructure:"alertemailrecipients" yaml:"AlertEmailRecipients"`
+		AlertEmailSender string `mapstructure:"alertemailsender" yaml:"AlertEmailSender"`
+		AlertEvaluationInterval time.Duration `mapstructure:"alertevaluationinterval" yaml:"AlertEvaluationInterval"`
+		AlertRepeatInterval time.Duration `mapstructure:"alertrepeatinterval" yaml:"AlertRepeatInterval"`
+		AlertRuleFiles []string `mapstructure:"alertrulefiles" yaml:"AlertRuleFiles"`
+		AlertSmtpPasswordFile string `mapstructure:"alertsmtppasswordfile" yaml:"AlertSmtpPasswordFile"`
+		AlertSmtpServer string `mapstructure:"alertsmtpserver" yaml:"AlertSmtpServer"`
+		AlertSmtpUsername string `mapstructure:"alertsmtpusername" yaml:"AlertSmtpUsername"`
+		AlertWebhookUrl string `mapstructure:"alertwebhookurl" yaml:"AlertWebhookUrl"`
 		DataLocation string `mapstructure:"datalocation" yaml:"DataLocation"`
 		DataRetention time.Duration `mapstructure:"dataretention" yaml:"DataRetention"`
 		DataRetentionSize string `mapstructure:"dataretentionsize" yaml:"DataRetentionSize"`
+		EnableBuiltinAlertRules bool `mapstructure:"enablebuiltinalertrules" yaml:"EnableBuiltinAlertRules"`
 		EnablePrometheus bool `mapstructure:"enableprometheus" yaml:"EnablePrometheus"`
 		LabelLimit int `mapstructure:"labellimit" yaml:"LabelLimit"`
 		LabelNameLengthLimit int `mapstructure:"labelnamelengthlimit" yaml:"LabelNameLengthLimit"`
@@ -283,9 +313,11 @@ type Config struct {
 		EnableIssuer bool `mapstructure:"enableissuer" yaml:"EnableIssuer"`
 		EnableListings bool `mapstructure:"enablelistings" yaml:"EnableListings"`
 		EnableMacaroons bool `mapstructure:"enablemacaroons" yaml:"EnableMacaroons"`
+		EnableNativeBackend bool `mapstructure:"enablenativebackend" yaml:"EnableNativeBackend"`
 		EnableOIDC bool `mapstructure:"enableoidc" yaml:"EnableOIDC"`
 		EnablePublicReads bool `mapstructure:"enablepublicreads" yaml:"EnablePublicReads"`
 		EnableReads bool `mapstructure:"enablereads" yaml:"EnableReads"`
+		EnableThirdPartyCopy bool `mapstructure:"enablethirdpartycopy" yaml:"EnableThirdPartyCopy"`
 		EnableVoms bool `mapstructure:"enablevoms" yaml:"EnableVoms"`
 		EnableWrite bool `mapstructure:"enablewrite" yaml:"EnableWrite"`
 		EnableWrites bool `mapstructure:"enablewrites" yaml:"EnableWrites"`
@@ -302,6 +334,7 @@ type Config struct {
 		GlobusTransferTokenFile string `mapstructure:"globustransfertokenfile" yaml:"GlobusTransferTokenFile"`
 		HttpAuthTokenFile string `mapstructure:"httpauthtokenfile" yaml:"HttpAuthTokenFile"`
 		HttpServiceUrl string `mapstructure:"httpserviceurl" yaml:"HttpServiceUrl"`
+		HttpTokenPassthrough bool `mapstructure:"httptokenpassthrough" yaml:"HttpTokenPassthrough"`
 		IssuerMode string `mapstructure:"issuermode" yaml:"IssuerMode"`
 		Mode string `mapstructure:"mode" yaml:"Mode"`
 		Multiuser bool `mapstructure:"multiuser" yaml:"Multiuser"`
@@ -353,8 +386,11 @@ type Config struct {
 		StoragePrefix string `mapstructure:"storageprefix" yaml:"StoragePrefix"`
 		StorageType string `mapstructure:"storagetype" yaml:"StorageType"`
 		SupportedChecksumTypes []string `mapstructure:"supportedchecksumtypes" yaml:"SupportedChecksumTypes"`
+		ThirdPartyCopyAllowedHosts []string `mapstructure:"thirdpartycopyallowedhosts" yaml:"ThirdPartyCopyAllowedHosts"`
+		ThirdPartyCopyDeniedHosts []string `mapstructure:"thirdpartycopydeniedhosts" yaml:"ThirdPartyCopyDeniedHosts"`
 		TokenAudience string `mapstructure:"tokenaudience" yaml:"TokenAudience"`
 		TransferRateLimit byte_rate.ByteRate `mapstructure:"transferratelimit" yaml:"TransferRateLimit"`
+		UploadStagingExpiry time.Duration `mapstructure:"uploadstagingexpiry" yaml:"UploadStagingExpiry"`
 		UploadTempLocation string `mapstructure:"uploadtemplocation" yaml:"UploadTempLocation"`
 		Url string `mapstructure:"url" yaml:"Url"`
 		UserMapfileRefreshInterval time.Duration `mapstructure:"usermapfilerefreshinterval" yaml:"UserMapfileRefreshInterval"`
@@ -372,6 +408,8 @@ type Config struct {
 		Institutions any `mapstructure:"institutions" yaml:"Institutions"`
 		InstitutionsUrl string `mapstructure:"institutionsurl" yaml:"InstitutionsUrl"`
 		InstitutionsUrlReloadMinutes time.Duration `mapstructure:"institutionsurlreloadminutes" yaml:"InstitutionsUrlReloadMinutes"`
+		ReplicationInterval time.Duration `mapstructure:"replicationinterval" yaml:"ReplicationInterval"`
+		ReplicationPeers []string `mapstructure:"replicationpeers" yaml:"ReplicationPeers"`
 		RequireCacheApproval bool `mapstructure:"requirecacheapproval" yaml:"RequireCacheApproval"`
 		RequireKeyChaining bool `mapstructure:"requirekeychaining" yaml:"RequireKeyChaining"`
 		RequireOriginApproval bool `mapstructure:"requireoriginapproval" yaml:"RequireOriginApproval"`
@@ -387,6 +425,9 @@ type Config struct {
 			MaxCount int `mapstructure:"maxcount" yaml:"MaxCount"`
 		} `mapstructure:"databasebackup" yaml:"DatabaseBackup"`
 		DbLocation string `mapstructure:"dblocation" yaml:"DbLocation"`
+		DbPasswordFile string `mapstructure:"dbpasswordfile" yaml:"DbPasswordFile"`
+		DbType string `mapstructure:"dbtype" yaml:"DbType"`
+		DbUrl string `mapstructure:"dburl" yaml:"DbUrl"`
 		DirectorUrls []string `mapstructure:"directorurls" yaml:"DirectorUrls"`
 		DropPrivileges bool `mapstructure:"dropprivileges" yaml:"DropPrivileges"`
 		EnablePKCS11 bool `mapstructure:"enablepkcs11" yaml:"EnablePKCS11"`
@@ -397,6 +438,11 @@ type Config struct {
 		Hostname string `mapstructure:"hostname" yaml:"Hostname"`
 		IssuerHostname string `mapstructure:"issuerhostname" yaml:"IssuerHostname"`
 		IssuerJwks string `mapstructure:"issuerjwks" yaml:"IssuerJwks"`
+		IssuerKeyRotation struct {
+			Interval time.Duration `mapstructure:"interval" yaml:"Interval"`
+			OverlapPeriod time.Duration `mapstructure:"overlapperiod" yaml:"OverlapPeriod"`
+			PublishPeriod time.Duration `mapstructure:"publishperiod" yaml:"PublishPeriod"`
+		} `mapstructure:"issuerkeyrotation" yaml:"IssuerKeyRotation"`
 		IssuerPort int `mapstructure:"issuerport" yaml:"IssuerPort"`
 		IssuerUrl string `mapstructure:"issuerurl" yaml:"IssuerUrl"`
 		Modules []string `mapstructure:"modules" yaml:"Modules"`
@@ -452,6 +498,10 @@ type Config struct {
 		DisableOriginX509 bool `mapstructure:"disableoriginx509" yaml:"DisableOriginX509"`
 		DisableOrigins bool `mapstructure:"disableorigins" yaml:"DisableOrigins"`
 	} `mapstructure:"topology" yaml:"Topology"`
+	Tracing struct {
+		Endpoint string `mapstructure:"endpoint" yaml:"Endpoint"`
+		SamplingPercentage int `mapstructure:"samplingpercentage" yaml:"SamplingPercentage"`
+	} `mapstructure:"tracing" yaml:"Tracing"`
 	Transport struct {
 		BrokerEndpointCacheTTL time.Duration `mapstructure:"brokerendpointcachettl" yaml:"BrokerEndpointCacheTTL"`
 		DialerKeepAlive time.Duration `mapstructure:"dialerkeepalive" yaml:"DialerKeepAlive"`
@@ -506,6 +556,8 @@ type configWithType struct {
 		DirectorTest struct { Type string; Value bool }
 		DisableClientX509 struct { Type string; Value bool }
 		EnableBroker struct { Type string; Value bool }
+		EnableCompression struct { Type string; Value bool }
+		EnableDeduplication struct { Type string; Value bool }
 		EnableEvictionMonitoring struct { Type string; Value bool }
 		EnableLotman struct { Type string; Value bool }
 		EnableOIDC struct { Type string; Value bool }
@@ -516,6 +568,7 @@ type configWithType struct {
 		EnableVoms struct { Type string; Value bool }
 		EvictionMonitoringInterval struct { Type string; Value time.Duration }
 		EvictionMonitoringMaxDepth struct { Type string; Value int }
+		EvictionPolicy struct { Type string; Value string }
 		ExportLocation struct { Type string; Value string }
 		FedTokenLocation struct { Type string; Value string }
 		FilesBaseSize struct { Type string; Value string }
@@ -527,7 +580,9 @@ type configWithType struct {
 		MemoryCacheSize struct { Type string; Value string }
 		MetaLocations struct { Type string; Value []string }
 		MinDirectorRefreshInterval struct { Type string; Value time.Duration }
+		NamespaceEvictionPolicies struct { Type string; Value any }
 		NamespaceLocation struct { Type string; Value string }
+		NamespaceQuotas struct { Type string; Value any }
 		PSSOrigin struct { Type string; Value string }
 		PermittedNamespaces struct { Type string; Value []string }
 		Port struct { Type string; Value int }
@@ -546,6 +601,7 @@ type configWithType struct {
 		DirectorRetries struct { Type string; Value int }
 		DisableHttpProxy struct { Type string; Value bool }
 		DisableProxyFallback struct { Type string; Value bool }
+		DisableTransferReports struct { Type string; Value bool }
 		EnableOverwrites struct { Type string; Value bool }
 		IsPlugin struct { Type string; Value bool }
 		MaximumDownloadSpeed struct { Type string; Value int }
@@ -554,6 +610,11 @@ type configWithType struct {
 		SlowTransferRampupTime struct { Type string; Value time.Duration }
 		SlowTransferWindow struct { Type string; Value time.Duration }
 		StoppedTransferTimeout struct { Type string; Value time.Duration }
+		StripedDownloadSources struct { Type string; Value int }
+		StripedDownloadStripeSize struct { Type string; Value int }
+		StripedDownloadThreshold struct { Type string; Value int }
+		UploadChunkSize struct { Type string; Value int }
+		UploadStateDirectory struct { Type string; Value string }
 		WorkerCount struct { Type string; Value int }
 	}
 	ClientAgent struct {
@@ -600,8 +661,11 @@ type configWithType struct {
 		RegistryQueryInterval struct { Type string; Value time.Duration }
 		StatConcurrencyLimit struct { Type string; Value int }
 		StatTimeout struct { Type string; Value time.Duration }
+		StateSnapshotInterval struct { Type string; Value time.Duration }
 		SupportContactEmail struct { Type string; Value string }
 		SupportContactUrl struct { Type string; Value string }
+		ThroughputSortEWMATimeConstant struct { Type string; Value time.Duration }
+		ThroughputSortExplorationPercentage struct { Type string; Value int }
 	}
 	DisableHttpProxy struct { Type string; Value bool }
 	DisableProxyFallback struct { Type string; Value bool }
@@ -610,6 +674,7 @@ type configWithType struct {
 		DirectorUrl struct { Type string; Value string }
 		DiscoveryUrl struct { Type string; Value string }
 		JwkUrl struct { Type string; Value string }
+		RegistryReplicaUrls struct { Type string; Value []string }
 		RegistryUrl struct { Type string; Value string }
 		TopologyDowntimeUrl struct { Type string; Value string }
 		TopologyNamespaceUrl struct { Type string; Value string }
@@ -646,11 +711,16 @@ type configWithType struct {
 		ChunkSize struct { Type string; Value string }
 		DataLocation struct { Type string; Value string }
 		DefaultMaxAge struct { Type string; Value time.Duration }
+		EnableCompression struct { Type string; Value bool }
+		EnableDeduplication struct { Type string; Value bool }
+		EvictionPolicy struct { Type string; Value string }
 		FDCacheSize struct { Type string; Value int }
 		HighWaterMarkPercentage struct { Type string; Value int }
 		LowWaterMarkPercentage struct { Type string; Value int }
 		MaxConcurrentPrefetch struct { Type string; Value int }
 		MemoryCacheSize struct { Type string; Value string }
+		NamespaceEvictionPolicies struct { Type string; Value any }
+		NamespaceQuotas struct { Type string; Value any }
 		PrefetchTimeout struct { Type string; Value time.Duration }
 		RevalidationJitter struct { Type string; Value int }
 		RunLocation struct { Type string; Value string }
@@ -699,9 +769,19 @@ type configWithType struct {
 	MinimumDownloadSpeed struct { Type string; Value int }
 	Monitoring struct {
 		AggregatePrefixes struct { Type string; Value []string }
+		AlertEmailRecipients struct { Type string; Value []string }
+		AlertEmailSender struct { Type string; Value string }
+		AlertEvaluationInterval struct { Type string; Value time.Duration }
+		AlertRepeatInterval struct { Type string; Value time.Duration }
+		AlertRuleFiles struct { Type string; Value []string }
+		AlertSmtpPasswordFile struct { Type string; Value string }
+		AlertSmtpServer struct { Type string; Value string }
+		AlertSmtpUsername struct { Type string; Value string }
+		AlertWebhookUrl struct { Type string; Value string }
 		DataLocation struct { Type string; Value string }
 		DataRetention struct { Type string; Value time.Duration }
 		DataRetentionSize struct { Type string; Value string }
+		EnableBuiltinAlertRules struct { Type string; Value bool }
 		EnablePrometheus struct { Type string; Value bool }
 		LabelLimit struct { Type string; Value int }
 		LabelNameLengthLimit struct { Type string; Value int }
@@ -750,9 +830,11 @@ type configWithType struct {
 		EnableIssuer struct { Type string; Value bool }
 		EnableListings struct { Type string; Value bool }
 		EnableMacaroons struct { Type string; Value bool }
+		EnableNativeBackend struct { Type string; Value bool }
 		EnableOIDC struct { Type string; Value bool }
 		EnablePublicReads struct { Type string; Value bool }
 		EnableReads struct { Type string; Value bool }
+		EnableThirdPartyCopy struct { Type string; Value bool }
 		EnableVoms struct { Type string; Value bool }
 		EnableWrite struct { Type string; Value bool }
 		EnableWrites struct { Type string; Value bool }
@@ -769,6 +851,7 @@ type configWithType struct {
 		GlobusTransferTokenFile struct { Type string; Value string }
 		HttpAuthTokenFile struct { Type string; Value string }
 		HttpServiceUrl struct { Type string; Value string }
+		HttpTokenPassthrough struct { Type string; Value bool }
 		IssuerMode struct { Type string; Value string }
 		Mode struct { Type string; Value string }
 		Multiuser struct { Type string; Value bool }
@@ -820,8 +903,11 @@ type configWithType struct {
 		StoragePrefix struct { Type string; Value string }
 		StorageType struct { Type string; Value string }
 		SupportedChecksumTypes struct { Type string; Value []string }
+		ThirdPartyCopyAllowedHosts struct { Type string; Value []string }
+		ThirdPartyCopyDeniedHosts struct { Type string; Value []string }
 		TokenAudience struct { Type string; Value string }
 		TransferRateLimit struct { Type string; Value byte_rate.ByteRate }
+		UploadStagingExpiry struct { Type string; Value time.Duration }
 		UploadTempLocation struct { Type string; Value string }
 		Url struct { Type string; Value string }
 		UserMapfileRefreshInterval struct { Type string; Value time.Duration }
@@ -839,6 +925,8 @@ type configWithType struct {
 		Institutions struct { Type string; Value any }
 		InstitutionsUrl struct { Type string; Value string }
 		InstitutionsUrlReloadMinutes struct { Type string; Value time.Duration }
+		ReplicationInterval struct { Type string; Value time.Duration }
+		ReplicationPeers struct { Type string; Value []string }
 		RequireCacheApproval struct { Type string; Value bool }
 		RequireKeyChaining struct { Type string; Value bool }
 		RequireOriginApproval struct { Type string; Value bool }
@@ -854,6 +942,9 @@ type configWithType struct {
 			MaxCount struct { Type string; Value int }
 		}
 		DbLocation struct { Type string; Value string }
+		DbPasswordFile struct { Type string; Value string }
+		DbType struct { Type string; Value string }
+		DbUrl struct { Type string; Value string }
 		DirectorUrls struct { Type string; Value []string }
 		DropPrivileges struct { Type string; Value bool }
 		EnablePKCS11 struct { Type string; Value bool }
@@ -864,6 +955,11 @@ type configWithType struct {
 		Hostname struct { Type string; Value string }
 		IssuerHostname struct { Type string; Value string }
 		IssuerJwks struct { Type string; Value string }
+		IssuerKeyRotation struct {
+			Interval struct { Type string; Value time.Duration }
+			OverlapPeriod struct { Type string; Value time.Duration }
+			PublishPeriod struct { Type string; Value time.Duration }
+		}
 		IssuerPort struct { Type string; Value int }
 		IssuerUrl struct { Type string; Value string }
 		Modules struct { Type string; Value []string }
@@ -919,6 +1015,10 @@ type configWithType struct {
 		DisableOriginX509 struct { Type string; Value bool }
 		DisableOrigins struct { Type string; Value bool }
 	}
+	Tracing struct {
+		Endpoint struct { Type string; Value string }
+		SamplingPercentage struct { Type string; Value int }
+	}
 	Transport struct {
 		BrokerEndpointCacheTTL struct { Type string; Value time.Duration }
 		DialerKeepAlive struct { Type string; Value time.Duration }
@@ -366,6 +366,67 @@ func TestMultiPubKeysRegisteredOnNamespace(t *testing.T) {
 	require.Equal(t, expectedKids, actualKids)
 }
 
+func TestNamespacePubKeyUpdateKeepsActiveKey(t *testing.T) {
+	t.Cleanup(test_utils.SetupTestLogging(t))
+	ctx, cancel, egrp := test_utils.TestContext(context.Background(), t)
+	server_utils.ResetTestState()
+	t.Cleanup(func() {
+		cancel()
+		assert.NoError(t, egrp.Wait())
+		server_utils.ResetTestState()
+	})
+
+	svr := registryMockup(ctx, t, "PubKeyUpdateKeepsActiveKey")
+	defer func() {
+		err := database.ShutdownDB()
+		assert.NoError(t, err)
+		svr.CloseClientConnections()
+		svr.Close()
+	}()
+	updateUrl := svr.URL + "/api/v1.0/registry/updateNamespacesPubKey"
+
+	oldKey, err := config.GetIssuerPrivateJWK()
+	require.NoError(t, err)
+	prefix := "/rotating"
+	require.NoError(t, registry_client.NamespaceRegister(oldKey, svr.URL+"/api/v1.0/registry", "", prefix, "mock_site_name"))
+
+	// Publish a new key alongside the active one, as an issuer key rotation does
+	issuerKeysDir := param.IssuerKeysDirectory.GetString()
+	newKey, err := config.GeneratePEM(issuerKeysDir)
+	require.NoError(t, err)
+	_, err = config.RefreshKeys()
+	require.NoError(t, err)
+	require.NoError(t, registry_client.NamespacesPubKeyUpdate(oldKey, []string{prefix}, "mock_site_name", updateUrl))
+	ns, err := getRegistrationByPrefix(prefix)
+	require.NoError(t, err)
+	kids, err := getSortedKids(ctx, ns.Pubkey)
+	require.NoError(t, err)
+	assert.Len(t, kids, 2)
+
+	// Dropping the old key while it still signs would invalidate the server's tokens
+	matches, err := filepath.Glob(filepath.Join(issuerKeysDir, "*.pem"))
+	require.NoError(t, err)
+	for _, match := range matches {
+		key, err := config.LoadSinglePEM(match)
+		require.NoError(t, err)
+		if key.KeyID() == oldKey.KeyID() {
+			require.NoError(t, os.Remove(match))
+		}
+	}
+	_, err = config.RefreshKeys()
+	require.NoError(t, err)
+	err = registry_client.NamespacesPubKeyUpdate(oldKey, []string{prefix}, "mock_site_name", updateUrl)
+	require.ErrorContains(t, err, "don't include the key")
+
+	// After the server switches to the new key, the old one can be retired
+	require.NoError(t, registry_client.NamespacesPubKeyUpdate(newKey, []string{prefix}, "mock_site_name", updateUrl))
+	ns, err = getRegistrationByPrefix(prefix)
+	require.NoError(t, err)
+	kids, err = getSortedKids(ctx, ns.Pubkey)
+	require.NoError(t, err)
+	assert.Equal(t, []string{newKey.KeyID()}, kids)
+}
+
 func TestRegistryKeyChainingOSDF(t *testing.T) {
 	t.Cleanup(test_utils.SetupTestLogging(t))
 	server_utils.ResetTestState()
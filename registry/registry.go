@@ -453,6 +453,11 @@ func keySignChallengeCommit(ctx *gin.Context, data *registrationData) (bool, map
 		}
 		return false, nil, errors.Wrapf(err, "Failed to add the prefix %q to the database", ns.Prefix)
 	} else {
+		auditUser := ns.AdminMetadata.UserID
+		if auditUser == "" {
+			auditUser = auditKeyHolder
+		}
+		auditRegistrationChange(ns.ID, ns.Prefix, auditUser, auditRegistrationCreated, "")
 		msg := fmt.Sprintf("Prefix %s successfully registered", ns.Prefix)
 		if inTopo {
 			msg = fmt.Sprintf("Prefix %s successfully registered. Note that there is an existing superspace or subspace of the namespace in the OSDF topology: %s. The registry admin will review your request and approve your namespace if this is expected.", ns.Prefix, GetTopoPrefixString(topoNss))
@@ -818,6 +823,14 @@ func deleteNamespaceHandler(ctx *gin.Context) {
 	}
 
 	// If we get to this point in the code, we've passed all the security checks and we're ready to delete
+	existingNs, err := getRegistrationByPrefix(prefix)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, server_structs.SimpleApiResp{
+			Status: server_structs.RespFailed,
+			Msg:    "server encountered an error deleting namespace from database"})
+		log.Errorf("Failed to get namespace from database: %v", err)
+		return
+	}
 	err = deleteRegistrationByPrefix(prefix)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, server_structs.SimpleApiResp{
@@ -826,6 +839,7 @@ func deleteNamespaceHandler(ctx *gin.Context) {
 		log.Errorf("Failed to delete namespace from database: %v", err)
 		return
 	}
+	auditRegistrationChange(existingNs.ID, prefix, auditKeyHolder, auditRegistrationDeleted, "")
 
 	ctx.JSON(http.StatusOK,
 		server_structs.SimpleApiResp{
@@ -1307,6 +1321,7 @@ func RegisterRegistryAPI(router *gin.RouterGroup) {
 		registryAPI.POST("/checkNamespaceExists", checkNamespaceExistsHandler)
 		registryAPI.POST("/checkNamespaceStatus", checkApprovalHandler)
 		registryAPI.POST("/updateNamespacesPubKey", updateNamespacesPubKey)
+		registryAPI.POST("/replication/changes", getChangeLogHandler)
 
 		registryAPI.DELETE("/*wildcard", deleteNamespaceHandler)
 	}
@@ -0,0 +1,690 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package registry
+
+// Co-admins, ownership transfers and the audit trail of registrations.
+//
+// A registration is owned by the user in AdminMetadata.UserID.  The owner (or
+// a registry admin) may add co-admins, who can view and edit the registration
+// like the owner can.  Changing the owner goes through a transfer request,
+// which any user managing the registration (or the prospective owner, to
+// claim a registration whose owner has left) can file and a registry admin
+// approves or denies.  Every change made through the web API is recorded in
+// the audit log along with the user who made it.
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/pelicanplatform/pelican/database"
+	"github.com/pelicanplatform/pelican/server_structs"
+	"github.com/pelicanplatform/pelican/web_ui"
+)
+
+type (
+	addRegistrationAdminRequest struct {
+		UserID string `json:"user_id" binding:"required"`
+	}
+
+	requestTransferRequest struct {
+		ToUserID string `json:"to_user_id" binding:"required"`
+	}
+
+	listTransfersRequest struct {
+		Status string `form:"status"`
+	}
+
+	listAuditRequest struct {
+		UserID string `form:"user_id"`
+		Prefix string `form:"prefix"`
+		Limit  int    `form:"limit"`
+	}
+)
+
+// Actions recorded in the audit log
+const (
+	auditRegistrationCreated  = "registration_created"
+	auditRegistrationUpdated  = "registration_updated"
+	auditRegistrationApproved = "registration_approved"
+	auditRegistrationDenied   = "registration_denied"
+	auditRegistrationDeleted  = "registration_deleted"
+	auditAdminAdded           = "admin_added"
+	auditAdminRemoved         = "admin_removed"
+	auditTransferRequested    = "transfer_requested"
+	auditTransferApproved     = "transfer_approved"
+	auditTransferDenied       = "transfer_denied"
+)
+
+// The user recorded for changes authenticated by the key of the registration
+// rather than by a logged-in user, e.g. a server registering itself
+const auditKeyHolder = "(namespace key holder)"
+
+// The number of audit entries returned when the request doesn't set a limit
+const defaultAuditLimit = 100
+
+func addAuditEntry(tx *gorm.DB, registrationID int, prefix, user, action, details string) error {
+	entry := server_structs.RegistrationAuditEntry{
+		RegistrationID: registrationID,
+		Prefix:         prefix,
+		UserID:         user,
+		Action:         action,
+		Details:        details,
+		CreatedAt:      time.Now(),
+	}
+	if err := tx.Create(&entry).Error; err != nil {
+		return errors.Wrapf(err, "failed to add %s entry to the audit log of %s", action, prefix)
+	}
+	return nil
+}
+
+// auditRegistrationChange records a change made by one of the existing
+// registration mutators, after it has been committed.  The change has already
+// happened by then, so failures are only logged.
+func auditRegistrationChange(registrationID int, prefix, user, action, details string) {
+	if err := addAuditEntry(database.ServerDatabase, registrationID, prefix, user, action, details); err != nil {
+		log.Warningln("Failed to record the change in the registry audit log:", err)
+	}
+}
+
+func isRegistrationAdmin(tx *gorm.DB, registrationID int, userID string) (bool, error) {
+	var count int64
+	if err := tx.Model(&server_structs.RegistrationAdmin{}).
+		Where("registration_id = ? AND user_id = ?", registrationID, userID).
+		Count(&count).Error; err != nil {
+		return false, errors.Wrap(err, "failed to check the admins of the registration")
+	}
+	return count > 0, nil
+}
+
+func getRegistrationAdmins(tx *gorm.DB, registrationID int) ([]server_structs.RegistrationAdmin, error) {
+	admins := []server_structs.RegistrationAdmin{}
+	if err := tx.Where("registration_id = ?", registrationID).Order("created_at ASC, user_id ASC").Find(&admins).Error; err != nil {
+		return nil, errors.Wrapf(err, "failed to get the admins of registration %d", registrationID)
+	}
+	return admins, nil
+}
+
+// getRegistrationIdsForAdmin returns the IDs of the registrations userID is a
+// co-admin of
+func getRegistrationIdsForAdmin(userID string) (map[int]bool, error) {
+	var ids []int
+	if err := database.ServerDatabase.Model(&server_structs.RegistrationAdmin{}).
+		Where("user_id = ?", userID).Pluck("registration_id", &ids).Error; err != nil {
+		return nil, errors.Wrapf(err, "failed to get the registrations of admin %s", userID)
+	}
+	result := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		result[id] = true
+	}
+	return result, nil
+}
+
+func addRegistrationAdmin(registrationID int, userID, addedBy string) error {
+	return database.ServerDatabase.Transaction(func(tx *gorm.DB) error {
+		ns := server_structs.Registration{}
+		if err := tx.First(&ns, registrationID).Error; err != nil {
+			return errors.Wrapf(err, "failed to get registration %d", registrationID)
+		}
+		if ns.AdminMetadata.UserID == userID {
+			return badRequestError{Message: fmt.Sprintf("User %s already owns the registration of %s", userID, ns.Prefix)}
+		}
+		exists, err := isRegistrationAdmin(tx, registrationID, userID)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return badRequestError{Message: fmt.Sprintf("User %s is already an admin of %s", userID, ns.Prefix)}
+		}
+		admin := server_structs.RegistrationAdmin{
+			RegistrationID: registrationID,
+			UserID:         userID,
+			AddedBy:        addedBy,
+			CreatedAt:      time.Now(),
+		}
+		if err := tx.Create(&admin).Error; err != nil {
+			return errors.Wrapf(err, "failed to add admin %s to %s", userID, ns.Prefix)
+		}
+		if err := addAuditEntry(tx, registrationID, ns.Prefix, addedBy, auditAdminAdded, userID); err != nil {
+			return err
+		}
+		return recordRegistrationChange(tx, ns.Prefix)
+	})
+}
+
+func removeRegistrationAdmin(registrationID int, userID, removedBy string) error {
+	return database.ServerDatabase.Transaction(func(tx *gorm.DB) error {
+		ns := server_structs.Registration{}
+		if err := tx.First(&ns, registrationID).Error; err != nil {
+			return errors.Wrapf(err, "failed to get registration %d", registrationID)
+		}
+		result := tx.Where("registration_id = ? AND user_id = ?", registrationID, userID).Delete(&server_structs.RegistrationAdmin{})
+		if result.Error != nil {
+			return errors.Wrapf(result.Error, "failed to remove admin %s from %s", userID, ns.Prefix)
+		}
+		if result.RowsAffected == 0 {
+			return badRequestError{Message: fmt.Sprintf("User %s is not an admin of %s", userID, ns.Prefix)}
+		}
+		if err := addAuditEntry(tx, registrationID, ns.Prefix, removedBy, auditAdminRemoved, userID); err != nil {
+			return err
+		}
+		return recordRegistrationChange(tx, ns.Prefix)
+	})
+}
+
+func getRegistrationTransfer(id int) (*server_structs.RegistrationTransfer, error) {
+	transfer := server_structs.RegistrationTransfer{}
+	err := database.ServerDatabase.First(&transfer, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "failed to get transfer %d", id)
+	}
+	return &transfer, nil
+}
+
+// listRegistrationTransfers returns the transfers of the registration with
+// registrationID, or of all registrations if it is 0, newest first.  An empty
+// status matches every transfer.
+func listRegistrationTransfers(registrationID int, status server_structs.RegistrationStatus) ([]server_structs.RegistrationTransfer, error) {
+	query := database.ServerDatabase.Order("id DESC")
+	if registrationID != 0 {
+		query = query.Where("registration_id = ?", registrationID)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	transfers := []server_structs.RegistrationTransfer{}
+	if err := query.Find(&transfers).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to list registration transfers")
+	}
+	return transfers, nil
+}
+
+// requestRegistrationTransfer files a pending transfer of the registration to
+// toUserID.  A registration has at most one pending transfer at a time.
+func requestRegistrationTransfer(registrationID int, toUserID, requestedBy string) (*server_structs.RegistrationTransfer, error) {
+	transfer := server_structs.RegistrationTransfer{}
+	err := database.ServerDatabase.Transaction(func(tx *gorm.DB) error {
+		ns := server_structs.Registration{}
+		if err := tx.First(&ns, registrationID).Error; err != nil {
+			return errors.Wrapf(err, "failed to get registration %d", registrationID)
+		}
+		if ns.AdminMetadata.UserID == toUserID {
+			return badRequestError{Message: fmt.Sprintf("User %s already owns the registration of %s", toUserID, ns.Prefix)}
+		}
+		var pending int64
+		if err := tx.Model(&server_structs.RegistrationTransfer{}).
+			Where("registration_id = ? AND status = ?", registrationID, server_structs.RegPending).
+			Count(&pending).Error; err != nil {
+			return errors.Wrapf(err, "failed to check the pending transfers of %s", ns.Prefix)
+		}
+		if pending > 0 {
+			return badRequestError{Message: fmt.Sprintf("The registration of %s already has a pending transfer", ns.Prefix)}
+		}
+		transfer = server_structs.RegistrationTransfer{
+			RegistrationID: registrationID,
+			FromUserID:     ns.AdminMetadata.UserID,
+			ToUserID:       toUserID,
+			RequestedBy:    requestedBy,
+			Status:         server_structs.RegPending,
+			CreatedAt:      time.Now(),
+		}
+		if err := tx.Create(&transfer).Error; err != nil {
+			return errors.Wrapf(err, "failed to save the transfer of %s", ns.Prefix)
+		}
+		return addAuditEntry(tx, registrationID, ns.Prefix, requestedBy, auditTransferRequested,
+			fmt.Sprintf("transfer %d from %q to %q", transfer.ID, transfer.FromUserID, toUserID))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+// decideRegistrationTransfer approves or denies a pending transfer.  Once
+// approved, the new owner is no longer listed as a co-admin, and the previous
+// owner loses access unless they are one.
+func decideRegistrationTransfer(transferID int, approve bool, decidedBy string) (*server_structs.RegistrationTransfer, error) {
+	transfer := server_structs.RegistrationTransfer{}
+	err := database.ServerDatabase.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&transfer, transferID).Error; err != nil {
+			return errors.Wrapf(err, "failed to get transfer %d", transferID)
+		}
+		if transfer.Status != server_structs.RegPending {
+			return badRequestError{Message: fmt.Sprintf("Transfer %d was already %s", transferID, transfer.Status.LowerString())}
+		}
+		ns := server_structs.Registration{}
+		if err := tx.First(&ns, transfer.RegistrationID).Error; err != nil {
+			return errors.Wrapf(err, "failed to get registration %d", transfer.RegistrationID)
+		}
+
+		now := time.Now()
+		transfer.DecidedBy = decidedBy
+		transfer.DecidedAt = &now
+		action := auditTransferDenied
+		transfer.Status = server_structs.RegDenied
+		if approve {
+			action = auditTransferApproved
+			transfer.Status = server_structs.RegApproved
+			ns.AdminMetadata.UserID = transfer.ToUserID
+			ns.AdminMetadata.UpdatedAt = now
+			adminMetadataByte, err := json.Marshal(ns.AdminMetadata)
+			if err != nil {
+				return errors.Wrap(err, "Error marshaling admin metadata")
+			}
+			if err := tx.Model(&ns).Where("id = ?", ns.ID).Update("admin_metadata", string(adminMetadataByte)).Error; err != nil {
+				return errors.Wrapf(err, "failed to change the owner of %s", ns.Prefix)
+			}
+			if err := tx.Where("registration_id = ? AND user_id = ?", ns.ID, transfer.ToUserID).Delete(&server_structs.RegistrationAdmin{}).Error; err != nil {
+				return errors.Wrapf(err, "failed to update the admins of %s", ns.Prefix)
+			}
+		}
+		if err := tx.Save(&transfer).Error; err != nil {
+			return errors.Wrapf(err, "failed to update transfer %d", transferID)
+		}
+		if err := addAuditEntry(tx, ns.ID, ns.Prefix, decidedBy, action,
+			fmt.Sprintf("transfer %d from %q to %q", transfer.ID, transfer.FromUserID, transfer.ToUserID)); err != nil {
+			return err
+		}
+		if approve {
+			return recordRegistrationChange(tx, ns.Prefix)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+// listAuditEntries returns the newest audit entries matching the filter.
+// A zero registrationID or an empty userID or prefix matches any value.
+func listAuditEntries(registrationID int, userID, prefix string, limit int) ([]server_structs.RegistrationAuditEntry, error) {
+	query := database.ServerDatabase.Order("id DESC")
+	if registrationID != 0 {
+		query = query.Where("registration_id = ?", registrationID)
+	}
+	if userID != "" {
+		query = query.Where("user_id = ?", userID)
+	}
+	if prefix != "" {
+		query = query.Where("prefix = ?", prefix)
+	}
+	if limit <= 0 {
+		limit = defaultAuditLimit
+	}
+	entries := []server_structs.RegistrationAuditEntry{}
+	if err := query.Limit(limit).Find(&entries).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to list the registry audit log")
+	}
+	return entries, nil
+}
+
+// writeRegistrationAdminError responds to a failed co-admin or transfer
+// operation, telling the user what was wrong with their request if that was
+// the cause.
+func writeRegistrationAdminError(ctx *gin.Context, err error, msg string) {
+	var badReq badRequestError
+	if errors.As(err, &badReq) {
+		ctx.JSON(http.StatusBadRequest, server_structs.SimpleApiResp{
+			Status: server_structs.RespFailed,
+			Msg:    badReq.Message})
+		return
+	}
+	log.Errorf("%s: %v", msg, err)
+	ctx.JSON(http.StatusInternalServerError, server_structs.SimpleApiResp{
+		Status: server_structs.RespFailed,
+		Msg:    msg})
+}
+
+// registrationAccess describes how the logged-in user relates to a registration
+type registrationAccess struct {
+	ns        *server_structs.Registration
+	user      string
+	isAdmin   bool // a registry admin
+	isOwner   bool
+	isCoAdmin bool
+}
+
+func (a *registrationAccess) canManage() bool {
+	return a.isAdmin || a.isOwner || a.isCoAdmin
+}
+
+// getRegistrationAccess looks up the registration whose ID is in the path and
+// the logged-in user's access to it.  On failure, it writes the error response
+// and returns nil.
+func getRegistrationAccess(ctx *gin.Context) *registrationAccess {
+	user, userId, groups, err := web_ui.GetUserGroups(ctx)
+	if err != nil || user == "" {
+		ctx.JSON(http.StatusUnauthorized, server_structs.SimpleApiResp{
+			Status: server_structs.RespFailed,
+			Msg:    "You need to login to perform this action"})
+		return nil
+	}
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || id <= 0 {
+		ctx.JSON(http.StatusBadRequest, server_structs.SimpleApiResp{
+			Status: server_structs.RespFailed,
+			Msg:    "Invalid ID format. ID must a non-zero integer"})
+		return nil
+	}
+	exists, err := registrationExistsById(id)
+	if err != nil {
+		log.Error("Error checking if namespace exists: ", err)
+		ctx.JSON(http.StatusInternalServerError, server_structs.SimpleApiResp{
+			Status: server_structs.RespFailed,
+			Msg:    "Error checking if namespace exists"})
+		return nil
+	}
+	if !exists {
+		ctx.JSON(http.StatusNotFound, server_structs.SimpleApiResp{
+			Status: server_structs.RespFailed,
+			Msg:    "Namespace not found"})
+		return nil
+	}
+	ns, err := getRegistrationById(id)
+	if err != nil {
+		log.Error("Error getting namespace: ", err)
+		ctx.JSON(http.StatusInternalServerError, server_structs.SimpleApiResp{
+			Status: server_structs.RespFailed,
+			Msg:    "Error getting namespace"})
+		return nil
+	}
+	isCoAdmin, err := isRegistrationAdmin(database.ServerDatabase, id, user)
+	if err != nil {
+		log.Error("Error checking if user is an admin of the namespace: ", err)
+		ctx.JSON(http.StatusInternalServerError, server_structs.SimpleApiResp{
+			Status: server_structs.RespFailed,
+			Msg:    "Error checking if namespace belongs to the user"})
+		return nil
+	}
+
+	identity := web_ui.UserIdentity{
+		Username: user,
+		ID:       userId,
+		Groups:   groups,
+		Sub:      ctx.GetString("OIDCSub"),
+	}
+	isAdmin, _ := web_ui.CheckAdmin(identity)
+	return &registrationAccess{
+		ns:        ns,
+		user:      user,
+		isAdmin:   isAdmin,
+		isOwner:   ns.AdminMetadata.UserID == user,
+		isCoAdmin: isCoAdmin,
+	}
+}
+
+// authorizeRegistrationAccess checks that the logged-in user may manage the
+// registration whose ID is in the path: registry admins and the owner always
+// may, while co-admins may unless ownerOnly is set.  Otherwise, it writes the
+// error response and returns nil.
+func authorizeRegistrationAccess(ctx *gin.Context, ownerOnly bool) *registrationAccess {
+	access := getRegistrationAccess(ctx)
+	if access == nil {
+		return nil
+	}
+	if access.isAdmin || access.isOwner || (!ownerOnly && access.isCoAdmin) {
+		return access
+	}
+	log.Errorf("Access denied from user %s for namespace with id=%d", access.user, access.ns.ID)
+	ctx.JSON(http.StatusForbidden, server_structs.SimpleApiResp{
+		Status: server_structs.RespFailed,
+		Msg:    "You do not have permissions to manage this namespace registration"})
+	return nil
+}
+
+// List the co-admins of a registration
+//
+// GET /namespaces/:id/admins
+func listRegistrationAdminsHandler(ctx *gin.Context) {
+	access := authorizeRegistrationAccess(ctx, false)
+	if access == nil {
+		return
+	}
+	ns := access.ns
+	admins, err := getRegistrationAdmins(database.ServerDatabase, ns.ID)
+	if err != nil {
+		log.Error(err)
+		ctx.JSON(http.StatusInternalServerError, server_structs.SimpleApiResp{
+			Status: server_structs.RespFailed,
+			Msg:    "Failed to list the admins of the namespace"})
+		return
+	}
+	ctx.JSON(http.StatusOK, admins)
+}
+
+// Add a co-admin to a registration.  Only the owner and registry admins can.
+//
+// POST /namespaces/:id/admins
+func addRegistrationAdminHandler(ctx *gin.Context) {
+	access := authorizeRegistrationAccess(ctx, true)
+	if access == nil {
+		return
+	}
+	req := addRegistrationAdminRequest{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, server_structs.SimpleApiResp{
+			Status: server_structs.RespFailed,
+			Msg:    fmt.Sprintf("Invalid request: %v", err)})
+		return
+	}
+	if err := addRegistrationAdmin(access.ns.ID, req.UserID, access.user); err != nil {
+		writeRegistrationAdminError(ctx, err, "Failed to add the admin to the namespace")
+		return
+	}
+	ctx.JSON(http.StatusOK, server_structs.SimpleApiResp{
+		Status: server_structs.RespOK,
+		Msg:    fmt.Sprintf("User %s is now an admin of %s", req.UserID, access.ns.Prefix)})
+}
+
+// Remove a co-admin from a registration.  The owner and registry admins can
+// remove anyone; co-admins can only remove themselves.
+//
+// DELETE /namespaces/:id/admins/:user
+func removeRegistrationAdminHandler(ctx *gin.Context) {
+	access := authorizeRegistrationAccess(ctx, false)
+	if access == nil {
+		return
+	}
+	target := ctx.Param("user")
+	if !access.isAdmin && !access.isOwner && target != access.user {
+		ctx.JSON(http.StatusForbidden, server_structs.SimpleApiResp{
+			Status: server_structs.RespFailed,
+			Msg:    "Only the owner of the namespace can remove other admins"})
+		return
+	}
+	if err := removeRegistrationAdmin(access.ns.ID, target, access.user); err != nil {
+		writeRegistrationAdminError(ctx, err, "Failed to remove the admin from the namespace")
+		return
+	}
+	ctx.JSON(http.StatusOK, server_structs.SimpleApiResp{
+		Status: server_structs.RespOK,
+		Msg:    "success"})
+}
+
+// List the ownership transfers of a registration
+//
+// GET /namespaces/:id/transfers
+func listNamespaceTransfersHandler(ctx *gin.Context) {
+	access := authorizeRegistrationAccess(ctx, false)
+	if access == nil {
+		return
+	}
+	ns := access.ns
+	transfers, err := listRegistrationTransfers(ns.ID, "")
+	if err != nil {
+		log.Error(err)
+		ctx.JSON(http.StatusInternalServerError, server_structs.SimpleApiResp{
+			Status: server_structs.RespFailed,
+			Msg:    "Failed to list the transfers of the namespace"})
+		return
+	}
+	ctx.JSON(http.StatusOK, transfers)
+}
+
+// Request the ownership of a registration to be transferred.  Users managing
+// the registration can transfer it to anyone, and any user can ask for a
+// registration to be transferred to themselves.
+//
+// POST /namespaces/:id/transfers
+func requestTransferHandler(ctx *gin.Context) {
+	req := requestTransferRequest{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, server_structs.SimpleApiResp{
+			Status: server_structs.RespFailed,
+			Msg:    fmt.Sprintf("Invalid request: %v", err)})
+		return
+	}
+	access := getRegistrationAccess(ctx)
+	if access == nil {
+		return
+	}
+	if !access.canManage() && access.user != req.ToUserID {
+		ctx.JSON(http.StatusForbidden, server_structs.SimpleApiResp{
+			Status: server_structs.RespFailed,
+			Msg:    "You can only request the transfer of a namespace you manage, or to yourself"})
+		return
+	}
+	transfer, err := requestRegistrationTransfer(access.ns.ID, req.ToUserID, access.user)
+	if err != nil {
+		writeRegistrationAdminError(ctx, err, "Failed to request the transfer of the namespace")
+		return
+	}
+	ctx.JSON(http.StatusOK, transfer)
+}
+
+// List ownership transfers of all registrations for registry admins to review
+//
+// GET /transfers
+func listTransfersHandler(ctx *gin.Context) {
+	req := listTransfersRequest{}
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, server_structs.SimpleApiResp{
+			Status: server_structs.RespFailed,
+			Msg:    fmt.Sprintf("Invalid query parameters: %v", err)})
+		return
+	}
+	if req.Status != "" && !server_structs.IsValidRegStatus(req.Status) {
+		ctx.JSON(http.StatusBadRequest, server_structs.SimpleApiResp{
+			Status: server_structs.RespFailed,
+			Msg:    fmt.Sprintf("Invalid query parameters %s: status must be one of 'Pending', 'Approved', 'Denied'", req.Status)})
+		return
+	}
+	transfers, err := listRegistrationTransfers(0, server_structs.RegistrationStatus(req.Status))
+	if err != nil {
+		log.Error(err)
+		ctx.JSON(http.StatusInternalServerError, server_structs.SimpleApiResp{
+			Status: server_structs.RespFailed,
+			Msg:    "Failed to list transfers"})
+		return
+	}
+	ctx.JSON(http.StatusOK, transfers)
+}
+
+// Approve or deny a pending ownership transfer
+//
+// PATCH /transfers/:id/approve
+// PATCH /transfers/:id/deny
+func decideTransferHandler(ctx *gin.Context, approve bool) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || id <= 0 {
+		ctx.JSON(http.StatusBadRequest, server_structs.SimpleApiResp{
+			Status: server_structs.RespFailed,
+			Msg:    "Invalid ID format. ID must a non-zero integer"})
+		return
+	}
+	existing, err := getRegistrationTransfer(id)
+	if err != nil {
+		log.Error(err)
+		ctx.JSON(http.StatusInternalServerError, server_structs.SimpleApiResp{
+			Status: server_structs.RespFailed,
+			Msg:    "Failed to get the transfer"})
+		return
+	}
+	if existing == nil {
+		ctx.JSON(http.StatusNotFound, server_structs.SimpleApiResp{
+			Status: server_structs.RespFailed,
+			Msg:    "Transfer not found"})
+		return
+	}
+	transfer, err := decideRegistrationTransfer(id, approve, ctx.GetString("User"))
+	if err != nil {
+		writeRegistrationAdminError(ctx, err, "Failed to update the transfer")
+		return
+	}
+	ctx.JSON(http.StatusOK, transfer)
+}
+
+// List the audit log of a registration
+//
+// GET /namespaces/:id/audit
+func listNamespaceAuditHandler(ctx *gin.Context) {
+	access := authorizeRegistrationAccess(ctx, false)
+	if access == nil {
+		return
+	}
+	ns := access.ns
+	req := listAuditRequest{}
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, server_structs.SimpleApiResp{
+			Status: server_structs.RespFailed,
+			Msg:    fmt.Sprintf("Invalid query parameters: %v", err)})
+		return
+	}
+	entries, err := listAuditEntries(ns.ID, req.UserID, "", req.Limit)
+	if err != nil {
+		log.Error(err)
+		ctx.JSON(http.StatusInternalServerError, server_structs.SimpleApiResp{
+			Status: server_structs.RespFailed,
+			Msg:    "Failed to list the audit log of the namespace"})
+		return
+	}
+	ctx.JSON(http.StatusOK, entries)
+}
+
+// List the audit log of all registrations, optionally filtered by user or prefix
+//
+// GET /audit
+func listAuditHandler(ctx *gin.Context) {
+	req := listAuditRequest{}
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, server_structs.SimpleApiResp{
+			Status: server_structs.RespFailed,
+			Msg:    fmt.Sprintf("Invalid query parameters: %v", err)})
+		return
+	}
+	entries, err := listAuditEntries(0, req.UserID, req.Prefix, req.Limit)
+	if err != nil {
+		log.Error(err)
+		ctx.JSON(http.StatusInternalServerError, server_structs.SimpleApiResp{
+			Status: server_structs.RespFailed,
+			Msg:    "Failed to list the audit log"})
+		return
+	}
+	ctx.JSON(http.StatusOK, entries)
+}
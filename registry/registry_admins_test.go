@@ -0,0 +1,229 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package registry
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pelicanplatform/pelican/database"
+	"github.com/pelicanplatform/pelican/server_structs"
+	"github.com/pelicanplatform/pelican/server_utils"
+	"github.com/pelicanplatform/pelican/test_utils"
+)
+
+// setupRegistrationAdminTest adds a registration of /foo owned by "owner"
+// and returns its ID
+func setupRegistrationAdminTest(t *testing.T) int {
+	t.Cleanup(test_utils.SetupTestLogging(t))
+	server_utils.ResetTestState()
+	t.Cleanup(server_utils.ResetTestState)
+	setupMockRegistryDB(t)
+	t.Cleanup(func() { teardownMockRegistryDB(t) })
+
+	require.NoError(t, insertMockDBData([]server_structs.Registration{
+		mockNamespace("/foo", "pubkey", "", server_structs.AdminMetadata{UserID: "owner", Status: server_structs.RegApproved}),
+	}))
+	id, err := getLastNamespaceId()
+	require.NoError(t, err)
+	return id
+}
+
+func auditActions(t *testing.T, id int) []string {
+	entries, err := listAuditEntries(id, "", "", 0)
+	require.NoError(t, err)
+	actions := []string{}
+	for _, entry := range entries {
+		actions = append(actions, entry.UserID+":"+entry.Action)
+	}
+	return actions
+}
+
+func TestRegistrationAdmins(t *testing.T) {
+	id := setupRegistrationAdminTest(t)
+
+	require.NoError(t, addRegistrationAdmin(id, "helper", "owner"))
+	belongs, err := registrationBelongsToUserId(id, "helper")
+	require.NoError(t, err)
+	assert.True(t, belongs)
+
+	var badReq badRequestError
+	assert.True(t, errors.As(addRegistrationAdmin(id, "helper", "owner"), &badReq), "adding an admin twice is refused")
+	assert.True(t, errors.As(addRegistrationAdmin(id, "owner", "admin"), &badReq), "the owner can't be a co-admin")
+
+	admins, err := getRegistrationAdmins(database.ServerDatabase, id)
+	require.NoError(t, err)
+	require.Len(t, admins, 1)
+	assert.Equal(t, "helper", admins[0].UserID)
+	assert.Equal(t, "owner", admins[0].AddedBy)
+
+	require.NoError(t, removeRegistrationAdmin(id, "helper", "admin"))
+	belongs, err = registrationBelongsToUserId(id, "helper")
+	require.NoError(t, err)
+	assert.False(t, belongs)
+	assert.True(t, errors.As(removeRegistrationAdmin(id, "helper", "admin"), &badReq))
+
+	assert.Equal(t, []string{"admin:" + auditAdminRemoved, "owner:" + auditAdminAdded}, auditActions(t, id))
+}
+
+func TestRegistrationTransfers(t *testing.T) {
+	id := setupRegistrationAdminTest(t)
+	require.NoError(t, addRegistrationAdmin(id, "successor", "owner"))
+
+	transfer, err := requestRegistrationTransfer(id, "stranger", "owner")
+	require.NoError(t, err)
+	assert.Equal(t, server_structs.RegPending, transfer.Status)
+	assert.Equal(t, "owner", transfer.FromUserID)
+
+	var badReq badRequestError
+	_, err = requestRegistrationTransfer(id, "successor", "owner")
+	assert.True(t, errors.As(err, &badReq), "only one transfer may be pending")
+
+	denied, err := decideRegistrationTransfer(transfer.ID, false, "admin")
+	require.NoError(t, err)
+	assert.Equal(t, server_structs.RegDenied, denied.Status)
+	assert.Equal(t, "admin", denied.DecidedBy)
+	require.NotNil(t, denied.DecidedAt)
+	_, err = decideRegistrationTransfer(transfer.ID, true, "admin")
+	assert.True(t, errors.As(err, &badReq), "a decided transfer can't be decided again")
+
+	ns, err := getRegistrationById(id)
+	require.NoError(t, err)
+	assert.Equal(t, "owner", ns.AdminMetadata.UserID)
+
+	transfer, err = requestRegistrationTransfer(id, "successor", "successor")
+	require.NoError(t, err)
+	approved, err := decideRegistrationTransfer(transfer.ID, true, "admin")
+	require.NoError(t, err)
+	assert.Equal(t, server_structs.RegApproved, approved.Status)
+
+	// The new owner is no longer a co-admin, and the previous owner has no access
+	ns, err = getRegistrationById(id)
+	require.NoError(t, err)
+	assert.Equal(t, "successor", ns.AdminMetadata.UserID)
+	assert.Equal(t, server_structs.RegApproved, ns.AdminMetadata.Status)
+	admins, err := getRegistrationAdmins(database.ServerDatabase, id)
+	require.NoError(t, err)
+	assert.Empty(t, admins)
+	belongs, err := registrationBelongsToUserId(id, "owner")
+	require.NoError(t, err)
+	assert.False(t, belongs)
+
+	transfers, err := listRegistrationTransfers(id, "")
+	require.NoError(t, err)
+	assert.Len(t, transfers, 2)
+	pending, err := listRegistrationTransfers(0, server_structs.RegPending)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+
+	assert.Equal(t, []string{
+		"admin:" + auditTransferApproved,
+		"successor:" + auditTransferRequested,
+		"admin:" + auditTransferDenied,
+		"owner:" + auditTransferRequested,
+		"owner:" + auditAdminAdded,
+	}, auditActions(t, id))
+}
+
+func TestRegistrationAdminHandlers(t *testing.T) {
+	id := setupRegistrationAdminTest(t)
+
+	router := gin.New()
+	group := router.Group("/", func(ctx *gin.Context) {
+		ctx.Set("User", ctx.GetHeader("X-Test-User"))
+	})
+	group.GET("/namespaces/user", listNamespacesForUser)
+	group.GET("/namespaces/:id/admins", listRegistrationAdminsHandler)
+	group.POST("/namespaces/:id/admins", addRegistrationAdminHandler)
+	group.DELETE("/namespaces/:id/admins/:user", removeRegistrationAdminHandler)
+	group.POST("/namespaces/:id/transfers", requestTransferHandler)
+	group.GET("/namespaces/:id/audit", listNamespaceAuditHandler)
+
+	request := func(user, method, path string, body any) *httptest.ResponseRecorder {
+		var reqBody bytes.Buffer
+		if body != nil {
+			require.NoError(t, json.NewEncoder(&reqBody).Encode(body))
+		}
+		req, err := http.NewRequest(method, path, &reqBody)
+		require.NoError(t, err)
+		req.Header.Set("X-Test-User", user)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	adminsPath := fmt.Sprintf("/namespaces/%d/admins", id)
+
+	assert.Equal(t, http.StatusUnauthorized, request("", http.MethodGet, adminsPath, nil).Code)
+	assert.Equal(t, http.StatusForbidden, request("stranger", http.MethodGet, adminsPath, nil).Code)
+	assert.Equal(t, http.StatusNotFound, request("owner", http.MethodGet, "/namespaces/999/admins", nil).Code)
+	assert.Equal(t, http.StatusBadRequest, request("owner", http.MethodPost, adminsPath, map[string]string{}).Code)
+
+	require.Equal(t, http.StatusOK, request("owner", http.MethodPost, adminsPath, map[string]string{"user_id": "helper"}).Code)
+	require.Equal(t, http.StatusOK, request("admin", http.MethodPost, adminsPath, map[string]string{"user_id": "other"}).Code)
+	assert.Equal(t, http.StatusBadRequest, request("owner", http.MethodPost, adminsPath, map[string]string{"user_id": "helper"}).Code)
+
+	// Co-admins see the registration but only the owner may add admins
+	w := request("helper", http.MethodGet, adminsPath, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	admins := []server_structs.RegistrationAdmin{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &admins))
+	assert.Len(t, admins, 2)
+	assert.Equal(t, http.StatusForbidden, request("helper", http.MethodPost, adminsPath, map[string]string{"user_id": "friend"}).Code)
+
+	w = request("helper", http.MethodGet, "/namespaces/user", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	registrations := []server_structs.Registration{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &registrations))
+	require.Len(t, registrations, 1)
+	assert.Equal(t, "/foo", registrations[0].Prefix)
+
+	// Co-admins can only remove themselves
+	assert.Equal(t, http.StatusForbidden, request("helper", http.MethodDelete, adminsPath+"/other", nil).Code)
+	assert.Equal(t, http.StatusOK, request("helper", http.MethodDelete, adminsPath+"/helper", nil).Code)
+	assert.Equal(t, http.StatusOK, request("owner", http.MethodDelete, adminsPath+"/other", nil).Code)
+
+	// Anyone may claim a registration, but not give it away
+	transfersPath := fmt.Sprintf("/namespaces/%d/transfers", id)
+	assert.Equal(t, http.StatusForbidden, request("stranger", http.MethodPost, transfersPath, map[string]string{"to_user_id": "friend"}).Code)
+	w = request("stranger", http.MethodPost, transfersPath, map[string]string{"to_user_id": "stranger"})
+	require.Equal(t, http.StatusOK, w.Code)
+	transfer := server_structs.RegistrationTransfer{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &transfer))
+	assert.Equal(t, "owner", transfer.FromUserID)
+	assert.Equal(t, server_structs.RegPending, transfer.Status)
+
+	assert.Equal(t, http.StatusForbidden, request("stranger", http.MethodGet, fmt.Sprintf("/namespaces/%d/audit", id), nil).Code)
+	w = request("owner", http.MethodGet, fmt.Sprintf("/namespaces/%d/audit?user_id=owner", id), nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	entries := []server_structs.RegistrationAuditEntry{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &entries))
+	require.Len(t, entries, 2)
+	assert.Equal(t, auditAdminRemoved, entries[0].Action)
+	assert.Equal(t, "other", entries[0].Details)
+	assert.Equal(t, auditAdminAdded, entries[1].Action)
+}
@@ -108,9 +108,9 @@ func namespaceSupSubChecks(prefix string) (superspaces []string, subspaces []str
 	// The very first thing we do is check if there's a match in topo. We simply flag it's in topology if so
 	if config.GetPreferredPrefix() == config.OsdfPrefix {
 		topoSuperSubQuery := `
-		SELECT prefix FROM topology WHERE (? || '/') LIKE (prefix || '/%')
+		SELECT prefix FROM topology WHERE (CAST(? AS TEXT) || '/') LIKE (prefix || '/%')
 		UNION
-		SELECT prefix FROM topology WHERE (prefix || '/') LIKE (? || '/%')
+		SELECT prefix FROM topology WHERE (prefix || '/') LIKE (CAST(? AS TEXT) || '/%')
 		`
 		err = database.ServerDatabase.Raw(topoSuperSubQuery, prefix, prefix).Scan(&topoNss).Error
 		if err != nil {
@@ -125,7 +125,7 @@ func namespaceSupSubChecks(prefix string) (superspaces []string, subspaces []str
 	// Check if any registered namespaces already superspace the incoming namespace,
 	// eg if /foo is already registered, this will be true for an incoming /foo/bar because
 	// /foo is logically above /foo/bar (according to my logic, anyway)
-	superspaceQuery := `SELECT prefix FROM registrations WHERE (?) LIKE (prefix || '/%')`
+	superspaceQuery := `SELECT prefix FROM registrations WHERE CAST(? AS TEXT) LIKE (prefix || '/%')`
 	err = database.ServerDatabase.Raw(superspaceQuery, prefix).Scan(&superspaces).Error
 	if err != nil {
 		return
@@ -134,7 +134,7 @@ func namespaceSupSubChecks(prefix string) (superspaces []string, subspaces []str
 	// Check if any registered namespaces already subspace the incoming namespace,
 	// eg if /foo/bar is already registered, this will be true for an incoming /foo because
 	// /foo/bar is logically below /foo
-	subspaceQuery := `SELECT prefix FROM registrations WHERE (prefix) LIKE (? || '/%')`
+	subspaceQuery := `SELECT prefix FROM registrations WHERE (prefix) LIKE (CAST(? AS TEXT) || '/%')`
 	err = database.ServerDatabase.Raw(subspaceQuery, prefix).Scan(&subspaces).Error
 	if err != nil {
 		return
@@ -161,7 +161,11 @@ func registrationBelongsToUserId(id int, userId string) (bool, error) {
 	} else if err != nil {
 		return false, errors.Wrap(err, "error retrieving registration")
 	}
-	return result.AdminMetadata.UserID == userId, nil
+	if result.AdminMetadata.UserID == userId {
+		return true, nil
+	}
+	// Co-admins manage the registration as the owner does
+	return isRegistrationAdmin(database.ServerDatabase, id, userId)
 }
 
 func getRegistrationJwksById(id int) (jwk.Set, error) {
@@ -642,6 +646,9 @@ func AddRegistration(ns *server_structs.Registration) error {
 				if err := tx.Create(&service).Error; err != nil {
 					return errors.Wrapf(err, "failed to save service: %s", ns.AdminMetadata.SiteName)
 				}
+				if err := recordServerChange(tx, server.ID); err != nil {
+					return err
+				}
 			} else if err != nil {
 				return errors.Wrapf(err, "failed to check for existing server: %s", ns.AdminMetadata.SiteName)
 			} else {
@@ -687,10 +694,13 @@ func AddRegistration(ns *server_structs.Registration) error {
 				if err := tx.Create(&service).Error; err != nil {
 					return errors.Wrapf(err, "failed to create new entry in service table: %s", ns.AdminMetadata.SiteName)
 				}
+				if err := recordServerChange(tx, existingServer.ID); err != nil {
+					return err
+				}
 			}
 		}
 
-		return nil
+		return recordRegistrationChange(tx, ns.Prefix)
 	})
 }
 
@@ -747,10 +757,13 @@ func updateRegistration(ns *server_structs.Registration) error {
 				if err := tx.Model(&server_structs.Server{}).Where("id = ?", service.ServerID).Updates(updates).Error; err != nil {
 					return errors.Wrapf(err, "failed to update server: %s", ns.AdminMetadata.SiteName)
 				}
+				if err := recordServerChange(tx, service.ServerID); err != nil {
+					return err
+				}
 			}
 		}
 
-		return nil
+		return recordRegistrationChange(tx, ns.Prefix)
 	})
 }
 
@@ -775,7 +788,12 @@ func updateRegistrationStatusById(id int, status server_structs.RegistrationStat
 		return errors.Wrap(err, "Error marshaling admin metadata")
 	}
 
-	return database.ServerDatabase.Model(ns).Where("id = ?", id).Update("admin_metadata", string(adminMetadataByte)).Error
+	return database.ServerDatabase.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(ns).Where("id = ?", id).Update("admin_metadata", string(adminMetadataByte)).Error; err != nil {
+			return err
+		}
+		return recordRegistrationChange(tx, ns.Prefix)
+	})
 }
 
 func setRegistrationPubKey(prefix string, pubkeyDbString string) error {
@@ -786,7 +804,12 @@ func setRegistrationPubKey(prefix string, pubkeyDbString string) error {
 		return errors.New("invalid pubkeyDbString. pubkeyDbString must not be empty")
 	}
 	ns := server_structs.Registration{}
-	return database.ServerDatabase.Model(ns).Where("prefix = ? ", prefix).Update("pubkey", pubkeyDbString).Error
+	return database.ServerDatabase.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(ns).Where("prefix = ? ", prefix).Update("pubkey", pubkeyDbString).Error; err != nil {
+			return err
+		}
+		return recordRegistrationChange(tx, prefix)
+	})
 }
 
 // Note: If this is a server registration, the foreign key constraint applied on the DB will
@@ -806,11 +829,20 @@ func deleteRegistrationByID(id int) error {
 			}
 			// No service mapping found; proceed to delete registration only
 		}
+		var prefixes []string
+		if err := tx.Model(&server_structs.Registration{}).Where("id = ?", id).Pluck("prefix", &prefixes).Error; err != nil {
+			return err
+		}
 
 		// Delete the registration (this cascadingly deletes the related service entry)
 		if err := tx.Delete(&server_structs.Registration{}, id).Error; err != nil {
 			return err
 		}
+		for _, prefix := range prefixes {
+			if err := recordRegistrationChange(tx, prefix); err != nil {
+				return err
+			}
+		}
 
 		if svc.ServerID != "" {
 			var remainingSvcs []server_structs.Service
@@ -858,6 +890,9 @@ func deleteRegistrationByID(id int) error {
 					return err
 				}
 			}
+			if err := recordServerChange(tx, svc.ServerID); err != nil {
+				return err
+			}
 		}
 
 		return nil
@@ -865,8 +900,12 @@ func deleteRegistrationByID(id int) error {
 }
 
 func deleteRegistrationByPrefix(prefix string) error {
-	// GORM by default uses transaction for write operations
-	return database.ServerDatabase.Where("prefix = ?", prefix).Delete(&server_structs.Registration{}).Error
+	return database.ServerDatabase.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("prefix = ?", prefix).Delete(&server_structs.Registration{}).Error; err != nil {
+			return err
+		}
+		return recordRegistrationChange(tx, prefix)
+	})
 }
 
 func deleteServerByID(id string) error {
@@ -887,15 +926,24 @@ func deleteServerByID(id string) error {
 		}
 		// Delete all registrations corresponding to the server separately
 		if len(registrationIDs) > 0 {
+			var prefixes []string
+			if err := tx.Model(&server_structs.Registration{}).Where("id IN ?", registrationIDs).Pluck("prefix", &prefixes).Error; err != nil {
+				return errors.Wrapf(err, "failed to get registrations for server %s", id)
+			}
 			if err := tx.Delete(&server_structs.Registration{}, registrationIDs).Error; err != nil {
 				return errors.Wrapf(err, "failed to delete registrations for server %s", id)
 			}
+			for _, prefix := range prefixes {
+				if err := recordRegistrationChange(tx, prefix); err != nil {
+					return err
+				}
+			}
 		}
 		// Delete all downtimes associated with this server
 		if err := tx.Where("server_id = ?", id).Delete(&server_structs.Downtime{}).Error; err != nil {
 			return errors.Wrapf(err, "failed to delete downtimes for server %s", id)
 		}
-		return nil
+		return recordServerChange(tx, id)
 	})
 }
 
@@ -69,6 +69,9 @@ func setupMockRegistryDB(t *testing.T) {
 		&server_structs.Contact{},
 		&server_structs.Endpoint{},
 		&server_structs.Downtime{},
+		&server_structs.RegistrationAdmin{},
+		&server_structs.RegistrationTransfer{},
+		&server_structs.RegistrationAuditEntry{},
 		&database.User{},
 		&database.Group{},
 		&database.GroupMember{},
@@ -85,6 +88,9 @@ func resetMockRegistryDB(t *testing.T) {
 		"contact":   &server_structs.Contact{},
 		"endpoint":  &server_structs.Endpoint{},
 		"downtimes": &server_structs.Downtime{},
+		"admins":    &server_structs.RegistrationAdmin{},
+		"transfers": &server_structs.RegistrationTransfer{},
+		"audit":     &server_structs.RegistrationAuditEntry{},
 	}
 
 	for name, model := range tablesToClear {
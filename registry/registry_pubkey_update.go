@@ -114,6 +114,22 @@ func compareJwks(jwks1, jwks2 jwk.Set) bool {
 	return true
 }
 
+// Check that an update of a namespace's public keys doesn't drop the key the server is
+// currently signing with. During an issuer key rotation both the previous and the new key
+// are published until the server retires the previous one; a key set that removes the
+// active key would invalidate every token the server issues.
+func checkActiveKeyRetained(activeKey jwk.Key, registered, incoming jwk.Set) error {
+	if _, isRegistered := registered.LookupKeyID(activeKey.KeyID()); !isRegistered {
+		return nil
+	}
+	if _, isIncoming := incoming.LookupKeyID(activeKey.KeyID()); !isIncoming {
+		return badRequestError{
+			Message: fmt.Sprintf("The updated public keys don't include the key %s the server signs with; retire a key only after the server has switched to another one", activeKey.KeyID()),
+		}
+	}
+	return nil
+}
+
 // Update the public key of registered prefix(es) if the http request passed client and server verification for nonce.
 // It returns the response data, and an error if any
 func updateNsKeySignChallengeCommit(data *RegisteredPrefixUpdate) (map[string]interface{}, error) {
@@ -208,6 +224,10 @@ func updateNsKeySignChallengeCommit(data *RegisteredPrefixUpdate) (map[string]in
 				return nil, errors.Wrap(err, "Failed to parse the client's public key(s) as JWKS")
 			}
 
+			if err = checkActiveKeyRetained(key, registryDbKeySet, clientJWKS); err != nil {
+				return nil, err
+			}
+
 			// Perform the update action when the latest keys in the origin are different from the registered ones
 			if compareJwks(clientJWKS, registryDbKeySet) {
 				returnMsg := map[string]interface{}{
@@ -0,0 +1,793 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package registry
+
+// Replication between registry instances.
+//
+// Every change to a registration or a server record is described by a
+// registryChange holding the complete state of the record afterwards (or
+// nothing, if it was deleted), signed with the issuer key of the registry
+// where the change was made and stored in the registry_change_log table.
+// Each registry periodically pulls the change logs of its peers and applies
+// every change newer than what it already has for the record.  Changes are
+// ordered by their version (a timestamp which never goes backwards for a
+// record) with the URL of the originating registry breaking ties, so all
+// registries end up with the same records regardless of the order in which
+// they see the changes.  Applied changes are added to the local change log
+// as-is, letting them propagate to registries which don't pull from the
+// originating one directly.
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jellydator/ttlcache/v3"
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/lestrrat-go/jwx/v2/jws"
+	"github.com/lestrrat-go/jwx/v2/jwt"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/pelicanplatform/pelican/config"
+	"github.com/pelicanplatform/pelican/database"
+	"github.com/pelicanplatform/pelican/metrics"
+	"github.com/pelicanplatform/pelican/param"
+	"github.com/pelicanplatform/pelican/server_structs"
+	"github.com/pelicanplatform/pelican/token"
+	"github.com/pelicanplatform/pelican/token_scopes"
+)
+
+const (
+	changeEntityRegistration = "registration"
+	changeEntityServer       = "server"
+
+	// Maximum number of changes returned by a single change log request
+	changeLogBatchSize = 500
+)
+
+type (
+	// registryChange is the state of a registration (keyed by prefix) or a
+	// server (keyed by ID) after a change made at the registry Origin.  If
+	// the record was deleted, both Registration and Server are nil.
+	registryChange struct {
+		Origin       string                  `json:"origin"`
+		Version      int64                   `json:"version"`
+		Entity       string                  `json:"entity"`
+		Key          string                  `json:"key"`
+		Registration *replicatedRegistration `json:"registration,omitempty"`
+		Server       *server_structs.Server  `json:"server,omitempty"`
+	}
+
+	// replicatedRegistration is a registration without its local ID, along
+	// with the ID of the server it belongs to, if any, and its co-admins.
+	replicatedRegistration struct {
+		server_structs.Registration
+		ServerID string                             `json:"server_id,omitempty"`
+		Admins   []server_structs.RegistrationAdmin `json:"admins,omitempty"`
+	}
+
+	changeLogEntry struct {
+		Seq          int64 `gorm:"primaryKey;autoIncrement"`
+		Entity       string
+		EntityKey    string
+		SignedChange string
+	}
+
+	entityVersion struct {
+		Entity    string `gorm:"primaryKey"`
+		EntityKey string `gorm:"primaryKey"`
+		Version   int64
+		Origin    string
+		Deleted   bool
+	}
+
+	replicationPeer struct {
+		Peer       string `gorm:"primaryKey"`
+		LastSeq    int64
+		CaughtUpAt *time.Time
+	}
+
+	changeLogRequest struct {
+		Since int64 `json:"since"`
+	}
+
+	changeLogItem struct {
+		Seq    int64  `json:"seq"`
+		Change string `json:"change"`
+	}
+
+	changeLogResponse struct {
+		Changes   []changeLogItem `json:"changes"`
+		LatestSeq int64           `json:"latest_seq"`
+	}
+)
+
+var (
+	peerJWKSCache = ttlcache.New(
+		ttlcache.WithTTL[string, jwk.Set](15*time.Minute),
+		ttlcache.WithDisableTouchOnHit[string, jwk.Set](),
+	)
+
+	// When replication started, used as the lag of peers this registry has
+	// never caught up with
+	replicationStart time.Time
+)
+
+func (changeLogEntry) TableName() string {
+	return "registry_change_log"
+}
+
+func (entityVersion) TableName() string {
+	return "registry_entity_versions"
+}
+
+func (replicationPeer) TableName() string {
+	return "registry_replication_peers"
+}
+
+// replicationEnabled reports whether this registry replicates with any peer
+func replicationEnabled() bool {
+	return len(param.Registry_ReplicationPeers.GetStringSlice()) > 0
+}
+
+// replicationOrigin returns the URL identifying this registry to its peers
+func replicationOrigin() string {
+	return strings.TrimSuffix(param.Server_ExternalWebUrl.GetString(), "/")
+}
+
+func replicationPeers() []string {
+	peers := []string{}
+	for _, peer := range param.Registry_ReplicationPeers.GetStringSlice() {
+		peers = append(peers, strings.TrimSuffix(peer, "/"))
+	}
+	return peers
+}
+
+func isReplicationPeer(registryUrl string) bool {
+	return slices.Contains(replicationPeers(), strings.TrimSuffix(registryUrl, "/"))
+}
+
+// changeIsNewer reports whether the change (version, origin) supersedes the
+// change (otherVersion, otherOrigin).
+func changeIsNewer(version int64, origin string, otherVersion int64, otherOrigin string) bool {
+	if version != otherVersion {
+		return version > otherVersion
+	}
+	return origin > otherOrigin
+}
+
+func getEntityVersion(tx *gorm.DB, entity, key string) (*entityVersion, error) {
+	var versions []entityVersion
+	if err := tx.Where("entity = ? AND entity_key = ?", entity, key).Limit(1).Find(&versions).Error; err != nil {
+		return nil, errors.Wrapf(err, "failed to get the version of %s %s", entity, key)
+	}
+	if len(versions) == 0 {
+		return nil, nil
+	}
+	return &versions[0], nil
+}
+
+func signChange(change *registryChange, key jwk.Key) (string, error) {
+	payload, err := json.Marshal(change)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal registry change")
+	}
+	signed, err := jws.Sign(payload, jws.WithKey(jwa.ES256, key))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to sign registry change")
+	}
+	return string(signed), nil
+}
+
+// storeChange records the version of the changed record and replaces any
+// older change to it in the change log.
+func storeChange(tx *gorm.DB, change *registryChange, signed string) error {
+	version := entityVersion{
+		Entity:    change.Entity,
+		EntityKey: change.Key,
+		Version:   change.Version,
+		Origin:    change.Origin,
+		Deleted:   change.Registration == nil && change.Server == nil,
+	}
+	if err := tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(&version).Error; err != nil {
+		return errors.Wrapf(err, "failed to store the version of %s %s", change.Entity, change.Key)
+	}
+	if err := tx.Where("entity = ? AND entity_key = ?", change.Entity, change.Key).Delete(&changeLogEntry{}).Error; err != nil {
+		return errors.Wrapf(err, "failed to remove the previous change of %s %s", change.Entity, change.Key)
+	}
+	entry := changeLogEntry{Entity: change.Entity, EntityKey: change.Key, SignedChange: signed}
+	if err := tx.Create(&entry).Error; err != nil {
+		return errors.Wrapf(err, "failed to add the change of %s %s to the change log", change.Entity, change.Key)
+	}
+	return nil
+}
+
+// recordLocalChange signs a change made at this registry and adds it to the
+// change log.  Its version is the current time, unless the record already
+// has a later version, e.g. from a peer with a clock ahead of ours.
+func recordLocalChange(tx *gorm.DB, change *registryChange) error {
+	current, err := getEntityVersion(tx, change.Entity, change.Key)
+	if err != nil {
+		return err
+	}
+	change.Origin = replicationOrigin()
+	change.Version = time.Now().UnixNano()
+	if current != nil && current.Version >= change.Version {
+		change.Version = current.Version + 1
+	}
+	key, err := config.GetIssuerPrivateJWK()
+	if err != nil {
+		return errors.Wrap(err, "failed to load the issuer key to sign registry changes")
+	}
+	signed, err := signChange(change, key)
+	if err != nil {
+		return err
+	}
+	return storeChange(tx, change, signed)
+}
+
+// recordRegistrationChange adds the current state of the registration for
+// prefix to the change log if replication is enabled.  It must be called in
+// the transaction changing the registration.
+func recordRegistrationChange(tx *gorm.DB, prefix string) error {
+	if !replicationEnabled() {
+		return nil
+	}
+	change := registryChange{Entity: changeEntityRegistration, Key: prefix}
+	var registrations []server_structs.Registration
+	if err := tx.Where("prefix = ?", prefix).Limit(1).Find(&registrations).Error; err != nil {
+		return errors.Wrapf(err, "failed to get registration %s", prefix)
+	}
+	if len(registrations) > 0 {
+		replicated := replicatedRegistration{Registration: registrations[0]}
+		var serverIDs []string
+		if err := tx.Model(&server_structs.Service{}).Where("registration_id = ?", replicated.ID).Limit(1).Pluck("server_id", &serverIDs).Error; err != nil {
+			return errors.Wrapf(err, "failed to get the server of registration %s", prefix)
+		}
+		if len(serverIDs) > 0 {
+			replicated.ServerID = serverIDs[0]
+		}
+		admins, err := getRegistrationAdmins(tx, replicated.ID)
+		if err != nil {
+			return err
+		}
+		for idx := range admins {
+			admins[idx].RegistrationID = 0
+		}
+		replicated.Admins = admins
+		replicated.ID = 0
+		change.Registration = &replicated
+	}
+	return recordLocalChange(tx, &change)
+}
+
+// recordServerChange adds the current state of the server with the given ID
+// to the change log if replication is enabled.  It must be called in the
+// transaction changing the server.
+func recordServerChange(tx *gorm.DB, serverID string) error {
+	if !replicationEnabled() || serverID == "" {
+		return nil
+	}
+	change := registryChange{Entity: changeEntityServer, Key: serverID}
+	var servers []server_structs.Server
+	if err := tx.Where("id = ?", serverID).Limit(1).Find(&servers).Error; err != nil {
+		return errors.Wrapf(err, "failed to get server %s", serverID)
+	}
+	if len(servers) > 0 {
+		change.Server = &servers[0]
+	}
+	return recordLocalChange(tx, &change)
+}
+
+// recordUnversionedRecords adds the registrations and servers which have
+// never been part of a change, e.g. because they were created before
+// replication was enabled, to the change log.
+func recordUnversionedRecords() error {
+	return database.ServerDatabase.Transaction(func(tx *gorm.DB) error {
+		var serverIDs []string
+		if err := tx.Model(&server_structs.Server{}).
+			Where("NOT EXISTS (SELECT 1 FROM registry_entity_versions v WHERE v.entity = ? AND v.entity_key = servers.id)", changeEntityServer).
+			Pluck("id", &serverIDs).Error; err != nil {
+			return errors.Wrap(err, "failed to find servers missing from the change log")
+		}
+		for _, serverID := range serverIDs {
+			if err := recordServerChange(tx, serverID); err != nil {
+				return err
+			}
+		}
+		var prefixes []string
+		if err := tx.Model(&server_structs.Registration{}).
+			Where("NOT EXISTS (SELECT 1 FROM registry_entity_versions v WHERE v.entity = ? AND v.entity_key = registrations.prefix)", changeEntityRegistration).
+			Pluck("prefix", &prefixes).Error; err != nil {
+			return errors.Wrap(err, "failed to find registrations missing from the change log")
+		}
+		for _, prefix := range prefixes {
+			if err := recordRegistrationChange(tx, prefix); err != nil {
+				return err
+			}
+		}
+		if len(serverIDs)+len(prefixes) > 0 {
+			log.Infof("Added %d existing server(s) and %d existing registration(s) to the registry change log", len(serverIDs), len(prefixes))
+		}
+		return nil
+	})
+}
+
+// getPeerJWKS returns the public keys of a peer registry, as published by its
+// issuer.  If refresh is set, cached keys are ignored.
+func getPeerJWKS(peer string, refresh bool) (jwk.Set, error) {
+	if !refresh {
+		if item := peerJWKSCache.Get(peer); item != nil {
+			return item.Value(), nil
+		}
+	}
+	if peer == replicationOrigin() {
+		keys, err := config.GetIssuerPublicJWKS()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load the issuer's public keys")
+		}
+		return keys, nil
+	}
+	keys, err := token.GetJWKSFromIssUrl(peer)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the public keys of registry %s", peer)
+	}
+	peerJWKSCache.Set(peer, *keys, ttlcache.DefaultTTL)
+	return *keys, nil
+}
+
+// verifyChange checks that a change from the change log of a peer was signed
+// by the registry where it was made, which must be this registry or one of
+// its peers.
+func verifyChange(signed string) (*registryChange, error) {
+	msg, err := jws.Parse([]byte(signed))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse signed change")
+	}
+	change := &registryChange{}
+	if err := json.Unmarshal(msg.Payload(), change); err != nil {
+		return nil, errors.Wrap(err, "failed to parse change")
+	}
+	if change.Origin != replicationOrigin() && !isReplicationPeer(change.Origin) {
+		return nil, errors.Errorf("change was made at %q, which is not a replication peer", change.Origin)
+	}
+	if change.Entity != changeEntityRegistration && change.Entity != changeEntityServer {
+		return nil, errors.Errorf("change is for an unknown kind of record %q", change.Entity)
+	}
+	if change.Key == "" {
+		return nil, errors.New("change is missing the key of its record")
+	}
+
+	verify := func(keys jwk.Set) error {
+		_, err := jws.Verify([]byte(signed), jws.WithKeySet(keys, jws.WithRequireKid(false), jws.WithInferAlgorithmFromKey(true)))
+		return err
+	}
+	keys, err := getPeerJWKS(change.Origin, false)
+	if err != nil {
+		return nil, err
+	}
+	if err = verify(keys); err != nil {
+		// The registry may have rotated its keys since they were cached
+		if keys, err = getPeerJWKS(change.Origin, true); err != nil {
+			return nil, err
+		}
+		if err = verify(keys); err != nil {
+			return nil, errors.Wrapf(err, "signature of the change does not match the keys of %s", change.Origin)
+		}
+	}
+	return change, nil
+}
+
+// ensureServer makes sure a server with the given ID exists.  If overwrite is
+// set, an existing server is updated to match.  As server names are unique,
+// a server whose name is already taken by another one is only stored if its
+// ID sorts before the other's, in which case the other server is removed.
+// Returns whether the server exists afterwards.
+func ensureServer(tx *gorm.DB, server server_structs.Server, overwrite bool) (bool, error) {
+	var existing []server_structs.Server
+	if err := tx.Where("id = ?", server.ID).Limit(1).Find(&existing).Error; err != nil {
+		return false, errors.Wrapf(err, "failed to get server %s", server.ID)
+	}
+	if len(existing) > 0 && !overwrite {
+		return true, nil
+	}
+
+	var others []server_structs.Server
+	if err := tx.Where("name = ? AND id <> ?", server.Name, server.ID).Limit(1).Find(&others).Error; err != nil {
+		return false, errors.Wrapf(err, "failed to check for servers named %s", server.Name)
+	}
+	if len(others) > 0 {
+		if others[0].ID < server.ID {
+			log.Warningf("Not replicating server %s: its name %q is taken by server %s", server.ID, server.Name, others[0].ID)
+			return len(existing) > 0, nil
+		}
+		log.Warningf("Replacing server %s with server %s from a peer registry as both are named %q", others[0].ID, server.ID, server.Name)
+		if err := tx.Where("id = ?", others[0].ID).Delete(&server_structs.Server{}).Error; err != nil {
+			return false, errors.Wrapf(err, "failed to delete server %s", others[0].ID)
+		}
+	}
+
+	if len(existing) > 0 {
+		if err := tx.Save(&server).Error; err != nil {
+			return false, errors.Wrapf(err, "failed to update server %s", server.ID)
+		}
+	} else if err := tx.Create(&server).Error; err != nil {
+		return false, errors.Wrapf(err, "failed to create server %s", server.ID)
+	}
+	return true, nil
+}
+
+func applyRegistrationChange(tx *gorm.DB, change *registryChange) error {
+	if change.Registration == nil {
+		// The services of the registration are removed by the foreign key
+		return tx.Where("prefix = ?", change.Key).Delete(&server_structs.Registration{}).Error
+	}
+
+	registration := change.Registration.Registration
+	registration.Prefix = change.Key
+	var existingIDs []int
+	if err := tx.Model(&server_structs.Registration{}).Where("prefix = ?", change.Key).Limit(1).Pluck("id", &existingIDs).Error; err != nil {
+		return errors.Wrapf(err, "failed to get registration %s", change.Key)
+	}
+	if len(existingIDs) > 0 {
+		registration.ID = existingIDs[0]
+		if err := tx.Save(&registration).Error; err != nil {
+			return errors.Wrapf(err, "failed to update registration %s", change.Key)
+		}
+	} else {
+		registration.ID = 0
+		if err := tx.Create(&registration).Error; err != nil {
+			return errors.Wrapf(err, "failed to create registration %s", change.Key)
+		}
+	}
+
+	if err := tx.Where("registration_id = ?", registration.ID).Delete(&server_structs.RegistrationAdmin{}).Error; err != nil {
+		return errors.Wrapf(err, "failed to remove the admins of registration %s", change.Key)
+	}
+	for _, admin := range change.Registration.Admins {
+		admin.RegistrationID = registration.ID
+		if err := tx.Create(&admin).Error; err != nil {
+			return errors.Wrapf(err, "failed to add admin %s to registration %s", admin.UserID, change.Key)
+		}
+	}
+
+	if err := tx.Where("registration_id = ?", registration.ID).Delete(&server_structs.Service{}).Error; err != nil {
+		return errors.Wrapf(err, "failed to unlink registration %s from its server", change.Key)
+	}
+	serverID := change.Registration.ServerID
+	if serverID == "" {
+		return nil
+	}
+	// The change of the server itself may not have arrived yet; create it
+	// the same way AddRegistration would until it does.
+	exists, err := ensureServer(tx, server_structs.Server{
+		ID:       serverID,
+		Name:     registration.AdminMetadata.SiteName,
+		IsOrigin: server_structs.IsOriginNS(registration.Prefix),
+		IsCache:  server_structs.IsCacheNS(registration.Prefix),
+	}, false)
+	if err != nil || !exists {
+		return err
+	}
+	service := server_structs.Service{ServerID: serverID, RegistrationID: registration.ID}
+	if err := tx.Create(&service).Error; err != nil {
+		return errors.Wrapf(err, "failed to link registration %s to server %s", change.Key, serverID)
+	}
+	return nil
+}
+
+func applyServerChange(tx *gorm.DB, change *registryChange) error {
+	if change.Server == nil {
+		// Registrations of the server are deleted by changes of their own
+		if err := tx.Where("id = ?", change.Key).Delete(&server_structs.Server{}).Error; err != nil {
+			return errors.Wrapf(err, "failed to delete server %s", change.Key)
+		}
+		if err := tx.Where("server_id = ?", change.Key).Delete(&server_structs.Downtime{}).Error; err != nil {
+			return errors.Wrapf(err, "failed to delete downtimes for server %s", change.Key)
+		}
+		return nil
+	}
+	server := *change.Server
+	server.ID = change.Key
+	_, err := ensureServer(tx, server, true)
+	return err
+}
+
+// applyChange applies a verified change from a peer unless the record
+// already reflects the same or a newer change.  Returns whether the change
+// was applied.
+func applyChange(tx *gorm.DB, change *registryChange, signed string) (bool, error) {
+	current, err := getEntityVersion(tx, change.Entity, change.Key)
+	if err != nil {
+		return false, err
+	}
+	if current != nil && !changeIsNewer(change.Version, change.Origin, current.Version, current.Origin) {
+		return false, nil
+	}
+	switch change.Entity {
+	case changeEntityRegistration:
+		err = applyRegistrationChange(tx, change)
+	case changeEntityServer:
+		err = applyServerChange(tx, change)
+	default:
+		err = errors.Errorf("unknown kind of record %q", change.Entity)
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, storeChange(tx, change, signed)
+}
+
+// getReplicationToken creates a token for reading the change log of a peer
+func getReplicationToken(peer string) (string, error) {
+	tokenCfg := token.NewWLCGToken()
+	tokenCfg.Lifetime = 5 * time.Minute
+	tokenCfg.Issuer = replicationOrigin()
+	tokenCfg.Subject = replicationOrigin()
+	aud, err := token.GetWLCGAudience(peer)
+	if err != nil {
+		return "", err
+	}
+	tokenCfg.AddAudiences(aud)
+	tokenCfg.AddScopes(token_scopes.Registry_Replicate)
+	return tokenCfg.CreateToken()
+}
+
+// verifyReplicationToken checks that the request comes from a peer registry
+func verifyReplicationToken(ctx *gin.Context) error {
+	strToken, found := strings.CutPrefix(ctx.GetHeader("Authorization"), "Bearer ")
+	if !found || strToken == "" {
+		return errors.New("no bearer token in the request")
+	}
+	unverified, err := jwt.Parse([]byte(strToken), jwt.WithVerify(false))
+	if err != nil {
+		return errors.Wrap(err, "invalid JWT")
+	}
+	peer := unverified.Issuer()
+	if !isReplicationPeer(peer) {
+		return errors.Errorf("token issuer %q is not a replication peer", peer)
+	}
+	keys, err := getPeerJWKS(strings.TrimSuffix(peer, "/"), false)
+	if err != nil {
+		return err
+	}
+	aud, err := token.GetWLCGAudience(param.Server_ExternalWebUrl.GetString())
+	if err != nil {
+		return err
+	}
+	scopeValidator := token_scopes.CreateScopeValidator([]token_scopes.TokenScope{token_scopes.Registry_Replicate}, false)
+	if _, err := jwt.Parse([]byte(strToken), jwt.WithKeySet(keys), jwt.WithValidate(true), jwt.WithAudience(aud), jwt.WithValidator(scopeValidator)); err != nil {
+		return errors.Wrap(err, "failed to verify token")
+	}
+	return nil
+}
+
+// Serve the changes of the change log after the requested sequence number
+func getChangeLogHandler(ctx *gin.Context) {
+	if !replicationEnabled() {
+		ctx.JSON(http.StatusNotFound, server_structs.SimpleApiResp{
+			Status: server_structs.RespFailed,
+			Msg:    "Replication is not enabled at this registry",
+		})
+		return
+	}
+	if err := verifyReplicationToken(ctx); err != nil {
+		log.Debugln("Rejected request for the registry change log:", err)
+		ctx.JSON(http.StatusForbidden, server_structs.SimpleApiResp{
+			Status: server_structs.RespFailed,
+			Msg:    fmt.Sprint("Failed to verify the token: ", err),
+		})
+		return
+	}
+	req := changeLogRequest{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, server_structs.SimpleApiResp{
+			Status: server_structs.RespFailed,
+			Msg:    fmt.Sprint("Invalid change log request: ", err),
+		})
+		return
+	}
+
+	res := changeLogResponse{Changes: []changeLogItem{}}
+	err := database.ServerDatabase.Transaction(func(tx *gorm.DB) error {
+		var entries []changeLogEntry
+		if err := tx.Where("seq > ?", req.Since).Order("seq ASC").Limit(changeLogBatchSize).Find(&entries).Error; err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			res.Changes = append(res.Changes, changeLogItem{Seq: entry.Seq, Change: entry.SignedChange})
+		}
+		return tx.Model(&changeLogEntry{}).Select("COALESCE(MAX(seq), 0)").Scan(&res.LatestSeq).Error
+	})
+	if err != nil {
+		log.Errorln("Failed to read the registry change log:", err)
+		ctx.JSON(http.StatusInternalServerError, server_structs.SimpleApiResp{
+			Status: server_structs.RespFailed,
+			Msg:    "Failed to read the change log",
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+// fetchChanges requests the changes after since from the change log of peer
+func fetchChanges(ctx context.Context, peer string, since int64) (*changeLogResponse, error) {
+	tok, err := getReplicationToken(peer)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create a token for the peer")
+	}
+	changesUrl, err := url.JoinPath(peer, "api", "v1.0", "registry", "replication", "changes")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to construct the change log URL")
+	}
+	body, err := json.Marshal(changeLogRequest{Since: since})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, changesUrl, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+tok)
+	resp, err := config.GetClient().Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to request the change log at %s", changesUrl)
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the change log")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("change log request returned status %d: %s", resp.StatusCode, respBody)
+	}
+	res := &changeLogResponse{}
+	if err := json.Unmarshal(respBody, res); err != nil {
+		return nil, errors.Wrap(err, "failed to parse the change log")
+	}
+	return res, nil
+}
+
+func getReplicationPeer(peer string) (*replicationPeer, error) {
+	var peers []replicationPeer
+	if err := database.ServerDatabase.Where("peer = ?", peer).Limit(1).Find(&peers).Error; err != nil {
+		return nil, errors.Wrapf(err, "failed to get the replication state of %s", peer)
+	}
+	if len(peers) == 0 {
+		return &replicationPeer{Peer: peer}, nil
+	}
+	return &peers[0], nil
+}
+
+// pullChanges applies the changes a peer made since the last pull
+func pullChanges(ctx context.Context, peer string) error {
+	state, err := getReplicationPeer(peer)
+	if err != nil {
+		return err
+	}
+	for {
+		requestedAt := time.Now()
+		res, err := fetchChanges(ctx, peer, state.LastSeq)
+		if err != nil {
+			return err
+		}
+		if res.LatestSeq < state.LastSeq {
+			// The peer lost its change log, e.g. it was restored from a backup
+			log.Warningf("Change log of registry %s restarted at %d after %d; pulling all of it again", peer, res.LatestSeq, state.LastSeq)
+			state.LastSeq = 0
+			continue
+		}
+
+		applied := 0
+		for _, item := range res.Changes {
+			change, verifyErr := verifyChange(item.Change)
+			err = database.ServerDatabase.Transaction(func(tx *gorm.DB) error {
+				if verifyErr != nil {
+					log.Warningf("Skipping change %d from registry %s: %v", item.Seq, peer, verifyErr)
+				} else if ok, err := applyChange(tx, change, item.Change); err != nil {
+					return errors.Wrapf(err, "failed to apply change %d from registry %s", item.Seq, peer)
+				} else if ok {
+					applied++
+				}
+				state.LastSeq = item.Seq
+				return tx.Save(state).Error
+			})
+			if err != nil {
+				return err
+			}
+		}
+		metrics.PelicanRegistryReplicationChanges.WithLabelValues(peer).Add(float64(applied))
+
+		if len(res.Changes) == 0 || state.LastSeq >= res.LatestSeq {
+			state.CaughtUpAt = &requestedAt
+			return database.ServerDatabase.Save(state).Error
+		}
+	}
+}
+
+// updateReplicationLag sets the replication lag metric of each peer to the
+// time since this registry last had all of the peer's changes.
+func updateReplicationLag(peers []string) {
+	for _, peer := range peers {
+		state, err := getReplicationPeer(peer)
+		if err != nil {
+			log.Warningln("Failed to update the replication lag metric:", err)
+			continue
+		}
+		caughtUpAt := replicationStart
+		if state.CaughtUpAt != nil && state.CaughtUpAt.After(caughtUpAt) {
+			caughtUpAt = *state.CaughtUpAt
+		}
+		metrics.PelicanRegistryReplicationLag.WithLabelValues(peer).Set(time.Since(caughtUpAt).Seconds())
+	}
+}
+
+// LaunchRegistryReplication starts pulling the change logs of the registries
+// in Registry.ReplicationPeers, if any.
+func LaunchRegistryReplication(ctx context.Context, egrp *errgroup.Group) error {
+	peers := replicationPeers()
+	if len(peers) == 0 {
+		return nil
+	}
+	if replicationOrigin() == "" {
+		return errors.Errorf("%s must be set to replicate with other registries", param.Server_ExternalWebUrl.GetName())
+	}
+	if slices.Contains(peers, replicationOrigin()) {
+		return errors.Errorf("%s must not contain this registry's own URL %s", param.Registry_ReplicationPeers.GetName(), replicationOrigin())
+	}
+	if err := recordUnversionedRecords(); err != nil {
+		return err
+	}
+	replicationStart = time.Now()
+	log.Infof("Replicating registrations with %d peer registries: %s", len(peers), strings.Join(peers, ", "))
+
+	egrp.Go(func() error {
+		ticker := time.NewTicker(param.Registry_ReplicationInterval.GetDuration())
+		defer ticker.Stop()
+		for {
+			for _, peer := range peers {
+				if err := pullChanges(ctx, peer); err != nil {
+					log.Warningf("Failed to replicate changes from registry %s: %v", peer, err)
+				}
+			}
+			updateReplicationLag(peers)
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+			}
+		}
+	})
+	return nil
+}
@@ -0,0 +1,451 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package registry
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pelicanplatform/pelican/config"
+	"github.com/pelicanplatform/pelican/database"
+	"github.com/pelicanplatform/pelican/metrics"
+	"github.com/pelicanplatform/pelican/param"
+	"github.com/pelicanplatform/pelican/server_structs"
+	"github.com/pelicanplatform/pelican/server_utils"
+	"github.com/pelicanplatform/pelican/test_utils"
+	"github.com/pelicanplatform/pelican/token"
+	"github.com/pelicanplatform/pelican/token_scopes"
+)
+
+const testRegistryUrl = "https://registry-a.example.com"
+
+// setupReplicationTest initializes a registry database with the replication
+// tables, replicating with the given peers.
+func setupReplicationTest(t *testing.T, peers ...string) {
+	t.Cleanup(test_utils.SetupTestLogging(t))
+	server_utils.ResetTestState()
+	t.Cleanup(server_utils.ResetTestState)
+	peerJWKSCache.DeleteAll()
+
+	tmpDir := t.TempDir()
+	require.NoError(t, param.Server_DbLocation.Set(filepath.Join(tmpDir, "registry.sqlite")))
+	require.NoError(t, param.IssuerKeysDirectory.Set(filepath.Join(tmpDir, "issuer-keys")))
+	require.NoError(t, param.ConfigDir.Set(tmpDir))
+	require.NoError(t, param.Server_ExternalWebUrl.Set(testRegistryUrl))
+	require.NoError(t, param.Registry_ReplicationPeers.Set(peers))
+	require.NoError(t, database.InitServerDatabase(server_structs.RegistryType))
+	t.Cleanup(func() {
+		assert.NoError(t, database.ShutdownDB())
+	})
+}
+
+// newPeerKey creates the issuer key of a fake peer registry
+func newPeerKey(t *testing.T) jwk.Key {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	key, err := jwk.FromRaw(priv)
+	require.NoError(t, err)
+	require.NoError(t, jwk.AssignKeyID(key))
+	require.NoError(t, key.Set(jwk.AlgorithmKey, jwa.ES256))
+	return key
+}
+
+// startPeerRegistry serves the issuer metadata of a fake peer registry and the
+// changes returned by getChanges as its change log.
+func startPeerRegistry(t *testing.T, key jwk.Key, getChanges func(since int64) changeLogResponse) *httptest.Server {
+	pubKey, err := key.PublicKey()
+	require.NoError(t, err)
+	jwks := jwk.NewSet()
+	require.NoError(t, jwks.AddKey(pubKey))
+
+	var svr *httptest.Server
+	svr = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			_ = json.NewEncoder(w).Encode(map[string]string{"jwks_uri": svr.URL + "/.well-known/issuer.jwks"})
+		case "/.well-known/issuer.jwks":
+			_ = json.NewEncoder(w).Encode(jwks)
+		case "/api/v1.0/registry/replication/changes":
+			if r.Header.Get("Authorization") == "" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			req := changeLogRequest{}
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(getChanges(req.Since))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(svr.Close)
+	return svr
+}
+
+func signTestChange(t *testing.T, change registryChange, key jwk.Key) string {
+	signed, err := signChange(&change, key)
+	require.NoError(t, err)
+	return signed
+}
+
+func testRegistrationChange(origin string, version int64, prefix, pubkey, serverID string) registryChange {
+	return registryChange{
+		Origin:  origin,
+		Version: version,
+		Entity:  changeEntityRegistration,
+		Key:     prefix,
+		Registration: &replicatedRegistration{
+			Registration: server_structs.Registration{
+				Prefix: prefix,
+				Pubkey: pubkey,
+				AdminMetadata: server_structs.AdminMetadata{
+					SiteName: "site-" + serverID,
+					Status:   server_structs.RegApproved,
+				},
+			},
+			ServerID: serverID,
+		},
+	}
+}
+
+func testServerChange(origin string, version int64, id, name string) registryChange {
+	return registryChange{
+		Origin:  origin,
+		Version: version,
+		Entity:  changeEntityServer,
+		Key:     id,
+		Server:  &server_structs.Server{ID: id, Name: name, IsOrigin: true},
+	}
+}
+
+// replicaState summarizes the replicated records of the registry database
+func replicaState(t *testing.T) []string {
+	state := []string{}
+	var registrations []server_structs.Registration
+	require.NoError(t, database.ServerDatabase.Find(&registrations).Error)
+	for _, reg := range registrations {
+		var serverIDs []string
+		require.NoError(t, database.ServerDatabase.Model(&server_structs.Service{}).Where("registration_id = ?", reg.ID).Pluck("server_id", &serverIDs).Error)
+		state = append(state, fmt.Sprintf("registration %s pubkey=%s servers=%v", reg.Prefix, reg.Pubkey, serverIDs))
+	}
+	var servers []server_structs.Server
+	require.NoError(t, database.ServerDatabase.Find(&servers).Error)
+	for _, server := range servers {
+		state = append(state, fmt.Sprintf("server %s name=%s origin=%t", server.ID, server.Name, server.IsOrigin))
+	}
+	sort.Strings(state)
+	return state
+}
+
+func TestRecordRegistryChanges(t *testing.T) {
+	setupReplicationTest(t, "https://registry-b.example.com")
+
+	getLog := func() map[string]*registryChange {
+		var entries []changeLogEntry
+		require.NoError(t, database.ServerDatabase.Order("seq").Find(&entries).Error)
+		changes := map[string]*registryChange{}
+		for _, entry := range entries {
+			change, err := verifyChange(entry.SignedChange)
+			require.NoError(t, err)
+			assert.Equal(t, testRegistryUrl, change.Origin)
+			changes[change.Entity+" "+change.Key] = change
+		}
+		assert.Len(t, changes, len(entries), "the change log should only hold the latest change of each record")
+		return changes
+	}
+
+	pubkey := `{"keys":[]}`
+	reg := server_structs.Registration{
+		Prefix:        "/origins/origin.example.com",
+		Pubkey:        pubkey,
+		AdminMetadata: server_structs.AdminMetadata{SiteName: "origin-site"},
+		CustomFields:  map[string]interface{}{"server_id": "abc1234"},
+	}
+	require.NoError(t, AddRegistration(&reg))
+
+	changes := getLog()
+	require.Contains(t, changes, "server abc1234")
+	require.NotNil(t, changes["server abc1234"].Server)
+	assert.Equal(t, "origin-site", changes["server abc1234"].Server.Name)
+	regChange := changes["registration /origins/origin.example.com"]
+	require.NotNil(t, regChange)
+	require.NotNil(t, regChange.Registration)
+	assert.Equal(t, "abc1234", regChange.Registration.ServerID)
+	assert.Zero(t, regChange.Registration.ID)
+	assert.Equal(t, server_structs.RegPending, regChange.Registration.AdminMetadata.Status)
+
+	require.NoError(t, updateRegistrationStatusById(reg.ID, server_structs.RegApproved, "admin"))
+	approved := getLog()["registration /origins/origin.example.com"]
+	require.NotNil(t, approved.Registration)
+	assert.Equal(t, server_structs.RegApproved, approved.Registration.AdminMetadata.Status)
+	assert.Greater(t, approved.Version, regChange.Version)
+
+	// Co-admins replicate along with the registration
+	require.NoError(t, addRegistrationAdmin(reg.ID, "helper", "admin"))
+	withAdmin := getLog()["registration /origins/origin.example.com"]
+	require.NotNil(t, withAdmin.Registration)
+	require.Len(t, withAdmin.Registration.Admins, 1)
+	assert.Equal(t, "helper", withAdmin.Registration.Admins[0].UserID)
+	assert.Zero(t, withAdmin.Registration.Admins[0].RegistrationID)
+	require.NoError(t, database.ServerDatabase.Where("1 = 1").Delete(&server_structs.RegistrationAdmin{}).Error)
+	require.NoError(t, applyRegistrationChange(database.ServerDatabase, withAdmin))
+	belongs, err := registrationBelongsToUserId(reg.ID, "helper")
+	require.NoError(t, err)
+	assert.True(t, belongs)
+
+	require.NoError(t, deleteRegistrationByID(reg.ID))
+	changes = getLog()
+	assert.Nil(t, changes["registration /origins/origin.example.com"].Registration)
+	assert.Nil(t, changes["server abc1234"].Server)
+
+	t.Run("ExistingRecords", func(t *testing.T) {
+		require.NoError(t, database.ServerDatabase.Create(&server_structs.Registration{Prefix: "/existing", Pubkey: pubkey}).Error)
+		require.NoError(t, recordUnversionedRecords())
+		require.Contains(t, getLog(), "registration /existing")
+
+		// Recorded records are left alone
+		var before int64
+		require.NoError(t, database.ServerDatabase.Model(&changeLogEntry{}).Select("MAX(seq)").Scan(&before).Error)
+		require.NoError(t, recordUnversionedRecords())
+		var after int64
+		require.NoError(t, database.ServerDatabase.Model(&changeLogEntry{}).Select("MAX(seq)").Scan(&after).Error)
+		assert.Equal(t, before, after)
+	})
+}
+
+func TestApplyChangesConverges(t *testing.T) {
+	setupReplicationTest(t, "https://registry-b.example.com", "https://registry-c.example.com")
+	key, err := config.GetIssuerPrivateJWK()
+	require.NoError(t, err)
+
+	const (
+		peerB = "https://registry-b.example.com"
+		peerC = "https://registry-c.example.com"
+	)
+	changes := []registryChange{
+		// Concurrent updates of the same registration; the one from C wins
+		// the tie on version
+		testRegistrationChange(peerB, 10, "/foo", "key-b", ""),
+		testRegistrationChange(peerC, 10, "/foo", "key-c", ""),
+		// A registration deleted after its creation stays deleted
+		testRegistrationChange(peerB, 5, "/bar", "key-b", ""),
+		{Origin: peerC, Version: 6, Entity: changeEntityRegistration, Key: "/bar"},
+		// A server registration arriving with or without its server
+		testRegistrationChange(peerB, 7, "/origins/origin.example.com", "key-b", "abc1234"),
+		testServerChange(peerB, 7, "abc1234", "origin-site"),
+		// Two servers registered with the same name at different registries
+		testServerChange(peerB, 8, "zzz9999", "duplicate-site"),
+		testServerChange(peerC, 9, "aaa0000", "duplicate-site"),
+	}
+
+	resetReplica := func() {
+		for _, table := range []string{"services", "registrations", "servers", "registry_entity_versions", "registry_change_log"} {
+			require.NoError(t, database.ServerDatabase.Exec("DELETE FROM "+table).Error)
+		}
+	}
+	applyAll := func(order []int) []string {
+		resetReplica()
+		for _, idx := range order {
+			change := changes[idx]
+			_, err := applyChange(database.ServerDatabase, &change, signTestChange(t, change, key))
+			require.NoError(t, err)
+		}
+		return replicaState(t)
+	}
+
+	forward := make([]int, len(changes))
+	backward := make([]int, len(changes))
+	for idx := range changes {
+		forward[idx] = idx
+		backward[idx] = len(changes) - 1 - idx
+	}
+	expected := applyAll(forward)
+	assert.Equal(t, []string{
+		"registration /foo pubkey=key-c servers=[]",
+		"registration /origins/origin.example.com pubkey=key-b servers=[abc1234]",
+		"server aaa0000 name=duplicate-site origin=true",
+		"server abc1234 name=origin-site origin=true",
+	}, expected)
+	assert.Equal(t, expected, applyAll(backward))
+	for i := 0; i < 20; i++ {
+		assert.Equal(t, expected, applyAll(randPerm(t, len(changes))))
+	}
+
+	// Stale changes, including ones this registry made itself, are not
+	// applied again
+	stale := testRegistrationChange(peerB, 9, "/foo", "key-old", "")
+	applied, err := applyChange(database.ServerDatabase, &stale, signTestChange(t, stale, key))
+	require.NoError(t, err)
+	assert.False(t, applied)
+	assert.Equal(t, expected, replicaState(t))
+}
+
+func randPerm(t *testing.T, n int) []int {
+	perm := make([]int, n)
+	for i := range perm {
+		perm[i] = i
+	}
+	for i := n - 1; i > 0; i-- {
+		var b [1]byte
+		_, err := rand.Read(b[:])
+		require.NoError(t, err)
+		j := int(b[0]) % (i + 1)
+		perm[i], perm[j] = perm[j], perm[i]
+	}
+	return perm
+}
+
+func TestPullChanges(t *testing.T) {
+	peerKey := newPeerKey(t)
+	otherKey := newPeerKey(t)
+	var peerUrl string
+	var changes []changeLogItem
+	peer := startPeerRegistry(t, peerKey, func(since int64) changeLogResponse {
+		res := changeLogResponse{Changes: []changeLogItem{}}
+		for _, item := range changes {
+			if item.Seq > since && len(res.Changes) < 2 {
+				res.Changes = append(res.Changes, item)
+			}
+			res.LatestSeq = item.Seq
+		}
+		return res
+	})
+	peerUrl = peer.URL
+	setupReplicationTest(t, peerUrl)
+	replicationStart = time.Now()
+
+	changes = []changeLogItem{
+		{Seq: 1, Change: signTestChange(t, testServerChange(peerUrl, 1, "abc1234", "origin-site"), peerKey)},
+		{Seq: 2, Change: signTestChange(t, testRegistrationChange(peerUrl, 1, "/origins/origin.example.com", "key-1", "abc1234"), peerKey)},
+		// Signed with a key the peer doesn't publish
+		{Seq: 3, Change: signTestChange(t, testRegistrationChange(peerUrl, 1, "/forged", "key-1", ""), otherKey)},
+		// Made at a registry which isn't a peer
+		{Seq: 5, Change: signTestChange(t, testRegistrationChange("https://evil.example.com", 1, "/evil", "key-1", ""), peerKey)},
+		{Seq: 6, Change: signTestChange(t, testRegistrationChange(peerUrl, 1, "/foo", "key-1", ""), peerKey)},
+	}
+	require.NoError(t, pullChanges(context.Background(), peerUrl))
+
+	assert.Equal(t, []string{
+		"registration /foo pubkey=key-1 servers=[]",
+		"registration /origins/origin.example.com pubkey=key-1 servers=[abc1234]",
+		"server abc1234 name=origin-site origin=true",
+	}, replicaState(t))
+	state, err := getReplicationPeer(peerUrl)
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), state.LastSeq)
+	require.NotNil(t, state.CaughtUpAt)
+
+	// Applied changes are relayed in this registry's change log
+	var relayed int64
+	require.NoError(t, database.ServerDatabase.Model(&changeLogEntry{}).Count(&relayed).Error)
+	assert.Equal(t, int64(3), relayed)
+
+	updateReplicationLag([]string{peerUrl})
+	assert.Less(t, testutil.ToFloat64(metrics.PelicanRegistryReplicationLag.WithLabelValues(peerUrl)), float64(60))
+
+	t.Run("RestartedChangeLog", func(t *testing.T) {
+		changes = []changeLogItem{
+			{Seq: 1, Change: signTestChange(t, testRegistrationChange(peerUrl, 2, "/foo", "key-2", ""), peerKey)},
+		}
+		require.NoError(t, pullChanges(context.Background(), peerUrl))
+		state, err := getReplicationPeer(peerUrl)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), state.LastSeq)
+		assert.Contains(t, replicaState(t), "registration /foo pubkey=key-2 servers=[]")
+	})
+}
+
+func TestChangeLogHandler(t *testing.T) {
+	peerKey := newPeerKey(t)
+	peer := startPeerRegistry(t, peerKey, func(int64) changeLogResponse { return changeLogResponse{} })
+	setupReplicationTest(t, peer.URL)
+	require.NoError(t, AddRegistration(&server_structs.Registration{
+		Prefix:        "/foo",
+		Pubkey:        `{"keys":[]}`,
+		AdminMetadata: server_structs.AdminMetadata{SiteName: "foo-site"},
+	}))
+
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	RegisterRegistryAPI(engine.Group("/"))
+
+	newToken := func(issuer string, key jwk.Key, scope token_scopes.TokenScope) string {
+		tokenCfg := token.NewWLCGToken()
+		tokenCfg.Lifetime = time.Minute
+		tokenCfg.Issuer = issuer
+		tokenCfg.Subject = issuer
+		tokenCfg.AddAudiences(testRegistryUrl)
+		tokenCfg.AddScopes(scope)
+		tok, err := tokenCfg.CreateTokenWithKey(key)
+		require.NoError(t, err)
+		return tok
+	}
+	request := func(tok string) (int, changeLogResponse) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1.0/registry/replication/changes", bytes.NewBufferString(`{"since":0}`))
+		req.Header.Set("Content-Type", "application/json")
+		if tok != "" {
+			req.Header.Set("Authorization", "Bearer "+tok)
+		}
+		recorder := httptest.NewRecorder()
+		engine.ServeHTTP(recorder, req)
+		res := changeLogResponse{}
+		if recorder.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+		}
+		return recorder.Code, res
+	}
+
+	status, res := request(newToken(peer.URL, peerKey, token_scopes.Registry_Replicate))
+	require.Equal(t, http.StatusOK, status)
+	require.Len(t, res.Changes, 1)
+	assert.Equal(t, res.Changes[0].Seq, res.LatestSeq)
+	change, err := verifyChange(res.Changes[0].Change)
+	require.NoError(t, err)
+	assert.Equal(t, "/foo", change.Key)
+
+	status, _ = request("")
+	assert.Equal(t, http.StatusForbidden, status)
+	status, _ = request(newToken(peer.URL, newPeerKey(t), token_scopes.Registry_Replicate))
+	assert.Equal(t, http.StatusForbidden, status)
+	status, _ = request(newToken(peer.URL, peerKey, token_scopes.Registry_EditRegistration))
+	assert.Equal(t, http.StatusForbidden, status)
+	status, _ = request(newToken("https://evil.example.com", peerKey, token_scopes.Registry_Replicate))
+	assert.Equal(t, http.StatusForbidden, status)
+}
@@ -0,0 +1,476 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package registry
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/pelicanplatform/pelican/database"
+	dbutils "github.com/pelicanplatform/pelican/database/utils"
+	"github.com/pelicanplatform/pelican/server_structs"
+	"github.com/pelicanplatform/pelican/web_ui"
+)
+
+type (
+	namespaceSearchRequest struct {
+		Prefix       string `form:"prefix"`
+		Institution  string `form:"institution"`
+		Status       string `form:"status"`
+		PrefixType   string `form:"prefixType"`
+		CreatedAfter string `form:"createdAfter"`
+		Sort         string `form:"sort"`
+		Order        string `form:"order"`
+		Limit        int    `form:"limit"`
+		Cursor       string `form:"cursor"`
+	}
+
+	// namespaceSearchFilter holds the validated criteria of a namespace search
+	namespaceSearchFilter struct {
+		prefix       string
+		institution  string
+		status       server_structs.RegistrationStatus
+		prefixType   prefixType
+		createdAfter time.Time
+		customFields map[string]string
+		sort         string
+		descending   bool
+		limit        int
+		cursor       *namespaceSearchCursor
+	}
+
+	// namespaceSearchCursor marks the last registration of a page. It is handed
+	// to clients as an opaque string and only valid for the same sort order.
+	namespaceSearchCursor struct {
+		Sort       string `json:"s"`
+		Descending bool   `json:"d,omitempty"`
+		Key        string `json:"k"`
+		ID         int    `json:"i"`
+	}
+
+	// registrationSearchRow is a registration along with the value of the
+	// search's sort key, which the cursor of the next page starts after
+	registrationSearchRow struct {
+		server_structs.Registration `gorm:"embedded"`
+		SortKey                     string
+	}
+
+	namespaceSearchResult struct {
+		NamespaceWOPubkey
+		CustomFields map[string]interface{} `json:"custom_fields,omitempty"`
+	}
+
+	namespaceSearchResponse struct {
+		Namespaces []namespaceSearchResult `json:"namespaces"`
+		// The number of registrations matching the filters across all pages
+		Total      int    `json:"total"`
+		NextCursor string `json:"next_cursor,omitempty"`
+	}
+)
+
+const (
+	defaultSearchLimit = 50
+	maxSearchLimit     = 1000
+
+	searchCustomFieldPrefix = "custom_fields."
+)
+
+// The keys registrations can be sorted by, as SQL expressions for the given
+// database engine. Sorting by ID needs no key besides the ID itself, which
+// breaks ties for all keys.
+var namespaceSortKeys = map[string]func(dbType dbutils.DbType) string{
+	"id":     func(dbutils.DbType) string { return "''" },
+	"prefix": func(dbutils.DbType) string { return "prefix" },
+	"site_name": func(dbType dbutils.DbType) string {
+		return "LOWER(COALESCE(" + adminMetadataField(dbType, "site_name") + ", ''))"
+	},
+	"created_at": func(dbType dbutils.DbType) string {
+		return "COALESCE(" + normalizedTime(dbType, adminMetadataField(dbType, "created_at")) + ", '')"
+	},
+	"updated_at": func(dbType dbutils.DbType) string {
+		return "COALESCE(" + normalizedTime(dbType, adminMetadataField(dbType, "updated_at")) + ", '')"
+	},
+}
+
+// The admin metadata fields a registration must have one of to be listed;
+// legacy registrations without admin metadata are left out, as in listNamespaces
+var (
+	adminMetadataTextFields = []string{"user_id", "description", "site_name", "institution", "security_contact_user_id", "status", "approver_id"}
+	adminMetadataTimeFields = []string{"approved_at", "created_at", "updated_at"}
+)
+
+// The JSON encoding of the zero time.Time
+const zeroTimeJSON = "0001-01-01T00:00:00Z"
+
+// adminMetadataField returns the SQL expression reading a field of the
+// JSON-encoded admin metadata; it is NULL where the metadata isn't JSON.
+func adminMetadataField(dbType dbutils.DbType, field string) string {
+	if dbType == dbutils.DbTypePostgres {
+		return fmt.Sprintf("(CASE WHEN admin_metadata LIKE '{%%' THEN admin_metadata::jsonb ->> '%s' END)", field)
+	}
+	return fmt.Sprintf("(CASE WHEN json_valid(admin_metadata) THEN json_extract(admin_metadata, '$.%s') END)", field)
+}
+
+// normalizedTime returns the SQL expression converting an RFC 3339 timestamp
+// to UTC text that sorts chronologically, whatever the offset it was written with.
+func normalizedTime(dbType dbutils.DbType, expr string) string {
+	if dbType == dbutils.DbTypePostgres {
+		return fmt.Sprintf(`to_char((%s)::timestamptz AT TIME ZONE 'UTC', 'YYYY-MM-DD"T"HH24:MI:SS.US')`, expr)
+	}
+	return fmt.Sprintf("strftime('%%Y-%%m-%%dT%%H:%%M:%%f', %s)", expr)
+}
+
+func encodeSearchCursor(cursor namespaceSearchCursor) string {
+	cursorBytes, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(cursorBytes)
+}
+
+func decodeSearchCursor(cursorStr string) (*namespaceSearchCursor, error) {
+	cursorBytes, err := base64.RawURLEncoding.DecodeString(cursorStr)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+	cursor := namespaceSearchCursor{}
+	if err := json.Unmarshal(cursorBytes, &cursor); err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+	return &cursor, nil
+}
+
+// Escape the wildcards of a LIKE pattern so the value is matched literally
+func escapeLikePattern(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
+
+// Check whether a registration's custom fields hold all the wanted values
+func customFieldsMatch(fields map[string]interface{}, want map[string]string) bool {
+	for name, value := range want {
+		if !customFieldMatches(fields[name], value) {
+			return false
+		}
+	}
+	return true
+}
+
+// Check whether a custom registration field holds the given value. Strings are
+// compared case-insensitively and lists match if any of their elements does.
+func customFieldMatches(value interface{}, want string) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case string:
+		return strings.EqualFold(v, want)
+	case []interface{}:
+		for _, elem := range v {
+			if customFieldMatches(elem, want) {
+				return true
+			}
+		}
+		return false
+	default:
+		return strings.EqualFold(fmt.Sprint(v), want)
+	}
+}
+
+// parseNamespaceSearch validates the query of a namespace search. Custom
+// registration fields are filtered with `custom_fields.<name>=<value>`.
+func parseNamespaceSearch(ctx *gin.Context) (*namespaceSearchFilter, error) {
+	req := namespaceSearchRequest{}
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		return nil, badRequestError{Message: fmt.Sprintf("Invalid query parameters: %v", err)}
+	}
+	filter := &namespaceSearchFilter{
+		prefix:       req.Prefix,
+		institution:  req.Institution,
+		prefixType:   prefixType(req.PrefixType),
+		sort:         req.Sort,
+		limit:        req.Limit,
+		customFields: map[string]string{},
+	}
+
+	if req.Status != "" {
+		if !server_structs.IsValidRegStatus(req.Status) {
+			return nil, badRequestError{Message: fmt.Sprintf("Invalid status %s: status must be one of 'Pending', 'Approved', 'Denied', 'Unknown'", req.Status)}
+		}
+		filter.status = server_structs.RegistrationStatus(req.Status)
+	}
+	switch filter.prefixType {
+	case "", prefixForNamespace, prefixForOrigin, prefixForCache:
+	default:
+		return nil, badRequestError{Message: fmt.Sprintf("Invalid prefix type: %s", req.PrefixType)}
+	}
+	if req.CreatedAfter != "" {
+		createdAfter, err := time.Parse(time.RFC3339, req.CreatedAfter)
+		if err != nil {
+			if createdAfter, err = time.Parse(time.DateOnly, req.CreatedAfter); err != nil {
+				return nil, badRequestError{Message: fmt.Sprintf("Invalid createdAfter %s: use an RFC 3339 timestamp or a YYYY-MM-DD date", req.CreatedAfter)}
+			}
+		}
+		filter.createdAfter = createdAfter
+	}
+
+	if filter.sort == "" {
+		filter.sort = "prefix"
+	}
+	if _, ok := namespaceSortKeys[filter.sort]; !ok {
+		return nil, badRequestError{Message: fmt.Sprintf("Invalid sort %s: registrations can be sorted by id, prefix, site_name, created_at or updated_at", req.Sort)}
+	}
+	switch strings.ToLower(req.Order) {
+	case "", "asc":
+	case "desc":
+		filter.descending = true
+	default:
+		return nil, badRequestError{Message: fmt.Sprintf("Invalid order %s: must be 'asc' or 'desc'", req.Order)}
+	}
+	if filter.limit == 0 {
+		filter.limit = defaultSearchLimit
+	} else if filter.limit < 0 || filter.limit > maxSearchLimit {
+		return nil, badRequestError{Message: fmt.Sprintf("Invalid limit %d: must be between 1 and %d", req.Limit, maxSearchLimit)}
+	}
+	if req.Cursor != "" {
+		cursor, err := decodeSearchCursor(req.Cursor)
+		if err != nil {
+			return nil, badRequestError{Message: err.Error()}
+		}
+		if cursor.Sort != filter.sort || cursor.Descending != filter.descending {
+			return nil, badRequestError{Message: "The cursor belongs to a search with a different sort order"}
+		}
+		filter.cursor = cursor
+	}
+
+	for key, values := range ctx.Request.URL.Query() {
+		if !strings.HasPrefix(key, searchCustomFieldPrefix) {
+			continue
+		}
+		name := strings.TrimPrefix(key, searchCustomFieldPrefix)
+		known := false
+		for _, field := range customRegFieldsConfigs {
+			if field.Name == name {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, badRequestError{Message: fmt.Sprintf("Unknown custom registration field %s", name)}
+		}
+		filter.customFields[name] = values[len(values)-1]
+	}
+	return filter, nil
+}
+
+// searchRegistrations returns one page of the registrations matching the
+// filter, along with the total number of matches and the cursor of the next
+// page, if any.
+//
+// Filtering, sorting and keyset pagination are done in the database, and the
+// total comes from a separate COUNT. Custom registration fields can hold
+// lists and are matched case-insensitively, so they are filtered here; pages
+// of such searches are read until enough registrations match.
+func searchRegistrations(filter *namespaceSearchFilter) ([]server_structs.Registration, int, string, error) {
+	db := database.ServerDatabase
+	dbType := dbutils.DbTypeOf(db)
+	sortKey := namespaceSortKeys[filter.sort](dbType)
+
+	filtered := func() *gorm.DB {
+		query := db.Model(&server_structs.Registration{})
+		switch filter.prefixType {
+		case prefixForCache:
+			query = query.Where("prefix LIKE ?", "/caches/%")
+		case prefixForOrigin:
+			query = query.Where("prefix LIKE ?", "/origins/%")
+		case prefixForNamespace:
+			query = query.Where("prefix NOT LIKE ? AND prefix NOT LIKE ?", "/caches/%", "/origins/%")
+		}
+		if filter.prefix != "" {
+			query = query.Where(`prefix LIKE ? ESCAPE '\'`, "%"+escapeLikePattern(filter.prefix)+"%")
+		}
+
+		hasMetadata := make([]string, 0, len(adminMetadataTextFields)+len(adminMetadataTimeFields))
+		for _, field := range adminMetadataTextFields {
+			hasMetadata = append(hasMetadata, "COALESCE("+adminMetadataField(dbType, field)+", '') <> ''")
+		}
+		for _, field := range adminMetadataTimeFields {
+			hasMetadata = append(hasMetadata, "COALESCE("+adminMetadataField(dbType, field)+", '') NOT IN ('', '"+zeroTimeJSON+"')")
+		}
+		query = query.Where(strings.Join(hasMetadata, " OR "))
+
+		status := adminMetadataField(dbType, "status")
+		if filter.status == server_structs.RegUnknown {
+			query = query.Where("COALESCE("+status+", '') IN ('', ?)", server_structs.RegUnknown)
+		} else if filter.status != "" {
+			query = query.Where(status+" = ?", filter.status)
+		}
+		if filter.institution != "" {
+			query = query.Where(adminMetadataField(dbType, "institution")+" = ?", filter.institution)
+		}
+		if !filter.createdAfter.IsZero() {
+			query = query.Where(normalizedTime(dbType, adminMetadataField(dbType, "created_at"))+" > "+normalizedTime(dbType, "?"),
+				filter.createdAfter.UTC().Format(time.RFC3339Nano))
+		}
+		return query
+	}
+
+	direction, comparison := "ASC", ">"
+	if filter.descending {
+		direction, comparison = "DESC", "<"
+	}
+	pageQuery := filtered().
+		Select("registrations.*, " + sortKey + " AS sort_key").
+		Order(sortKey + " " + direction).
+		Order("id " + direction)
+	if filter.cursor != nil {
+		pageQuery = pageQuery.Where("("+sortKey+", id) "+comparison+" (?, ?)", filter.cursor.Key, filter.cursor.ID)
+	}
+
+	page := make([]registrationSearchRow, 0, filter.limit+1)
+	var total int
+	if len(filter.customFields) == 0 {
+		if err := pageQuery.Limit(filter.limit + 1).Find(&page).Error; err != nil {
+			return nil, 0, "", errors.Wrap(err, "failed to query registrations")
+		}
+		var count int64
+		if err := filtered().Count(&count).Error; err != nil {
+			return nil, 0, "", errors.Wrap(err, "failed to count registrations")
+		}
+		total = int(count)
+	} else {
+		rows, err := pageQuery.Rows()
+		if err != nil {
+			return nil, 0, "", errors.Wrap(err, "failed to query registrations")
+		}
+		for len(page) <= filter.limit && rows.Next() {
+			row := registrationSearchRow{}
+			if err := db.ScanRows(rows, &row); err != nil {
+				rows.Close()
+				return nil, 0, "", errors.Wrap(err, "failed to read registration")
+			}
+			if customFieldsMatch(row.CustomFields, filter.customFields) {
+				page = append(page, row)
+			}
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, 0, "", errors.Wrap(err, "failed to query registrations")
+		}
+
+		candidates := []server_structs.Registration{}
+		if err := filtered().Select("id", "custom_fields").Find(&candidates).Error; err != nil {
+			return nil, 0, "", errors.Wrap(err, "failed to count registrations")
+		}
+		for _, ns := range candidates {
+			if customFieldsMatch(ns.CustomFields, filter.customFields) {
+				total++
+			}
+		}
+	}
+
+	nextCursor := ""
+	if len(page) > filter.limit {
+		page = page[:filter.limit]
+		last := page[len(page)-1]
+		nextCursor = encodeSearchCursor(namespaceSearchCursor{
+			Sort:       filter.sort,
+			Descending: filter.descending,
+			Key:        last.SortKey,
+			ID:         last.ID,
+		})
+	}
+	registrations := make([]server_structs.Registration, 0, len(page))
+	for _, row := range page {
+		registrations = append(registrations, row.Registration)
+	}
+	return registrations, total, nextCursor, nil
+}
+
+// Search namespace registrations with filters, sorting and cursor pagination.
+// As for listNamespaces, unauthenticated users only see approved registrations
+// and can't filter on custom registration fields.
+//
+// Query against prefix (substring), institution, status, prefixType,
+// createdAfter, custom_fields.<name>, sort, order, limit and cursor
+//
+// GET /namespaces/search
+func searchNamespaces(ctx *gin.Context) {
+	// Directly call GetUser as we want this endpoint to also be able to serve unauthed users
+	user, _, _, err := web_ui.GetUserGroups(ctx)
+	if err != nil {
+		log.Error("Failed to check user login status: ", err)
+		ctx.JSON(http.StatusInternalServerError, server_structs.SimpleApiResp{
+			Status: server_structs.RespFailed,
+			Msg:    "Failed to check user login status"})
+		return
+	}
+	isAuthed := user != ""
+
+	filter, err := parseNamespaceSearch(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, server_structs.SimpleApiResp{
+			Status: server_structs.RespFailed,
+			Msg:    err.Error()})
+		return
+	}
+	if !isAuthed {
+		if filter.status != "" && filter.status != server_structs.RegApproved {
+			ctx.JSON(http.StatusForbidden, server_structs.SimpleApiResp{
+				Status: server_structs.RespFailed,
+				Msg:    "You don't have permission to filter non-approved namespace registrations"})
+			return
+		}
+		if len(filter.customFields) > 0 {
+			ctx.JSON(http.StatusForbidden, server_structs.SimpleApiResp{
+				Status: server_structs.RespFailed,
+				Msg:    "You need to login to filter on custom registration fields"})
+			return
+		}
+		filter.status = server_structs.RegApproved
+	}
+
+	registrations, total, nextCursor, err := searchRegistrations(filter)
+	if err != nil {
+		log.Error("Failed to search namespaces: ", err)
+		ctx.JSON(http.StatusInternalServerError, server_structs.SimpleApiResp{
+			Status: server_structs.RespFailed,
+			Msg:    "Server encountered an error trying to search namespaces"})
+		return
+	}
+
+	resp := namespaceSearchResponse{
+		Namespaces: make([]namespaceSearchResult, 0, len(registrations)),
+		Total:      total,
+		NextCursor: nextCursor,
+	}
+	for idx, nsWOPubkey := range excludePubKey(registrations) {
+		result := namespaceSearchResult{NamespaceWOPubkey: nsWOPubkey}
+		if isAuthed {
+			result.CustomFields = registrations[idx].CustomFields
+		}
+		resp.Namespaces = append(resp.Namespaces, result)
+	}
+	ctx.JSON(http.StatusOK, resp)
+}
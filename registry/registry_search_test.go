@@ -0,0 +1,285 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package registry
+
+import (
+	"context"
+	"crypto/elliptic"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pelicanplatform/pelican/config"
+	"github.com/pelicanplatform/pelican/param"
+	"github.com/pelicanplatform/pelican/server_structs"
+	"github.com/pelicanplatform/pelican/server_utils"
+	"github.com/pelicanplatform/pelican/test_utils"
+)
+
+func TestSearchNamespaces(t *testing.T) {
+	t.Cleanup(test_utils.SetupTestLogging(t))
+	server_utils.ResetTestState()
+	ctx, cancel, egrp := test_utils.TestContext(context.Background(), t)
+	defer func() { require.NoError(t, egrp.Wait()) }()
+	defer cancel()
+
+	setupMockRegistryDB(t)
+	defer teardownMockRegistryDB(t)
+
+	require.NoError(t, param.Server_WebPort.Set(0))
+	require.NoError(t, param.Server_ExternalWebUrl.Set("https://mock-server.com"))
+	require.NoError(t, param.ConfigDir.Set(t.TempDir()))
+	require.NoError(t, param.Origin_Port.Set(0))
+	test_utils.MockFederationRoot(t, nil, nil)
+	require.NoError(t, config.InitServer(ctx, server_structs.OriginType))
+	require.NoError(t, config.GeneratePrivateKey(param.IssuerKey.GetString(), elliptic.P256(), false))
+
+	customRegFieldsConfigs = []customRegFieldsConfig{
+		{Name: "department", Type: "string"},
+		{Name: "tags", Type: "enum"},
+	}
+	t.Cleanup(func() { customRegFieldsConfigs = nil })
+
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	mockNs := func(prefix, institution string, status server_structs.RegistrationStatus, createdDays int, fields map[string]interface{}) server_structs.Registration {
+		ns := mockNamespace(prefix, "pubkey", "", server_structs.AdminMetadata{
+			Institution: institution,
+			SiteName:    "site" + prefix,
+			Status:      status,
+			CreatedAt:   base.AddDate(0, 0, createdDays),
+		})
+		ns.CustomFields = fields
+		return ns
+	}
+	require.NoError(t, insertMockDBData([]server_structs.Registration{
+		mockNs("/data/alpha", "UW", server_structs.RegApproved, 0, map[string]interface{}{"department": "Physics"}),
+		mockNs("/data/beta", "UW", server_structs.RegApproved, 1, map[string]interface{}{"department": "Chemistry", "tags": []interface{}{"hpc", "gpu"}}),
+		mockNs("/data/gamma", "UNL", server_structs.RegPending, 2, map[string]interface{}{"department": "physics"}),
+		mockNs("/origins/origin.example.org", "UW", server_structs.RegApproved, 3, nil),
+		mockNs("/caches/cache.example.org", "UNL", server_structs.RegApproved, 4, nil),
+		mockNs("/foo_bar", "UNL", server_structs.RegApproved, 5, nil),
+		{Prefix: "/legacy"},
+	}))
+
+	router := gin.New()
+	router.GET("/namespaces/search", searchNamespaces)
+
+	search := func(t *testing.T, query url.Values, authed bool) (int, namespaceSearchResponse) {
+		req, err := http.NewRequest(http.MethodGet, "/namespaces/search?"+query.Encode(), nil)
+		require.NoError(t, err)
+		if authed {
+			token, err := mockAdminToken()
+			require.NoError(t, err)
+			req.AddCookie(&http.Cookie{Name: "login", Value: token, Path: "/"})
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		resp := namespaceSearchResponse{}
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		}
+		return w.Code, resp
+	}
+	prefixes := func(resp namespaceSearchResponse) (result []string) {
+		for _, ns := range resp.Namespaces {
+			result = append(result, ns.Prefix)
+		}
+		return
+	}
+
+	tests := []struct {
+		description string
+		query       url.Values
+		authed      bool
+		expected    []string
+	}{
+		{
+			description: "authed-sees-all-sorted-by-prefix",
+			authed:      true,
+			expected:    []string{"/caches/cache.example.org", "/data/alpha", "/data/beta", "/data/gamma", "/foo_bar", "/origins/origin.example.org"},
+		},
+		{
+			description: "unauthed-only-sees-approved",
+			query:       url.Values{"prefix": {"/data"}},
+			expected:    []string{"/data/alpha", "/data/beta"},
+		},
+		{
+			description: "prefix-wildcards-match-literally",
+			query:       url.Values{"prefix": {"o_b"}},
+			authed:      true,
+			expected:    []string{"/foo_bar"},
+		},
+		{
+			description: "institution-and-type",
+			query:       url.Values{"institution": {"UW"}, "prefixType": {"namespace"}},
+			authed:      true,
+			expected:    []string{"/data/alpha", "/data/beta"},
+		},
+		{
+			description: "status",
+			query:       url.Values{"status": {"Pending"}},
+			authed:      true,
+			expected:    []string{"/data/gamma"},
+		},
+		{
+			description: "custom-field-ignores-case",
+			query:       url.Values{"custom_fields.department": {"PHYSICS"}},
+			authed:      true,
+			expected:    []string{"/data/alpha", "/data/gamma"},
+		},
+		{
+			description: "custom-field-list-element",
+			query:       url.Values{"custom_fields.tags": {"gpu"}},
+			authed:      true,
+			expected:    []string{"/data/beta"},
+		},
+		{
+			description: "created-after-date",
+			query:       url.Values{"createdAfter": {"2026-01-03"}},
+			authed:      true,
+			expected:    []string{"/caches/cache.example.org", "/foo_bar", "/origins/origin.example.org"},
+		},
+		{
+			description: "sort-by-created-desc",
+			query:       url.Values{"sort": {"created_at"}, "order": {"desc"}, "institution": {"UNL"}},
+			authed:      true,
+			expected:    []string{"/foo_bar", "/caches/cache.example.org", "/data/gamma"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			code, resp := search(t, tc.query, tc.authed)
+			require.Equal(t, http.StatusOK, code)
+			assert.Equal(t, tc.expected, prefixes(resp))
+			assert.Equal(t, len(tc.expected), resp.Total)
+			assert.Empty(t, resp.NextCursor)
+		})
+	}
+
+	t.Run("custom-fields-only-for-authed-users", func(t *testing.T) {
+		_, resp := search(t, url.Values{"prefix": {"/data/alpha"}}, true)
+		require.Len(t, resp.Namespaces, 1)
+		assert.Equal(t, "Physics", resp.Namespaces[0].CustomFields["department"])
+		assert.Empty(t, resp.Namespaces[0].Pubkey)
+
+		_, resp = search(t, url.Values{"prefix": {"/data/alpha"}}, false)
+		require.Len(t, resp.Namespaces, 1)
+		assert.Nil(t, resp.Namespaces[0].CustomFields)
+	})
+
+	t.Run("cursor-pagination", func(t *testing.T) {
+		for _, order := range []string{"asc", "desc"} {
+			query := url.Values{"limit": {"2"}, "sort": {"site_name"}, "order": {order}}
+			all := []string{}
+			pages := 0
+			for {
+				code, resp := search(t, query, true)
+				require.Equal(t, http.StatusOK, code)
+				assert.Equal(t, 6, resp.Total)
+				all = append(all, prefixes(resp)...)
+				pages++
+				if resp.NextCursor == "" {
+					break
+				}
+				query.Set("cursor", resp.NextCursor)
+			}
+			assert.Equal(t, 3, pages)
+			if order == "asc" {
+				assert.Equal(t, []string{"/caches/cache.example.org", "/data/alpha", "/data/beta", "/data/gamma", "/foo_bar", "/origins/origin.example.org"}, all)
+			} else {
+				assert.Equal(t, []string{"/origins/origin.example.org", "/foo_bar", "/data/gamma", "/data/beta", "/data/alpha", "/caches/cache.example.org"}, all)
+			}
+		}
+	})
+
+	t.Run("cursor-from-other-sort-rejected", func(t *testing.T) {
+		_, resp := search(t, url.Values{"limit": {"1"}}, true)
+		require.NotEmpty(t, resp.NextCursor)
+		code, _ := search(t, url.Values{"cursor": {resp.NextCursor}, "sort": {"id"}}, true)
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+
+	errorTests := []struct {
+		description  string
+		query        url.Values
+		authed       bool
+		expectedCode int
+	}{
+		{"unauthed-non-approved-status", url.Values{"status": {"Pending"}}, false, http.StatusForbidden},
+		{"unauthed-custom-field", url.Values{"custom_fields.department": {"Physics"}}, false, http.StatusForbidden},
+		{"unknown-custom-field", url.Values{"custom_fields.color": {"red"}}, true, http.StatusBadRequest},
+		{"invalid-status", url.Values{"status": {"random"}}, true, http.StatusBadRequest},
+		{"invalid-prefix-type", url.Values{"prefixType": {"random"}}, true, http.StatusBadRequest},
+		{"invalid-sort", url.Values{"sort": {"pubkey"}}, true, http.StatusBadRequest},
+		{"invalid-order", url.Values{"order": {"up"}}, true, http.StatusBadRequest},
+		{"invalid-limit", url.Values{"limit": {fmt.Sprint(maxSearchLimit + 1)}}, true, http.StatusBadRequest},
+		{"invalid-created-after", url.Values{"createdAfter": {"yesterday"}}, true, http.StatusBadRequest},
+		{"invalid-cursor", url.Values{"cursor": {"not a cursor"}}, true, http.StatusBadRequest},
+	}
+	for _, tc := range errorTests {
+		t.Run(tc.description, func(t *testing.T) {
+			code, _ := search(t, tc.query, tc.authed)
+			assert.Equal(t, tc.expectedCode, code)
+		})
+	}
+
+	t.Run("custom-field-pagination", func(t *testing.T) {
+		query := url.Values{"custom_fields.department": {"physics"}, "limit": {"1"}}
+		code, resp := search(t, query, true)
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, []string{"/data/alpha"}, prefixes(resp))
+		assert.Equal(t, 2, resp.Total)
+		require.NotEmpty(t, resp.NextCursor)
+
+		query.Set("cursor", resp.NextCursor)
+		_, resp = search(t, query, true)
+		assert.Equal(t, []string{"/data/gamma"}, prefixes(resp))
+		assert.Equal(t, 2, resp.Total)
+		assert.Empty(t, resp.NextCursor)
+	})
+
+	t.Run("created-at-with-offsets", func(t *testing.T) {
+		// Timestamps written with different UTC offsets sort and filter by
+		// the instant they stand for
+		east := time.FixedZone("east", 10*3600)
+		west := time.FixedZone("west", -10*3600)
+		tzNs := func(prefix string, createdAt time.Time) server_structs.Registration {
+			return mockNamespace(prefix, "pubkey", "", server_structs.AdminMetadata{Status: server_structs.RegApproved, CreatedAt: createdAt})
+		}
+		require.NoError(t, insertMockDBData([]server_structs.Registration{
+			tzNs("/tz/first", time.Date(2030, 1, 1, 8, 0, 0, 0, east)),     // 2029-12-31T22:00Z
+			tzNs("/tz/second", time.Date(2029, 12, 31, 13, 0, 0, 0, west)), // 2029-12-31T23:00Z
+			tzNs("/tz/third", time.Date(2030, 1, 1, 0, 30, 0, 0, time.UTC)),
+		}))
+
+		_, resp := search(t, url.Values{"prefix": {"/tz/"}, "sort": {"created_at"}}, true)
+		assert.Equal(t, []string{"/tz/first", "/tz/second", "/tz/third"}, prefixes(resp))
+
+		_, resp = search(t, url.Values{"prefix": {"/tz/"}, "createdAfter": {"2029-12-31T22:30:00Z"}}, true)
+		assert.Equal(t, []string{"/tz/second", "/tz/third"}, prefixes(resp))
+	})
+}
@@ -290,7 +290,8 @@ func listNamespacesForUser(ctx *gin.Context) {
 		return
 	}
 
-	filterNs := server_structs.Registration{AdminMetadata: server_structs.AdminMetadata{UserID: user}}
+	// Filter by the user below, as they may also be a co-admin of registrations they don't own
+	filterNs := server_structs.Registration{}
 
 	if queryParams.Status != "" {
 		if server_structs.IsValidRegStatus(queryParams.Status) {
@@ -302,7 +303,7 @@ func listNamespacesForUser(ctx *gin.Context) {
 		}
 	}
 
-	namespaces, err := getRegistrationsByFilter(filterNs, "", false)
+	allNamespaces, err := getRegistrationsByFilter(filterNs, "", false)
 	if err != nil {
 		log.Error("Error getting namespaces for user ", user)
 		ctx.JSON(http.StatusInternalServerError, server_structs.SimpleApiResp{
@@ -310,6 +311,20 @@ func listNamespacesForUser(ctx *gin.Context) {
 			Msg:    "Error getting namespaces by user ID"})
 		return
 	}
+	adminOf, err := getRegistrationIdsForAdmin(user)
+	if err != nil {
+		log.Error("Error getting namespaces for user ", user, ": ", err)
+		ctx.JSON(http.StatusInternalServerError, server_structs.SimpleApiResp{
+			Status: server_structs.RespFailed,
+			Msg:    "Error getting namespaces by user ID"})
+		return
+	}
+	namespaces := []server_structs.Registration{}
+	for _, ns := range allNamespaces {
+		if ns.AdminMetadata.UserID == user || adminOf[ns.ID] {
+			namespaces = append(namespaces, ns)
+		}
+	}
 	ctx.JSON(http.StatusOK, namespaces)
 }
 
@@ -513,6 +528,7 @@ func createUpdateNamespace(ctx *gin.Context, isUpdate bool) {
 				Msg:    fmt.Sprintf("New registration failed. %s", err.Error())})
 			return
 		}
+		auditRegistrationChange(ns.ID, ns.Prefix, user, auditRegistrationCreated, "")
 		if inTopo {
 			ctx.JSON(http.StatusOK,
 				server_structs.SimpleApiResp{
@@ -585,6 +601,20 @@ func createUpdateNamespace(ctx *gin.Context, isUpdate bool) {
 				return
 			}
 
+			// Owners and co-admins can't hand the registration over by editing
+			// it; that takes an ownership transfer approved by a registry admin
+			if belongsTo {
+				existingNs, err := getRegistrationById(ns.ID)
+				if err != nil {
+					log.Error("Error getting namespace: ", err)
+					ctx.JSON(http.StatusInternalServerError, server_structs.SimpleApiResp{
+						Status: server_structs.RespFailed,
+						Msg:    "Error getting namespace"})
+					return
+				}
+				ns.AdminMetadata.UserID = existingNs.AdminMetadata.UserID
+			}
+
 			// If non-admin user accesses a namespace with user_id != user but with access_token
 			if !isAdmin && !belongsTo && accessToken != "" {
 				jwks, err := jwk.Parse([]byte(ns.Pubkey))
@@ -659,6 +689,7 @@ func createUpdateNamespace(ctx *gin.Context, isUpdate bool) {
 				Msg:    "Fail to update namespace"})
 			return
 		}
+		auditRegistrationChange(ns.ID, ns.Prefix, user, auditRegistrationUpdated, "")
 	}
 }
 
@@ -817,6 +848,13 @@ func updateNamespaceStatus(ctx *gin.Context, status server_structs.RegistrationS
 			Msg:    "Failed to update namespace"})
 		return
 	}
+	if ns, err := getRegistrationById(id); err == nil {
+		action := auditRegistrationDenied
+		if status == server_structs.RegApproved {
+			action = auditRegistrationApproved
+		}
+		auditRegistrationChange(id, ns.Prefix, user, action, "")
+	}
 	ctx.JSON(http.StatusOK,
 		server_structs.SimpleApiResp{
 			Status: server_structs.RespOK,
@@ -895,13 +933,23 @@ func deleteNamespace(ctx *gin.Context) {
 			Msg:    "Namespace not found"})
 		return
 	}
+	ns, err := getRegistrationById(id)
+	if err != nil {
+		log.Error("Error getting namespace: ", err)
+		ctx.JSON(http.StatusInternalServerError, server_structs.SimpleApiResp{
+			Status: server_structs.RespFailed,
+			Msg:    "Error getting namespace"})
+		return
+	}
 	err = deleteRegistrationByID(id)
 	if err != nil {
 		log.Errorf("Error deleting the namespace: %v", err)
 		ctx.JSON(http.StatusInternalServerError, server_structs.SimpleApiResp{
 			Status: server_structs.RespFailed,
 			Msg:    "Error deleting the namespace"})
+		return
 	}
+	auditRegistrationChange(id, ns.Prefix, ctx.GetString("User"), auditRegistrationDeleted, "")
 	ctx.JSON(http.StatusOK,
 		server_structs.SimpleApiResp{
 			Status: server_structs.RespOK,
@@ -1034,7 +1082,17 @@ func RegisterRegistryWebAPI(router *gin.RouterGroup) error {
 	// Add CSRF middleware to all the routes below. CSRF middleware will look for
 	// any update methods (post/delete/patch, etc) and automatically check if a
 	// X-CSRF-Token header is present and the token matches
-	registryWebAPI.Use(csrfHandler)
+	//
+	// Requests carrying a bearer token and no login cookie, such as those from
+	// the `pelican registry` commands, can't be forged by a browser and skip
+	// the check.
+	registryWebAPI.Use(func(ctx *gin.Context) {
+		if _, err := ctx.Cookie("login"); err != nil && strings.HasPrefix(ctx.GetHeader("Authorization"), "Bearer ") {
+			ctx.Next()
+			return
+		}
+		csrfHandler(ctx)
+	})
 	// Follow RESTful schema
 	{
 		registryWebAPI.GET("/namespaces", listNamespaces)
@@ -1045,6 +1103,8 @@ func RegisterRegistryWebAPI(router *gin.RouterGroup) error {
 
 		registryWebAPI.GET("/namespaces/user", web_ui.AuthHandler, listNamespacesForUser)
 
+		registryWebAPI.GET("/namespaces/search", searchNamespaces)
+
 		registryWebAPI.GET("/namespaces/:id", web_ui.AuthHandler, getNamespace)
 		registryWebAPI.PUT("/namespaces/:id", web_ui.AuthHandler, func(ctx *gin.Context) {
 			createUpdateNamespace(ctx, true)
@@ -1057,6 +1117,22 @@ func RegisterRegistryWebAPI(router *gin.RouterGroup) error {
 		registryWebAPI.PATCH("/namespaces/:id/deny", web_ui.AuthHandler, web_ui.AdminAuthHandler, func(ctx *gin.Context) {
 			updateNamespaceStatus(ctx, server_structs.RegDenied)
 		})
+		registryWebAPI.GET("/namespaces/:id/admins", web_ui.AuthHandler, listRegistrationAdminsHandler)
+		registryWebAPI.POST("/namespaces/:id/admins", web_ui.AuthHandler, addRegistrationAdminHandler)
+		registryWebAPI.DELETE("/namespaces/:id/admins/:user", web_ui.AuthHandler, removeRegistrationAdminHandler)
+		registryWebAPI.GET("/namespaces/:id/transfers", web_ui.AuthHandler, listNamespaceTransfersHandler)
+		registryWebAPI.POST("/namespaces/:id/transfers", web_ui.AuthHandler, requestTransferHandler)
+		registryWebAPI.GET("/namespaces/:id/audit", web_ui.AuthHandler, listNamespaceAuditHandler)
+	}
+	{
+		registryWebAPI.GET("/transfers", web_ui.AuthHandler, web_ui.AdminAuthHandler, listTransfersHandler)
+		registryWebAPI.PATCH("/transfers/:id/approve", web_ui.AuthHandler, web_ui.AdminAuthHandler, func(ctx *gin.Context) {
+			decideTransferHandler(ctx, true)
+		})
+		registryWebAPI.PATCH("/transfers/:id/deny", web_ui.AuthHandler, web_ui.AdminAuthHandler, func(ctx *gin.Context) {
+			decideTransferHandler(ctx, false)
+		})
+		registryWebAPI.GET("/audit", web_ui.AuthHandler, web_ui.AdminAuthHandler, listAuditHandler)
 	}
 	{
 		registryWebAPI.GET("/servers", listServersHandler)
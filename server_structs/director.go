@@ -23,6 +23,7 @@ import (
 	"net/http"
 	"net/url"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -92,8 +93,9 @@ type (
 		Class       Class    `json:"class" gorm:"not null"`              // SCHEDULED or UNSCHEDULED
 		Description string   `json:"description" gorm:"type:text"`
 		Severity    Severity `json:"severity" gorm:"type:varchar(80);not null"`
-		StartTime   int64    `json:"startTime" gorm:"not null;index"` // Epoch UTC
-		EndTime     int64    `json:"endTime" gorm:"not null;index"`   // Epoch UTC
+		StartTime   int64    `json:"startTime" gorm:"not null;index"`                 // Epoch UTC
+		EndTime     int64    `json:"endTime" gorm:"not null;index"`                   // Epoch UTC
+		Recurrence  string   `json:"recurrence" gorm:"type:text;not null;default:''"` // RRULE repeating the StartTime-EndTime window; empty for a one-off downtime
 		CreatedAt   int64    `json:"createdAt" gorm:"autoCreateTime:milli"`
 		UpdatedAt   int64    `json:"updatedAt" gorm:"autoUpdateTime:milli"`
 		DeletedAt   *int64   `json:"deletedAt"`
@@ -152,6 +154,7 @@ type (
 		StatusWeightLastUpdate int64             `json:"statusWeightLastUpdate"` // The last time the status weight was updated, in epoch seconds
 		Downtimes              []Downtime        `json:"downtimes"`              // Would be an empty slice if no downtime
 		RequiredFeatures       []string          `json:"requiredFeatures"`       // A list of feature names required by this server
+		Features               []string          `json:"features"`               // A list of optional features offered by this server, such as FeatureThirdPartyCopy
 		Status                 string            `json:"status"`
 	}
 
@@ -185,6 +188,7 @@ type (
 		DisableDirectorTest bool              `json:"directorTest"` // Use negative attribute (disable instead of enable) to be BC with legacy servers where they don't have this field
 		Downtimes           []Downtime        `json:"downtimes"`    // Would be an empty slice if no downtime
 		RequiredFeatures    []string          `json:"requiredFeatures"`
+		Features            []string          `json:"features,omitempty"`
 		Now                 time.Time         `json:"now"`    // Populated when ad is sent to the director; otherwise, may be zero.  Used to detect time skews between client and server
 		Status              string            `json:"status"` // The status of the server ad. This is a human-readable string that describes the server's status.
 	}
@@ -236,6 +240,10 @@ type (
 		CollectionsUrl *url.URL
 	}
 
+	XPelFeatures struct {
+		Features []string // Optional features offered by every server in the response
+	}
+
 	XPelTokGen struct {
 		Issuers       []*url.URL
 		MaxScopeDepth uint
@@ -264,6 +272,7 @@ type (
 		IOLoadWeight       float64 `json:"ioLoadWeight"`
 		StatusWeight       float64 `json:"statusWeight"`
 		AvailabilityWeight float64 `json:"availabilityWeight"`
+		ThroughputWeight   float64 `json:"throughputWeight,omitempty"`
 	}
 
 	ServerRedirectInfo struct {
@@ -283,9 +292,25 @@ type (
 		XPelAuthHdr   XPelAuth
 		XPelNsHdr     XPelNs
 		XPelTokGenHdr XPelTokGen
+		XPelFeatHdr   XPelFeatures
 		RedirectInfo  *RedirectInfo // Director's decision information (populated when X-Pelican-Debug is set)
 	}
 
+	// TransferReport is a client's measurement of one successful transfer
+	// from a server, sent to the director to inform throughput-based sorting.
+	TransferReport struct {
+		ServerURL       string        `json:"serverUrl"`       // URL of the server the data came from
+		Bytes           int64         `json:"bytes"`           // Bytes transferred
+		Duration        time.Duration `json:"duration"`        // Wall time of the transfer, including time to first byte
+		TimeToFirstByte time.Duration `json:"timeToFirstByte"` // Time until the first byte arrived
+	}
+
+	// TransferReportRequest is the body of a POST to the director's
+	// transfer report endpoint.
+	TransferReportRequest struct {
+		Reports []TransferReport `json:"reports"`
+	}
+
 	AdAfter int // Ternary logic for the `Ad.After` function
 )
 
@@ -301,6 +326,12 @@ var (
 	generationID atomic.Uint64
 )
 
+const (
+	// The feature advertised by origins accepting WLCG HTTP third-party-copy
+	// (COPY) requests
+	FeatureThirdPartyCopy = "ThirdPartyCopy"
+)
+
 const (
 	CoordinateSourceOverride = "override"
 	CoordinateSourceRandom   = "random"
@@ -364,6 +395,25 @@ func (x *XPelAuth) ParseRawResponse(resp *http.Response) error {
 	return nil
 }
 
+func (x XPelFeatures) GetName() string {
+	return "X-Pelican-Features"
+}
+func (x *XPelFeatures) ParseRawResponse(resp *http.Response) error {
+	for _, raw := range resp.Header.Values(x.GetName()) {
+		for _, feature := range strings.Split(raw, ",") {
+			if feature = strings.TrimSpace(feature); feature != "" {
+				x.Features = append(x.Features, feature)
+			}
+		}
+	}
+	return nil
+}
+
+// Whether the servers in the director response offer the named feature
+func (x XPelFeatures) Has(feature string) bool {
+	return slices.Contains(x.Features, feature)
+}
+
 func (x XPelTokGen) GetName() string {
 	return "X-Pelican-Token-Generation"
 }
@@ -435,10 +485,17 @@ const (
 	DistanceAndLoadType SortType = "distanceAndLoad"
 	RandomType          SortType = "random"
 	AdaptiveType        SortType = "adaptive"
+	ThroughputType      SortType = "throughput"
 
 	AdAfterFalse   AdAfter = 0 // The ad was *not* generated after the compared one
 	AdAfterTrue    AdAfter = 1 // The ad was generated after the compared one
 	AdAfterUnknown AdAfter = 2 // One of the ads in the comparison is missing necessary attributes to determine when it was generated
+
+	// Transfers smaller than this are dominated by latency rather than bandwidth;
+	// clients don't report them and the director ignores them.
+	MinTransferReportBytes = 1 << 20
+	// The most transfer reports the director accepts in a single request.
+	MaxTransferReportsPerRequest = 100
 )
 
 func (st SortType) String() string {
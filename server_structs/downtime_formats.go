@@ -0,0 +1,419 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package server_structs
+
+import (
+	"bufio"
+	"bytes"
+	"hash/fnv"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+type (
+	// ImportedDowntime is a downtime read from an iCalendar file
+	ImportedDowntime struct {
+		Downtime
+		UID       string // UID of the calendar event the downtime was read from
+		Cancelled bool   // The event is cancelled, so the downtime should be removed
+	}
+
+	// icalProperty is a parsed iCalendar content line
+	icalProperty struct {
+		name   string
+		params map[string]string
+		value  string
+	}
+)
+
+const (
+	// Layout of the times in the OSG Topology downtime XML
+	TopoDowntimeTimeLayout = "Jan 2, 2006 15:04 PM MST"
+
+	icalTimeLayout = "20060102T150405Z"
+	icalDateLayout = "20060102"
+
+	// iCalendar properties carrying the Pelican-specific downtime fields
+	icalClassProperty      = "X-PELICAN-CLASS"
+	icalSeverityProperty   = "X-PELICAN-SEVERITY"
+	icalServerNameProperty = "X-PELICAN-SERVER-NAME"
+	icalServerIDProperty   = "X-PELICAN-SERVER-ID"
+
+	// Content lines are folded after this many octets (RFC 5545, section 3.1)
+	icalMaxLineLength = 75
+)
+
+var (
+	icalTextEscaper   = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+	icalTextUnescaper = strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n")
+
+	icalDurationRegex = regexp.MustCompile(`^([+-])?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+	// Namespace for the downtime UUIDs derived from the UIDs of imported events
+	importedDowntimeNamespace = uuid.NewSHA1(uuid.NameSpaceURL, []byte("https://pelicanplatform.org/downtime"))
+)
+
+// writeICalLine writes an iCalendar content line, folding it as needed
+func writeICalLine(buf *bytes.Buffer, line string) {
+	for len(line) > icalMaxLineLength {
+		// Don't split a multi-byte UTF-8 character
+		cut := icalMaxLineLength
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		buf.WriteString(line[:cut])
+		buf.WriteString("\r\n ")
+		line = line[cut:]
+	}
+	buf.WriteString(line)
+	buf.WriteString("\r\n")
+}
+
+// MarshalDowntimesICal encodes downtimes as an iCalendar (RFC 5545) calendar
+// with one event per downtime.  Recurring downtimes carry their RRULE, and
+// downtimes without an end time are exported as events with no DTEND.
+func MarshalDowntimesICal(downtimes []Downtime, prodID string) []byte {
+	var buf bytes.Buffer
+	writeICalLine(&buf, "BEGIN:VCALENDAR")
+	writeICalLine(&buf, "VERSION:2.0")
+	writeICalLine(&buf, "PRODID:"+prodID)
+	writeICalLine(&buf, "CALSCALE:GREGORIAN")
+	for _, dt := range downtimes {
+		summary := dt.ServerName + " downtime"
+		if dt.ServerName == "" {
+			summary = "Downtime"
+		}
+		stamp := dt.UpdatedAt
+		if stamp == 0 {
+			stamp = dt.CreatedAt
+		}
+
+		writeICalLine(&buf, "BEGIN:VEVENT")
+		writeICalLine(&buf, "UID:"+dt.UUID)
+		writeICalLine(&buf, "DTSTAMP:"+time.UnixMilli(stamp).UTC().Format(icalTimeLayout))
+		writeICalLine(&buf, "DTSTART:"+time.UnixMilli(dt.StartTime).UTC().Format(icalTimeLayout))
+		if dt.EndTime != IndefiniteEndTime {
+			writeICalLine(&buf, "DTEND:"+time.UnixMilli(dt.EndTime).UTC().Format(icalTimeLayout))
+		}
+		if dt.Recurrence != "" {
+			writeICalLine(&buf, "RRULE:"+dt.Recurrence)
+		}
+		writeICalLine(&buf, "SUMMARY:"+icalTextEscaper.Replace(summary))
+		// Always written, so that an empty description isn't replaced by the summary on import
+		writeICalLine(&buf, "DESCRIPTION:"+icalTextEscaper.Replace(dt.Description))
+		writeICalLine(&buf, "CATEGORIES:"+icalTextEscaper.Replace(string(dt.Class)))
+		writeICalLine(&buf, "TRANSP:OPAQUE")
+		writeICalLine(&buf, icalClassProperty+":"+icalTextEscaper.Replace(string(dt.Class)))
+		writeICalLine(&buf, icalSeverityProperty+":"+icalTextEscaper.Replace(string(dt.Severity)))
+		if dt.ServerName != "" {
+			writeICalLine(&buf, icalServerNameProperty+":"+icalTextEscaper.Replace(dt.ServerName))
+		}
+		if dt.ServerID != "" {
+			writeICalLine(&buf, icalServerIDProperty+":"+icalTextEscaper.Replace(dt.ServerID))
+		}
+		writeICalLine(&buf, "END:VEVENT")
+	}
+	writeICalLine(&buf, "END:VCALENDAR")
+	return buf.Bytes()
+}
+
+func parseICalLine(line string) (icalProperty, error) {
+	// The value starts after the first colon outside of a quoted parameter
+	inQuotes := false
+	colon := -1
+	for idx, char := range line {
+		if char == '"' {
+			inQuotes = !inQuotes
+		} else if char == ':' && !inQuotes {
+			colon = idx
+			break
+		}
+	}
+	if colon < 0 {
+		return icalProperty{}, errors.Errorf("invalid iCalendar content line %q", line)
+	}
+	parts := strings.Split(line[:colon], ";")
+	prop := icalProperty{name: strings.ToUpper(parts[0]), params: make(map[string]string), value: line[colon+1:]}
+	for _, param := range parts[1:] {
+		key, value, _ := strings.Cut(param, "=")
+		prop.params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+	}
+	return prop, nil
+}
+
+// parseICalTime parses a DATE or DATE-TIME value.  Times without a UTC
+// designator are interpreted in their TZID, or in UTC if there is none.
+func parseICalTime(prop icalProperty) (time.Time, error) {
+	if prop.params["VALUE"] == "DATE" || len(prop.value) == len(icalDateLayout) {
+		return time.Parse(icalDateLayout, prop.value)
+	}
+	if strings.HasSuffix(prop.value, "Z") {
+		return time.Parse(icalTimeLayout, prop.value)
+	}
+	loc := time.UTC
+	if tzid := prop.params["TZID"]; tzid != "" {
+		var err error
+		if loc, err = time.LoadLocation(tzid); err != nil {
+			return time.Time{}, errors.Wrapf(err, "unknown time zone %q", tzid)
+		}
+	}
+	return time.ParseInLocation(strings.TrimSuffix(icalTimeLayout, "Z"), prop.value, loc)
+}
+
+// parseICalDuration parses an iCalendar DURATION value, e.g. "PT4H" or "P1D"
+func parseICalDuration(value string) (time.Duration, error) {
+	match := icalDurationRegex.FindStringSubmatch(value)
+	if match == nil || value == "P" || strings.HasSuffix(value, "T") {
+		return 0, errors.Errorf("invalid duration %q", value)
+	}
+	var duration time.Duration
+	for idx, unit := range []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second} {
+		if match[idx+2] == "" {
+			continue
+		}
+		count, err := strconv.Atoi(match[idx+2])
+		if err != nil {
+			return 0, errors.Errorf("invalid duration %q", value)
+		}
+		duration += time.Duration(count) * unit
+	}
+	if match[1] == "-" {
+		duration = -duration
+	}
+	return duration, nil
+}
+
+// ImportedDowntimeUUID returns the downtime UUID for a calendar event UID.
+// Events exported by Pelican keep the UUID of their downtime; other UIDs are
+// mapped to a stable name-based UUID so that importing a calendar again
+// updates the same downtimes.
+func ImportedDowntimeUUID(uid string) string {
+	if id, err := uuid.Parse(uid); err == nil {
+		return id.String()
+	}
+	return uuid.NewSHA1(importedDowntimeNamespace, []byte(uid)).String()
+}
+
+// ParseDowntimesICal reads the events of an iCalendar (RFC 5545) calendar as
+// downtimes.  Events without an end time or duration are treated as
+// indefinite downtimes.  The Pelican-specific properties written by
+// MarshalDowntimesICal set the class and severity; otherwise downtimes are
+// SCHEDULED outages.
+func ParseDowntimesICal(reader io.Reader) ([]ImportedDowntime, error) {
+	// Unfold the content lines
+	var lines []string
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to read calendar")
+	}
+
+	var result []ImportedDowntime
+	var event []icalProperty
+	inEvent, sawCalendar := false, false
+	// Components nested in an event, e.g. VALARM, are skipped
+	nested := 0
+	for lineno, line := range lines {
+		prop, err := parseICalLine(line)
+		if err != nil {
+			return nil, errors.Wrapf(err, "line %d", lineno+1)
+		}
+		switch {
+		case prop.name == "BEGIN" && strings.EqualFold(prop.value, "VCALENDAR"):
+			sawCalendar = true
+		case prop.name == "BEGIN" && strings.EqualFold(prop.value, "VEVENT"):
+			inEvent, event = true, nil
+		case prop.name == "END" && strings.EqualFold(prop.value, "VEVENT"):
+			if !inEvent {
+				return nil, errors.Errorf("line %d: END:VEVENT without BEGIN:VEVENT", lineno+1)
+			}
+			inEvent = false
+			downtime, err := downtimeFromICalEvent(event)
+			if err != nil {
+				return nil, errors.Wrapf(err, "event ending on line %d", lineno+1)
+			}
+			result = append(result, downtime)
+		case inEvent && prop.name == "BEGIN":
+			nested++
+		case inEvent && prop.name == "END":
+			nested--
+		case inEvent && nested == 0:
+			event = append(event, prop)
+		}
+	}
+	if !sawCalendar {
+		return nil, errors.New("not an iCalendar file: missing BEGIN:VCALENDAR")
+	}
+	if inEvent {
+		return nil, errors.New("calendar ends inside an event")
+	}
+	return result, nil
+}
+
+func downtimeFromICalEvent(props []icalProperty) (ImportedDowntime, error) {
+	result := ImportedDowntime{Downtime: Downtime{Class: SCHEDULED, Severity: Outage, EndTime: IndefiniteEndTime}}
+	var start, end time.Time
+	var duration time.Duration
+	summary, hasDescription := "", false
+	for _, prop := range props {
+		var err error
+		switch prop.name {
+		case "UID":
+			result.UID = prop.value
+		case "DTSTART":
+			start, err = parseICalTime(prop)
+		case "DTEND":
+			end, err = parseICalTime(prop)
+		case "DURATION":
+			duration, err = parseICalDuration(prop.value)
+		case "RRULE":
+			var rule *RecurrenceRule
+			if rule, err = ParseRecurrence(prop.value); err == nil {
+				result.Recurrence = rule.String()
+			}
+		case "SUMMARY":
+			summary = icalTextUnescaper.Replace(prop.value)
+		case "DESCRIPTION":
+			result.Description = icalTextUnescaper.Replace(prop.value)
+			hasDescription = true
+		case "STATUS":
+			result.Cancelled = strings.EqualFold(prop.value, "CANCELLED")
+		case icalClassProperty:
+			result.Class = Class(strings.ToUpper(icalTextUnescaper.Replace(prop.value)))
+			if result.Class != SCHEDULED && result.Class != UNSCHEDULED {
+				err = errors.Errorf("unknown downtime class %q", prop.value)
+			}
+		case icalSeverityProperty:
+			if result.Severity, err = parseSeverity(icalTextUnescaper.Replace(prop.value)); err != nil {
+				err = errors.Wrapf(err, "unknown downtime severity %q", prop.value)
+			}
+		case icalServerNameProperty:
+			result.ServerName = icalTextUnescaper.Replace(prop.value)
+		case icalServerIDProperty:
+			result.ServerID = icalTextUnescaper.Replace(prop.value)
+		}
+		if err != nil {
+			return ImportedDowntime{}, errors.Wrapf(err, "invalid %s", prop.name)
+		}
+	}
+
+	if result.UID == "" {
+		return ImportedDowntime{}, errors.New("event has no UID")
+	}
+	if start.IsZero() {
+		return ImportedDowntime{}, errors.Errorf("event %s has no DTSTART", result.UID)
+	}
+	if !hasDescription {
+		result.Description = summary
+	}
+	result.UUID = ImportedDowntimeUUID(result.UID)
+	result.StartTime = start.UnixMilli()
+	switch {
+	case !end.IsZero():
+		result.EndTime = end.UnixMilli()
+	case duration != 0:
+		result.EndTime = start.Add(duration).UnixMilli()
+	}
+	if result.EndTime != IndefiniteEndTime && result.EndTime < result.StartTime {
+		return ImportedDowntime{}, errors.Errorf("event %s ends before it starts", result.UID)
+	}
+	if err := result.ValidateRecurrence(); err != nil {
+		return ImportedDowntime{}, errors.Wrapf(err, "event %s", result.UID)
+	}
+	return result, nil
+}
+
+// parseSeverity finds the severity of a downtime from its full name, or from
+// its first word as in the OSG Topology downtime XML
+func parseSeverity(value string) (Severity, error) {
+	for _, severity := range []Severity{Outage, Severe, IntermittentOutage, NoSignificantOutageExpected} {
+		if strings.EqualFold(value, string(severity)) {
+			return severity, nil
+		}
+	}
+	fields := strings.Fields(value)
+	if len(fields) > 0 {
+		for _, severity := range []Severity{Outage, Severe, IntermittentOutage, NoSignificantOutageExpected} {
+			if strings.EqualFold(fields[0], strings.Fields(string(severity))[0]) {
+				return severity, nil
+			}
+		}
+	}
+	return "", errors.New("must be one of Outage, Severe, Intermittent Outage or No Significant Outage Expected")
+}
+
+// DowntimesToTopology converts downtimes to the OSG Topology downtime XML
+// format.  A recurring downtime is listed by its current or next occurrence,
+// and downtimes without an end time are given one far in the future, as
+// Topology requires an end time.  Downtimes that are over are left out.
+func DowntimesToTopology(downtimes []Downtime, resourceFQDN string, now time.Time) TopoDowntimeInfo {
+	info := TopoDowntimeInfo{}
+	nowMilli := now.UnixMilli()
+	formatTime := func(epochMilli int64) string {
+		return time.UnixMilli(epochMilli).UTC().Format(TopoDowntimeTimeLayout)
+	}
+	for _, dt := range downtimes {
+		start, end, ok := dt.CurrentWindow(nowMilli)
+		if !ok {
+			continue
+		}
+		if end == IndefiniteEndTime {
+			end = now.AddDate(100, 0, 0).UnixMilli()
+		}
+
+		// Topology downtime IDs are integers, so derive a stable one from the UUID
+		hash := fnv.New32a()
+		_, _ = hash.Write([]byte(dt.UUID))
+
+		topoDowntime := TopoServerDowntime{
+			ID:           int(hash.Sum32() & 0x7fffffff),
+			ResourceName: dt.ServerName,
+			ResourceFQDN: resourceFQDN,
+			StartTime:    formatTime(start),
+			EndTime:      formatTime(end),
+			CreatedTime:  formatTime(dt.CreatedAt),
+			UpdateTime:   formatTime(dt.UpdatedAt),
+			Description:  dt.Description,
+			Class:        string(dt.Class),
+			Severity:     string(dt.Severity),
+		}
+		if start <= nowMilli {
+			info.CurrentDowntimes.Downtimes = append(info.CurrentDowntimes.Downtimes, topoDowntime)
+		} else {
+			info.FutureDowntimes.Downtimes = append(info.FutureDowntimes.Downtimes, topoDowntime)
+		}
+	}
+	return info
+}
@@ -0,0 +1,206 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package server_structs
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDowntimeICalRoundTrip(t *testing.T) {
+	start := time.Date(2026, 3, 10, 2, 0, 0, 0, time.UTC)
+	downtimes := []Downtime{
+		{
+			UUID:        "01952a2f-d4e7-7413-91d6-fdb025176c9f",
+			ServerName:  "my-origin",
+			ServerID:    "server-id",
+			Class:       SCHEDULED,
+			Severity:    IntermittentOutage,
+			Description: "Weekly patching; kernel, firmware\nand " + strings.Repeat("long text ", 20),
+			StartTime:   start.UnixMilli(),
+			EndTime:     start.Add(4 * time.Hour).UnixMilli(),
+			Recurrence:  "FREQ=WEEKLY;BYDAY=TU",
+			UpdatedAt:   start.UnixMilli(),
+		},
+		{
+			UUID:      "01952a5a-fdc4-72a7-88e7-c98aaee5278d",
+			Class:     UNSCHEDULED,
+			Severity:  NoSignificantOutageExpected,
+			StartTime: start.UnixMilli(),
+			EndTime:   IndefiniteEndTime,
+		},
+	}
+
+	calendar := MarshalDowntimesICal(downtimes, "-//Test//EN")
+	for _, line := range strings.Split(strings.TrimSuffix(string(calendar), "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), icalMaxLineLength+1, "content lines must be folded")
+	}
+	assert.Contains(t, string(calendar), "RRULE:FREQ=WEEKLY;BYDAY=TU\r\n")
+	assert.NotContains(t, string(calendar), "DTEND:"+time.UnixMilli(IndefiniteEndTime).UTC().Format(icalTimeLayout))
+
+	imported, err := ParseDowntimesICal(bytes.NewReader(calendar))
+	require.NoError(t, err)
+	require.Len(t, imported, 2)
+	for idx, dt := range imported {
+		expected := downtimes[idx]
+		assert.Equal(t, expected.UUID, dt.UUID, "Pelican UUIDs are kept")
+		assert.Equal(t, expected.UUID, dt.UID)
+		assert.Equal(t, expected.ServerName, dt.ServerName)
+		assert.Equal(t, expected.ServerID, dt.ServerID)
+		assert.Equal(t, expected.Class, dt.Class)
+		assert.Equal(t, expected.Severity, dt.Severity)
+		assert.Equal(t, expected.StartTime, dt.StartTime)
+		assert.Equal(t, expected.EndTime, dt.EndTime)
+		assert.Equal(t, expected.Recurrence, dt.Recurrence)
+		assert.False(t, dt.Cancelled)
+	}
+	assert.Equal(t, downtimes[0].Description, imported[0].Description)
+	assert.Empty(t, imported[1].Description)
+}
+
+func TestParseDowntimesICal(t *testing.T) {
+	calendar := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Example Site//Calendar//EN",
+		"BEGIN:VEVENT",
+		"UID:patch-window@example.org",
+		"DTSTART;TZID=America/Chicago:20260310T020000",
+		"DURATION:PT4H30M",
+		"RRULE:FREQ=MONTHLY;BYDAY=2TU",
+		"SUMMARY:Patch Tuesday",
+		"DESCRIPTION:Monthly patches\\, reboots",
+		"  and firmware",
+		"BEGIN:VALARM",
+		"ACTION:DISPLAY",
+		"DESCRIPTION:Reminder",
+		"END:VALARM",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:cancelled@example.org",
+		"DTSTART;VALUE=DATE:20260401",
+		"DTEND;VALUE=DATE:20260402",
+		"STATUS:CANCELLED",
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n")
+
+	imported, err := ParseDowntimesICal(strings.NewReader(calendar))
+	require.NoError(t, err)
+	require.Len(t, imported, 2)
+
+	chicago, err := time.LoadLocation("America/Chicago")
+	require.NoError(t, err)
+	start := time.Date(2026, 3, 10, 2, 0, 0, 0, chicago)
+	patch := imported[0]
+	assert.Equal(t, "patch-window@example.org", patch.UID)
+	assert.Equal(t, ImportedDowntimeUUID("patch-window@example.org"), patch.UUID)
+	assert.Equal(t, start.UnixMilli(), patch.StartTime)
+	assert.Equal(t, start.Add(4*time.Hour+30*time.Minute).UnixMilli(), patch.EndTime)
+	assert.Equal(t, "FREQ=MONTHLY;BYDAY=2TU", patch.Recurrence)
+	assert.Equal(t, "Monthly patches, reboots and firmware", patch.Description)
+	assert.Equal(t, SCHEDULED, patch.Class)
+	assert.Equal(t, Outage, patch.Severity)
+
+	cancelled := imported[1]
+	assert.True(t, cancelled.Cancelled)
+	assert.Equal(t, time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC).UnixMilli(), cancelled.StartTime)
+	assert.Equal(t, time.Date(2026, 4, 2, 0, 0, 0, 0, time.UTC).UnixMilli(), cancelled.EndTime)
+
+	// Importing again maps the same UID to the same downtime
+	assert.Equal(t, patch.UUID, ImportedDowntimeUUID("patch-window@example.org"))
+	assert.NotEqual(t, patch.UUID, cancelled.UUID)
+
+	invalid := map[string]string{
+		"not-a-calendar": "BEGIN:VEVENT\r\nUID:a\r\nDTSTART:20260101T000000Z\r\nEND:VEVENT\r\n",
+		"no-uid":         "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nDTSTART:20260101T000000Z\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n",
+		"no-start":       "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nUID:a\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n",
+		"ends-early":     "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nUID:a\r\nDTSTART:20260102T000000Z\r\nDTEND:20260101T000000Z\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n",
+		"bad-rrule":      "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nUID:a\r\nDTSTART:20260101T000000Z\r\nDTEND:20260101T010000Z\r\nRRULE:FREQ=YEARLY\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n",
+		"bad-severity":   "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nUID:a\r\nDTSTART:20260101T000000Z\r\nX-PELICAN-SEVERITY:Catastrophic\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n",
+		"unterminated":   "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nUID:a\r\nDTSTART:20260101T000000Z\r\n",
+	}
+	for name, input := range invalid {
+		_, err := ParseDowntimesICal(strings.NewReader(input))
+		assert.Error(t, err, name)
+	}
+}
+
+func TestDowntimesToTopology(t *testing.T) {
+	now := time.Date(2026, 3, 12, 12, 0, 0, 0, time.UTC)
+	downtimes := []Downtime{
+		{
+			UUID:       "current",
+			ServerName: "my-origin",
+			Class:      UNSCHEDULED,
+			Severity:   Outage,
+			StartTime:  now.Add(-time.Hour).UnixMilli(),
+			EndTime:    IndefiniteEndTime,
+		},
+		{
+			// Weekly on Tuesdays; the next occurrence is on March 17th
+			UUID:       "recurring",
+			ServerName: "my-origin",
+			Class:      SCHEDULED,
+			Severity:   Severe,
+			StartTime:  time.Date(2026, 3, 3, 2, 0, 0, 0, time.UTC).UnixMilli(),
+			EndTime:    time.Date(2026, 3, 3, 6, 0, 0, 0, time.UTC).UnixMilli(),
+			Recurrence: "FREQ=WEEKLY",
+		},
+		{
+			UUID:      "past",
+			StartTime: now.Add(-48 * time.Hour).UnixMilli(),
+			EndTime:   now.Add(-24 * time.Hour).UnixMilli(),
+		},
+	}
+
+	info := DowntimesToTopology(downtimes, "origin.example.org", now)
+	require.Len(t, info.CurrentDowntimes.Downtimes, 1)
+	require.Len(t, info.FutureDowntimes.Downtimes, 1)
+	current := info.CurrentDowntimes.Downtimes[0]
+	future := info.FutureDowntimes.Downtimes[0]
+	assert.Equal(t, "my-origin", current.ResourceName)
+	assert.Equal(t, "origin.example.org", current.ResourceFQDN)
+	assert.Equal(t, "UNSCHEDULED", current.Class)
+	assert.NotEqual(t, current.ID, future.ID)
+
+	// The times can be read back with the layout the director uses for Topology
+	start, err := time.Parse(TopoDowntimeTimeLayout, future.StartTime)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 3, 17, 2, 0, 0, 0, time.UTC), start.UTC())
+	end, err := time.Parse(TopoDowntimeTimeLayout, future.EndTime)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 3, 17, 6, 0, 0, 0, time.UTC), end.UTC())
+	end, err = time.Parse(TopoDowntimeTimeLayout, current.EndTime)
+	require.NoError(t, err)
+	assert.True(t, end.After(now.AddDate(50, 0, 0)), "indefinite downtimes get an end far in the future")
+
+	encoded, err := xml.Marshal(info)
+	require.NoError(t, err)
+	var decoded TopoDowntimeInfo
+	require.NoError(t, xml.Unmarshal(encoded, &decoded))
+	assert.Equal(t, info.FutureDowntimes, decoded.FutureDowntimes)
+}
@@ -0,0 +1,417 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package server_structs
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type (
+	// RecurrenceRule describes how a downtime repeats.  It is a subset of the
+	// iCalendar RRULE syntax (RFC 5545), e.g. "FREQ=WEEKLY;BYDAY=TU" or
+	// "FREQ=MONTHLY;BYDAY=2TU;UNTIL=20271231T000000Z".  Occurrences are
+	// evaluated in UTC and start at the time of day of the downtime's first
+	// window.
+	RecurrenceRule struct {
+		Freq       string          // RecurrenceDaily, RecurrenceWeekly or RecurrenceMonthly
+		Interval   int             // Number of periods between occurrences; at least 1
+		ByDay      []RecurrenceDay // Days of the week the downtime occurs on (WEEKLY and MONTHLY only)
+		ByMonthDay []int           // Days of the month the downtime occurs on; negative values count from the end (MONTHLY only)
+		Count      int             // Total number of occurrences, including the first; 0 for no limit
+		Until      time.Time       // Latest start of an occurrence; zero for no limit
+	}
+
+	// RecurrenceDay is a BYDAY entry of a recurrence rule
+	RecurrenceDay struct {
+		Weekday time.Weekday
+		Ordinal int // For MONTHLY rules, the n-th such weekday of the month (negative from the end); 0 for every one
+	}
+)
+
+const (
+	RecurrenceDaily   = "DAILY"
+	RecurrenceWeekly  = "WEEKLY"
+	RecurrenceMonthly = "MONTHLY"
+
+	// Give up on a rule that produces no occurrence in this many consecutive
+	// periods, e.g. BYMONTHDAY=30 on a yearly schedule that starts in February
+	recurrenceMaxEmptyPeriods = 1000
+
+	recurrenceUntilFormat = "20060102T150405Z"
+)
+
+var weekdayCodes = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// ParseRecurrence parses a downtime recurrence rule.  The rule may carry an
+// "RRULE:" prefix; the supported parts are FREQ (DAILY, WEEKLY or MONTHLY),
+// INTERVAL, BYDAY, BYMONTHDAY, COUNT and UNTIL.
+func ParseRecurrence(rule string) (*RecurrenceRule, error) {
+	rule = strings.TrimSpace(rule)
+	if len(rule) >= 6 && strings.EqualFold(rule[:6], "RRULE:") {
+		rule = rule[6:]
+	}
+	if rule == "" {
+		return nil, errors.New("empty recurrence rule")
+	}
+
+	result := &RecurrenceRule{Interval: 1}
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(rule, ";") {
+		key, value, found := strings.Cut(part, "=")
+		key = strings.ToUpper(strings.TrimSpace(key))
+		value = strings.ToUpper(strings.TrimSpace(value))
+		if !found || value == "" {
+			return nil, errors.Errorf("invalid recurrence rule part %q; expected KEY=VALUE", part)
+		}
+		if seen[key] {
+			return nil, errors.Errorf("recurrence rule part %s is given more than once", key)
+		}
+		seen[key] = true
+
+		var err error
+		switch key {
+		case "FREQ":
+			switch value {
+			case RecurrenceDaily, RecurrenceWeekly, RecurrenceMonthly:
+				result.Freq = value
+			default:
+				return nil, errors.Errorf("unsupported recurrence frequency %q; must be DAILY, WEEKLY or MONTHLY", value)
+			}
+		case "INTERVAL":
+			if result.Interval, err = strconv.Atoi(value); err != nil || result.Interval < 1 {
+				return nil, errors.Errorf("invalid recurrence interval %q; must be a positive integer", value)
+			}
+		case "COUNT":
+			if result.Count, err = strconv.Atoi(value); err != nil || result.Count < 1 {
+				return nil, errors.Errorf("invalid recurrence count %q; must be a positive integer", value)
+			}
+		case "UNTIL":
+			if result.Until, err = time.Parse(recurrenceUntilFormat, value); err != nil {
+				if result.Until, err = time.Parse("20060102", value); err != nil {
+					return nil, errors.Errorf("invalid recurrence end %q; must be of the form YYYYMMDD or YYYYMMDDTHHMMSSZ", value)
+				}
+				// A date-only end includes occurrences on that day
+				result.Until = result.Until.Add(24*time.Hour - time.Second)
+			}
+		case "BYDAY":
+			for _, entry := range strings.Split(value, ",") {
+				day, err := parseRecurrenceDay(entry)
+				if err != nil {
+					return nil, err
+				}
+				result.ByDay = append(result.ByDay, day)
+			}
+		case "BYMONTHDAY":
+			for _, entry := range strings.Split(value, ",") {
+				monthDay, err := strconv.Atoi(entry)
+				if err != nil || monthDay == 0 || monthDay < -31 || monthDay > 31 {
+					return nil, errors.Errorf("invalid recurrence month day %q; must be between 1 and 31, or -31 and -1", entry)
+				}
+				result.ByMonthDay = append(result.ByMonthDay, monthDay)
+			}
+		default:
+			return nil, errors.Errorf("unsupported recurrence rule part %s", key)
+		}
+	}
+
+	if result.Freq == "" {
+		return nil, errors.New("recurrence rule is missing FREQ")
+	}
+	if result.Count > 0 && !result.Until.IsZero() {
+		return nil, errors.New("recurrence rule may not contain both COUNT and UNTIL")
+	}
+	if len(result.ByDay) > 0 && result.Freq == RecurrenceDaily {
+		return nil, errors.New("BYDAY is only supported for WEEKLY and MONTHLY recurrences")
+	}
+	if len(result.ByMonthDay) > 0 && result.Freq != RecurrenceMonthly {
+		return nil, errors.New("BYMONTHDAY is only supported for MONTHLY recurrences")
+	}
+	if len(result.ByMonthDay) > 0 && len(result.ByDay) > 0 {
+		return nil, errors.New("recurrence rule may not contain both BYDAY and BYMONTHDAY")
+	}
+	for _, day := range result.ByDay {
+		if day.Ordinal != 0 && result.Freq != RecurrenceMonthly {
+			return nil, errors.New("numbered BYDAY entries (e.g. 2TU) are only supported for MONTHLY recurrences")
+		}
+	}
+	return result, nil
+}
+
+func parseRecurrenceDay(entry string) (RecurrenceDay, error) {
+	if len(entry) < 2 {
+		return RecurrenceDay{}, errors.Errorf("invalid recurrence weekday %q", entry)
+	}
+	day := RecurrenceDay{Weekday: -1}
+	code := entry[len(entry)-2:]
+	for idx, weekdayCode := range weekdayCodes {
+		if code == weekdayCode {
+			day.Weekday = time.Weekday(idx)
+		}
+	}
+	if day.Weekday < 0 {
+		return RecurrenceDay{}, errors.Errorf("invalid recurrence weekday %q; must be one of MO, TU, WE, TH, FR, SA or SU", entry)
+	}
+	if ordinal := entry[:len(entry)-2]; ordinal != "" {
+		var err error
+		if day.Ordinal, err = strconv.Atoi(ordinal); err != nil || day.Ordinal == 0 || day.Ordinal < -5 || day.Ordinal > 5 {
+			return RecurrenceDay{}, errors.Errorf("invalid recurrence weekday %q; the week number must be between 1 and 5, or -5 and -1", entry)
+		}
+	}
+	return day, nil
+}
+
+// String formats the day as it appears in a BYDAY rule part
+func (d RecurrenceDay) String() string {
+	if d.Ordinal != 0 {
+		return fmt.Sprintf("%d%s", d.Ordinal, weekdayCodes[d.Weekday])
+	}
+	return weekdayCodes[d.Weekday]
+}
+
+// String returns the rule in its canonical RRULE form
+func (r *RecurrenceRule) String() string {
+	parts := []string{"FREQ=" + r.Freq}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, 0, len(r.ByDay))
+		for _, day := range r.ByDay {
+			days = append(days, day.String())
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		monthDays := make([]string, 0, len(r.ByMonthDay))
+		for _, monthDay := range r.ByMonthDay {
+			monthDays = append(monthDays, strconv.Itoa(monthDay))
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(monthDays, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format(recurrenceUntilFormat))
+	}
+	return strings.Join(parts, ";")
+}
+
+// periodStart returns the start of the n-th period of the rule, counting the
+// period containing the first occurrence as 0
+func (r *RecurrenceRule) periodStart(first time.Time, n int) time.Time {
+	day := time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, time.UTC)
+	switch r.Freq {
+	case RecurrenceWeekly:
+		// Weeks start on Monday, the RRULE default
+		monday := day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+		return monday.AddDate(0, 0, 7*r.Interval*n)
+	case RecurrenceMonthly:
+		return time.Date(first.Year(), first.Month()+time.Month(r.Interval*n), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return day.AddDate(0, 0, r.Interval*n)
+	}
+}
+
+// periodsBefore returns how many whole periods of the rule lie between the
+// start of the first period and the given time
+func (r *RecurrenceRule) periodsBefore(first, t time.Time) int {
+	start := r.periodStart(first, 0)
+	if !t.After(start) {
+		return 0
+	}
+	switch r.Freq {
+	case RecurrenceWeekly:
+		return int(t.Sub(start)/(7*24*time.Hour)) / r.Interval
+	case RecurrenceMonthly:
+		return ((t.Year()-start.Year())*12 + int(t.Month()-start.Month())) / r.Interval
+	default:
+		return int(t.Sub(start)/(24*time.Hour)) / r.Interval
+	}
+}
+
+// occurrencesInPeriod returns the sorted occurrence starts the rule produces
+// in the period beginning at the given time
+func (r *RecurrenceRule) occurrencesInPeriod(first, period time.Time) []time.Time {
+	atTimeOfDay := func(day time.Time) time.Time {
+		return time.Date(day.Year(), day.Month(), day.Day(), first.Hour(), first.Minute(), first.Second(), first.Nanosecond(), time.UTC)
+	}
+
+	var days []time.Time
+	switch r.Freq {
+	case RecurrenceDaily:
+		days = append(days, period)
+	case RecurrenceWeekly:
+		weekdays := []time.Weekday{first.Weekday()}
+		if len(r.ByDay) > 0 {
+			weekdays = weekdays[:0]
+			for _, day := range r.ByDay {
+				weekdays = append(weekdays, day.Weekday)
+			}
+		}
+		for _, weekday := range weekdays {
+			days = append(days, period.AddDate(0, 0, (int(weekday)+6)%7))
+		}
+	case RecurrenceMonthly:
+		daysInMonth := period.AddDate(0, 1, -1).Day()
+		monthDays := r.ByMonthDay
+		if len(r.ByDay) == 0 && len(monthDays) == 0 {
+			monthDays = []int{first.Day()}
+		}
+		for _, monthDay := range monthDays {
+			if monthDay < 0 {
+				monthDay += daysInMonth + 1
+			}
+			// Months without the given day are skipped, as in RFC 5545
+			if monthDay >= 1 && monthDay <= daysInMonth {
+				days = append(days, period.AddDate(0, 0, monthDay-1))
+			}
+		}
+		for _, day := range r.ByDay {
+			firstMatch := 1 + (int(day.Weekday)-int(period.Weekday())+7)%7
+			var matches []int
+			for monthDay := firstMatch; monthDay <= daysInMonth; monthDay += 7 {
+				matches = append(matches, monthDay)
+			}
+			switch {
+			case day.Ordinal == 0:
+				for _, monthDay := range matches {
+					days = append(days, period.AddDate(0, 0, monthDay-1))
+				}
+			case day.Ordinal > 0 && day.Ordinal <= len(matches):
+				days = append(days, period.AddDate(0, 0, matches[day.Ordinal-1]-1))
+			case day.Ordinal < 0 && -day.Ordinal <= len(matches):
+				days = append(days, period.AddDate(0, 0, matches[len(matches)+day.Ordinal]-1))
+			}
+		}
+	}
+
+	result := make([]time.Time, 0, len(days))
+	for _, day := range days {
+		result = append(result, atTimeOfDay(day))
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Before(result[j]) })
+	return result
+}
+
+// NextOccurrence returns the start of the first occurrence of the rule, for a
+// series whose first occurrence is at `first`, that begins at or after `from`.
+// As in RFC 5545, `first` itself is always an occurrence.  Returns false if
+// the series ends before `from`.
+func (r *RecurrenceRule) NextOccurrence(first, from time.Time) (time.Time, bool) {
+	first = first.UTC()
+	if !from.After(first) {
+		return first, true
+	}
+
+	// Without a COUNT the occurrences before `from` don't matter, so skip
+	// ahead to the period before the one containing it
+	period := 0
+	count := 1 // The first occurrence
+	if r.Count == 0 {
+		period = max(r.periodsBefore(first, from)-1, 0)
+	}
+
+	for emptyPeriods := 0; emptyPeriods < recurrenceMaxEmptyPeriods; period++ {
+		occurrences := r.occurrencesInPeriod(first, r.periodStart(first, period))
+		emptyPeriods++
+		var previous time.Time
+		for _, occurrence := range occurrences {
+			if !occurrence.After(first) || occurrence.Equal(previous) {
+				continue
+			}
+			previous = occurrence
+			emptyPeriods = 0
+			if !r.Until.IsZero() && occurrence.After(r.Until) {
+				return time.Time{}, false
+			}
+			count++
+			if r.Count > 0 && count > r.Count {
+				return time.Time{}, false
+			}
+			if !occurrence.Before(from) {
+				return occurrence, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// IsRecurring reports whether the downtime repeats according to a recurrence
+// rule.  StartTime and EndTime of a recurring downtime are its first window.
+func (dt Downtime) IsRecurring() bool {
+	return dt.Recurrence != ""
+}
+
+// ValidateRecurrence checks that the downtime's recurrence rule, if any, can
+// be parsed and that the downtime has a finite first window to repeat
+func (dt Downtime) ValidateRecurrence() error {
+	if !dt.IsRecurring() {
+		return nil
+	}
+	if _, err := ParseRecurrence(dt.Recurrence); err != nil {
+		return err
+	}
+	if dt.EndTime == IndefiniteEndTime {
+		return errors.New("a recurring downtime must have an end time")
+	}
+	if dt.EndTime <= dt.StartTime {
+		return errors.New("a recurring downtime must end after it starts")
+	}
+	return nil
+}
+
+// CurrentWindow returns the window of the downtime (as epoch milliseconds)
+// that is in effect at `now`, or else the next one to start.  For a recurring
+// downtime this is the matching occurrence of its rule.  Returns false if the
+// downtime has no window ending at or after `now`.
+//
+// A downtime with an invalid recurrence rule is treated as a one-off downtime
+func (dt Downtime) CurrentWindow(now int64) (start, end int64, ok bool) {
+	var rule *RecurrenceRule
+	if dt.ValidateRecurrence() == nil && dt.IsRecurring() {
+		rule, _ = ParseRecurrence(dt.Recurrence)
+	}
+	if rule == nil {
+		if dt.EndTime == IndefiniteEndTime || dt.EndTime >= now {
+			return dt.StartTime, dt.EndTime, true
+		}
+		return 0, 0, false
+	}
+
+	duration := dt.EndTime - dt.StartTime
+	next, found := rule.NextOccurrence(time.UnixMilli(dt.StartTime), time.UnixMilli(now-duration))
+	if !found {
+		return 0, 0, false
+	}
+	return next.UnixMilli(), next.UnixMilli() + duration, true
+}
+
+// IsActive reports whether the downtime is in effect at `now`, given as epoch
+// milliseconds
+func (dt Downtime) IsActive(now int64) bool {
+	start, _, ok := dt.CurrentWindow(now)
+	return ok && start <= now
+}
@@ -0,0 +1,170 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package server_structs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRecurrence(t *testing.T) {
+	valid := map[string]string{
+		"FREQ=WEEKLY;BYDAY=TU":                        "FREQ=WEEKLY;BYDAY=TU",
+		"rrule:freq=weekly;interval=2;byday=mo,th":    "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH",
+		"FREQ=MONTHLY;BYDAY=2TU;COUNT=12":             "FREQ=MONTHLY;BYDAY=2TU;COUNT=12",
+		"FREQ=MONTHLY;BYMONTHDAY=1,-1":                "FREQ=MONTHLY;BYMONTHDAY=1,-1",
+		"FREQ=DAILY;UNTIL=20270101T000000Z":           "FREQ=DAILY;UNTIL=20270101T000000Z",
+		"FREQ=DAILY;UNTIL=20270101":                   "FREQ=DAILY;UNTIL=20270101T235959Z",
+		"FREQ=MONTHLY;INTERVAL=1;BYDAY=-1FR":          "FREQ=MONTHLY;BYDAY=-1FR",
+		" FREQ=WEEKLY ; BYDAY=SA,SU ":                 "FREQ=WEEKLY;BYDAY=SA,SU",
+		"FREQ=WEEKLY;BYDAY=TU;UNTIL=20261231T120000Z": "FREQ=WEEKLY;BYDAY=TU;UNTIL=20261231T120000Z",
+	}
+	for input, expected := range valid {
+		rule, err := ParseRecurrence(input)
+		if assert.NoError(t, err, input) {
+			assert.Equal(t, expected, rule.String(), input)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"BYDAY=TU",
+		"FREQ=YEARLY",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=WEEKLY;BYDAY=2TU",
+		"FREQ=DAILY;BYDAY=TU",
+		"FREQ=WEEKLY;BYMONTHDAY=1",
+		"FREQ=MONTHLY;BYDAY=TU;BYMONTHDAY=1",
+		"FREQ=MONTHLY;BYDAY=6TU",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=2;UNTIL=20270101",
+		"FREQ=DAILY;UNTIL=tomorrow",
+		"FREQ=DAILY;FREQ=WEEKLY",
+		"FREQ=DAILY;BYHOUR=3",
+		"FREQ",
+	}
+	for _, input := range invalid {
+		_, err := ParseRecurrence(input)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestRecurrenceNextOccurrence(t *testing.T) {
+	// Tuesday, 2026-01-06 02:00 UTC
+	first := time.Date(2026, 1, 6, 2, 0, 0, 0, time.UTC)
+	date := func(month time.Month, day, hour int) time.Time {
+		return time.Date(2026, month, day, hour, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name     string
+		rule     string
+		from     time.Time
+		expected time.Time // Zero if the series is over
+	}{
+		{"first", "FREQ=WEEKLY", first.Add(-time.Hour), first},
+		{"weekly", "FREQ=WEEKLY", first.Add(time.Minute), date(1, 13, 2)},
+		{"weekly-exact", "FREQ=WEEKLY", date(1, 13, 2), date(1, 13, 2)},
+		{"weekly-far", "FREQ=WEEKLY", date(10, 14, 3), date(10, 20, 2)},
+		{"biweekly", "FREQ=WEEKLY;INTERVAL=2", first.Add(time.Minute), date(1, 20, 2)},
+		{"weekly-byday", "FREQ=WEEKLY;BYDAY=TU,TH", first.Add(time.Minute), date(1, 8, 2)},
+		{"weekly-byday-next-week", "FREQ=WEEKLY;BYDAY=MO,TU", date(1, 7, 0), date(1, 12, 2)},
+		{"daily", "FREQ=DAILY", date(3, 1, 5), date(3, 2, 2)},
+		{"monthly", "FREQ=MONTHLY", first.Add(time.Minute), date(2, 6, 2)},
+		{"monthly-second-tuesday", "FREQ=MONTHLY;BYDAY=2TU", first.Add(time.Minute), date(1, 13, 2)},
+		{"monthly-second-tuesday-later", "FREQ=MONTHLY;BYDAY=2TU", date(1, 14, 0), date(2, 10, 2)},
+		{"monthly-last-friday", "FREQ=MONTHLY;BYDAY=-1FR", first.Add(time.Minute), date(1, 30, 2)},
+		{"monthly-last-day", "FREQ=MONTHLY;BYMONTHDAY=-1", date(2, 1, 0), date(2, 28, 2)},
+		{"monthly-31st-skips-short-months", "FREQ=MONTHLY;BYMONTHDAY=31", date(2, 1, 0), date(3, 31, 2)},
+		{"count", "FREQ=WEEKLY;COUNT=3", date(1, 19, 0), date(1, 20, 2)},
+		{"count-exhausted", "FREQ=WEEKLY;COUNT=3", date(1, 21, 0), time.Time{}},
+		{"until", "FREQ=WEEKLY;UNTIL=20260120", date(1, 19, 0), date(1, 20, 2)},
+		{"until-passed", "FREQ=WEEKLY;UNTIL=20260120", date(1, 21, 0), time.Time{}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rule, err := ParseRecurrence(tc.rule)
+			require.NoError(t, err)
+			next, ok := rule.NextOccurrence(first, tc.from)
+			if tc.expected.IsZero() {
+				assert.False(t, ok, "expected no occurrence, got %s", next)
+				return
+			}
+			require.True(t, ok)
+			assert.Equal(t, tc.expected, next)
+		})
+	}
+
+	t.Run("never-matches", func(t *testing.T) {
+		// February never has a 30th
+		rule, err := ParseRecurrence("FREQ=MONTHLY;INTERVAL=12;BYMONTHDAY=30")
+		require.NoError(t, err)
+		february := date(2, 3, 2)
+		_, ok := rule.NextOccurrence(february, february.Add(time.Minute))
+		assert.False(t, ok)
+	})
+}
+
+func TestDowntimeCurrentWindow(t *testing.T) {
+	first := time.Date(2026, 1, 6, 2, 0, 0, 0, time.UTC)
+	weekly := Downtime{
+		StartTime:  first.UnixMilli(),
+		EndTime:    first.Add(4 * time.Hour).UnixMilli(),
+		Recurrence: "FREQ=WEEKLY;BYDAY=TU",
+	}
+	require.NoError(t, weekly.ValidateRecurrence())
+
+	// During an occurrence a month later
+	during := first.AddDate(0, 0, 28).Add(time.Hour).UnixMilli()
+	start, end, ok := weekly.CurrentWindow(during)
+	require.True(t, ok)
+	assert.Equal(t, first.AddDate(0, 0, 28).UnixMilli(), start)
+	assert.Equal(t, first.AddDate(0, 0, 28).Add(4*time.Hour).UnixMilli(), end)
+	assert.True(t, weekly.IsActive(during))
+
+	// Between occurrences
+	between := first.AddDate(0, 0, 30).UnixMilli()
+	start, _, ok = weekly.CurrentWindow(between)
+	require.True(t, ok)
+	assert.Equal(t, first.AddDate(0, 0, 35).UnixMilli(), start)
+	assert.False(t, weekly.IsActive(between))
+
+	// A one-off downtime is only active during its window
+	oneOff := weekly
+	oneOff.Recurrence = ""
+	assert.True(t, oneOff.IsActive(first.Add(time.Hour).UnixMilli()))
+	assert.False(t, oneOff.IsActive(during))
+	_, _, ok = oneOff.CurrentWindow(during)
+	assert.False(t, ok)
+
+	indefinite := Downtime{StartTime: first.UnixMilli(), EndTime: IndefiniteEndTime}
+	assert.True(t, indefinite.IsActive(during))
+
+	// A recurring downtime needs a finite window
+	indefinite.Recurrence = "FREQ=WEEKLY"
+	assert.Error(t, indefinite.ValidateRecurrence())
+	invalid := weekly
+	invalid.Recurrence = "FREQ=HOURLY"
+	assert.Error(t, invalid.ValidateRecurrence())
+	assert.False(t, invalid.IsActive(during), "a downtime with an invalid rule is treated as one-off")
+}
@@ -187,6 +187,40 @@ type Contact struct {
 	Server Server `json:"server" gorm:"foreignKey:ServerID;references:ID;constraint:OnDelete:CASCADE"`
 }
 
+// RegistrationAdmin is a user who, along with the owner in AdminMetadata.UserID,
+// may view and manage a registration
+type RegistrationAdmin struct {
+	RegistrationID int       `json:"registration_id" gorm:"primaryKey;autoIncrement:false"`
+	UserID         string    `json:"user_id" gorm:"primaryKey"`
+	AddedBy        string    `json:"added_by"`
+	CreatedAt      time.Time `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
+}
+
+// RegistrationTransfer is a request to change the owner of a registration.
+// Transfers are pending until a registry admin approves or denies them.
+type RegistrationTransfer struct {
+	ID             int                `json:"id" gorm:"primaryKey"`
+	RegistrationID int                `json:"registration_id"`
+	FromUserID     string             `json:"from_user_id"`
+	ToUserID       string             `json:"to_user_id"`
+	RequestedBy    string             `json:"requested_by"`
+	Status         RegistrationStatus `json:"status"`
+	DecidedBy      string             `json:"decided_by,omitempty"`
+	CreatedAt      time.Time          `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
+	DecidedAt      *time.Time         `json:"decided_at,omitempty"`
+}
+
+// RegistrationAuditEntry records a change made to a registration and who made it
+type RegistrationAuditEntry struct {
+	ID             int       `json:"id" gorm:"primaryKey"`
+	RegistrationID int       `json:"registration_id"`
+	Prefix         string    `json:"prefix"`
+	UserID         string    `json:"user_id"`
+	Action         string    `json:"action"`
+	Details        string    `json:"details,omitempty"`
+	CreatedAt      time.Time `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
+}
+
 // Define the table name for each struct for GORM
 func (Server) TableName() string {
 	return "servers"
@@ -204,6 +238,18 @@ func (Contact) TableName() string {
 	return "contacts"
 }
 
+func (RegistrationAdmin) TableName() string {
+	return "registration_admins"
+}
+
+func (RegistrationTransfer) TableName() string {
+	return "registration_transfers"
+}
+
+func (RegistrationAuditEntry) TableName() string {
+	return "registration_audit_log"
+}
+
 // ServerRegistration combines Server and Registration structs
 // Usually one server has one registration, but in the future, one server can
 // have two registrations (origin and cache)
@@ -646,6 +646,21 @@ func GetOriginExports() ([]OriginExport, error) {
 	return originExports, nil
 }
 
+// OriginUsesXRootD reports whether the configured origin storage type is
+// served by XRootD rather than by the Go-native origin_serve handlers.
+// POSIXv2 and SSH origins are always native; S3 and HTTPS origins are
+// native when Origin.EnableNativeBackend is set.
+func OriginUsesXRootD() bool {
+	switch server_structs.OriginStorageType(param.Origin_StorageType.GetString()) {
+	case server_structs.OriginStoragePosixv2, server_structs.OriginStorageSSH:
+		return false
+	case server_structs.OriginStorageS3, server_structs.OriginStorageHTTPS:
+		return !param.Origin_EnableNativeBackend.GetBool()
+	default:
+		return true
+	}
+}
+
 // Parse the volumes passed via -v flag
 func getVolumes() []exportVolume {
 	volumes := param.Origin_ExportVolumes.GetStringSlice()
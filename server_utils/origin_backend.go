@@ -54,8 +54,9 @@ type OriginChecksummer interface {
 	// GetDigests returns RFC 3230 formatted digest strings (e.g.
 	// "md5=...", "crc32c=...") for the given file.  wantDigest is the
 	// raw Want-Digest header value from the client (comma-separated
-	// algorithm names).
-	GetDigests(relativePath string, wantDigest string) ([]string, error)
+	// algorithm names).  ctx is the client request's context, carrying
+	// any stashed PelicanHeaders.
+	GetDigests(ctx context.Context, relativePath string, wantDigest string) ([]string, error)
 }
 
 // HTTPStatusCoder is optionally implemented by errors returned from
@@ -74,9 +75,14 @@ type pelicanHeadersKey struct{}
 // PelicanHeaders holds the subset of client HTTP headers that should
 // be propagated through internal layers (e.g. WebDAV → backend) for
 // tracing and timeout purposes.
+//
+// Authorization carries the client's bearer credential so that backends
+// configured for token passthrough can present it upstream; other
+// backends must not forward it.
 type PelicanHeaders struct {
-	JobId   string
-	Timeout string
+	JobId         string
+	Timeout       string
+	Authorization string
 }
 
 // WithPelicanHeaders stores the given headers in ctx.
@@ -93,13 +99,22 @@ func PelicanHeadersFromContext(ctx context.Context) *PelicanHeaders {
 	return nil
 }
 
-// StashPelicanHeaders is a convenience that extracts X-Pelican-JobId
-// and X-Pelican-Timeout from the incoming request and returns a new
-// request whose context carries the values.
+// StashPelicanHeaders is a convenience that extracts X-Pelican-JobId,
+// X-Pelican-Timeout and the client's credential from the incoming
+// request and returns a new request whose context carries the values.
+// A token passed via the access_token query parameter is normalised to
+// an Authorization header value.
 func StashPelicanHeaders(r *http.Request) *http.Request {
+	authz := r.Header.Get("Authorization")
+	if authz == "" {
+		if tok := r.URL.Query().Get("access_token"); tok != "" {
+			authz = "Bearer " + tok
+		}
+	}
 	ctx := WithPelicanHeaders(r.Context(), &PelicanHeaders{
-		JobId:   r.Header.Get("X-Pelican-JobId"),
-		Timeout: r.Header.Get("X-Pelican-Timeout"),
+		JobId:         r.Header.Get("X-Pelican-JobId"),
+		Timeout:       r.Header.Get("X-Pelican-Timeout"),
+		Authorization: authz,
 	})
 	return r.WithContext(ctx)
 }
@@ -36,6 +36,7 @@ import (
 	"golang.org/x/net/webdav"
 
 	"github.com/pelicanplatform/pelican/server_utils"
+	"github.com/pelicanplatform/pelican/tracing"
 )
 
 // SSHFileSystem implements webdav.FileSystem by proxying requests to the remote
@@ -93,10 +94,12 @@ func IsTransportReady() bool {
 }
 
 // setPelicanHeaders copies the stashed Pelican headers from ctx onto
-// an outgoing HTTP request destined for the helper.  The headers are
+// an outgoing HTTP request destined for the helper, along with the W3C
+// trace context of the request.  The Pelican headers are
 // placed in the context by the generic handler via
 // server_utils.StashPelicanHeaders.
 func setPelicanHeaders(ctx context.Context, req *http.Request) {
+	tracing.InjectHeaders(ctx, req.Header)
 	if h := server_utils.PelicanHeadersFromContext(ctx); h != nil {
 		if h.JobId != "" {
 			req.Header.Set("X-Pelican-JobId", h.JobId)
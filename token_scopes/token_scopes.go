@@ -38,6 +38,7 @@ const (
 	WebUi_Access TokenScope = "web_ui.access"
 	Pelican_LoggingModify TokenScope = "pelican.logging_modify"
 	Registry_EditRegistration TokenScope = "registry.edit_registration"
+	Registry_Replicate TokenScope = "registry.replicate"
 	Monitoring_Scrape TokenScope = "monitoring.scrape"
 	Monitoring_Query TokenScope = "monitoring.query"
 	Broker_Reverse TokenScope = "broker.reverse"
@@ -0,0 +1,315 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+// Package tracing wires Pelican into OpenTelemetry distributed tracing.
+//
+// Spans are exported over OTLP/HTTP to the collector configured in
+// Tracing.Endpoint, and the W3C trace context is propagated on every HTTP hop
+// between client, director, cache and origin.  When no endpoint is configured
+// the global tracer provider stays a no-op, but incoming trace context is
+// still forwarded so a downstream service with tracing enabled can attach to
+// the caller's trace.
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/pelicanplatform/pelican/config"
+	"github.com/pelicanplatform/pelican/param"
+	"github.com/pelicanplatform/pelican/version"
+)
+
+const (
+	instrumentationName = "github.com/pelicanplatform/pelican"
+
+	// AttrJobId is the span attribute carrying the X-Pelican-JobId request ID,
+	// so traces can be matched against the existing per-request log lines.
+	AttrJobId = attribute.Key("pelican.job_id")
+
+	otlpTracesPath = "/v1/traces"
+)
+
+var (
+	providerMtx sync.Mutex
+	provider    *sdktrace.TracerProvider
+)
+
+func init() {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+}
+
+// InitTracing configures the global tracer provider from the Tracing.*
+// parameters.  It does nothing if Tracing.Endpoint is unset or a provider was
+// already installed (e.g. the cache's embedded transfer engine starting after
+// the server set up tracing).  If egrp is non-nil, the provider is flushed and
+// shut down when ctx is cancelled; otherwise callers should use ForceFlush
+// before exiting.
+func InitTracing(ctx context.Context, egrp *errgroup.Group, serviceName string) error {
+	endpoint := param.Tracing_Endpoint.GetString()
+	if endpoint == "" {
+		return nil
+	}
+
+	providerMtx.Lock()
+	defer providerMtx.Unlock()
+	if provider != nil {
+		return nil
+	}
+
+	endpointUrl, err := url.Parse(endpoint)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse %s", param.Tracing_Endpoint.GetName())
+	}
+	if endpointUrl.Host == "" {
+		return errors.Errorf("%s must be a URL such as http://localhost:4318; got %q", param.Tracing_Endpoint.GetName(), endpoint)
+	}
+	urlPath := strings.TrimSuffix(endpointUrl.Path, "/")
+	if !strings.HasSuffix(urlPath, otlpTracesPath) {
+		urlPath += otlpTracesPath
+	}
+	opts := []otlptracehttp.Option{
+		otlptracehttp.WithEndpoint(endpointUrl.Host),
+		otlptracehttp.WithURLPath(urlPath),
+		otlptracehttp.WithTimeout(10 * time.Second),
+	}
+	switch endpointUrl.Scheme {
+	case "http":
+		opts = append(opts, otlptracehttp.WithInsecure())
+	case "https":
+		if transport := config.GetTransport(); transport != nil && transport.TLSClientConfig != nil {
+			opts = append(opts, otlptracehttp.WithTLSClientConfig(transport.TLSClientConfig.Clone()))
+		}
+	default:
+		return errors.Errorf("unsupported scheme %q in %s; must be http or https", endpointUrl.Scheme, param.Tracing_Endpoint.GetName())
+	}
+
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return errors.Wrap(err, "failed to create OTLP trace exporter")
+	}
+
+	percentage := param.Tracing_SamplingPercentage.GetInt()
+	if percentage < 0 || percentage > 100 {
+		log.Warningf("Invalid value of %d for %s; must be between 0 and 100. Sampling all traces",
+			percentage, param.Tracing_SamplingPercentage.GetName())
+		percentage = 100
+	}
+
+	provider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(
+			attribute.String("service.name", serviceName),
+			attribute.String("service.version", version.GetVersion()),
+		)),
+		// Always honor the caller's sampling decision so a trace isn't cut
+		// short half-way through the federation.
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(float64(percentage)/100))),
+	)
+	otel.SetTracerProvider(provider)
+	log.Infof("Exporting traces for %s to %s", serviceName, endpointUrl.String())
+
+	if egrp != nil {
+		egrp.Go(func() error {
+			<-ctx.Done()
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			if err := Shutdown(shutdownCtx); err != nil {
+				log.Warningln("Failed to shut down the trace exporter:", err)
+			}
+			return nil
+		})
+	}
+	return nil
+}
+
+// ForceFlush exports all spans that have ended but not yet been sent.
+func ForceFlush(ctx context.Context) error {
+	providerMtx.Lock()
+	tp := provider
+	providerMtx.Unlock()
+	if tp == nil {
+		return nil
+	}
+	return tp.ForceFlush(ctx)
+}
+
+// Shutdown flushes any pending spans and reverts to a no-op tracer provider.
+func Shutdown(ctx context.Context) error {
+	providerMtx.Lock()
+	defer providerMtx.Unlock()
+	if provider == nil {
+		return nil
+	}
+	err := provider.Shutdown(ctx)
+	provider = nil
+	otel.SetTracerProvider(noop.NewTracerProvider())
+	return err
+}
+
+// Tracer returns the tracer used for all Pelican spans.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// StartSpan starts an internal span named name as a child of any span in ctx.
+func StartSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// StartClientSpan starts a span for an outgoing request to another service.
+// Use InjectHeaders with the returned context to propagate it.
+func StartClientSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+}
+
+// EndSpan records err (if any) on span and ends it.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// InjectHeaders writes the W3C trace context of ctx into header.
+func InjectHeaders(ctx context.Context, header http.Header) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+// ExtractHeaders returns a child of ctx carrying the trace context found in header.
+func ExtractHeaders(ctx context.Context, header http.Header) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(header))
+}
+
+// Detach returns a background context that carries only the span of ctx.
+// It is meant for work that must outlive the request that triggered it
+// (e.g. a cache fill continuing after the client disconnects) but should
+// still show up in the request's trace.
+func Detach(ctx context.Context) context.Context {
+	return trace.ContextWithSpan(context.Background(), trace.SpanFromContext(ctx))
+}
+
+func startServerSpan(r *http.Request, route string) (*http.Request, trace.Span) {
+	name := r.Method
+	if route != "" {
+		name += " " + route
+	}
+	ctx := ExtractHeaders(r.Context(), r.Header)
+	ctx, span := Tracer().Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			attribute.String("http.request.method", r.Method),
+			attribute.String("url.path", r.URL.Path),
+		),
+	)
+	if route != "" {
+		span.SetAttributes(attribute.String("http.route", route))
+	}
+	if jobId := r.Header.Get("X-Pelican-JobId"); jobId != "" {
+		span.SetAttributes(AttrJobId.String(jobId))
+	}
+	return r.WithContext(ctx), span
+}
+
+func endServerSpan(span trace.Span, status int) {
+	span.SetAttributes(attribute.Int("http.response.status_code", status))
+	if status >= http.StatusInternalServerError {
+		span.SetStatus(codes.Error, http.StatusText(status))
+	}
+	span.End()
+}
+
+// GinMiddleware starts a server span for every request, continuing the
+// caller's trace when the request carries a traceparent header.
+func GinMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var span trace.Span
+		ctx.Request, span = startServerSpan(ctx.Request, ctx.FullPath())
+		span.SetAttributes(attribute.String("client.address", ctx.ClientIP()))
+		defer func() {
+			endServerSpan(span, ctx.Writer.Status())
+		}()
+		ctx.Next()
+	}
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *statusRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// HTTPMiddleware is the net/http equivalent of GinMiddleware, used by
+// listeners that don't go through the gin engine.
+func HTTPMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r, span := startServerSpan(r, "")
+		rec := &statusRecorder{ResponseWriter: w}
+		defer func() {
+			status := rec.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			endServerSpan(span, status)
+		}()
+		next.ServeHTTP(rec, r)
+	})
+}
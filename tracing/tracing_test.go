@@ -0,0 +1,255 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package tracing
+
+import (
+	"context"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/pelicanplatform/pelican/param"
+	"github.com/pelicanplatform/pelican/server_utils"
+)
+
+// testCollector stands in for an OTLP/HTTP collector, recording every span
+// it receives.
+type testCollector struct {
+	mtx      sync.Mutex
+	services []string
+	spans    []*tracepb.Span
+}
+
+func (c *testCollector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != otlpTracesPath || r.Header.Get("Content-Type") != "application/x-protobuf" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	req := &coltracepb.ExportTraceServiceRequest{}
+	if err := proto.Unmarshal(body, req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	c.mtx.Lock()
+	for _, rs := range req.ResourceSpans {
+		for _, attr := range rs.GetResource().GetAttributes() {
+			if attr.Key == "service.name" {
+				c.services = append(c.services, attr.GetValue().GetStringValue())
+			}
+		}
+		for _, ss := range rs.ScopeSpans {
+			c.spans = append(c.spans, ss.Spans...)
+		}
+	}
+	c.mtx.Unlock()
+
+	resp, _ := proto.Marshal(&coltracepb.ExportTraceServiceResponse{})
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	_, _ = w.Write(resp)
+}
+
+func (c *testCollector) span(name string) *tracepb.Span {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	for _, span := range c.spans {
+		if span.Name == name {
+			return span
+		}
+	}
+	return nil
+}
+
+func (c *testCollector) count() int {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return len(c.spans)
+}
+
+func spanAttr(span *tracepb.Span, key string) string {
+	for _, attr := range span.Attributes {
+		if attr.Key == key {
+			return attr.GetValue().GetStringValue()
+		}
+	}
+	return ""
+}
+
+func setupCollector(t *testing.T) *testCollector {
+	server_utils.ResetTestState()
+	collector := &testCollector{}
+	srv := httptest.NewServer(collector)
+	t.Cleanup(func() {
+		assert.NoError(t, Shutdown(context.Background()))
+		srv.Close()
+		server_utils.ResetTestState()
+	})
+	require.NoError(t, param.Tracing_Endpoint.Set(srv.URL))
+	require.NoError(t, param.Tracing_SamplingPercentage.Set(100))
+	return collector
+}
+
+func TestExportSpans(t *testing.T) {
+	collector := setupCollector(t)
+	require.NoError(t, InitTracing(context.Background(), nil, "pelican-test"))
+
+	ctx, parent := StartSpan(context.Background(), "parent")
+	_, child := StartClientSpan(ctx, "child", AttrJobId.String("job-1"))
+	EndSpan(child, io.ErrUnexpectedEOF)
+	EndSpan(parent, nil)
+	require.NoError(t, ForceFlush(context.Background()))
+
+	parentSpan := collector.span("parent")
+	childSpan := collector.span("child")
+	require.NotNil(t, parentSpan)
+	require.NotNil(t, childSpan)
+	assert.Equal(t, parentSpan.TraceId, childSpan.TraceId)
+	assert.Equal(t, parentSpan.SpanId, childSpan.ParentSpanId)
+	assert.Equal(t, tracepb.Span_SPAN_KIND_CLIENT, childSpan.Kind)
+	assert.Equal(t, "job-1", spanAttr(childSpan, string(AttrJobId)))
+	assert.Equal(t, tracepb.Status_STATUS_CODE_ERROR, childSpan.Status.GetCode())
+	assert.Contains(t, collector.services, "pelican-test")
+}
+
+// TestPropagation sends a request carrying a traceparent through an
+// instrumented gin engine, which in turn calls a plain net/http server; all
+// three hops must end up in the same trace.
+func TestPropagation(t *testing.T) {
+	collector := setupCollector(t)
+	require.NoError(t, InitTracing(context.Background(), nil, "pelican-test"))
+
+	var downstreamTraceparent string
+	downstream := httptest.NewServer(HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		downstreamTraceparent = r.Header.Get("traceparent")
+		w.WriteHeader(http.StatusTeapot)
+	})))
+	defer downstream.Close()
+
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(GinMiddleware())
+	engine.GET("/api/v1.0/objects/*path", func(ctx *gin.Context) {
+		req, err := http.NewRequestWithContext(ctx.Request.Context(), http.MethodGet, downstream.URL, nil)
+		if !assert.NoError(t, err) {
+			return
+		}
+		InjectHeaders(req.Context(), req.Header)
+		resp, err := http.DefaultClient.Do(req)
+		if !assert.NoError(t, err) {
+			return
+		}
+		resp.Body.Close()
+		ctx.Status(http.StatusOK)
+	})
+
+	ctx, caller := StartClientSpan(context.Background(), "caller")
+	req := httptest.NewRequest(http.MethodGet, "/api/v1.0/objects/foo/bar", nil)
+	req.Header.Set("X-Pelican-JobId", "job-2")
+	InjectHeaders(ctx, req.Header)
+	recorder := httptest.NewRecorder()
+	engine.ServeHTTP(recorder, req)
+	caller.End()
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.NoError(t, ForceFlush(context.Background()))
+
+	callerSpan := collector.span("caller")
+	ginSpan := collector.span("GET /api/v1.0/objects/*path")
+	downstreamSpan := collector.span("GET")
+	require.NotNil(t, callerSpan)
+	require.NotNil(t, ginSpan)
+	require.NotNil(t, downstreamSpan)
+
+	assert.Equal(t, callerSpan.TraceId, ginSpan.TraceId)
+	assert.Equal(t, callerSpan.SpanId, ginSpan.ParentSpanId)
+	assert.Equal(t, ginSpan.TraceId, downstreamSpan.TraceId)
+	assert.Equal(t, ginSpan.SpanId, downstreamSpan.ParentSpanId)
+	assert.Equal(t, tracepb.Span_SPAN_KIND_SERVER, ginSpan.Kind)
+	assert.Equal(t, "job-2", spanAttr(ginSpan, string(AttrJobId)))
+	assert.Equal(t, "/api/v1.0/objects/*path", spanAttr(ginSpan, "http.route"))
+	assert.True(t, strings.HasPrefix(downstreamTraceparent, "00-"+hex.EncodeToString(ginSpan.TraceId)+"-"))
+}
+
+// TestSampling checks that a sampling percentage of zero drops new traces
+// but still records spans for a trace the caller decided to sample.
+func TestSampling(t *testing.T) {
+	collector := setupCollector(t)
+	require.NoError(t, param.Tracing_SamplingPercentage.Set(0))
+	require.NoError(t, InitTracing(context.Background(), nil, "pelican-test"))
+
+	_, root := StartSpan(context.Background(), "root")
+	assert.False(t, root.SpanContext().IsSampled())
+	root.End()
+
+	header := http.Header{}
+	header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	_, child := StartSpan(ExtractHeaders(context.Background(), header), "child")
+	child.End()
+	require.NoError(t, ForceFlush(context.Background()))
+
+	assert.Nil(t, collector.span("root"))
+	childSpan := collector.span("child")
+	require.NotNil(t, childSpan)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", hex.EncodeToString(childSpan.TraceId))
+}
+
+func TestTracingDisabled(t *testing.T) {
+	collector := setupCollector(t)
+	require.NoError(t, param.Tracing_Endpoint.Set(""))
+	require.NoError(t, InitTracing(context.Background(), nil, "pelican-test"))
+
+	ctx, span := StartSpan(context.Background(), "ignored")
+	assert.False(t, span.SpanContext().IsValid())
+	EndSpan(span, nil)
+	require.NoError(t, ForceFlush(ctx))
+	assert.Equal(t, 0, collector.count())
+
+	// An incoming trace context is still forwarded so downstream services
+	// with tracing enabled can join the caller's trace.
+	incoming := http.Header{}
+	incoming.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	outgoing := http.Header{}
+	InjectHeaders(ExtractHeaders(context.Background(), incoming), outgoing)
+	assert.Equal(t, incoming.Get("traceparent"), outgoing.Get("traceparent"))
+}
+
+func TestInitTracingInvalidEndpoint(t *testing.T) {
+	server_utils.ResetTestState()
+	t.Cleanup(server_utils.ResetTestState)
+
+	require.NoError(t, param.Tracing_Endpoint.Set("grpc://localhost:4317"))
+	assert.Error(t, InitTracing(context.Background(), nil, "pelican-test"))
+	require.NoError(t, param.Tracing_Endpoint.Set("localhost:4318"))
+	assert.Error(t, InitTracing(context.Background(), nil, "pelican-test"))
+}
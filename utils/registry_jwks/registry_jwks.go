@@ -31,15 +31,28 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/pelicanplatform/pelican/config"
+	"github.com/pelicanplatform/pelican/param"
 )
 
+// GetRegistryEndpoints returns the URL of the federation's registry followed by the
+// URLs of the registries replicating it (Federation.RegistryReplicaUrls), in the
+// order they should be tried.
+func GetRegistryEndpoints(ctx context.Context) ([]string, error) {
+	fedInfo, err := config.GetFederation(ctx)
+	registryUrlStr := fedInfo.RegistryEndpoint
+	if registryUrlStr == "" {
+		if err != nil {
+			return nil, err
+		}
+		return nil, errors.New("federation registry URL is not set and was not discovered")
+	}
+	return append([]string{registryUrlStr}, param.Federation_RegistryReplicaUrls.GetStringSlice()...), nil
+}
+
 // For a given prefix, get the prefix's issuer URL, where we consider that the openid endpoint
 // we use to look up a key location. Note that this is NOT the same as the issuer key -- to
 // find that, follow openid-style discovery using the issuer URL as a base.
 func GetNSIssuerURL(prefix string) (string, error) {
-	if prefix == "" || !strings.HasPrefix(prefix, "/") {
-		return "", errors.New(fmt.Sprintf("the prefix \"%s\" is invalid", prefix))
-	}
 	fedInfo, err := config.GetFederation(context.Background())
 	registryUrlStr := fedInfo.RegistryEndpoint
 	if registryUrlStr == "" {
@@ -48,6 +61,14 @@ func GetNSIssuerURL(prefix string) (string, error) {
 		}
 		return "", errors.New("federation registry URL is not set and was not discovered")
 	}
+	return GetNSIssuerURLAt(registryUrlStr, prefix)
+}
+
+// GetNSIssuerURLAt is GetNSIssuerURL for the registry (or registry replica) at registryUrlStr.
+func GetNSIssuerURLAt(registryUrlStr string, prefix string) (string, error) {
+	if prefix == "" || !strings.HasPrefix(prefix, "/") {
+		return "", errors.New(fmt.Sprintf("the prefix \"%s\" is invalid", prefix))
+	}
 	registryUrl, err := url.Parse(registryUrlStr)
 	if err != nil {
 		return "", err
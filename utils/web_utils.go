@@ -34,6 +34,15 @@ import (
 	"github.com/pkg/errors"
 )
 
+const (
+	// The header naming the resumable upload a chunked PUT belongs to
+	ResumableUploadIdHeader = "X-Pelican-Upload-Id"
+	// The header in which origins report the bytes received for a resumable upload
+	ResumableUploadOffsetHeader = "X-Pelican-Upload-Offset"
+	// The name prefix of the hidden files origins stage resumable uploads in
+	ResumableUploadStagingPrefix = ".pelican-upload-"
+)
+
 // MakeRequest makes an http request with our custom http client. It acts similarly to the http.NewRequest but
 // it only takes json as the request data.
 func MakeRequest(ctx context.Context, tr *http.Transport, url string, method string, data map[string]interface{}, headers map[string]string) ([]byte, error) {
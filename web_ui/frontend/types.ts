@@ -83,6 +83,7 @@ export interface DowntimeBase {
   severity: DowntimeSeverity;
   startTime: number;
   endTime: number;
+  recurrence?: string;
 }
 
 export interface DowntimePost extends DowntimeBase {}
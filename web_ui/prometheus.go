@@ -434,6 +434,11 @@ func ConfigureEmbeddedPrometheus(ctx context.Context, engine *gin.Engine, dialCo
 		return fmt.Errorf("parse external URL %v: %w", param.Server_ExternalWebUrl.GetString(), err)
 	}
 
+	notifier, err := newAlertNotifier(ctx, external_url.String())
+	if err != nil {
+		return errors.Wrap(err, "failed to configure alert notifications")
+	}
+
 	CORSOrigin, err := compileCORSRegexString(".*")
 	if err != nil {
 		panic(err)
@@ -491,6 +496,12 @@ func ConfigureEmbeddedPrometheus(ctx context.Context, engine *gin.Engine, dialCo
 	}
 
 	promCfg.GlobalConfig.ScrapeInterval = model.Duration(15 * time.Second)
+	if evalInterval := param.Monitoring_AlertEvaluationInterval.GetDuration(); evalInterval > 0 {
+		promCfg.GlobalConfig.EvaluationInterval = model.Duration(evalInterval)
+	} else {
+		logrus.Warningf("Invalid value of '%v' for config param %s; must be a positive duration. Using the default of %v",
+			evalInterval, param.Monitoring_AlertEvaluationInterval.GetName(), promCfg.GlobalConfig.EvaluationInterval)
+	}
 
 	if promCfg.StorageConfig.TSDBConfig != nil {
 		cfg.tsdb.OutOfOrderTimeWindow = promCfg.StorageConfig.TSDBConfig.OutOfOrderTimeWindow
@@ -526,7 +537,7 @@ func ConfigureEmbeddedPrometheus(ctx context.Context, engine *gin.Engine, dialCo
 	}
 
 	noStepSubqueryInterval := &safePromQLNoStepSubqueryInterval{}
-	noStepSubqueryInterval.Set(config.DefaultGlobalConfig.EvaluationInterval)
+	noStepSubqueryInterval.Set(promCfg.GlobalConfig.EvaluationInterval)
 
 	var (
 		localStorage = &readyStorage{stats: tsdb.NewDBStats()}
@@ -574,6 +585,21 @@ func ConfigureEmbeddedPrometheus(ctx context.Context, engine *gin.Engine, dialCo
 	}
 	scraper.Set(scrapeManager)
 
+	ruleManager := rules.NewManager(&rules.ManagerOptions{
+		Appendable:      fanoutStorage,
+		Queryable:       localStorage,
+		QueryFunc:       rules.EngineQueryFunc(queryEngine, fanoutStorage),
+		NotifyFunc:      notifier.notify,
+		Context:         ctx,
+		ExternalURL:     external_url,
+		Registerer:      prometheus.DefaultRegisterer,
+		Logger:          log.With(logger, "component", "rule manager"),
+		OutageTolerance: time.Duration(cfg.outageTolerance),
+		ForGracePeriod:  time.Duration(cfg.forGracePeriod),
+		ResendDelay:     time.Duration(cfg.resendDelay),
+		GroupLoader:     alertRuleLoader{},
+	})
+
 	TSDBDir := localStoragePath
 
 	Version := &web.PrometheusVersion{
@@ -598,7 +624,7 @@ func ConfigureEmbeddedPrometheus(ctx context.Context, engine *gin.Engine, dialCo
 	factorySPr := func(_ context.Context) api_v1.ScrapePoolsRetriever { return scrapeManager }
 	factoryTr := func(_ context.Context) api_v1.TargetRetriever { return scrapeManager }
 	factoryAr := func(_ context.Context) api_v1.AlertmanagerRetriever { return stubAlertmanagerRetriever{} }
-	factoryRr := func(_ context.Context) api_v1.RulesRetriever { return stubRulesRetriever{} }
+	factoryRr := func(_ context.Context) api_v1.RulesRetriever { return ruleManager }
 
 	readyHandler := ReadyHandler{}
 	readyHandler.SetReady(false)
@@ -686,6 +712,11 @@ func ConfigureEmbeddedPrometheus(ctx context.Context, engine *gin.Engine, dialCo
 				}
 				return discoveryManagerScrape.ApplyConfig(c)
 			},
+		}, {
+			name: "rules",
+			reloader: func(cfg *config.Config) error {
+				return updateAlertRules(ruleManager, time.Duration(cfg.GlobalConfig.EvaluationInterval), cfg.GlobalConfig.ExternalLabels, external_url.String())
+			},
 		},
 	}
 
@@ -905,6 +936,19 @@ func ConfigureEmbeddedPrometheus(ctx context.Context, engine *gin.Engine, dialCo
 			},
 		)
 	}
+	{
+		// Rule manager.
+		g.Add(
+			func() error {
+				<-reloadReady.C
+				ruleManager.Run()
+				return nil
+			},
+			func(err error) {
+				ruleManager.Stop()
+			},
+		)
+	}
 	{
 		cancel := make(chan struct{})
 		g.Add(
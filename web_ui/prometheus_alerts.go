@@ -0,0 +1,432 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package web_ui
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/rulefmt"
+	"github.com/prometheus/prometheus/rules"
+	log "github.com/sirupsen/logrus"
+
+	pelican_config "github.com/pelicanplatform/pelican/config"
+	"github.com/pelicanplatform/pelican/param"
+)
+
+type (
+	// alertRuleLoader loads rule files from disk like Prometheus does, but
+	// serves the built-in rule set from the binary.
+	alertRuleLoader struct {
+		rules.FileLoader
+	}
+
+	// alertNotifier implements rules.NotifyFunc by delivering alerts to a
+	// webhook and/or a list of email recipients.  The rule manager re-notifies
+	// firing alerts every resend delay; the notifier only forwards an alert
+	// when it first fires, every repeatInterval while it keeps firing, and
+	// once when it resolves.
+	alertNotifier struct {
+		ctx            context.Context
+		externalURL    string
+		webhookURL     string
+		email          *alertEmailConfig
+		repeatInterval time.Duration
+		client         *http.Client
+
+		mtx      sync.Mutex
+		lastSent map[uint64]time.Time
+		wg       sync.WaitGroup
+	}
+
+	alertEmailConfig struct {
+		server     string
+		sender     string
+		recipients []string
+		username   string
+		password   string
+	}
+
+	// alertWebhookMessage is the body POSTed to Monitoring.AlertWebhookUrl.
+	// It follows the Alertmanager webhook format so existing receivers
+	// (chat bridges, ticketing integrations) work unmodified.
+	alertWebhookMessage struct {
+		Version           string            `json:"version"`
+		Status            string            `json:"status"`
+		Receiver          string            `json:"receiver"`
+		GroupLabels       map[string]string `json:"groupLabels"`
+		CommonLabels      map[string]string `json:"commonLabels"`
+		CommonAnnotations map[string]string `json:"commonAnnotations"`
+		ExternalURL       string            `json:"externalURL"`
+		Alerts            []alertMessage    `json:"alerts"`
+	}
+
+	alertMessage struct {
+		Status       string            `json:"status"`
+		Labels       map[string]string `json:"labels"`
+		Annotations  map[string]string `json:"annotations"`
+		StartsAt     time.Time         `json:"startsAt"`
+		EndsAt       time.Time         `json:"endsAt"`
+		GeneratorURL string            `json:"generatorURL"`
+		Fingerprint  string            `json:"fingerprint"`
+	}
+)
+
+const (
+	// builtinAlertRulesFile is the identifier under which the built-in rules
+	// are loaded; it shows up as the "file" of the rule group in the API.
+	builtinAlertRulesFile = "pelican-builtin-alert-rules.yaml"
+
+	alertStatusFiring   = "firing"
+	alertStatusResolved = "resolved"
+)
+
+//go:embed resources/alert_rules.yaml
+var builtinAlertRules []byte
+
+func (l alertRuleLoader) Load(identifier string) (*rulefmt.RuleGroups, []error) {
+	if identifier == builtinAlertRulesFile {
+		return rulefmt.Parse(builtinAlertRules)
+	}
+	return l.FileLoader.Load(identifier)
+}
+
+// getAlertRuleFiles returns the rule files configured via Monitoring.AlertRuleFiles,
+// with glob patterns expanded.
+func getAlertRuleFiles() ([]string, error) {
+	files := []string{}
+	for _, pattern := range param.Monitoring_AlertRuleFiles.GetStringSlice() {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid pattern %q in %s", pattern, param.Monitoring_AlertRuleFiles.GetName())
+		}
+		if len(matches) == 0 {
+			log.Warningf("%s pattern %q does not match any file", param.Monitoring_AlertRuleFiles.GetName(), pattern)
+		}
+		files = append(files, matches...)
+	}
+	return files, nil
+}
+
+// updateAlertRules (re)loads the configured rule files into the rule manager.
+// A broken user-provided rule file should not take the whole monitoring stack
+// down, so if loading fails we fall back to the built-in rules alone.
+func updateAlertRules(ruleManager *rules.Manager, interval time.Duration, externalLabels labels.Labels, externalURL string) error {
+	files, err := getAlertRuleFiles()
+	if err != nil {
+		log.Errorln("Failed to expand alert rule files:", err)
+		files = nil
+	}
+	builtin := param.Monitoring_EnableBuiltinAlertRules.GetBool()
+	if builtin {
+		files = append([]string{builtinAlertRulesFile}, files...)
+	}
+	if err == nil {
+		if err = ruleManager.Update(interval, files, externalLabels, externalURL, nil); err == nil {
+			return nil
+		}
+		log.Errorln("Failed to load alert rules:", err)
+	}
+	if !builtin {
+		return nil
+	}
+	log.Warningln("Falling back to the built-in alert rules only")
+	return ruleManager.Update(interval, []string{builtinAlertRulesFile}, externalLabels, externalURL, nil)
+}
+
+func readAlertSmtpPassword() (string, error) {
+	passwordFile := param.Monitoring_AlertSmtpPasswordFile.GetString()
+	if passwordFile == "" {
+		return "", nil
+	}
+	contents, err := os.ReadFile(passwordFile)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read %s", param.Monitoring_AlertSmtpPasswordFile.GetName())
+	}
+	return strings.TrimSpace(string(contents)), nil
+}
+
+// newAlertNotifier builds the notifier from the Monitoring.Alert* parameters.
+// With neither a webhook nor email recipients configured, alerts are still
+// evaluated (and visible through the /alerts API) but not delivered anywhere.
+func newAlertNotifier(ctx context.Context, externalURL string) (*alertNotifier, error) {
+	n := &alertNotifier{
+		ctx:            ctx,
+		externalURL:    strings.TrimSuffix(externalURL, "/"),
+		webhookURL:     param.Monitoring_AlertWebhookUrl.GetString(),
+		repeatInterval: param.Monitoring_AlertRepeatInterval.GetDuration(),
+		client: &http.Client{
+			Transport: pelican_config.GetTransport(),
+			Timeout:   30 * time.Second,
+		},
+		lastSent: make(map[uint64]time.Time),
+	}
+	if n.webhookURL != "" {
+		if _, err := url.Parse(n.webhookURL); err != nil {
+			return nil, errors.Wrapf(err, "invalid %s", param.Monitoring_AlertWebhookUrl.GetName())
+		}
+	}
+
+	if recipients := param.Monitoring_AlertEmailRecipients.GetStringSlice(); len(recipients) > 0 {
+		email := &alertEmailConfig{
+			server:     param.Monitoring_AlertSmtpServer.GetString(),
+			sender:     param.Monitoring_AlertEmailSender.GetString(),
+			recipients: recipients,
+			username:   param.Monitoring_AlertSmtpUsername.GetString(),
+		}
+		if email.server == "" {
+			return nil, errors.Errorf("%s is set but %s is empty", param.Monitoring_AlertEmailRecipients.GetName(), param.Monitoring_AlertSmtpServer.GetName())
+		}
+		if _, _, err := net.SplitHostPort(email.server); err != nil {
+			return nil, errors.Wrapf(err, "invalid %s; expected host:port", param.Monitoring_AlertSmtpServer.GetName())
+		}
+		if email.sender == "" {
+			return nil, errors.Errorf("%s is set but %s is empty", param.Monitoring_AlertEmailRecipients.GetName(), param.Monitoring_AlertEmailSender.GetName())
+		}
+		password, err := readAlertSmtpPassword()
+		if err != nil {
+			return nil, err
+		}
+		email.password = password
+		n.email = email
+	}
+
+	if n.webhookURL == "" && n.email == nil {
+		log.Debugln("No alert webhook or email recipients configured; alerts will not be delivered")
+	}
+	return n, nil
+}
+
+// notify is the rules.NotifyFunc handed to the rule manager.  Delivery happens
+// in the background so slow receivers don't hold up rule evaluation.
+func (n *alertNotifier) notify(_ context.Context, expr string, alerts ...*rules.Alert) {
+	now := time.Now()
+	toSend := make([]*rules.Alert, 0, len(alerts))
+	hashes := make([]uint64, 0, len(alerts))
+
+	n.mtx.Lock()
+	for _, alert := range alerts {
+		hash := alert.Labels.Hash()
+		last, sent := n.lastSent[hash]
+		if !alert.ResolvedAt.IsZero() {
+			// Only tell receivers about a resolution if they heard about the alert.
+			if sent {
+				delete(n.lastSent, hash)
+				toSend = append(toSend, alert)
+			}
+			continue
+		}
+		if !sent || now.Sub(last) >= n.repeatInterval {
+			n.lastSent[hash] = now
+			toSend = append(toSend, alert)
+			hashes = append(hashes, hash)
+		}
+	}
+	n.mtx.Unlock()
+
+	if len(toSend) == 0 || (n.webhookURL == "" && n.email == nil) {
+		return
+	}
+
+	msg := n.buildMessage(expr, toSend)
+	n.wg.Add(1)
+	go func() {
+		defer n.wg.Done()
+		if err := n.deliver(msg); err != nil {
+			log.Warningf("Failed to deliver %d alert(s) for %s: %v", len(msg.Alerts), msg.GroupLabels["alertname"], err)
+			// Forget the firing alerts so the next evaluation retries them.
+			n.mtx.Lock()
+			for _, hash := range hashes {
+				if n.lastSent[hash].Equal(now) {
+					delete(n.lastSent, hash)
+				}
+			}
+			n.mtx.Unlock()
+		}
+	}()
+}
+
+func (n *alertNotifier) buildMessage(expr string, alerts []*rules.Alert) *alertWebhookMessage {
+	msg := &alertWebhookMessage{
+		Version:     "4",
+		Status:      alertStatusResolved,
+		Receiver:    "pelican",
+		ExternalURL: n.externalURL,
+		Alerts:      make([]alertMessage, 0, len(alerts)),
+	}
+	// Note the query endpoint requires the same authorization as the rest of
+	// the embedded Prometheus API.
+	generatorURL := n.externalURL + "/api/v1.0/prometheus/query?query=" + url.QueryEscape(expr)
+
+	for idx, alert := range alerts {
+		am := alertMessage{
+			Status:       alertStatusFiring,
+			Labels:       alert.Labels.Map(),
+			Annotations:  alert.Annotations.Map(),
+			StartsAt:     alert.FiredAt,
+			GeneratorURL: generatorURL,
+			Fingerprint:  fmt.Sprintf("%016x", alert.Labels.Hash()),
+		}
+		if !alert.ResolvedAt.IsZero() {
+			am.Status = alertStatusResolved
+			am.EndsAt = alert.ResolvedAt
+		} else {
+			msg.Status = alertStatusFiring
+		}
+		msg.Alerts = append(msg.Alerts, am)
+
+		if idx == 0 {
+			msg.CommonLabels = alert.Labels.Map()
+			msg.CommonAnnotations = alert.Annotations.Map()
+		} else {
+			intersectLabels(msg.CommonLabels, am.Labels)
+			intersectLabels(msg.CommonAnnotations, am.Annotations)
+		}
+	}
+	msg.GroupLabels = map[string]string{"alertname": alerts[0].Labels.Get(labels.AlertName)}
+	return msg
+}
+
+// intersectLabels removes from common every entry not present with the same value in other.
+func intersectLabels(common, other map[string]string) {
+	for k, v := range common {
+		if other[k] != v {
+			delete(common, k)
+		}
+	}
+}
+
+func (n *alertNotifier) deliver(msg *alertWebhookMessage) error {
+	var errs []string
+	if n.webhookURL != "" {
+		if err := n.sendWebhook(msg); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if n.email != nil {
+		if err := n.sendEmail(msg); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func (n *alertNotifier) sendWebhook(msg *alertWebhookMessage) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal webhook payload")
+	}
+	req, err := http.NewRequestWithContext(n.ctx, http.MethodPost, n.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "failed to create webhook request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "webhook request failed")
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (n *alertNotifier) sendEmail(msg *alertWebhookMessage) error {
+	var auth smtp.Auth
+	if n.email.username != "" {
+		host, _, _ := net.SplitHostPort(n.email.server)
+		auth = smtp.PlainAuth("", n.email.username, n.email.password, host)
+	}
+	// SendMail upgrades the connection with STARTTLS whenever the server offers it.
+	if err := smtp.SendMail(n.email.server, auth, n.email.sender, n.email.recipients, formatAlertEmail(n.email, msg)); err != nil {
+		return errors.Wrap(err, "failed to send alert email")
+	}
+	return nil
+}
+
+// formatAlertEmail renders msg as a plain-text RFC 5322 message.
+func formatAlertEmail(email *alertEmailConfig, msg *alertWebhookMessage) []byte {
+	firing := 0
+	for _, alert := range msg.Alerts {
+		if alert.Status == alertStatusFiring {
+			firing++
+		}
+	}
+	subject := fmt.Sprintf("[%s] %s", strings.ToUpper(msg.Status), msg.GroupLabels["alertname"])
+	if firing > 0 {
+		subject = fmt.Sprintf("[%s:%d] %s", strings.ToUpper(msg.Status), firing, msg.GroupLabels["alertname"])
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", email.sender)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(email.recipients, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", subject)
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+
+	for _, alert := range msg.Alerts {
+		fmt.Fprintf(&buf, "[%s] %s\r\n", strings.ToUpper(alert.Status), alert.Labels[labels.AlertName])
+		if summary := alert.Annotations["summary"]; summary != "" {
+			fmt.Fprintf(&buf, "%s\r\n", summary)
+		}
+		if description := alert.Annotations["description"]; description != "" {
+			fmt.Fprintf(&buf, "%s\r\n", description)
+		}
+		fmt.Fprintf(&buf, "Started: %s\r\n", alert.StartsAt.Format(time.RFC3339))
+		if !alert.EndsAt.IsZero() {
+			fmt.Fprintf(&buf, "Resolved: %s\r\n", alert.EndsAt.Format(time.RFC3339))
+		}
+		keys := make([]string, 0, len(alert.Labels))
+		for k := range alert.Labels {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		buf.WriteString("Labels:\r\n")
+		for _, k := range keys {
+			fmt.Fprintf(&buf, "  %s = %s\r\n", k, alert.Labels[k])
+		}
+		buf.WriteString("\r\n")
+	}
+	if msg.ExternalURL != "" {
+		fmt.Fprintf(&buf, "Sent by Pelican at %s\r\n", msg.ExternalURL)
+	}
+	return buf.Bytes()
+}
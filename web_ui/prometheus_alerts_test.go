@@ -0,0 +1,202 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package web_ui
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/rules"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pelicanplatform/pelican/param"
+	"github.com/pelicanplatform/pelican/server_utils"
+)
+
+func TestBuiltinAlertRules(t *testing.T) {
+	groups, errs := alertRuleLoader{}.Load(builtinAlertRulesFile)
+	require.Empty(t, errs)
+
+	names := []string{}
+	for _, group := range groups.Groups {
+		for _, rule := range group.Rules {
+			names = append(names, rule.Alert.Value)
+			_, err := alertRuleLoader{}.Parse(rule.Expr.Value)
+			assert.NoError(t, err, "rule %s", rule.Alert.Value)
+		}
+	}
+	assert.ElementsMatch(t, []string{"CacheDiskNearFull", "OriginUnreachable", "XRootDRestartLoop"}, names)
+}
+
+func TestGetAlertRuleFiles(t *testing.T) {
+	server_utils.ResetTestState()
+	t.Cleanup(server_utils.ResetTestState)
+
+	dir := t.TempDir()
+	for _, name := range []string{"a.yaml", "b.yaml", "c.txt"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("groups: []\n"), 0644))
+	}
+	require.NoError(t, param.Monitoring_AlertRuleFiles.Set([]string{filepath.Join(dir, "*.yaml"), filepath.Join(dir, "c.txt")}))
+
+	files, err := getAlertRuleFiles()
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "a.yaml"), filepath.Join(dir, "b.yaml"), filepath.Join(dir, "c.txt")}, files)
+
+	require.NoError(t, param.Monitoring_AlertRuleFiles.Set([]string{"[bad"}))
+	_, err = getAlertRuleFiles()
+	assert.Error(t, err)
+}
+
+func newTestAlert(name string, resolved bool) *rules.Alert {
+	alert := &rules.Alert{
+		State:       rules.StateFiring,
+		Labels:      labels.FromStrings(labels.AlertName, name, "severity", "warning", "instance", "cache.example.org:8444"),
+		Annotations: labels.FromStrings("summary", name+" is firing"),
+		FiredAt:     time.Now().Add(-time.Minute),
+	}
+	if resolved {
+		alert.State = rules.StateInactive
+		alert.ResolvedAt = time.Now()
+	}
+	return alert
+}
+
+func TestAlertNotifierWebhook(t *testing.T) {
+	server_utils.ResetTestState()
+	t.Cleanup(server_utils.ResetTestState)
+
+	var mtx sync.Mutex
+	received := []alertWebhookMessage{}
+	fail := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		var msg alertWebhookMessage
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&msg))
+		mtx.Lock()
+		defer mtx.Unlock()
+		if fail {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		received = append(received, msg)
+	}))
+	defer srv.Close()
+
+	require.NoError(t, param.Monitoring_AlertWebhookUrl.Set(srv.URL))
+	require.NoError(t, param.Monitoring_AlertRepeatInterval.Set(time.Hour))
+
+	n, err := newAlertNotifier(context.Background(), "https://cache.example.org:8444/")
+	require.NoError(t, err)
+	n.client = srv.Client()
+
+	notify := func(alerts ...*rules.Alert) []alertWebhookMessage {
+		mtx.Lock()
+		before := len(received)
+		mtx.Unlock()
+		n.notify(context.Background(), "up == 0", alerts...)
+		n.wg.Wait()
+		mtx.Lock()
+		defer mtx.Unlock()
+		return received[before:]
+	}
+
+	t.Run("FiringIsSentOnce", func(t *testing.T) {
+		msgs := notify(newTestAlert("CacheDiskNearFull", false))
+		require.Len(t, msgs, 1)
+		msg := msgs[0]
+		assert.Equal(t, "4", msg.Version)
+		assert.Equal(t, alertStatusFiring, msg.Status)
+		assert.Equal(t, "https://cache.example.org:8444", msg.ExternalURL)
+		assert.Equal(t, "CacheDiskNearFull", msg.GroupLabels["alertname"])
+		assert.Equal(t, "warning", msg.CommonLabels["severity"])
+		require.Len(t, msg.Alerts, 1)
+		assert.Equal(t, alertStatusFiring, msg.Alerts[0].Status)
+		assert.Equal(t, "CacheDiskNearFull is firing", msg.Alerts[0].Annotations["summary"])
+		assert.True(t, strings.HasPrefix(msg.Alerts[0].GeneratorURL, "https://cache.example.org:8444/api/v1.0/prometheus/query?query="))
+
+		// The rule manager re-notifies every resend delay; those are suppressed
+		// until the repeat interval has passed.
+		assert.Empty(t, notify(newTestAlert("CacheDiskNearFull", false)))
+	})
+
+	t.Run("ResolvedIsSentOnce", func(t *testing.T) {
+		msgs := notify(newTestAlert("CacheDiskNearFull", true))
+		require.Len(t, msgs, 1)
+		assert.Equal(t, alertStatusResolved, msgs[0].Status)
+		require.Len(t, msgs[0].Alerts, 1)
+		assert.False(t, msgs[0].Alerts[0].EndsAt.IsZero())
+
+		assert.Empty(t, notify(newTestAlert("CacheDiskNearFull", true)))
+	})
+
+	t.Run("ResolvedWithoutFiringIsDropped", func(t *testing.T) {
+		assert.Empty(t, notify(newTestAlert("OriginUnreachable", true)))
+	})
+
+	t.Run("FailedDeliveryIsRetried", func(t *testing.T) {
+		mtx.Lock()
+		fail = true
+		mtx.Unlock()
+		assert.Empty(t, notify(newTestAlert("XRootDRestartLoop", false)))
+
+		mtx.Lock()
+		fail = false
+		mtx.Unlock()
+		assert.Len(t, notify(newTestAlert("XRootDRestartLoop", false)), 1)
+	})
+}
+
+func TestAlertNotifierEmailConfig(t *testing.T) {
+	server_utils.ResetTestState()
+	t.Cleanup(server_utils.ResetTestState)
+
+	require.NoError(t, param.Monitoring_AlertEmailRecipients.Set([]string{"ops@example.org"}))
+	_, err := newAlertNotifier(context.Background(), "https://origin.example.org")
+	assert.Error(t, err, "recipients without an SMTP server should be rejected")
+
+	passwordFile := filepath.Join(t.TempDir(), "smtp-password")
+	require.NoError(t, os.WriteFile(passwordFile, []byte("hunter2\n"), 0600))
+	require.NoError(t, param.Monitoring_AlertSmtpServer.Set("smtp.example.org:587"))
+	require.NoError(t, param.Monitoring_AlertEmailSender.Set("pelican@example.org"))
+	require.NoError(t, param.Monitoring_AlertSmtpUsername.Set("pelican"))
+	require.NoError(t, param.Monitoring_AlertSmtpPasswordFile.Set(passwordFile))
+	n, err := newAlertNotifier(context.Background(), "https://origin.example.org")
+	require.NoError(t, err)
+	require.NotNil(t, n.email)
+	assert.Equal(t, "hunter2", n.email.password)
+
+	msg := n.buildMessage("up == 0", []*rules.Alert{newTestAlert("OriginUnreachable", false), newTestAlert("OriginUnreachable", true)})
+	assert.Equal(t, alertStatusFiring, msg.Status)
+	body := string(formatAlertEmail(n.email, msg))
+	assert.Contains(t, body, "Subject: [FIRING:1] OriginUnreachable\r\n")
+	assert.Contains(t, body, "To: ops@example.org\r\n")
+	assert.Contains(t, body, "[RESOLVED] OriginUnreachable\r\n")
+	assert.Contains(t, body, "OriginUnreachable is firing\r\n")
+}
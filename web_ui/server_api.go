@@ -54,6 +54,9 @@ type (
 		Severity    server_structs.Severity `json:"severity"`
 		StartTime   int64                   `json:"startTime"` // Epoch UTC in seconds
 		EndTime     int64                   `json:"endTime"`   // Epoch UTC in seconds
+		// RRULE repeating the downtime window.  In an update, leave it out to keep the
+		// current rule, or set it to an empty string to make the downtime one-off
+		Recurrence *string `json:"recurrence,omitempty"`
 	}
 )
 
@@ -137,9 +140,26 @@ func validateDowntimeInput(downtimeInput DowntimeInput) error {
 	if !isValidTimeRange(downtimeInput.StartTime, downtimeInput.EndTime) {
 		return errors.New("Invalid downtime time range")
 	}
+	if downtimeInput.Recurrence != nil && *downtimeInput.Recurrence != "" {
+		if _, err := server_structs.ParseRecurrence(*downtimeInput.Recurrence); err != nil {
+			return errors.Wrap(err, "Invalid downtime recurrence")
+		}
+	}
 	return nil
 }
 
+// Get the canonical form of an input recurrence rule, which must already be validated
+func normalizedRecurrence(recurrence *string) string {
+	if recurrence == nil || *recurrence == "" {
+		return ""
+	}
+	rule, err := server_structs.ParseRecurrence(*recurrence)
+	if err != nil {
+		return *recurrence
+	}
+	return rule.String()
+}
+
 func HandleCreateDowntime(ctx *gin.Context) {
 	var downtimeInput DowntimeInput
 	if err := ctx.ShouldBindJSON(&downtimeInput); err != nil {
@@ -214,6 +234,11 @@ func HandleCreateDowntime(ctx *gin.Context) {
 		Severity:    downtimeInput.Severity,
 		StartTime:   downtimeInput.StartTime,
 		EndTime:     downtimeInput.EndTime,
+		Recurrence:  normalizedRecurrence(downtimeInput.Recurrence),
+	}
+	if err := downtime.ValidateRecurrence(); err != nil {
+		ctx.JSON(http.StatusBadRequest, server_structs.SimpleApiResp{Status: server_structs.RespFailed, Msg: "Invalid downtime recurrence: " + err.Error()})
+		return
 	}
 
 	// Mirror to Registry when running as Origin/Cache so downtime persists centrally (Director polls Registry for all sources)
@@ -248,8 +273,13 @@ func HandleCreateDowntime(ctx *gin.Context) {
 			existing.Severity = downtime.Severity
 			existing.StartTime = downtime.StartTime
 			existing.EndTime = downtime.EndTime
+			existing.Recurrence = downtime.Recurrence
 
-			if updateErr := database.UpdateDowntime(idStr, existing); updateErr != nil {
+			updateErr := database.UpdateDowntime(idStr, existing)
+			if updateErr == nil {
+				updateErr = database.SetDowntimeRecurrence(idStr, existing.Recurrence)
+			}
+			if updateErr != nil {
 				ctx.JSON(http.StatusInternalServerError, server_structs.SimpleApiResp{
 					Status: server_structs.RespFailed,
 					Msg:    "Failed to update existing downtime with UUID " + idStr + " during create: " + updateErr.Error(),
@@ -289,6 +319,41 @@ func HandleGetDowntime(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, downtimes)
 }
 
+// HandleExportDowntimes publishes the server's downtimes as an iCalendar feed
+// (format=ics, the default) or in the OSG Topology downtime XML format
+// (format=topology), so that calendars and federation tooling can follow them
+func HandleExportDowntimes(ctx *gin.Context) {
+	status := ctx.Query("status")
+	source := strings.ToLower(ctx.Query("source"))
+	var downtimes []server_structs.Downtime
+	var err error
+
+	switch status {
+	case "all":
+		downtimes, err = database.GetAllDowntimes(source)
+	default:
+		downtimes, err = database.GetIncompleteDowntimes(source)
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, server_structs.SimpleApiResp{Status: server_structs.RespFailed, Msg: "Failed to get downtimes: " + err.Error()})
+		return
+	}
+
+	switch strings.ToLower(ctx.DefaultQuery("format", "ics")) {
+	case "ics", "ical", "icalendar":
+		prodID := "-//Pelican Platform//Pelican " + config.GetVersion() + "//EN"
+		ctx.Data(http.StatusOK, "text/calendar; charset=utf-8", server_structs.MarshalDowntimesICal(downtimes, prodID))
+	case "topology", "xml":
+		resourceFQDN := param.Server_Hostname.GetString()
+		ctx.XML(http.StatusOK, server_structs.DowntimesToTopology(downtimes, resourceFQDN, time.Now()))
+	default:
+		ctx.JSON(http.StatusBadRequest, server_structs.SimpleApiResp{
+			Status: server_structs.RespFailed,
+			Msg:    "Invalid export format " + ctx.Query("format") + "; must be 'ics' or 'topology'",
+		})
+	}
+}
+
 func HandleGetDowntimeByUUID(ctx *gin.Context) {
 	uuid := ctx.Param("uuid")
 	downtime, err := database.GetDowntimeByUUID(uuid)
@@ -370,16 +435,28 @@ func HandleUpdateDowntime(ctx *gin.Context) {
 	if downtimeInput.EndTime != 0 {
 		updatedDowntime.EndTime = downtimeInput.EndTime
 	}
+	if downtimeInput.Recurrence != nil {
+		updatedDowntime.Recurrence = normalizedRecurrence(downtimeInput.Recurrence)
+	}
 	updatedDowntime.UpdatedBy = downtimeInput.UpdatedBy
 	// To avoid confusion, we don't allow to change the server name and id in an update
 
+	if err := updatedDowntime.ValidateRecurrence(); err != nil {
+		ctx.JSON(http.StatusBadRequest, server_structs.SimpleApiResp{Status: server_structs.RespFailed, Msg: "Invalid downtime recurrence: " + err.Error()})
+		return
+	}
+
 	// Mirror updates to the Registry to keep the central downtime records consistent with local changes.
 	// If mirroring fails, we proceed with the local operation and rely on eventual consistency through server advertisements.
 	if err := mirrorDowntimeToRegistry(ctx, updatedDowntime, http.MethodPut, uuid); err != nil {
 		log.Warningf("Failed to sync downtime update to the Registry immediately; synchronization will occur during the next server advertisement: %v", err)
 	}
 
-	if err := database.UpdateDowntime(uuid, &updatedDowntime); err != nil {
+	err = database.UpdateDowntime(uuid, &updatedDowntime)
+	if err == nil && downtimeInput.Recurrence != nil {
+		err = database.SetDowntimeRecurrence(uuid, updatedDowntime.Recurrence)
+	}
+	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, server_structs.SimpleApiResp{
 			Status: server_structs.RespFailed,
 			Msg:    "Failed to update downtime with UUID " + uuid + ": " + err.Error(),
@@ -21,6 +21,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"encoding/xml"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -57,6 +58,7 @@ func setupRouter() *gin.Engine {
 		originDowntimeAPI.PUT("/:uuid", HandleUpdateDowntime)
 		originDowntimeAPI.DELETE("/:uuid", HandleDeleteDowntime)
 	}
+	r.GET("/api/v1.0/downtimes", HandleExportDowntimes)
 	return r
 }
 
@@ -259,6 +261,100 @@ func TestDowntime(t *testing.T) {
 		assert.Contains(t, w.Body.String(), "Invalid input downtime severity")
 	})
 
+	t.Run("create-and-update-recurring-downtime", func(t *testing.T) {
+		send := func(method, target string, input DowntimeInput) *httptest.ResponseRecorder {
+			body, _ := json.Marshal(input)
+			req, err := http.NewRequest(method, target, bytes.NewBuffer(body))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			return w
+		}
+
+		// A weekly window whose first occurrence is already over
+		rule := "freq=weekly;byday=tu"
+		w := send("POST", "/api/v1.0/downtime", DowntimeInput{
+			ServerID:   "test-server-id",
+			Class:      "SCHEDULED",
+			Severity:   server_structs.Outage,
+			StartTime:  time.Now().UTC().Add(-30 * 24 * time.Hour).UnixMilli(),
+			EndTime:    time.Now().UTC().Add(-30*24*time.Hour + 4*time.Hour).UnixMilli(),
+			Recurrence: &rule,
+		})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var created server_structs.Downtime
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+		assert.Equal(t, "FREQ=WEEKLY;BYDAY=TU", created.Recurrence)
+
+		// It is still listed as incomplete
+		req, _ := http.NewRequest("GET", "/api/v1.0/downtime?status=incomplete", nil)
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Contains(t, w.Body.String(), created.UUID)
+
+		// Updates that leave out the rule keep it
+		w = send("PUT", "/api/v1.0/downtime/"+created.UUID, DowntimeInput{Description: "Patch Tuesday"})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		fetched, err := database.GetDowntimeByUUID(created.UUID)
+		require.NoError(t, err)
+		assert.Equal(t, "FREQ=WEEKLY;BYDAY=TU", fetched.Recurrence)
+
+		// An empty rule makes the downtime one-off
+		noRule := ""
+		w = send("PUT", "/api/v1.0/downtime/"+created.UUID, DowntimeInput{Recurrence: &noRule})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		fetched, err = database.GetDowntimeByUUID(created.UUID)
+		require.NoError(t, err)
+		assert.Empty(t, fetched.Recurrence)
+
+		invalidRule := "FREQ=HOURLY"
+		w = send("PUT", "/api/v1.0/downtime/"+created.UUID, DowntimeInput{Recurrence: &invalidRule})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "Invalid downtime recurrence")
+
+		// A recurring downtime needs an end time
+		w = send("POST", "/api/v1.0/downtime", DowntimeInput{
+			ServerID:   "test-server-id",
+			Class:      "SCHEDULED",
+			Severity:   server_structs.Outage,
+			StartTime:  time.Now().UTC().UnixMilli(),
+			EndTime:    server_structs.IndefiniteEndTime,
+			Recurrence: &rule,
+		})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "must have an end time")
+	})
+
+	t.Run("export-downtimes", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/api/v1.0/downtimes", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/calendar; charset=utf-8", w.Header().Get("Content-Type"))
+		imported, err := server_structs.ParseDowntimesICal(w.Body)
+		require.NoError(t, err)
+		uuids := make([]string, 0, len(imported))
+		for _, dt := range imported {
+			uuids = append(uuids, dt.UUID)
+		}
+		assert.Contains(t, uuids, activeDowntime.UUID)
+		assert.NotContains(t, uuids, pastDowntime.UUID)
+
+		req, _ = http.NewRequest("GET", "/api/v1.0/downtimes?format=topology", nil)
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		var info server_structs.TopoDowntimeInfo
+		require.NoError(t, xml.Unmarshal(w.Body.Bytes(), &info))
+		assert.NotEmpty(t, append(info.CurrentDowntimes.Downtimes, info.FutureDowntimes.Downtimes...))
+
+		req, _ = http.NewRequest("GET", "/api/v1.0/downtimes?format=pdf", nil)
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
 	t.Run("delete-downtime", func(t *testing.T) {
 		req, _ := http.NewRequest("DELETE", "/api/v1.0/downtime/"+activeDowntime.UUID, nil)
 		w := httptest.NewRecorder()
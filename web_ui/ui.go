@@ -58,6 +58,7 @@ import (
 	"github.com/pelicanplatform/pelican/server_utils"
 	"github.com/pelicanplatform/pelican/token"
 	"github.com/pelicanplatform/pelican/token_scopes"
+	"github.com/pelicanplatform/pelican/tracing"
 )
 
 var (
@@ -678,6 +679,7 @@ func registerCommonEndpoints(routerGroup *gin.RouterGroup) error {
 		downtimeAPI.PUT("/:uuid", DowntimeAuthHandler, HandleUpdateDowntime)
 		downtimeAPI.DELETE("/:uuid", DowntimeAuthHandler, HandleDeleteDowntime)
 	}
+	routerGroup.GET("/downtimes", HandleExportDowntimes)
 
 	groupRouterGroup := routerGroup.Group("/groups", AuthHandler, AdminAuthHandler)
 	{
@@ -936,6 +938,7 @@ func GetEngine() (*gin.Engine, error) {
 			"resource": ctx.Request.URL.Path},
 		).Info("Served Request")
 	})
+	engine.Use(tracing.GinMiddleware())
 	engine.HandleMethodNotAllowed = true
 
 	return engine, nil